@@ -79,7 +79,7 @@ func buildServer(ctx context.Context, d *coreDependencies) *server {
 	es, vs := buildVoteStore(ctx, d, closers) // ev, vs
 
 	// engine
-	e := buildEngine(d, ctx, db, accounts, vs, d.namespaceManager)
+	e := buildEngine(d, ctx, db, accounts, vs, d.namespaceManager, bs, closers)
 	d.namespaceManager.Ready()
 
 	// Mempool
@@ -99,7 +99,7 @@ func buildServer(ctx context.Context, d *coreDependencies) *server {
 	ce := buildConsensusEngine(ctx, d, db, mp, bs, bp)
 
 	// Node
-	node := buildNode(d, mp, bs, ce, snapshotStore, db, bp, p2pSvc)
+	node := buildNode(d, mp, bs, ce, snapshotStore, db, bp, p2pSvc, e)
 
 	// listeners
 	lm := buildListenerManager(d, es, bp, node)
@@ -162,6 +162,7 @@ func buildServer(ctx context.Context, d *coreDependencies) *server {
 		listeners:          lm,
 		jsonRPCServer:      jsonRPCServer,
 		jsonRPCAdminServer: jsonRPCAdminServer,
+		engine:             e,
 		dbCtx:              db,
 		log:                d.logger,
 		// erc20BridgeSigner:  erc20BridgeSignerMgr,
@@ -539,8 +540,18 @@ func buildConsensusEngine(_ context.Context, d *coreDependencies, db *pg.DB,
 
 func buildNode(d *coreDependencies, mp *mempool.Mempool, bs *store.BlockStore,
 	ce *consensus.ConsensusEngine, ss *snapshotter.SnapshotStore, db *pg.DB,
-	bp *blockprocessor.BlockProcessor, p2p *node.P2PService) *node.Node {
+	bp *blockprocessor.BlockProcessor, p2p *node.P2PService, e *interpreter.ThreadSafeInterpreter) *node.Node {
 	logger := d.logger.New("NODE")
+
+	var nsGossip *node.NamespaceGossip
+	if d.cfg.P2P.NamespaceGossip {
+		var err error
+		nsGossip, err = node.NewNamespaceGossip(e, db, logger.New("NSGOSSIP"))
+		if err != nil {
+			failBuild(err, "failed to create namespace gossip")
+		}
+	}
+
 	nc := &node.Config{
 		ChainID:     d.genesisCfg.ChainID,
 		RootDir:     d.rootDir,
@@ -556,6 +567,8 @@ func buildNode(d *coreDependencies, mp *mempool.Mempool, bs *store.BlockStore,
 		Logger:      logger,
 		DBConfig:    &d.cfg.DB,
 		P2PService:  p2p,
+
+		NamespaceGossip: nsGossip,
 	}
 
 	node, err := node.NewNode(nc)
@@ -581,7 +594,11 @@ func failBuild(err error, msg string) {
 	})
 }
 
-func buildEngine(d *coreDependencies, ctx context.Context, db *pg.DB, accounts common.Accounts, validators common.Validators, namespaceManager engine.NamespaceRegister) *interpreter.ThreadSafeInterpreter {
+// adminPoolConns is the number of connections in the pool used by admin calls.
+const adminPoolConns = 2
+
+func buildEngine(d *coreDependencies, ctx context.Context, db *pg.DB, accounts common.Accounts, validators common.Validators, namespaceManager engine.NamespaceRegister,
+	bs *store.BlockStore, closers *closeFuncs) *interpreter.ThreadSafeInterpreter {
 	extensions := precompiles.RegisteredPrecompiles()
 	for name := range extensions {
 		d.logger.Info("registered extension", "name", name)
@@ -593,10 +610,39 @@ func buildEngine(d *coreDependencies, ctx context.Context, db *pg.DB, accounts c
 	}
 	defer tx.Rollback(ctx)
 
-	interp, err := interpreter.NewInterpreter(ctx, tx, d.service("engine"), accounts, validators, namespaceManager)
+	// admin calls get their own small pool, so that they are not starved of
+	// connections by user calls
+	adminDB, err := d.poolOpener(ctx, d.cfg.DB.DBName, adminPoolConns)
+	if err != nil {
+		failBuild(err, "failed to open kwild postgres database for admin calls")
+	}
+	closers.addCloser(adminDB.Close, "Closing admin DB")
+
+	service := d.service("engine")
+	service.AdminDB = adminDB
+
+	opts := []interpreter.InterpreterOpt{interpreter.WithBlockStore(bs)}
+	if d.cfg.P2P.NamespaceGossip {
+		opts = append(opts, interpreter.WithNamespaceGossip())
+	}
+	if d.cfg.RPC.MaxConcurrentCalls > 0 {
+		opts = append(opts, interpreter.WithMaxConcurrentCalls(d.cfg.RPC.MaxConcurrentCalls))
+	}
+
+	interp, err := interpreter.NewInterpreter(ctx, tx, service, accounts, validators, namespaceManager, opts...)
 	if err != nil {
 		failBuild(err, "failed to initialize engine")
 	}
+	closers.addCloser(interp.StopIndexBuilds, "Stopping index builds")
+	closers.addCloser(interp.StopCDCPublisher, "Stopping CDC publisher")
+
+	// the stream is started with the server, and a slot left by a stream
+	// that is no longer enabled would retain WAL forever
+	if d.cfg.DB.EnableWALStream {
+		closers.addCloser(interp.StopWALStream, "Stopping WAL stream")
+	} else if err = interp.DropWALStreamSlot(ctx); err != nil {
+		failBuild(err, "failed to drop WAL stream slot")
+	}
 
 	err = tx.Commit(ctx)
 	if err != nil {
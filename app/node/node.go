@@ -22,6 +22,7 @@ import (
 	authExt "github.com/kwilteam/kwil-db/extensions/auth"
 	"github.com/kwilteam/kwil-db/node"
 	"github.com/kwilteam/kwil-db/node/consensus"
+	"github.com/kwilteam/kwil-db/node/engine/interpreter"
 	"github.com/kwilteam/kwil-db/node/listeners"
 	rpcserver "github.com/kwilteam/kwil-db/node/services/jsonrpc"
 	"github.com/kwilteam/kwil-db/version"
@@ -43,6 +44,7 @@ type server struct {
 	listeners          *listeners.ListenerManager
 	jsonRPCServer      *rpcserver.Server
 	jsonRPCAdminServer *rpcserver.Server
+	engine             *interpreter.ThreadSafeInterpreter
 	// erc20BridgeSigner  *signersvc.ServiceMgr
 }
 
@@ -212,6 +214,13 @@ func (s *server) Start(ctx context.Context) error {
 
 	group, groupCtx := errgroup.WithContext(ctx)
 
+	// stream committed row changes until the server stops
+	if s.cfg.DB.EnableWALStream {
+		if err := s.engine.StartWALStream(groupCtx); err != nil {
+			return fmt.Errorf("failed to start WAL stream: %w", err)
+		}
+	}
+
 	group.Go(func() error {
 		// If the DB dies unexpectedly, stop the entire error group.
 		select {
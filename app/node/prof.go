@@ -21,7 +21,7 @@ const (
 	profModeMutex    profMode = "mutex"
 )
 
-func startProfilers(mode profMode, pprofFile string) (func(), error) {
+func startProfilers(mode profMode, pprofFile, listenAddr string) (func(), error) {
 	if pprofFile == "" {
 		pprofFile = fmt.Sprintf("kwild-%s.pprof", mode)
 	}
@@ -32,8 +32,8 @@ func startProfilers(mode profMode, pprofFile string) (func(), error) {
 		// handler with the root path on the default mux.
 		http.Handle("/", http.RedirectHandler("/debug/pprof/", http.StatusSeeOther))
 		go func() {
-			fmt.Println("starting http profiler on localhost:6060")
-			if err := http.ListenAndServe("localhost:6060", nil); err != nil {
+			fmt.Printf("starting http profiler on %s\n", listenAddr)
+			if err := http.ListenAndServe(listenAddr, nil); err != nil {
 				fmt.Printf("http.ListenAndServe: %v\n", err)
 			}
 		}()
@@ -72,7 +72,7 @@ func StartCmd() *cobra.Command {
 				return string(rawToml)
 			}))
 
-			stopProfiler, err := startProfilers(profMode(cfg.ProfileMode), cfg.ProfileFile)
+			stopProfiler, err := startProfilers(profMode(cfg.ProfileMode), cfg.ProfileFile, cfg.ProfileListen)
 			if err != nil {
 				cmd.Usage()
 				return err
@@ -12,6 +12,7 @@ import (
 	"strings"
 
 	"github.com/kwilteam/kwil-db/app/shared"
+	rpcclient "github.com/kwilteam/kwil-db/core/rpc/client"
 
 	"github.com/spf13/cobra"
 )
@@ -97,17 +98,26 @@ type wrappedMsg struct {
 	Error  error        `json:"error"`
 }
 
+// MarshalJSON includes the request ID of a failed RPC request in the output,
+// so that the request can be found in the logs of the node.
 func (w *wrappedMsg) MarshalJSON() ([]byte, error) {
-	var errMsg string
+	var errMsg, requestID string
 	if w.Error != nil {
 		errMsg = w.Error.Error()
+
+		var rpcErr *rpcclient.RPCError
+		if errors.As(w.Error, &rpcErr) {
+			requestID = rpcErr.RequestID
+		}
 	}
 	return json.Marshal(struct {
-		Result MsgFormatter `json:"result"`
-		Error  string       `json:"error"`
+		Result    MsgFormatter `json:"result"`
+		Error     string       `json:"error"`
+		RequestID string       `json:"request_id,omitempty"`
 	}{
-		Result: w.Result,
-		Error:  errMsg,
+		Result:    w.Result,
+		Error:     errMsg,
+		RequestID: requestID,
 	})
 }
 
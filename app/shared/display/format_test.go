@@ -9,6 +9,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	rpcclient "github.com/kwilteam/kwil-db/core/rpc/client"
 )
 
 type demoFormat struct {
@@ -70,6 +72,18 @@ func Example_wrappedMsg_json_withError() {
 	// }
 }
 
+func Example_wrappedMsg_json_withRequestID() {
+	err := &rpcclient.RPCError{Msg: "an error", Code: -300, RequestID: "req-1"}
+	msg := wrapMsg(&demoFormat{data: []byte("demo")}, err)
+	prettyPrint(msg, "json", os.Stdout, os.Stderr)
+	// Output:
+	// {
+	//   "result": null,
+	//   "error": "err code = -300, msg = an error",
+	//   "request_id": "req-1"
+	// }
+}
+
 func TestOutputFormat_String(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -41,10 +41,20 @@ type RoundTripper func(ctx context.Context, client clientType.Client, conf *conf
 // DialClient dials a kwil node and calls the passed function with the client.
 // It includes the command that is being run, so that it can read global flags.
 func DialClient(ctx context.Context, cmd *cobra.Command, flags uint8, fn RoundTripper) error {
+	return DialClientAt(ctx, cmd, flags, "", fn)
+}
+
+// DialClientAt is like DialClient, but dials the given provider instead of the
+// configured one, if it is not empty. It is used to follow redirects to other
+// nodes.
+func DialClientAt(ctx context.Context, cmd *cobra.Command, flags uint8, provider string, fn RoundTripper) error {
 	conf, err := config.ActiveConfig()
 	if err != nil {
 		return err
 	}
+	if provider != "" {
+		conf.Provider = provider
+	}
 
 	if conf.Provider == "" {
 		return errors.New("rpc provider url is required")
@@ -19,7 +19,7 @@ import (
 
 func balanceCmd() *cobra.Command {
 	var pending bool
-	var keyTypeStr string
+	var keyTypeStr, tokenStr string
 	cmd := &cobra.Command{
 		Use:   "balance accountID keyType",
 		Short: "Gets an account's balance and nonce",
@@ -29,6 +29,11 @@ func balanceCmd() *cobra.Command {
 			var acctID *types.AccountID
 			var clientFlags uint8
 
+			token, err := parseTokenID(tokenStr)
+			if err != nil {
+				return display.PrintErr(cmd, err)
+			}
+
 			if len(args) > 0 {
 				clientFlags = client.WithoutPrivateKey
 
@@ -66,6 +71,12 @@ func balanceCmd() *cobra.Command {
 				if err != nil {
 					return display.PrintErr(cmd, fmt.Errorf("get account failed: %w", err))
 				}
+				if len(token) > 0 {
+					acct.Balance, err = cl.GetTokenBalance(ctx, acctID, token, status)
+					if err != nil {
+						return display.PrintErr(cmd, fmt.Errorf("get token balance failed: %w", err))
+					}
+				}
 				// NOTE: empty acct.Identifier means it doesn't even have a record
 				// on the network. We now convey that to the caller.
 
@@ -87,6 +98,7 @@ func balanceCmd() *cobra.Command {
 
 	cmd.Flags().BoolVar(&pending, "pending", false, "reflect pending updates from mempool (default is confirmed only)")
 	cmd.Flags().StringVarP(&keyTypeStr, "keytype", "t", crypto.KeyTypeSecp256k1.String(), "key type of account ID (default secp256k1 for Ethereum)")
+	cmd.Flags().StringVar(&tokenStr, "token", "", "hex ID of the token of the balance (default is the native token)")
 
 	return cmd
 }
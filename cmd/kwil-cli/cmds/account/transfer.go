@@ -19,11 +19,11 @@ import (
 )
 
 func transferCmd() *cobra.Command {
-	var keyTypeStr string
+	var keyTypeStr, tokenStr string
 	cmd := &cobra.Command{
 		Use:   "transfer <recipientID> <amount>",
 		Short: "Transfer value to an account",
-		Long:  `Transfers value to an account.`,
+		Long:  `Transfers value to an account. The native token is transferred, unless the hex ID of another token is given with --token.`,
 		Args:  cobra.ExactArgs(2), // recipient, amt
 		RunE: func(cmd *cobra.Command, args []string) error {
 			recipient, amt := args[0], args[1]
@@ -40,6 +40,11 @@ func transferCmd() *cobra.Command {
 				return display.PrintErr(cmd, fmt.Errorf("failed to decode account ID: %w", err))
 			}
 
+			token, err := parseTokenID(tokenStr)
+			if err != nil {
+				return display.PrintErr(cmd, err)
+			}
+
 			// NOTE: could validate on client side first if built with extensions:
 			//   keyType, err := crypto.ParseKeyType(typeStr)
 			// Otherwise we leave it to the nodes to decide if it is supported.
@@ -51,7 +56,7 @@ func transferCmd() *cobra.Command {
 
 			return client.DialClient(cmd.Context(), cmd, 0, func(ctx context.Context, cl clientType.Client, conf *config.KwilCliConfig) error {
 				txHash, err := cl.Transfer(ctx, to, amount, clientType.WithNonce(nonceOverride),
-					clientType.WithSyncBroadcast(syncBcast))
+					clientType.WithSyncBroadcast(syncBcast), clientType.WithToken(token))
 				if err != nil {
 					return display.PrintErr(cmd, fmt.Errorf("transfer failed: %w", err))
 				}
@@ -70,5 +75,19 @@ func transferCmd() *cobra.Command {
 	}
 
 	cmd.Flags().StringVarP(&keyTypeStr, "keytype", "t", crypto.KeyTypeSecp256k1.String(), "key type of the recipient account ID (default secp256k1 for Ethereum)")
+	cmd.Flags().StringVar(&tokenStr, "token", "", "hex ID of the token to transfer (default is the native token)")
 	return cmd
 }
+
+// parseTokenID decodes the hex ID of a token given with --token. It is empty
+// for the native token.
+func parseTokenID(s string) ([]byte, error) {
+	token, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s is not hex", types.ErrInvalidTokenID, s)
+	}
+	if err = types.ValidateTokenID(token); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
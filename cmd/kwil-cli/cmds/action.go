@@ -0,0 +1,157 @@
+package cmds
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/kwilteam/kwil-db/app/shared/display"
+	"github.com/kwilteam/kwil-db/cmd/kwil-cli/client"
+	"github.com/kwilteam/kwil-db/cmd/kwil-cli/config"
+	clientType "github.com/kwilteam/kwil-db/core/client/types"
+	"github.com/kwilteam/kwil-db/node/engine"
+	"github.com/kwilteam/kwil-db/node/engine/parse"
+	"github.com/spf13/cobra"
+)
+
+var (
+	actionGraphLong = `Print the graph of the calls between the actions of a namespace.
+
+The CREATE ACTION statements of the namespace are read from the node and parsed, and every
+call an action makes to another action of the namespace is an edge of the graph. Calls to
+functions, extension methods, and actions of other namespaces are not included.
+
+The graph is printed in the Graphviz DOT format, or as a Mermaid flowchart with --format
+mermaid. Calls that are part of a cycle, including actions that call themselves, are
+labeled "cycle".`
+
+	actionGraphExample = `# Print the dependency graph of the actions in the namespace 'main' in DOT format
+kwil-cli action dependency-graph --namespace main
+
+# Render the graph with Graphviz
+kwil-cli action dependency-graph --namespace main | dot -Tsvg > actions.svg
+
+# Print the dependency graph as a Mermaid flowchart
+kwil-cli action dependency-graph --namespace main --format mermaid`
+)
+
+func actionCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "action",
+		Short: "Action related commands.",
+		Long:  "Commands that inspect the actions of a namespace, such as printing their dependency graph.",
+	}
+
+	cmd.AddCommand(actionGraphCmd())
+
+	return cmd
+}
+
+func actionGraphCmd() *cobra.Command {
+	var namespace, format string
+
+	cmd := &cobra.Command{
+		Use:     "dependency-graph",
+		Short:   "Print the graph of the calls between the actions of a namespace.",
+		Long:    actionGraphLong,
+		Example: actionGraphExample,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != "dot" && format != "mermaid" {
+				return display.PrintErr(cmd, fmt.Errorf(`unknown format "%s", expected "dot" or "mermaid"`, format))
+			}
+			if namespace == "" {
+				namespace = engine.DefaultNamespace
+			}
+
+			return client.DialClient(cmd.Context(), cmd, client.WithoutPrivateKey, func(ctx context.Context, cl clientType.Client, conf *config.KwilCliConfig) error {
+				res, err := cl.Query(ctx, "{info}SELECT raw_statement FROM actions WHERE namespace = $namespace", map[string]any{
+					"namespace": namespace,
+				}, false)
+				if err != nil {
+					return display.PrintErr(cmd, err)
+				}
+
+				var actions []*parse.CreateActionStatement
+				for _, row := range res.Values {
+					raw, ok := row[0].(string)
+					if !ok {
+						return display.PrintErr(cmd, fmt.Errorf("unexpected type %T of action statement", row[0]))
+					}
+
+					stmts, err := parse.Parse(raw)
+					if err != nil {
+						return display.PrintErr(cmd, fmt.Errorf("failed to parse action: %w", err))
+					}
+					for _, stmt := range stmts {
+						if act, ok := stmt.(*parse.CreateActionStatement); ok {
+							actions = append(actions, act)
+						}
+					}
+				}
+
+				graph := parse.NewActionGraph(namespace, actions)
+				return display.PrintCmd(cmd, &respActionGraph{Namespace: namespace, Format: format, Graph: graph})
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "namespace of the actions")
+	cmd.Flags().StringVar(&format, "format", "dot", `format of the graph, "dot" or "mermaid"`)
+
+	return cmd
+}
+
+type respActionGraph struct {
+	Namespace string
+	Format    string
+	Graph     *parse.ActionGraph
+}
+
+func (r *respActionGraph) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.Graph)
+}
+
+func (r *respActionGraph) MarshalText() ([]byte, error) {
+	if r.Format == "mermaid" {
+		return []byte(mermaidActionGraph(r.Graph)), nil
+	}
+	return []byte(dotActionGraph(r.Namespace, r.Graph)), nil
+}
+
+// dotActionGraph formats an action graph in the Graphviz DOT format.
+func dotActionGraph(namespace string, g *parse.ActionGraph) string {
+	var str strings.Builder
+	fmt.Fprintf(&str, "digraph %q {\n", namespace)
+	for _, act := range g.Actions {
+		fmt.Fprintf(&str, "  %q;\n", act)
+	}
+	for _, call := range g.Calls {
+		fmt.Fprintf(&str, "  %q -> %q", call.Caller, call.Callee)
+		if call.Cyclic {
+			str.WriteString(` [label="cycle", color="red"]`)
+		}
+		str.WriteString(";\n")
+	}
+	str.WriteString("}")
+	return str.String()
+}
+
+// mermaidActionGraph formats an action graph as a Mermaid flowchart. Action
+// names are identifiers, so they are valid Mermaid node IDs.
+func mermaidActionGraph(g *parse.ActionGraph) string {
+	var str strings.Builder
+	str.WriteString("flowchart LR\n")
+	for _, act := range g.Actions {
+		fmt.Fprintf(&str, "  %s\n", act)
+	}
+	for _, call := range g.Calls {
+		if call.Cyclic {
+			fmt.Fprintf(&str, "  %s -->|cycle| %s\n", call.Caller, call.Callee)
+		} else {
+			fmt.Fprintf(&str, "  %s --> %s\n", call.Caller, call.Callee)
+		}
+	}
+	return strings.TrimSuffix(str.String(), "\n")
+}
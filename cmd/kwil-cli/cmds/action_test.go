@@ -0,0 +1,38 @@
+package cmds
+
+import (
+	"testing"
+
+	"github.com/kwilteam/kwil-db/node/engine/parse"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ActionGraphFormats(t *testing.T) {
+	stmts, err := parse.Parse(`CREATE ACTION a() public { b(); };
+	CREATE ACTION b() public { c(); };
+	CREATE ACTION c() public { b(); };`)
+	require.NoError(t, err)
+
+	var actions []*parse.CreateActionStatement
+	for _, stmt := range stmts {
+		actions = append(actions, stmt.(*parse.CreateActionStatement))
+	}
+	graph := parse.NewActionGraph("main", actions)
+
+	require.Equal(t, `digraph "main" {
+  "a";
+  "b";
+  "c";
+  "a" -> "b";
+  "b" -> "c" [label="cycle", color="red"];
+  "c" -> "b" [label="cycle", color="red"];
+}`, dotActionGraph("main", graph))
+
+	require.Equal(t, `flowchart LR
+  a
+  b
+  c
+  a --> b
+  b -->|cycle| c
+  c -->|cycle| b`, mermaidActionGraph(graph))
+}
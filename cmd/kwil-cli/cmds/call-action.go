@@ -4,7 +4,10 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
+	"net/url"
 	"strconv"
 	"strings"
 
@@ -12,6 +15,7 @@ import (
 	"github.com/kwilteam/kwil-db/cmd/kwil-cli/client"
 	"github.com/kwilteam/kwil-db/cmd/kwil-cli/config"
 	clientType "github.com/kwilteam/kwil-db/core/client/types"
+	userjson "github.com/kwilteam/kwil-db/core/rpc/json/user"
 	"github.com/kwilteam/kwil-db/core/types"
 	"github.com/spf13/cobra"
 )
@@ -23,7 +27,11 @@ This command calls a view action against the database, and formats the results i
 It can only be used to call view actions, not write actions.
 
 It is not required to have a private key configured, unless the RPC you are calling is in
-private mode, or you are talking to Kwil Gateway.`
+private mode, or you are talking to Kwil Gateway.
+
+If the namespace has a different home node than the node being called, the call is rejected
+with the address of the home node. With --follow-affinity, the call is retried once on the
+home node, using the scheme and port of the configured provider with the host of the home node.`
 
 	callActionExample = `# Call the action 'get-accounts' with no parameters
 kwil-cli call-action get-accounts
@@ -41,13 +49,16 @@ kwil-cli call-action get-account --namespace users
 kwil-cli call-action get-account --rpc-auth
 
 # Call the action 'get-account' and authenticate with Kwil Gateway
-kwil-cli call-action get-account --gateway-auth`
+kwil-cli call-action get-account --gateway-auth
+
+# Call the action 'get-account', and follow a redirect to the home node of the namespace 'users'
+kwil-cli call-action get-account --namespace users --follow-affinity`
 )
 
 func callActionCmd() *cobra.Command {
 	var namespace string
 	var namedParams []string
-	var gwAuth, rpcAuth, logs bool
+	var gwAuth, rpcAuth, logs, followAffinity bool
 
 	cmd := &cobra.Command{
 		Use:     "call-action",
@@ -116,6 +127,22 @@ func callActionCmd() *cobra.Command {
 				}
 
 				res, err := cl.Call(ctx, namespace, args[0], params)
+				var notHome userjson.NotHomeNodeError
+				if followAffinity && errors.As(err, &notHome) {
+					provider, err := homeNodeProvider(conf.Provider, notHome.HomeNode)
+					if err != nil {
+						return display.PrintErr(cmd, err)
+					}
+
+					return client.DialClientAt(ctx, cmd, dialFlags, provider, func(ctx context.Context, cl clientType.Client, conf *config.KwilCliConfig) error {
+						res, err := cl.Call(ctx, namespace, args[0], params)
+						if err != nil {
+							return display.PrintErr(cmd, err)
+						}
+
+						return display.PrintCmd(cmd, &respCall{Data: res, PrintLogs: logs, cmd: cmd})
+					})
+				}
 				if err != nil {
 					return display.PrintErr(cmd, err)
 				}
@@ -132,11 +159,40 @@ func callActionCmd() *cobra.Command {
 	cmd.Flags().BoolVar(&rpcAuth, "rpc-auth", false, "signals that the call is being made to a kwil node and should be authenticated with the private key")
 	cmd.Flags().BoolVar(&gwAuth, "gateway-auth", false, "signals that the call is being made to a gateway and should be authenticated with the private key")
 	cmd.Flags().BoolVar(&logs, "logs", false, "result will include logs from notices raised during the call")
+	cmd.Flags().BoolVar(&followAffinity, "follow-affinity", false, "retry the call on the home node of the namespace if the node redirects it")
 	display.BindTableFlags(cmd)
 
 	return cmd
 }
 
+// homeNodeProvider returns the provider URL of a namespace's home node. Since
+// the home node is identified by its P2P address, the URL has the host of that
+// address, and the scheme and port of the current provider.
+func homeNodeProvider(provider, homeNode string) (string, error) {
+	u, err := url.Parse(provider)
+	if err != nil {
+		return "", fmt.Errorf("invalid provider %s: %w", provider, err)
+	}
+
+	host, _, err := net.SplitHostPort(homeNode)
+	if err != nil {
+		host = homeNode
+	}
+	if host == "" {
+		return "", fmt.Errorf("invalid home node address %s", homeNode)
+	}
+
+	if port := u.Port(); port != "" {
+		u.Host = net.JoinHostPort(host, port)
+	} else if strings.Contains(host, ":") {
+		u.Host = "[" + host + "]"
+	} else {
+		u.Host = host
+	}
+
+	return u.String(), nil
+}
+
 type respCall struct {
 	Data      *types.CallResult
 	PrintLogs bool
@@ -0,0 +1,36 @@
+package cmds
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_HomeNodeProvider(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider string
+		homeNode string
+		want     string
+		wantErr  bool
+	}{
+		{"port of provider", "http://127.0.0.1:8484", "10.0.0.2:6600", "http://10.0.0.2:8484", false},
+		{"provider path", "https://node1.example.com:8443/rpc", "node2.example.com:6600", "https://node2.example.com:8443/rpc", false},
+		{"no provider port", "https://node1.example.com", "node2.example.com:6600", "https://node2.example.com", false},
+		{"home node without port", "http://127.0.0.1:8484", "10.0.0.2", "http://10.0.0.2:8484", false},
+		{"ipv6 home node", "http://127.0.0.1:8484", "[::1]:6600", "http://[::1]:8484", false},
+		{"empty home node", "http://127.0.0.1:8484", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := homeNodeProvider(tt.provider, tt.homeNode)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package cmds
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
@@ -31,7 +32,10 @@ This command requires a private key, and it will author a transaction from the p
 Actions can be given any mix of positional and named parameters in order to execute an action one. If you wish
 to batch execute an action, you can provide a CSV file, and map the CSV column names to either the action parameter
 name or the action parameter position. If any named parameters are specified while using a CSV file, then all batch
-action calls will set the named parameter to the same value.`
+action calls will set the named parameter to the same value.
+
+With --simulate, the action is run by the node without broadcasting a transaction, and none of its changes are kept.
+The logs, result rows, and gas usage of the action are displayed, along with whether it would succeed.`
 
 	execActionExample = `# Execute the action 'register' with no parameters
 kwil-cli exec-action register
@@ -53,12 +57,16 @@ kwil-cli exec-action register --csv /path/to/file.csv --csv-mapping name:0 --csv
 
 # Execute the action 'register' with a CSV file, but override all ages to be 10
 # Assume the same action signature and CSV file as above
-kwil-cli exec-action register --csv /path/to/file.csv --csv-mapping name:0 --param age:int=10`
+kwil-cli exec-action register --csv /path/to/file.csv --csv-mapping name:0 --param age:int=10
+
+# Simulate the action 'register' without broadcasting a transaction
+kwil-cli exec-action register text:satoshi --simulate`
 )
 
 func execActionCmd() *cobra.Command {
 	var namespace, csvFile string
 	var namedParams, csvParams []string
+	var simulate bool
 
 	cmd := &cobra.Command{
 		Use:     "exec-action",
@@ -78,6 +86,9 @@ func execActionCmd() *cobra.Command {
 			if len(args) > 1 && csvFile != "" {
 				return display.PrintErr(cmd, fmt.Errorf("cannot specify both CSV file and positional parameters"))
 			}
+			if simulate && csvFile != "" {
+				return display.PrintErr(cmd, fmt.Errorf("cannot simulate a batch of actions from a CSV file"))
+			}
 
 			// if csv file is specified, it is a batch action
 			if csvFile != "" {
@@ -161,6 +172,15 @@ func execActionCmd() *cobra.Command {
 					}
 				}
 
+				if simulate {
+					res, err := cl.SimulateCall(ctx, namespace, args[0], params, nil)
+					if err != nil {
+						return display.PrintErr(cmd, err)
+					}
+
+					return display.PrintCmd(cmd, &respSimulation{Data: res})
+				}
+
 				tx, err := cl.Execute(ctx, namespace, args[0], [][]any{params}, clientType.WithNonce(txFlags.NonceOverride), clientType.WithSyncBroadcast(txFlags.SyncBroadcast))
 				if err != nil {
 					return display.PrintErr(cmd, err)
@@ -177,10 +197,40 @@ func execActionCmd() *cobra.Command {
 	cmd.Flags().StringArrayVarP(&namedParams, "param", "p", nil, `named parameters that will override any positional or CSV parameters. format: "name:type=value"`)
 	cmd.Flags().StringVar(&csvFile, "csv", "", "CSV file containing the parameters to pass to the action")
 	cmd.Flags().StringSliceVarP(&csvParams, "csv-mapping", "m", nil, `mapping of CSV columns to action parameters. format: "csv_column:action_param_name" OR "csv_column:action_param_position"`)
+	cmd.Flags().BoolVar(&simulate, "simulate", false, "run the action on the node without broadcasting a transaction or keeping its changes")
 	common.BindTxFlags(cmd)
 	return cmd
 }
 
+type respSimulation struct {
+	Data *types.SimulationResult
+}
+
+func (r *respSimulation) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.Data)
+}
+
+func (r *respSimulation) MarshalText() ([]byte, error) {
+	var str strings.Builder
+	fmt.Fprintf(&str, "Would succeed: %t\nGas estimate: %d\n", r.Data.WouldSucceed, r.Data.GasEstimate)
+
+	if len(r.Data.ResultRows) > 0 {
+		str.WriteString("Result rows:\n")
+		for _, row := range getStringRows(r.Data.ResultRows) {
+			str.WriteString("  " + strings.Join(row, " | ") + "\n")
+		}
+	}
+
+	if len(r.Data.Logs) > 0 {
+		str.WriteString("Logs:\n")
+		for i, l := range r.Data.Logs {
+			fmt.Fprintf(&str, "  %d. %s\n", i+1, l)
+		}
+	}
+
+	return []byte(strings.TrimSuffix(str.String(), "\n")), nil
+}
+
 type actionParamInfo struct {
 	datatype *types.DataType
 	pos      int
@@ -0,0 +1,752 @@
+package cmds
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/kwilteam/kwil-db/app/shared/display"
+	"github.com/kwilteam/kwil-db/cmd/kwil-cli/client"
+	"github.com/kwilteam/kwil-db/cmd/kwil-cli/cmds/common"
+	"github.com/kwilteam/kwil-db/cmd/kwil-cli/config"
+	"github.com/kwilteam/kwil-db/cmd/kwil-cli/helpers"
+	clientType "github.com/kwilteam/kwil-db/core/client/types"
+	"github.com/kwilteam/kwil-db/core/types"
+	"github.com/kwilteam/kwil-db/node/engine"
+	"github.com/kwilteam/kwil-db/node/engine/parse"
+	"github.com/spf13/cobra"
+)
+
+var (
+	namespaceMigrateLong = `Migrate a namespace to a new schema.
+
+The current tables and indexes of the namespace are read from the node, and compared
+with the CREATE TABLE and CREATE INDEX statements in the schema file. The changes that
+migrate the namespace to the new schema are displayed, and then applied in a single
+transaction. Use --dry-run to only display them.
+
+Tables and columns that are not in the new schema are dropped, deleting their data.
+Such migrations are only applied if --allow-destructive is given. Changes that cannot be
+made without recreating a table, such as changing the type of a column, are rejected.
+
+The schema is rejected if it is not self-consistent, e.g. if a foreign key references
+a table that it does not create, or an index is on a column that its table does not have.
+
+The schema file can import other schema files with @import directives, which are
+comments with a path relative to the importing file, e.g. -- @import "tables.kf".
+The imported tables and indexes are migrated along with those of the schema file.
+
+A warning is displayed for each foreign key whose columns are not covered by an index.
+With --strict-fk-indexes, the migration is rejected instead.
+
+This command requires a private key, unless --dry-run is given.`
+
+	namespaceMigrateExample = `# Display the changes that migrate the namespace 'main' to the schema in a file
+kwil-cli namespace migrate --namespace main --schema ./schema.kf --dry-run
+
+# Migrate the namespace 'main', allowing tables and columns to be dropped
+kwil-cli namespace migrate --namespace main --schema ./schema.kf --allow-destructive`
+)
+
+var (
+	namespaceExportLong = `Export the tables of a namespace as a schema.
+
+The tables, columns, unique constraints and indexes of the namespace are read from the
+node, and printed as the CREATE TABLE and CREATE INDEX statements that create them. The
+schema can be edited and passed to 'namespace migrate'. Check and foreign key constraints,
+and the annotations of tables and columns, are not exported.`
+
+	namespaceExportExample = `# Export the tables of the namespace 'main' to a file
+kwil-cli namespace export --namespace main > schema.kf`
+)
+
+var (
+	namespaceHealthLong = `Check the health of a namespace.
+
+The namespace is checked by calling its ` + "`" + engine.HealthAction + "`" + ` action, which must be a view action
+with no parameters. The namespace is healthy if the action does not error. Namespaces
+backed by extensions can use this to report whether they are ready to serve requests.`
+
+	namespaceHealthExample = `# Check the health of the namespace 'my_ext'
+kwil-cli namespace health --namespace my_ext`
+)
+
+var (
+	namespaceReplayLong = `Display the state of an event sourced namespace at a block height.
+
+Namespaces created with the @event_sourced annotation record every INSERT, UPDATE and
+DELETE on their tables as an event. The events up to and including the block height are
+replayed with the ` + "`project_events`" + ` table function, and the rows that each table had at
+that height are displayed. Use --table to only display one table.`
+
+	namespaceReplayExample = `# Display the tables of the namespace 'ledger' as of block 100
+kwil-cli namespace replay --namespace ledger --at-block 100
+
+# Display only the table 'accounts'
+kwil-cli namespace replay --namespace ledger --at-block 100 --table accounts`
+)
+
+var (
+	namespaceSetReadOnlyLong = `Set whether a namespace is read-only.
+
+While a namespace is read-only, only its view actions can be called. Calls to its other
+actions fail, so its data cannot be modified through them. The setting is changed with
+an ALTER NAMESPACE statement, which requires the ALTER privilege on the namespace.`
+
+	namespaceSetReadOnlyExample = `# Make the namespace 'main' read-only
+kwil-cli namespace set-readonly --namespace main --enable
+
+# Allow the actions of the namespace 'main' to modify it again
+kwil-cli namespace set-readonly --namespace main --disable`
+)
+
+var (
+	namespaceConfigSetLong = `Set a configuration value of a namespace.
+
+Actions of the namespace read configuration values with the ` + "`config_get`" + ` function, and can
+set them with ` + "`config_set`" + `. Values are set with an ALTER NAMESPACE statement, which requires
+the ALTER privilege on the namespace. Keys and values cannot contain quotes or backslashes.`
+
+	namespaceConfigSetExample = `# Set the configuration value 'fee_rate' of the namespace 'main'
+kwil-cli namespace config set --namespace main --key fee_rate --value 0.25`
+
+	namespaceConfigGetLong = `Display a configuration value of a namespace.
+
+Configuration values are public, and are read from the info.namespace_config view.`
+
+	namespaceConfigGetExample = `# Display the configuration value 'fee_rate' of the namespace 'main'
+kwil-cli namespace config get --namespace main --key fee_rate`
+)
+
+func namespaceCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "namespace",
+		Short: "Namespace related commands.",
+		Long:  "Commands related to namespaces, such as migrating them to a new schema.",
+	}
+
+	cmd.AddCommand(namespaceMigrateCmd(), namespaceHealthCmd(), namespaceExportCmd(), namespaceReplayCmd(), namespaceSetReadOnlyCmd(), namespaceConfigCmd())
+
+	return cmd
+}
+
+func namespaceMigrateCmd() *cobra.Command {
+	var namespace, schemaFile string
+	var dryRun, allowDestructive, strictFKIndexes bool
+
+	cmd := &cobra.Command{
+		Use:     "migrate",
+		Short:   "Migrate a namespace to a new schema.",
+		Long:    namespaceMigrateLong,
+		Example: namespaceMigrateExample,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			txFlags, err := common.GetTxFlags(cmd)
+			if err != nil {
+				return display.PrintErr(cmd, err)
+			}
+
+			expanded, err := helpers.ExpandPath(schemaFile)
+			if err != nil {
+				return display.PrintErr(cmd, err)
+			}
+
+			schema, err := parse.ParseWithImports(expanded, os.ReadFile)
+			if err != nil {
+				return display.PrintErr(cmd, fmt.Errorf("failed to parse schema: %w", err))
+			}
+
+			if errs := parse.ValidateSchema(schema); len(errs) > 0 {
+				msgs := make([]string, len(errs))
+				for i, e := range errs {
+					msgs[i] = e.Message
+				}
+				return display.PrintErr(cmd, fmt.Errorf("invalid schema:\n%s", strings.Join(msgs, "\n")))
+			}
+
+			warnings := parse.ForeignKeyIndexWarnings(schema)
+			if strictFKIndexes && len(warnings) > 0 {
+				return display.PrintErr(cmd, errors.New(strings.Join(warnings, "\n")))
+			}
+			for _, warning := range warnings {
+				display.Log(cmd, "warning: "+warning)
+			}
+
+			var dialFlags uint8
+			if dryRun {
+				dialFlags = client.WithoutPrivateKey
+			}
+
+			return client.DialClient(cmd.Context(), cmd, dialFlags, func(ctx context.Context, cl clientType.Client, conf *config.KwilCliConfig) error {
+				current, err := introspectTables(ctx, cl, namespace)
+				if err != nil {
+					return display.PrintErr(cmd, fmt.Errorf("failed to get the current schema: %w", err))
+				}
+
+				changes, err := parse.DiffSchema(current, schema)
+				if err != nil {
+					return display.PrintErr(cmd, err)
+				}
+
+				if err = display.PrintCmd(cmd, &respSchemaDiff{Changes: changes}); err != nil {
+					return err
+				}
+				if dryRun || len(changes) == 0 {
+					return nil
+				}
+
+				if !allowDestructive {
+					for _, change := range changes {
+						if change.Destructive() {
+							return display.PrintErr(cmd, errors.New("the migration drops tables or columns, which requires --allow-destructive"))
+						}
+					}
+				}
+
+				var stmts strings.Builder
+				for _, change := range changes {
+					fmt.Fprintf(&stmts, "{%s}%s\n", namespace, change.Statement)
+				}
+
+				txHash, err := cl.ExecuteSQL(ctx, stmts.String(), nil, clientType.WithNonce(txFlags.NonceOverride), clientType.WithSyncBroadcast(txFlags.SyncBroadcast))
+				if err != nil {
+					return display.PrintErr(cmd, err)
+				}
+
+				return common.DisplayTxResult(ctx, cl, txHash, cmd)
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", engine.DefaultNamespace, "the namespace to migrate")
+	cmd.Flags().StringVar(&schemaFile, "schema", "", "the file containing the new schema")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "only display the changes of the migration")
+	cmd.Flags().BoolVar(&allowDestructive, "allow-destructive", false, "allow the migration to drop tables and columns")
+	cmd.Flags().BoolVar(&strictFKIndexes, "strict-fk-indexes", false, "reject the schema if a foreign key's columns are not covered by an index")
+	cmd.MarkFlagRequired("schema")
+	common.BindTxFlags(cmd)
+
+	return cmd
+}
+
+func namespaceExportCmd() *cobra.Command {
+	var namespace string
+
+	cmd := &cobra.Command{
+		Use:     "export",
+		Short:   "Export the tables of a namespace as a schema.",
+		Long:    namespaceExportLong,
+		Example: namespaceExportExample,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return client.DialClient(cmd.Context(), cmd, client.WithoutPrivateKey, func(ctx context.Context, cl clientType.Client, conf *config.KwilCliConfig) error {
+				tables, err := introspectTables(ctx, cl, namespace)
+				if err != nil {
+					return display.PrintErr(cmd, fmt.Errorf("failed to get the current schema: %w", err))
+				}
+
+				schema, err := parse.GenerateSchema(tables)
+				if err != nil {
+					return display.PrintErr(cmd, err)
+				}
+
+				return display.PrintCmd(cmd, display.RespString(schema))
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", engine.DefaultNamespace, "the namespace to export")
+
+	return cmd
+}
+
+func namespaceHealthCmd() *cobra.Command {
+	var namespace string
+
+	cmd := &cobra.Command{
+		Use:     "health",
+		Short:   "Check the health of a namespace.",
+		Long:    namespaceHealthLong,
+		Example: namespaceHealthExample,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return client.DialClient(cmd.Context(), cmd, client.WithoutPrivateKey, func(ctx context.Context, cl clientType.Client, conf *config.KwilCliConfig) error {
+				res, err := cl.Call(ctx, namespace, engine.HealthAction, nil)
+				if err != nil {
+					return display.PrintErr(cmd, fmt.Errorf("namespace %s is not healthy: %w", namespace, err))
+				}
+				if res.Error != nil {
+					return display.PrintErr(cmd, fmt.Errorf("namespace %s is not healthy: %s", namespace, *res.Error))
+				}
+
+				return display.PrintCmd(cmd, display.RespString(fmt.Sprintf("namespace %s is healthy", namespace)))
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "the namespace to check")
+	cmd.MarkFlagRequired("namespace")
+
+	return cmd
+}
+
+func namespaceReplayCmd() *cobra.Command {
+	var namespace, table string
+	var atBlock int64
+
+	cmd := &cobra.Command{
+		Use:     "replay",
+		Short:   "Display the state of an event sourced namespace at a block height.",
+		Long:    namespaceReplayLong,
+		Example: namespaceReplayExample,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return client.DialClient(cmd.Context(), cmd, client.WithoutPrivateKey, func(ctx context.Context, cl clientType.Client, conf *config.KwilCliConfig) error {
+				tables, err := introspectTables(ctx, cl, namespace)
+				if err != nil {
+					return display.PrintErr(cmd, fmt.Errorf("failed to get the tables of the namespace: %w", err))
+				}
+
+				resp := &respProjection{cmd: cmd}
+				for _, tbl := range tables {
+					if table != "" && !strings.EqualFold(tbl.Name, table) {
+						continue
+					}
+
+					projected, err := projectTable(ctx, cl, namespace, tbl, atBlock)
+					if err != nil {
+						return display.PrintErr(cmd, err)
+					}
+					resp.Tables = append(resp.Tables, projected)
+				}
+				if table != "" && len(resp.Tables) == 0 {
+					return display.PrintErr(cmd, fmt.Errorf("table %s does not exist in namespace %s", table, namespace))
+				}
+
+				return display.PrintCmd(cmd, resp)
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "the event sourced namespace to replay")
+	cmd.Flags().Int64Var(&atBlock, "at-block", 0, "the block height to replay the events up to, inclusive")
+	cmd.Flags().StringVar(&table, "table", "", "only display this table")
+	cmd.MarkFlagRequired("namespace")
+	cmd.MarkFlagRequired("at-block")
+	display.BindTableFlags(cmd)
+
+	return cmd
+}
+
+func namespaceSetReadOnlyCmd() *cobra.Command {
+	var namespace string
+	var enable, disable bool
+
+	cmd := &cobra.Command{
+		Use:     "set-readonly",
+		Short:   "Set whether a namespace is read-only.",
+		Long:    namespaceSetReadOnlyLong,
+		Example: namespaceSetReadOnlyExample,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			txFlags, err := common.GetTxFlags(cmd)
+			if err != nil {
+				return display.PrintErr(cmd, err)
+			}
+
+			stmt := setReadOnlyStatement(namespace, enable)
+
+			return client.DialClient(cmd.Context(), cmd, 0, func(ctx context.Context, cl clientType.Client, conf *config.KwilCliConfig) error {
+				txHash, err := cl.ExecuteSQL(ctx, stmt, nil, clientType.WithNonce(txFlags.NonceOverride), clientType.WithSyncBroadcast(txFlags.SyncBroadcast))
+				if err != nil {
+					return display.PrintErr(cmd, err)
+				}
+
+				return common.DisplayTxResult(ctx, cl, txHash, cmd)
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "the namespace to set")
+	cmd.Flags().BoolVar(&enable, "enable", false, "make the namespace read-only")
+	cmd.Flags().BoolVar(&disable, "disable", false, "make the namespace writable")
+	cmd.MarkFlagRequired("namespace")
+	cmd.MarkFlagsOneRequired("enable", "disable")
+	cmd.MarkFlagsMutuallyExclusive("enable", "disable")
+	common.BindTxFlags(cmd)
+
+	return cmd
+}
+
+// setReadOnlyStatement returns the statement that sets whether a namespace is
+// read-only.
+func setReadOnlyStatement(namespace string, readOnly bool) string {
+	if readOnly {
+		return "ALTER NAMESPACE " + namespace + " SET READ ONLY;"
+	}
+	return "ALTER NAMESPACE " + namespace + " SET READ WRITE;"
+}
+
+func namespaceConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Set and display the configuration values of a namespace.",
+		Long:  "Commands that set and display the configuration values that the actions of a namespace read with config_get.",
+	}
+
+	cmd.AddCommand(namespaceConfigSetCmd(), namespaceConfigGetCmd())
+
+	return cmd
+}
+
+func namespaceConfigSetCmd() *cobra.Command {
+	var namespace, key, value string
+
+	cmd := &cobra.Command{
+		Use:     "set",
+		Short:   "Set a configuration value of a namespace.",
+		Long:    namespaceConfigSetLong,
+		Example: namespaceConfigSetExample,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			txFlags, err := common.GetTxFlags(cmd)
+			if err != nil {
+				return display.PrintErr(cmd, err)
+			}
+
+			stmt, err := setConfigStatement(namespace, key, value)
+			if err != nil {
+				return display.PrintErr(cmd, err)
+			}
+
+			return client.DialClient(cmd.Context(), cmd, 0, func(ctx context.Context, cl clientType.Client, conf *config.KwilCliConfig) error {
+				txHash, err := cl.ExecuteSQL(ctx, stmt, nil, clientType.WithNonce(txFlags.NonceOverride), clientType.WithSyncBroadcast(txFlags.SyncBroadcast))
+				if err != nil {
+					return display.PrintErr(cmd, err)
+				}
+
+				return common.DisplayTxResult(ctx, cl, txHash, cmd)
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "the namespace to configure")
+	cmd.Flags().StringVar(&key, "key", "", "the key of the configuration value")
+	cmd.Flags().StringVar(&value, "value", "", "the configuration value")
+	cmd.MarkFlagRequired("namespace")
+	cmd.MarkFlagRequired("key")
+	cmd.MarkFlagRequired("value")
+	common.BindTxFlags(cmd)
+
+	return cmd
+}
+
+func namespaceConfigGetCmd() *cobra.Command {
+	var namespace, key string
+
+	cmd := &cobra.Command{
+		Use:     "get",
+		Short:   "Display a configuration value of a namespace.",
+		Long:    namespaceConfigGetLong,
+		Example: namespaceConfigGetExample,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return client.DialClient(cmd.Context(), cmd, client.WithoutPrivateKey, func(ctx context.Context, cl clientType.Client, conf *config.KwilCliConfig) error {
+				res, err := cl.Query(ctx, "{info}SELECT value FROM namespace_config WHERE namespace = $namespace AND key = $key", map[string]any{
+					"namespace": namespace,
+					"key":       key,
+				}, false)
+				if err != nil {
+					return display.PrintErr(cmd, err)
+				}
+				if len(res.Values) == 0 {
+					return display.PrintErr(cmd, fmt.Errorf(`configuration value "%s" of namespace %s is not set`, key, namespace))
+				}
+
+				value, ok := res.Values[0][0].(string)
+				if !ok {
+					return display.PrintErr(cmd, fmt.Errorf("unexpected type %T of configuration value", res.Values[0][0]))
+				}
+
+				return display.PrintCmd(cmd, display.RespString(value))
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "the namespace to read")
+	cmd.Flags().StringVar(&key, "key", "", "the key of the configuration value")
+	cmd.MarkFlagRequired("namespace")
+	cmd.MarkFlagRequired("key")
+
+	return cmd
+}
+
+// setConfigStatement returns the statement that sets a configuration value of
+// a namespace. String literals are not unescaped, so keys and values with
+// quotes or backslashes cannot be written.
+func setConfigStatement(namespace, key, value string) (string, error) {
+	for _, s := range []string{key, value} {
+		if strings.ContainsAny(s, `'\`) {
+			return "", fmt.Errorf("configuration keys and values cannot contain quotes or backslashes: %s", s)
+		}
+	}
+
+	return "ALTER NAMESPACE " + namespace + " SET CONFIG '" + key + "' = '" + value + "';", nil
+}
+
+// projectedTable is the state of a table at a block height.
+type projectedTable struct {
+	Name    string           `json:"name"`
+	Columns []string         `json:"columns"`
+	Rows    []map[string]any `json:"rows"`
+}
+
+// projectTable replays the events of a table up to a block height. The rows
+// are returned by project_events as JSON objects, which are decoded with their
+// numbers as they were written, so that large integers and decimals are not
+// rounded.
+func projectTable(ctx context.Context, cl clientType.Client, namespace string, tbl *engine.Table, atBlock int64) (*projectedTable, error) {
+	res, err := cl.Query(ctx, "SELECT row_data FROM project_events($namespace, $table, $height)", map[string]any{
+		"namespace": namespace,
+		"table":     tbl.Name,
+		"height":    atBlock,
+	}, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to project table %s: %w", tbl.Name, err)
+	}
+
+	projected := &projectedTable{Name: tbl.Name, Rows: []map[string]any{}}
+	for _, col := range tbl.Columns {
+		projected.Columns = append(projected.Columns, col.Name)
+	}
+
+	var data string
+	err = res.Scan(func() error {
+		dec := json.NewDecoder(strings.NewReader(data))
+		dec.UseNumber()
+
+		row := make(map[string]any)
+		if err := dec.Decode(&row); err != nil {
+			return fmt.Errorf("failed to decode row of table %s: %w", tbl.Name, err)
+		}
+		projected.Rows = append(projected.Rows, row)
+		return nil
+	}, &data)
+	if err != nil {
+		return nil, err
+	}
+
+	return projected, nil
+}
+
+type respProjection struct {
+	Tables []*projectedTable
+	// cmd is used for table formatting
+	cmd *cobra.Command
+}
+
+func (r *respProjection) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.Tables)
+}
+
+func (r *respProjection) MarshalText() ([]byte, error) {
+	var str strings.Builder
+	for i, tbl := range r.Tables {
+		if i > 0 {
+			str.WriteString("\n\n")
+		}
+
+		rows := make([][]any, len(tbl.Rows))
+		for j, row := range tbl.Rows {
+			rows[j] = make([]any, len(tbl.Columns))
+			for k, col := range tbl.Columns {
+				rows[j][k] = row[col]
+			}
+		}
+
+		formatted, err := display.FormatTable(r.cmd, tbl.Columns, getStringRows(rows))
+		if err != nil {
+			return nil, err
+		}
+
+		fmt.Fprintf(&str, "Table %s:\n", tbl.Name)
+		str.Write(formatted)
+	}
+
+	return []byte(str.String()), nil
+}
+
+// introspectTables reads the tables of a namespace, with their columns, unique
+// constraints, and the indexes that were not created by constraints, from the
+// info namespace.
+func introspectTables(ctx context.Context, cl clientType.Client, namespace string) ([]*engine.Table, error) {
+	params := map[string]any{"namespace": namespace}
+
+	res, err := cl.Query(ctx, "SELECT name FROM info.tables WHERE namespace = $namespace", params, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var tables []*engine.Table
+	tablesByName := make(map[string]*engine.Table)
+	var name string
+	err = res.Scan(func() error {
+		tbl := &engine.Table{Name: name}
+		tables = append(tables, tbl)
+		tablesByName[name] = tbl
+		return nil
+	}, &name)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err = cl.Query(ctx, `SELECT table_name, name, data_type, is_nullable, is_primary_key
+	FROM info.columns WHERE namespace = $namespace ORDER BY table_name, ordinal_position`, params, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var tableName, dataType string
+	var nullable, primaryKey bool
+	err = res.Scan(func() error {
+		tbl, ok := tablesByName[tableName]
+		if !ok {
+			// columns of the engine's internal tables
+			return nil
+		}
+
+		dt, err := types.ParseDataType(dataType)
+		if err != nil {
+			return fmt.Errorf("column %s.%s: %w", tableName, name, err)
+		}
+
+		tbl.Columns = append(tbl.Columns, &engine.Column{
+			Name:         name,
+			DataType:     dt,
+			Nullable:     nullable,
+			IsPrimaryKey: primaryKey,
+		})
+		return nil
+	}, &tableName, &name, &dataType, &nullable, &primaryKey)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err = cl.Query(ctx, `SELECT table_name, name, constraint_type, columns
+	FROM info.constraints WHERE namespace = $namespace`, params, true)
+	if err != nil {
+		return nil, err
+	}
+
+	constraints := make(map[string]struct{})
+	var constraintType string
+	var columns []string
+	err = res.Scan(func() error {
+		constraints[name] = struct{}{}
+
+		tbl, ok := tablesByName[tableName]
+		if !ok || !strings.EqualFold(constraintType, "unique") {
+			return nil
+		}
+		if tbl.Constraints == nil {
+			tbl.Constraints = make(map[string]*engine.Constraint)
+		}
+		tbl.Constraints[name] = &engine.Constraint{
+			Type:    engine.ConstraintUnique,
+			Columns: columns,
+		}
+		return nil
+	}, &tableName, &name, &constraintType, &columns)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err = cl.Query(ctx, `SELECT table_name, name, is_primary_key, is_unique, columns
+	FROM info.indexes WHERE namespace = $namespace`, params, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var unique bool
+	err = res.Scan(func() error {
+		tbl, ok := tablesByName[tableName]
+		if !ok {
+			return nil
+		}
+		if _, ok := constraints[name]; ok {
+			return nil
+		}
+
+		idx := &engine.Index{
+			Name:    name,
+			Columns: columns,
+			Type:    engine.BTREE,
+		}
+		switch {
+		case primaryKey:
+			idx.Type = engine.PRIMARY
+		case unique:
+			idx.Type = engine.UNIQUE_BTREE
+		}
+		tbl.Indexes = append(tbl.Indexes, idx)
+		return nil
+	}, &tableName, &name, &primaryKey, &unique, &columns)
+	if err != nil {
+		return nil, err
+	}
+
+	return tables, nil
+}
+
+type respSchemaDiff struct {
+	Changes []*parse.SchemaChange
+}
+
+func (r *respSchemaDiff) MarshalJSON() ([]byte, error) {
+	type change struct {
+		Type        parse.SchemaChangeType `json:"type"`
+		Table       string                 `json:"table"`
+		Name        string                 `json:"name,omitempty"`
+		Statement   string                 `json:"statement"`
+		Destructive bool                   `json:"destructive"`
+	}
+
+	changes := make([]change, len(r.Changes))
+	for i, c := range r.Changes {
+		changes[i] = change{
+			Type:        c.Type,
+			Table:       c.Table,
+			Name:        c.Name,
+			Statement:   c.Statement,
+			Destructive: c.Destructive(),
+		}
+	}
+
+	return json.Marshal(changes)
+}
+
+func (r *respSchemaDiff) MarshalText() ([]byte, error) {
+	if len(r.Changes) == 0 {
+		return []byte("The namespace already has the new schema."), nil
+	}
+
+	var str strings.Builder
+	str.WriteString("Changes:\n")
+	for _, c := range r.Changes {
+		marker := "~"
+		switch {
+		case c.Destructive():
+			marker = "-"
+		case c.Type == parse.SchemaChangeCreateTable || c.Type == parse.SchemaChangeAddColumn || c.Type == parse.SchemaChangeCreateIndex:
+			marker = "+"
+		case c.Type == parse.SchemaChangeDropIndex:
+			marker = "-"
+		}
+		fmt.Fprintf(&str, "  %s %s\n", marker, c)
+	}
+
+	return []byte(strings.TrimSuffix(str.String(), "\n")), nil
+}
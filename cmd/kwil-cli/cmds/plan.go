@@ -0,0 +1,134 @@
+package cmds
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/kwilteam/kwil-db/app/shared/display"
+	"github.com/kwilteam/kwil-db/cmd/kwil-cli/client"
+	"github.com/kwilteam/kwil-db/cmd/kwil-cli/config"
+	clientType "github.com/kwilteam/kwil-db/core/client/types"
+	"github.com/kwilteam/kwil-db/core/types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	planCompareLong = `Compare the query plans of an action against a stored baseline.
+
+The node calls the action in a transaction that is rolled back, and compares the plan of
+each query it executes with the plans stored in the baseline. This can be used to detect
+plan regressions caused by schema changes or Postgres upgrades.
+
+Since the action is called without a caller, it cannot depend on variables such as @caller.
+Plan comparison is not available on nodes in private mode.`
+
+	planCompareExample = `# Compare the plans of the action 'get-posts' against the baseline 'v1'
+kwil-cli plan compare --namespace main --action get-posts --baseline v1
+
+# Compare the plans of the action 'get-posts' called with one positional parameter
+kwil-cli plan compare --namespace main --action get-posts --baseline v1 int:1`
+)
+
+func planCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "plan",
+		Short: "Query plan related commands.",
+		Long:  "Commands related to the query plans of actions, such as comparing them against a baseline.",
+	}
+
+	cmd.AddCommand(planCompareCmd())
+
+	return cmd
+}
+
+// planComparer is implemented by clients that can compare query plans. The
+// gateway client does not.
+type planComparer interface {
+	ComparePlans(ctx context.Context, namespace, action string, inputs []any, baselineID string) (*types.PlanComparison, error)
+}
+
+func planCompareCmd() *cobra.Command {
+	var namespace, action, baseline string
+
+	cmd := &cobra.Command{
+		Use:     "compare",
+		Short:   "Compare the query plans of an action against a baseline.",
+		Long:    planCompareLong,
+		Example: planCompareExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var params []any
+			for _, p := range args {
+				_, param, err := parseTypedParam(p)
+				if err != nil {
+					return display.PrintErr(cmd, err)
+				}
+
+				params = append(params, param)
+			}
+
+			return client.DialClient(cmd.Context(), cmd, client.WithoutPrivateKey, func(ctx context.Context, cl clientType.Client, conf *config.KwilCliConfig) error {
+				comparer, ok := cl.(planComparer)
+				if !ok {
+					return display.PrintErr(cmd, errors.New("the client does not support plan comparison"))
+				}
+
+				res, err := comparer.ComparePlans(ctx, namespace, action, params, baseline)
+				if err != nil {
+					return display.PrintErr(cmd, err)
+				}
+
+				return display.PrintCmd(cmd, &respPlanComparison{Data: res})
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "namespace of the action")
+	cmd.Flags().StringVarP(&action, "action", "a", "", "name of the action")
+	cmd.Flags().StringVarP(&baseline, "baseline", "b", "", "ID of the plan baseline to compare against")
+	cmd.MarkFlagRequired("action")
+	cmd.MarkFlagRequired("baseline")
+
+	return cmd
+}
+
+type respPlanComparison struct {
+	Data *types.PlanComparison
+}
+
+func (r *respPlanComparison) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.Data)
+}
+
+func (r *respPlanComparison) MarshalText() ([]byte, error) {
+	var str strings.Builder
+	if !r.Data.Changed {
+		str.WriteString("Plans are unchanged.\n")
+	} else {
+		str.WriteString("Plans have changed.\n")
+	}
+
+	if len(r.Data.AddedNodes) > 0 {
+		str.WriteString("Added nodes:\n")
+		for _, node := range r.Data.AddedNodes {
+			fmt.Fprintf(&str, "  + %s\n", node)
+		}
+	}
+	if len(r.Data.RemovedNodes) > 0 {
+		str.WriteString("Removed nodes:\n")
+		for _, node := range r.Data.RemovedNodes {
+			fmt.Fprintf(&str, "  - %s\n", node)
+		}
+	}
+
+	delta := strconv.FormatFloat(r.Data.CostDelta, 'f', 2, 64)
+	if r.Data.CostDelta >= 0 {
+		delta = "+" + delta
+	}
+	str.WriteString("Cost delta: " + delta)
+
+	return []byte(str.String()), nil
+}
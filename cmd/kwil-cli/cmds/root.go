@@ -80,6 +80,10 @@ func NewRootCmd() *cobra.Command {
 		execActionCmd(),
 		callActionCmd(),
 		queryCmd(),
+		planCmd(),
+		actionCmd(),
+		namespaceCmd(),
+		schemaCmd(),
 	)
 
 	shared.ApplySanitizedHelpFuncRecursively(rootCmd)
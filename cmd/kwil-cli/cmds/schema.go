@@ -0,0 +1,457 @@
+package cmds
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/kwilteam/kwil-db/app/shared"
+	"github.com/kwilteam/kwil-db/app/shared/display"
+	"github.com/kwilteam/kwil-db/cmd/kwil-cli/client"
+	"github.com/kwilteam/kwil-db/cmd/kwil-cli/config"
+	"github.com/kwilteam/kwil-db/cmd/kwil-cli/helpers"
+	clientType "github.com/kwilteam/kwil-db/core/client/types"
+	"github.com/kwilteam/kwil-db/core/types"
+	"github.com/kwilteam/kwil-db/node/engine"
+	"github.com/kwilteam/kwil-db/node/engine/parse"
+	"github.com/spf13/cobra"
+)
+
+var (
+	schemaLintLong = `Lint a schema file before it is deployed.
+
+The schema is parsed and checked for errors, such as foreign keys that reference tables
+the schema does not create, or indexes on columns their table does not have. Foreign keys
+whose columns are not covered by an index, and actions that filter a table by columns no
+index covers, are reported as warnings. Use --require-fk-indexes to report foreign keys
+without an index as errors, and --max-columns to limit the number of columns of tables.
+
+The command fails if there are errors, and succeeds if there are only warnings. It does
+not connect to a node.`
+
+	schemaLintExample = `# Lint a schema file
+kwil-cli schema lint --file ./schema.kf
+
+# Lint a schema file, requiring indexes on foreign keys
+kwil-cli schema lint --file ./schema.kf --require-fk-indexes`
+
+	schemaFmtLong = `Format a schema file as canonical Kuneiform.
+
+The schema is parsed and written back with upper case keywords, lower case identifiers
+and types, and consistent indentation and spacing. Comments are not kept, except for
+annotations such as ` + "`@sensitive`" + `, and @import directives, which are moved to the
+top of the schema. Imported files are not formatted. Formatting a formatted schema does not change it.
+
+The formatted schema is printed, unless --write is given, in which case the file is
+overwritten with it. It does not connect to a node.`
+
+	schemaFmtExample = `# Print the formatted schema
+kwil-cli schema fmt --file ./schema.kf
+
+# Format the schema file in place
+kwil-cli schema fmt --file ./schema.kf --write`
+
+	schemaTestLong = `Run the test actions of a schema file.
+
+The schema is deployed to a temporary test namespace, named after --namespace, and each
+action annotated with ` + "`@test`" + ` is executed in its own transaction. A test passes if its
+transaction succeeds, and fails if the action raises an error. The test namespace is
+dropped once the tests have run.
+
+Test actions can only be created in namespaces declared with ` + "`@test_namespace`" + `, and
+cannot be run by read-only calls. The command fails if a test fails.`
+
+	schemaTestExample = `# Run the tests of a schema file
+kwil-cli schema test --namespace ledger --file ./schema.kf`
+)
+
+func schemaCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schema",
+		Short: "Schema related commands.",
+		Long:  "Commands that work with schema files, such as linting, formatting and testing them.",
+	}
+
+	cmd.AddCommand(schemaLintCmd(), schemaFmtCmd(), schemaTestCmd())
+
+	return cmd
+}
+
+func schemaLintCmd() *cobra.Command {
+	var file string
+	var rules parse.LintRules
+
+	cmd := &cobra.Command{
+		Use:     "lint",
+		Short:   "Lint a schema file.",
+		Long:    schemaLintLong,
+		Example: schemaLintExample,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			expanded, err := helpers.ExpandPath(file)
+			if err != nil {
+				return display.PrintErr(cmd, err)
+			}
+
+			source, err := os.ReadFile(expanded)
+			if err != nil {
+				return display.PrintErr(cmd, err)
+			}
+
+			schema, err := parse.Parse(string(source))
+			if err != nil {
+				return display.PrintErr(cmd, fmt.Errorf("failed to parse schema: %w", err))
+			}
+
+			res := &respLintResult{File: file, Issues: parse.LintSchema(schema, rules)}
+			if err = display.PrintCmd(cmd, res); err != nil {
+				return err
+			}
+
+			// the result is already printed, so the error is only recorded
+			// for the exit code
+			if n := res.count(parse.LintError); n > 0 {
+				shared.SetCmdCtxErr(cmd, fmt.Errorf("schema has %d errors", n))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "file", "f", "", "the schema file to lint")
+	cmd.Flags().IntVar(&rules.MaxColumnsPerTable, "max-columns", 0, "the maximum number of columns of a table, or 0 for no limit")
+	cmd.Flags().BoolVar(&rules.RequireIndexOnForeignKey, "require-fk-indexes", false, "report foreign keys whose columns are not covered by an index as errors")
+	cmd.MarkFlagRequired("file")
+
+	return cmd
+}
+
+func schemaFmtCmd() *cobra.Command {
+	var file string
+	var write bool
+
+	cmd := &cobra.Command{
+		Use:     "fmt",
+		Short:   "Format a schema file.",
+		Long:    schemaFmtLong,
+		Example: schemaFmtExample,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			expanded, err := helpers.ExpandPath(file)
+			if err != nil {
+				return display.PrintErr(cmd, err)
+			}
+
+			info, err := os.Stat(expanded)
+			if err != nil {
+				return display.PrintErr(cmd, err)
+			}
+
+			source, err := os.ReadFile(expanded)
+			if err != nil {
+				return display.PrintErr(cmd, err)
+			}
+
+			res, err := parse.ParseWithErrListener(string(source))
+			if err != nil {
+				return display.PrintErr(cmd, fmt.Errorf("failed to parse schema: %w", err))
+			}
+			if res.Err() != nil {
+				return display.PrintErr(cmd, fmt.Errorf("failed to parse schema: %w", res.Err()))
+			}
+
+			formatted, err := parse.Format(res.Statements)
+			if err != nil {
+				return display.PrintErr(cmd, fmt.Errorf("failed to format schema: %w", err))
+			}
+
+			// directives are comments, so they are not kept by Format
+			if len(res.Imports) > 0 {
+				var directives strings.Builder
+				for _, imp := range res.Imports {
+					fmt.Fprintf(&directives, "-- @import %q\n", imp)
+				}
+				formatted = directives.String() + "\n" + formatted
+			}
+
+			if !write {
+				return display.PrintCmd(cmd, display.RespString(strings.TrimSuffix(formatted, "\n")))
+			}
+
+			if formatted == string(source) {
+				return display.PrintCmd(cmd, display.RespString(fmt.Sprintf("%s is already formatted", file)))
+			}
+
+			if err = os.WriteFile(expanded, []byte(formatted), info.Mode().Perm()); err != nil {
+				return display.PrintErr(cmd, err)
+			}
+
+			return display.PrintCmd(cmd, display.RespString(fmt.Sprintf("formatted %s", file)))
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "file", "f", "", "the schema file to format")
+	cmd.Flags().BoolVarP(&write, "write", "w", false, "overwrite the file with the formatted schema instead of printing it")
+	cmd.MarkFlagRequired("file")
+
+	return cmd
+}
+
+func schemaTestCmd() *cobra.Command {
+	var namespace, file string
+
+	cmd := &cobra.Command{
+		Use:     "test",
+		Short:   "Run the test actions of a schema file.",
+		Long:    schemaTestLong,
+		Example: schemaTestExample,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			expanded, err := helpers.ExpandPath(file)
+			if err != nil {
+				return display.PrintErr(cmd, err)
+			}
+
+			schema, err := parse.ParseWithImports(expanded, os.ReadFile)
+			if err != nil {
+				return display.PrintErr(cmd, fmt.Errorf("failed to parse schema: %w", err))
+			}
+
+			return client.DialClient(cmd.Context(), cmd, 0, func(ctx context.Context, cl clientType.Client, conf *config.KwilCliConfig) error {
+				res, err := runSchemaTests(ctx, cl, namespace, schema)
+				if err != nil {
+					return display.PrintErr(cmd, err)
+				}
+
+				return printSchemaTests(cmd, res)
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", engine.DefaultNamespace, "the namespace that the test namespace is named after")
+	cmd.Flags().StringVarP(&file, "file", "f", "", "the schema file to test")
+	cmd.MarkFlagRequired("file")
+
+	return cmd
+}
+
+// schemaTestClient is the part of the client that runs schema tests.
+type schemaTestClient interface {
+	Execute(ctx context.Context, namespace string, action string, tuples [][]any, opts ...clientType.TxOpt) (types.Hash, error)
+	ExecuteSQL(ctx context.Context, sql string, params map[string]any, opts ...clientType.TxOpt) (types.Hash, error)
+	WaitTx(ctx context.Context, txHash types.Hash, interval time.Duration) (*types.TxQueryResponse, error)
+}
+
+// schemaTestPollInterval is how often the results of test transactions are
+// polled.
+const schemaTestPollInterval = 500 * time.Millisecond
+
+// runSchemaTests deploys the schema to a temporary test namespace, runs its
+// @test actions, and drops the namespace.
+func runSchemaTests(ctx context.Context, cl schemaTestClient, namespace string, schema []parse.TopLevelStatement) (res *respSchemaTests, err error) {
+	suffix := make([]byte, 4)
+	if _, err = rand.Read(suffix); err != nil {
+		return nil, err
+	}
+	res = &respSchemaTests{Namespace: fmt.Sprintf("%s_test_%s", namespace, hex.EncodeToString(suffix))}
+
+	stmts := []parse.TopLevelStatement{&parse.CreateNamespaceStatement{Namespace: res.Namespace, Test: true}}
+	var tests []string
+	for _, stmt := range schema {
+		namespaced, ok := stmt.(interface{ SetNamespacePrefix(string) })
+		if !ok {
+			return nil, errors.New("schemas with tests cannot create, drop or switch namespaces")
+		}
+		namespaced.SetNamespacePrefix(res.Namespace)
+		stmts = append(stmts, stmt)
+
+		if act, ok := stmt.(*parse.CreateActionStatement); ok && act.Test {
+			tests = append(tests, act.Name)
+		}
+	}
+	if len(tests) == 0 {
+		return nil, errors.New("the schema has no @test actions")
+	}
+
+	deploy, err := parse.Format(stmts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to format schema: %w", err)
+	}
+
+	// run waits for the transaction and returns its error, if it failed
+	run := func(txHash types.Hash, err error) error {
+		if err != nil {
+			return err
+		}
+		resp, err := cl.WaitTx(ctx, txHash, schemaTestPollInterval)
+		if err != nil {
+			return err
+		}
+		if resp.Result.Code != uint32(types.CodeOk) {
+			return errors.New(resp.Result.Log)
+		}
+		return nil
+	}
+
+	if err = run(cl.ExecuteSQL(ctx, deploy, nil)); err != nil {
+		return nil, fmt.Errorf("failed to deploy schema: %w", err)
+	}
+	defer func() {
+		if dropErr := run(cl.ExecuteSQL(ctx, "DROP NAMESPACE "+res.Namespace, nil)); dropErr != nil {
+			err = errors.Join(err, fmt.Errorf("failed to drop test namespace %s: %w", res.Namespace, dropErr))
+		}
+	}()
+
+	for _, test := range tests {
+		result := &schemaTestResult{Action: test}
+		if testErr := run(cl.Execute(ctx, res.Namespace, test, [][]any{{}})); testErr != nil {
+			result.Error = testErr.Error()
+		}
+		res.Results = append(res.Results, result)
+	}
+
+	return res, nil
+}
+
+// printSchemaTests prints the test report, and records an error for the exit
+// code if a test failed.
+func printSchemaTests(cmd *cobra.Command, res *respSchemaTests) error {
+	if err := display.PrintCmd(cmd, res); err != nil {
+		return err
+	}
+
+	// the report is already printed, so the error is only recorded for the
+	// exit code
+	if n := res.failed(); n > 0 {
+		shared.SetCmdCtxErr(cmd, fmt.Errorf("%d of %d tests failed", n, len(res.Results)))
+	}
+	return nil
+}
+
+type schemaTestResult struct {
+	Action string `json:"action"`
+	// Error is the error raised by the test, if it failed.
+	Error string `json:"error,omitempty"`
+}
+
+type respSchemaTests struct {
+	Namespace string
+	Results   []*schemaTestResult
+}
+
+// failed returns the number of tests that failed.
+func (r *respSchemaTests) failed() int {
+	n := 0
+	for _, res := range r.Results {
+		if res.Error != "" {
+			n++
+		}
+	}
+	return n
+}
+
+func (r *respSchemaTests) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Namespace string              `json:"namespace"`
+		Passed    int                 `json:"passed"`
+		Failed    int                 `json:"failed"`
+		Results   []*schemaTestResult `json:"results"`
+	}{
+		Namespace: r.Namespace,
+		Passed:    len(r.Results) - r.failed(),
+		Failed:    r.failed(),
+		Results:   r.Results,
+	})
+}
+
+func (r *respSchemaTests) MarshalText() ([]byte, error) {
+	var str strings.Builder
+	for _, res := range r.Results {
+		if res.Error != "" {
+			fmt.Fprintf(&str, "FAIL %s: %s\n", res.Action, res.Error)
+		} else {
+			fmt.Fprintf(&str, "PASS %s\n", res.Action)
+		}
+	}
+	fmt.Fprintf(&str, "%d passed, %d failed", len(r.Results)-r.failed(), r.failed())
+
+	return []byte(str.String()), nil
+}
+
+type respLintResult struct {
+	File   string
+	Issues []*parse.LintIssue
+}
+
+// count returns the number of issues of a severity.
+func (r *respLintResult) count(severity parse.LintSeverity) int {
+	n := 0
+	for _, issue := range r.Issues {
+		if issue.Severity == severity {
+			n++
+		}
+	}
+	return n
+}
+
+func (r *respLintResult) MarshalJSON() ([]byte, error) {
+	type issue struct {
+		Severity parse.LintSeverity `json:"severity"`
+		Message  string             `json:"message"`
+		Line     int                `json:"line,omitempty"`
+		Column   int                `json:"column,omitempty"`
+	}
+
+	issues := make([]issue, len(r.Issues))
+	for i, is := range r.Issues {
+		line, col := lintPosition(is)
+		issues[i] = issue{
+			Severity: is.Severity,
+			Message:  is.Message,
+			Line:     line,
+			Column:   col,
+		}
+	}
+
+	return json.Marshal(struct {
+		File     string  `json:"file"`
+		Errors   int     `json:"errors"`
+		Warnings int     `json:"warnings"`
+		Issues   []issue `json:"issues"`
+	}{
+		File:     r.File,
+		Errors:   r.count(parse.LintError),
+		Warnings: r.count(parse.LintWarning),
+		Issues:   issues,
+	})
+}
+
+func (r *respLintResult) MarshalText() ([]byte, error) {
+	var str strings.Builder
+	for _, issue := range r.Issues {
+		if line, col := lintPosition(issue); line > 0 {
+			fmt.Fprintf(&str, "%s:%d:%d: ", r.File, line, col)
+		} else {
+			fmt.Fprintf(&str, "%s: ", r.File)
+		}
+		fmt.Fprintf(&str, "%s: %s\n", issue.Severity, issue.Message)
+	}
+	fmt.Fprintf(&str, "%d errors, %d warnings", r.count(parse.LintError), r.count(parse.LintWarning))
+
+	return []byte(str.String()), nil
+}
+
+// lintPosition returns the 1-indexed line and column of an issue, or zeros if
+// it has no position.
+func lintPosition(issue *parse.LintIssue) (line, col int) {
+	if issue.Position == nil || issue.Position.StartLine == nil || issue.Position.StartCol == nil {
+		return 0, 0
+	}
+	// columns are 0-indexed by the parser
+	return *issue.Position.StartLine, *issue.Position.StartCol + 1
+}
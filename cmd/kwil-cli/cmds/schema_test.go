@@ -0,0 +1,195 @@
+package cmds
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kwilteam/kwil-db/app/shared"
+	clientType "github.com/kwilteam/kwil-db/core/client/types"
+	"github.com/kwilteam/kwil-db/core/types"
+	"github.com/kwilteam/kwil-db/node/engine/parse"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SchemaLint(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "schema.kf")
+	err := os.WriteFile(file, []byte(`CREATE TABLE users (id int primary key);
+CREATE TABLE posts (id int primary key, author int references users(id));`), 0644)
+	require.NoError(t, err)
+
+	lint := func(args ...string) (string, error) {
+		root := NewRootCmd()
+		var out bytes.Buffer
+		root.SetOut(&out)
+		root.SetArgs(append([]string{"schema", "lint", "--file", file}, args...))
+		require.NoError(t, root.ExecuteContext(context.Background()))
+		return out.String(), shared.CmdCtxErr(root)
+	}
+
+	// a foreign key without an index is a warning, which does not fail
+	out, err := lint()
+	require.NoError(t, err)
+	require.Equal(t, file+":2:1: warning: table posts: foreign key on (author) has no index on its columns\n0 errors, 1 warnings\n", out)
+
+	out, err = lint("--require-fk-indexes")
+	require.Error(t, err)
+	require.Contains(t, out, "error: table posts: foreign key on (author) has no index on its columns")
+}
+
+func Test_SchemaFmt(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "schema.kf")
+	err := os.WriteFile(file, []byte(`create table users (id int primary key, name text not null);
+-- users are looked up by name
+create index users_name on users (name);`), 0644)
+	require.NoError(t, err)
+
+	format := func(args ...string) string {
+		root := NewRootCmd()
+		var out bytes.Buffer
+		root.SetOut(&out)
+		root.SetArgs(append([]string{"schema", "fmt", "--file", file}, args...))
+		require.NoError(t, root.ExecuteContext(context.Background()))
+		require.NoError(t, shared.CmdCtxErr(root))
+		return out.String()
+	}
+
+	formatted := `CREATE TABLE users (
+	id int8 PRIMARY KEY,
+	name text NOT NULL
+);
+
+CREATE INDEX users_name ON users(name);
+`
+
+	// without --write, the file is not changed
+	require.Equal(t, formatted, format())
+
+	require.Equal(t, "formatted "+file+"\n", format("--write"))
+	bts, err := os.ReadFile(file)
+	require.NoError(t, err)
+	require.Equal(t, formatted, string(bts))
+
+	require.Equal(t, file+" is already formatted\n", format("--write"))
+}
+
+func Test_SchemaFmtImports(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "schema.kf")
+	err := os.WriteFile(file, []byte(`create table posts (id int primary key, author int references users(id));
+// @import "users.kf"
+create index posts_author on posts (author);`), 0644)
+	require.NoError(t, err)
+
+	root := NewRootCmd()
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetArgs([]string{"schema", "fmt", "--file", file})
+	require.NoError(t, root.ExecuteContext(context.Background()))
+	require.NoError(t, shared.CmdCtxErr(root))
+
+	// the directive is kept, at the top of the schema
+	require.Equal(t, `-- @import "users.kf"
+
+CREATE TABLE posts (
+	id int8 PRIMARY KEY,
+	author int8 REFERENCES users(id)
+);
+
+CREATE INDEX posts_author ON posts(author);
+`, out.String())
+}
+
+// testSchemaClient deploys schemas and runs actions without a node. An action
+// fails if it calls the error built-in.
+type testSchemaClient struct {
+	actions map[string]*parse.CreateActionStatement
+	results map[types.Hash]*types.TxResult
+	sql     []string
+}
+
+func (c *testSchemaClient) result(code types.TxCode, log string) types.Hash {
+	hash := types.Hash{byte(len(c.results) + 1)}
+	c.results[hash] = &types.TxResult{Code: uint32(code), Log: log}
+	return hash
+}
+
+func (c *testSchemaClient) ExecuteSQL(ctx context.Context, sql string, params map[string]any, opts ...clientType.TxOpt) (types.Hash, error) {
+	c.sql = append(c.sql, sql)
+	stmts, err := parse.Parse(sql)
+	if err != nil {
+		return types.Hash{}, err
+	}
+	for _, stmt := range stmts {
+		if act, ok := stmt.(*parse.CreateActionStatement); ok {
+			c.actions[act.GetNamespacePrefix()+"."+act.Name] = act
+		}
+	}
+	return c.result(types.CodeOk, ""), nil
+}
+
+func (c *testSchemaClient) Execute(ctx context.Context, namespace string, action string, tuples [][]any, opts ...clientType.TxOpt) (types.Hash, error) {
+	act, ok := c.actions[namespace+"."+action]
+	if !ok {
+		return c.result(types.CodeUnknownError, "action not found"), nil
+	}
+	for _, stmt := range act.Statements {
+		if call, ok := stmt.(*parse.ActionStmtCall); ok && call.Call.Name == "error" {
+			return c.result(types.CodeUnknownError, call.Call.Args[0].(*parse.ExpressionLiteral).Value.(string)), nil
+		}
+	}
+	return c.result(types.CodeOk, ""), nil
+}
+
+func (c *testSchemaClient) WaitTx(ctx context.Context, txHash types.Hash, interval time.Duration) (*types.TxQueryResponse, error) {
+	return &types.TxQueryResponse{Hash: txHash, Result: c.results[txHash]}, nil
+}
+
+func Test_SchemaTest(t *testing.T) {
+	schema, err := parse.Parse(`CREATE TABLE accounts (id int primary key, balance int not null);
+-- @test
+CREATE ACTION test_insert() public {
+	INSERT INTO accounts (id, balance) VALUES (1, 100);
+};
+-- @test
+CREATE ACTION test_overdraft() public {
+	error('overdraft was allowed');
+};`)
+	require.NoError(t, err)
+
+	cl := &testSchemaClient{
+		actions: make(map[string]*parse.CreateActionStatement),
+		results: make(map[types.Hash]*types.TxResult),
+	}
+	res, err := runSchemaTests(context.Background(), cl, "ledger", schema)
+	require.NoError(t, err)
+
+	// the schema is deployed to a test namespace, which is dropped afterwards
+	require.True(t, strings.HasPrefix(res.Namespace, "ledger_test_"))
+	require.Len(t, cl.sql, 2)
+	require.True(t, strings.HasPrefix(cl.sql[0], "-- @test_namespace\nCREATE NAMESPACE "+res.Namespace))
+	require.Equal(t, "DROP NAMESPACE "+res.Namespace, cl.sql[1])
+
+	require.Equal(t, []*schemaTestResult{
+		{Action: "test_insert"},
+		{Action: "test_overdraft", Error: "overdraft was allowed"},
+	}, res.Results)
+
+	// the failed test fails the command
+	cmd := &cobra.Command{}
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	require.NoError(t, printSchemaTests(cmd, res))
+	require.Error(t, shared.CmdCtxErr(cmd))
+	require.Equal(t, "PASS test_insert\nFAIL test_overdraft: overdraft was allowed\n1 passed, 1 failed\n", out.String())
+
+	// schemas without tests are rejected
+	schema, err = parse.Parse(`CREATE TABLE accounts (id int primary key);`)
+	require.NoError(t, err)
+	_, err = runSchemaTests(context.Background(), cl, "ledger", schema)
+	require.Error(t, err)
+}
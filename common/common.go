@@ -27,6 +27,11 @@ type Service struct {
 
 	// Identity is the node/validator identity (pubkey).
 	Identity []byte // maybe this actuall needs to be crypto.PubKey???
+
+	// AdminDB is a connection pool used only by admin calls, so that they do
+	// not wait for connections used by user calls. If nil, admin calls use
+	// the database they are given.
+	AdminDB sql.DB
 }
 
 // NameLogger returns a new Service with the logger named.
@@ -37,6 +42,7 @@ func (s *Service) NamedLogger(name string) *Service {
 		GenesisConfig: s.GenesisConfig,
 		LocalConfig:   s.LocalConfig,
 		Identity:      s.Identity,
+		AdminDB:       s.AdminDB,
 	}
 }
 
@@ -115,8 +121,62 @@ type EngineContext struct {
 	// and make sure to create a fake transaction context.
 	// If InvalidTxCtx is set to true, OverrideAuthz should also be set to true.
 	InvalidTxCtx bool
+	// QueryClass is the kind of workload of the call. It adjusts the
+	// resources Postgres gives to read-only calls.
+	QueryClass QueryClass
+	// Metadata is metadata of the request that made a call, such as the
+	// headers of an RPC request, keyed by lower case name. It is not set
+	// for transactions.
+	Metadata map[string]string
+	// WorkloadClass is the kind of caller making the call. Admin calls do
+	// not compete with user calls for resources.
+	WorkloadClass WorkloadClass
+	// RefundOnFailure refunds the caller the part of the fee that a failed
+	// call did not use. The fee is GasLimit * GasPrice, and a call uses a
+	// unit of gas for each statement it runs against the database. The
+	// changes of a failed call are rolled back before the refund is
+	// credited, so the refund is kept if the transaction is committed.
+	RefundOnFailure bool
+	// GasLimit is the amount of gas the caller paid for.
+	GasLimit uint64
+	// GasPrice is the price of a unit of gas.
+	GasPrice *big.Int
+	// Simulate runs the call without keeping any of its changes. The call
+	// is run in a nested transaction that is always rolled back, and any
+	// change it makes to the engine is discarded. A failure of the call is
+	// reported in the Error of its CallResult instead of being returned.
+	Simulate bool
 }
 
+// WorkloadClass separates the calls of administrators from those of users.
+type WorkloadClass uint8
+
+const (
+	// UserWorkload is a call made by a user. It is the default.
+	UserWorkload WorkloadClass = iota
+	// AdminWorkload is a call made by an administrator, such as a call to an
+	// owner-restricted action. It is not limited by the engine's concurrency
+	// limits, runs with exclusive access to the engine, and reads from the
+	// service's AdminDB if one is set.
+	AdminWorkload
+)
+
+// QueryClass classifies the workload of a call, so that analytical and
+// reporting queries do not take resources from transactional ones.
+type QueryClass uint8
+
+const (
+	// Transactional is a short call that is part of an application's normal
+	// operation. It is the default, and uses the default resources.
+	Transactional QueryClass = iota
+	// Analytical is an ad hoc query over large amounts of data. It is given
+	// less memory and a shorter statement timeout.
+	Analytical
+	// Reporting is a scheduled query over large amounts of data. It is given
+	// more memory.
+	Reporting
+)
+
 func (e *EngineContext) Valid() error {
 	if e.InvalidTxCtx && !e.OverrideAuthz {
 		return fmt.Errorf("invalid transaction context: If InvalidTxCtx is set to true, OverrideAuthz should also be set to true")
@@ -135,7 +195,9 @@ type Engine interface {
 	CallWithoutEngineCtx(ctx context.Context, db sql.DB, namespace, action string, args []any, resultFn func(*Row) error) (*CallResult, error)
 	// Execute executes a statement in the database. The fn callback is
 	// called for each row in the result set. If the fn returns an error,
-	// the call will be aborted and the error will be returned.
+	// the call will be aborted and the error will be returned. The params
+	// set the variables of the statement, and can be named $amount, @amount
+	// or amount to set $amount.
 	Execute(ctx *EngineContext, db sql.DB, statement string, params map[string]any, fn func(*Row) error) error
 	// ExecuteWithoutEngineCtx executes a statement in the database without
 	// needing an engine context. This is useful for extensions that need to
@@ -154,6 +216,9 @@ type CallResult struct {
 	// It is explicitly used for user-defined exceptions thrown
 	// with the `error` function.
 	Error error // TODO: implement
+	// GasUsed is the gas used by the call, which is a unit for each
+	// statement it runs against the database.
+	GasUsed uint64
 }
 
 // FormatLogs formats the logs into a string.
@@ -209,6 +274,14 @@ type Accounts interface {
 	// funds to spend the amount, the entire balance will be spent and
 	// the spend will fail.
 	ApplySpend(ctx context.Context, tx sql.Executor, account *types.AccountID, amount *big.Int, nonce int64) error
+	// TransferToken transfers an amount of a token other than the native
+	// token from one account to another. It fails if the from account does
+	// not have enough of the token. The balances of other tokens are not
+	// changed.
+	TransferToken(ctx context.Context, tx sql.TxMaker, from, to *types.AccountID, token []byte, amt *big.Int) error
+	// TokenBalance retrieves the balance of an account in a token other than
+	// the native token. It is zero if the account has never held the token.
+	TokenBalance(ctx context.Context, tx sql.Executor, account *types.AccountID, token []byte) (*big.Int, error)
 }
 
 // Validators is an interface for managing validators on the Kwil network.
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"hash/fnv"
 	"io"
 	"math/big"
 	"os"
@@ -290,6 +291,7 @@ func DefaultConfig() *Config {
 			FileRollSize:   10_000, // KB
 			RetainMaxRolls: 0,      // retain all archived logs
 		},
+		ProfileListen: "localhost:6060",
 		Telemetry: Telemetry{
 			Enable:       false,
 			OTLPEndpoint: "127.0.0.1:4318",
@@ -299,6 +301,9 @@ func DefaultConfig() *Config {
 			Pex:               true,
 			BootNodes:         []string{},
 			TargetConnections: 20,
+			DialTimeout:       types.Duration(10 * time.Second),
+			MaxRetries:        58, // about 48 hours with the default backoff
+			RetryBackoff:      types.Duration(2 * time.Second),
 		},
 		Consensus: ConsensusConfig{
 			ProposeTimeout:        types.Duration(1000 * time.Millisecond),
@@ -354,6 +359,11 @@ func DefaultConfig() *Config {
 			Height: 0,
 			Hash:   "",
 		},
+		Sharding: ShardingConfig{
+			ShardCount:    0,
+			NodeAddresses: []string{},
+		},
+		NamespaceAffinity: make(map[string]string),
 		// Erc20Bridge: ERC20BridgeConfig{
 		// 	RPC:                make(map[string]string),
 		// 	BlockSyncChuckSize: make(map[string]string),
@@ -368,8 +378,9 @@ func DefaultConfig() *Config {
 type Config struct {
 	Log Logging `toml:"log" comment:"logging configuration"`
 
-	ProfileMode string `toml:"profile_mode,commented" comment:"profile mode (http, cpu, mem, mutex, or block)"`
-	ProfileFile string `toml:"profile_file,commented" comment:"profile output file path (e.g. cpu.pprof)"`
+	ProfileMode   string `toml:"profile_mode,commented" comment:"profile mode (http, cpu, mem, mutex, or block)"`
+	ProfileFile   string `toml:"profile_file,commented" comment:"profile output file path (e.g. cpu.pprof)"`
+	ProfileListen string `toml:"profile_listen,commented" comment:"listen address of the http profiler (profile mode http)"`
 
 	Telemetry Telemetry `toml:"telemetry" comment:"telemetry (metrics and traces) configuration"`
 
@@ -386,6 +397,11 @@ type Config struct {
 	GenesisState string                       `toml:"genesis_state" comment:"path to the genesis state file, relative to the root directory"`
 	Migrations   MigrationConfig              `toml:"migrations" comment:"zero downtime migration configuration"`
 	Checkpoint   Checkpoint                   `toml:"checkpoint" comment:"checkpoint info for the leader to sync to before proposing a new block"`
+	Sharding     ShardingConfig               `toml:"sharding" comment:"namespace sharding configuration"`
+	// NamespaceAffinity maps namespaces to the P2P addresses of their home
+	// nodes. Read-only calls to a namespace on another node are rejected with
+	// the address of its home node, so that clients can redirect them.
+	NamespaceAffinity map[string]string `toml:"namespace_affinity" comment:"home node P2P address (host:port) of namespaces, to which read-only calls are redirected"`
 	// Erc20Bridge  ERC20BridgeConfig            `toml:"erc20_bridge" comment:"ERC20 bridge configuration"`
 
 	SkipDependencyVerification bool `toml:"skip_dependency_verification" comment:"skip runtime dependency verification (the pg_dump and psql binaries)"`
@@ -423,6 +439,12 @@ type PeerConfig struct {
 	Whitelist         []string `toml:"whitelist" comment:"allowed node IDs when in private mode"`
 	TargetConnections int      `toml:"target_connections" comment:"target number of connections to maintain"`
 	ExternalAddress   string   `toml:"external_address" comment:"external address in host:port format to advertise to the network"`
+
+	DialTimeout  types.Duration `toml:"dial_timeout" comment:"timeout for each attempt to connect to a peer"`
+	MaxRetries   int            `toml:"max_retries" comment:"number of times a failed connection to a known peer is retried before the peer is removed"`
+	RetryBackoff types.Duration `toml:"retry_backoff" comment:"delay before the first retry of a failed connection to a peer, which doubles with each retry up to an hour"`
+
+	NamespaceGossip bool `toml:"namespace_gossip" comment:"gossip namespace deployments with peers, which load announced namespaces from their own committed state"`
 }
 
 // StoreConfig contains options related to the block store. This is the embedded
@@ -450,13 +472,14 @@ type DBConfig struct {
 	// However, this is less error prone, and prevents passing settings that
 	// would alter the functionality of the connection. An advanced option could
 	// be added to supplement the conn string if that seems useful.
-	Host          string         `toml:"host" comment:"postgres host name (IP or UNIX socket path)"`
-	Port          string         `toml:"port" comment:"postgres TCP port (leave empty for UNIX socket)"`
-	User          string         `toml:"user" comment:"postgres role/user name"`
-	Pass          string         `toml:"pass" comment:"postgres password if required for the user and host"`
-	DBName        string         `toml:"dbname" comment:"postgres database name"`
-	ReadTxTimeout types.Duration `toml:"read_timeout" comment:"timeout on read transactions from user RPC calls and queries"`
-	MaxConns      uint32         `toml:"max_connections" comment:"maximum number of DB connections to permit"`
+	Host            string         `toml:"host" comment:"postgres host name (IP or UNIX socket path)"`
+	Port            string         `toml:"port" comment:"postgres TCP port (leave empty for UNIX socket)"`
+	User            string         `toml:"user" comment:"postgres role/user name"`
+	Pass            string         `toml:"pass" comment:"postgres password if required for the user and host"`
+	DBName          string         `toml:"dbname" comment:"postgres database name"`
+	ReadTxTimeout   types.Duration `toml:"read_timeout" comment:"timeout on read transactions from user RPC calls and queries"`
+	MaxConns        uint32         `toml:"max_connections" comment:"maximum number of DB connections to permit"`
+	EnableWALStream bool           `toml:"enable_wal_stream" comment:"stream committed row changes from the postgres write-ahead log (the replication slot is dropped when disabled)"`
 }
 
 type ConsensusConfig struct {
@@ -485,6 +508,7 @@ type RPCConfig struct {
 	ChallengeExpiry    types.Duration `toml:"challenge_expiry" comment:"lifetime of a server-generated challenge"`
 	ChallengeRateLimit float64        `toml:"challenge_rate_limit" comment:"maximum number of challenges per second that a user can request"`
 	DisableServices    []string       `toml:"disabled_services" comment:"services to disable on the RPC server e.g. 'chain'"`
+	MaxConcurrentCalls int            `toml:"max_concurrent_calls" comment:"maximum number of read-only calls the engine executes at once, beyond which calls are rejected (0 for no limit). Block execution is never limited."`
 }
 
 func (c *RPCConfig) ServiceDisabled(svc string) bool {
@@ -526,6 +550,42 @@ type Checkpoint struct {
 	Hash   string `toml:"hash" comment:"checkpoint block hash"`
 }
 
+// ShardingConfig assigns namespaces to the nodes that serve them. The first
+// ShardCount node addresses form a ring, and each namespace is assigned to one
+// of them with consistent hashing, so that adding a node to the ring reassigns
+// only the namespaces that move to the new node.
+type ShardingConfig struct {
+	ShardCount    int      `toml:"shard_count" comment:"number of nodes in the ring, taken from the start of node_addresses (0 disables sharding)"`
+	NodeAddresses []string `toml:"node_addresses" comment:"JSON-RPC addresses of the nodes that serve the shards"`
+	LocalAddress  string   `toml:"local_address" comment:"JSON-RPC address of this node, as it appears in node_addresses"`
+}
+
+// AssignShard returns the address of the node responsible for a namespace. It
+// returns an empty string if sharding is disabled.
+func (c *ShardingConfig) AssignShard(namespace string) string {
+	n := min(c.ShardCount, len(c.NodeAddresses))
+	if n <= 0 {
+		return ""
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte(strings.ToLower(namespace)))
+	return c.NodeAddresses[jumpHash(h.Sum64(), n)]
+}
+
+// jumpHash is the jump consistent hash of Lamping and Veach. It assigns the key
+// to one of numBuckets buckets, and when the number of buckets grows from n to
+// n+1, only 1/(n+1) of the keys move, all of them to the new bucket.
+func jumpHash(key uint64, numBuckets int) int {
+	var b, j int64 = -1, 0
+	for j < int64(numBuckets) {
+		b = j
+		key = key*2862933555777941757 + 1
+		j = int64(float64(b+1) * (float64(int64(1)<<31) / float64((key>>33)+1)))
+	}
+	return int(b)
+}
+
 type ERC20BridgeConfig struct {
 	RPC                map[string]string `toml:"rpc" comment:"evm websocket RPC; format: chain_name='rpc_url'"`
 	BlockSyncChuckSize map[string]string `toml:"block_sync_chuck_size" comment:"rpc option block sync chunk size; format: chain_name='chunk_size'"`
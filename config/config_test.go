@@ -475,3 +475,35 @@ func TestConfigFromTOML(t *testing.T) {
 		})
 	}
 }
+
+func TestAssignShard(t *testing.T) {
+	var addrs []string
+	for i := range 11 {
+		addrs = append(addrs, fmt.Sprintf("http://node%d:8484", i))
+	}
+
+	disabled := ShardingConfig{NodeAddresses: addrs}
+	require.Empty(t, disabled.AssignShard("main"))
+
+	const n = 10
+	before := ShardingConfig{ShardCount: n, NodeAddresses: addrs}
+	after := ShardingConfig{ShardCount: n + 1, NodeAddresses: addrs}
+
+	const numNamespaces = 10_000
+	var moved int
+	for i := range numNamespaces {
+		ns := fmt.Sprintf("namespace_%d", i)
+		from, to := before.AssignShard(ns), after.AssignShard(ns)
+		require.Equal(t, from, before.AssignShard(ns)) // deterministic
+		require.NotEqual(t, addrs[n], from)
+		if from != to {
+			// namespaces only move to the new node
+			require.Equal(t, addrs[n], to)
+			moved++
+		}
+	}
+
+	// adding a node to a ring of N nodes reassigns about 1/(N+1) of the namespaces
+	require.Greater(t, moved, 0)
+	require.Less(t, moved, numNamespaces/n)
+}
@@ -3,8 +3,10 @@
 package client
 
 import (
+	"bytes"
 	"context"
 	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"math/big"
@@ -196,6 +198,18 @@ func syncBcastFlag(syncBcast bool) rpcclient.BroadcastWait {
 	return syncFlag
 }
 
+// traceContext returns a context that sends the request ID and traceparent of
+// the transaction options with the requests made for the transaction.
+func traceContext(ctx context.Context, txOpts *clientType.TxOptions) context.Context {
+	if txOpts.RequestID != "" {
+		ctx = rpcclient.ContextWithRequestID(ctx, txOpts.RequestID)
+	}
+	if txOpts.TraceParent != "" {
+		ctx = rpcclient.ContextWithTraceParent(ctx, txOpts.TraceParent)
+	}
+	return ctx
+}
+
 // Transfer transfers balance to a given address.
 func (c *Client) Transfer(ctx context.Context, to *types.AccountID, amount *big.Int, opts ...clientType.TxOpt) (types.Hash, error) {
 	// Get account balance to ensure we can afford the transfer, and use the
@@ -213,23 +227,42 @@ func (c *Client) Transfer(ctx context.Context, to *types.AccountID, amount *big.
 	nonceOpt := clientType.WithNonce(acct.Nonce + 1)
 	opts = append([]clientType.TxOpt{nonceOpt}, opts...) // prepend in case caller specified a nonce
 	txOpts := clientType.GetTxOpts(opts)
+	ctx = traceContext(ctx, txOpts)
+
+	if err := types.ValidateTokenID(txOpts.Token); err != nil {
+		return types.Hash{}, err
+	}
 
 	trans := &types.Transfer{
-		To:     to,
-		Amount: amount,
+		To:      to,
+		Amount:  amount,
+		TokenID: txOpts.Token,
 	}
 	tx, err := c.newTx(ctx, trans, txOpts)
 	if err != nil {
 		return types.Hash{}, err
 	}
 
-	totalSpend := big.NewInt(0).Add(tx.Body.Fee, amount)
+	// fees are paid in the native token, so only transfers of the native
+	// token add the amount to the fee
+	totalSpend := big.NewInt(0).Set(tx.Body.Fee)
+	if len(txOpts.Token) == 0 {
+		totalSpend.Add(totalSpend, amount)
+	} else {
+		tokenBal, err := c.txClient.GetTokenBalance(ctx, signerAcctID, txOpts.Token, types.AccountStatusLatest)
+		if err != nil {
+			return types.Hash{}, err
+		}
+		if amount.Cmp(tokenBal) > 0 {
+			return types.Hash{}, fmt.Errorf("send amount (%v) larger than balance of token %x (%v)", amount, txOpts.Token, tokenBal)
+		}
+	}
 	if totalSpend.Cmp(acct.Balance) > 0 {
 		return types.Hash{}, fmt.Errorf("send amount plus fees (%v) larger than balance (%v)", totalSpend, acct.Balance)
 	}
 
 	c.logger.Debug("transfer", "to", to,
-		"amount", amount.String())
+		"amount", amount.String(), "token", hex.EncodeToString(txOpts.Token))
 
 	return c.txClient.Broadcast(ctx, tx, syncBcastFlag(txOpts.SyncBcast))
 }
@@ -261,6 +294,7 @@ func (c *Client) Execute(ctx context.Context, namespace string, action string, t
 	}
 
 	txOpts := clientType.GetTxOpts(opts)
+	ctx = traceContext(ctx, txOpts)
 	tx, err := c.newTx(ctx, executionBody, txOpts)
 	if err != nil {
 		return types.Hash{}, err
@@ -293,6 +327,7 @@ func (c *Client) ExecuteSQL(ctx context.Context, stmt string, params map[string]
 	}
 
 	txOpts := clientType.GetTxOpts(opts)
+	ctx = traceContext(ctx, txOpts)
 	tx, err := c.newTx(ctx, execTx, txOpts)
 	if err != nil {
 		return types.Hash{}, err
@@ -307,6 +342,33 @@ func (c *Client) ExecuteSQL(ctx context.Context, stmt string, params map[string]
 	return c.txClient.Broadcast(ctx, tx, syncBcastFlag(txOpts.SyncBcast))
 }
 
+// DeployNamespace deploys a schema to a namespace, creating the namespace if
+// it does not exist. If expectedVersion is not nil, the transaction fails
+// unless the namespace is at that version, which is 0 for a namespace that was
+// never deployed.
+func (c *Client) DeployNamespace(ctx context.Context, namespace string, statements []string, expectedVersion *int64, opts ...clientType.TxOpt) (types.Hash, error) {
+	deployTx := &types.DeployNamespace{
+		Namespace:       namespace,
+		Statements:      statements,
+		ExpectedVersion: expectedVersion,
+	}
+
+	txOpts := clientType.GetTxOpts(opts)
+	ctx = traceContext(ctx, txOpts)
+	tx, err := c.newTx(ctx, deployTx, txOpts)
+	if err != nil {
+		return types.Hash{}, err
+	}
+
+	c.logger.Debug("deploy namespace",
+		"namespace", namespace, "statements", len(statements),
+		"signature_type", tx.Signature.Type,
+		"signature", base64.StdEncoding.EncodeToString(tx.Signature.Data),
+		"fee", tx.Body.Fee.String(), "nonce", tx.Body.Nonce)
+
+	return c.txClient.Broadcast(ctx, tx, syncBcastFlag(txOpts.SyncBcast))
+}
+
 // Call calls an action. It returns the result records.
 func (c *Client) Call(ctx context.Context, namespace string, action string, inputs []any) (*types.CallResult, error) {
 	encoded, err := EncodeInputs(inputs)
@@ -346,6 +408,60 @@ func (c *Client) Call(ctx context.Context, namespace string, action string, inpu
 	return res, nil
 }
 
+// ComparePlans compares the query plans of an action called with the given
+// inputs against a plan baseline stored on the node.
+func (c *Client) ComparePlans(ctx context.Context, namespace, action string, inputs []any, baselineID string) (*types.PlanComparison, error) {
+	encoded, err := EncodeInputs(inputs)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.txClient.ComparePlans(ctx, namespace, action, encoded, baselineID)
+}
+
+// SimulateCall runs an action on the node without keeping any of its changes,
+// and returns its logs, result rows, gas usage, and whether it would succeed
+// in a transaction. The action is called as caller, which is not
+// authenticated. If caller is nil, the client's signer is the caller.
+func (c *Client) SimulateCall(ctx context.Context, namespace, action string, args []any, caller []byte) (*types.SimulationResult, error) {
+	encoded, err := EncodeInputs(args)
+	if err != nil {
+		return nil, err
+	}
+
+	var authType string
+	switch {
+	case caller == nil && c.signer != nil:
+		caller, authType = c.signer.CompactID(), c.signer.AuthType()
+	case caller == nil:
+		// anonymous caller
+	case c.signer != nil && bytes.Equal(caller, c.signer.CompactID()):
+		authType = c.signer.AuthType()
+	default:
+		authType, err = callerAuthType(caller)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return c.txClient.SimulateCall(ctx, namespace, action, encoded, caller, authType)
+}
+
+// callerAuthType returns the auth type of a caller identifier that is not the
+// client's signer, which is known from its length.
+func callerAuthType(caller []byte) (string, error) {
+	switch len(caller) {
+	case 20: // Ethereum address
+		return auth.EthPersonalSignAuth, nil
+	case 32:
+		return auth.Ed25519Auth, nil
+	case 33: // compressed public key
+		return auth.Secp256k1Auth, nil
+	default:
+		return "", fmt.Errorf("unknown caller identifier of %d bytes", len(caller))
+	}
+}
+
 // Query executes a query.
 func (c *Client) Query(ctx context.Context, query string, params map[string]any, skipAuth bool) (*types.QueryResult, error) {
 	if params == nil {
@@ -395,6 +511,12 @@ func (c *Client) GetAccount(ctx context.Context, acctID *types.AccountID, status
 	return c.txClient.GetAccount(ctx, acctID, status)
 }
 
+// GetTokenBalance gets the balance of an account in a token. An empty token
+// is the native token.
+func (c *Client) GetTokenBalance(ctx context.Context, acctID *types.AccountID, token []byte, status types.AccountStatus) (*big.Int, error) {
+	return c.txClient.GetTokenBalance(ctx, acctID, token, status)
+}
+
 func (c *Client) GetNumAccounts(ctx context.Context) (count, height int64, err error) {
 	return c.txClient.GetNumAccounts(ctx)
 }
@@ -22,8 +22,10 @@ type Client interface {
 	Execute(ctx context.Context, namespace string, action string, tuples [][]any, opts ...TxOpt) (types.Hash, error)
 	ExecuteSQL(ctx context.Context, sql string, params map[string]any, opts ...TxOpt) (types.Hash, error)
 	GetAccount(ctx context.Context, account *types.AccountID, status types.AccountStatus) (*types.Account, error)
+	GetTokenBalance(ctx context.Context, account *types.AccountID, token []byte, status types.AccountStatus) (*big.Int, error)
 	Ping(ctx context.Context) (string, error)
 	Query(ctx context.Context, query string, params map[string]any, auth bool) (*types.QueryResult, error)
+	SimulateCall(ctx context.Context, namespace, action string, args []any, caller []byte) (*types.SimulationResult, error)
 	TxQuery(ctx context.Context, txHash types.Hash) (*types.TxQueryResponse, error)
 	WaitTx(ctx context.Context, txHash types.Hash, interval time.Duration) (*types.TxQueryResponse, error)
 	Transfer(ctx context.Context, to *types.AccountID, amount *big.Int, opts ...TxOpt) (types.Hash, error)
@@ -78,8 +78,17 @@ func DefaultOptions() *Options {
 type TxOptions struct {
 	Nonce int64
 	Fee   *big.Int
+	// Token is the token of a transfer. It is empty for the native token.
+	Token []byte
 
 	SyncBcast bool // wait for mining on broadcast
+
+	// RequestID is sent with the requests made for the transaction in the
+	// X-Request-ID header.
+	RequestID string
+	// TraceParent is sent with the requests made for the transaction in the
+	// W3C traceparent header.
+	TraceParent string
 }
 
 func GetTxOpts(opts []TxOpt) *TxOptions {
@@ -108,6 +117,14 @@ func WithFee(fee *big.Int) TxOpt {
 	}
 }
 
+// WithToken sets the token of a transfer, which is otherwise the native token.
+// Other transactions ignore it.
+func WithToken(token []byte) TxOpt {
+	return func(o *TxOptions) {
+		o.Token = token
+	}
+}
+
 // WithSyncBroadcast indicates that broadcast should wait for the transaction to
 // be included in a block, not merely accepted into mempool.
 func WithSyncBroadcast(wait bool) TxOpt {
@@ -115,3 +132,20 @@ func WithSyncBroadcast(wait bool) TxOpt {
 		o.SyncBcast = wait
 	}
 }
+
+// WithRequestID sets the ID that identifies the requests made for the
+// transaction, such as estimating its fee and broadcasting it, to the server.
+// The server includes it in the errors it returns and logs.
+func WithRequestID(id string) TxOpt {
+	return func(o *TxOptions) {
+		o.RequestID = id
+	}
+}
+
+// WithTraceParent sets the W3C traceparent header of the requests made for
+// the transaction, to propagate the trace that it is part of.
+func WithTraceParent(traceParent string) TxOpt {
+	return func(o *TxOptions) {
+		o.TraceParent = traceParent
+	}
+}
@@ -26,6 +26,9 @@ var (
 type RPCError struct {
 	Msg  string
 	Code int32
+	// RequestID is the ID of the failed request, if one was set with
+	// ContextWithRequestID.
+	RequestID string
 }
 
 func (err RPCError) Error() string {
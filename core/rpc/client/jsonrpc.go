@@ -110,6 +110,27 @@ func WithHTTPClient(client *http.Client) RPCClientOpts {
 	}
 }
 
+type traceCtxKey string
+
+const (
+	requestIDCtx   traceCtxKey = "requestID"
+	traceParentCtx traceCtxKey = "traceParent"
+)
+
+// ContextWithRequestID returns a context that makes the requests of a
+// JSONRPCClient set the X-Request-ID header to the given ID. The server
+// includes it in the errors it returns and logs, so that a failed request can
+// be found in the logs of both.
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDCtx, id)
+}
+
+// ContextWithTraceParent returns a context that makes the requests of a
+// JSONRPCClient set the W3C traceparent header to the given value.
+func ContextWithTraceParent(ctx context.Context, traceParent string) context.Context {
+	return context.WithValue(ctx, traceParentCtx, traceParent)
+}
+
 func (cl *JSONRPCClient) nextReqID() string {
 	id := cl.reqID.Add(1)
 	return strconv.FormatUint(id, 10)
@@ -153,6 +174,12 @@ func (cl *JSONRPCClient) CallMethod(ctx context.Context, method string, cmd, res
 	if cl.basicAuthHdr != "" {
 		httpReq.Header.Set("Authorization", cl.basicAuthHdr) // httpReq.SetBasicAuth("user", cl.pass)
 	}
+	if reqID, ok := ctx.Value(requestIDCtx).(string); ok {
+		httpReq.Header.Set(jsonrpc.HeaderRequestID, reqID)
+	}
+	if traceParent, ok := ctx.Value(traceParentCtx).(string); ok {
+		httpReq.Header.Set(jsonrpc.HeaderTraceParent, traceParent)
+	}
 
 	httpResponse, err := cl.conn.Do(httpReq)
 	if err != nil {
@@ -213,8 +240,9 @@ func (cl *JSONRPCClient) CallMethod(ctx context.Context, method string, cmd, res
 // named error kind like ErrNotFound, ErrUnauthorized, etc. based on the code.
 func clientError(jsonRPCErr *jsonrpc.Error) error {
 	rpcErr := &RPCError{ // @Jon, should we change this to RPCError instead of *RPCError ?
-		Msg:  jsonRPCErr.Message,
-		Code: int32(jsonRPCErr.Code),
+		Msg:       jsonRPCErr.Message,
+		Code:      int32(jsonRPCErr.Code),
+		RequestID: jsonRPCErr.RequestID,
 	}
 	err := errors.Join(jsonRPCErr, rpcErr)
 
@@ -74,12 +74,55 @@ func (cl *Client) Call(ctx context.Context, msg *types.CallMessage, opts ...rpcc
 	res := userjson.CallResponse{}
 	err := cl.CallMethod(ctx, string(userjson.MethodCall), cmd, &res)
 	if err != nil {
+		var jsonRPCErr *jsonrpc.Error
+		if errors.As(err, &jsonRPCErr) && jsonRPCErr.Code == jsonrpc.ErrorEngineNotHomeNode && len(jsonRPCErr.Data) > 0 {
+			var herr userjson.NotHomeNodeError
+			jsonErr := json.Unmarshal(jsonRPCErr.Data, &herr)
+			if jsonErr != nil {
+				return nil, errors.Join(jsonErr, err)
+			}
+
+			err = errors.Join(herr, err)
+		}
 		return nil, err
 	}
 
 	return (*types.CallResult)(&res), nil
 }
 
+func (cl *Client) ComparePlans(ctx context.Context, namespace, action string, args []*types.EncodedValue, baselineID string) (*types.PlanComparison, error) {
+	cmd := &userjson.ComparePlansRequest{
+		Namespace:  namespace,
+		Action:     action,
+		Arguments:  args,
+		BaselineID: baselineID,
+	}
+	res := &userjson.ComparePlansResponse{}
+	err := cl.CallMethod(ctx, string(userjson.MethodComparePlans), cmd, res)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (cl *Client) SimulateCall(ctx context.Context, namespace, action string, args []*types.EncodedValue, caller []byte, authType string) (*types.SimulationResult, error) {
+	cmd := &userjson.SimulateCallRequest{
+		Namespace: namespace,
+		Action:    action,
+		Arguments: args,
+		Caller:    caller,
+		AuthType:  authType,
+	}
+	res := &userjson.SimulateCallResponse{}
+	err := cl.CallMethod(ctx, string(userjson.MethodSimulateCall), cmd, res)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
 func (cl *Client) ChainInfo(ctx context.Context) (*types.ChainInfo, error) {
 	cmd := &userjson.ChainInfoRequest{}
 	res := &userjson.ChainInfoResponse{}
@@ -134,6 +177,28 @@ func (cl *Client) GetAccount(ctx context.Context, account *types.AccountID, stat
 	}, nil
 }
 
+// GetTokenBalance gets the balance of an account in a token. An empty token
+// is the native token.
+func (cl *Client) GetTokenBalance(ctx context.Context, account *types.AccountID, token []byte, status types.AccountStatus) (*big.Int, error) {
+	cmd := &userjson.AccountRequest{
+		ID:      account,
+		Status:  &status,
+		TokenID: token,
+	}
+	res := &userjson.AccountResponse{}
+	err := cl.CallMethod(ctx, string(userjson.MethodAccount), cmd, res)
+	if err != nil {
+		return nil, err
+	}
+
+	balance, ok := new(big.Int).SetString(res.Balance, 10)
+	if !ok {
+		return nil, fmt.Errorf("failed to parse balance to big.Int. received: %s", res.Balance)
+	}
+
+	return balance, nil
+}
+
 func (cl *Client) GetNumAccounts(ctx context.Context) (count, height int64, err error) {
 	cmd := &userjson.NumAccountsRequest{}
 	res := &userjson.NumAccountsResponse{}
@@ -18,10 +18,13 @@ type TxSvcClient interface {
 	ChainInfo(ctx context.Context) (*types.ChainInfo, error)
 	EstimateCost(ctx context.Context, tx *types.Transaction) (*big.Int, error)
 	GetAccount(ctx context.Context, identifier *types.AccountID, status types.AccountStatus) (*types.Account, error) // maybe return height too
+	GetTokenBalance(ctx context.Context, identifier *types.AccountID, token []byte, status types.AccountStatus) (*big.Int, error)
 	Ping(ctx context.Context) (string, error)
 	Query(ctx context.Context, query string, params map[string]*types.EncodedValue) (*types.QueryResult, error)
 	AuthenticatedQuery(ctx context.Context, msg *types.AuthenticatedQuery) (*types.QueryResult, error)
 	TxQuery(ctx context.Context, txHash types.Hash) (*types.TxQueryResponse, error)
+	ComparePlans(ctx context.Context, namespace, action string, args []*types.EncodedValue, baselineID string) (*types.PlanComparison, error)
+	SimulateCall(ctx context.Context, namespace, action string, args []*types.EncodedValue, caller []byte, authType string) (*types.SimulationResult, error)
 
 	// Migration methods
 	ListMigrations(ctx context.Context) ([]*types.Migration, error)
@@ -28,6 +28,7 @@ const (
 	ErrorEngineInternal        ErrorCode = -300
 	ErrorEngineDatasetNotFound ErrorCode = -301
 	ErrorEngineDatasetExists   ErrorCode = -302
+	ErrorEngineNotHomeNode     ErrorCode = -303 // error data is NotHomeNodeError
 
 	ErrorDBInternal ErrorCode = -400
 
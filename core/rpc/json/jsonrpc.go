@@ -16,6 +16,14 @@ import (
 // Method is a type used for all recognized JSON-RPC method names.
 type Method string
 
+// HTTP headers that carry the trace context of a request. The request ID is
+// included in the error responses of the request, and the traceparent is the
+// W3C Trace Context header.
+const (
+	HeaderRequestID   = "X-Request-ID"
+	HeaderTraceParent = "traceparent"
+)
+
 // Error is the "error" object defined by JSON-RPC 2.0
 type Error struct {
 	// Code is an integer error code. Values on [-32768,-32000] are reserved by
@@ -29,6 +37,9 @@ type Error struct {
 	// errors etc.). The requester may attempt to unmarshal into the expected
 	// detailed error type for the method.
 	Data json.RawMessage `json:"data,omitempty"`
+	// RequestID is the ID of the request that failed, if the client set one
+	// with the X-Request-ID header.
+	RequestID string `json:"request_id,omitempty"`
 }
 
 // NewError constructs a new error.
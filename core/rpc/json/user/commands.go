@@ -28,6 +28,8 @@ type SchemaRequest struct {
 type AccountRequest struct {
 	ID     *types.AccountID `json:"id" desc:"account identifier"`
 	Status *AccountStatus   `json:"status,omitempty" desc:"blockchain status (confirmed or unconfirmed)"` // Mapped to URL query parameter `status`.
+	// TokenID is the token of the balance. It is empty for the native token.
+	TokenID types.HexBytes `json:"token_id,omitempty" desc:"token of the balance, empty for the native token"`
 }
 
 type NumAccountsRequest struct{}
@@ -70,6 +72,25 @@ type CallRequest = types.CallMessage
 // AuthenticatedQueryRequest contains the request parameters for MethodAuthenticatedQuery.
 type AuthenticatedQueryRequest = types.AuthenticatedQuery
 
+// ComparePlansRequest contains the request parameters for MethodComparePlans.
+type ComparePlansRequest struct {
+	Namespace  string                `json:"namespace"`
+	Action     string                `json:"action"`
+	Arguments  []*types.EncodedValue `json:"arguments"`
+	BaselineID string                `json:"baseline_id"`
+}
+
+// SimulateCallRequest contains the request parameters for MethodSimulateCall.
+// The action is called as Caller, which is not authenticated, so AuthType
+// only identifies how the caller's identifier is derived.
+type SimulateCallRequest struct {
+	Namespace string                `json:"namespace"`
+	Action    string                `json:"action"`
+	Arguments []*types.EncodedValue `json:"arguments"`
+	Caller    types.HexBytes        `json:"caller,omitempty"`
+	AuthType  string                `json:"auth_type,omitempty"`
+}
+
 // ChainInfoRequest contains the request parameters for MethodChainInfo.
 type ChainInfoRequest struct{}
 
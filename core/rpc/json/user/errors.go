@@ -14,3 +14,16 @@ type BroadcastError struct {
 func (be BroadcastError) Error() string {
 	return fmt.Sprintf("broadcast error: code = %d, hash = %s, msg = %s", be.ErrCode, be.Hash, be.Message)
 }
+
+// NotHomeNodeError is a structured error object used by MethodCall when a
+// namespace is called on a node other than its home node. It is in the Data of
+// an error with the ErrorEngineNotHomeNode RPC ErrorCode. The call should be
+// made to the home node instead.
+type NotHomeNodeError struct {
+	Namespace string `json:"namespace"`
+	HomeNode  string `json:"home_node"` // P2P address (host:port) of the home node
+}
+
+func (he NotHomeNodeError) Error() string {
+	return fmt.Sprintf("namespace %s is served by home node %s", he.Namespace, he.HomeNode)
+}
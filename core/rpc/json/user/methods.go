@@ -26,4 +26,6 @@ const (
 	MethodMigrationMetadata     jsonrpc.Method = "user.migration_metadata"
 	MethodMigrationGenesisChunk jsonrpc.Method = "user.migration_genesis_chunk"
 	MethodChallenge             jsonrpc.Method = "user.challenge"
+	MethodComparePlans          jsonrpc.Method = "user.compare_plans"
+	MethodSimulateCall          jsonrpc.Method = "user.simulate_call"
 )
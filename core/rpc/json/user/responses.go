@@ -40,6 +40,12 @@ type QueryResponse types.QueryResult
 // CallResponse contains the response object for MethodCall.
 type CallResponse types.CallResult
 
+// ComparePlansResponse contains the response object for MethodComparePlans.
+type ComparePlansResponse = types.PlanComparison
+
+// SimulateCallResponse contains the response object for MethodSimulateCall.
+type SimulateCallResponse = types.SimulationResult
+
 // ChainInfoResponse contains the response object for MethodChainInfo.
 type ChainInfoResponse = types.ChainInfo
 
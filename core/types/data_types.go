@@ -18,6 +18,50 @@ type DataType struct {
 	IsArray bool `json:"is_array"`
 	// Metadata is the metadata of the type.
 	Metadata [2]uint16 `json:"metadata"`
+	// Fields are the fields of a composite type. They are only set if the
+	// type is composite. It is a pointer so that DataType is comparable.
+	Fields *[]FieldDef `json:"fields,omitempty"`
+}
+
+// FieldDef is a named field of a composite type.
+type FieldDef struct {
+	// Name is the name of the field.
+	Name string `json:"name"`
+	// Type is the type of the field.
+	Type *DataType `json:"type"`
+}
+
+// CompositeDataType returns a composite type with the given fields. A
+// composite type is the type of a row, such as the rows returned by a table
+// function. It cannot be the type of a column, and it cannot be serialized.
+func CompositeDataType(fields []FieldDef) *DataType {
+	return &DataType{
+		Name:   compositeStr,
+		Fields: copyFields(fields),
+	}
+}
+
+// copyFields deep copies the fields of a composite type.
+func copyFields(fields []FieldDef) *[]FieldDef {
+	res := make([]FieldDef, len(fields))
+	for i, f := range fields {
+		res[i] = FieldDef{Name: f.Name, Type: f.Type.Copy()}
+	}
+	return &res
+}
+
+// IsComposite returns true if the type is a composite type.
+func (c *DataType) IsComposite() bool {
+	return c.Name == compositeStr
+}
+
+// CompositeFields returns the fields of a composite type. It returns nil if
+// the type is not composite.
+func (c *DataType) CompositeFields() []FieldDef {
+	if c.Fields == nil {
+		return nil
+	}
+	return *c.Fields
 }
 
 func (c DataType) SerializeSize() int {
@@ -34,6 +78,10 @@ func boolToByte(b bool) byte {
 }
 
 func (c DataType) MarshalBinary() ([]byte, error) {
+	if c.IsComposite() {
+		return nil, errors.New("composite types cannot be serialized")
+	}
+
 	b := make([]byte, c.SerializeSize())
 	const ver uint16 = 0
 	binary.BigEndian.PutUint16(b, ver)
@@ -89,6 +137,21 @@ func (c *DataType) UnmarshalBinary(data []byte) error {
 // String returns the string representation of the type.
 func (c *DataType) String() string {
 	str := strings.Builder{}
+	if c.IsComposite() {
+		// e.g. (id int8, name text)
+		str.WriteString("(")
+		for i, f := range c.CompositeFields() {
+			if i > 0 {
+				str.WriteString(", ")
+			}
+			str.WriteString(f.Name)
+			str.WriteString(" ")
+			str.WriteString(f.Type.String())
+		}
+		str.WriteString(")")
+		return str.String()
+	}
+
 	aliased, ok := typeAlias[c.Name]
 	if !ok {
 		aliased = "[!invalid!]" + c.Name
@@ -144,6 +207,14 @@ func (c *DataType) PGScalar() (string, error) {
 		scalar = "BYTEA"
 	case uuidStr:
 		scalar = "UUID"
+	case timestamptzStr:
+		scalar = "TIMESTAMPTZ"
+	case intervalStr:
+		scalar = "INTERVAL"
+	case inetStr:
+		scalar = "INET"
+	case cidrStr:
+		scalar = "CIDR"
 	case NumericStr:
 		if !c.HasMetadata() {
 			return "", errors.New("numeric type requires metadata")
@@ -152,6 +223,8 @@ func (c *DataType) PGScalar() (string, error) {
 		}
 	case nullStr:
 		return "", errors.New("cannot have null column type")
+	case compositeStr:
+		return "", errors.New("cannot have composite column type")
 	default:
 		return "", fmt.Errorf("unknown column type: %s", c.Name)
 	}
@@ -168,7 +241,7 @@ func (c *DataType) Clean() error {
 	}
 
 	switch referencedType {
-	case intStr, textStr, boolStr, byteaStr, uuidStr: // ok
+	case intStr, textStr, boolStr, byteaStr, uuidStr, timestamptzStr, intervalStr, inetStr, cidrStr: // ok
 		if c.HasMetadata() {
 			return fmt.Errorf("type %s cannot have metadata", c.Name)
 		}
@@ -205,6 +278,9 @@ func (c *DataType) Copy() *DataType {
 		IsArray:  c.IsArray,
 		Metadata: c.Metadata,
 	}
+	if c.Fields != nil {
+		d.Fields = copyFields(*c.Fields)
+	}
 
 	return d
 }
@@ -222,6 +298,18 @@ func (c *DataType) EqualsStrict(other *DataType) bool {
 		return false
 	}
 
+	// composite types are equal if their fields have the same names and
+	// types, in the same order
+	fields, otherFields := c.CompositeFields(), other.CompositeFields()
+	if len(fields) != len(otherFields) {
+		return false
+	}
+	for i, f := range fields {
+		if f.Name != otherFields[i].Name || !f.Type.EqualsStrict(otherFields[i].Type) {
+			return false
+		}
+	}
+
 	return strings.EqualFold(c.Name, other.Name)
 }
 
@@ -275,6 +363,28 @@ var (
 		Name: uuidStr,
 	}
 	UUIDArrayType = ArrayType(UUIDType)
+	// TimestampTZType is a point in time, with microsecond precision. It is
+	// stored in UTC.
+	TimestampTZType = &DataType{
+		Name: timestamptzStr,
+	}
+	TimestampTZArrayType = ArrayType(TimestampTZType)
+	// IntervalType is a length of time, with microsecond precision. A day is
+	// always 24 hours.
+	IntervalType = &DataType{
+		Name: intervalStr,
+	}
+	IntervalArrayType = ArrayType(IntervalType)
+	// InetType is an IPv4 or IPv6 address.
+	InetType = &DataType{
+		Name: inetStr,
+	}
+	InetArrayType = ArrayType(InetType)
+	// CIDRType is an IPv4 or IPv6 network.
+	CIDRType = &DataType{
+		Name: cidrStr,
+	}
+	CIDRArrayType = ArrayType(CIDRType)
 	// NumericType contains 1,0 metadata.
 	// For type detection, users should prefer compare a datatype
 	// name with the NumericStr constant.
@@ -310,6 +420,12 @@ const (
 	boolStr  = "bool"
 	byteaStr = "bytea"
 	uuidStr  = "uuid"
+	// timestamptzStr is a timestamp with a time zone.
+	timestamptzStr = "timestamptz"
+	intervalStr    = "interval"
+	inetStr        = "inet"
+	cidrStr        = "cidr"
+	compositeStr   = "composite"
 	// NumericStr is a fixed point number.
 	NumericStr = "numeric"
 	nullStr    = "null"
@@ -404,6 +520,13 @@ var typeAlias = map[string]string{
 	"blob":    byteaStr,
 	"bytea":   byteaStr,
 	"uuid":    uuidStr,
-	"decimal": NumericStr,
-	"numeric": NumericStr,
+	// timestamps are always stored with their time zone, so that they are
+	// the same point in time on every node
+	"timestamp":   timestamptzStr,
+	"timestamptz": timestamptzStr,
+	"interval":    intervalStr,
+	"inet":        inetStr,
+	"cidr":        cidrStr,
+	"decimal":     NumericStr,
+	"numeric":     NumericStr,
 }
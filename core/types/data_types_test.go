@@ -129,6 +129,25 @@ func Test_ParseDataTypes(t *testing.T) {
 				IsArray: true,
 			},
 		},
+		{
+			in: "timestamp",
+			out: DataType{
+				Name: timestamptzStr,
+			},
+		},
+		{
+			in: "TIMESTAMPTZ[]",
+			out: DataType{
+				Name:    timestamptzStr,
+				IsArray: true,
+			},
+		},
+		{
+			in: "INTERVAL",
+			out: DataType{
+				Name: intervalStr,
+			},
+		},
 		{
 			in: "text[]",
 			out: DataType{
@@ -196,3 +215,39 @@ func Test_ParseDataTypes(t *testing.T) {
 		})
 	}
 }
+
+func Test_CompositeDataType(t *testing.T) {
+	fields := []FieldDef{{Name: "id", Type: IntType}, {Name: "name", Type: TextType}}
+	c := CompositeDataType(fields)
+
+	if !c.IsComposite() {
+		t.Fatal("expected a composite type")
+	}
+	if got := c.String(); got != "(id int8, name text)" {
+		t.Errorf("got %s, want (id int8, name text)", got)
+	}
+
+	// the fields are copied, so changing them does not change the type
+	fields[0].Name = "other"
+	if !c.EqualsStrict(CompositeDataType([]FieldDef{{Name: "id", Type: IntType}, {Name: "name", Type: TextType}})) {
+		t.Errorf("expected %s to equal (id int8, name text)", c)
+	}
+	if c.EqualsStrict(CompositeDataType([]FieldDef{{Name: "id", Type: IntType}})) {
+		t.Errorf("expected %s not to equal (id int8)", c)
+	}
+	if c.EqualsStrict(TextType) {
+		t.Errorf("expected %s not to equal text", c)
+	}
+
+	cp := c.Copy()
+	if !cp.EqualsStrict(c) || &cp.CompositeFields()[0] == &c.CompositeFields()[0] {
+		t.Error("expected a deep copy")
+	}
+
+	if _, err := c.MarshalBinary(); err == nil {
+		t.Error("expected composite types not to be serializable")
+	}
+	if _, err := c.PGString(); err == nil {
+		t.Error("expected composite types not to be column types")
+	}
+}
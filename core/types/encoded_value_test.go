@@ -3,7 +3,9 @@ package types
 import (
 	"encoding/binary"
 	"math/big"
+	"net/netip"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -57,6 +59,72 @@ func TestEncodedValue_EdgeCases(t *testing.T) {
 		assert.Equal(t, &exp, decoded)
 	})
 
+	t.Run("encode timestamp", func(t *testing.T) {
+		// the time zone is not kept, and the precision is microseconds
+		ts := time.Date(2024, 2, 29, 23, 30, 0, 123456789, time.FixedZone("UTC-5", -5*60*60))
+		ev, err := EncodeValue(ts)
+		require.NoError(t, err)
+		assert.True(t, ev.Type.EqualsStrict(TimestampTZType))
+
+		decoded, err := ev.Decode()
+		require.NoError(t, err)
+		want := time.Date(2024, 3, 1, 4, 30, 0, 123456000, time.UTC)
+		assert.Equal(t, &want, decoded)
+	})
+
+	t.Run("encode timestamp array", func(t *testing.T) {
+		ts := time.Unix(1700000000, 0).UTC()
+		ev, err := EncodeValue([]*time.Time{&ts, nil})
+		require.NoError(t, err)
+		assert.True(t, ev.Type.EqualsStrict(TimestampTZArrayType))
+
+		decoded, err := ev.Decode()
+		require.NoError(t, err)
+		assert.Equal(t, []*time.Time{&ts, nil}, decoded)
+	})
+
+	t.Run("encode interval", func(t *testing.T) {
+		d := -26*time.Hour - 1500*time.Microsecond
+		ev, err := EncodeValue(d)
+		require.NoError(t, err)
+		assert.True(t, ev.Type.EqualsStrict(IntervalType))
+
+		decoded, err := ev.Decode()
+		require.NoError(t, err)
+		assert.Equal(t, &d, decoded)
+
+		ev, err = EncodeValue([]time.Duration{d})
+		require.NoError(t, err)
+		assert.True(t, ev.Type.EqualsStrict(IntervalArrayType))
+
+		decoded, err = ev.Decode()
+		require.NoError(t, err)
+		assert.Equal(t, []*time.Duration{&d}, decoded)
+	})
+
+	t.Run("encode inet and cidr", func(t *testing.T) {
+		addr := netip.MustParseAddr("2001:db8::1")
+		ev, err := EncodeValue([]netip.Addr{addr})
+		require.NoError(t, err)
+		assert.True(t, ev.Type.EqualsStrict(InetArrayType))
+
+		decoded, err := ev.Decode()
+		require.NoError(t, err)
+		assert.Equal(t, []*netip.Addr{&addr}, decoded)
+
+		prefix := netip.MustParsePrefix("192.168.0.0/16")
+		ev, err = EncodeValue(prefix)
+		require.NoError(t, err)
+		assert.True(t, ev.Type.EqualsStrict(CIDRType))
+
+		decoded, err = ev.Decode()
+		require.NoError(t, err)
+		assert.Equal(t, &prefix, decoded)
+
+		_, err = EncodeValue(netip.MustParsePrefix("192.168.1.1/16"))
+		require.Error(t, err)
+	})
+
 	t.Run("encode empty string", func(t *testing.T) {
 		ev, err := EncodeValue("")
 		require.NoError(t, err)
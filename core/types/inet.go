@@ -0,0 +1,41 @@
+package types
+
+import (
+	"fmt"
+	"net/netip"
+)
+
+// ParseInet parses an IPv4 or IPv6 address, e.g. "192.168.1.1" or "::1". The
+// address may have a netmask of its full length, e.g. "192.168.1.1/32", which
+// Postgres omits when it formats an inet.
+func ParseInet(s string) (netip.Addr, error) {
+	addr, err := netip.ParseAddr(s)
+	if err == nil {
+		return addr, nil
+	}
+
+	prefix, err := netip.ParsePrefix(s)
+	if err != nil || prefix.Bits() != prefix.Addr().BitLen() {
+		return netip.Addr{}, fmt.Errorf(`invalid inet "%s"`, s)
+	}
+	return prefix.Addr(), nil
+}
+
+// ParseCIDR parses an IPv4 or IPv6 network, e.g. "192.168.0.0/16". Like
+// Postgres, it does not allow bits to be set to the right of the netmask. An
+// address without a netmask is a network of one address.
+func ParseCIDR(s string) (netip.Prefix, error) {
+	prefix, err := netip.ParsePrefix(s)
+	if err != nil {
+		addr, err2 := netip.ParseAddr(s)
+		if err2 != nil {
+			return netip.Prefix{}, fmt.Errorf(`invalid cidr "%s"`, s)
+		}
+		prefix = netip.PrefixFrom(addr, addr.BitLen())
+	}
+
+	if prefix != prefix.Masked() {
+		return netip.Prefix{}, fmt.Errorf(`invalid cidr "%s": bits are set to the right of the netmask`, s)
+	}
+	return prefix, nil
+}
@@ -0,0 +1,43 @@
+package types
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseInet(t *testing.T) {
+	for s, want := range map[string]string{
+		"192.168.1.1":    "192.168.1.1",
+		"192.168.1.1/32": "192.168.1.1",
+		"2001:db8::1":    "2001:db8::1",
+	} {
+		addr, err := ParseInet(s)
+		require.NoError(t, err, s)
+		assert.Equal(t, want, addr.String())
+	}
+
+	for _, s := range []string{"", "192.168.1", "192.168.1.1/24", "192.168.0.0/16"} {
+		_, err := ParseInet(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestParseCIDR(t *testing.T) {
+	for s, want := range map[string]string{
+		"192.168.0.0/16": "192.168.0.0/16",
+		"10.0.0.1":       "10.0.0.1/32",
+		"2001:db8::/32":  "2001:db8::/32",
+	} {
+		prefix, err := ParseCIDR(s)
+		require.NoError(t, err, s)
+		assert.Equal(t, netip.MustParsePrefix(want), prefix)
+	}
+
+	for _, s := range []string{"", "192.168.1.1/16", "10.0.0.0/33"} {
+		_, err := ParseCIDR(s)
+		assert.Error(t, err, s)
+	}
+}
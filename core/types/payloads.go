@@ -16,8 +16,10 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"net/netip"
 	"reflect"
 	"strconv"
+	"time"
 
 	"github.com/kwilteam/kwil-db/core/crypto"
 )
@@ -40,6 +42,7 @@ type Payload interface {
 
 const (
 	PayloadTypeRawStatement        PayloadType = "raw_statement"
+	PayloadTypeDeployNamespace     PayloadType = "deploy_namespace"
 	PayloadTypeExecute             PayloadType = "execute"
 	PayloadTypeTransfer            PayloadType = "transfer"
 	PayloadTypeValidatorJoin       PayloadType = "validator_join"
@@ -59,6 +62,7 @@ var payloadConcreteTypes = map[PayloadType]Payload{
 	// PayloadTypeDropSchema:          &DropSchema{},
 	// PayloadTypeDeploySchema:        &Schema{},
 	PayloadTypeRawStatement:        &RawStatement{},
+	PayloadTypeDeployNamespace:     &DeployNamespace{},
 	PayloadTypeExecute:             &ActionExecution{},
 	PayloadTypeValidatorJoin:       &ValidatorJoin{},
 	PayloadTypeValidatorApprove:    &ValidatorApprove{},
@@ -100,6 +104,7 @@ func UnmarshalPayload(payloadType PayloadType, payload []byte) (Payload, error)
 // payloadTypes includes native types and types registered from extensions.
 var payloadTypes = map[PayloadType]bool{
 	PayloadTypeRawStatement:        true,
+	PayloadTypeDeployNamespace:     true,
 	PayloadTypeExecute:             true,
 	PayloadTypeTransfer:            true,
 	PayloadTypeValidatorJoin:       true,
@@ -127,6 +132,7 @@ func (p PayloadType) Valid() bool {
 		PayloadTypeApproveResolution,
 		PayloadTypeDeleteResolution,
 		PayloadTypeRawStatement,
+		PayloadTypeDeployNamespace,
 		PayloadTypeExecute,
 		// These should not come in user transactions, but they are not invalid
 		// payload types in general.
@@ -273,6 +279,121 @@ func (r RawStatement) Type() PayloadType {
 	return PayloadTypeRawStatement
 }
 
+// DeployNamespace is the payload that is used to deploy a schema to a
+// namespace. The namespace is created if it does not exist.
+type DeployNamespace struct {
+	Namespace  string
+	Statements []string
+	// ExpectedVersion, if not nil, is the version that the namespace must be
+	// at for the deploy to succeed. A namespace that was never deployed is at
+	// version 0.
+	ExpectedVersion *int64
+}
+
+var _ Payload = (*DeployNamespace)(nil)
+
+func (d DeployNamespace) Type() PayloadType {
+	return PayloadTypeDeployNamespace
+}
+
+const dnVersion = 0
+
+// DeployNamespace serialization is as follows (using SerializationByteOrder in
+// all cases):
+//
+//   - Two bytes for version (uint16), which is presently 0 (dnVersion).
+//   - The namespace string is written according to WriteString, which has a
+//	   4 byte length prefix followed by the bytes of the utf8 string.
+//   - The number of statements is written as a uint16.
+//   - Each statement is written according to WriteString.
+//   - One byte (bool) for whether there is an expected version.
+//   - If there is, the expected version is written as an int64.
+
+func (d DeployNamespace) MarshalBinary() ([]byte, error) {
+	buf := &bytes.Buffer{}
+	// version uint16
+	if err := binary.Write(buf, SerializationByteOrder, uint16(dnVersion)); err != nil {
+		return nil, err
+	}
+	// namespace
+	err := WriteString(buf, d.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	// statements, max 65535 (uint16)
+	if err := binary.Write(buf, SerializationByteOrder, uint16(len(d.Statements))); err != nil {
+		return nil, err
+	}
+	for _, stmt := range d.Statements {
+		err = WriteString(buf, stmt)
+		if err != nil {
+			return nil, err
+		}
+	}
+	// expected version
+	if err := binary.Write(buf, SerializationByteOrder, d.ExpectedVersion != nil); err != nil {
+		return nil, err
+	}
+	if d.ExpectedVersion != nil {
+		if err := binary.Write(buf, SerializationByteOrder, *d.ExpectedVersion); err != nil {
+			return nil, err
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (d *DeployNamespace) UnmarshalBinary(b []byte) error {
+	rd := bytes.NewReader(b)
+
+	var version uint16
+	if err := binary.Read(rd, SerializationByteOrder, &version); err != nil {
+		return err
+	}
+	if version != dnVersion {
+		return fmt.Errorf("unsupported version %d", version)
+	}
+
+	// namespace
+	namespace, err := ReadString(rd)
+	if err != nil {
+		return err
+	}
+
+	// statements
+	var numStmts uint16
+	if err := binary.Read(rd, SerializationByteOrder, &numStmts); err != nil {
+		return err
+	}
+	statements := make([]string, numStmts)
+	for i := range statements {
+		statements[i], err = ReadString(rd)
+		if err != nil {
+			return err
+		}
+	}
+
+	// expected version
+	var hasExpected bool
+	if err := binary.Read(rd, SerializationByteOrder, &hasExpected); err != nil {
+		return err
+	}
+	var expected *int64
+	if hasExpected {
+		expected = new(int64)
+		if err := binary.Read(rd, SerializationByteOrder, expected); err != nil {
+			return err
+		}
+	}
+
+	// only modify the input if no errors
+	d.Namespace = namespace
+	d.Statements = statements
+	d.ExpectedVersion = expected
+
+	return nil
+}
+
 // ActionExecution is the payload that is used to execute an action
 type ActionExecution struct {
 	Namespace string
@@ -633,6 +754,14 @@ func (e *EncodedValue) Decode() (any, error) {
 			return decodeAnyArr[[]byte](e.Data, typeName, e.Type.Metadata)
 		case UUIDType.Name:
 			return decodeAnyArr[UUID](e.Data, typeName, e.Type.Metadata)
+		case TimestampTZType.Name:
+			return decodeAnyArr[time.Time](e.Data, typeName, e.Type.Metadata)
+		case IntervalType.Name:
+			return decodeAnyArr[time.Duration](e.Data, typeName, e.Type.Metadata)
+		case InetType.Name:
+			return decodeAnyArr[netip.Addr](e.Data, typeName, e.Type.Metadata)
+		case CIDRType.Name:
+			return decodeAnyArr[netip.Prefix](e.Data, typeName, e.Type.Metadata)
 		case BoolType.Name:
 			return decodeAnyArr[bool](e.Data, typeName, e.Type.Metadata)
 		case NumericStr:
@@ -697,6 +826,41 @@ func EncodeValue(v any) (*EncodedValue, error) {
 			return encodeNotNull(t[:]), UUIDType, nil
 		case *UUID:
 			return encodeNotNull(t[:]), UUIDType, nil
+		case time.Time:
+			// timestamps are encoded as microseconds since the epoch, which is
+			// their precision in Postgres
+			var buf [8]byte
+			binary.BigEndian.PutUint64(buf[:], uint64(t.UnixMicro()))
+			return encodeNotNull(buf[:]), TimestampTZType, nil
+		case time.Duration:
+			// intervals are also encoded as microseconds
+			var buf [8]byte
+			binary.BigEndian.PutUint64(buf[:], uint64(t.Microseconds()))
+			return encodeNotNull(buf[:]), IntervalType, nil
+		case netip.Addr:
+			// addresses are encoded as their 4 or 16 bytes
+			if !t.IsValid() {
+				return nil, nil, errors.New("invalid inet")
+			}
+			b, err := t.MarshalBinary()
+			if err != nil {
+				return nil, nil, err
+			}
+			return encodeNotNull(b), InetType, nil
+		case netip.Prefix:
+			// networks are encoded as their address and the length of their
+			// netmask
+			if !t.IsValid() {
+				return nil, nil, errors.New("invalid cidr")
+			}
+			if t != t.Masked() {
+				return nil, nil, errors.New("invalid cidr: bits are set to the right of the netmask")
+			}
+			b, err := t.MarshalBinary()
+			if err != nil {
+				return nil, nil, err
+			}
+			return encodeNotNull(b), CIDRType, nil
 		case bool:
 			if t {
 				return encodeNotNull([]byte{1}), BoolType, nil
@@ -875,6 +1039,34 @@ func decodeScalar(data []byte, typename string, metadata [2]uint16) (any, error)
 		var uuid UUID
 		copy(uuid[:], data)
 		return &uuid, nil
+	case TimestampTZType.Name:
+		if len(data) != 8 {
+			return nil, fmt.Errorf("timestamptz must be 8 bytes")
+		}
+		ts := time.UnixMicro(int64(binary.BigEndian.Uint64(data))).UTC()
+		return &ts, nil
+	case IntervalType.Name:
+		if len(data) != 8 {
+			return nil, fmt.Errorf("interval must be 8 bytes")
+		}
+		micros := int64(binary.BigEndian.Uint64(data))
+		if micros > maxIntervalMicros || micros < -maxIntervalMicros {
+			return nil, fmt.Errorf("interval out of range")
+		}
+		d := time.Duration(micros) * time.Microsecond
+		return &d, nil
+	case InetType.Name:
+		var addr netip.Addr
+		if err := addr.UnmarshalBinary(data); err != nil || !addr.IsValid() {
+			return nil, fmt.Errorf("invalid inet")
+		}
+		return &addr, nil
+	case CIDRType.Name:
+		var prefix netip.Prefix
+		if err := prefix.UnmarshalBinary(data); err != nil || !prefix.IsValid() || prefix != prefix.Masked() {
+			return nil, fmt.Errorf("invalid cidr")
+		}
+		return &prefix, nil
 	case BoolType.Name:
 		bb := data[0] == 1
 		return &bb, nil
@@ -891,6 +1083,25 @@ func decodeScalar(data []byte, typename string, metadata [2]uint16) (any, error)
 type Transfer struct {
 	To     *AccountID `json:"to"`     // to be string as user identifier
 	Amount *big.Int   `json:"amount"` // big.Int
+	// TokenID is the token that is transferred. It is empty for the native
+	// token.
+	TokenID HexBytes `json:"token_id,omitempty"`
+}
+
+// MaxTokenIDLength is the maximum length of the ID of a token.
+const MaxTokenIDLength = 32
+
+// ErrInvalidTokenID is returned for a token ID that is too long.
+var ErrInvalidTokenID = errors.New("invalid token ID")
+
+// ValidateTokenID checks that a token ID is valid. An empty ID is the native
+// token.
+func ValidateTokenID(token []byte) error {
+	if len(token) > MaxTokenIDLength {
+		return fmt.Errorf("%w: %x is %d bytes long, but token IDs are at most %d bytes long",
+			ErrInvalidTokenID, token, len(token), MaxTokenIDLength)
+	}
+	return nil
 }
 
 var _ Payload = (*Transfer)(nil)
@@ -903,18 +1114,31 @@ var _ encoding.BinaryUnmarshaler = (*Transfer)(nil)
 var _ encoding.BinaryMarshaler = (*Transfer)(nil)
 var _ encoding.BinaryMarshaler = Transfer{}
 
-// transfer payload version
-const tVersion = 0
+// transfer payload versions. Transfers of the native token are encoded in the
+// first version, so that they are encoded as they were before tokens were
+// added.
+const (
+	tVersion      = 0
+	tVersionToken = 1
+)
 
 func (v Transfer) MarshalBinary() ([]byte, error) {
 	if v.To == nil {
 		return nil, errors.New("missing To field in transfer")
 	}
+	if err := ValidateTokenID(v.TokenID); err != nil {
+		return nil, err
+	}
 
 	buf := new(bytes.Buffer)
 
+	version := uint16(tVersion)
+	if len(v.TokenID) > 0 {
+		version = tVersionToken
+	}
+
 	// version uint16
-	if err := binary.Write(buf, SerializationByteOrder, uint16(tVersion)); err != nil {
+	if err := binary.Write(buf, SerializationByteOrder, version); err != nil {
 		return nil, err
 	}
 
@@ -932,6 +1156,13 @@ func (v Transfer) MarshalBinary() ([]byte, error) {
 	if err := WriteBigInt(buf, v.Amount); err != nil {
 		return nil, err
 	}
+
+	// token
+	if version == tVersionToken {
+		if err := WriteBytes(buf, v.TokenID); err != nil {
+			return nil, err
+		}
+	}
 	return buf.Bytes(), nil
 }
 
@@ -942,7 +1173,7 @@ func (v *Transfer) UnmarshalBinary(b []byte) error {
 	if err := binary.Read(rd, SerializationByteOrder, &version); err != nil {
 		return err
 	}
-	if version != tVersion {
+	if version != tVersion && version != tVersionToken {
 		return fmt.Errorf("unsupported transfer payload version %d", version)
 	}
 
@@ -963,6 +1194,21 @@ func (v *Transfer) UnmarshalBinary(b []byte) error {
 	if err != nil {
 		return err
 	}
+
+	// token
+	v.TokenID = nil
+	if version == tVersionToken {
+		v.TokenID, err = ReadBytes(rd)
+		if err != nil {
+			return err
+		}
+		if len(v.TokenID) == 0 {
+			return fmt.Errorf("%w: empty token ID in a token transfer", ErrInvalidTokenID)
+		}
+		if err = ValidateTokenID(v.TokenID); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/binary"
 	"fmt"
+	"math/big"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -792,6 +793,66 @@ func TestRawStatement_MarshalUnmarshal(t *testing.T) {
 	})
 }
 
+func TestDeployNamespace_MarshalUnmarshal(t *testing.T) {
+	t.Run("with expected version", func(t *testing.T) {
+		expected := int64(3)
+		original := DeployNamespace{
+			Namespace:       "shop",
+			Statements:      []string{"CREATE TABLE items (id INT PRIMARY KEY);", "CREATE INDEX ON items (id);"},
+			ExpectedVersion: &expected,
+		}
+
+		data, err := original.MarshalBinary()
+		require.NoError(t, err)
+
+		var decoded DeployNamespace
+		err = decoded.UnmarshalBinary(data)
+		require.NoError(t, err)
+		require.Equal(t, original, decoded)
+	})
+
+	t.Run("without expected version", func(t *testing.T) {
+		original := DeployNamespace{
+			Namespace:  "shop",
+			Statements: []string{},
+		}
+
+		data, err := original.MarshalBinary()
+		require.NoError(t, err)
+
+		var decoded DeployNamespace
+		err = decoded.UnmarshalBinary(data)
+		require.NoError(t, err)
+		require.Equal(t, original, decoded)
+		require.Nil(t, decoded.ExpectedVersion)
+	})
+
+	t.Run("invalid version", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		binary.Write(buf, SerializationByteOrder, uint16(999))
+		WriteString(buf, "shop")
+		binary.Write(buf, SerializationByteOrder, uint16(0))
+		binary.Write(buf, SerializationByteOrder, false)
+
+		var decoded DeployNamespace
+		err := decoded.UnmarshalBinary(buf.Bytes())
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unsupported version")
+	})
+
+	t.Run("truncated expected version", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		binary.Write(buf, SerializationByteOrder, uint16(dnVersion))
+		WriteString(buf, "shop")
+		binary.Write(buf, SerializationByteOrder, uint16(0))
+		binary.Write(buf, SerializationByteOrder, true)
+
+		var decoded DeployNamespace
+		err := decoded.UnmarshalBinary(buf.Bytes())
+		require.Error(t, err)
+	})
+}
+
 func TestActionExecution_MarshalUnmarshal(t *testing.T) {
 	t.Run("valid action execution with multiple calls", func(t *testing.T) {
 		original := ActionExecution{
@@ -1044,3 +1105,36 @@ func TestActionCall_MarshalUnmarshal(t *testing.T) {
 		require.Error(t, err)
 	})
 }
+
+func TestTransfer_MarshalUnmarshal(t *testing.T) {
+	to := &AccountID{Identifier: []byte{1, 2, 3}, KeyType: crypto.KeyTypeSecp256k1}
+
+	t.Run("native token", func(t *testing.T) {
+		original := Transfer{To: to, Amount: big.NewInt(100)}
+
+		data, err := original.MarshalBinary()
+		require.NoError(t, err)
+		require.Equal(t, uint16(tVersion), binary.LittleEndian.Uint16(data))
+
+		var unmarshaled Transfer
+		require.NoError(t, unmarshaled.UnmarshalBinary(data))
+		require.Equal(t, original, unmarshaled)
+	})
+
+	t.Run("token", func(t *testing.T) {
+		original := Transfer{To: to, Amount: big.NewInt(100), TokenID: []byte{0xab, 0xcd}}
+
+		data, err := original.MarshalBinary()
+		require.NoError(t, err)
+		require.Equal(t, uint16(tVersionToken), binary.LittleEndian.Uint16(data))
+
+		var unmarshaled Transfer
+		require.NoError(t, unmarshaled.UnmarshalBinary(data))
+		require.Equal(t, original, unmarshaled)
+	})
+
+	t.Run("token ID too long", func(t *testing.T) {
+		_, err := Transfer{To: to, Amount: big.NewInt(100), TokenID: make([]byte, MaxTokenIDLength+1)}.MarshalBinary()
+		require.ErrorIs(t, err, ErrInvalidTokenID)
+	})
+}
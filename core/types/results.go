@@ -227,6 +227,21 @@ func (e *Event) UnmarshalBinary(data []byte) error {
 	return nil
 }
 
+// PlanComparison is the difference between the query plans of an action and a
+// stored baseline. Plan nodes are described by their type and relation, e.g.
+// "Index Scan on users using users_pkey".
+type PlanComparison struct {
+	// Changed is true if the plan nodes differ from the baseline.
+	Changed bool `json:"changed"`
+	// AddedNodes are the nodes that are in the plan but not in the baseline.
+	AddedNodes []string `json:"added_nodes"`
+	// RemovedNodes are the nodes that are in the baseline but not in the plan.
+	RemovedNodes []string `json:"removed_nodes"`
+	// CostDelta is the estimated total cost of the plan minus that of the
+	// baseline.
+	CostDelta float64 `json:"cost_delta"`
+}
+
 // CallResult is the result of an action call.
 type CallResult struct {
 	QueryResult *QueryResult `json:"query_result"`
@@ -234,6 +249,22 @@ type CallResult struct {
 	Error       *string      `json:"error"`
 }
 
+// SimulationResult is the result of a simulated action call, which is run
+// without keeping any of its changes.
+type SimulationResult struct {
+	// Logs are the logs of the action. If the action failed, the last log is
+	// its error.
+	Logs []string `json:"logs"`
+	// GasEstimate is the gas the action used, which is a unit for each
+	// statement it ran against the database.
+	GasEstimate uint64 `json:"gas_estimate"`
+	// ResultRows are the rows returned by the action.
+	ResultRows [][]any `json:"result_rows"`
+	// WouldSucceed is true if the action would succeed if it were executed
+	// in a transaction.
+	WouldSucceed bool `json:"would_succeed"`
+}
+
 // QueryResult is the result of a SQL query or action.
 type QueryResult struct {
 	ColumnNames []string    `json:"column_names"`
@@ -1,6 +1,12 @@
 package types
 
-import "time"
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
 
 // Duration is a wrapper around time.Duration that implements text
 // (un)marshalling for the go-toml package to work with Go duration strings
@@ -23,3 +29,113 @@ func (d *Duration) UnmarshalText(text []byte) error {
 func (d Duration) String() string {
 	return time.Duration(d).String()
 }
+
+// FormatInterval formats an interval in the text format of Postgres, e.g.
+// "1 day 02:03:04.5" or "-1 days". A day is 24 hours, and the precision is
+// microseconds.
+func FormatInterval(d time.Duration) string {
+	micros := d.Microseconds()
+	days := micros / microsPerDay
+	micros %= microsPerDay
+
+	var sb strings.Builder
+	if days != 0 {
+		unit := "days"
+		if days == 1 {
+			unit = "day"
+		}
+		fmt.Fprintf(&sb, "%d %s", days, unit)
+		if micros == 0 {
+			return sb.String()
+		}
+		sb.WriteByte(' ')
+	}
+
+	if micros < 0 {
+		sb.WriteByte('-')
+		micros = -micros
+	}
+	secs := micros / 1e6
+	fmt.Fprintf(&sb, "%02d:%02d:%02d", secs/3600, secs/60%60, secs%60)
+	if frac := micros % 1e6; frac != 0 {
+		sb.WriteString(strings.TrimRight(fmt.Sprintf(".%06d", frac), "0"))
+	}
+
+	return sb.String()
+}
+
+// ParseInterval parses an interval in the format of FormatInterval. The time
+// may have a sign, e.g. "-1 days +02:00:00", as Postgres formats intervals
+// whose days and time have different signs. Intervals of months or years are
+// not supported, since their length varies.
+func ParseInterval(s string) (time.Duration, error) {
+	invalid := func() (time.Duration, error) {
+		return 0, fmt.Errorf(`invalid interval "%s"`, s)
+	}
+
+	fields := strings.Fields(s)
+	if len(fields) == 0 || len(fields) > 3 {
+		return invalid()
+	}
+
+	var micros int64
+	if len(fields) >= 2 {
+		if fields[1] != "day" && fields[1] != "days" {
+			return invalid()
+		}
+		days, err := strconv.ParseInt(fields[0], 10, 64)
+		if err != nil || days > maxIntervalDays || days < -maxIntervalDays {
+			return invalid()
+		}
+		micros = days * microsPerDay
+		fields = fields[2:]
+	}
+
+	if len(fields) == 1 {
+		t := fields[0]
+		neg := strings.HasPrefix(t, "-")
+		t = strings.TrimLeft(t, "+-")
+
+		parts := strings.Split(t, ":")
+		if len(parts) != 3 {
+			return invalid()
+		}
+		secs, frac, _ := strings.Cut(parts[2], ".")
+		if len(frac) > 6 {
+			return invalid()
+		}
+		frac += strings.Repeat("0", 6-len(frac))
+
+		var nums [4]int64
+		for i, str := range []string{parts[0], parts[1], secs, frac} {
+			n, err := strconv.ParseUint(str, 10, 32)
+			if err != nil {
+				return invalid()
+			}
+			nums[i] = int64(n)
+		}
+		if nums[0] > maxIntervalDays*24 || nums[1] >= 60 || nums[2] >= 60 {
+			return invalid()
+		}
+
+		timeMicros := ((nums[0]*60+nums[1])*60+nums[2])*1e6 + nums[3]
+		if neg {
+			timeMicros = -timeMicros
+		}
+		micros += timeMicros
+	}
+
+	if micros > maxIntervalMicros || micros < -maxIntervalMicros {
+		return invalid()
+	}
+
+	return time.Duration(micros) * time.Microsecond, nil
+}
+
+const (
+	microsPerDay = 24 * 60 * 60 * 1_000_000
+	// maxIntervalMicros and maxIntervalDays are the most microseconds and
+	// days that a time.Duration can hold.
+	maxIntervalMicros = math.MaxInt64 / 1_000
+	maxIntervalDays   = maxIntervalMicros / microsPerDay
+)
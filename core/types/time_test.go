@@ -93,3 +93,38 @@ func TestDurationRoundTrip(t *testing.T) {
 		})
 	}
 }
+
+func TestInterval(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		text string
+	}{
+		{0, "00:00:00"},
+		{24 * time.Hour, "1 day"},
+		{-24 * time.Hour, "-1 days"},
+		{50*time.Hour + 3*time.Minute + 4500*time.Millisecond, "2 days 02:03:04.5"},
+		{-26 * time.Hour, "-1 days -02:00:00"},
+		{-time.Microsecond, "-00:00:00.000001"},
+		{23*time.Hour + 59*time.Minute, "23:59:00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			require.Equal(t, tt.text, FormatInterval(tt.d))
+
+			d, err := ParseInterval(tt.text)
+			require.NoError(t, err)
+			require.Equal(t, tt.d, d)
+		})
+	}
+
+	// days and times with different signs are added
+	d, err := ParseInterval("-1 days +02:00:00")
+	require.NoError(t, err)
+	assert.Equal(t, -22*time.Hour, d)
+
+	for _, s := range []string{"", "1 mon", "1 year 2 days", "1 day 25", "00:60:00", "1 day 1 day", "1h30m", "200000 days"} {
+		_, err := ParseInterval(s)
+		assert.Error(t, err, s)
+	}
+}
@@ -109,6 +109,7 @@ func (s *Spend) UnmarshalBinary(data []byte) (err error) {
 func InitializeAccountStore(ctx context.Context, db sql.DB, logger log.Logger) (*Accounts, error) {
 	upgradeFns := map[int64]versioning.UpgradeFunc{
 		0: initTables,
+		1: initTokenTables,
 	}
 
 	err := versioning.Upgrade(ctx, db, schemaName, upgradeFns, accountStoreVersion)
@@ -3,6 +3,7 @@ package accounts
 import (
 	"context"
 	"errors"
+	"fmt"
 	"math/big"
 	"testing"
 
@@ -31,11 +32,13 @@ func (m *mockTx) Rollback(ctx context.Context) error {
 type mockDB struct {
 	accessCnt int64
 	accts     map[string]*types.Account
+	tokens    map[string]string
 }
 
 func newDB() *mockDB {
 	return &mockDB{
-		accts: make(map[string]*types.Account),
+		accts:  make(map[string]*types.Account),
+		tokens: make(map[string]string),
 	}
 }
 
@@ -132,6 +135,25 @@ func (m *mockDB) Execute(ctx context.Context, stmt string, args ...any) (*sql.Re
 				{account.Balance.String(), account.Nonce},
 			},
 		}, nil
+	case sqlGetTokenBalance: // via getTokenBalance
+		key := fmt.Sprintf("%x:%d:%x", args[0], args[1], args[2])
+		bal, ok := m.tokens[key]
+		if !ok {
+			return &sql.ResultSet{}, nil
+		}
+		return &sql.ResultSet{
+			Columns: []string{"balance"},
+			Rows:    [][]any{{bal}},
+		}, nil
+	case sqlSetTokenBalance: // via setTokenBalance
+		key := fmt.Sprintf("%x:%d:%x", args[0], args[1], args[2])
+		m.tokens[key] = args[3].(string)
+		return &sql.ResultSet{
+			Status: sql.CommandTag{
+				RowsAffected: 1,
+				Text:         `INSERT ...`,
+			},
+		}, nil
 	default:
 		return nil, errors.New("bad query")
 	}
@@ -406,6 +428,50 @@ var acctsTestCases = []acctsTestCase{
 			verifyDBAccessCount(t, c, 1, skip)
 		},
 	},
+	{
+		name: "token transfers",
+		fn: func(t *testing.T, db sql.DB, a *Accounts, c counter, skip bool) {
+			ctx := context.Background()
+			tokenA, tokenB := []byte{0xa}, []byte{0xb}
+
+			require.NoError(t, a.Credit(ctx, db, account1, big.NewInt(1000)))
+			require.NoError(t, a.CreditToken(ctx, db, account1, tokenA, big.NewInt(100)))
+			require.NoError(t, a.CreditToken(ctx, db, account1, tokenB, big.NewInt(50)))
+
+			balance := func(acct *types.AccountID, token []byte) int64 {
+				bal, err := a.TokenBalance(ctx, db, acct, token)
+				require.NoError(t, err)
+				return bal.Int64()
+			}
+
+			require.NoError(t, a.TransferToken(ctx, db, account1, account2, tokenA, big.NewInt(30)))
+			assert.Equal(t, int64(70), balance(account1, tokenA))
+			assert.Equal(t, int64(30), balance(account2, tokenA))
+
+			// token B and the native token are not affected
+			assert.Equal(t, int64(50), balance(account1, tokenB))
+			assert.Equal(t, int64(0), balance(account2, tokenB))
+			acct, err := a.GetAccount(ctx, db, account1)
+			require.NoError(t, err)
+			assert.Equal(t, int64(1000), acct.Balance.Int64())
+
+			// transferring more than the balance of the token fails
+			err = a.TransferToken(ctx, db, account2, account1, tokenA, big.NewInt(31))
+			require.ErrorIs(t, err, ErrInsufficientFunds)
+			err = a.TransferToken(ctx, db, account2, account1, tokenB, big.NewInt(1))
+			require.ErrorIs(t, err, ErrInsufficientFunds)
+
+			// a transfer to oneself does not change the balance
+			require.NoError(t, a.TransferToken(ctx, db, account1, account1, tokenA, big.NewInt(70)))
+			assert.Equal(t, int64(70), balance(account1, tokenA))
+
+			// the native token and overlong IDs are not tokens
+			err = a.TransferToken(ctx, db, account1, account2, nil, big.NewInt(1))
+			require.ErrorIs(t, err, types.ErrInvalidTokenID)
+			_, err = a.TokenBalance(ctx, db, account1, make([]byte, types.MaxTokenIDLength+1))
+			require.ErrorIs(t, err, types.ErrInvalidTokenID)
+		},
+	},
 	{
 		name: "Account Cache test",
 		fn: func(t *testing.T, db sql.DB, a *Accounts, c counter, skip bool) {
@@ -14,7 +14,7 @@ import (
 const (
 	schemaName = `kwild_accts`
 
-	accountStoreVersion = 0
+	accountStoreVersion = 1
 
 	sqlInitTables = `CREATE TABLE IF NOT EXISTS ` + schemaName + `.accounts (
 		identifier BYTEA NOT NULL,
@@ -32,6 +32,23 @@ const (
 	sqlGetAccount = `SELECT balance, nonce FROM ` + schemaName + `.accounts WHERE identifier = $1 AND id_type = $2`
 
 	sqlNumAccounts = `SELECT COUNT(1) FROM ` + schemaName + `.accounts`
+
+	// token balances are kept apart from the accounts, which hold the
+	// balances of the native token
+	sqlInitTokenTables = `CREATE TABLE IF NOT EXISTS ` + schemaName + `.token_balances (
+		identifier BYTEA NOT NULL,
+		id_type INT4 NOT NULL,
+		token_id BYTEA NOT NULL,
+		balance TEXT NOT NULL,
+		PRIMARY KEY(identifier, id_type, token_id)
+	);`
+
+	sqlGetTokenBalance = `SELECT balance FROM ` + schemaName + `.token_balances
+		WHERE identifier = $1 AND id_type = $2 AND token_id = $3`
+
+	sqlSetTokenBalance = `INSERT INTO ` + schemaName + `.token_balances (identifier, id_type, token_id, balance)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (identifier, id_type, token_id) DO UPDATE SET balance = EXCLUDED.balance`
 )
 
 func initTables(ctx context.Context, tx sql.DB) error {
@@ -43,6 +60,49 @@ func initTables(ctx context.Context, tx sql.DB) error {
 	return nil
 }
 
+func initTokenTables(ctx context.Context, tx sql.DB) error {
+	_, err := tx.Execute(ctx, sqlInitTokenTables)
+	if err != nil {
+		return fmt.Errorf("failed to initialize token tables: %w", err)
+	}
+
+	return nil
+}
+
+// getTokenBalance retrieves the balance of an account in a token. It is zero
+// if the account has never held the token.
+func getTokenBalance(ctx context.Context, db sql.Executor, acctID []byte, acctType uint32, token []byte) (*big.Int, error) {
+	results, err := db.Execute(ctx, sqlGetTokenBalance, acctID, acctType, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(results.Rows) == 0 {
+		return big.NewInt(0), nil
+	}
+	if len(results.Rows) > 1 {
+		return nil, fmt.Errorf("expected 1 row, got %d", len(results.Rows))
+	}
+
+	stringBal, ok := results.Rows[0][0].(string)
+	if !ok {
+		return nil, errors.New("failed to convert stored string balance to big int")
+	}
+
+	balance, ok := new(big.Int).SetString(stringBal, 10)
+	if !ok {
+		return nil, ErrConvertToBigInt
+	}
+
+	return balance, nil
+}
+
+// setTokenBalance sets the balance of an account in a token.
+func setTokenBalance(ctx context.Context, db sql.Executor, acctID []byte, acctType uint32, token []byte, amount *big.Int) error {
+	_, err := db.Execute(ctx, sqlSetTokenBalance, acctID, acctType, token, amount.String())
+	return err
+}
+
 // updateAccount updates the balance and nonce of an account.
 func updateAccount(ctx context.Context, db sql.Executor, acctID []byte, acctType uint32, amount *big.Int, nonce int64) error {
 	_, err := db.Execute(ctx, sqlUpdateAccount, amount.String(), nonce, acctID, acctType)
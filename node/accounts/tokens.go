@@ -0,0 +1,118 @@
+package accounts
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/kwilteam/kwil-db/core/crypto"
+	"github.com/kwilteam/kwil-db/core/types"
+	"github.com/kwilteam/kwil-db/node/types/sql"
+)
+
+// Balances of tokens other than the native token are kept in their own table.
+// Unlike the native balances, they are not cached, and are read from and
+// written to the database directly.
+
+// TokenBalance retrieves the balance of an account in a token. It is zero if
+// the account has never held the token.
+func (a *Accounts) TokenBalance(ctx context.Context, tx sql.Executor, account *types.AccountID, token []byte) (*big.Int, error) {
+	flag, err := tokenAccount(account, token)
+	if err != nil {
+		return nil, err
+	}
+
+	return getTokenBalance(ctx, tx, account.Identifier, flag, token)
+}
+
+// CreditToken credits an account with an amount of a token. A negative amount
+// is a debit, which cannot make the balance negative.
+func (a *Accounts) CreditToken(ctx context.Context, tx sql.Executor, account *types.AccountID, token []byte, amt *big.Int) error {
+	flag, err := tokenAccount(account, token)
+	if err != nil {
+		return err
+	}
+
+	bal, err := getTokenBalance(ctx, tx, account.Identifier, flag, token)
+	if err != nil {
+		return err
+	}
+
+	newBal := new(big.Int).Add(bal, amt)
+	if newBal.Sign() < 0 {
+		return ErrNegativeBalance
+	}
+
+	return setTokenBalance(ctx, tx, account.Identifier, flag, token, newBal)
+}
+
+// TransferToken transfers an amount of a token from one account to another.
+// It fails if the from account does not have enough of the token. Balances of
+// other tokens, including the native token, are not changed.
+func (a *Accounts) TransferToken(ctx context.Context, db sql.TxMaker, from, to *types.AccountID, token []byte, amt *big.Int) error {
+	if amt.Sign() < 0 {
+		return ErrNegativeTransfer
+	}
+
+	fromFlag, err := tokenAccount(from, token)
+	if err != nil {
+		return err
+	}
+	toFlag, err := tokenAccount(to, token)
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	fromBal, err := getTokenBalance(ctx, tx, from.Identifier, fromFlag, token)
+	if err != nil {
+		return err
+	}
+
+	newFromBal := new(big.Int).Sub(fromBal, amt)
+	if newFromBal.Sign() < 0 {
+		return fmt.Errorf("%w: account %s tried to use %s of token %x, but only has balance %s",
+			ErrInsufficientFunds, from, amt, token, fromBal)
+	}
+
+	// the sender is debited before the receiver is read, so that a transfer
+	// to oneself does not change the balance
+	if err = setTokenBalance(ctx, tx, from.Identifier, fromFlag, token, newFromBal); err != nil {
+		return err
+	}
+
+	toBal, err := getTokenBalance(ctx, tx, to.Identifier, toFlag, token)
+	if err != nil {
+		return err
+	}
+
+	if err = setTokenBalance(ctx, tx, to.Identifier, toFlag, token, toBal.Add(toBal, amt)); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// tokenAccount checks the account and token of a token balance, and returns
+// the encoded key type of the account. The native token, which has an empty
+// ID, is not a token balance.
+func tokenAccount(account *types.AccountID, token []byte) (uint32, error) {
+	if len(token) == 0 {
+		return 0, fmt.Errorf("%w: the native token is not held in token balances", types.ErrInvalidTokenID)
+	}
+	if err := types.ValidateTokenID(token); err != nil {
+		return 0, err
+	}
+
+	kd, ok := crypto.KeyTypeDefinition(account.KeyType)
+	if !ok {
+		return 0, fmt.Errorf("invalid key type: %s", account.KeyType)
+	}
+
+	return kd.EncodeFlag(), nil
+}
@@ -910,6 +910,10 @@ func (bp *BlockProcessor) AccountInfo(ctx context.Context, db sql.DB, identifier
 	return bp.txapp.AccountInfo(ctx, db, identifier, pending)
 }
 
+func (bp *BlockProcessor) TokenBalance(ctx context.Context, db sql.DB, identifier *ktypes.AccountID, token []byte) (*big.Int, error) {
+	return bp.txapp.TokenBalance(ctx, db, identifier, token)
+}
+
 func (bp *BlockProcessor) NumAccounts(ctx context.Context, db sql.Executor) (count, height int64, err error) {
 	return bp.txapp.NumAccounts(ctx, db)
 }
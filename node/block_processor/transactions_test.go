@@ -681,6 +681,10 @@ func (m *mockTxApp) AccountInfo(ctx context.Context, db sql.DB, acctID *types.Ac
 	return accountBalance, 0, nil
 }
 
+func (m *mockTxApp) TokenBalance(ctx context.Context, db sql.DB, acctID *types.AccountID, token []byte) (*big.Int, error) {
+	return big.NewInt(0), nil
+}
+
 func (a *mockTxApp) NumAccounts(ctx context.Context, tx sql.Executor) (int64, int64, error) {
 	return 1, 1, nil
 }
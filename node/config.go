@@ -26,4 +26,7 @@ type Config struct {
 	Logger      log.Logger
 
 	P2PService *P2PService
+
+	// NamespaceGossip, if set, gossips namespace deployments with peers.
+	NamespaceGossip *NamespaceGossip
 }
@@ -2,6 +2,8 @@ package engine
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 )
 
 const (
@@ -22,6 +24,7 @@ var (
 	ErrArithmetic              = errors.New("arithmetic error")
 	ErrComparison              = errors.New("comparison error")
 	ErrCast                    = errors.New("type cast error")
+	ErrTypeCoercionFailed      = errors.New("type coercion failed")
 	ErrUnary                   = errors.New("unary operation error")
 	ErrIndexOutOfBounds        = errors.New("index out of bounds")
 	ErrArrayDimensionality     = errors.New("array dimensionality error")
@@ -29,10 +32,12 @@ var (
 	ErrArrayTooSmall           = errors.New("array too small")
 	ErrExtensionImplementation = errors.New("extension implementation error")
 	ErrActionInvocation        = errors.New("action invocation error")
+	ErrWindowGroupByConflict   = errors.New("window function conflicts with GROUP BY")
 
 	// Errors that signal the existence or non-existence of an object.
 	ErrUnknownAction     = errors.New("unknown action")
 	ErrUnknownTable      = errors.New("unknown table")
+	ErrUnknownColumn     = errors.New("unknown column")
 	ErrNamespaceNotFound = errors.New("namespace not found")
 	ErrNamespaceExists   = errors.New("namespace already exists")
 
@@ -50,6 +55,9 @@ var (
 	ErrInvalidTxCtx               = errors.New("invalid transaction context")
 	ErrReservedNamespacePrefix    = errors.New("namespace prefix is reserved")
 	ErrCannotAlterPrimaryKey      = errors.New("cannot drop or alter a table's primary key")
+	ErrNamespaceReadOnly          = errors.New("namespace is read-only")
+	ErrForeignKeyViolation        = errors.New("foreign key violation")
+	ErrVersionConflict            = errors.New("namespace version conflict")
 
 	// Errors that are the result of not having proper permissions or failing to meet a condition
 	// that was programmed by the user.
@@ -63,4 +71,243 @@ var (
 	ErrParse        = errors.New("parse error")
 	ErrQueryPlanner = errors.New("query planner error")
 	ErrPGGen        = errors.New("postgres SQL generation error")
+
+	// ErrBackpressure is returned when the interpreter is already executing its
+	// maximum number of concurrent calls.
+	ErrBackpressure = errors.New("too many concurrent calls, try again later")
+	// ErrCircuitOpen is returned when calls to an extension are rejected
+	// because its recent calls have kept failing.
+	ErrCircuitOpen = errors.New("extension circuit is open")
+	// ErrNotHomeNode is returned when a namespace is called on a node other
+	// than its home node. It is wrapped by a *NotHomeNodeError.
+	ErrNotHomeNode = errors.New("not the home node of the namespace")
+	// ErrStatementTimeout is returned when a call or statement runs for longer
+	// than it is allowed to. It is wrapped by a *StatementTimeoutError.
+	ErrStatementTimeout = errors.New("statement timeout")
+	// ErrExtensionPanic is returned when an extension method panics. It is
+	// wrapped by an *ExtensionPanicError.
+	ErrExtensionPanic = errors.New("extension panicked")
 )
+
+// NotHomeNodeError is returned when a namespace is called on a node other than
+// its home node. The call should be made to HomeNode instead.
+type NotHomeNodeError struct {
+	// Namespace is the namespace that was called.
+	Namespace string
+	// HomeNode is the address of the namespace's home node.
+	HomeNode string
+}
+
+func (e *NotHomeNodeError) Error() string {
+	return fmt.Sprintf("%v: namespace %s is served by %s", ErrNotHomeNode, e.Namespace, e.HomeNode)
+}
+
+func (e *NotHomeNodeError) Unwrap() error {
+	return ErrNotHomeNode
+}
+
+// NamespaceNotFoundError is returned when a namespace that does not exist is
+// called. It wraps ErrNamespaceNotFound.
+type NamespaceNotFoundError struct {
+	// Namespace is the namespace that was called.
+	Namespace string
+}
+
+func (e *NamespaceNotFoundError) Error() string {
+	return fmt.Sprintf(`%v: "%s"`, ErrNamespaceNotFound, e.Namespace)
+}
+
+func (e *NamespaceNotFoundError) Unwrap() error {
+	return ErrNamespaceNotFound
+}
+
+// ActionNotFoundError is returned when an action that does not exist is
+// called. It wraps ErrUnknownAction.
+type ActionNotFoundError struct {
+	// Namespace is the namespace of the action.
+	Namespace string
+	// Action is the action that was called.
+	Action string
+}
+
+func (e *ActionNotFoundError) Error() string {
+	return fmt.Sprintf(`%v: action "%s" does not exist in namespace "%s"`, ErrUnknownAction, e.Action, e.Namespace)
+}
+
+func (e *ActionNotFoundError) Unwrap() error {
+	return ErrUnknownAction
+}
+
+// UnauthorizedError is returned when a caller is not allowed to call an
+// action. Err is the reason, such as ErrActionPrivate or
+// ErrDoesNotHavePrivilege.
+type UnauthorizedError struct {
+	// Namespace is the namespace of the action.
+	Namespace string
+	// Action is the action that was called.
+	Action string
+	// Caller is the caller of the action.
+	Caller string
+	// Err is the reason the caller is not allowed to call the action.
+	Err error
+}
+
+func (e *UnauthorizedError) Error() string {
+	return fmt.Sprintf(`caller "%s" cannot call action "%s"."%s": %v`, e.Caller, e.Namespace, e.Action, e.Err)
+}
+
+func (e *UnauthorizedError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationError is returned when the arguments of a call, or the
+// parameters of a statement, are invalid. Action is empty for statements.
+type ValidationError struct {
+	// Namespace is the namespace of the action or statement.
+	Namespace string
+	// Action is the action that was called. It is empty for statements.
+	Action string
+	// Err is the reason the arguments are invalid.
+	Err error
+}
+
+func (e *ValidationError) Error() string {
+	if e.Action == "" {
+		return fmt.Sprintf("invalid statement parameters: %v", e.Err)
+	}
+	return fmt.Sprintf(`invalid arguments to action "%s"."%s": %v`, e.Namespace, e.Action, e.Err)
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// QuotaExceededError is returned when a call or statement is rejected
+// because the interpreter is at its limit, such as its maximum number of
+// concurrent calls. Err is the limit that was reached, such as
+// ErrBackpressure. Action is empty for statements.
+type QuotaExceededError struct {
+	// Namespace is the namespace of the action or statement.
+	Namespace string
+	// Action is the action that was called. It is empty for statements.
+	Action string
+	// Caller is the caller of the action or statement.
+	Caller string
+	// Err is the limit that was reached.
+	Err error
+}
+
+func (e *QuotaExceededError) Error() string {
+	if e.Action == "" {
+		return fmt.Sprintf(`statement by "%s" rejected: %v`, e.Caller, e.Err)
+	}
+	return fmt.Sprintf(`call to action "%s"."%s" by "%s" rejected: %v`, e.Namespace, e.Action, e.Caller, e.Err)
+}
+
+func (e *QuotaExceededError) Unwrap() error {
+	return e.Err
+}
+
+// StatementTimeoutError is returned when a call or statement is stopped
+// because it ran past the deadline of its context, or past Postgres's
+// statement_timeout. It wraps ErrStatementTimeout and Err, the error that
+// stopped it. Action is empty for statements.
+type StatementTimeoutError struct {
+	// Namespace is the namespace of the action or statement.
+	Namespace string
+	// Action is the action that was called. It is empty for statements.
+	Action string
+	// Err is the error that stopped the call or statement.
+	Err error
+}
+
+func (e *StatementTimeoutError) Error() string {
+	if e.Action == "" {
+		return fmt.Sprintf("%v: %v", ErrStatementTimeout, e.Err)
+	}
+	return fmt.Sprintf(`%v: action "%s"."%s": %v`, ErrStatementTimeout, e.Namespace, e.Action, e.Err)
+}
+
+func (e *StatementTimeoutError) Unwrap() []error {
+	return []error{ErrStatementTimeout, e.Err}
+}
+
+// ExtensionPanicError is returned when the method of an extension panics. It
+// wraps ErrExtensionPanic.
+type ExtensionPanicError struct {
+	// Namespace is the namespace of the extension.
+	Namespace string
+	// Method is the method that panicked.
+	Method string
+	// Value is the value the method panicked with.
+	Value any
+}
+
+func (e *ExtensionPanicError) Error() string {
+	return fmt.Sprintf(`%v: method "%s"."%s": %v`, ErrExtensionPanic, e.Namespace, e.Method, e.Value)
+}
+
+func (e *ExtensionPanicError) Unwrap() error {
+	return ErrExtensionPanic
+}
+
+// SchemaError is an error in the schema of a table. It identifies the table,
+// and the column or index of the table, that caused the error.
+type SchemaError struct {
+	// Table is the name of the table.
+	Table string
+	// Column is the name of the column. It is empty if the error is not
+	// caused by a column.
+	Column string
+	// Index is the name of the index. It is empty if the error is not caused
+	// by an index.
+	Index string
+	// Cause is the error that occurred.
+	Cause error
+}
+
+func (e *SchemaError) Error() string {
+	path := fmt.Sprintf("table '%s'", e.Table)
+	if e.Column != "" {
+		path += fmt.Sprintf(", column '%s'", e.Column)
+	}
+	if e.Index != "" {
+		path += fmt.Sprintf(", index '%s'", e.Index)
+	}
+	return fmt.Sprintf("%s: %v", path, e.Cause)
+}
+
+func (e *SchemaError) Unwrap() error {
+	return e.Cause
+}
+
+// ModifierValidationError is returned when the modifiers of an action are
+// invalid. Err joins all of the violations, so that they can be fixed at once.
+type ModifierValidationError struct {
+	// Modifiers are the modifiers as they were declared.
+	Modifiers []string
+	// Err joins the violations of the modifiers.
+	Err error
+}
+
+func (e *ModifierValidationError) Error() string {
+	return fmt.Sprintf(`invalid modifiers "%s": %v`, strings.Join(e.Modifiers, ", "), e.Err)
+}
+
+func (e *ModifierValidationError) Unwrap() error {
+	return e.Err
+}
+
+// ApplicationError is an error raised by an action with raise_error. Its code
+// is one of the error types the action declared with @error_type, so callers
+// can handle it without parsing the message.
+type ApplicationError struct {
+	// Code identifies the error.
+	Code int
+	// Message describes the error.
+	Message string
+}
+
+func (e *ApplicationError) Error() string {
+	return fmt.Sprintf("error %d: %s", e.Code, e.Message)
+}
@@ -0,0 +1,79 @@
+package engine_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/kwilteam/kwil-db/node/engine"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ErrorTypes(t *testing.T) {
+	// each error is wrapped, as it is when it is returned from a nested call
+	wrap := func(err error) error {
+		return fmt.Errorf("call stack: %w", err)
+	}
+
+	t.Run("namespace not found", func(t *testing.T) {
+		err := wrap(&engine.NamespaceNotFoundError{Namespace: "ns"})
+		var target *engine.NamespaceNotFoundError
+		require.ErrorAs(t, err, &target)
+		require.Equal(t, "ns", target.Namespace)
+		require.ErrorIs(t, err, engine.ErrNamespaceNotFound)
+	})
+
+	t.Run("action not found", func(t *testing.T) {
+		err := wrap(&engine.ActionNotFoundError{Namespace: "ns", Action: "act"})
+		var target *engine.ActionNotFoundError
+		require.ErrorAs(t, err, &target)
+		require.Equal(t, "act", target.Action)
+		require.ErrorIs(t, err, engine.ErrUnknownAction)
+		require.EqualError(t, err, `call stack: unknown action: action "act" does not exist in namespace "ns"`)
+	})
+
+	t.Run("unauthorized", func(t *testing.T) {
+		err := wrap(&engine.UnauthorizedError{Namespace: "ns", Action: "act", Caller: "alice",
+			Err: fmt.Errorf("%w: action act is private", engine.ErrActionPrivate)})
+		var target *engine.UnauthorizedError
+		require.ErrorAs(t, err, &target)
+		require.Equal(t, "alice", target.Caller)
+		require.ErrorIs(t, err, engine.ErrActionPrivate)
+	})
+
+	t.Run("validation failed", func(t *testing.T) {
+		err := wrap(&engine.ValidationError{Namespace: "ns", Action: "act", Err: engine.ErrActionInvocation})
+		var target *engine.ValidationError
+		require.ErrorAs(t, err, &target)
+		require.Equal(t, "act", target.Action)
+		require.ErrorIs(t, err, engine.ErrActionInvocation)
+	})
+
+	t.Run("quota exceeded", func(t *testing.T) {
+		err := wrap(&engine.QuotaExceededError{Namespace: "ns", Action: "act", Caller: "alice", Err: engine.ErrBackpressure})
+		var target *engine.QuotaExceededError
+		require.ErrorAs(t, err, &target)
+		require.Equal(t, "alice", target.Caller)
+		require.ErrorIs(t, err, engine.ErrBackpressure)
+	})
+
+	t.Run("statement timeout", func(t *testing.T) {
+		err := wrap(&engine.StatementTimeoutError{Namespace: "ns", Err: context.DeadlineExceeded})
+		var target *engine.StatementTimeoutError
+		require.ErrorAs(t, err, &target)
+		require.Equal(t, "ns", target.Namespace)
+		// it is both a statement timeout and the error that stopped it
+		require.ErrorIs(t, err, engine.ErrStatementTimeout)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("extension panic", func(t *testing.T) {
+		err := wrap(&engine.ExtensionPanicError{Namespace: "ext", Method: "m", Value: "boom"})
+		var target *engine.ExtensionPanicError
+		require.ErrorAs(t, err, &target)
+		require.Equal(t, "boom", target.Value)
+		require.ErrorIs(t, err, engine.ErrExtensionPanic)
+		require.False(t, errors.Is(err, engine.ErrNamespaceNotFound))
+	})
+}
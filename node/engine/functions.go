@@ -46,6 +46,64 @@ var (
 				return def + "::text", nil
 			},
 		},
+		"config_get": &ScalarFunctionDefinition{
+			ValidateArgsFunc: func(args []*types.DataType) (*types.DataType, error) {
+				if len(args) != 1 {
+					return nil, wrapErrArgumentNumber(1, len(args))
+				}
+
+				if !args[0].Equals(types.TextType) {
+					return nil, wrapErrArgumentType(types.TextType, args[0])
+				}
+
+				return types.TextType, nil
+			},
+			PGFormatFunc: func(inputs []string) (string, error) {
+				return "", fmt.Errorf(`%w: "config_get" cannot be used in SQL statements`, ErrIllegalFunctionUsage)
+			},
+		},
+		"config_set": &ScalarFunctionDefinition{
+			ValidateArgsFunc: func(args []*types.DataType) (*types.DataType, error) {
+				if len(args) != 2 {
+					return nil, wrapErrArgumentNumber(2, len(args))
+				}
+
+				for _, arg := range args {
+					if !arg.Equals(types.TextType) {
+						return nil, wrapErrArgumentType(types.TextType, arg)
+					}
+				}
+
+				return types.NullType, nil
+			},
+			PGFormatFunc: func(inputs []string) (string, error) {
+				return "", fmt.Errorf(`%w: "config_set" cannot be used in SQL statements`, ErrIllegalFunctionUsage)
+			},
+		},
+		"raise_error": &ScalarFunctionDefinition{
+			ValidateArgsFunc: func(args []*types.DataType) (*types.DataType, error) {
+				// the code and the message
+				if len(args) != 2 {
+					return nil, wrapErrArgumentNumber(2, len(args))
+				}
+
+				if !args[0].Equals(types.IntType) {
+					return nil, wrapErrArgumentType(types.IntType, args[0])
+				}
+
+				if !args[1].Equals(types.TextType) {
+					return nil, wrapErrArgumentType(types.TextType, args[1])
+				}
+
+				// like error, it returns text so that it can be planned
+				return types.TextType, nil
+			},
+			PGFormatFunc: func(inputs []string) (string, error) {
+				// it is implemented by the engine, since the error types are
+				// declared by actions
+				return "", fmt.Errorf(`%w: "raise_error" cannot be used in SQL statements`, ErrIllegalFunctionUsage)
+			},
+		},
 		"parse_unix_timestamp": &ScalarFunctionDefinition{
 			ValidateArgsFunc: func(args []*types.DataType) (*types.DataType, error) {
 				// two args, both text
@@ -102,6 +160,60 @@ var (
 				return "", fmt.Errorf(`%w: "notice" cannot be used in SQL statements`, ErrIllegalFunctionUsage)
 			},
 		},
+		"soft_delete_row": &ScalarFunctionDefinition{
+			ValidateArgsFunc: softDeleteArgs,
+			PGFormatFunc: func(inputs []string) (string, error) {
+				return "", fmt.Errorf(`%w: "soft_delete_row" cannot be used in SQL statements`, ErrIllegalFunctionUsage)
+			},
+		},
+		"get_row_history": &ScalarFunctionDefinition{
+			ValidateArgsFunc: func(args []*types.DataType) (*types.DataType, error) {
+				// table name, primary key value of any type, and limit
+				if len(args) != 3 {
+					return nil, wrapErrArgumentNumber(3, len(args))
+				}
+
+				if !args[0].Equals(types.TextType) {
+					return nil, wrapErrArgumentType(types.TextType, args[0])
+				}
+
+				if !args[2].Equals(types.IntType) {
+					return nil, wrapErrArgumentType(types.IntType, args[2])
+				}
+
+				// get_row_history returns a table, so it can only be looped over
+				return types.NullType, nil
+			},
+			PGFormatFunc: func(inputs []string) (string, error) {
+				return "", fmt.Errorf(`%w: "get_row_history" cannot be used in SQL statements`, ErrIllegalFunctionUsage)
+			},
+		},
+		"restore_row": &ScalarFunctionDefinition{
+			ValidateArgsFunc: softDeleteArgs,
+			PGFormatFunc: func(inputs []string) (string, error) {
+				return "", fmt.Errorf(`%w: "restore_row" cannot be used in SQL statements`, ErrIllegalFunctionUsage)
+			},
+		},
+		"seeded_random": &ScalarFunctionDefinition{
+			ValidateArgsFunc: func(args []*types.DataType) (*types.DataType, error) {
+				// the seed, and the exclusive upper bound of the number
+				if len(args) != 2 {
+					return nil, wrapErrArgumentNumber(2, len(args))
+				}
+
+				for _, arg := range args {
+					if !arg.Equals(types.IntType) {
+						return nil, wrapErrArgumentType(types.IntType, arg)
+					}
+				}
+
+				return types.IntType, nil
+			},
+			PGFormatFunc: func(inputs []string) (string, error) {
+				// it is implemented by the engine, not by Postgres
+				return "", fmt.Errorf(`%w: "seeded_random" cannot be used in SQL statements`, ErrIllegalFunctionUsage)
+			},
+		},
 		"uuid_generate_v5": &ScalarFunctionDefinition{
 			ValidateArgsFunc: func(args []*types.DataType) (*types.DataType, error) {
 				// first argument must be a uuid, second argument must be text
@@ -139,6 +251,300 @@ var (
 				return "uuid_generate_v5('a247cac1-d817-4949-bac7-dc4b1dc41d09'::uuid," + inputs[0] + ")", nil
 			},
 		},
+		// uuid_generate returns a random UUID. Since nodes generate different
+		// UUIDs, it can only be used by calls that cannot change state;
+		// uuid_generate_kwil returns the same UUID on every node.
+		"uuid_generate": &ScalarFunctionDefinition{
+			ValidateArgsFunc: func(args []*types.DataType) (*types.DataType, error) {
+				if len(args) != 0 {
+					return nil, wrapErrArgumentNumber(0, len(args))
+				}
+
+				return types.UUIDType, nil
+			},
+			PGFormatFunc: func(inputs []string) (string, error) {
+				// it is implemented by the engine, so that it can be rejected
+				// in calls that can change state
+				return "", fmt.Errorf(`%w: "uuid_generate" cannot be used in SQL statements`, ErrIllegalFunctionUsage)
+			},
+		},
+		"uuid_nil": &ScalarFunctionDefinition{
+			ValidateArgsFunc: func(args []*types.DataType) (*types.DataType, error) {
+				if len(args) != 0 {
+					return nil, wrapErrArgumentNumber(0, len(args))
+				}
+
+				return types.UUIDType, nil
+			},
+			PGFormatFunc: func(inputs []string) (string, error) {
+				return "'00000000-0000-0000-0000-000000000000'::uuid", nil
+			},
+		},
+		"uuid_parse": &ScalarFunctionDefinition{
+			ValidateArgsFunc: func(args []*types.DataType) (*types.DataType, error) {
+				// 1 argument, must be text
+				if len(args) != 1 {
+					return nil, wrapErrArgumentNumber(1, len(args))
+				}
+
+				if !args[0].Equals(types.TextType) {
+					return nil, wrapErrArgumentType(types.TextType, args[0])
+				}
+
+				return types.UUIDType, nil
+			},
+			PGFormatFunc: func(inputs []string) (string, error) {
+				return "(" + inputs[0] + ")::uuid", nil
+			},
+		},
+		// the decimal functions compute on NUMERIC values of any precision and
+		// scale. Their results have a precision and scale that hold them
+		// exactly, except for division, which is rounded to the larger scale
+		// of its arguments.
+		"decimal_add": &ScalarFunctionDefinition{
+			ValidateArgsFunc: func(args []*types.DataType) (*types.DataType, error) {
+				if err := checkDecimalArgs(args); err != nil {
+					return nil, err
+				}
+
+				s := max(args[0].Metadata[1], args[1].Metadata[1])
+				digits := max(args[0].Metadata[0]-args[0].Metadata[1], args[1].Metadata[0]-args[1].Metadata[1])
+				// adding can carry into another digit
+				return decimalResultType(args, digits+1+s, s)
+			},
+			PGFormatFunc: func(inputs []string) (string, error) {
+				return fmt.Sprintf("(%s + %s)", inputs[0], inputs[1]), nil
+			},
+		},
+		"decimal_mul": &ScalarFunctionDefinition{
+			ValidateArgsFunc: func(args []*types.DataType) (*types.DataType, error) {
+				if err := checkDecimalArgs(args); err != nil {
+					return nil, err
+				}
+
+				return decimalResultType(args, args[0].Metadata[0]+args[1].Metadata[0], args[0].Metadata[1]+args[1].Metadata[1])
+			},
+			PGFormatFunc: func(inputs []string) (string, error) {
+				return fmt.Sprintf("(%s * %s)", inputs[0], inputs[1]), nil
+			},
+		},
+		"decimal_div": &ScalarFunctionDefinition{
+			ValidateArgsFunc: func(args []*types.DataType) (*types.DataType, error) {
+				if err := checkDecimalArgs(args); err != nil {
+					return nil, err
+				}
+
+				// the quotient is largest when the divisor is the smallest
+				// value of its scale
+				s := max(args[0].Metadata[1], args[1].Metadata[1])
+				digits := args[0].Metadata[0] - args[0].Metadata[1] + args[1].Metadata[1]
+				return decimalResultType(args, digits+s, s)
+			},
+			PGFormatFunc: func(inputs []string) (string, error) {
+				// Postgres picks the scale of a quotient itself, so it is
+				// rounded to the scale of the result type
+				return fmt.Sprintf("round(%s / %s, greatest(scale(%s), scale(%s)))", inputs[0], inputs[1], inputs[0], inputs[1]), nil
+			},
+		},
+		"decimal_round": &ScalarFunctionDefinition{
+			ValidateArgsFunc: func(args []*types.DataType) (*types.DataType, error) {
+				// the value, and the number of decimal places to round it to
+				if len(args) != 2 {
+					return nil, wrapErrArgumentNumber(2, len(args))
+				}
+
+				if args[0].Name != types.NumericStr || args[0].IsArray {
+					return nil, fmt.Errorf("%w: expected argument to be numeric, got %s", ErrType, args[0].String())
+				}
+
+				if !args[1].Equals(types.IntType) {
+					return nil, wrapErrArgumentType(types.IntType, args[1])
+				}
+
+				// the result keeps the scale of the value, but rounding can
+				// carry into another digit
+				return decimalResultType(args[:1], args[0].Metadata[0]+1, args[0].Metadata[1])
+			},
+			PGFormatFunc: func(inputs []string) (string, error) {
+				return fmt.Sprintf("round(%s, %s::int4)", inputs[0], inputs[1]), nil
+			},
+		},
+		// now returns the timestamp of the block, which is the same on every
+		// node. It is not the time of Postgres.
+		"now": &ScalarFunctionDefinition{
+			ValidateArgsFunc: func(args []*types.DataType) (*types.DataType, error) {
+				if len(args) != 0 {
+					return nil, wrapErrArgumentNumber(0, len(args))
+				}
+
+				return types.TimestampTZType, nil
+			},
+			PGFormatFunc: func(inputs []string) (string, error) {
+				return BlockTimestampSQL, nil
+			},
+		},
+		// action_elapsed_ms returns the milliseconds since the call was
+		// started. Kwil does not have a float type, so it is returned as a
+		// decimal with nanosecond precision.
+		"action_elapsed_ms": &ScalarFunctionDefinition{
+			ValidateArgsFunc: func(args []*types.DataType) (*types.DataType, error) {
+				if len(args) != 0 {
+					return nil, wrapErrArgumentNumber(0, len(args))
+				}
+
+				return decimal16_6, nil
+			},
+			PGFormatFunc: func(inputs []string) (string, error) {
+				// it is implemented by the engine, not by Postgres
+				return "", fmt.Errorf(`%w: "action_elapsed_ms" cannot be used in SQL statements`, ErrIllegalFunctionUsage)
+			},
+		},
+		// the timestamp functions compute in UTC, so that their results do
+		// not depend on the time zone of the node
+		"date_trunc": &ScalarFunctionDefinition{
+			ValidateArgsFunc: func(args []*types.DataType) (*types.DataType, error) {
+				// the unit, e.g. 'day', and the timestamp
+				if len(args) != 2 {
+					return nil, wrapErrArgumentNumber(2, len(args))
+				}
+
+				if !args[0].Equals(types.TextType) {
+					return nil, wrapErrArgumentType(types.TextType, args[0])
+				}
+
+				if !args[1].Equals(types.TimestampTZType) {
+					return nil, wrapErrArgumentType(types.TimestampTZType, args[1])
+				}
+
+				return types.TimestampTZType, nil
+			},
+			PGFormatFunc: func(inputs []string) (string, error) {
+				return fmt.Sprintf("date_trunc(%s, %s, 'UTC')", inputs[0], inputs[1]), nil
+			},
+		},
+		"age": &ScalarFunctionDefinition{
+			ValidateArgsFunc: func(args []*types.DataType) (*types.DataType, error) {
+				if len(args) != 2 {
+					return nil, wrapErrArgumentNumber(2, len(args))
+				}
+
+				for _, arg := range args {
+					if !arg.Equals(types.TimestampTZType) {
+						return nil, wrapErrArgumentType(types.TimestampTZType, arg)
+					}
+				}
+
+				// Kwil does not have an interval type, so the interval is
+				// returned as text, e.g. '1 year 2 mons 3 days'
+				return types.TextType, nil
+			},
+			PGFormatFunc: func(inputs []string) (string, error) {
+				return fmt.Sprintf("age(%s AT TIME ZONE 'UTC', %s AT TIME ZONE 'UTC')::text", inputs[0], inputs[1]), nil
+			},
+		},
+		"extract": &ScalarFunctionDefinition{
+			ValidateArgsFunc: func(args []*types.DataType) (*types.DataType, error) {
+				// the field, e.g. 'hour', and the timestamp
+				if len(args) != 2 {
+					return nil, wrapErrArgumentNumber(2, len(args))
+				}
+
+				if !args[0].Equals(types.TextType) {
+					return nil, wrapErrArgumentType(types.TextType, args[0])
+				}
+
+				if !args[1].Equals(types.TimestampTZType) {
+					return nil, wrapErrArgumentType(types.TimestampTZType, args[1])
+				}
+
+				// Kwil does not have a float type, so the field is returned
+				// as a decimal with microsecond precision, like a UNIX
+				// timestamp
+				return decimal16_6, nil
+			},
+			PGFormatFunc: func(inputs []string) (string, error) {
+				return fmt.Sprintf("date_part(%s, %s AT TIME ZONE 'UTC')::numeric(16,6)", inputs[0], inputs[1]), nil
+			},
+		},
+		"make_interval": &ScalarFunctionDefinition{
+			ValidateArgsFunc: func(args []*types.DataType) (*types.DataType, error) {
+				// days, hours, minutes and seconds
+				if len(args) != 4 {
+					return nil, wrapErrArgumentNumber(4, len(args))
+				}
+
+				for _, arg := range args {
+					if !arg.Equals(types.IntType) {
+						return nil, wrapErrArgumentType(types.IntType, arg)
+					}
+				}
+
+				return types.IntervalType, nil
+			},
+			PGFormatFunc: func(inputs []string) (string, error) {
+				// Postgres does not implicitly cast int8 to the types of the
+				// parameters of make_interval
+				return fmt.Sprintf("make_interval(days => (%s)::int4, hours => (%s)::int4, mins => (%s)::int4, secs => (%s)::float8)",
+					inputs[0], inputs[1], inputs[2], inputs[3]), nil
+			},
+		},
+		"interval_add": &ScalarFunctionDefinition{
+			ValidateArgsFunc: func(args []*types.DataType) (*types.DataType, error) {
+				if len(args) != 2 {
+					return nil, wrapErrArgumentNumber(2, len(args))
+				}
+
+				for _, arg := range args {
+					if !arg.Equals(types.IntervalType) {
+						return nil, wrapErrArgumentType(types.IntervalType, arg)
+					}
+				}
+
+				return types.IntervalType, nil
+			},
+			PGFormatFunc: func(inputs []string) (string, error) {
+				return fmt.Sprintf("(%s + %s)", inputs[0], inputs[1]), nil
+			},
+		},
+		// the network functions also take text, which they cast, so that
+		// literals can be passed to them, e.g. inet_contains('10.0.0.0/8', $ip)
+		"inet_contains": &ScalarFunctionDefinition{
+			ValidateArgsFunc: func(args []*types.DataType) (*types.DataType, error) {
+				// the network and the address
+				if len(args) != 2 {
+					return nil, wrapErrArgumentNumber(2, len(args))
+				}
+
+				if !args[0].Equals(types.CIDRType) && !args[0].Equals(types.TextType) {
+					return nil, wrapErrArgumentType(types.CIDRType, args[0])
+				}
+
+				if !args[1].Equals(types.InetType) && !args[1].Equals(types.TextType) {
+					return nil, wrapErrArgumentType(types.InetType, args[1])
+				}
+
+				return types.BoolType, nil
+			},
+			PGFormatFunc: func(inputs []string) (string, error) {
+				return fmt.Sprintf("((%s)::cidr >> (%s)::inet)", inputs[0], inputs[1]), nil
+			},
+		},
+		"host": &ScalarFunctionDefinition{
+			ValidateArgsFunc: func(args []*types.DataType) (*types.DataType, error) {
+				if len(args) != 1 {
+					return nil, wrapErrArgumentNumber(1, len(args))
+				}
+
+				if !args[0].Equals(types.InetType) && !args[0].Equals(types.TextType) {
+					return nil, wrapErrArgumentType(types.InetType, args[0])
+				}
+
+				return types.TextType, nil
+			},
+			PGFormatFunc: func(inputs []string) (string, error) {
+				return fmt.Sprintf("host((%s)::inet)", inputs[0]), nil
+			},
+		},
 		"encode": &ScalarFunctionDefinition{
 			ValidateArgsFunc: func(args []*types.DataType) (*types.DataType, error) {
 				// first must be blob, second must be text
@@ -654,63 +1060,43 @@ var (
 		},
 		"coalesce": &ScalarFunctionDefinition{
 			ValidateArgsFunc: func(args []*types.DataType) (*types.DataType, error) {
-				if len(args) < 1 {
-					return nil, fmt.Errorf("invalid number of arguments: expected at least 1, got %d", len(args))
-				}
-
-				firstType := args[0]
-				// all arguments must be the same type
-				for i, arg := range args {
-					if !firstType.Equals(arg) {
-						return nil, fmt.Errorf("%w: all arguments must be the same type, but argument %d is %s and argument 1 is %s", ErrType, i+1, arg.String(), firstType.String())
-					}
-				}
-
-				return firstType, nil
+				return args[0], nil
 			},
 			PGFormatFunc: defaultFormat("coalesce"),
+			Variadic:     true,
 		},
-		"greatest": &ScalarFunctionDefinition{
+		"concat": &ScalarFunctionDefinition{
 			ValidateArgsFunc: func(args []*types.DataType) (*types.DataType, error) {
-				if len(args) == 0 {
-					return nil, fmt.Errorf("invalid number of arguments: expected at least 1, got 0")
-				}
-
-				if !args[0].IsNumeric() || !args[1].IsNumeric() {
-					return nil, fmt.Errorf("%w: expected both arguments to be numeric, got %s and %s", ErrType, args[0].String(), args[1].String())
+				if !args[0].Equals(types.TextType) {
+					return nil, wrapErrArgumentType(types.TextType, args[0])
 				}
 
-				// all arguments must be the same type
-				for i, arg := range args {
-					if !args[0].Equals(arg) {
-						return nil, fmt.Errorf("%w: all arguments must be the same type, but argument %d is %s and argument 1 is %s", ErrType, i+1, arg.String(), args[0].String())
-					}
+				return types.TextType, nil
+			},
+			PGFormatFunc: defaultFormat("concat"),
+			Variadic:     true,
+		},
+		"greatest": &ScalarFunctionDefinition{
+			ValidateArgsFunc: func(args []*types.DataType) (*types.DataType, error) {
+				if !args[0].IsNumeric() {
+					return nil, fmt.Errorf("%w: expected numeric arguments, got %s", ErrType, args[0].String())
 				}
 
 				return args[0], nil
 			},
 			PGFormatFunc: defaultFormat("greatest"),
+			Variadic:     true,
 		},
 		"least": &ScalarFunctionDefinition{
 			ValidateArgsFunc: func(args []*types.DataType) (*types.DataType, error) {
-				if len(args) == 0 {
-					return nil, fmt.Errorf("invalid number of arguments: expected at least 1, got 0")
-				}
-
-				if !args[0].IsNumeric() || !args[1].IsNumeric() {
-					return nil, fmt.Errorf("%w: expected both arguments to be numeric, got %s and %s", ErrType, args[0].String(), args[1].String())
-				}
-
-				// all arguments must be the same type
-				for i, arg := range args {
-					if !args[0].Equals(arg) {
-						return nil, fmt.Errorf("%w: all arguments must be the same type, but argument %d is %s and argument 1 is %s", ErrType, i+1, arg.String(), args[0].String())
-					}
+				if !args[0].IsNumeric() {
+					return nil, fmt.Errorf("%w: expected numeric arguments, got %s", ErrType, args[0].String())
 				}
 
 				return args[0], nil
 			},
 			PGFormatFunc: defaultFormat("least"),
+			Variadic:     true,
 		},
 		"nullif": &ScalarFunctionDefinition{
 			ValidateArgsFunc: func(args []*types.DataType) (*types.DataType, error) {
@@ -975,6 +1361,72 @@ var (
 	}
 )
 
+var (
+	// TableFunctions are the built-in functions that return a table. They can
+	// only be used in the FROM clause of a query.
+	TableFunctions = map[string]*TableFunctionDefinition{
+		"generate_series": {
+			ValidateArgsFunc: func(args []*types.DataType) (*types.DataType, error) {
+				// generate_series(start, stop, step)
+				if len(args) != 3 {
+					return nil, wrapErrArgumentNumber(3, len(args))
+				}
+
+				for _, arg := range args {
+					if !arg.Equals(types.IntType) {
+						return nil, wrapErrArgumentType(types.IntType, arg)
+					}
+				}
+
+				return types.CompositeDataType([]types.FieldDef{{Name: "generate_series", Type: types.IntType}}), nil
+			},
+			PGFormatFunc: func(inputs []string) (string, error) {
+				// integer literals are int4 in Postgres, so the inputs are cast to
+				// make it return int8 values.
+				return fmt.Sprintf("SELECT * FROM generate_series(%s::INT8, %s::INT8, %s::INT8)", inputs[0], inputs[1], inputs[2]), nil
+			},
+		},
+		"project_events": {
+			ValidateArgsFunc: func(args []*types.DataType) (*types.DataType, error) {
+				// project_events(namespace, table, as_of_block)
+				if len(args) != 3 {
+					return nil, wrapErrArgumentNumber(3, len(args))
+				}
+
+				for i, want := range []*types.DataType{types.TextType, types.TextType, types.IntType} {
+					if !args[i].Equals(want) {
+						return nil, wrapErrArgumentType(want, args[i])
+					}
+				}
+
+				// the columns of the projected table are not known when the
+				// query is planned, so the rows are returned as JSON objects.
+				return types.CompositeDataType([]types.FieldDef{
+					{Name: "row_key", Type: types.TextType},
+					{Name: "row_data", Type: types.TextType},
+				}), nil
+			},
+			PGFormatFunc: func(inputs []string) (string, error) {
+				return fmt.Sprintf("SELECT * FROM kwild_engine.project_events(%s::TEXT, %s::TEXT, %s::INT8)", inputs[0], inputs[1], inputs[2]), nil
+			},
+		},
+	}
+)
+
+// softDeleteArgs validates the arguments of soft_delete_row and restore_row,
+// which take a table name and the value of the row's primary key.
+func softDeleteArgs(args []*types.DataType) (*types.DataType, error) {
+	if len(args) != 2 {
+		return nil, wrapErrArgumentNumber(2, len(args))
+	}
+
+	if !args[0].Equals(types.TextType) {
+		return nil, wrapErrArgumentType(types.TextType, args[0])
+	}
+
+	return types.NullType, nil
+}
+
 // defaultFormat is the default PGFormat function for functions that do not have a custom one.
 func defaultFormat(name string) func(inputs []string) (string, error) {
 	return func(inputs []string) (string, error) {
@@ -1018,10 +1470,50 @@ type FunctionDefinition interface {
 type ScalarFunctionDefinition struct {
 	ValidateArgsFunc func(args []*types.DataType) (*types.DataType, error)
 	PGFormatFunc     func(inputs []string) (string, error)
+	// Variadic is true if the function takes one or more arguments of the
+	// same type, e.g. coalesce. ValidateArgs checks that they have the same
+	// type, and passes ValidateArgsFunc a single argument of that type, so
+	// that it checks it like the parameter of a function with one argument.
+	// PGFormatFunc is given an input for each argument.
+	Variadic bool
 }
 
 func (s *ScalarFunctionDefinition) ValidateArgs(args []*types.DataType) (*types.DataType, error) {
-	return s.ValidateArgsFunc(args)
+	if !s.Variadic {
+		return s.ValidateArgsFunc(args)
+	}
+
+	typ, err := variadicType(args)
+	if err != nil {
+		return nil, err
+	}
+	return s.ValidateArgsFunc([]*types.DataType{typ})
+}
+
+// variadicType returns the type of the arguments of a variadic function. It
+// is the type of the first argument that is not null, so that nulls can be
+// given with arguments of any type.
+func variadicType(args []*types.DataType) (*types.DataType, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("invalid number of arguments: expected at least 1, got 0")
+	}
+
+	typ := args[0]
+	for _, arg := range args {
+		if !arg.EqualsStrict(types.NullType) {
+			typ = arg
+			break
+		}
+	}
+
+	// all arguments must be the same type
+	for i, arg := range args {
+		if !typ.Equals(arg) {
+			return nil, fmt.Errorf("%w: all arguments must be the same type, but argument %d is %s and the others are %s", ErrType, i+1, arg.String(), typ.String())
+		}
+	}
+
+	return typ, nil
 }
 
 func (s *ScalarFunctionDefinition) funcdef() {}
@@ -1060,9 +1552,78 @@ func (w *WindowFunctionDefinition) ValidateArgs(args []*types.DataType) (*types.
 
 func (w *WindowFunctionDefinition) funcdef() {}
 
+// TableFunctionDefinition is a definition of a function that returns a table.
+// Unlike the other function definitions, it is not a FunctionDefinition, since
+// it cannot be used as an expression.
+type TableFunctionDefinition struct {
+	// ValidateArgsFunc checks the arguments passed to the function, and returns
+	// the type of the rows it returns. It must be a composite type, whose
+	// fields are the columns of the table.
+	ValidateArgsFunc func(args []*types.DataType) (*types.DataType, error)
+	// PGFormatFunc formats the inputs to the function as a Postgres query
+	// that selects from it. For example, generate_series would format the
+	// inputs as `SELECT * FROM generate_series($1, $2, $3)`.
+	PGFormatFunc func(inputs []string) (string, error)
+}
+
+// ValidateArgs validates the arguments of the function, returning the
+// composite type of the rows it returns.
+func (t *TableFunctionDefinition) ValidateArgs(args []*types.DataType) (*types.DataType, error) {
+	ret, err := t.ValidateArgsFunc(args)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ret.IsComposite() || len(ret.CompositeFields()) == 0 {
+		return nil, fmt.Errorf("table function must return a composite type with at least one field, got %s", ret)
+	}
+
+	return ret, nil
+}
+
 // FormatFunc is a function that formats a string of inputs for a SQL function.
 type FormatFunc func(inputs []string) (string, error)
 
+// BlockTimestampSQL is the SQL that now() is formatted as. It reads the
+// timestamp of the block from a setting, which the interpreter sets for the
+// statements that use it.
+const BlockTimestampSQL = "to_timestamp(current_setting('kwild.block_timestamp')::int8)"
+
+// checkDecimalArgs checks that the arguments of a decimal function are two
+// NUMERIC values.
+func checkDecimalArgs(args []*types.DataType) error {
+	if len(args) != 2 {
+		return wrapErrArgumentNumber(2, len(args))
+	}
+
+	for _, arg := range args {
+		if arg.Name != types.NumericStr || arg.IsArray {
+			return fmt.Errorf("%w: expected argument to be numeric, got %s", ErrType, arg.String())
+		}
+	}
+
+	return nil
+}
+
+// decimalResultType returns the NUMERIC type of the result of a decimal
+// function. Its precision is capped at the maximum precision. If an argument
+// has no precision, neither does the result.
+func decimalResultType(args []*types.DataType, precision, scale uint16) (*types.DataType, error) {
+	for _, arg := range args {
+		if arg.Metadata[0] == 0 {
+			return types.NumericType.Copy(), nil
+		}
+	}
+
+	precision = min(precision, decimal1000.Metadata[0])
+	dt, err := types.NewNumericType(precision, scale)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrType, err)
+	}
+
+	return dt, nil
+}
+
 func wrapErrArgumentNumber(expected, got int) error {
 	return fmt.Errorf("expected %d, got %d", expected, got)
 }
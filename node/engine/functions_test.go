@@ -3,7 +3,9 @@ package engine_test
 import (
 	"testing"
 
+	"github.com/kwilteam/kwil-db/core/types"
 	"github.com/kwilteam/kwil-db/node/engine"
+	"github.com/stretchr/testify/require"
 )
 
 // tests that we have implemented all functions
@@ -39,3 +41,185 @@ func Test_AllFunctionsImplemented(t *testing.T) {
 		}
 	}
 }
+
+func Test_DecimalFunctionTypes(t *testing.T) {
+	numeric := func(precision, scale uint16) *types.DataType {
+		dt, err := types.NewNumericType(precision, scale)
+		require.NoError(t, err)
+		return dt
+	}
+
+	tests := []struct {
+		name string
+		fn   string
+		args []*types.DataType
+		want *types.DataType
+		err  bool
+	}{
+		{name: "add", fn: "decimal_add", args: []*types.DataType{numeric(1, 1), numeric(1, 1)}, want: numeric(2, 1)},
+		{name: "add different scales", fn: "decimal_add", args: []*types.DataType{numeric(10, 2), numeric(5, 4)}, want: numeric(13, 4)},
+		{name: "mul", fn: "decimal_mul", args: []*types.DataType{numeric(10, 2), numeric(5, 4)}, want: numeric(15, 6)},
+		{name: "mul capped precision", fn: "decimal_mul", args: []*types.DataType{numeric(900, 0), numeric(900, 0)}, want: numeric(1000, 0)},
+		{name: "div", fn: "decimal_div", args: []*types.DataType{numeric(10, 2), numeric(5, 4)}, want: numeric(16, 4)},
+		{name: "round", fn: "decimal_round", args: []*types.DataType{numeric(10, 2), types.IntType}, want: numeric(11, 2)},
+		{name: "unconstrained", fn: "decimal_add", args: []*types.DataType{types.NumericType, numeric(10, 2)}, want: types.NumericType},
+		{name: "int argument", fn: "decimal_add", args: []*types.DataType{types.IntType, numeric(10, 2)}, err: true},
+		{name: "array argument", fn: "decimal_mul", args: []*types.DataType{numeric(10, 2), types.NumericArrayType}, err: true},
+		{name: "one argument", fn: "decimal_div", args: []*types.DataType{numeric(10, 2)}, err: true},
+		{name: "round numeric scale", fn: "decimal_round", args: []*types.DataType{numeric(10, 2), numeric(1, 0)}, err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := engine.Functions[tt.fn].ValidateArgs(tt.args)
+			if tt.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, got.EqualsStrict(tt.want), "got %s, want %s", got, tt.want)
+		})
+	}
+}
+
+func Test_TimeFunctionTypes(t *testing.T) {
+	decimal16_6, err := types.NewNumericType(16, 6)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		fn   string
+		args []*types.DataType
+		want *types.DataType
+		err  bool
+	}{
+		{name: "now", fn: "now", want: types.TimestampTZType},
+		{name: "date_trunc", fn: "date_trunc", args: []*types.DataType{types.TextType, types.TimestampTZType}, want: types.TimestampTZType},
+		{name: "age", fn: "age", args: []*types.DataType{types.TimestampTZType, types.TimestampTZType}, want: types.TextType},
+		{name: "extract", fn: "extract", args: []*types.DataType{types.TextType, types.TimestampTZType}, want: decimal16_6},
+		{name: "now with argument", fn: "now", args: []*types.DataType{types.TextType}, err: true},
+		{name: "date_trunc of text", fn: "date_trunc", args: []*types.DataType{types.TextType, types.TextType}, err: true},
+		{name: "age of int", fn: "age", args: []*types.DataType{types.TimestampTZType, types.IntType}, err: true},
+		{name: "extract of array", fn: "extract", args: []*types.DataType{types.TextType, types.TimestampTZArrayType}, err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := engine.Functions[tt.fn].ValidateArgs(tt.args)
+			if tt.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, got.EqualsStrict(tt.want), "got %s, want %s", got, tt.want)
+		})
+	}
+}
+
+func Test_IntervalFunctions(t *testing.T) {
+	got, err := engine.Functions["make_interval"].ValidateArgs([]*types.DataType{types.IntType, types.IntType, types.IntType, types.IntType})
+	require.NoError(t, err)
+	require.True(t, got.EqualsStrict(types.IntervalType))
+
+	_, err = engine.Functions["make_interval"].ValidateArgs([]*types.DataType{types.IntType, types.IntType, types.IntType, types.TextType})
+	require.Error(t, err)
+
+	got, err = engine.Functions["interval_add"].ValidateArgs([]*types.DataType{types.IntervalType, types.IntervalType})
+	require.NoError(t, err)
+	require.True(t, got.EqualsStrict(types.IntervalType))
+
+	_, err = engine.Functions["interval_add"].ValidateArgs([]*types.DataType{types.IntervalType, types.IntType})
+	require.Error(t, err)
+
+	sql, err := engine.Functions["make_interval"].(*engine.ScalarFunctionDefinition).PGFormatFunc([]string{"$1", "$2", "$3", "$4"})
+	require.NoError(t, err)
+	require.Equal(t, "make_interval(days => ($1)::int4, hours => ($2)::int4, mins => ($3)::int4, secs => ($4)::float8)", sql)
+}
+
+func Test_NetworkFunctions(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   string
+		args []*types.DataType
+		want *types.DataType
+		err  bool
+	}{
+		{name: "inet_contains", fn: "inet_contains", args: []*types.DataType{types.CIDRType, types.InetType}, want: types.BoolType},
+		{name: "inet_contains text", fn: "inet_contains", args: []*types.DataType{types.TextType, types.TextType}, want: types.BoolType},
+		{name: "host", fn: "host", args: []*types.DataType{types.InetType}, want: types.TextType},
+		{name: "inet_contains int", fn: "inet_contains", args: []*types.DataType{types.CIDRType, types.IntType}, err: true},
+		{name: "inet_contains reversed", fn: "inet_contains", args: []*types.DataType{types.InetType, types.CIDRType}, err: true},
+		{name: "host of cidr", fn: "host", args: []*types.DataType{types.CIDRType}, err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := engine.Functions[tt.fn].ValidateArgs(tt.args)
+			if tt.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, got.EqualsStrict(tt.want), "got %s, want %s", got, tt.want)
+		})
+	}
+}
+
+func Test_TableFunctionReturnsComposite(t *testing.T) {
+	users := types.CompositeDataType([]types.FieldDef{{Name: "id", Type: types.IntType}, {Name: "name", Type: types.TextType}})
+
+	fn := &engine.TableFunctionDefinition{
+		ValidateArgsFunc: func(args []*types.DataType) (*types.DataType, error) {
+			return users, nil
+		},
+	}
+	got, err := fn.ValidateArgs(nil)
+	require.NoError(t, err)
+	require.Equal(t, []types.FieldDef{{Name: "id", Type: types.IntType}, {Name: "name", Type: types.TextType}}, got.CompositeFields())
+
+	// table functions must return composite types with fields
+	for _, ret := range []*types.DataType{types.IntType, types.CompositeDataType(nil)} {
+		fn.ValidateArgsFunc = func(args []*types.DataType) (*types.DataType, error) {
+			return ret, nil
+		}
+		_, err = fn.ValidateArgs(nil)
+		require.Error(t, err)
+	}
+}
+
+func Test_VariadicFunctions(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   string
+		args []*types.DataType
+		want *types.DataType
+		err  bool
+	}{
+		{name: "concat", fn: "concat", args: []*types.DataType{types.TextType, types.TextType, types.TextType}, want: types.TextType},
+		{name: "concat one argument", fn: "concat", args: []*types.DataType{types.TextType}, want: types.TextType},
+		{name: "coalesce nulls first", fn: "coalesce", args: []*types.DataType{types.NullType, types.NullType, types.TextType}, want: types.TextType},
+		{name: "coalesce nulls", fn: "coalesce", args: []*types.DataType{types.NullType, types.NullType}, want: types.NullType},
+		{name: "greatest", fn: "greatest", args: []*types.DataType{types.IntType, types.IntType, types.NullType, types.IntType}, want: types.IntType},
+		{name: "least one argument", fn: "least", args: []*types.DataType{types.IntType}, want: types.IntType},
+		{name: "concat no arguments", fn: "concat", args: nil, err: true},
+		{name: "concat int", fn: "concat", args: []*types.DataType{types.TextType, types.IntType}, err: true},
+		{name: "coalesce different types", fn: "coalesce", args: []*types.DataType{types.NullType, types.IntType, types.TextType}, err: true},
+		{name: "greatest text", fn: "greatest", args: []*types.DataType{types.TextType, types.TextType}, err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := engine.Functions[tt.fn].ValidateArgs(tt.args)
+			if tt.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, got.EqualsStrict(tt.want), "got %s, want %s", got, tt.want)
+		})
+	}
+
+	sql, err := engine.Functions["concat"].(*engine.ScalarFunctionDefinition).PGFormatFunc([]string{"$1", "$2", "$3"})
+	require.NoError(t, err)
+	require.Equal(t, "concat($1, $2, $3)", sql)
+}
@@ -0,0 +1,264 @@
+package interpreter
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/kwilteam/kwil-db/node/engine"
+	"github.com/kwilteam/kwil-db/node/types/sql"
+)
+
+// ABVariant is a variant of an action that is A/B tested.
+type ABVariant string
+
+const (
+	ABVariantA ABVariant = "A"
+	ABVariantB ABVariant = "B"
+)
+
+// ABTestStats are the results of the A/B test of an action.
+type ABTestStats struct {
+	Namespace string
+	Action    string
+	// SplitPercent is the percentage of callers that are assigned variant A.
+	SplitPercent float64
+	A            ABVariantStats
+	B            ABVariantStats
+}
+
+// ABVariantStats are the results of the calls to a variant of an action.
+type ABVariantStats struct {
+	// Calls is the number of calls to the variant, and Errors the number of
+	// them that failed.
+	Calls  int64
+	Errors int64
+	// ErrorRate is Errors / Calls. It is zero if there have been no calls.
+	ErrorRate float64
+	// AvgLatency and P95Latency are the average and 95th percentile durations
+	// of the recent calls. They are zero if there have been no calls.
+	AvgLatency time.Duration
+	P95Latency time.Duration
+}
+
+// abTests are the actions that are A/B tested. Calls record their results
+// concurrently, so they are guarded by their own mutex.
+type abTests struct {
+	mu    sync.Mutex
+	tests map[baselineKey]*abTest
+}
+
+// abTest is an action whose calls are split between two variants.
+type abTest struct {
+	splitPercent float64
+	a, b         *abVariant
+}
+
+// abVariant is a variant of an A/B tested action and the results of its calls.
+type abVariant struct {
+	exec *executable
+	// durations are the most recent call durations, used as a ring buffer.
+	durations []time.Duration
+	// next is the position of durations that the next duration is written to.
+	next   int
+	calls  int64
+	errors int64
+}
+
+func newABTests() *abTests {
+	return &abTests{
+		tests: make(map[baselineKey]*abTest),
+	}
+}
+
+// record records the duration of a call to the variant, and whether it
+// failed. It must be called with the mutex of the tests held.
+func (v *abVariant) record(d time.Duration, failed bool) {
+	if len(v.durations) < baselineSamples {
+		v.durations = append(v.durations, d)
+	} else {
+		v.durations[v.next] = d
+	}
+	v.next = (v.next + 1) % baselineSamples
+	v.calls++
+	if failed {
+		v.errors++
+	}
+}
+
+// stats returns the results of the calls to the variant.
+func (v *abVariant) stats() ABVariantStats {
+	stats := ABVariantStats{
+		Calls:      v.calls,
+		Errors:     v.errors,
+		P95Latency: p95(v.durations),
+	}
+	if v.calls > 0 {
+		stats.ErrorRate = float64(v.errors) / float64(v.calls)
+	}
+	if len(v.durations) > 0 {
+		var total time.Duration
+		for _, d := range v.durations {
+			total += d
+		}
+		stats.AvgLatency = total / time.Duration(len(v.durations))
+	}
+	return stats
+}
+
+// DeployABTest splits the calls to an action between two variants. Callers
+// are assigned variant A if the hash of the caller, the action and the block
+// height of their first call falls below splitPercent, and variant B
+// otherwise. The assignment is stored in kwild_engine.ab_assignments, so that
+// a caller keeps calling the same variant. Deploying a test for an action that
+// already has one replaces it, and resets its assignments and results.
+//
+// Only the top level calls to the action are split; calls made from other
+// actions are not.
+func (t *ThreadSafeInterpreter) DeployABTest(ctx context.Context, db sql.DB, namespace, action string, variantA, variantB *executable, splitPercent float64) error {
+	if variantA == nil || variantB == nil {
+		return errors.New("both variants must be provided")
+	}
+	for _, v := range []*executable{variantA, variantB} {
+		if v.Type != executableTypeAction && v.Type != executableTypePrecompile {
+			return fmt.Errorf(`variant "%s" is not an action`, v.Name)
+		}
+	}
+	if math.IsNaN(splitPercent) || splitPercent < 0 || splitPercent > 100 {
+		return fmt.Errorf("split percent must be between 0 and 100, got %v", splitPercent)
+	}
+
+	key := newBaselineKey(namespace, action)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	ns, ok := t.i.namespaces[key.namespace]
+	if !ok {
+		return fmt.Errorf(`%w: "%s"`, engine.ErrNamespaceNotFound, key.namespace)
+	}
+	if _, ok = ns.availableFunctions[key.action]; !ok {
+		return &engine.ActionNotFoundError{Namespace: key.namespace, Action: key.action}
+	}
+
+	err := execute(ctx, db, `DELETE FROM kwild_engine.ab_assignments WHERE namespace = $1 AND action_name = $2`,
+		key.namespace, key.action)
+	if err != nil {
+		return err
+	}
+
+	t.i.abTests.mu.Lock()
+	defer t.i.abTests.mu.Unlock()
+
+	t.i.abTests.tests[key] = &abTest{
+		splitPercent: splitPercent,
+		a:            &abVariant{exec: variantA},
+		b:            &abVariant{exec: variantB},
+	}
+	return nil
+}
+
+// GetABTestResults returns the results of the A/B test of an action.
+func (t *ThreadSafeInterpreter) GetABTestResults(namespace, action string) (*ABTestStats, error) {
+	key := newBaselineKey(namespace, action)
+
+	t.i.abTests.mu.Lock()
+	defer t.i.abTests.mu.Unlock()
+
+	test, ok := t.i.abTests.tests[key]
+	if !ok {
+		return nil, fmt.Errorf(`action "%s.%s" is not A/B tested`, key.namespace, key.action)
+	}
+
+	return &ABTestStats{
+		Namespace:    key.namespace,
+		Action:       key.action,
+		SplitPercent: test.splitPercent,
+		A:            test.a.stats(),
+		B:            test.b.stats(),
+	}, nil
+}
+
+// route returns the variant of an A/B tested action that the caller of the
+// execution context is assigned. It returns nil if the action is not A/B
+// tested.
+func (a *abTests) route(exec *executionContext, namespace, action string) (*abVariant, error) {
+	a.mu.Lock()
+	test, ok := a.tests[newBaselineKey(namespace, action)]
+	a.mu.Unlock()
+	if !ok {
+		return nil, nil
+	}
+
+	variant, err := exec.abAssignment(namespace, action, test.splitPercent)
+	if err != nil {
+		return nil, err
+	}
+
+	if variant == ABVariantA {
+		return test.a, nil
+	}
+	return test.b, nil
+}
+
+// record records the result of a call to a variant.
+func (a *abTests) record(v *abVariant, d time.Duration, failed bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	v.record(d, failed)
+}
+
+// abAssignment returns the variant of an A/B tested action that the caller is
+// assigned, assigning one if it has none. The assignment is only stored if
+// the execution context can mutate state; read-only calls use the variant
+// that the caller would be assigned.
+func (e *executionContext) abAssignment(namespace, action string, splitPercent float64) (ABVariant, error) {
+	ctx := e.engineCtx.TxContext.Ctx
+	caller := e.engineCtx.TxContext.Caller
+
+	var stored string
+	err := queryRowFunc(ctx, e.db, `SELECT variant::TEXT FROM kwild_engine.ab_assignments
+	WHERE namespace = $1 AND action_name = $2 AND caller = $3`, []any{&stored}, func() error { return nil },
+		namespace, action, caller)
+	if err != nil {
+		return "", err
+	}
+	if stored != "" {
+		return ABVariant(stored), nil
+	}
+
+	var height int64
+	if e.engineCtx.TxContext.BlockContext != nil {
+		height = e.engineCtx.TxContext.BlockContext.Height
+	}
+	variant := chooseABVariant(caller, action, height, splitPercent)
+
+	if !e.canMutateState {
+		return variant, nil
+	}
+
+	err = execute(ctx, e.db, `INSERT INTO kwild_engine.ab_assignments (namespace, action_name, caller, variant)
+	VALUES ($1, $2, $3, $4) ON CONFLICT DO NOTHING`, namespace, action, caller, string(variant))
+	if err != nil {
+		return "", err
+	}
+	return variant, nil
+}
+
+// chooseABVariant deterministically chooses the variant of an action for a
+// caller, by hashing the caller, the action and the block height, so that
+// every node makes the same choice.
+func chooseABVariant(caller, action string, height int64, splitPercent float64) ABVariant {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s%s%d", caller, action, height)))
+	fraction := float64(binary.BigEndian.Uint64(sum[:8])) / float64(math.MaxUint64)
+	if fraction*100 < splitPercent {
+		return ABVariantA
+	}
+	return ABVariantB
+}
@@ -0,0 +1,78 @@
+package interpreter
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ChooseABVariant(t *testing.T) {
+	// 1000 callers are split roughly in half
+	var a int
+	for i := range 1000 {
+		if chooseABVariant(fmt.Sprintf("caller%d", i), "transfer", 10, 50) == ABVariantA {
+			a++
+		}
+	}
+	require.InDelta(t, 500, a, 50)
+
+	// the choice is deterministic
+	require.Equal(t, chooseABVariant("alice", "transfer", 10, 50), chooseABVariant("alice", "transfer", 10, 50))
+
+	for i := range 100 {
+		caller := fmt.Sprintf("caller%d", i)
+		require.Equal(t, ABVariantB, chooseABVariant(caller, "transfer", 10, 0))
+		require.Equal(t, ABVariantA, chooseABVariant(caller, "transfer", 10, 100))
+	}
+}
+
+func Test_ABTestResults(t *testing.T) {
+	interp := &ThreadSafeInterpreter{
+		i: &baseInterpreter{abTests: newABTests()},
+	}
+	ctx := context.Background()
+
+	variantA := &executable{Name: "transfer_a", Type: executableTypeAction}
+	variantB := &executable{Name: "transfer_b", Type: executableTypeAction}
+
+	require.Error(t, interp.DeployABTest(ctx, nil, "main", "transfer", variantA, nil, 50))
+	require.Error(t, interp.DeployABTest(ctx, nil, "main", "transfer", variantA, variantB, 101))
+	require.Error(t, interp.DeployABTest(ctx, nil, "main", "transfer", variantA,
+		&executable{Name: "abs", Type: executableTypeFunction}, 50))
+
+	_, err := interp.GetABTestResults("main", "transfer")
+	require.Error(t, err)
+
+	test := &abTest{
+		splitPercent: 50,
+		a:            &abVariant{exec: variantA},
+		b:            &abVariant{exec: variantB},
+	}
+	interp.i.abTests.tests[newBaselineKey("", "Transfer")] = test
+
+	interp.i.abTests.record(test.a, 10*time.Millisecond, false)
+	interp.i.abTests.record(test.a, 30*time.Millisecond, true)
+	interp.i.abTests.record(test.b, 5*time.Millisecond, false)
+
+	stats, err := interp.GetABTestResults("main", "transfer")
+	require.NoError(t, err)
+	require.Equal(t, "main", stats.Namespace)
+	require.Equal(t, "transfer", stats.Action)
+	require.Equal(t, 50.0, stats.SplitPercent)
+
+	require.Equal(t, ABVariantStats{
+		Calls:      2,
+		Errors:     1,
+		ErrorRate:  0.5,
+		AvgLatency: 20 * time.Millisecond,
+		P95Latency: 30 * time.Millisecond,
+	}, stats.A)
+	require.Equal(t, ABVariantStats{
+		Calls:      1,
+		AvgLatency: 5 * time.Millisecond,
+		P95Latency: 5 * time.Millisecond,
+	}, stats.B)
+}
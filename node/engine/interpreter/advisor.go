@@ -0,0 +1,235 @@
+package interpreter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sync"
+
+	"github.com/kwilteam/kwil-db/core/log"
+	"github.com/kwilteam/kwil-db/node/engine"
+	"github.com/kwilteam/kwil-db/node/engine/parse"
+	"github.com/kwilteam/kwil-db/node/types/sql"
+)
+
+// WithoutQueryAdvisor stops the interpreter from logging suggestions for the
+// queries of calls whose plans are suboptimal.
+func WithoutQueryAdvisor() InterpreterOpt {
+	return func(o *InterpreterOptions) {
+		o.DisableQueryAdvisor = true
+	}
+}
+
+// maxAdvisedStatements is the number of statements the query advisor remembers
+// having analyzed. Once reached, it forgets them all.
+const maxAdvisedStatements = 10000
+
+// QueryAdvisor suggests improvements to the queries of calls whose plans are
+// suboptimal, such as sequential scans that could use an index, foreign key
+// columns without an index, and joins without a join condition. Each statement
+// is explained once, and each suggestion is logged as a warning once.
+type QueryAdvisor struct {
+	logger log.Logger
+
+	mu sync.Mutex
+	// analyzed are the statements whose plans were analyzed.
+	analyzed map[string]struct{}
+	// suggested are the suggestions that were logged.
+	suggested map[string]struct{}
+}
+
+// NewQueryAdvisor creates a query advisor that logs its suggestions.
+func NewQueryAdvisor(logger log.Logger) *QueryAdvisor {
+	return &QueryAdvisor{
+		logger:    logger,
+		analyzed:  make(map[string]struct{}),
+		suggested: make(map[string]struct{}),
+	}
+}
+
+// shouldAnalyze returns true if a statement has not been analyzed yet, marking
+// it as analyzed.
+func (a *QueryAdvisor) shouldAnalyze(stmt string) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if _, ok := a.analyzed[stmt]; ok {
+		return false
+	}
+	if len(a.analyzed) >= maxAdvisedStatements {
+		clear(a.analyzed)
+	}
+	a.analyzed[stmt] = struct{}{}
+	return true
+}
+
+// advise logs the suggestions for the plans of a call that were not logged
+// before.
+func (a *QueryAdvisor) advise(plans []*explainedPlan, getTable func(namespace, table string) (*engine.Table, error)) {
+	var suggestions []string
+	for _, plan := range plans {
+		suggestions = append(suggestions, suggestOptimizations(plan, getTable)...)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for _, suggestion := range suggestions {
+		if _, ok := a.suggested[suggestion]; ok {
+			continue
+		}
+		a.suggested[suggestion] = struct{}{}
+		a.logger.Warn(suggestion)
+	}
+}
+
+// advisedCall collects the plans of the queries of a call, which are analyzed by
+// the query advisor once the call is done.
+type advisedCall struct {
+	advisor *QueryAdvisor
+	plans   []*explainedPlan
+}
+
+// explain records the plan of a query that is about to be executed, if the
+// advisor has not analyzed it before.
+func (c *advisedCall) explain(ctx context.Context, db sql.DB, stmt string, args []value) error {
+	if !c.advisor.shouldAnalyze(stmt) {
+		return nil
+	}
+
+	var out []byte
+	var explained []byte
+	err := query(ctx, db, "EXPLAIN (FORMAT JSON, VERBOSE) "+stmt, []any{&out}, func() error {
+		explained = bytes.Clone(out)
+		return nil
+	}, args)
+	if err != nil {
+		return fmt.Errorf("failed to explain query: %w", err)
+	}
+
+	var plans []struct {
+		Plan explainedPlan `json:"Plan"`
+	}
+	if err = json.Unmarshal(explained, &plans); err != nil {
+		return fmt.Errorf("failed to decode query plan: %w", err)
+	}
+
+	for i := range plans {
+		c.plans = append(c.plans, &plans[i].Plan)
+	}
+	return nil
+}
+
+// filterColumn matches the columns compared in a filter of a verbose plan,
+// which are qualified by the alias of their relation.
+var filterColumn = regexp.MustCompile(`([a-z_][a-z0-9_]*)\.([a-z_][a-z0-9_]*)\s*(=|<>|<=|>=|<|>|~~|IS\b)`)
+
+// suggestOptimizations returns the suggestions for a plan and its children.
+// Only the tables that getTable finds are considered, which excludes the
+// engine's internal tables.
+func suggestOptimizations(plan *explainedPlan, getTable func(namespace, table string) (*engine.Table, error)) []string {
+	var suggestions []string
+
+	if plan.RelationName != "" {
+		if tbl, err := getTable(plan.Schema, plan.RelationName); err == nil {
+			if plan.NodeType == "Seq Scan" && plan.Filter != "" {
+				for _, match := range filterColumn.FindAllStringSubmatch(plan.Filter, -1) {
+					if match[1] != plan.Alias {
+						continue
+					}
+					if _, ok := tbl.Column(match[2]); ok && !hasLeadingIndex(tbl, match[2]) {
+						suggestions = append(suggestions, fmt.Sprintf("Consider adding index on %s.%s (sequential scan detected)", tbl.Name, match[2]))
+					}
+					break
+				}
+			}
+
+			for _, constraint := range tbl.Constraints {
+				if constraint.Type == engine.ConstraintFK && len(constraint.Columns) > 0 && !hasLeadingIndex(tbl, constraint.Columns[0]) {
+					suggestions = append(suggestions, fmt.Sprintf("Consider adding index on %s.%s (foreign key without index detected)", tbl.Name, constraint.Columns[0]))
+				}
+			}
+		}
+	}
+
+	if plan.NodeType == "Nested Loop" && plan.JoinFilter == "" && len(plan.Plans) == 2 && !hasCondition(&plan.Plans[1]) {
+		outer, inner := firstRelation(&plan.Plans[0]), firstRelation(&plan.Plans[1])
+		if outer != "" && inner != "" {
+			suggestions = append(suggestions, fmt.Sprintf("Consider adding a join condition between %s and %s (cartesian product join detected)", outer, inner))
+		}
+	}
+
+	for i := range plan.Plans {
+		suggestions = append(suggestions, suggestOptimizations(&plan.Plans[i], getTable)...)
+	}
+
+	return suggestions
+}
+
+// hasLeadingIndex returns true if the column is the first column of the table's
+// primary key, or of one of its indexes or unique constraints.
+func hasLeadingIndex(tbl *engine.Table, column string) bool {
+	if pk := tbl.PrimaryKeyCols(); len(pk) > 0 && pk[0].Name == column {
+		return true
+	}
+	for _, idx := range tbl.Indexes {
+		if len(idx.Columns) > 0 && idx.Columns[0] == column {
+			return true
+		}
+	}
+	for _, constraint := range tbl.Constraints {
+		if constraint.Type == engine.ConstraintUnique && len(constraint.Columns) > 0 && constraint.Columns[0] == column {
+			return true
+		}
+	}
+	return false
+}
+
+// hasCondition returns true if a plan node or one of its children restricts
+// the rows it returns by a condition, which for the inner side of a nested
+// loop means that it is joined on it.
+func hasCondition(plan *explainedPlan) bool {
+	if plan.Filter != "" || plan.JoinFilter != "" || plan.IndexCond != "" || plan.HashCond != "" || plan.MergeCond != "" {
+		return true
+	}
+	for i := range plan.Plans {
+		if hasCondition(&plan.Plans[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// firstRelation returns the name of the first relation scanned by a plan node
+// or its children.
+func firstRelation(plan *explainedPlan) string {
+	if plan.RelationName != "" {
+		return plan.RelationName
+	}
+	for i := range plan.Plans {
+		if name := firstRelation(&plan.Plans[i]); name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+// adviseIndexCoverage logs the index coverage warnings of an action that
+// were not logged before.
+func (a *QueryAdvisor) adviseIndexCoverage(act *parse.CreateActionStatement, getTable func(namespace, table string) (*engine.Table, error)) {
+	warnings := parse.IndexCoverageWarnings(act, getTable)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for _, warning := range warnings {
+		suggestion := warning.String()
+		if _, ok := a.suggested[suggestion]; ok {
+			continue
+		}
+		a.suggested[suggestion] = struct{}{}
+		a.logger.Warn(suggestion)
+	}
+}
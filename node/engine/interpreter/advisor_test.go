@@ -0,0 +1,98 @@
+package interpreter
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kwilteam/kwil-db/core/types"
+	"github.com/kwilteam/kwil-db/node/engine"
+)
+
+func Test_SuggestOptimizations(t *testing.T) {
+	tables := map[string]*engine.Table{
+		"users": {
+			Name: "users",
+			Columns: []*engine.Column{
+				{Name: "id", DataType: types.IntType, IsPrimaryKey: true},
+				{Name: "email", DataType: types.TextType},
+				{Name: "name", DataType: types.TextType},
+			},
+			Indexes: []*engine.Index{
+				{Name: "users_name_idx", Columns: []string{"name"}, Type: engine.BTREE},
+			},
+		},
+		"posts": {
+			Name: "posts",
+			Columns: []*engine.Column{
+				{Name: "id", DataType: types.IntType, IsPrimaryKey: true},
+				{Name: "author_id", DataType: types.IntType},
+			},
+			Constraints: map[string]*engine.Constraint{
+				"posts_author_fk": {Type: engine.ConstraintFK, Columns: []string{"author_id"}},
+			},
+		},
+	}
+	getTable := func(namespace, table string) (*engine.Table, error) {
+		if tbl, ok := tables[table]; ok && namespace == "main" {
+			return tbl, nil
+		}
+		return nil, fmt.Errorf("unknown table %s", table)
+	}
+
+	tests := []struct {
+		name string
+		plan string
+		want []string
+	}{
+		{
+			name: "sequential scan on unindexed column",
+			plan: `{"Node Type": "Seq Scan", "Relation Name": "users", "Schema": "main", "Alias": "u",
+				"Filter": "(u.email = $1)"}`,
+			want: []string{"Consider adding index on users.email (sequential scan detected)"},
+		},
+		{
+			name: "sequential scan on indexed column",
+			plan: `{"Node Type": "Seq Scan", "Relation Name": "users", "Schema": "main", "Alias": "users",
+				"Filter": "(users.name = 'a'::text)"}`,
+		},
+		{
+			name: "sequential scan of internal table",
+			plan: `{"Node Type": "Seq Scan", "Relation Name": "users", "Schema": "kwild_engine", "Alias": "users",
+				"Filter": "(users.email = 'a'::text)"}`,
+		},
+		{
+			name: "foreign key without index",
+			plan: `{"Node Type": "Index Scan", "Relation Name": "posts", "Schema": "main", "Alias": "posts",
+				"Index Cond": "(posts.id = 1)"}`,
+			want: []string{"Consider adding index on posts.author_id (foreign key without index detected)"},
+		},
+		{
+			name: "cartesian product join",
+			plan: `{"Node Type": "Nested Loop", "Plans": [
+				{"Node Type": "Index Scan", "Relation Name": "users", "Schema": "main", "Alias": "users", "Index Cond": "(users.id = 1)"},
+				{"Node Type": "Materialize", "Plans": [
+					{"Node Type": "Seq Scan", "Relation Name": "users", "Schema": "main", "Alias": "u2"}
+				]}
+			]}`,
+			want: []string{"Consider adding a join condition between users and users (cartesian product join detected)"},
+		},
+		{
+			name: "nested loop with join condition",
+			plan: `{"Node Type": "Nested Loop", "Plans": [
+				{"Node Type": "Seq Scan", "Relation Name": "users", "Schema": "main", "Alias": "users"},
+				{"Node Type": "Index Scan", "Relation Name": "users", "Schema": "main", "Alias": "u2", "Index Cond": "(u2.id = users.id)"}
+			]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var plan explainedPlan
+			require.NoError(t, json.Unmarshal([]byte(tt.plan), &plan))
+			require.Equal(t, tt.want, suggestOptimizations(&plan, getTable))
+		})
+	}
+}
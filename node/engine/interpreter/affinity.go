@@ -0,0 +1,66 @@
+package interpreter
+
+import (
+	"strings"
+
+	"github.com/kwilteam/kwil-db/config"
+	"github.com/kwilteam/kwil-db/node/engine"
+	"github.com/kwilteam/kwil-db/node/types/sql"
+)
+
+// namespaceAffinity rejects calls to namespaces whose home node is another
+// node, so that clients can redirect them to it.
+type namespaceAffinity struct {
+	// homes maps lower case namespaces to the addresses of their home nodes.
+	homes map[string]string
+	// local is the P2P address of this node.
+	local string
+}
+
+// newNamespaceAffinity returns the namespace affinity of a node's config, or
+// nil if it has none. The node is identified by its external P2P address, or
+// its listen address if it has none.
+func newNamespaceAffinity(cfg *config.Config) *namespaceAffinity {
+	if len(cfg.NamespaceAffinity) == 0 {
+		return nil
+	}
+
+	local := cfg.P2P.ExternalAddress
+	if local == "" {
+		local = cfg.P2P.ListenAddress
+	}
+
+	homes := make(map[string]string, len(cfg.NamespaceAffinity))
+	for ns, addr := range cfg.NamespaceAffinity {
+		homes[strings.ToLower(ns)] = addr
+	}
+
+	return &namespaceAffinity{
+		homes: homes,
+		local: local,
+	}
+}
+
+// check returns a *engine.NotHomeNodeError if a call to the namespace should
+// be made on another node. Only read-only calls are checked, since calls that
+// can change state are part of consensus and must be executed by every node.
+func (n *namespaceAffinity) check(db sql.DB, namespace string) error {
+	am, ok := db.(sql.AccessModer)
+	if !ok || am.AccessMode() != sql.ReadOnly {
+		return nil
+	}
+
+	if namespace == "" {
+		namespace = engine.DefaultNamespace
+	}
+
+	home, ok := n.homes[strings.ToLower(namespace)]
+	if !ok || home == n.local {
+		return nil
+	}
+
+	return &engine.NotHomeNodeError{
+		Namespace: namespace,
+		HomeNode:  home,
+	}
+}
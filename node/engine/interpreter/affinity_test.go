@@ -0,0 +1,51 @@
+package interpreter
+
+import (
+	"testing"
+
+	"github.com/kwilteam/kwil-db/config"
+	"github.com/kwilteam/kwil-db/node/engine"
+	"github.com/kwilteam/kwil-db/node/types/sql"
+	"github.com/stretchr/testify/require"
+)
+
+// accessModeDB is a sql.DB with an access mode. Its other methods are not
+// implemented.
+type accessModeDB struct {
+	sql.DB
+	mode sql.AccessMode
+}
+
+func (a *accessModeDB) AccessMode() sql.AccessMode {
+	return a.mode
+}
+
+func Test_NamespaceAffinity(t *testing.T) {
+	cfg := config.DefaultConfig()
+	require.Nil(t, newNamespaceAffinity(cfg))
+
+	cfg.P2P.ExternalAddress = "10.0.0.1:6600"
+	cfg.NamespaceAffinity = map[string]string{
+		"Local":  "10.0.0.1:6600",
+		"remote": "10.0.0.2:6600",
+		"main":   "10.0.0.2:6600",
+	}
+	affinity := newNamespaceAffinity(cfg)
+	require.NotNil(t, affinity)
+
+	readOnly := &accessModeDB{mode: sql.ReadOnly}
+	require.NoError(t, affinity.check(readOnly, "local"))
+	require.NoError(t, affinity.check(readOnly, "unassigned"))
+
+	err := affinity.check(readOnly, "REMOTE")
+	require.ErrorIs(t, err, engine.ErrNotHomeNode)
+	var notHome *engine.NotHomeNodeError
+	require.ErrorAs(t, err, &notHome)
+	require.Equal(t, "10.0.0.2:6600", notHome.HomeNode)
+
+	// the default namespace is used if none is given
+	require.ErrorIs(t, affinity.check(readOnly, ""), engine.ErrNotHomeNode)
+
+	// calls that can change state are executed by every node
+	require.NoError(t, affinity.check(&accessModeDB{mode: sql.ReadWrite}, "remote"))
+}
@@ -0,0 +1,115 @@
+package interpreter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/kwilteam/kwil-db/common"
+	"github.com/kwilteam/kwil-db/node/types/sql"
+)
+
+// AnalyzeResult is the execution statistics of the queries executed by an
+// action, as measured by Postgres with EXPLAIN ANALYZE.
+type AnalyzeResult struct {
+	// Queries are the statistics of each query, in the order they were
+	// executed.
+	Queries []*QueryAnalysis
+}
+
+// QueryAnalysis is the execution statistics of a single query.
+type QueryAnalysis struct {
+	// SQL is the Postgres SQL the query was translated to.
+	SQL string
+	// PlanningTime is the time Postgres took to plan the query.
+	PlanningTime time.Duration
+	// ExecutionTime is the time Postgres took to execute the query.
+	ExecutionTime time.Duration
+	// Rows is the number of rows the query returned.
+	Rows int64
+	// BufferHits is the number of shared buffer blocks found in the cache.
+	BufferHits int64
+	// BufferMisses is the number of shared buffer blocks read from disk.
+	BufferMisses int64
+}
+
+// analyzedPlan is the JSON output of EXPLAIN ANALYZE for a query.
+type analyzedPlan struct {
+	Plan struct {
+		ActualRows float64 `json:"Actual Rows"`
+		SharedHit  int64   `json:"Shared Hit Blocks"`
+		SharedRead int64   `json:"Shared Read Blocks"`
+	} `json:"Plan"`
+	PlanningTime  float64 `json:"Planning Time"`  // milliseconds
+	ExecutionTime float64 `json:"Execution Time"` // milliseconds
+}
+
+// analyze records the execution statistics of a query that is about to be
+// executed. EXPLAIN ANALYZE executes the query, so it is run in a nested
+// transaction that is rolled back, and the query is only applied once.
+func (a *AnalyzeResult) analyze(ctx context.Context, db sql.DB, stmt string, args []value) error {
+	tx, err := db.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var out []byte
+	var explained []byte
+	err = query(ctx, tx, "EXPLAIN (ANALYZE, FORMAT JSON, BUFFERS) "+stmt, []any{&out}, func() error {
+		explained = bytes.Clone(out)
+		return nil
+	}, args)
+	if err != nil {
+		return fmt.Errorf("failed to analyze query: %w", err)
+	}
+
+	var plans []analyzedPlan
+	if err = json.Unmarshal(explained, &plans); err != nil {
+		return fmt.Errorf("failed to decode query analysis: %w", err)
+	}
+
+	for _, plan := range plans {
+		a.Queries = append(a.Queries, &QueryAnalysis{
+			SQL:           stmt,
+			PlanningTime:  msDuration(plan.PlanningTime),
+			ExecutionTime: msDuration(plan.ExecutionTime),
+			Rows:          int64(plan.Plan.ActualRows),
+			BufferHits:    plan.Plan.SharedHit,
+			BufferMisses:  plan.Plan.SharedRead,
+		})
+	}
+	return nil
+}
+
+// msDuration converts a number of milliseconds reported by Postgres to a
+// duration.
+func msDuration(ms float64) time.Duration {
+	return time.Duration(ms * float64(time.Millisecond))
+}
+
+// AnalyzeCall calls an action and returns the execution statistics of each
+// query it executes, measured with EXPLAIN ANALYZE. The call is simulated, so
+// none of its changes are kept, and it fails if the action fails.
+func (t *ThreadSafeInterpreter) AnalyzeCall(ctx *common.EngineContext, db sql.DB, namespace, action string, args []any) (*AnalyzeResult, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	analysis := &AnalyzeResult{}
+	t.i.analysis = analysis
+	defer func() { t.i.analysis = nil }()
+
+	simulated := *ctx
+	simulated.Simulate = true
+	res, err := t.i.call(&simulated, db, namespace, action, args, nil, true, nil)
+	if err != nil {
+		return nil, err
+	}
+	if res.Error != nil {
+		return nil, res.Error
+	}
+
+	return analysis, nil
+}
@@ -0,0 +1,148 @@
+package interpreter
+
+import (
+	"sync"
+	"time"
+
+	"github.com/kwilteam/kwil-db/common"
+	"github.com/kwilteam/kwil-db/node/metrics"
+)
+
+// AnomalyAlert describes an unusual pattern of calls.
+type AnomalyAlert struct {
+	// Caller is the signer of the calls.
+	Caller []byte
+	// Namespace and Action are the action that was called.
+	Namespace string
+	Action    string
+	// Calls is the number of calls made in Window.
+	Calls  int
+	Window time.Duration
+	// Time is when the call that raised the alert was made.
+	Time time.Time
+}
+
+// AnomalyDetector detects unusual patterns of calls, such as an account calling
+// an action far more often than usual. It is given every call to the
+// interpreter after it is made, and returns an alert if the call is part of an
+// unusual pattern.
+type AnomalyDetector interface {
+	Observe(caller []byte, namespace, action string, ts time.Time) (AnomalyAlert, bool)
+}
+
+// WithAnomalyDetector logs a warning for each alert raised by the detector.
+func WithAnomalyDetector(d AnomalyDetector) InterpreterOpt {
+	return func(o *InterpreterOptions) {
+		o.AnomalyDetector = d
+	}
+}
+
+// anomalyWindow is the window over which SlidingWindowAnomalyDetector counts
+// calls.
+const anomalyWindow = time.Minute
+
+// SlidingWindowAnomalyDetector raises an alert when a caller calls an action
+// more than ThresholdPerMinute times in a sliding 60 second window. It raises
+// one alert each time the rate goes over the threshold, rather than one for
+// every call while it stays over.
+type SlidingWindowAnomalyDetector struct {
+	ThresholdPerMinute int
+
+	mu    sync.Mutex
+	calls map[anomalyKey]*callWindow
+	// lastSweep is when windows without recent calls were last removed.
+	lastSweep time.Time
+}
+
+// anomalyKey identifies the calls of a caller to an action.
+type anomalyKey struct {
+	caller    string
+	namespace string
+	action    string
+}
+
+// callWindow is the calls of a caller to an action in the current window.
+type callWindow struct {
+	// times are the times of the calls, oldest first.
+	times []time.Time
+	// alerted is true if an alert was raised since the rate last went over
+	// the threshold.
+	alerted bool
+}
+
+// NewSlidingWindowAnomalyDetector creates an anomaly detector that raises an
+// alert when a caller calls an action more than thresholdPerMinute times in a
+// minute.
+func NewSlidingWindowAnomalyDetector(thresholdPerMinute int) *SlidingWindowAnomalyDetector {
+	return &SlidingWindowAnomalyDetector{
+		ThresholdPerMinute: max(thresholdPerMinute, 1),
+		calls:              make(map[anomalyKey]*callWindow),
+	}
+}
+
+// Observe records a call, returning an alert if it takes the caller's rate of
+// calls to the action over the threshold.
+func (d *SlidingWindowAnomalyDetector) Observe(caller []byte, namespace, action string, ts time.Time) (AnomalyAlert, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	cutoff := ts.Add(-anomalyWindow)
+	if ts.Sub(d.lastSweep) >= anomalyWindow {
+		for key, w := range d.calls {
+			if !w.times[len(w.times)-1].After(cutoff) {
+				delete(d.calls, key)
+			}
+		}
+		d.lastSweep = ts
+	}
+
+	key := anomalyKey{caller: string(caller), namespace: namespace, action: action}
+	w, ok := d.calls[key]
+	if !ok {
+		w = &callWindow{}
+		d.calls[key] = w
+	}
+
+	var expired int
+	for expired < len(w.times) && !w.times[expired].After(cutoff) {
+		expired++
+	}
+	w.times = append(w.times[expired:], ts)
+
+	if len(w.times) <= d.ThresholdPerMinute {
+		w.alerted = false
+		return AnomalyAlert{}, false
+	}
+	if w.alerted {
+		return AnomalyAlert{}, false
+	}
+	w.alerted = true
+
+	return AnomalyAlert{
+		Caller:    caller,
+		Namespace: namespace,
+		Action:    action,
+		Calls:     len(w.times),
+		Window:    anomalyWindow,
+		Time:      ts,
+	}, true
+}
+
+// observeCall gives a call to the anomaly detector, logging a warning if it
+// raises an alert.
+func (t *ThreadSafeInterpreter) observeCall(ctx *common.EngineContext, namespace, action string) {
+	if t.anomalies == nil || ctx.InvalidTxCtx {
+		return
+	}
+
+	alert, ok := t.anomalies.Observe(ctx.TxContext.Signer, namespace, action, time.Now())
+	if !ok {
+		return
+	}
+
+	metrics.Engine.AnomalyDetected(ctx.TxContext.Ctx)
+	if t.i.service != nil && t.i.service.Logger != nil {
+		t.i.service.Logger.Warn("unusual call pattern detected", "caller", ctx.TxContext.Caller,
+			"namespace", alert.Namespace, "action", alert.Action, "calls", alert.Calls, "window", alert.Window)
+	}
+}
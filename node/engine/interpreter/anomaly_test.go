@@ -0,0 +1,56 @@
+package interpreter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SlidingWindowAnomalyDetector(t *testing.T) {
+	d := NewSlidingWindowAnomalyDetector(50)
+	start := time.Unix(1000, 0)
+	alice, bob := []byte("alice"), []byte("bob")
+
+	// 100 calls in a second raise a single alert, on the call that goes over
+	// the threshold
+	var alerts []AnomalyAlert
+	for i := range 100 {
+		ts := start.Add(time.Duration(i) * 10 * time.Millisecond)
+		if alert, ok := d.Observe(alice, "main", "transfer", ts); ok {
+			require.Equal(t, 50, i)
+			alerts = append(alerts, alert)
+		}
+	}
+	require.Len(t, alerts, 1)
+	require.Equal(t, AnomalyAlert{
+		Caller:    alice,
+		Namespace: "main",
+		Action:    "transfer",
+		Calls:     51,
+		Window:    time.Minute,
+		Time:      start.Add(500 * time.Millisecond),
+	}, alerts[0])
+
+	// rates are per caller and action
+	for i := range 50 {
+		ts := start.Add(time.Duration(i) * 10 * time.Millisecond)
+		_, ok := d.Observe(bob, "main", "transfer", ts)
+		require.False(t, ok)
+		_, ok = d.Observe(alice, "main", "other", ts)
+		require.False(t, ok)
+	}
+
+	// once the calls leave the window, the rate is under the threshold, and
+	// going over it again raises a new alert
+	later := start.Add(2 * time.Minute)
+	for i := range 50 {
+		_, ok := d.Observe(alice, "main", "transfer", later.Add(time.Duration(i)*time.Millisecond))
+		require.False(t, ok)
+	}
+	_, ok := d.Observe(alice, "main", "transfer", later.Add(time.Second))
+	require.True(t, ok)
+
+	// windows without recent calls are removed
+	require.Len(t, d.calls, 1)
+}
@@ -0,0 +1,316 @@
+package interpreter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kwilteam/kwil-db/common"
+	"github.com/kwilteam/kwil-db/node/engine"
+	"github.com/kwilteam/kwil-db/node/engine/parse"
+	"github.com/kwilteam/kwil-db/node/types/sql"
+)
+
+// backupVersion is the version of the backup format written by
+// BackupNamespace.
+const backupVersion = 1
+
+// backupTableKey is the key of the object that starts the rows of a table in a
+// backup. Since column names cannot start with $, it cannot be a row.
+const backupTableKey = "$table"
+
+// namespaceBackup is the first line of a backup. It holds the schema of the
+// namespace.
+type namespaceBackup struct {
+	Version   int    `json:"version"`
+	Namespace string `json:"namespace"`
+	// Schema creates the tables, indexes and actions of the namespace.
+	Schema []string `json:"schema"`
+	// Constraints add the column defaults, and the check and foreign key
+	// constraints, which GenerateSchema does not include. They are added
+	// after the rows are restored, so that rows can be restored in any order.
+	Constraints []string `json:"constraints"`
+}
+
+// BackupNamespace writes a backup of a namespace, including its data, to w.
+// The backup is newline-delimited JSON: its first line holds the schema of
+// the namespace, which is followed, for each table, by a line with the name
+// of the table and a line for each of its rows, with column names as keys.
+// It is restored with RestoreNamespace.
+//
+// Roles and privileges, which are not part of the namespace, are not backed
+// up, and neither are the history of @history tables or the events of
+// @event_sourced namespaces. Event-sourced namespaces cannot be backed up,
+// since their events could not be replayed from the backup.
+func (t *ThreadSafeInterpreter) BackupNamespace(ctx context.Context, db sql.DB, name string, w io.Writer) error {
+	name = strings.ToLower(name)
+
+	t.mu.RLock()
+	ns, ok := t.i.namespaces[name]
+	var nsType namespaceType
+	var eventSourced bool
+	if ok {
+		nsType, eventSourced = ns.namespaceType, ns.eventSourced
+	}
+	t.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf(`%w: "%s"`, engine.ErrNamespaceNotFound, name)
+	}
+	if nsType != namespaceTypeUser {
+		return fmt.Errorf(`cannot back up %s namespace "%s"`, strings.ToLower(string(nsType)), name)
+	}
+	if eventSourced {
+		return fmt.Errorf(`cannot back up event-sourced namespace "%s"`, name)
+	}
+
+	// the backup is read in one transaction, so that it is consistent
+	tx, err := db.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	tables, err := listTablesInNamespace(ctx, tx, name)
+	if err != nil {
+		return err
+	}
+	slices.SortFunc(tables, func(a, b *engine.Table) int { return strings.Compare(a.Name, b.Name) })
+
+	header, err := backupSchema(ctx, tx, name, tables)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(w)
+	if err = enc.Encode(header); err != nil {
+		return err
+	}
+
+	for _, tbl := range tables {
+		if err = enc.Encode(map[string]string{backupTableKey: tbl.Name}); err != nil {
+			return err
+		}
+
+		// rows are ordered by primary key, so that backups of the same data
+		// are the same
+		var orderBy []string
+		for _, col := range tbl.PrimaryKeyCols() {
+			orderBy = append(orderBy, col.Name)
+		}
+		stmt := fmt.Sprintf(`SELECT row_to_json(t)::TEXT FROM %s.%s AS t`, name, tbl.Name)
+		if len(orderBy) > 0 {
+			stmt += " ORDER BY " + strings.Join(orderBy, ", ")
+		}
+
+		var row string
+		err = queryRowFunc(ctx, tx, stmt, []any{&row}, func() error {
+			_, err := io.WriteString(w, row+"\n")
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("table %s: %w", tbl.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// backupSchema returns the statements that re-create a namespace.
+func backupSchema(ctx context.Context, db sql.DB, namespace string, tables []*engine.Table) (*namespaceBackup, error) {
+	schema, err := parse.GenerateSchema(tables)
+	if err != nil {
+		return nil, err
+	}
+
+	header := &namespaceBackup{
+		Version:   backupVersion,
+		Namespace: namespace,
+		Schema:    []string{schema},
+	}
+
+	isTable := make(map[string]bool, len(tables))
+	isColumn := make(map[[2]string]bool)
+	for _, tbl := range tables {
+		isTable[tbl.Name] = true
+		for _, col := range tbl.Columns {
+			isColumn[[2]string{tbl.Name, col.Name}] = true
+		}
+	}
+
+	// defaults and constraints are in the text Postgres formats them in, which
+	// is also valid in Kwil for the expressions Kwil can create
+	var table, column, def string
+	err = queryRowFunc(ctx, db, `SELECT table_name::TEXT, column_name::TEXT, column_default::TEXT
+	FROM information_schema.columns
+	WHERE table_schema = $1 AND column_default IS NOT NULL
+	ORDER BY table_name, ordinal_position`, []any{&table, &column, &def}, func() error {
+		if isColumn[[2]string{table, column}] {
+			header.Constraints = append(header.Constraints, fmt.Sprintf(`ALTER TABLE %s ALTER COLUMN %s SET DEFAULT %s;`, table, column, def))
+		}
+		return nil
+	}, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	var constraint string
+	err = queryRowFunc(ctx, db, `SELECT t.relname::TEXT, c.conname::TEXT, pg_get_constraintdef(c.oid)
+	FROM pg_constraint c
+	JOIN pg_class t ON t.oid = c.conrelid
+	JOIN pg_namespace n ON n.oid = t.relnamespace
+	WHERE n.nspname = $1 AND c.contype IN ('c', 'f')
+	ORDER BY t.relname, c.conname`, []any{&table, &constraint, &def}, func() error {
+		if isTable[table] {
+			header.Constraints = append(header.Constraints, fmt.Sprintf(`ALTER TABLE %s ADD CONSTRAINT %s %s;`, table, constraint, def))
+		}
+		return nil
+	}, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	err = queryRowFunc(ctx, db, `SELECT raw_statement FROM kwild_engine.actions
+	WHERE namespace = $1 AND built_in = false
+	ORDER BY name`, []any{&def}, func() error {
+		header.Schema = append(header.Schema, def)
+		return nil
+	}, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	return header, nil
+}
+
+// RestoreNamespace restores a backup written by BackupNamespace. It creates the
+// namespace, which must not have any of the tables or actions of the backup,
+// and inserts the rows of its tables. The backup is restored in a single
+// transaction, and is not restored at all if it fails.
+//
+// Since restoring is not part of a block, the history of @history tables is
+// recorded at the time of the restore.
+func (t *ThreadSafeInterpreter) RestoreNamespace(ctx context.Context, db sql.DB, r io.Reader) (err error) {
+	dec := json.NewDecoder(r)
+	var header namespaceBackup
+	if err = dec.Decode(&header); err != nil {
+		return fmt.Errorf("invalid backup: %w", err)
+	}
+	if header.Version != backupVersion {
+		return fmt.Errorf("unsupported backup version %d", header.Version)
+	}
+	namespace := strings.ToLower(header.Namespace)
+	if !identRegexp.MatchString(namespace) {
+		return fmt.Errorf(`invalid namespace name "%s"`, namespace)
+	}
+
+	engineCtx := &common.EngineContext{
+		TxContext: &common.TxContext{
+			Ctx: ctx,
+			BlockContext: &common.BlockContext{
+				ChainContext: &common.ChainContext{
+					NetworkParameters: &common.NetworkParameters{},
+					MigrationParams:   &common.MigrationContext{},
+				},
+				Timestamp: time.Now().Unix(),
+			},
+		},
+		OverrideAuthz: true,
+	}
+
+	release, err := t.acquire(ctx, db)
+	if err != nil {
+		return &engine.QuotaExceededError{Namespace: namespace, Err: err}
+	}
+	defer release()
+
+	tx, err := db.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err = claimNamespaceVersion(engineCtx, tx, namespace, nil); err != nil {
+		return err
+	}
+
+	unlock, err := t.lock(db)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if t.nsEvents != nil {
+		before := namespaceNames(t.i.namespaces)
+		defer func() {
+			if err == nil {
+				t.publishNamespaceEvents(before)
+			}
+		}()
+	}
+
+	copied := t.i.copy()
+	defer func() {
+		if err != nil {
+			t.i.apply(copied)
+		}
+	}()
+
+	if err = t.i.deployStatements(engineCtx, tx, namespace, header.Schema); err != nil {
+		return err
+	}
+
+	if err = restoreRows(ctx, tx, namespace, dec, t.i.namespaces[namespace].tables); err != nil {
+		return err
+	}
+
+	if err = t.i.deployStatements(engineCtx, tx, namespace, header.Constraints); err != nil {
+		return fmt.Errorf("constraints: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+// restoreRows inserts the rows of a backup into the tables of a namespace.
+func restoreRows(ctx context.Context, db sql.DB, namespace string, dec *json.Decoder, tables map[string]*engine.Table) error {
+	var table string
+	for line := 2; ; line++ {
+		var raw json.RawMessage
+		err := dec.Decode(&raw)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("invalid backup at line %d: %w", line, err)
+		}
+
+		var start map[string]json.RawMessage
+		if err = json.Unmarshal(raw, &start); err != nil {
+			return fmt.Errorf("invalid backup at line %d: %w", line, err)
+		}
+		if name, ok := start[backupTableKey]; ok {
+			if err = json.Unmarshal(name, &table); err != nil {
+				return fmt.Errorf("invalid backup at line %d: %w", line, err)
+			}
+			if _, ok := tables[table]; !ok {
+				return fmt.Errorf(`%w: "%s"`, engine.ErrUnknownTable, table)
+			}
+			continue
+		}
+		if table == "" {
+			return fmt.Errorf("invalid backup at line %d: row is not in a table", line)
+		}
+
+		// the row includes the columns the engine adds, e.g. for soft
+		// deletes, since it is a row of the Postgres table
+		err = execute(ctx, db, fmt.Sprintf(`INSERT INTO %s.%s SELECT * FROM json_populate_record(NULL::%s.%s, $1::JSON)`,
+			namespace, table, namespace, table), string(raw))
+		if err != nil {
+			return fmt.Errorf("table %s, line %d: %w", table, line, err)
+		}
+	}
+}
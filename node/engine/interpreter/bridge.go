@@ -0,0 +1,291 @@
+package interpreter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/kwilteam/kwil-db/common"
+	"github.com/kwilteam/kwil-db/core/types"
+	"github.com/kwilteam/kwil-db/node/engine"
+	"github.com/kwilteam/kwil-db/node/pg"
+)
+
+// bridgeFunction creates the kwild_engine.kwil_call_action function, which
+// posts a call to the bridge server with the http extension. It returns the
+// rows of the call as a JSON array of objects, and raises an exception if the
+// call fails. The port of the bridge server is formatted into it.
+const bridgeFunction = `CREATE OR REPLACE FUNCTION kwild_engine.kwil_call_action(namespace TEXT, action TEXT, args JSON)
+RETURNS JSON LANGUAGE plpgsql AS $$
+DECLARE
+    resp JSON;
+BEGIN
+    SELECT content::JSON INTO resp FROM http_post('http://127.0.0.1:%d/call',
+        json_build_object('namespace', namespace, 'action', action, 'args', COALESCE(args, '[]'::JSON))::TEXT,
+        'application/json');
+    IF resp->>'error' IS NOT NULL THEN
+        RAISE EXCEPTION 'kwil_call_action: %%', resp->>'error';
+    END IF;
+    RETURN resp->'rows';
+END;
+$$;`
+
+// bridgeServer serves the calls that Postgres triggers make with
+// kwild_engine.kwil_call_action.
+type bridgeServer struct {
+	pool   *pg.Pool
+	server *http.Server
+	done   chan struct{}
+}
+
+// bridgeRequest is a call made through the bridge.
+type bridgeRequest struct {
+	Namespace string            `json:"namespace"`
+	Action    string            `json:"action"`
+	Args      []json.RawMessage `json:"args"`
+}
+
+// bridgeResponse is the result of a call made through the bridge.
+type bridgeResponse struct {
+	Rows  []map[string]any `json:"rows"`
+	Logs  []string         `json:"logs,omitempty"`
+	Error *string          `json:"error,omitempty"`
+}
+
+// StartBridgeServer starts a server on 127.0.0.1:port that lets Postgres
+// triggers call actions with kwild_engine.kwil_call_action(namespace, action,
+// args), where args is a JSON array. The function requires the http extension,
+// which is created if it does not exist. If port is 0, a free port is used.
+//
+// Calls made through the bridge use their own read-only transaction, so they
+// can only call view actions, and do not see the uncommitted changes of the
+// statement that fired the trigger. They do not lock the interpreter, since
+// the call that fired the trigger holds the lock while it waits for them.
+//
+// WARNING: the bridge is for nodes that run custom Postgres extensions. Calls
+// made through it are not part of consensus.
+func (t *ThreadSafeInterpreter) StartBridgeServer(port int) error {
+	if t.bridge != nil {
+		return errors.New("bridge server is already running")
+	}
+
+	ctx := context.Background()
+	pool, err := newServicePool(ctx, t.i.service)
+	if err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+	if err != nil {
+		pool.Close()
+		return err
+	}
+
+	if _, err = pool.Execute(ctx, `CREATE EXTENSION IF NOT EXISTS http`); err != nil {
+		listener.Close()
+		pool.Close()
+		return fmt.Errorf("failed to create the http extension: %w", err)
+	}
+	_, err = pool.Execute(ctx, fmt.Sprintf(bridgeFunction, listener.Addr().(*net.TCPAddr).Port))
+	if err != nil {
+		listener.Close()
+		pool.Close()
+		return fmt.Errorf("failed to create kwil_call_action: %w", err)
+	}
+
+	b := &bridgeServer{
+		pool: pool,
+		done: make(chan struct{}),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /call", func(w http.ResponseWriter, r *http.Request) {
+		resp := b.call(r.Context(), t.i, r)
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(resp); err != nil && t.i.service.Logger != nil {
+			t.i.service.Logger.Warn("failed to write bridge response", "error", err)
+		}
+	})
+	b.server = &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		defer close(b.done)
+		if err := b.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) && t.i.service.Logger != nil {
+			t.i.service.Logger.Errorf("bridge server stopped: %v", err)
+		}
+	}()
+
+	t.bridge = b
+	return nil
+}
+
+// StopBridgeServer stops the bridge server, if it was started. Calls to
+// kwil_call_action fail until it is started again.
+func (t *ThreadSafeInterpreter) StopBridgeServer() {
+	if t.bridge == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	t.bridge.server.Shutdown(ctx)
+	<-t.bridge.done
+	t.bridge.pool.Close()
+	t.bridge = nil
+}
+
+// call calls the action of a bridge request.
+func (b *bridgeServer) call(ctx context.Context, i *baseInterpreter, r *http.Request) *bridgeResponse {
+	fail := func(err error) *bridgeResponse {
+		msg := err.Error()
+		return &bridgeResponse{Error: &msg}
+	}
+
+	var req bridgeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return fail(fmt.Errorf("invalid request: %w", err))
+	}
+
+	args, err := bridgeArgs(i, req)
+	if err != nil {
+		return fail(err)
+	}
+
+	tx, err := b.pool.BeginReadTx(ctx)
+	if err != nil {
+		return fail(err)
+	}
+	defer tx.Rollback(ctx)
+
+	resp := &bridgeResponse{Rows: []map[string]any{}}
+	res, err := i.call(newInvalidEngineCtx(ctx), tx, req.Namespace, req.Action, args, func(row *common.Row) error {
+		obj := make(map[string]any, len(row.Values))
+		for j, col := range row.ColumnNames {
+			obj[col] = row.Values[j]
+		}
+		resp.Rows = append(resp.Rows, obj)
+		return nil
+	}, true, nil)
+	if err != nil {
+		return fail(err)
+	}
+	if res.Error != nil {
+		return fail(res.Error)
+	}
+
+	resp.Logs = res.Logs
+	return resp
+}
+
+// bridgeArgs converts the JSON arguments of a bridge request to the types of
+// the parameters of its action. Each argument is read as text, and then cast,
+// so that e.g. a JSON number can be passed as an INT or a NUMERIC.
+func bridgeArgs(i *baseInterpreter, req bridgeRequest) ([]any, error) {
+	ns, ok := i.namespaces[strings.ToLower(req.Namespace)]
+	if !ok {
+		return nil, &engine.NamespaceNotFoundError{Namespace: req.Namespace}
+	}
+	exec, ok := ns.availableFunctions[strings.ToLower(req.Action)]
+	if !ok || exec.ExpectedArgs == nil {
+		return nil, &engine.ActionNotFoundError{Namespace: req.Namespace, Action: req.Action}
+	}
+
+	expect := *exec.ExpectedArgs
+	if len(expect) != len(req.Args) {
+		return nil, fmt.Errorf(`%w: action "%s" expected %d arguments, but got %d`, engine.ErrActionInvocation, req.Action, len(expect), len(req.Args))
+	}
+
+	args := make([]any, len(req.Args))
+	for j, raw := range req.Args {
+		var text value
+		if expect[j].IsArray {
+			var elems []json.RawMessage
+			if err := json.Unmarshal(raw, &elems); err != nil {
+				return nil, fmt.Errorf("argument %d is not an array: %w", j+1, err)
+			}
+			if elems == nil {
+				text = makeNullText(true)
+			} else {
+				strs := make([]*string, len(elems))
+				for k, elem := range elems {
+					s, err := jsonText(elem)
+					if err != nil {
+						return nil, err
+					}
+					strs[k] = s
+				}
+				text = newTextArrayValue(strs)
+			}
+		} else {
+			s, err := jsonText(raw)
+			if err != nil {
+				return nil, err
+			}
+			if s == nil {
+				text = makeNullText(false)
+			} else {
+				text = makeText(*s)
+			}
+		}
+
+		v, err := text.Cast(expect[j])
+		if err != nil {
+			return nil, fmt.Errorf("argument %d: %w", j+1, err)
+		}
+		// text is cast to a numeric with the precision of the text, which
+		// is then cast to the precision of the parameter
+		if expect[j].Name == types.NumericStr && !expect[j].IsArray {
+			v, err = v.Cast(expect[j])
+			if err != nil {
+				return nil, fmt.Errorf("argument %d: %w", j+1, err)
+			}
+		}
+		args[j] = v.RawValue()
+	}
+
+	return args, nil
+}
+
+// makeNullText returns a null text or text array.
+func makeNullText(array bool) value {
+	dt := types.TextType
+	if array {
+		dt = types.TextArrayType
+	}
+	v, _ := makeNull(dt)
+	return v
+}
+
+// jsonText returns the text of a scalar JSON value, or nil if it is null.
+func jsonText(raw json.RawMessage) (*string, error) {
+	var v any
+	dec := json.NewDecoder(strings.NewReader(string(raw)))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	var s string
+	switch v := v.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		s = v
+	case json.Number:
+		s = v.String()
+	case bool:
+		s = fmt.Sprint(v)
+	default:
+		return nil, fmt.Errorf("unsupported argument %s", raw)
+	}
+	return &s, nil
+}
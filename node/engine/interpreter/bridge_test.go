@@ -0,0 +1,47 @@
+package interpreter
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kwilteam/kwil-db/core/types"
+)
+
+func Test_BridgeArgs(t *testing.T) {
+	numeric, err := types.NewNumericType(10, 2)
+	require.NoError(t, err)
+	expect := []*types.DataType{types.IntType, types.TextType, numeric, types.BoolArrayType, types.IntType}
+	i := &baseInterpreter{
+		namespaces: map[string]*namespace{
+			"main": {
+				availableFunctions: map[string]*executable{
+					"act": {Type: executableTypeAction, ExpectedArgs: &expect},
+				},
+			},
+		},
+	}
+
+	args := func(s string) []json.RawMessage {
+		var raw []json.RawMessage
+		require.NoError(t, json.Unmarshal([]byte(s), &raw))
+		return raw
+	}
+
+	got, err := bridgeArgs(i, bridgeRequest{Namespace: "main", Action: "act", Args: args(`[1, "a", 1.5, [true, null], null]`)})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), got[0])
+	require.Equal(t, "a", got[1])
+	require.Equal(t, "1.50", got[2].(*types.Decimal).String())
+	tru := true
+	require.Equal(t, []*bool{&tru, nil}, got[3])
+	require.Nil(t, got[4])
+
+	_, err = bridgeArgs(i, bridgeRequest{Namespace: "main", Action: "act", Args: args(`[1]`)})
+	require.Error(t, err)
+	_, err = bridgeArgs(i, bridgeRequest{Namespace: "main", Action: "act", Args: args(`["a", "a", 1.5, [], null]`)})
+	require.Error(t, err)
+	_, err = bridgeArgs(i, bridgeRequest{Namespace: "main", Action: "other", Args: nil})
+	require.Error(t, err)
+}
@@ -0,0 +1,121 @@
+package interpreter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/kwilteam/kwil-db/node/engine"
+	"github.com/kwilteam/kwil-db/node/types/sql"
+)
+
+// BulkLoad inserts the rows received from rows into a table, until rows is
+// closed, and returns the number of rows inserted. Each row has a value for
+// each column of the table, in the order of the columns, which is checked and
+// converted like the arguments of an action. The rows are inserted with
+// Postgres' COPY, which is much faster than inserting them one at a time, so
+// the transactions of db must be sql.Copiers.
+//
+// It is meant for seeding large tables, and bypasses the engine's access
+// control, so it must not be exposed to callers. The rows are inserted in a
+// nested transaction, so either all of them are inserted, or none are.
+func (t *ThreadSafeInterpreter) BulkLoad(ctx context.Context, db sql.DB, namespace, table string, rows <-chan []any) (int64, error) {
+	namespace, table = strings.ToLower(namespace), strings.ToLower(table)
+
+	am, ok := db.(sql.AccessModer)
+	if !ok {
+		return 0, fmt.Errorf("database does not implement AccessModer")
+	}
+	if am.AccessMode() != sql.ReadWrite {
+		return 0, errors.New("cannot bulk load in a read-only transaction")
+	}
+
+	t.mu.RLock()
+	tbl, err := t.getTable(namespace, table)
+	readOnly := err == nil && t.i.namespaces[namespace].readOnly
+	t.mu.RUnlock()
+	if err != nil {
+		return 0, err
+	}
+	if readOnly {
+		return 0, fmt.Errorf(`%w: cannot bulk load into namespace "%s"`, engine.ErrNamespaceReadOnly, namespace)
+	}
+	// the table is copied so that it does not change while the rows are
+	// checked
+	tbl = tbl.Copy()
+
+	columns := make([]string, len(tbl.Columns))
+	for i, col := range tbl.Columns {
+		columns[i] = col.Name
+	}
+
+	var rowNum int
+	next := func() ([]any, bool, error) {
+		var row []any
+		select {
+		case <-ctx.Done():
+			return nil, false, ctx.Err()
+		case row, ok = <-rows:
+			if !ok {
+				return nil, false, nil
+			}
+		}
+		rowNum++
+
+		vals, err := bulkLoadRow(tbl, row)
+		if err != nil {
+			return nil, false, &engine.ValidationError{Namespace: namespace, Err: fmt.Errorf("row %d: %w", rowNum, err)}
+		}
+		return vals, true, nil
+	}
+
+	tx, err := db.BeginTx(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	copier, ok := tx.(sql.Copier)
+	if !ok {
+		return 0, errors.New("database does not support bulk loading")
+	}
+
+	n, err := copier.CopyRows(ctx, namespace, table, columns, next)
+	if err != nil {
+		return 0, err
+	}
+
+	return n, tx.Commit(ctx)
+}
+
+// bulkLoadRow checks that a row has a value of the type of each column of a
+// table, and returns the values to insert.
+func bulkLoadRow(tbl *engine.Table, row []any) ([]any, error) {
+	if len(row) != len(tbl.Columns) {
+		return nil, fmt.Errorf(`table "%s" has %d columns, but the row has %d values`, tbl.Name, len(tbl.Columns), len(row))
+	}
+
+	vals := make([]any, len(row))
+	for i, col := range tbl.Columns {
+		arg, err := coerceArg(row[i], col.DataType)
+		if err != nil {
+			return nil, fmt.Errorf(`column "%s": %w`, col.Name, err)
+		}
+
+		val, ok, err := newValueWithSoftCast(arg, col.DataType)
+		if err != nil {
+			return nil, fmt.Errorf(`column "%s": %w`, col.Name, err)
+		}
+		if !ok {
+			return nil, fmt.Errorf(`%w: column "%s" is of type %s, but the value is of type %s`, engine.ErrType, col.Name, col.DataType, val.Type())
+		}
+		if val.Null() && !col.Nullable {
+			return nil, fmt.Errorf(`column "%s" cannot be null`, col.Name)
+		}
+
+		vals[i] = val
+	}
+
+	return vals, nil
+}
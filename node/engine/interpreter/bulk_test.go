@@ -0,0 +1,49 @@
+package interpreter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kwilteam/kwil-db/core/types"
+	"github.com/kwilteam/kwil-db/node/engine"
+)
+
+func Test_BulkLoadRow(t *testing.T) {
+	numeric102, err := types.NewNumericType(10, 2)
+	require.NoError(t, err)
+
+	tbl := &engine.Table{
+		Name: "items",
+		Columns: []*engine.Column{
+			{Name: "id", DataType: types.IntType, IsPrimaryKey: true},
+			{Name: "name", DataType: types.TextType, Nullable: true},
+			{Name: "price", DataType: numeric102, Nullable: true},
+		},
+	}
+
+	vals, err := bulkLoadRow(tbl, []any{int32(1), "a", 1.5})
+	require.NoError(t, err)
+	require.Len(t, vals, 3)
+	require.Equal(t, int64(1), vals[0].(value).RawValue())
+	require.Equal(t, "a", vals[1].(value).RawValue())
+	require.Equal(t, "1.50", vals[2].(value).RawValue().(*types.Decimal).String())
+
+	// nulls take the type of their column
+	vals, err = bulkLoadRow(tbl, []any{int64(2), nil, nil})
+	require.NoError(t, err)
+	require.True(t, vals[1].(value).Null())
+	require.True(t, vals[2].(value).Type().Equals(numeric102))
+
+	_, err = bulkLoadRow(tbl, []any{int64(1), "a"})
+	require.Error(t, err)
+
+	_, err = bulkLoadRow(tbl, []any{"1", "a", nil})
+	require.ErrorIs(t, err, engine.ErrType)
+
+	_, err = bulkLoadRow(tbl, []any{nil, "a", nil})
+	require.ErrorContains(t, err, "cannot be null")
+
+	_, err = bulkLoadRow(tbl, []any{int64(1), "a", 1.234})
+	require.ErrorIs(t, err, engine.ErrTypeCoercionFailed)
+}
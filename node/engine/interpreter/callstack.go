@@ -0,0 +1,76 @@
+package interpreter
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// callFrame is a call to an action or extension method.
+type callFrame struct {
+	Namespace string
+	Action    string
+	ArgCount  int
+}
+
+// pushCallFrame returns the call stack with a call added to it. The call
+// stack that is passed is not modified, so that it can be shared by the
+// calls made from the same scope.
+func pushCallFrame(callStack []callFrame, namespace, action string, argCount int) []callFrame {
+	return append(slices.Clip(callStack), callFrame{
+		Namespace: namespace,
+		Action:    action,
+		ArgCount:  argCount,
+	})
+}
+
+// withCallStack adds the call stack to an error returned by a nested call,
+// so that the calls that led to it are not lost. Only the innermost call
+// adds it, since it has the full stack; the calls that return the error
+// after it leave it as is. Errors of calls that are not nested are returned
+// as they are.
+func withCallStack(callStack []callFrame, err error) error {
+	if err == nil || len(callStack) < 2 {
+		return err
+	}
+
+	var stackErr *callStackError
+	if errors.As(err, &stackErr) {
+		return err
+	}
+
+	return &callStackError{
+		callStack: slices.Clone(callStack),
+		err:       err,
+	}
+}
+
+// callStackError is an error returned by a nested call, along with the calls
+// that led to it.
+type callStackError struct {
+	callStack []callFrame
+	err       error
+}
+
+func (c *callStackError) Error() string {
+	return fmt.Errorf("call stack:\n%s\n%w", formatCallStack(c.callStack), c.err).Error()
+}
+
+func (c *callStackError) Unwrap() error {
+	return c.err
+}
+
+// formatCallStack formats a call stack with a line per call, starting with
+// the outermost call.
+func formatCallStack(callStack []callFrame) string {
+	lines := make([]string, len(callStack))
+	for i, frame := range callStack {
+		args := "args"
+		if frame.ArgCount == 1 {
+			args = "arg"
+		}
+		lines[i] = fmt.Sprintf("\t%d: %s.%s (%d %s)", i, frame.Namespace, frame.Action, frame.ArgCount, args)
+	}
+	return strings.Join(lines, "\n")
+}
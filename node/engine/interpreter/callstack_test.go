@@ -0,0 +1,53 @@
+package interpreter
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kwilteam/kwil-db/node/engine"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_WithCallStack(t *testing.T) {
+	errBoom := errors.New("boom")
+	a := pushCallFrame(nil, "main", "a", 0)
+	b := pushCallFrame(a, "main", "b", 1)
+	c := pushCallFrame(b, "ext", "c", 2)
+
+	// a call that is not nested has no call stack
+	require.Equal(t, errBoom, withCallStack(a, errBoom))
+
+	err := withCallStack(c, errBoom)
+	require.ErrorIs(t, err, errBoom)
+	require.Equal(t, "call stack:\n\t0: main.a (0 args)\n\t1: main.b (1 arg)\n\t2: ext.c (2 args)\nboom", err.Error())
+
+	// the calls that return the error keep the stack of the innermost call
+	require.Equal(t, err, withCallStack(b, err))
+
+	// pushing a frame does not modify the stack it was pushed on
+	d := pushCallFrame(b, "main", "d", 0)
+	require.Equal(t, "c", c[2].Action)
+	require.Equal(t, "d", d[2].Action)
+}
+
+func Test_TimeoutErr(t *testing.T) {
+	require.NoError(t, timeoutErr("main", "act", nil))
+
+	errBoom := errors.New("boom")
+	require.Equal(t, errBoom, timeoutErr("main", "act", errBoom))
+
+	// the call stack of a nested call is kept
+	err := timeoutErr("main", "a", withCallStack(pushCallFrame(pushCallFrame(nil, "main", "a", 0), "main", "b", 0), context.DeadlineExceeded))
+	var timeout *engine.StatementTimeoutError
+	require.ErrorAs(t, err, &timeout)
+	require.Equal(t, "a", timeout.Action)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.ErrorContains(t, err, "call stack:")
+
+	pgErr := errors.New("ERROR: canceling statement due to statement timeout (SQLSTATE 57014)")
+	require.ErrorIs(t, timeoutErr("main", "", pgErr), engine.ErrStatementTimeout)
+
+	// it is only wrapped once
+	require.Equal(t, err, timeoutErr("main", "b", err))
+}
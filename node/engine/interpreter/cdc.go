@@ -0,0 +1,403 @@
+package interpreter
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/kwilteam/kwil-db/common"
+	"github.com/kwilteam/kwil-db/core/log"
+	"github.com/kwilteam/kwil-db/extensions/hooks"
+	"github.com/kwilteam/kwil-db/node/engine"
+	"github.com/kwilteam/kwil-db/node/types/sql"
+	"github.com/kwilteam/kwil-db/node/wal"
+)
+
+// CDCEvent is a single row change made by an action, in a form suitable for
+// change data capture consumers.
+type CDCEvent struct {
+	// Namespace is the namespace of the changed table.
+	Namespace string
+	// Table is the name of the changed table.
+	Table string
+	// Operation is the kind of change.
+	Operation wal.Operation
+	// BeforeImage is the JSON encoded row prior to an UPDATE or DELETE.
+	// It is nil for an INSERT.
+	BeforeImage []byte
+	// AfterImage is the JSON encoded row after an INSERT or UPDATE.
+	// It is nil for a DELETE.
+	AfterImage []byte
+	// TxHash is the hash of the transaction that called the action.
+	TxHash []byte
+	// BlockHeight is the height of the block containing the transaction.
+	BlockHeight int64
+}
+
+// CDCPublisher publishes row changes to a downstream consumer.
+type CDCPublisher interface {
+	// Publish publishes a single row change. Events are published in the order
+	// that the changes were made, after the block that made them is committed.
+	// It is called by a single goroutine, separate from block execution, and an
+	// error is only logged.
+	Publish(event CDCEvent) error
+}
+
+// cdcCaptureTrigger is the name of the trigger that stages row changes for CDC.
+const cdcCaptureTrigger = "kwild_cdc_capture"
+
+// cdcEndBlockHook is the name of the end block hook that collects the row
+// changes made by the block.
+const cdcEndBlockHook = "cdc_changes"
+
+func init() {
+	err := hooks.RegisterEndBlockHook(cdcEndBlockHook, collectChanges)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// collectChanges collects the row changes made by the block at the end of
+// each block.
+func collectChanges(ctx context.Context, app *common.App, block *common.BlockContext) error {
+	interp, ok := app.Engine.(*ThreadSafeInterpreter)
+	if !ok {
+		return nil
+	}
+
+	return interp.CollectChanges(ctx, app.DB)
+}
+
+// cdcCapture publishes the row changes made by actions.
+//
+// Changes are staged by a trigger on each table while a top level action runs,
+// and the changes of an action that fails are discarded. The staged changes
+// are collected at the end of each block, in the block's transaction, and are
+// only used to invalidate the read cache, and queued to be published, once the
+// block is committed. Changes staged outside of a block, or by end block hooks
+// that run after the collection, are collected with the next block.
+//
+// Queued changes are published by a separate goroutine, so that a slow or
+// failing publisher never holds up block execution.
+type cdcCapture struct {
+	// publisher is nil if changes are only captured to invalidate the read
+	// cache.
+	publisher CDCPublisher
+	logger    log.Logger
+	// readCache is invalidated by the changes to the namespaces it caches, if
+	// enabled.
+	readCache *readCache
+
+	mu sync.Mutex
+	// pending are the changes collected from the block being executed.
+	pending []CDCEvent
+	// queued are the committed changes waiting to be published.
+	queued []CDCEvent
+
+	// notify wakes the publishing goroutine when changes are queued.
+	notify   chan struct{}
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+// newCDCCapture creates the capture of row changes, and starts publishing
+// them if there is a publisher.
+func newCDCCapture(publisher CDCPublisher, logger log.Logger, readCache *readCache) *cdcCapture {
+	c := &cdcCapture{
+		publisher: publisher,
+		logger:    logger,
+		readCache: readCache,
+		notify:    make(chan struct{}, 1),
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+
+	if publisher == nil {
+		close(c.done)
+		return c
+	}
+
+	go c.publish()
+	return c
+}
+
+// createCDCTrigger creates the trigger that stages the row changes of a table.
+func createCDCTrigger(ctx context.Context, db sql.DB, namespace, table string) error {
+	return execute(ctx, db, fmt.Sprintf(`CREATE OR REPLACE TRIGGER %s AFTER INSERT OR UPDATE OR DELETE ON %s.%s
+	FOR EACH ROW EXECUTE FUNCTION kwild_engine.capture_change()`, cdcCaptureTrigger, namespace, table))
+}
+
+// createCDCTriggers creates the CDC trigger on every table. It is used when a
+// publisher is configured for a database whose tables were created without one.
+func createCDCTriggers(ctx context.Context, db sql.DB, namespaces map[string]*namespace) error {
+	for nsName, ns := range namespaces {
+		// the info namespace only has views
+		if nsName == engine.InfoNamespace {
+			continue
+		}
+
+		for tblName := range ns.tables {
+			if err := createCDCTrigger(ctx, db, nsName, tblName); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// start enables capturing changes for the rest of the transaction, recording
+// the transaction and block that make them. It returns the id of the last
+// staged change, so that the changes of a failed action can be discarded.
+func (c *cdcCapture) start(engCtx *common.EngineContext, db sql.DB) (marker int64, err error) {
+	var txHash string
+	var height string
+	if !engCtx.InvalidTxCtx {
+		// the tx id is the hex encoded tx hash
+		txHash = engCtx.TxContext.TxID
+		if engCtx.TxContext.BlockContext != nil {
+			height = strconv.FormatInt(engCtx.TxContext.BlockContext.Height, 10)
+		}
+	}
+
+	ctx := engCtx.TxContext.Ctx
+	err = execute(ctx, db, `SELECT set_config('kwild.cdc_capture', 'on', true),
+		set_config('kwild.cdc_tx_hash', $1, true), set_config('kwild.cdc_block_height', $2, true)`, txHash, height)
+	if err != nil {
+		return 0, err
+	}
+
+	err = queryRowFunc(ctx, db, `SELECT COALESCE(MAX(id), 0) FROM kwild_engine.cdc_changes`, []any{&marker}, func() error { return nil })
+	return marker, err
+}
+
+// finish disables capturing changes. If the action failed, the changes it
+// staged after marker are discarded.
+func (c *cdcCapture) finish(ctx context.Context, db sql.DB, marker int64, failed bool) error {
+	err := execute(ctx, db, `SELECT set_config('kwild.cdc_capture', 'off', true)`)
+	if err != nil || !failed {
+		return err
+	}
+
+	return execute(ctx, db, `DELETE FROM kwild_engine.cdc_changes WHERE id > $1`, marker)
+}
+
+// collect drains the staged changes, which are held until the transaction is
+// committed or rolled back.
+func (c *cdcCapture) collect(ctx context.Context, db sql.DB) error {
+	var events []CDCEvent
+	var namespace, table, operation string
+	var before, after []byte
+	var txHash *string
+	var height *int64
+	err := queryRowFunc(ctx, db, `WITH drained AS (
+		DELETE FROM kwild_engine.cdc_changes RETURNING id, namespace, table_name, operation,
+			convert_to(before_image::text, 'UTF8') AS before_image, convert_to(after_image::text, 'UTF8') AS after_image,
+			tx_hash, block_height
+	)
+	SELECT namespace, table_name, operation, before_image, after_image, tx_hash, block_height FROM drained ORDER BY id`,
+		[]any{&namespace, &table, &operation, &before, &after, &txHash, &height}, func() error {
+			event := CDCEvent{
+				Namespace:   namespace,
+				Table:       table,
+				Operation:   wal.Operation(operation),
+				BeforeImage: bytes.Clone(before),
+				AfterImage:  bytes.Clone(after),
+			}
+			if txHash != nil {
+				event.TxHash, _ = hex.DecodeString(*txHash)
+			}
+			if height != nil {
+				event.BlockHeight = *height
+			}
+			events = append(events, event)
+			return nil
+		})
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.pending = append(c.pending, events...)
+	return nil
+}
+
+// commit invalidates the cached results of the namespaces changed by the
+// collected changes, and queues them to be published.
+func (c *cdcCapture) commit() {
+	c.mu.Lock()
+	events := c.pending
+	c.pending = nil
+	if c.publisher != nil {
+		c.queued = append(c.queued, events...)
+	}
+	c.mu.Unlock()
+
+	if c.readCache != nil {
+		c.readCache.commit(events)
+	}
+	if c.publisher == nil || len(events) == 0 {
+		return
+	}
+
+	select {
+	case c.notify <- struct{}{}:
+	default: // the publisher has already been woken
+	}
+}
+
+// publish publishes the queued changes until the capture is stopped.
+func (c *cdcCapture) publish() {
+	defer close(c.done)
+
+	for {
+		select {
+		case <-c.notify:
+			c.publishQueued()
+		case <-c.stop:
+			// the changes of blocks committed before stopping are still
+			// published
+			c.publishQueued()
+			return
+		}
+	}
+}
+
+// publishQueued publishes the queued changes in order. A change that fails to
+// publish is logged and skipped.
+func (c *cdcCapture) publishQueued() {
+	c.mu.Lock()
+	events := c.queued
+	c.queued = nil
+	c.mu.Unlock()
+
+	for _, event := range events {
+		if err := c.publisher.Publish(event); err != nil {
+			c.logger.Errorf("failed to publish CDC event for %s.%s at height %d: %v", event.Namespace, event.Table, event.BlockHeight, err)
+		}
+	}
+}
+
+// close stops publishing changes once the queued changes are published.
+func (c *cdcCapture) close() {
+	c.stopOnce.Do(func() { close(c.stop) })
+	<-c.done
+}
+
+// rollback discards the collected changes.
+func (c *cdcCapture) rollback() {
+	c.mu.Lock()
+	c.pending = nil
+	c.mu.Unlock()
+
+	if c.readCache != nil {
+		c.readCache.rollback()
+	}
+}
+
+// CollectChanges collects the row changes staged by the actions of the
+// block's transaction. They are published once the block is committed. It is
+// called by an end block hook, and only needs to be called directly by tests.
+func (t *ThreadSafeInterpreter) CollectChanges(ctx context.Context, db sql.DB) error {
+	if t.i.cdc == nil {
+		return nil
+	}
+	if am, ok := db.(sql.AccessModer); !ok || am.AccessMode() != sql.ReadWrite {
+		return engine.ErrCannotMutateState
+	}
+
+	return t.i.cdc.collect(ctx, db)
+}
+
+// BlockCommitted queues the row changes collected from the committed block to
+// be published, and invalidates the cached results that they made stale.
+func (t *ThreadSafeInterpreter) BlockCommitted() {
+	if t.i.cdc != nil {
+		t.i.cdc.commit()
+	}
+}
+
+// BlockRolledBack discards the row changes collected from the block that was
+// rolled back.
+func (t *ThreadSafeInterpreter) BlockRolledBack() {
+	if t.i.cdc != nil {
+		t.i.cdc.rollback()
+	}
+}
+
+// StopCDCPublisher stops publishing row changes, after publishing those of the
+// blocks that were already committed.
+func (t *ThreadSafeInterpreter) StopCDCPublisher() error {
+	if t.i.cdc != nil {
+		t.i.cdc.close()
+	}
+	return nil
+}
+
+// KafkaProducer sends a message to a Kafka topic. It is implemented by an
+// adapter around a synchronous Kafka client, such as a sarama.SyncProducer:
+//
+//	func (p *saramaProducer) SendMessage(topic string, key, value []byte) error {
+//		_, _, err := p.SyncProducer.SendMessage(&sarama.ProducerMessage{
+//			Topic: topic,
+//			Key:   sarama.ByteEncoder(key),
+//			Value: sarama.ByteEncoder(value),
+//		})
+//		return err
+//	}
+type KafkaProducer interface {
+	SendMessage(topic string, key, value []byte) error
+}
+
+// KafkaCDCPublisher publishes CDC events to a Kafka topic as JSON messages.
+// Messages are keyed by namespace and table, so that the changes to a table are
+// kept in order within a partition.
+type KafkaCDCPublisher struct {
+	producer KafkaProducer
+	topic    string
+}
+
+// NewKafkaCDCPublisher creates a CDC publisher that sends events to the topic
+// using the producer.
+func NewKafkaCDCPublisher(producer KafkaProducer, topic string) *KafkaCDCPublisher {
+	return &KafkaCDCPublisher{
+		producer: producer,
+		topic:    topic,
+	}
+}
+
+// kafkaCDCMessage is the JSON encoding of a CDCEvent. The row images are
+// embedded as JSON rather than base64 encoded bytes.
+type kafkaCDCMessage struct {
+	Namespace   string          `json:"namespace"`
+	Table       string          `json:"table"`
+	Operation   wal.Operation   `json:"operation"`
+	BeforeImage json.RawMessage `json:"before,omitempty"`
+	AfterImage  json.RawMessage `json:"after,omitempty"`
+	TxHash      string          `json:"tx_hash"`
+	BlockHeight int64           `json:"block_height"`
+}
+
+// Publish sends the event to the publisher's topic.
+func (k *KafkaCDCPublisher) Publish(event CDCEvent) error {
+	msg, err := json.Marshal(&kafkaCDCMessage{
+		Namespace:   event.Namespace,
+		Table:       event.Table,
+		Operation:   event.Operation,
+		BeforeImage: event.BeforeImage,
+		AfterImage:  event.AfterImage,
+		TxHash:      hex.EncodeToString(event.TxHash),
+		BlockHeight: event.BlockHeight,
+	})
+	if err != nil {
+		return err
+	}
+
+	return k.producer.SendMessage(k.topic, []byte(event.Namespace+"."+event.Table), msg)
+}
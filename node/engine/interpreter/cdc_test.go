@@ -0,0 +1,83 @@
+package interpreter
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kwilteam/kwil-db/core/log"
+	"github.com/kwilteam/kwil-db/node/wal"
+)
+
+type mockKafkaProducer struct {
+	topic      string
+	key, value []byte
+}
+
+func (m *mockKafkaProducer) SendMessage(topic string, key, value []byte) error {
+	m.topic, m.key, m.value = topic, key, value
+	return nil
+}
+
+func Test_KafkaCDCPublisher(t *testing.T) {
+	producer := &mockKafkaProducer{}
+	pub := NewKafkaCDCPublisher(producer, "kwil.changes")
+
+	err := pub.Publish(CDCEvent{
+		Namespace:   "main",
+		Table:       "items",
+		Operation:   wal.OperationUpdate,
+		BeforeImage: []byte(`{"id": 1, "name": "a"}`),
+		AfterImage:  []byte(`{"id": 1, "name": "b"}`),
+		TxHash:      []byte{0xab, 0xcd},
+		BlockHeight: 10,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, "kwil.changes", producer.topic)
+	require.Equal(t, "main.items", string(producer.key))
+	require.JSONEq(t, `{
+		"namespace": "main",
+		"table": "items",
+		"operation": "UPDATE",
+		"before": {"id": 1, "name": "a"},
+		"after": {"id": 1, "name": "b"},
+		"tx_hash": "abcd",
+		"block_height": 10
+	}`, string(producer.value))
+}
+
+type failingCDCPublisher struct {
+	events []CDCEvent
+}
+
+func (f *failingCDCPublisher) Publish(event CDCEvent) error {
+	f.events = append(f.events, event)
+	return errors.New("unavailable")
+}
+
+func Test_CDCCapturePublishing(t *testing.T) {
+	pub := &failingCDCPublisher{}
+	c := newCDCCapture(pub, log.DiscardLogger, nil)
+
+	events := []CDCEvent{
+		{Namespace: "main", Table: "items", Operation: wal.OperationInsert, BlockHeight: 1},
+		{Namespace: "main", Table: "items", Operation: wal.OperationDelete, BlockHeight: 1},
+	}
+
+	// the changes of a block that is rolled back are never published
+	c.pending = []CDCEvent{{Namespace: "main", Table: "items", Operation: wal.OperationUpdate}}
+	c.rollback()
+
+	// changes are published in order after the block is committed, and a
+	// failure to publish one does not stop the others
+	c.pending = slices.Clone(events)
+	c.commit()
+	c.close()
+	require.Equal(t, events, pub.events)
+
+	// closing is idempotent
+	c.close()
+}
@@ -0,0 +1,189 @@
+package interpreter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/kwilteam/kwil-db/node/engine"
+)
+
+// CircuitState is the state of the circuit of an extension.
+type CircuitState string
+
+const (
+	// CircuitClosed allows calls to the extension.
+	CircuitClosed CircuitState = "closed"
+	// CircuitOpen rejects calls to the extension without attempting them.
+	CircuitOpen CircuitState = "open"
+	// CircuitHalfOpen allows a limited number of test calls to the extension,
+	// which decide whether the circuit closes or opens again.
+	CircuitHalfOpen CircuitState = "half-open"
+)
+
+// CircuitBreaker stops calling the methods of an extension whose calls keep
+// failing, e.g. because the service backing it is down. Failures are tracked
+// per extension alias. After FailureThreshold consecutive failures, the circuit
+// opens, and calls fail with engine.ErrCircuitOpen. After ResetTimeout, the
+// circuit is half-open, and up to HalfOpenMaxCalls test calls are attempted. If
+// they all succeed, the circuit closes; if any fails, it opens again.
+//
+// Since the circuit opens based on the calls made to the node and on the local
+// clock, nodes disagree on whether a call fails. It is therefore only applied
+// to read-only calls, which are not part of consensus: calls that can change
+// state, such as those made while executing blocks, always call the
+// extension. Errors caused by the caller, rather than the extension, do not
+// count as failures.
+type CircuitBreaker struct {
+	FailureThreshold int
+	ResetTimeout     time.Duration
+	HalfOpenMaxCalls int
+
+	// now returns the current time. It is time.Now, other than in tests.
+	now func() time.Time
+
+	mu       sync.Mutex
+	circuits map[string]*circuit // keyed by extension alias
+}
+
+// circuit is the state of the circuit of a single extension.
+type circuit struct {
+	state CircuitState
+	// failures is the number of consecutive failures while closed.
+	failures int
+	// openedAt is when the circuit last opened.
+	openedAt time.Time
+	// halfOpenCalls and halfOpenSuccesses count the test calls made and
+	// succeeded while half-open.
+	halfOpenCalls     int
+	halfOpenSuccesses int
+}
+
+// NewCircuitBreaker creates a circuit breaker for extension calls.
+func NewCircuitBreaker(failureThreshold int, resetTimeout time.Duration, halfOpenMaxCalls int) *CircuitBreaker {
+	return &CircuitBreaker{
+		FailureThreshold: max(failureThreshold, 1),
+		ResetTimeout:     resetTimeout,
+		HalfOpenMaxCalls: max(halfOpenMaxCalls, 1),
+		now:              time.Now,
+		circuits:         make(map[string]*circuit),
+	}
+}
+
+// State returns the state of the circuit of an extension.
+func (c *CircuitBreaker) State(alias string) CircuitState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.circuit(alias).state
+}
+
+// circuit returns the circuit of an extension, moving it from open to half-open
+// if the reset timeout has passed. c.mu must be held.
+func (c *CircuitBreaker) circuit(alias string) *circuit {
+	circ, ok := c.circuits[alias]
+	if !ok {
+		circ = &circuit{state: CircuitClosed}
+		c.circuits[alias] = circ
+	}
+
+	if circ.state == CircuitOpen && c.now().Sub(circ.openedAt) >= c.ResetTimeout {
+		circ.state = CircuitHalfOpen
+		circ.halfOpenCalls = 0
+		circ.halfOpenSuccesses = 0
+	}
+
+	return circ
+}
+
+// allow returns engine.ErrCircuitOpen if a call to an extension may not be
+// attempted.
+func (c *CircuitBreaker) allow(alias string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	circ := c.circuit(alias)
+	switch circ.state {
+	case CircuitOpen:
+		return fmt.Errorf(`%w: extension "%s"`, engine.ErrCircuitOpen, alias)
+	case CircuitHalfOpen:
+		if circ.halfOpenCalls >= c.HalfOpenMaxCalls {
+			return fmt.Errorf(`%w: extension "%s"`, engine.ErrCircuitOpen, alias)
+		}
+		circ.halfOpenCalls++
+	}
+
+	return nil
+}
+
+// callerErrors are the errors caused by the caller of an extension, e.g. by
+// passing invalid arguments or lacking a privilege. The extension worked, so
+// they do not count as failures.
+var callerErrors = []error{
+	context.Canceled,
+	engine.ErrType,
+	engine.ErrReturnShape,
+	engine.ErrArithmetic,
+	engine.ErrCast,
+	engine.ErrInvalidNull,
+	engine.ErrIndexOutOfBounds,
+	engine.ErrActionInvocation,
+	engine.ErrUnknownAction,
+	engine.ErrUnknownTable,
+	engine.ErrUnknownColumn,
+	engine.ErrNamespaceNotFound,
+	engine.ErrCannotMutateState,
+	engine.ErrIllegalFunctionUsage,
+	engine.ErrActionOwnerOnly,
+	engine.ErrActionPrivate,
+	engine.ErrActionSystemOnly,
+	engine.ErrDoesNotHavePrivilege,
+}
+
+// isCallerError returns true if err was caused by the caller of an extension.
+func isCallerError(err error) bool {
+	for _, callerErr := range callerErrors {
+		if errors.Is(err, callerErr) {
+			return true
+		}
+	}
+	return false
+}
+
+// record records the outcome of an attempted call to an extension. Errors
+// caused by the caller are recorded as successes.
+func (c *CircuitBreaker) record(alias string, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if isCallerError(err) {
+		err = nil
+	}
+
+	circ := c.circuit(alias)
+	switch circ.state {
+	case CircuitClosed:
+		if err == nil {
+			circ.failures = 0
+			return
+		}
+		circ.failures++
+		if circ.failures >= c.FailureThreshold {
+			circ.state = CircuitOpen
+			circ.openedAt = c.now()
+		}
+	case CircuitHalfOpen:
+		if err != nil {
+			circ.state = CircuitOpen
+			circ.openedAt = c.now()
+			return
+		}
+		circ.halfOpenSuccesses++
+		if circ.halfOpenSuccesses >= c.HalfOpenMaxCalls {
+			circ.state = CircuitClosed
+			circ.failures = 0
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package interpreter
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kwilteam/kwil-db/node/engine"
+)
+
+func Test_CircuitBreaker(t *testing.T) {
+	now := time.Unix(1000, 0)
+	cb := NewCircuitBreaker(3, time.Minute, 2)
+	cb.now = func() time.Time { return now }
+
+	errFailed := errors.New("service unavailable")
+	call := func(alias string, err error) error {
+		if err := cb.allow(alias); err != nil {
+			return err
+		}
+		cb.record(alias, err)
+		return err
+	}
+
+	// a success resets the consecutive failures
+	require.ErrorIs(t, call("ext", errFailed), errFailed)
+	require.ErrorIs(t, call("ext", errFailed), errFailed)
+	require.NoError(t, call("ext", nil))
+	require.ErrorIs(t, call("ext", errFailed), errFailed)
+	require.ErrorIs(t, call("ext", errFailed), errFailed)
+	require.Equal(t, CircuitClosed, cb.State("ext"))
+
+	// the third consecutive failure opens the circuit
+	require.ErrorIs(t, call("ext", errFailed), errFailed)
+	require.Equal(t, CircuitOpen, cb.State("ext"))
+	require.ErrorIs(t, call("ext", nil), engine.ErrCircuitOpen)
+
+	// errors caused by the caller do not count as failures
+	require.NoError(t, call("third", nil))
+	for range 3 {
+		require.ErrorIs(t, call("third", fmt.Errorf("%w: bad argument", engine.ErrType)), engine.ErrType)
+	}
+	require.Equal(t, CircuitClosed, cb.State("third"))
+
+	// circuits are per extension
+	require.Equal(t, CircuitClosed, cb.State("other"))
+	require.NoError(t, call("other", nil))
+
+	// after the reset timeout, a failed test call opens the circuit again
+	now = now.Add(time.Minute)
+	require.Equal(t, CircuitHalfOpen, cb.State("ext"))
+	require.ErrorIs(t, call("ext", errFailed), errFailed)
+	require.Equal(t, CircuitOpen, cb.State("ext"))
+	require.ErrorIs(t, call("ext", nil), engine.ErrCircuitOpen)
+
+	// only HalfOpenMaxCalls test calls are attempted while half-open
+	now = now.Add(time.Minute)
+	require.NoError(t, cb.allow("ext"))
+	require.NoError(t, cb.allow("ext"))
+	require.ErrorIs(t, cb.allow("ext"), engine.ErrCircuitOpen)
+
+	// the circuit closes once the test calls succeed
+	cb.record("ext", nil)
+	require.Equal(t, CircuitHalfOpen, cb.State("ext"))
+	cb.record("ext", nil)
+	require.Equal(t, CircuitClosed, cb.State("ext"))
+	require.NoError(t, call("ext", nil))
+}
@@ -0,0 +1,209 @@
+package interpreter
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+
+	"github.com/kwilteam/kwil-db/core/types"
+	"github.com/kwilteam/kwil-db/node/engine"
+)
+
+// decimalType is the reflect type of types.Decimal.
+var decimalType = reflect.TypeOf(types.Decimal{})
+
+// coerceArg converts an argument of an action to the type of its parameter,
+// if the argument is a Go value that the parameter can hold exactly:
+//   - integers of any size, floats, and decimals to INT8
+//   - integers of any size, floats, and decimals to NUMERIC
+//   - strings, including named string types, to TEXT
+//   - byte slices and arrays, including named ones, to BYTEA
+//
+// Slices are converted element by element to arrays of these types. Arguments
+// that are already of the parameter's type, or that cannot be converted to
+// it, are returned as they are. If a conversion would lose information, e.g.
+// because a float has a fractional part, it returns
+// engine.ErrTypeCoercionFailed.
+func coerceArg(arg any, dt *types.DataType) (any, error) {
+	if arg == nil {
+		return nil, nil
+	}
+	if v, err := newValue(arg); err == nil && v.Type().Equals(dt) {
+		return arg, nil
+	}
+
+	rv := reflect.ValueOf(arg)
+	if !dt.IsArray {
+		res, ok, err := coerceScalar(rv, dt)
+		if err != nil || !ok {
+			return arg, err
+		}
+		return res, nil
+	}
+
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil, nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Slice {
+		return arg, nil
+	}
+	if rv.IsNil() {
+		return nil, nil
+	}
+
+	scalar := dt.Copy()
+	scalar.IsArray = false
+
+	elems := make([]any, rv.Len())
+	for i := range elems {
+		res, ok, err := coerceScalar(rv.Index(i), scalar)
+		if err != nil {
+			return nil, fmt.Errorf("element %d: %w", i+1, err)
+		}
+		if !ok {
+			return arg, nil
+		}
+		elems[i] = res
+	}
+
+	switch scalar.Name {
+	case types.IntType.Name:
+		return pointers[int64](elems), nil
+	case types.NumericStr:
+		decs := make([]*types.Decimal, len(elems))
+		for i, e := range elems {
+			if e != nil {
+				decs[i] = e.(*types.Decimal)
+			}
+		}
+		return decs, nil
+	case types.TextType.Name:
+		return pointers[string](elems), nil
+	case types.ByteaType.Name:
+		return pointers[[]byte](elems), nil
+	default:
+		return arg, nil
+	}
+}
+
+// pointers converts the coerced elements of a slice to pointers, so that its
+// nil elements are kept as nulls.
+func pointers[T any](elems []any) []*T {
+	res := make([]*T, len(elems))
+	for i, e := range elems {
+		if e != nil {
+			v := e.(T)
+			res[i] = &v
+		}
+	}
+	return res
+}
+
+// coerceScalar converts a value to a Go value of a scalar type, as described
+// by coerceArg. It returns false if it cannot be converted. Nil pointers are
+// converted to nil.
+func coerceScalar(rv reflect.Value, dt *types.DataType) (res any, ok bool, err error) {
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil, true, nil
+		}
+		rv = rv.Elem()
+	}
+
+	lossy := func() (any, bool, error) {
+		return nil, false, fmt.Errorf("%w: cannot convert %v (%s) to %s without losing information",
+			engine.ErrTypeCoercionFailed, rv.Interface(), rv.Type(), dt)
+	}
+
+	switch dt.Name {
+	case types.IntType.Name:
+		switch {
+		case rv.CanInt():
+			return rv.Int(), true, nil
+		case rv.CanUint():
+			u := rv.Uint()
+			if u > math.MaxInt64 {
+				return lossy()
+			}
+			return int64(u), true, nil
+		case rv.CanFloat():
+			f := rv.Float()
+			// 2^63 is the smallest float that is too large, since MaxInt64
+			// cannot be represented as a float
+			if math.IsNaN(f) || math.IsInf(f, 0) || f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+				return lossy()
+			}
+			return int64(f), true, nil
+		case rv.Type() == decimalType:
+			d := rv.Interface().(types.Decimal)
+			if d.NaN() || d.Inf() {
+				return lossy()
+			}
+			i, err := d.Int64()
+			if err != nil {
+				return lossy()
+			}
+			if c, err := types.NewDecimalFromInt(i).Cmp(&d); err != nil || c != 0 {
+				return lossy()
+			}
+			return i, true, nil
+		}
+	case types.NumericStr:
+		var s string
+		switch {
+		case rv.CanInt():
+			s = strconv.FormatInt(rv.Int(), 10)
+		case rv.CanUint():
+			s = strconv.FormatUint(rv.Uint(), 10)
+		case rv.CanFloat():
+			f := rv.Float()
+			if math.IsNaN(f) || math.IsInf(f, 0) {
+				return lossy()
+			}
+			s = strconv.FormatFloat(f, 'f', -1, rv.Type().Bits())
+		case rv.Type() == decimalType:
+			d := rv.Interface().(types.Decimal)
+			if d.NaN() || d.Inf() {
+				return lossy()
+			}
+			s = d.String()
+		default:
+			return nil, false, nil
+		}
+
+		exact, err := types.ParseDecimal(s)
+		if err != nil {
+			return lossy()
+		}
+		if dt.Metadata[0] == 0 {
+			// the parameter does not have a precision
+			return exact, true, nil
+		}
+		// the value is lossy if it does not fit the precision and scale of the
+		// parameter, or if it is rounded to fit them
+		d, err := types.ParseDecimalExplicit(s, dt.Metadata[0], dt.Metadata[1])
+		if err != nil {
+			return lossy()
+		}
+		if c, err := d.Cmp(exact); err != nil || c != 0 {
+			return lossy()
+		}
+		return d, true, nil
+	case types.TextType.Name:
+		if rv.Kind() == reflect.String {
+			return rv.String(), true, nil
+		}
+	case types.ByteaType.Name:
+		if (rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array) && rv.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, rv.Len())
+			reflect.Copy(reflect.ValueOf(b), rv)
+			return b, true, nil
+		}
+	}
+
+	return nil, false, nil
+}
@@ -0,0 +1,127 @@
+package interpreter
+
+import (
+	"math"
+	"testing"
+
+	"github.com/kwilteam/kwil-db/core/types"
+	"github.com/kwilteam/kwil-db/node/engine"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CoerceArg(t *testing.T) {
+	type name string
+	type blob []byte
+
+	numeric := types.NumericType
+	numeric102, err := types.NewNumericType(10, 2)
+	require.NoError(t, err)
+	numeric102Array := numeric102.Copy()
+	numeric102Array.IsArray = true
+
+	ptr := func(i int32) *int32 { return &i }
+	i64 := func(i int64) *int64 { return &i }
+	str := func(s string) *string { return &s }
+	dec := types.MustParseDecimal
+
+	tests := []struct {
+		name string
+		arg  any
+		dt   *types.DataType
+		want any
+		err  bool
+	}{
+		// integers and floats widen to INT8
+		{name: "int8 to int8", arg: int8(-2), dt: types.IntType, want: int64(-2)},
+		{name: "int16 to int8", arg: int16(3), dt: types.IntType, want: int64(3)},
+		{name: "int32 to int8", arg: int32(math.MaxInt32), dt: types.IntType, want: int64(math.MaxInt32)},
+		{name: "uint to int8", arg: uint(5), dt: types.IntType, want: int64(5)},
+		{name: "uint8 to int8", arg: uint8(6), dt: types.IntType, want: int64(6)},
+		{name: "uint16 to int8", arg: uint16(7), dt: types.IntType, want: int64(7)},
+		{name: "uint32 to int8", arg: uint32(math.MaxUint32), dt: types.IntType, want: int64(math.MaxUint32)},
+		{name: "uint64 to int8", arg: uint64(math.MaxInt64), dt: types.IntType, want: int64(math.MaxInt64)},
+		{name: "float32 to int8", arg: float32(-8), dt: types.IntType, want: int64(-8)},
+		{name: "float64 to int8", arg: float64(9), dt: types.IntType, want: int64(9)},
+		{name: "decimal to int8", arg: dec("10.00"), dt: types.IntType, want: int64(10)},
+		{name: "int32 pointer to int8", arg: ptr(11), dt: types.IntType, want: int64(11)},
+		{name: "nil int32 pointer to int8", arg: (*int32)(nil), dt: types.IntType, want: (*int32)(nil)},
+
+		// integers, floats and decimals widen to NUMERIC
+		{name: "int32 to numeric", arg: int32(12), dt: numeric102, want: "12.00"},
+		{name: "uint64 to numeric", arg: uint64(13), dt: numeric102, want: "13.00"},
+		{name: "float32 to numeric", arg: float32(1.5), dt: numeric102, want: "1.50"},
+		{name: "float64 to numeric", arg: float64(-14.25), dt: numeric102, want: "-14.25"},
+		{name: "decimal to numeric", arg: dec("15.5"), dt: numeric102, want: "15.50"},
+		{name: "float64 to numeric without precision", arg: float64(16.125), dt: numeric, want: "16.125"},
+
+		// named strings and byte slices
+		{name: "named string to text", arg: name("satoshi"), dt: types.TextType, want: "satoshi"},
+		{name: "named byte slice to blob", arg: blob{1, 2}, dt: types.ByteaType, want: []byte{1, 2}},
+		{name: "byte array to blob", arg: [3]byte{3, 4, 5}, dt: types.ByteaType, want: []byte{3, 4, 5}},
+
+		// slices are converted element by element
+		{name: "int32 slice to int8 array", arg: []int32{1, 2}, dt: types.IntArrayType, want: []*int64{i64(1), i64(2)}},
+		{name: "int32 pointer slice to int8 array", arg: []*int32{ptr(1), nil}, dt: types.IntArrayType, want: []*int64{i64(1), nil}},
+		{name: "float64 slice to numeric array", arg: []float64{1, 2.5}, dt: numeric102Array, want: []string{"1.00", "2.50"}},
+		{name: "named string slice to text array", arg: []name{"a", "b"}, dt: types.TextArrayType, want: []*string{str("a"), str("b")}},
+		{name: "named byte slices to blob array", arg: []blob{{1}}, dt: types.ByteaArrayType, want: []*[]byte{{1}}},
+		{name: "empty slice to int8 array", arg: []int32{}, dt: types.IntArrayType, want: []*int64{}},
+
+		// values of the parameter's type, and values that cannot be converted,
+		// are not changed
+		{name: "int64 to int8", arg: int64(1), dt: types.IntType, want: int64(1)},
+		{name: "int to int8", arg: int(1), dt: types.IntType, want: int(1)},
+		{name: "bool to int8", arg: true, dt: types.IntType, want: true},
+		{name: "int32 to text", arg: int32(1), dt: types.TextType, want: int32(1)},
+		{name: "bool slice to int8 array", arg: []bool{true}, dt: types.IntArrayType, want: []bool{true}},
+
+		// conversions that would lose information
+		{name: "uint64 too large for int8", arg: uint64(math.MaxInt64) + 1, dt: types.IntType, err: true},
+		{name: "float64 with fraction to int8", arg: 1.5, dt: types.IntType, err: true},
+		{name: "float32 with fraction to int8", arg: float32(0.25), dt: types.IntType, err: true},
+		{name: "float64 too large for int8", arg: math.Pow(2, 63), dt: types.IntType, err: true},
+		{name: "float64 too small for int8", arg: -math.Pow(2, 64), dt: types.IntType, err: true},
+		{name: "NaN to int8", arg: math.NaN(), dt: types.IntType, err: true},
+		{name: "infinity to int8", arg: math.Inf(1), dt: types.IntType, err: true},
+		{name: "decimal with fraction to int8", arg: dec("1.5"), dt: types.IntType, err: true},
+		{name: "float64 rounded by numeric scale", arg: 1.234, dt: numeric102, err: true},
+		{name: "decimal rounded by numeric scale", arg: dec("1.001"), dt: numeric102, err: true},
+		{name: "int64 too large for numeric precision", arg: int32(123456789), dt: numeric102, err: true},
+		{name: "NaN to numeric", arg: math.NaN(), dt: numeric102, err: true},
+		{name: "infinity to numeric", arg: math.Inf(-1), dt: numeric102, err: true},
+		{name: "lossy slice element", arg: []float64{1, 1.5}, dt: types.IntArrayType, err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := coerceArg(tt.arg, tt.dt)
+			if tt.err {
+				require.ErrorIs(t, err, engine.ErrTypeCoercionFailed)
+				return
+			}
+			require.NoError(t, err)
+
+			// decimals are compared by their text, which includes their scale,
+			// and must have the precision of the parameter if it has one
+			switch got := got.(type) {
+			case *types.Decimal:
+				require.Equal(t, tt.want, got.String())
+			case []*types.Decimal:
+				strs := make([]string, len(got))
+				for i, d := range got {
+					strs[i] = d.String()
+				}
+				require.Equal(t, tt.want, strs)
+			default:
+				require.Equal(t, tt.want, got)
+				return
+			}
+
+			if tt.dt.Metadata[0] != 0 {
+				_, ok, err := newValueWithSoftCast(got, tt.dt)
+				require.NoError(t, err)
+				require.True(t, ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package interpreter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/kwilteam/kwil-db/core/types"
+	"github.com/kwilteam/kwil-db/node/engine"
+	"github.com/kwilteam/kwil-db/node/types/sql"
+)
+
+// configGet implements the config_get built-in. It returns the configuration
+// value of the current namespace with the given key, or null if it is not set.
+func (e *executionContext) configGet(key value) (value, error) {
+	if key.Null() {
+		return nil, errors.New("config_get: key cannot be null")
+	}
+
+	v, ok, err := getNamespaceConfig(e.engineCtx.TxContext.Ctx, e.db, e.scope.namespace, key.RawValue().(string))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return makeNull(types.TextType)
+	}
+
+	return makeText(v), nil
+}
+
+// configSet implements the config_set built-in. It sets the configuration
+// value of the current namespace with the given key. Since it writes to the
+// database, it cannot be used in calls that cannot change state.
+func (e *executionContext) configSet(key, val value) error {
+	if key.Null() {
+		return errors.New("config_set: key cannot be null")
+	}
+	if val.Null() {
+		return errors.New("config_set: value cannot be null")
+	}
+
+	if !e.canMutateState {
+		return fmt.Errorf(`%w: "config_set" cannot be used in calls that cannot change state`, engine.ErrCannotMutateState)
+	}
+
+	if err := e.checkNamespaceMutatbility(); err != nil {
+		return err
+	}
+
+	return setNamespaceConfig(e.engineCtx.TxContext.Ctx, e.db, e.scope.namespace, key.RawValue().(string), val.RawValue().(string))
+}
+
+// setNamespaceConfig stores a configuration value of a namespace.
+func setNamespaceConfig(ctx context.Context, db sql.DB, namespace, key, val string) error {
+	return execute(ctx, db, `INSERT INTO kwild_engine.namespace_config_kv (namespace, key, value) VALUES ($1, $2, $3)
+	ON CONFLICT (namespace, key) DO UPDATE SET value = $3`, namespace, key, val)
+}
+
+// getNamespaceConfig gets a configuration value of a namespace. It returns
+// false if the value is not set.
+func getNamespaceConfig(ctx context.Context, db sql.DB, namespace, key string) (string, bool, error) {
+	var val string
+	var found bool
+	err := queryRowFunc(ctx, db, `SELECT value FROM kwild_engine.namespace_config_kv WHERE namespace = $1 AND key = $2`,
+		[]any{&val}, func() error {
+			found = true
+			return nil
+		}, namespace, key)
+	if err != nil {
+		return "", false, err
+	}
+
+	return val, found, nil
+}
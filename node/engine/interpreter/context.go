@@ -3,6 +3,7 @@ package interpreter
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/decred/dcrd/container/lru"
 	"github.com/kwilteam/kwil-db/common"
@@ -36,6 +37,38 @@ type executionContext struct {
 	// This is used to prevent nested queries, which can cause
 	// a deadlock or unexpected behavior.
 	queryActive bool
+	// plans collects the query plans of the queries executed, if set.
+	plans *planCapture
+	// analysis collects the execution statistics of the queries executed, if
+	// set.
+	analysis *AnalyzeResult
+	// advised collects the query plans of the call for the query advisor, if
+	// it is enabled.
+	advised *advisedCall
+	// optimizerHints are the optimizer hints of the action being executed.
+	optimizerHints []*engine.OptimizerHint
+	// errorTypes are the error types that the action being executed can
+	// raise with raise_error.
+	errorTypes []*engine.ErrorType
+	// distinct de-duplicates the rows returned by the action being executed,
+	// if set.
+	distinct *distinctResults
+	// returning is true while the query of a RETURN statement is executed.
+	returning bool
+	// callStack is the chain of action and extension method calls being
+	// executed, starting with the outermost.
+	callStack []callFrame
+	// cursors are the cursors declared by the action being executed, keyed
+	// by name.
+	cursors map[string]*cursor
+	// createdAt is when the execution context of the call was created. It
+	// is kept by subscopes, so that action_elapsed_ms measures the whole
+	// call.
+	createdAt time.Time
+	// gasUsed is the number of statements the call has run against the
+	// database. It is shared with subscopes, so that it counts the
+	// statements of the actions the call calls.
+	gasUsed *uint64
 }
 
 // subscope creates a new subscope execution context.
@@ -51,9 +84,29 @@ func (e *executionContext) subscope(namespace string) *executionContext {
 		db:             e.db,
 		interpreter:    e.interpreter,
 		logs:           e.logs,
+		plans:          e.plans,
+		analysis:       e.analysis,
+		advised:        e.advised,
+		callStack:      e.callStack,
+		createdAt:      e.createdAt,
+		gasUsed:        e.gasUsed,
 	}
 }
 
+// checkCanceled returns an error if the context of the call has been
+// canceled. It is checked before each statement, so that an action does not
+// keep executing, e.g. in a loop that does not query the database, after
+// its caller has given up on it.
+func (e *executionContext) checkCanceled() error {
+	if e.engineCtx.TxContext == nil || e.engineCtx.TxContext.Ctx == nil {
+		return nil
+	}
+	if err := e.engineCtx.TxContext.Ctx.Err(); err != nil {
+		return fmt.Errorf("execution canceled: %w", err)
+	}
+	return nil
+}
+
 // checkPrivilege checks that the current user has a privilege,
 // and returns an error if they do not.
 func (e *executionContext) checkPrivilege(priv privilege) error {
@@ -152,34 +205,36 @@ func (e *executionContext) query(sql string, fn func(*row) error) error {
 	}
 	e.queryActive = true
 	defer func() { e.queryActive = false }()
+	*e.gasUsed++
 
-	generatedSQL, analyzed, args, err := e.prepareQuery(sql)
+	generatedSQL, analyzed, args, tableHints, err := e.prepareQuery(sql)
 	if err != nil {
 		return err
 	}
 
-	// get the scan values as well:
-	var scanValues []any
-	for _, field := range analyzed.Plan.Relation().Fields {
-		scalar, err := field.Scalar()
-		if err != nil {
-			return err
-		}
+	cols, scanValues, err := queryColumns(analyzed)
+	if err != nil {
+		return err
+	}
 
-		zVal, err := newZeroValue(scalar)
-		if err != nil {
-			return err
-		}
+	if e.returning && e.distinct != nil {
+		generatedSQL = e.distinct.wrap(generatedSQL, len(cols))
+	}
 
-		scanValues = append(scanValues, zVal)
+	hints := mergeOptimizerHints(e.optimizerHints, tableHints)
+	if err = setOptimizerHints(e.engineCtx.TxContext.Ctx, e.db, hints); err != nil {
+		return err
 	}
 
-	cols := make([]string, len(analyzed.Plan.Relation().Fields))
-	for i, field := range analyzed.Plan.Relation().Fields {
-		cols[i] = field.Name
+	if err = e.prepareBlockTime(generatedSQL); err != nil {
+		return err
 	}
 
-	return query(e.engineCtx.TxContext.Ctx, e.db, generatedSQL, scanValues, func() error {
+	if err = e.explain(generatedSQL, args); err != nil {
+		return err
+	}
+
+	err = query(e.engineCtx.TxContext.Ctx, e.db, generatedSQL, scanValues, func() error {
 		if len(scanValues) != len(cols) {
 			// should never happen, but just in case
 			return fmt.Errorf("node bug: scan values and columns are not the same length")
@@ -197,6 +252,56 @@ func (e *executionContext) query(sql string, fn func(*row) error) error {
 			Values:  vals,
 		})
 	}, args)
+	if err != nil {
+		return err
+	}
+
+	return resetOptimizerHints(e.engineCtx.TxContext.Ctx, e.db, hints)
+}
+
+// queryColumns returns the names of the columns of a query, and the values
+// to scan its rows into.
+func queryColumns(analyzed *logical.AnalyzedPlan) (cols []string, scanValues []any, err error) {
+	for _, field := range analyzed.Plan.Relation().Fields {
+		scalar, err := field.Scalar()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		zVal, err := newZeroValue(scalar)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		cols = append(cols, field.Name)
+		scanValues = append(scanValues, zVal)
+	}
+
+	return cols, scanValues, nil
+}
+
+// explain collects the plan of a query, if the plans of the call are being
+// captured or analyzed, or the query advisor is enabled.
+func (e *executionContext) explain(generatedSQL string, args []value) error {
+	if e.plans != nil {
+		if err := e.plans.explain(e.engineCtx.TxContext.Ctx, e.db, generatedSQL, args); err != nil {
+			return err
+		}
+	}
+
+	if e.analysis != nil {
+		if err := e.analysis.analyze(e.engineCtx.TxContext.Ctx, e.db, generatedSQL, args); err != nil {
+			return err
+		}
+	}
+
+	if e.advised != nil {
+		if err := e.advised.explain(e.engineCtx.TxContext.Ctx, e.db, generatedSQL, args); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 func fromScanValues(scanVals []any) ([]value, error) {
@@ -227,52 +332,62 @@ func (e *executionContext) getValues(names []string) ([]value, error) {
 // prepareQuery prepares a query for execution.
 // It will check the cache for a prepared statement, and if it does not exist,
 // it will parse the SQL, create a logical plan, and cache the statement.
-func (e *executionContext) prepareQuery(sql string) (pgSql string, plan *logical.AnalyzedPlan, args []value, err error) {
+func (e *executionContext) prepareQuery(sql string) (pgSql string, plan *logical.AnalyzedPlan, args []value, hints []*engine.OptimizerHint, err error) {
 	cached, ok := statementCache.get(e.scope.namespace, sql)
 	if ok {
+		if err := e.checkSensitiveTargetAccess(cached.sensitiveTargetColumns); err != nil {
+			return "", nil, nil, nil, err
+		}
+
 		// if it is mutating state it must be deterministic
 		if e.canMutateState {
 			values, err := e.getValues(cached.deterministicParams)
 			if err != nil {
-				return "", nil, nil, err
+				return "", nil, nil, nil, err
 			}
 
-			return cached.deterministicSQL, cached.deterministicPlan, values, nil
+			return cached.deterministicSQL, cached.deterministicPlan, values, cached.optimizerHints, nil
 		}
 		values, err := e.getValues(cached.nonDeterministicParams)
 		if err != nil {
-			return "", nil, nil, err
+			return "", nil, nil, nil, err
 		}
-		return cached.nonDeterministicSQL, cached.nonDeterministicPlan, values, nil
+		return cached.nonDeterministicSQL, cached.nonDeterministicPlan, values, cached.optimizerHints, nil
 	}
 
 	deterministicAST, err := getAST(sql)
 	if err != nil {
-		return "", nil, nil, err
+		return "", nil, nil, nil, err
 	}
 	nondeterministicAST, err := getAST(sql)
 	if err != nil {
-		return "", nil, nil, err
+		return "", nil, nil, nil, err
 	}
 
+	hints = e.tableOptimizerHints(deterministicAST)
+
+	sensitiveCols := e.sensitiveTargetColumns(deterministicAST)
+	e.restrictTableReads(deterministicAST)
+	e.restrictTableReads(nondeterministicAST)
+
 	deterministicPlan, err := makePlan(e, deterministicAST)
 	if err != nil {
-		return "", nil, nil, fmt.Errorf("%w: %w", engine.ErrQueryPlanner, err)
+		return "", nil, nil, nil, fmt.Errorf("%w: %w", engine.ErrQueryPlanner, err)
 	}
 
 	nonDeterministicPlan, err := makePlan(e, nondeterministicAST)
 	if err != nil {
-		return "", nil, nil, fmt.Errorf("%w: %w", engine.ErrQueryPlanner, err)
+		return "", nil, nil, nil, fmt.Errorf("%w: %w", engine.ErrQueryPlanner, err)
 	}
 
 	deterministicSQL, deterministicParams, err := pggenerate.GenerateSQL(deterministicAST, e.scope.namespace, e.getVariableType)
 	if err != nil {
-		return "", nil, nil, fmt.Errorf("%w: %w", engine.ErrPGGen, err)
+		return "", nil, nil, nil, fmt.Errorf("%w: %w", engine.ErrPGGen, err)
 	}
 
 	nonDeterministicSQL, nonDeterministicParams, err := pggenerate.GenerateSQL(nondeterministicAST, e.scope.namespace, e.getVariableType)
 	if err != nil {
-		return "", nil, nil, fmt.Errorf("%w: %w", engine.ErrPGGen, err)
+		return "", nil, nil, nil, fmt.Errorf("%w: %w", engine.ErrPGGen, err)
 	}
 
 	statementCache.set(e.scope.namespace, sql, &preparedStatement{
@@ -282,21 +397,27 @@ func (e *executionContext) prepareQuery(sql string) (pgSql string, plan *logical
 		nonDeterministicPlan:   nonDeterministicPlan,
 		nonDeterministicSQL:    nonDeterministicSQL,
 		nonDeterministicParams: nonDeterministicParams,
+		optimizerHints:         hints,
+		sensitiveTargetColumns: sensitiveCols,
 	})
 
+	if err := e.checkSensitiveTargetAccess(sensitiveCols); err != nil {
+		return "", nil, nil, nil, err
+	}
+
 	if e.canMutateState {
 		values, err := e.getValues(deterministicParams)
 		if err != nil {
-			return "", nil, nil, err
+			return "", nil, nil, nil, err
 		}
 
-		return deterministicSQL, deterministicPlan, values, nil
+		return deterministicSQL, deterministicPlan, values, hints, nil
 	}
 	values, err := e.getValues(nonDeterministicParams)
 	if err != nil {
-		return "", nil, nil, err
+		return "", nil, nil, nil, err
 	}
-	return nonDeterministicSQL, nonDeterministicPlan, values, nil
+	return nonDeterministicSQL, nonDeterministicPlan, values, hints, nil
 }
 
 // getAST gets the AST of a SQL statement.
@@ -376,6 +497,11 @@ type preparedStatement struct {
 	nonDeterministicPlan   *logical.AnalyzedPlan
 	nonDeterministicSQL    string
 	nonDeterministicParams []string
+	// optimizerHints are the optimizer hints of the tables used by the statement.
+	optimizerHints []*engine.OptimizerHint
+	// sensitiveTargetColumns are the sensitive columns of the table updated or
+	// deleted from that the statement reads.
+	sensitiveTargetColumns []string
 }
 
 // statementCache caches parsed statements.
@@ -416,6 +542,11 @@ type executable struct {
 	// It is a pointer to a slice because it may be nil;
 	// it is only set if the function is a precompile or action.
 	ExpectedArgs *[]*types.DataType
+	// Idempotent is true if the executable is an action declared with
+	// @idempotent.
+	Idempotent bool
+	// Test is true if the executable is an action declared with @test.
+	Test bool
 }
 
 type executableType string
@@ -540,6 +671,8 @@ func (e *executionContext) getVariable(name string) (value, error) {
 				return nil, engine.ErrInvalidTxCtx
 			}
 			return makeText(e.engineCtx.TxContext.Authenticator), nil
+		case sensitiveAccessVar[1:]:
+			return makeBool(e.checkPrivilege(_SENSITIVE_PRIVILEGE) == nil), nil
 		default:
 			return nil, fmt.Errorf("%w: %s", engine.ErrInvalidVariable, name)
 		}
@@ -577,6 +710,14 @@ func (e *executionContext) canExecute(newNamespace, actionName string, modifiers
 		return fmt.Errorf(`%w: action "%s" requires a writer connection`, engine.ErrCannotMutateState, actionName)
 	}
 
+	// like the VIEW check above, this cannot be overridden, since a read-only
+	// namespace must not be modified by anyone
+	if !modifiers.Has(precompiles.VIEW) {
+		if ns, ok := e.interpreter.namespaces[newNamespace]; ok && ns.readOnly {
+			return fmt.Errorf(`%w: action "%s" is not a view action and namespace "%s" is read-only`, engine.ErrNamespaceReadOnly, actionName, newNamespace)
+		}
+	}
+
 	// the VIEW check protects against state being modified outside of consensus. This is critical to protect
 	// against consensus errors. Every other check enforces user-defined rules, and thus can be overridden by
 	// extensions.
@@ -590,21 +731,34 @@ func (e *executionContext) canExecute(newNamespace, actionName string, modifiers
 	// - the calling namespace is not the same as the new namespace
 	// - the action is top level
 	// then return an error
+	unauthorized := func(err error) error {
+		return &engine.UnauthorizedError{
+			Namespace: newNamespace,
+			Action:    actionName,
+			Caller:    e.engineCtx.TxContext.Caller,
+			Err:       err,
+		}
+	}
+
 	if modifiers.Has(precompiles.PRIVATE) && (e.scope.namespace != newNamespace || e.scope.isTopLevel) {
-		return fmt.Errorf("%w: action %s is private", engine.ErrActionPrivate, actionName)
+		return unauthorized(fmt.Errorf("%w: action %s is private", engine.ErrActionPrivate, actionName))
 	}
 
 	// if it is system-only, then it must be within a subscope
 	if modifiers.Has(precompiles.SYSTEM) && e.scope.isTopLevel {
-		return fmt.Errorf("%w: action %s is system-only", engine.ErrActionSystemOnly, actionName)
+		return unauthorized(fmt.Errorf("%w: action %s is system-only", engine.ErrActionSystemOnly, actionName))
 	}
 
 	// if the action is owner only, then check if the user is the owner
 	if modifiers.Has(precompiles.OWNER) && !e.interpreter.accessController.IsOwner(e.engineCtx.TxContext.Caller) {
-		return fmt.Errorf("%w: action %s can only be executed by the owner", engine.ErrActionOwnerOnly, actionName)
+		return unauthorized(fmt.Errorf("%w: action %s can only be executed by the owner", engine.ErrActionOwnerOnly, actionName))
+	}
+
+	if err := e.checkPrivilege(_CALL_PRIVILEGE); err != nil {
+		return unauthorized(err)
 	}
 
-	return e.checkPrivilege(_CALL_PRIVILEGE)
+	return nil
 }
 
 func (e *executionContext) app() *common.App {
@@ -613,8 +767,9 @@ func (e *executionContext) app() *common.App {
 		Service: e.interpreter.service,
 		DB:      e.db,
 		Engine: &recursiveInterpreter{
-			i:    e.interpreter,
-			logs: e.logs,
+			i:         e.interpreter,
+			logs:      e.logs,
+			callStack: e.callStack,
 		},
 		Accounts:   e.interpreter.accounts,
 		Validators: e.interpreter.validators,
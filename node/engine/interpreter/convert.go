@@ -0,0 +1,138 @@
+package interpreter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/kwilteam/kwil-db/core/types"
+	"github.com/kwilteam/kwil-db/extensions/precompiles"
+	"github.com/kwilteam/kwil-db/node/engine"
+)
+
+// convertColumnsToEngine converts the columns of a table, as stored in the info
+// namespace, to engine columns.
+func convertColumnsToEngine(table string, names, dataTypes []string, isNullables, isPrimaryKeys []bool) ([]*engine.Column, error) {
+	columns := make([]*engine.Column, 0, len(names))
+	for i, name := range names {
+		dt, err := types.ParseDataType(dataTypes[i])
+		if err != nil {
+			return nil, &engine.SchemaError{Table: table, Column: name, Cause: err}
+		}
+
+		columns = append(columns, &engine.Column{
+			Name:         name,
+			DataType:     dt,
+			Nullable:     isNullables[i],
+			IsPrimaryKey: isPrimaryKeys[i],
+		})
+	}
+
+	return columns, nil
+}
+
+// convertIndexesToEngine converts the indexes of a table, as stored in the info
+// namespace, to engine indexes.
+func convertIndexesToEngine(table string, names []string, columns [][]string, isPKs, isUniques []bool) ([]*engine.Index, error) {
+	var indexes []*engine.Index
+	for i, name := range names {
+		if len(columns[i]) == 0 {
+			return nil, &engine.SchemaError{Table: table, Index: name, Cause: errors.New("index has no columns")}
+		}
+
+		indexType := engine.BTREE
+		if isPKs[i] {
+			indexType = engine.PRIMARY
+		} else if isUniques[i] {
+			indexType = engine.UNIQUE_BTREE
+		}
+
+		indexes = append(indexes, &engine.Index{
+			Name:    name,
+			Columns: columns[i],
+			Type:    indexType,
+		})
+	}
+
+	return indexes, nil
+}
+
+// convertConstraintsToEngine converts the constraints and foreign keys of a
+// table, as stored in the info namespace, to engine constraints keyed by name.
+func convertConstraintsToEngine(table string, names, constraintTypes []string, columns [][]string, fkNames []string, fkColumns [][]string) (map[string]*engine.Constraint, error) {
+	constraints := make(map[string]*engine.Constraint, len(names)+len(fkNames))
+	for i, name := range names {
+		var constraintType engine.ConstraintType
+		switch strings.ToLower(constraintTypes[i]) {
+		case "unique":
+			constraintType = engine.ConstraintUnique
+		case "check":
+			constraintType = engine.ConstraintCheck
+		default:
+			return nil, &engine.SchemaError{Table: table, Cause: fmt.Errorf("unknown constraint type %s of constraint %s", constraintTypes[i], name)}
+		}
+
+		if _, ok := constraints[name]; ok {
+			return nil, &engine.SchemaError{Table: table, Cause: fmt.Errorf("duplicate constraint %s", name)}
+		}
+
+		constraints[name] = &engine.Constraint{
+			Type:    constraintType,
+			Columns: columns[i],
+		}
+	}
+
+	for i, name := range fkNames {
+		if _, ok := constraints[name]; ok {
+			return nil, &engine.SchemaError{Table: table, Cause: fmt.Errorf("duplicate foreign key %s", name)}
+		}
+
+		constraints[name] = &engine.Constraint{
+			Type:    engine.ConstraintFK,
+			Columns: fkColumns[i],
+		}
+	}
+
+	return constraints, nil
+}
+
+// convertModifiersToEngine converts the modifiers of an action to engine
+// modifiers, removing duplicates. Exactly one of PUBLIC, PRIVATE, or SYSTEM is
+// required. All violations are returned at once, in a
+// *engine.ModifierValidationError.
+func convertModifiersToEngine(modifiers []string) ([]precompiles.Modifier, error) {
+	var errs []error
+	modSet := make(map[precompiles.Modifier]struct{})
+	mods := []precompiles.Modifier{}
+	accessModifiers := 0
+	for _, m := range modifiers {
+		mod, err := stringToMod(m)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		if _, ok := modSet[mod]; ok {
+			continue
+		}
+		modSet[mod] = struct{}{}
+		mods = append(mods, mod)
+
+		if mod == precompiles.PUBLIC || mod == precompiles.PRIVATE || mod == precompiles.SYSTEM {
+			accessModifiers++
+		}
+	}
+
+	switch {
+	case accessModifiers == 0:
+		errs = append(errs, errors.New("one of PUBLIC, PRIVATE, or SYSTEM access modifier is required"))
+	case accessModifiers > 1:
+		errs = append(errs, errors.New("only one of PUBLIC, PRIVATE, or SYSTEM is allowed"))
+	}
+
+	if len(errs) > 0 {
+		return nil, &engine.ModifierValidationError{Modifiers: modifiers, Err: errors.Join(errs...)}
+	}
+
+	return mods, nil
+}
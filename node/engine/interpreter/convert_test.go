@@ -0,0 +1,99 @@
+package interpreter
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kwilteam/kwil-db/core/types"
+	"github.com/kwilteam/kwil-db/extensions/precompiles"
+	"github.com/kwilteam/kwil-db/node/engine"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ConvertColumnsToEngine(t *testing.T) {
+	cols, err := convertColumnsToEngine("users", []string{"id", "email"}, []string{"int8", "text"}, []bool{false, true}, []bool{true, false})
+	require.NoError(t, err)
+	require.Len(t, cols, 2)
+	assert.Equal(t, "email", cols[1].Name)
+	assert.True(t, cols[1].Nullable)
+	assert.True(t, cols[0].IsPrimaryKey)
+
+	_, err = convertColumnsToEngine("users", []string{"id", "email"}, []string{"int8", "text2"}, []bool{false, true}, []bool{true, false})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "table 'users', column 'email': ")
+	assert.Contains(t, err.Error(), "text2")
+
+	var schemaErr *engine.SchemaError
+	require.True(t, errors.As(err, &schemaErr))
+	assert.Equal(t, "users", schemaErr.Table)
+	assert.Equal(t, "email", schemaErr.Column)
+}
+
+func Test_ConvertDecimalColumnsToEngine(t *testing.T) {
+	cols, err := convertColumnsToEngine("payments", []string{"amount", "fees"}, []string{"decimal(10,2)", "numeric(20,4)[]"}, []bool{false, true}, []bool{false, false})
+	require.NoError(t, err)
+
+	amount, err := types.NewNumericType(10, 2)
+	require.NoError(t, err)
+	assert.True(t, cols[0].DataType.EqualsStrict(amount), "got %s", cols[0].DataType)
+
+	fees, err := types.NewNumericType(20, 4)
+	require.NoError(t, err)
+	fees.IsArray = true
+	assert.True(t, cols[1].DataType.EqualsStrict(fees), "got %s", cols[1].DataType)
+
+	_, err = convertColumnsToEngine("payments", []string{"amount"}, []string{"decimal(2,10)"}, []bool{false}, []bool{false})
+	require.Error(t, err)
+}
+
+func Test_ConvertTimestampColumnsToEngine(t *testing.T) {
+	cols, err := convertColumnsToEngine("events", []string{"created_at", "times"}, []string{"timestamp", "timestamptz[]"}, []bool{false, true}, []bool{false, false})
+	require.NoError(t, err)
+	assert.True(t, cols[0].DataType.EqualsStrict(types.TimestampTZType), "got %s", cols[0].DataType)
+	assert.True(t, cols[1].DataType.EqualsStrict(types.TimestampTZArrayType), "got %s", cols[1].DataType)
+}
+
+func Test_ConvertNetworkColumnsToEngine(t *testing.T) {
+	cols, err := convertColumnsToEngine("acl", []string{"addr", "networks"}, []string{"inet", "cidr[]"}, []bool{false, true}, []bool{true, false})
+	require.NoError(t, err)
+	assert.True(t, cols[0].DataType.EqualsStrict(types.InetType), "got %s", cols[0].DataType)
+	assert.True(t, cols[1].DataType.EqualsStrict(types.CIDRArrayType), "got %s", cols[1].DataType)
+}
+
+func Test_ConvertIndexesToEngine(t *testing.T) {
+	_, err := convertIndexesToEngine("users", []string{"users_pkey", "email_idx"}, [][]string{{"id"}, {}}, []bool{true, false}, []bool{true, false})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "table 'users', index 'email_idx': ")
+}
+
+func Test_ConvertConstraintsToEngine(t *testing.T) {
+	_, err := convertConstraintsToEngine("users", []string{"email_check"}, []string{"exclude"}, [][]string{{"email"}}, nil, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "table 'users': unknown constraint type exclude")
+
+	_, err = convertConstraintsToEngine("users", []string{"c"}, []string{"unique"}, [][]string{{"email"}}, []string{"c"}, [][]string{{"org_id"}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "duplicate foreign key c")
+}
+
+func Test_ConvertModifiersToEngine(t *testing.T) {
+	mods, err := convertModifiersToEngine([]string{"PUBLIC", "view", "view"})
+	require.NoError(t, err)
+	assert.Equal(t, []precompiles.Modifier{precompiles.PUBLIC, precompiles.VIEW}, mods)
+
+	// all violations are returned, not just the first
+	_, err = convertModifiersToEngine([]string{"public", "private", "cached", "fast"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "only one of PUBLIC, PRIVATE, or SYSTEM is allowed")
+	assert.Contains(t, err.Error(), "unknown modifier cached")
+	assert.Contains(t, err.Error(), "unknown modifier fast")
+
+	var modErr *engine.ModifierValidationError
+	require.True(t, errors.As(err, &modErr))
+	assert.Equal(t, []string{"public", "private", "cached", "fast"}, modErr.Modifiers)
+	assert.Len(t, modErr.Err.(interface{ Unwrap() []error }).Unwrap(), 3)
+
+	_, err = convertModifiersToEngine([]string{"view"})
+	assert.ErrorContains(t, err, "one of PUBLIC, PRIVATE, or SYSTEM access modifier is required")
+}
@@ -0,0 +1,162 @@
+package interpreter
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/kwilteam/kwil-db/node/engine"
+)
+
+// cursor is a cursor declared by an action with DECLARE ... CURSOR FOR. It
+// is backed by a Postgres cursor in the transaction, so that the rows of its
+// query are fetched one at a time instead of being loaded at once.
+type cursor struct {
+	// pgName is the name of the cursor in Postgres.
+	pgName string
+	// columns are the names of the columns of the query.
+	columns []string
+	// scanValues are the values the rows of the query are scanned into.
+	scanValues []any
+}
+
+// pgCursorName returns the name of a cursor in Postgres. Cursors of nested
+// calls can have the same name, so the name includes the depth of the call
+// that declared it. Calls at the same depth cannot overlap, and each closes
+// its cursors when it returns.
+func (e *executionContext) pgCursorName(name string) string {
+	return fmt.Sprintf("kwil_cursor_%d_%s", len(e.callStack), name)
+}
+
+// declareCursor declares a cursor over the rows of a query.
+func (e *executionContext) declareCursor(name, sql string) error {
+	if e.queryActive {
+		return engine.ErrQueryActive
+	}
+	if _, ok := e.cursors[name]; ok {
+		return fmt.Errorf(`cursor "%s" is already declared`, name)
+	}
+
+	generatedSQL, analyzed, args, tableHints, err := e.prepareQuery(sql)
+	if err != nil {
+		return err
+	}
+
+	cols, scanValues, err := queryColumns(analyzed)
+	if err != nil {
+		return err
+	}
+
+	// the hints are set while the cursor is declared, since that is when its
+	// query is planned
+	hints := mergeOptimizerHints(e.optimizerHints, tableHints)
+	if err = setOptimizerHints(e.engineCtx.TxContext.Ctx, e.db, hints); err != nil {
+		return err
+	}
+
+	if err = e.prepareBlockTime(generatedSQL); err != nil {
+		return err
+	}
+
+	if err = e.explain(generatedSQL, args); err != nil {
+		return err
+	}
+
+	c := &cursor{
+		pgName:     e.pgCursorName(name),
+		columns:    cols,
+		scanValues: scanValues,
+	}
+
+	argVals := make([]any, len(args))
+	for i, v := range args {
+		argVals[i] = v
+	}
+
+	err = execute(e.engineCtx.TxContext.Ctx, e.db, `DECLARE `+c.pgName+` NO SCROLL CURSOR FOR `+generatedSQL, argVals...)
+	if err != nil {
+		return err
+	}
+
+	if e.cursors == nil {
+		e.cursors = make(map[string]*cursor)
+	}
+	e.cursors[name] = c
+
+	return resetOptimizerHints(e.engineCtx.TxContext.Ctx, e.db, hints)
+}
+
+// fetchCursor fetches the next row of a cursor. It returns nil if the cursor
+// has no more rows.
+func (e *executionContext) fetchCursor(name string) (*row, error) {
+	if e.queryActive {
+		return nil, engine.ErrQueryActive
+	}
+	c, ok := e.cursors[name]
+	if !ok {
+		return nil, fmt.Errorf(`cursor "%s" is not declared`, name)
+	}
+
+	var fetched *row
+	err := queryRowFunc(e.engineCtx.TxContext.Ctx, e.db, `FETCH NEXT FROM `+c.pgName, c.scanValues, func() error {
+		vals, err := fromScanValues(c.scanValues)
+		if err != nil {
+			return err
+		}
+
+		fetched = &row{
+			columns: c.columns,
+			Values:  vals,
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return fetched, nil
+}
+
+// nullRow returns a row of nulls with the columns of a cursor. It is what
+// FETCH assigns once a cursor has no more rows.
+func (c *cursor) nullRow() (*row, error) {
+	vals, err := fromScanValues(c.scanValues)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, v := range vals {
+		vals[i], err = makeNull(v.Type())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &row{
+		columns: c.columns,
+		Values:  vals,
+	}, nil
+}
+
+// closeCursor closes a cursor, releasing it in Postgres.
+func (e *executionContext) closeCursor(name string) error {
+	if e.queryActive {
+		return engine.ErrQueryActive
+	}
+	c, ok := e.cursors[name]
+	if !ok {
+		return fmt.Errorf(`cursor "%s" is not declared`, name)
+	}
+
+	delete(e.cursors, name)
+	return execute(e.engineCtx.TxContext.Ctx, e.db, `CLOSE `+c.pgName)
+}
+
+// closeCursors closes the cursors that are still open when an action
+// returns.
+func (e *executionContext) closeCursors() error {
+	var errs []error
+	for name := range e.cursors {
+		errs = append(errs, e.closeCursor(name))
+	}
+	return errors.Join(errs...)
+}
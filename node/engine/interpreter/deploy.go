@@ -0,0 +1,126 @@
+package interpreter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kwilteam/kwil-db/common"
+	"github.com/kwilteam/kwil-db/node/engine"
+	"github.com/kwilteam/kwil-db/node/types/sql"
+)
+
+// DeployNamespace deploys a schema to a namespace. It creates the namespace if
+// it does not exist, executes the statements of the schema in it, and
+// increments the version of the namespace, which it returns.
+//
+// If expectedVersion is not nil, the deploy fails with
+// engine.ErrVersionConflict unless the namespace is at that version. A
+// namespace that was never deployed is at version 0. The version is locked
+// until db is committed or rolled back, so that of two deploys that expect the
+// same version, only the first succeeds, even if they run in concurrent
+// transactions. Versions are kept when a namespace is dropped.
+func (t *ThreadSafeInterpreter) DeployNamespace(ctx *common.EngineContext, db sql.DB, namespace string, statements []string, expectedVersion *int64) (version int64, err error) {
+	namespace = strings.ToLower(namespace)
+	if !identRegexp.MatchString(namespace) {
+		return 0, fmt.Errorf(`invalid namespace name "%s"`, namespace)
+	}
+
+	release, err := t.acquire(ctx.TxContext.Ctx, db)
+	if err != nil {
+		return 0, &engine.QuotaExceededError{Namespace: namespace, Caller: ctx.TxContext.Caller, Err: err}
+	}
+	defer release()
+
+	tx, err := db.BeginTx(ctx.TxContext.Ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx.TxContext.Ctx)
+
+	// the version is claimed before the interpreter is locked, since claiming
+	// it waits for concurrent deploys to commit
+	version, err = claimNamespaceVersion(ctx, tx, namespace, expectedVersion)
+	if err != nil {
+		return 0, err
+	}
+
+	unlock, err := t.lock(db)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	if t.nsEvents != nil {
+		before := namespaceNames(t.i.namespaces)
+		defer func() {
+			if err == nil {
+				t.publishNamespaceEvents(before)
+			}
+		}()
+	}
+
+	copied := t.i.copy()
+	defer func() {
+		if err != nil {
+			t.i.apply(copied)
+		}
+	}()
+
+	if err = t.i.deployStatements(ctx, tx, namespace, statements); err != nil {
+		return 0, err
+	}
+
+	if err = tx.Commit(ctx.TxContext.Ctx); err != nil {
+		return 0, err
+	}
+
+	return version, nil
+}
+
+// deployStatements creates a namespace if it does not exist, and executes
+// statements in it.
+func (i *baseInterpreter) deployStatements(ctx *common.EngineContext, db sql.DB, namespace string, statements []string) error {
+	err := i.execute(ctx, db, "CREATE NAMESPACE IF NOT EXISTS "+namespace, nil, nil, true)
+	if err != nil {
+		return err
+	}
+
+	for j, stmt := range statements {
+		if err = i.executeIn(ctx, db, namespace, stmt, nil, nil, true); err != nil {
+			return fmt.Errorf("statement %d: %w", j+1, err)
+		}
+	}
+
+	return nil
+}
+
+// claimNamespaceVersion increments the version of a namespace, and locks it. If
+// expected is not nil, it fails unless the namespace is at that version.
+func claimNamespaceVersion(ctx *common.EngineContext, db sql.DB, namespace string, expected *int64) (int64, error) {
+	// the row is created first, so that concurrent deploys of a new namespace
+	// lock the same row
+	err := execute(ctx.TxContext.Ctx, db, `INSERT INTO kwild_engine.namespace_versions (namespace, version)
+	VALUES ($1, 0) ON CONFLICT (namespace) DO NOTHING`, namespace)
+	if err != nil {
+		return 0, err
+	}
+
+	current, err := queryOneInt64(ctx.TxContext.Ctx, db, `SELECT version FROM kwild_engine.namespace_versions
+	WHERE namespace = $1 FOR UPDATE`, namespace)
+	if err != nil {
+		return 0, err
+	}
+
+	if expected != nil && *expected != current {
+		return 0, fmt.Errorf(`%w: expected namespace "%s" to be at version %d, but it is at version %d`,
+			engine.ErrVersionConflict, namespace, *expected, current)
+	}
+
+	err = execute(ctx.TxContext.Ctx, db, `UPDATE kwild_engine.namespace_versions SET version = $2 WHERE namespace = $1`,
+		namespace, current+1)
+	if err != nil {
+		return 0, err
+	}
+
+	return current + 1, nil
+}
@@ -0,0 +1,72 @@
+package interpreter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// distinctResults de-duplicates the rows returned by an action declared with
+// @distinct or @distinct_on.
+type distinctResults struct {
+	// on are the positions of the returned columns that rows are
+	// de-duplicated on. If it is empty, rows are de-duplicated on all columns.
+	on []int
+}
+
+// newDistinctResults returns the de-duplication of an action's results, or nil
+// if the action does not de-duplicate them.
+func newDistinctResults(act *action) *distinctResults {
+	if !act.Distinct {
+		return nil
+	}
+
+	d := &distinctResults{}
+	for _, col := range act.DistinctOn {
+		for i, field := range act.Returns.Fields {
+			if field.Name == col {
+				d.on = append(d.on, i)
+				break
+			}
+		}
+	}
+
+	return d
+}
+
+// wrap wraps a generated query that returns numCols columns so that only the
+// first of each set of duplicate rows is returned. The order of the rows is
+// kept, which also keeps the results deterministic. If the query does not
+// return the columns rows are de-duplicated on, it is returned unchanged, and
+// its results are rejected by the action's return checks.
+//
+// For a query returning two columns that is de-duplicated on the first, it
+// generates:
+//
+//	SELECT _distinct_1, _distinct_2 FROM (
+//		SELECT DISTINCT ON (_distinct_1) _distinct_1, _distinct_2, _distinct_ord FROM (
+//			SELECT *, row_number() OVER () AS _distinct_ord FROM (<query>) AS _distinct_src(_distinct_1, _distinct_2)
+//		) AS _distinct_numbered ORDER BY _distinct_1, _distinct_ord
+//	) AS _distinct_results ORDER BY _distinct_ord
+func (d *distinctResults) wrap(stmt string, numCols int) string {
+	cols := make([]string, numCols)
+	for i := range cols {
+		cols[i] = "_distinct_" + strconv.Itoa(i+1)
+	}
+
+	keys := cols
+	if len(d.on) > 0 {
+		keys = make([]string, len(d.on))
+		for i, pos := range d.on {
+			if pos >= numCols {
+				return stmt
+			}
+			keys[i] = cols[pos]
+		}
+	}
+
+	colList := strings.Join(cols, ", ")
+	keyList := strings.Join(keys, ", ")
+	return fmt.Sprintf(`SELECT %[1]s FROM (SELECT DISTINCT ON (%[2]s) %[1]s, _distinct_ord FROM (SELECT *, row_number() OVER () AS _distinct_ord FROM (%[3]s) AS _distinct_src(%[1]s)) AS _distinct_numbered ORDER BY %[2]s, _distinct_ord) AS _distinct_results ORDER BY _distinct_ord;`,
+		colList, keyList, strings.TrimSuffix(strings.TrimSpace(stmt), ";"))
+}
@@ -0,0 +1,174 @@
+package interpreter
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/kwilteam/kwil-db/node/types/sql"
+)
+
+// distributedTxPrefix prefixes the global identifier of the prepared
+// transactions of distributed transactions, so that they are distinguishable
+// from those prepared by the pg.DB.
+const distributedTxPrefix = "kwild_dtx_"
+
+// distributedTxIDRegex restricts distributed transaction IDs to characters that
+// are safe to embed in a PREPARE TRANSACTION statement, which cannot be
+// parameterized.
+var distributedTxIDRegex = regexp.MustCompile(`^[a-zA-Z0-9_\-]{1,100}$`)
+
+// distributedTx is a transaction that is committed with two-phase commit.
+type distributedTx struct {
+	// db executes COMMIT PREPARED and ROLLBACK PREPARED, which must be
+	// executed outside of the prepared transaction.
+	db sql.DB
+	// tx is the open transaction. It is nil once the transaction is prepared.
+	tx sql.Tx
+}
+
+// BeginDistributedTx begins a transaction on db that takes part in a
+// distributed transaction with the given ID. Actions are executed in it using
+// the tx returned by DistributedTx, and it is committed with two-phase commit
+// by PrepareDistributedTx followed by CommitDistributedTx or
+// RollbackDistributedTx. db must be able to execute statements outside of the
+// transaction, such as a *pg.Pool.
+func (t *ThreadSafeInterpreter) BeginDistributedTx(ctx context.Context, db sql.DB, txID string) error {
+	if !distributedTxIDRegex.MatchString(txID) {
+		return fmt.Errorf(`invalid distributed transaction ID "%s"`, txID)
+	}
+
+	t.dtxMu.Lock()
+	defer t.dtxMu.Unlock()
+
+	if _, ok := t.distributedTxs[txID]; ok {
+		return fmt.Errorf(`distributed transaction "%s" already exists`, txID)
+	}
+
+	tx, err := db.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+
+	t.distributedTxs[txID] = &distributedTx{db: db, tx: tx}
+	return nil
+}
+
+// DistributedTx returns the open transaction of a distributed transaction.
+func (t *ThreadSafeInterpreter) DistributedTx(txID string) (sql.Tx, error) {
+	t.dtxMu.Lock()
+	defer t.dtxMu.Unlock()
+
+	dtx, ok := t.distributedTxs[txID]
+	if !ok {
+		return nil, fmt.Errorf(`unknown distributed transaction "%s"`, txID)
+	}
+	if dtx.tx == nil {
+		return nil, fmt.Errorf(`distributed transaction "%s" is already prepared`, txID)
+	}
+
+	return dtx.tx, nil
+}
+
+// PrepareDistributedTx prepares a distributed transaction for commit. Once
+// prepared, the transaction survives a crash of the node or of Postgres, and
+// must be completed with CommitDistributedTx or RollbackDistributedTx.
+func (t *ThreadSafeInterpreter) PrepareDistributedTx(txID string) error {
+	t.dtxMu.Lock()
+	defer t.dtxMu.Unlock()
+
+	dtx, ok := t.distributedTxs[txID]
+	if !ok {
+		return fmt.Errorf(`unknown distributed transaction "%s"`, txID)
+	}
+	if dtx.tx == nil {
+		return fmt.Errorf(`distributed transaction "%s" is already prepared`, txID)
+	}
+
+	ctx := context.Background()
+	err := execute(ctx, dtx.tx, fmt.Sprintf(`PREPARE TRANSACTION '%s%s'`, distributedTxPrefix, txID))
+	if err != nil {
+		return err
+	}
+
+	// The session is no longer in a transaction, so the rollback is a no-op
+	// that releases the connection.
+	if err = dtx.tx.Rollback(ctx); err != nil {
+		return err
+	}
+	dtx.tx = nil
+
+	return nil
+}
+
+// CommitDistributedTx commits a prepared distributed transaction.
+func (t *ThreadSafeInterpreter) CommitDistributedTx(txID string) error {
+	return t.finishDistributedTx(txID, "COMMIT PREPARED")
+}
+
+// RollbackDistributedTx rolls back a distributed transaction, whether or not it
+// is prepared.
+func (t *ThreadSafeInterpreter) RollbackDistributedTx(txID string) error {
+	return t.finishDistributedTx(txID, "ROLLBACK PREPARED")
+}
+
+func (t *ThreadSafeInterpreter) finishDistributedTx(txID, stmt string) error {
+	t.dtxMu.Lock()
+	defer t.dtxMu.Unlock()
+
+	dtx, ok := t.distributedTxs[txID]
+	if !ok {
+		return fmt.Errorf(`unknown distributed transaction "%s"`, txID)
+	}
+
+	ctx := context.Background()
+	if dtx.tx != nil {
+		if stmt == "COMMIT PREPARED" {
+			return fmt.Errorf(`distributed transaction "%s" must be prepared before it is committed`, txID)
+		}
+
+		if err := dtx.tx.Rollback(ctx); err != nil {
+			return err
+		}
+		delete(t.distributedTxs, txID)
+		return nil
+	}
+
+	err := execute(ctx, dtx.db, fmt.Sprintf(`%s '%s%s'`, stmt, distributedTxPrefix, txID))
+	if err != nil {
+		return err
+	}
+
+	delete(t.distributedTxs, txID)
+	return nil
+}
+
+// RecoverDistributedTxs finds the distributed transactions that were prepared
+// but not completed, e.g. because the node stopped between prepare and commit,
+// so that they can be completed with CommitDistributedTx or
+// RollbackDistributedTx. It returns their IDs.
+func (t *ThreadSafeInterpreter) RecoverDistributedTxs(ctx context.Context, db sql.DB) ([]string, error) {
+	t.dtxMu.Lock()
+	defer t.dtxMu.Unlock()
+
+	var gid string
+	var txIDs []string
+	err := queryRowFunc(ctx, db, `SELECT gid FROM pg_prepared_xacts
+	WHERE database = current_database() AND starts_with(gid, $1) ORDER BY prepared`,
+		[]any{&gid}, func() error {
+			txIDs = append(txIDs, strings.TrimPrefix(gid, distributedTxPrefix))
+			return nil
+		}, distributedTxPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, txID := range txIDs {
+		if _, ok := t.distributedTxs[txID]; !ok {
+			t.distributedTxs[txID] = &distributedTx{db: db}
+		}
+	}
+
+	return txIDs, nil
+}
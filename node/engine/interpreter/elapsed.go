@@ -0,0 +1,32 @@
+package interpreter
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/kwilteam/kwil-db/core/types"
+	"github.com/kwilteam/kwil-db/node/engine"
+)
+
+// actionElapsedMs implements the action_elapsed_ms built-in. It returns the
+// milliseconds since the execution context of the call was created, as a
+// decimal of type dt. Like seeded_random, it is computed without a round trip
+// to Postgres. Since the result differs on every node, it cannot be used by
+// calls that can change state.
+func (e *executionContext) actionElapsedMs(dt *types.DataType) (value, error) {
+	if e.canMutateState {
+		return nil, fmt.Errorf(`%w: "action_elapsed_ms" cannot be used in calls that can change state`, engine.ErrIllegalFunctionUsage)
+	}
+
+	// nanoseconds are milliseconds with a scale of 6
+	d, err := types.NewDecimalFromBigInt(big.NewInt(time.Since(e.createdAt).Nanoseconds()), -6)
+	if err != nil {
+		return nil, err
+	}
+	if err = d.SetPrecisionAndScale(dt.Metadata[0], dt.Metadata[1]); err != nil {
+		return nil, err
+	}
+
+	return makeDecimal(d), nil
+}
@@ -0,0 +1,38 @@
+package interpreter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kwilteam/kwil-db/core/types"
+	"github.com/kwilteam/kwil-db/node/engine"
+)
+
+func Test_ActionElapsedMs(t *testing.T) {
+	dt, err := engine.Functions["action_elapsed_ms"].ValidateArgs(nil)
+	require.NoError(t, err)
+
+	const delay = 20 * time.Millisecond
+
+	e := &executionContext{createdAt: time.Now()}
+	time.Sleep(delay)
+
+	v, err := e.actionElapsedMs(dt)
+	require.NoError(t, err)
+	require.True(t, v.Type().Equals(dt))
+
+	elapsed, err := v.RawValue().(*types.Decimal).Float64()
+	require.NoError(t, err)
+	require.Greater(t, elapsed, float64(delay.Milliseconds()))
+
+	// subscopes measure from the start of the call
+	sub := e.subscope("main")
+	require.Equal(t, e.createdAt, sub.createdAt)
+
+	// the result differs on every node
+	e.canMutateState = true
+	_, err = e.actionElapsedMs(dt)
+	require.ErrorIs(t, err, engine.ErrIllegalFunctionUsage)
+}
@@ -0,0 +1,120 @@
+package interpreter
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kwilteam/kwil-db/node/engine/parse"
+	"github.com/kwilteam/kwil-db/node/types/sql"
+)
+
+// eventsTablePrefix prefixes the name of the events table of a namespace
+// declared with @event_sourced. Events tables are in the kwild_engine schema.
+const eventsTablePrefix = "events_"
+
+// maxEventSourcedNamespaceLength is the longest name of an event sourced
+// namespace, so that the name of its events table fits in a Postgres
+// identifier, which is truncated to 63 bytes.
+const maxEventSourcedNamespaceLength = 63 - len(eventsTablePrefix)
+
+// createEventsTable creates the events table of a namespace declared with
+// @event_sourced. Every INSERT, UPDATE and DELETE on the namespace's tables is
+// appended to it, with:
+//   - seq, which orders the events of the namespace
+//   - block_height, the height of the block in which the change was made
+//   - table_name, the table that was changed
+//   - operation, "I" for INSERT, "U" for UPDATE, or "D" for DELETE
+//   - row_key, the primary key of the row as a JSON array
+//   - row_data, the row after the change, or before it for DELETE
+func createEventsTable(ctx context.Context, db sql.DB, namespace string) error {
+	if len(namespace) > maxEventSourcedNamespaceLength {
+		return fmt.Errorf(`event sourced namespace "%s" is longer than %d characters`, namespace, maxEventSourcedNamespaceLength)
+	}
+
+	err := execute(ctx, db, fmt.Sprintf(`CREATE TABLE kwild_engine.%s%s (
+		seq INT8 PRIMARY KEY,
+		block_height INT8 NOT NULL,
+		table_name TEXT NOT NULL,
+		operation CHAR(1) NOT NULL,
+		row_key JSONB NOT NULL,
+		row_data JSONB NOT NULL
+	)`, eventsTablePrefix, namespace))
+	if err != nil {
+		return err
+	}
+
+	// projections read the latest event of each row of a table
+	return execute(ctx, db, fmt.Sprintf(`CREATE INDEX ON kwild_engine.%s%s (table_name, row_key, seq)`, eventsTablePrefix, namespace))
+}
+
+// dropEventsTable drops the events table of a namespace, if it has one.
+func dropEventsTable(ctx context.Context, db sql.DB, namespace string) error {
+	return execute(ctx, db, fmt.Sprintf(`DROP TABLE IF EXISTS kwild_engine.%s%s`, eventsTablePrefix, namespace))
+}
+
+// createEventTrigger creates the trigger that records the changes of a table
+// of an event sourced namespace. Tables with sensitive columns cannot be
+// event sourced, since their events are readable with project_events.
+func createEventTrigger(ctx context.Context, db sql.DB, namespace string, stmt *parse.CreateTableStatement) error {
+	for _, col := range stmt.Columns {
+		if col.SensitivityPolicy != nil {
+			return fmt.Errorf(`column "%s" of table "%s" cannot be sensitive, since namespace "%s" is event sourced`,
+				col.Name, stmt.Name, namespace)
+		}
+	}
+
+	return execute(ctx, db, fmt.Sprintf(`CREATE TRIGGER record_event AFTER INSERT OR UPDATE OR DELETE ON %s.%s
+	FOR EACH ROW EXECUTE FUNCTION kwild_engine.record_event()`, namespace, stmt.Name))
+}
+
+// dropTableEvents deletes the events of a dropped table, so that a table
+// created later with the same name does not project its rows.
+func dropTableEvents(ctx context.Context, db sql.DB, namespace, table string) error {
+	return execute(ctx, db, fmt.Sprintf(`DELETE FROM kwild_engine.%s%s WHERE table_name = $1`, eventsTablePrefix, namespace), table)
+}
+
+// renameTableEvents keeps the events of a table in sync with a RENAME TO
+// action, so that they are projected under its new name.
+func renameTableEvents(ctx context.Context, db sql.DB, namespace, table, newName string) error {
+	return execute(ctx, db, fmt.Sprintf(`UPDATE kwild_engine.%s%s SET table_name = $2 WHERE table_name = $1`, eventsTablePrefix, namespace),
+		table, newName)
+}
+
+// listEventSourcedNamespaces lists the namespaces that have an events table.
+func listEventSourcedNamespaces(ctx context.Context, db sql.DB) (map[string]bool, error) {
+	namespaces := make(map[string]bool)
+	var eventsTable string
+	err := queryRowFunc(ctx, db, `SELECT tablename::text FROM pg_tables
+	WHERE schemaname = 'kwild_engine' AND starts_with(tablename, $1)`, []any{&eventsTable},
+		func() error {
+			namespaces[strings.TrimPrefix(eventsTable, eventsTablePrefix)] = true
+			return nil
+		}, eventsTablePrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	return namespaces, nil
+}
+
+// hasEventSourcedNamespaces returns true if any namespace is event sourced.
+func (i *baseInterpreter) hasEventSourcedNamespaces() bool {
+	for _, ns := range i.namespaces {
+		if ns.eventSourced {
+			return true
+		}
+	}
+	return false
+}
+
+// setEventBlockHeight sets the block height used by the event trigger for the
+// rest of the transaction.
+func (e *executionContext) setEventBlockHeight() error {
+	if e.engineCtx.InvalidTxCtx || e.engineCtx.TxContext.BlockContext == nil {
+		return nil
+	}
+
+	return execute(e.engineCtx.TxContext.Ctx, e.db, `SELECT set_config('kwild.block_height', $1, true)`,
+		fmt.Sprint(e.engineCtx.TxContext.BlockContext.Height))
+}
@@ -2,6 +2,7 @@ package interpreter
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 
@@ -70,42 +71,31 @@ func initializeExtension(ctx context.Context, svc *common.Service, db sql.DB, i
 				}
 
 				exec2 := exec.subscope(alias)
+				exec2.callStack = pushCallFrame(exec.callStack, alias, lowerName, len(args))
 
-				return method.Handler(exec2.engineCtx, exec2.app(), argVals, func(a []any) error {
-					// if no return is specified for this method, then the callback should never be called
-					if method.Returns == nil {
-						return fmt.Errorf(`%w: method "%s"."%s" returned no value, but expected one`, engine.ErrExtensionImplementation, alias, lowerName)
-					}
-
-					colNames := make([]string, len(a))
-					returnVals := make([]value, len(a))
-
-					if len(method.Returns.Fields) != len(a) {
-						return fmt.Errorf("%w: method %s returned %d values, but expected %d", engine.ErrExtensionImplementation, lowerName, len(a), len(method.Returns.Fields))
-					}
-
-					for i, v := range a {
-						newVal, ok, err := newValueWithSoftCast(v, method.Returns.Fields[i].Type)
-						if err != nil {
-							return err
-						}
-						if !ok {
-							return fmt.Errorf(`%w: method "%s"."%s" returned a value of type %s, but expected %s. column: "%s"`, engine.ErrExtensionImplementation, alias, lowerName, newVal.Type(), method.Returns.Fields[i].Type, method.Returns.Fields[i].Name)
-						}
-
-						if !method.Returns.Fields[i].Nullable && newVal.Null() {
-							return fmt.Errorf(`%w: method "%s"."%s" returned a null value for a non-nullable column. column: "%s"`, engine.ErrExtensionImplementation, alias, lowerName, method.Returns.Fields[i].Name)
-						}
-
-						returnVals[i] = newVal
-						colNames[i] = method.Returns.Fields[i].Name
-					}
+				// the breaker is local to the node, so calls that can change
+				// state always call the extension
+				breaker := exec.interpreter.breaker
+				if breaker == nil || exec.canMutateState {
+					return withCallStack(exec2.callStack, callExtensionMethod(exec2, alias, lowerName, &method, argVals, fn))
+				}
+				if err := breaker.allow(alias); err != nil {
+					return err
+				}
 
-					return fn(&row{
-						columns: colNames, // it is ok if this is nil
-						Values:  returnVals,
-					})
+				// errors returned while the caller handles the rows of the
+				// extension are not failures of the extension
+				var handlerErr error
+				err := callExtensionMethod(exec2, alias, lowerName, &method, argVals, func(r *row) error {
+					handlerErr = fn(r)
+					return handlerErr
 				})
+				if handlerErr != nil && errors.Is(err, handlerErr) {
+					breaker.record(alias, nil)
+				} else {
+					breaker.record(alias, err)
+				}
+				return withCallStack(exec2.callStack, err)
 			},
 			Type: executableTypePrecompile,
 		}
@@ -132,6 +122,53 @@ func initializeExtension(ctx context.Context, svc *common.Service, db sql.DB, i
 	}, &inst, nil
 }
 
+// callExtensionMethod calls the handler of an extension method, checking the
+// values it returns.
+func callExtensionMethod(exec *executionContext, alias, lowerName string, method *precompiles.Method, argVals []any, fn resultFunc) (err error) {
+	// panics are returned as errors that identify the extension method
+	defer func() {
+		if r := recover(); r != nil {
+			err = &engine.ExtensionPanicError{Namespace: alias, Method: lowerName, Value: r}
+		}
+	}()
+
+	return method.Handler(exec.engineCtx, exec.app(), argVals, func(a []any) error {
+		// if no return is specified for this method, then the callback should never be called
+		if method.Returns == nil {
+			return fmt.Errorf(`%w: method "%s"."%s" returned no value, but expected one`, engine.ErrExtensionImplementation, alias, lowerName)
+		}
+
+		colNames := make([]string, len(a))
+		returnVals := make([]value, len(a))
+
+		if len(method.Returns.Fields) != len(a) {
+			return fmt.Errorf("%w: method %s returned %d values, but expected %d", engine.ErrExtensionImplementation, lowerName, len(a), len(method.Returns.Fields))
+		}
+
+		for i, v := range a {
+			newVal, ok, err := newValueWithSoftCast(v, method.Returns.Fields[i].Type)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return fmt.Errorf(`%w: method "%s"."%s" returned a value of type %s, but expected %s. column: "%s"`, engine.ErrExtensionImplementation, alias, lowerName, newVal.Type(), method.Returns.Fields[i].Type, method.Returns.Fields[i].Name)
+			}
+
+			if !method.Returns.Fields[i].Nullable && newVal.Null() {
+				return fmt.Errorf(`%w: method "%s"."%s" returned a null value for a non-nullable column. column: "%s"`, engine.ErrExtensionImplementation, alias, lowerName, method.Returns.Fields[i].Name)
+			}
+
+			returnVals[i] = newVal
+			colNames[i] = method.Returns.Fields[i].Name
+		}
+
+		return fn(&row{
+			columns: colNames, // it is ok if this is nil
+			Values:  returnVals,
+		})
+	})
+}
+
 type precompileExecutable struct {
 	method *precompiles.Method
 	exec   *executable
@@ -0,0 +1,131 @@
+package interpreter
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kwilteam/kwil-db/node/types/sql"
+)
+
+// namespaceEventsBuffer is the number of namespace events that are kept until
+// they are read from NamespaceEvents. Events are dropped once it is full.
+const namespaceEventsBuffer = 100
+
+// NamespaceEvent is the deployment or undeployment of a namespace by an
+// execution.
+type NamespaceEvent struct {
+	Namespace string
+	// Deployed is true if the namespace was deployed, and false if it was
+	// undeployed.
+	Deployed bool
+}
+
+// WithNamespaceGossip makes the namespaces deployed and undeployed by
+// executions available from NamespaceEvents.
+func WithNamespaceGossip() InterpreterOpt {
+	return func(o *InterpreterOptions) {
+		o.GossipEnabled = true
+	}
+}
+
+// NamespaceEvents returns the namespaces deployed and undeployed by
+// executions. It is nil if gossip is not enabled. Events are sent when the
+// execution succeeds, which is before its transaction is committed.
+func (t *ThreadSafeInterpreter) NamespaceEvents() <-chan NamespaceEvent {
+	return t.nsEvents
+}
+
+// namespaceNames returns the names of the namespaces.
+func namespaceNames(namespaces map[string]*namespace) map[string]struct{} {
+	names := make(map[string]struct{}, len(namespaces))
+	for name := range namespaces {
+		names[name] = struct{}{}
+	}
+	return names
+}
+
+// publishNamespaceEvents sends an event for each namespace that was deployed
+// or undeployed since the names were taken. It must be called with the lock
+// held.
+func (t *ThreadSafeInterpreter) publishNamespaceEvents(before map[string]struct{}) {
+	publish := func(ev NamespaceEvent) {
+		select {
+		case t.nsEvents <- ev:
+		default: // the events are not being read
+		}
+	}
+
+	for name := range t.i.namespaces {
+		if _, ok := before[name]; !ok {
+			publish(NamespaceEvent{Namespace: name, Deployed: true})
+		}
+	}
+	for name := range before {
+		if _, ok := t.i.namespaces[name]; !ok {
+			publish(NamespaceEvent{Namespace: name})
+		}
+	}
+}
+
+// LoadNamespace loads a namespace that is in the database but that the
+// interpreter does not have, such as one that another node announced. It
+// returns false if the namespace is not in the database, or is already loaded.
+// Loaded namespaces are never replaced or removed, since the interpreter is
+// ahead of the database while a block is executed, and namespaces only change
+// through the transactions that every node executes.
+func (t *ThreadSafeInterpreter) LoadNamespace(ctx context.Context, db sql.DB, name string) (bool, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, ok := t.i.namespaces[name]; ok {
+		return false, nil
+	}
+
+	namespaces, err := listNamespaces(ctx, db)
+	if err != nil {
+		return false, err
+	}
+
+	for _, ns := range namespaces {
+		if ns.Name != name {
+			continue
+		}
+		if ns.Type != namespaceTypeUser {
+			return false, fmt.Errorf(`namespace "%s" is not a user namespace`, name)
+		}
+
+		loaded, err := loadNamespace(ctx, db, ns.Name, ns.Type)
+		if err != nil {
+			return false, err
+		}
+
+		verbosities, err := listErrorVerbosities(ctx, db)
+		if err != nil {
+			return false, err
+		}
+		eventSourced, err := listEventSourcedNamespaces(ctx, db)
+		if err != nil {
+			return false, err
+		}
+		testNamespaces, err := listTestNamespaces(ctx, db)
+		if err != nil {
+			return false, err
+		}
+		readOnly, err := listReadOnlyNamespaces(ctx, db)
+		if err != nil {
+			return false, err
+		}
+
+		loaded.errorVerbosity = verbosities[name]
+		loaded.eventSourced = eventSourced[name]
+		loaded.test = testNamespaces[name]
+		loaded.readOnly = readOnly[name]
+
+		t.i.namespaces[name] = loaded
+		t.i.accessController.registerNamespace(name)
+		t.i.syncNamespaceManager()
+		return true, nil
+	}
+
+	return false, nil
+}
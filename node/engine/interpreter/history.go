@@ -0,0 +1,213 @@
+package interpreter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/kwilteam/kwil-db/node/engine"
+	"github.com/kwilteam/kwil-db/node/engine/parse"
+	"github.com/kwilteam/kwil-db/node/types/sql"
+)
+
+const (
+	// historyChangedAtColumn is the column returned by get_row_history with the
+	// unix timestamp of the block in which the row was changed.
+	historyChangedAtColumn = "history_changed_at"
+	// historyOperationColumn is the column returned by get_row_history with the
+	// operation that changed the row: "U" for UPDATE, or "D" for DELETE.
+	historyOperationColumn = "history_operation"
+)
+
+// createHistoryTable creates the history table of a table declared with @history,
+// and the trigger that records the previous state of its rows on UPDATE and DELETE.
+// The history table has all of the table's columns, without constraints, plus:
+//   - _history_seq, which orders the history of the table
+//   - _changed_at, the block timestamp of the change
+//   - _operation, "U" for UPDATE or "D" for DELETE
+func createHistoryTable(ctx context.Context, db sql.DB, namespace string, stmt *parse.CreateTableStatement) error {
+	historyTable := engine.HistoryTablePrefix + stmt.Name
+
+	var defs, pkCols []string
+	for _, col := range stmt.Columns {
+		typ, err := col.Type.PGString()
+		if err != nil {
+			return err
+		}
+		defs = append(defs, col.Name+" "+typ)
+
+		for _, con := range col.Constraints {
+			if _, ok := con.(*parse.PrimaryKeyInlineConstraint); ok {
+				pkCols = append(pkCols, col.Name)
+			}
+		}
+	}
+	defs = append(defs, "_history_seq INT8 PRIMARY KEY", "_changed_at TIMESTAMPTZ", "_operation CHAR(1) NOT NULL")
+
+	err := execute(ctx, db, fmt.Sprintf(`CREATE TABLE %s.%s (%s)`, namespace, historyTable, strings.Join(defs, ", ")))
+	if err != nil {
+		return err
+	}
+
+	// index the primary key, since history is read by primary key
+	for _, con := range stmt.Constraints {
+		if pk, ok := con.Constraint.(*parse.PrimaryKeyOutOfLineConstraint); ok {
+			pkCols = pk.Columns
+		}
+	}
+	if len(pkCols) > 0 {
+		err = execute(ctx, db, fmt.Sprintf(`CREATE INDEX ON %s.%s (%s)`, namespace, historyTable, strings.Join(pkCols, ", ")))
+		if err != nil {
+			return err
+		}
+	}
+
+	return execute(ctx, db, fmt.Sprintf(`CREATE TRIGGER record_history AFTER UPDATE OR DELETE ON %s.%s
+	FOR EACH ROW EXECUTE FUNCTION kwild_engine.record_history()`, namespace, stmt.Name))
+}
+
+// dropHistoryTable drops the history table of a table, if it has one.
+func dropHistoryTable(ctx context.Context, db sql.DB, namespace, table string) error {
+	return execute(ctx, db, fmt.Sprintf(`DROP TABLE IF EXISTS %s.%s%s`, namespace, engine.HistoryTablePrefix, table))
+}
+
+// alterHistoryTable keeps the history table of a table in sync with an ALTER TABLE
+// action. Dropped columns are kept in the history table, so that their history
+// is not lost.
+func alterHistoryTable(ctx context.Context, db sql.DB, namespace, table string, action parse.AlterTableAction) error {
+	historyTable := namespace + "." + engine.HistoryTablePrefix + table
+
+	switch action := action.(type) {
+	case *parse.AddColumn:
+		typ, err := action.Type.PGString()
+		if err != nil {
+			return err
+		}
+		return execute(ctx, db, fmt.Sprintf(`ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s %s`, historyTable, action.Name, typ))
+	case *parse.RenameColumn:
+		return execute(ctx, db, fmt.Sprintf(`ALTER TABLE %s RENAME COLUMN %s TO %s`, historyTable, action.OldName, action.NewName))
+	case *parse.RenameTable:
+		return execute(ctx, db, fmt.Sprintf(`ALTER TABLE %s RENAME TO %s%s`, historyTable, engine.HistoryTablePrefix, action.Name))
+	}
+
+	return nil
+}
+
+// listHistoryTables lists the tables in a namespace that have a history table.
+func listHistoryTables(ctx context.Context, db sql.DB, namespace string) (map[string]bool, error) {
+	tables := make(map[string]bool)
+	var historyTable string
+	err := queryRowFunc(ctx, db, `SELECT tablename::text FROM pg_tables
+	WHERE schemaname = $1 AND starts_with(tablename, $2)`, []any{&historyTable},
+		func() error {
+			tables[strings.TrimPrefix(historyTable, engine.HistoryTablePrefix)] = true
+			return nil
+		}, namespace, engine.HistoryTablePrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	return tables, nil
+}
+
+// hasHistoryTables returns true if any table has a history table.
+func (i *baseInterpreter) hasHistoryTables() bool {
+	for _, ns := range i.namespaces {
+		for _, tbl := range ns.tables {
+			if tbl.History {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// setHistoryTimestamp sets the block timestamp used by the history trigger for
+// the rest of the transaction.
+func (e *executionContext) setHistoryTimestamp() error {
+	if e.engineCtx.InvalidTxCtx || e.engineCtx.TxContext.BlockContext == nil {
+		return nil
+	}
+
+	return execute(e.engineCtx.TxContext.Ctx, e.db, `SELECT set_config('kwild.block_timestamp', $1, true)`,
+		fmt.Sprint(e.engineCtx.TxContext.BlockContext.Timestamp))
+}
+
+// getRowHistory returns the previous states of the row of a history table with
+// the given primary key, most recent first. It implements get_row_history. Each
+// row has the table's current columns, plus history_changed_at and
+// history_operation.
+func (e *executionContext) getRowHistory(tableName, pk, limit value, fn resultFunc) error {
+	if tableName.Null() {
+		return errors.New("table name cannot be null")
+	}
+	if pk.Null() {
+		return errors.New("primary key cannot be null")
+	}
+
+	if err := e.checkPrivilege(_SELECT_PRIVILEGE); err != nil {
+		return err
+	}
+
+	tbl, err := e.getTable("", tableName.RawValue().(string))
+	if err != nil {
+		return err
+	}
+	if !tbl.History {
+		return fmt.Errorf(`table "%s" does not have history`, tbl.Name)
+	}
+
+	pkCols := tbl.PrimaryKeyCols()
+	if len(pkCols) != 1 {
+		return fmt.Errorf(`table "%s" must have a single column primary key to get row history`, tbl.Name)
+	}
+
+	pk, err = pk.Cast(pkCols[0].DataType)
+	if err != nil {
+		return err
+	}
+	pkCast, err := engine.MakeTypeCast(pkCols[0].DataType)
+	if err != nil {
+		return err
+	}
+
+	var selectCols []string
+	var columns []string
+	var scanValues []any
+	for _, col := range tbl.Columns {
+		zv, err := newZeroValue(col.DataType)
+		if err != nil {
+			return err
+		}
+		selectCols = append(selectCols, col.Name)
+		columns = append(columns, col.Name)
+		scanValues = append(scanValues, zv)
+	}
+	selectCols = append(selectCols, "extract(epoch FROM _changed_at)::INT8", "_operation::TEXT")
+	columns = append(columns, historyChangedAtColumn, historyOperationColumn)
+	scanValues = append(scanValues, makeInt8(0), makeText(""))
+
+	args := []value{pk}
+	stmt := fmt.Sprintf(`SELECT %s FROM %s.%s%s WHERE %s = $1%s ORDER BY _history_seq DESC`,
+		strings.Join(selectCols, ", "), e.scope.namespace, engine.HistoryTablePrefix, tbl.Name, pkCols[0].Name, pkCast)
+	if !limit.Null() {
+		stmt += " LIMIT $2::INT8"
+		args = append(args, limit)
+	}
+
+	e.queryActive = true
+	defer func() { e.queryActive = false }()
+
+	return query(e.engineCtx.TxContext.Ctx, e.db, stmt, scanValues, func() error {
+		vals, err := fromScanValues(scanValues)
+		if err != nil {
+			return err
+		}
+
+		return fn(&row{
+			columns: columns,
+			Values:  vals,
+		})
+	}, args)
+}
@@ -0,0 +1,147 @@
+package interpreter
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+
+	"github.com/kwilteam/kwil-db/common"
+	"github.com/kwilteam/kwil-db/core/types"
+)
+
+// IdempotencyWindowBlocks is the number of blocks for which the result of a
+// call to an action declared with @idempotent is kept. A call with the same
+// caller and arguments made within the window returns the stored result. Since
+// it decides whether actions are executed, it must be the same on every node.
+const IdempotencyWindowBlocks = 1000
+
+// idempotentResult is the stored result of a call to an @idempotent action.
+type idempotentResult struct {
+	Logs []string         `json:"logs"`
+	Rows []*idempotentRow `json:"rows"`
+}
+
+// idempotentRow is a row returned by a call to an @idempotent action.
+type idempotentRow struct {
+	Columns []string              `json:"columns"`
+	Types   []*types.DataType     `json:"types"`
+	Values  []*types.EncodedValue `json:"values"`
+}
+
+// recorder returns a result function that records the rows of the call before
+// passing them to fn.
+func (r *idempotentResult) recorder(fn func(*common.Row) error) func(*common.Row) error {
+	return func(row *common.Row) error {
+		stored := &idempotentRow{
+			Columns: row.ColumnNames,
+			Types:   row.ColumnTypes,
+		}
+		for _, v := range row.Values {
+			ev, err := types.EncodeValue(v)
+			if err != nil {
+				return err
+			}
+			stored.Values = append(stored.Values, ev)
+		}
+		r.Rows = append(r.Rows, stored)
+
+		return fn(row)
+	}
+}
+
+// replay passes the stored rows to fn.
+func (r *idempotentResult) replay(fn func(*common.Row) error) error {
+	for _, stored := range r.Rows {
+		row := &common.Row{
+			ColumnNames: stored.Columns,
+			ColumnTypes: stored.Types,
+			Values:      make([]any, len(stored.Values)),
+		}
+		for i, ev := range stored.Values {
+			decoded, err := ev.Decode()
+			if err != nil {
+				return err
+			}
+			// values are decoded as pointers, and are converted back to
+			// the raw values that calls return
+			v, err := newValue(decoded)
+			if err != nil {
+				return err
+			}
+			row.Values[i] = v.RawValue()
+		}
+
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// idempotencyKey returns the hash that identifies a call to an @idempotent
+// action by its caller, action and arguments.
+func idempotencyKey(caller, namespace, action string, args []value) ([]byte, error) {
+	h := sha256.New()
+	write := func(b []byte) {
+		binary.Write(h, binary.BigEndian, uint32(len(b)))
+		h.Write(b)
+	}
+
+	write([]byte(caller))
+	write([]byte(namespace))
+	write([]byte(action))
+	for _, arg := range args {
+		ev, err := types.EncodeValue(arg.RawValue())
+		if err != nil {
+			return nil, err
+		}
+		bts, err := ev.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		write(bts)
+	}
+
+	return h.Sum(nil), nil
+}
+
+// storedIdempotentResult prunes the results that are older than the
+// idempotency window, and returns the stored result of the call with the key.
+// It returns nil if the call has not been made within the window.
+func (e *executionContext) storedIdempotentResult(key []byte) (*idempotentResult, error) {
+	ctx := e.engineCtx.TxContext.Ctx
+	height := e.engineCtx.TxContext.BlockContext.Height
+
+	err := execute(ctx, e.db, `DELETE FROM kwild_engine.call_history WHERE block_height <= $1`, height-IdempotencyWindowBlocks)
+	if err != nil {
+		return nil, err
+	}
+
+	var data []byte
+	err = queryRowFunc(ctx, e.db, `SELECT result FROM kwild_engine.call_history WHERE call_hash = $1`,
+		[]any{&data}, func() error { return nil }, key)
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return nil, nil
+	}
+
+	res := &idempotentResult{}
+	if err = json.Unmarshal(data, res); err != nil {
+		return nil, fmt.Errorf("failed to decode stored call result: %w", err)
+	}
+	return res, nil
+}
+
+// storeIdempotentResult stores the result of the call with the key.
+func (e *executionContext) storeIdempotentResult(key []byte, namespace string, res *idempotentResult) error {
+	data, err := json.Marshal(res)
+	if err != nil {
+		return err
+	}
+
+	return execute(e.engineCtx.TxContext.Ctx, e.db, `INSERT INTO kwild_engine.call_history (call_hash, namespace, block_height, result)
+	VALUES ($1, $2, $3, $4)`, key, namespace, e.engineCtx.TxContext.BlockContext.Height, data)
+}
@@ -0,0 +1,76 @@
+package interpreter
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kwilteam/kwil-db/common"
+	"github.com/kwilteam/kwil-db/core/types"
+)
+
+func Test_IdempotencyKey(t *testing.T) {
+	key := func(caller, action string, args ...value) []byte {
+		k, err := idempotencyKey(caller, "main", action, args)
+		require.NoError(t, err)
+		return k
+	}
+
+	pay := key("alice", "pay", makeText("bob"), makeInt8(100))
+	require.Equal(t, pay, key("alice", "pay", makeText("bob"), makeInt8(100)))
+
+	require.NotEqual(t, pay, key("carol", "pay", makeText("bob"), makeInt8(100)))
+	require.NotEqual(t, pay, key("alice", "refund", makeText("bob"), makeInt8(100)))
+	require.NotEqual(t, pay, key("alice", "pay", makeText("bob"), makeInt8(101)))
+	// the arguments are length prefixed, so they cannot run into each other
+	require.NotEqual(t, key("alice", "pay", makeText("ab"), makeText("c")), key("alice", "pay", makeText("a"), makeText("bc")))
+}
+
+func Test_IdempotentResultReplay(t *testing.T) {
+	rows := []*common.Row{
+		{
+			ColumnNames: []string{"id", "memo"},
+			ColumnTypes: []*types.DataType{types.IntType, types.TextType},
+			Values:      []any{int64(1), "first"},
+		},
+		{
+			ColumnNames: []string{"id", "memo"},
+			ColumnTypes: []*types.DataType{types.IntType, types.TextType},
+			Values:      []any{int64(2), nil},
+		},
+	}
+
+	var passed []*common.Row
+	res := &idempotentResult{Logs: []string{"paid"}}
+	record := res.recorder(func(r *common.Row) error {
+		passed = append(passed, r)
+		return nil
+	})
+	for _, row := range rows {
+		require.NoError(t, record(row))
+	}
+	require.Equal(t, rows, passed)
+
+	// the result is stored as JSON
+	data, err := json.Marshal(res)
+	require.NoError(t, err)
+	stored := &idempotentResult{}
+	require.NoError(t, json.Unmarshal(data, stored))
+	require.Equal(t, []string{"paid"}, stored.Logs)
+
+	var replayed []*common.Row
+	err = stored.replay(func(r *common.Row) error {
+		replayed = append(replayed, r)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, replayed, 2)
+	for i := range rows {
+		require.Equal(t, rows[i].ColumnNames, replayed[i].ColumnNames)
+		require.Equal(t, rows[i].Values, replayed[i].Values)
+		for j, typ := range rows[i].ColumnTypes {
+			require.True(t, typ.Equals(replayed[i].ColumnTypes[j]))
+		}
+	}
+}
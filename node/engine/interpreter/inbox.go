@@ -0,0 +1,166 @@
+package interpreter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/kwilteam/kwil-db/common"
+	"github.com/kwilteam/kwil-db/core/types"
+	"github.com/kwilteam/kwil-db/node/engine"
+	"github.com/kwilteam/kwil-db/node/types/sql"
+)
+
+// The statuses of the messages of an inbox.
+const (
+	inboxPending    = "pending"
+	inboxProcessing = "processing"
+	inboxDone       = "done"
+)
+
+// inboxTable returns the name of the inbox table of an action.
+func inboxTable(namespace, action string) string {
+	return fmt.Sprintf("kwild_engine.inbox_%s_%s", namespace, action)
+}
+
+// RegisterInbox creates the inbox of an action. Messages sent to the inbox
+// are delivered to the action by ConsumeInbox. Each message has an ID, so
+// that a message that is sent again, e.g. because a network call was retried,
+// is only stored once.
+func (t *ThreadSafeInterpreter) RegisterInbox(ctx context.Context, db sql.DB, namespace, action string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	ns, ok := t.i.namespaces[namespace]
+	if !ok {
+		return fmt.Errorf(`%w: "%s"`, engine.ErrNamespaceNotFound, namespace)
+	}
+	if _, ok = ns.availableFunctions[action]; !ok {
+		return &engine.ActionNotFoundError{Namespace: namespace, Action: action}
+	}
+
+	return execute(ctx, db, fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		message_id UUID PRIMARY KEY,
+		args BYTEA NOT NULL,
+		status TEXT NOT NULL DEFAULT '%s' CHECK (status IN ('%s', '%s', '%s'))
+	)`, inboxTable(namespace, action), inboxPending, inboxPending, inboxProcessing, inboxDone))
+}
+
+// SendToInbox sends a message with the arguments of a call to the inbox of
+// an action. Sending a message with the ID of a message that was already sent
+// does nothing.
+func (t *ThreadSafeInterpreter) SendToInbox(ctx context.Context, db sql.DB, namespace, action string, messageID *types.UUID, args []any) error {
+	encoded := make([]*types.EncodedValue, len(args))
+	for i, arg := range args {
+		ev, err := types.EncodeValue(arg)
+		if err != nil {
+			return err
+		}
+		encoded[i] = ev
+	}
+
+	data, err := json.Marshal(encoded)
+	if err != nil {
+		return err
+	}
+
+	return execute(ctx, db, fmt.Sprintf(`INSERT INTO %s (message_id, args) VALUES ($1, $2)
+	ON CONFLICT (message_id) DO NOTHING`, inboxTable(namespace, action)), messageID, data)
+}
+
+// inboxMessage is a message claimed from an inbox.
+type inboxMessage struct {
+	id   *types.UUID
+	args []any
+}
+
+// ConsumeInbox delivers up to batchSize messages of the inbox of an action,
+// in the order of their IDs. The messages are marked as processing, the action
+// is called with the arguments of each, and each message is marked as done
+// once its call succeeds.
+//
+// Messages that are still marked as processing, because an earlier
+// ConsumeInbox failed before marking them as done, are delivered again. Thus
+// each message is delivered at least once. Actions that must only process a
+// message once should be declared with @idempotent, so that a call that is
+// delivered again returns the stored result instead of being executed.
+func (t *ThreadSafeInterpreter) ConsumeInbox(ctx *common.EngineContext, db sql.DB, namespace, action string, batchSize int) error {
+	if batchSize <= 0 {
+		return fmt.Errorf("batch size must be positive, got %d", batchSize)
+	}
+
+	table := inboxTable(namespace, action)
+
+	// the messages are claimed first, since the action cannot be called while
+	// the claimed rows are being read
+	var msgs []*inboxMessage
+	id := &types.UUID{}
+	var data []byte
+	err := queryRowFunc(ctx.TxContext.Ctx, db, fmt.Sprintf(`UPDATE %s SET status = '%s'
+	WHERE message_id IN (
+		SELECT message_id FROM %s WHERE status <> '%s' ORDER BY message_id LIMIT $1 FOR UPDATE SKIP LOCKED
+	) RETURNING message_id, args`, table, inboxProcessing, table, inboxDone),
+		[]any{id, &data}, func() error {
+			args, err := decodeInboxArgs(data)
+			if err != nil {
+				return err
+			}
+
+			idCopy := *id
+			msgs = append(msgs, &inboxMessage{id: &idCopy, args: args})
+			return nil
+		}, batchSize)
+	if err != nil {
+		return err
+	}
+
+	// UPDATE ... RETURNING does not return the rows in the order of the
+	// subquery
+	slices.SortFunc(msgs, func(a, b *inboxMessage) int {
+		return bytes.Compare(a.id[:], b.id[:])
+	})
+
+	for _, msg := range msgs {
+		res, err := t.Call(ctx, db, namespace, action, msg.args, nil)
+		if err != nil {
+			return fmt.Errorf("failed to deliver message %s: %w", msg.id, err)
+		}
+		if res.Error != nil {
+			return fmt.Errorf("failed to deliver message %s: %w", msg.id, res.Error)
+		}
+
+		err = execute(ctx.TxContext.Ctx, db, fmt.Sprintf(`UPDATE %s SET status = '%s' WHERE message_id = $1`, table, inboxDone), msg.id)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// decodeInboxArgs decodes the arguments of an inbox message.
+func decodeInboxArgs(data []byte) ([]any, error) {
+	var encoded []*types.EncodedValue
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return nil, fmt.Errorf("failed to decode inbox message: %w", err)
+	}
+
+	args := make([]any, len(encoded))
+	for i, ev := range encoded {
+		decoded, err := ev.Decode()
+		if err != nil {
+			return nil, err
+		}
+		// values are decoded as pointers, and are converted back to the raw
+		// values that calls take
+		v, err := newValue(decoded)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = v.RawValue()
+	}
+
+	return args, nil
+}
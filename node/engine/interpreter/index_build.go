@@ -0,0 +1,568 @@
+package interpreter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/kwilteam/kwil-db/common"
+	"github.com/kwilteam/kwil-db/node/engine"
+	"github.com/kwilteam/kwil-db/node/engine/parse"
+	pggenerate "github.com/kwilteam/kwil-db/node/engine/pg_generate"
+	"github.com/kwilteam/kwil-db/node/pg"
+	"github.com/kwilteam/kwil-db/node/types/sql"
+)
+
+// The phases of an IndexStatus that are not reported by Postgres.
+const (
+	// IndexBuildPending is the phase of a build that waits for the
+	// transaction that created the index to finish.
+	IndexBuildPending = "pending"
+	// IndexBuildValid is the phase of an index that is built, and is used by
+	// queries.
+	IndexBuildValid = "valid"
+	// IndexBuildFailed is the phase of a build that failed. It is retried
+	// when the node restarts, or when a statement changes the namespace.
+	IndexBuildFailed = "failed"
+	// IndexBuildCancelled is the phase of a build whose transaction was
+	// rolled back, or whose index was dropped.
+	IndexBuildCancelled = "cancelled"
+)
+
+// indexBuilding is the phase of a build that Postgres started. It is not
+// reported, since Postgres reports the phases of the build.
+const indexBuilding = "building"
+
+// indexBuildPoll is how often a build checks whether the transaction it waits
+// for has finished.
+const indexBuildPoll = 100 * time.Millisecond
+
+// IndexStatus is the status of an index created with CREATE INDEX
+// CONCURRENTLY.
+type IndexStatus struct {
+	// Phase is the phase of the build. While Postgres builds the index, it
+	// is the phase reported by pg_stat_progress_create_index, e.g.
+	// "building index: scanning table". Otherwise, it is one of the
+	// IndexBuild phases.
+	Phase string
+	// BlocksDone and BlocksTotal are the blocks processed in the current
+	// phase, if Postgres reports them.
+	BlocksDone  int64
+	BlocksTotal int64
+	// TuplesDone and TuplesTotal are the tuples processed in the current
+	// phase, if Postgres reports them.
+	TuplesDone  int64
+	TuplesTotal int64
+	// Valid is true if the index is built.
+	Valid bool
+	// Err is the error a build failed with, if it failed since the node
+	// started.
+	Err error
+}
+
+// indexBuilds builds the indexes created with CREATE INDEX CONCURRENTLY.
+//
+// An index created concurrently is stored in kwild_engine.concurrent_indexes
+// by the transaction that creates it, and exists from the commit of that
+// transaction on: statements and info.indexes see it whether or not this
+// node has built it. Each node builds it in the background once the
+// transaction commits, and resumes the builds that did not finish when it
+// restarts. Statements that lock the tables of a namespace cancel the builds
+// of the namespace before they run, since a build waits for the transaction
+// of the statement, and restart them once the transaction finishes.
+type indexBuilds struct {
+	service *common.Service
+
+	// ctx is cancelled when the builds are stopped.
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu sync.Mutex
+	// builds are keyed by the schema and name of their index.
+	builds map[[2]string]*indexBuild
+	// pool is used to read the status of builds. It is connected when it is
+	// first needed.
+	pool *pg.Pool
+}
+
+// indexBuild is a build of an index. Its fields are guarded by the mutex of
+// indexBuilds.
+type indexBuild struct {
+	// phase is one of the IndexBuild phases, or indexBuilding.
+	phase string
+	err   error
+	// cancel stops the build, and done is closed once it has stopped.
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func newIndexBuilds(service *common.Service) *indexBuilds {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &indexBuilds{
+		service: service,
+		ctx:     ctx,
+		cancel:  cancel,
+		builds:  make(map[[2]string]*indexBuild),
+	}
+}
+
+// createIndexConcurrently creates an index that is built without locking its
+// table against writes. The index is stored, so that it exists from the
+// commit of the transaction of exec on, and is built once the transaction
+// commits. Since nodes build it at different times, only indexes that do not
+// change the results of statements can be created concurrently.
+func createIndexConcurrently(exec *executionContext, stmt *parse.CreateIndexStatement) error {
+	// a unique index rejects writes that duplicate its columns, so nodes that
+	// finished building it would reject writes that others accept
+	if stmt.Type == parse.IndexTypeUnique {
+		return errors.New("unique indexes cannot be created concurrently")
+	}
+	// the status of a build is looked up by the name of its index
+	if stmt.Name == "" {
+		return errors.New("indexes created concurrently must be named")
+	}
+
+	exists, err := relationExists(exec, stmt.Name)
+	if err != nil {
+		return err
+	}
+	if exists {
+		if stmt.IfNotExists {
+			return nil
+		}
+		return fmt.Errorf(`relation "%s" already exists`, stmt.Name)
+	}
+
+	ctx := exec.engineCtx.TxContext.Ctx
+	err = execute(ctx, exec.db, `INSERT INTO kwild_engine.concurrent_indexes (namespace, name, table_name, columns) VALUES ($1, $2, $3, $4)`,
+		exec.scope.namespace, stmt.Name, stmt.On, stmt.Columns)
+	if err != nil {
+		return err
+	}
+
+	if err = exec.interpreter.indexBuilds.scheduleAfter(ctx, exec.db, exec.scope.namespace, stmt.Name); err != nil {
+		return err
+	}
+
+	// we reload tables here because we track indexes in the table object
+	return exec.reloadNamespaceCache()
+}
+
+// relationExists returns true if a relation with the name exists in the
+// namespace of exec, including an index created concurrently that this node
+// has not built yet.
+func relationExists(exec *executionContext, name string) (bool, error) {
+	var exists bool
+	err := queryRowFunc(exec.engineCtx.TxContext.Ctx, exec.db, `SELECT to_regclass($1) IS NOT NULL`,
+		[]any{&exists}, func() error { return nil }, exec.scope.namespace+"."+name)
+	if err != nil || exists {
+		return exists, err
+	}
+
+	return concurrentIndexExists(exec, name)
+}
+
+// concurrentIndexExists returns true if an index with the name was created
+// concurrently in the namespace of exec. Since nodes build the index at
+// different times, statements must check for it rather than rely on Postgres
+// to report that it exists.
+func concurrentIndexExists(exec *executionContext, name string) (bool, error) {
+	var exists bool
+	err := queryRowFunc(exec.engineCtx.TxContext.Ctx, exec.db, `SELECT EXISTS (SELECT 1 FROM kwild_engine.concurrent_indexes WHERE namespace = $1 AND name = $2)`,
+		[]any{&exists}, func() error { return nil }, exec.scope.namespace, name)
+	return exists, err
+}
+
+// dropConcurrentIndex deletes an index that was created concurrently. It
+// returns false if the index was not created concurrently. Since this node
+// may not have built the index, it is dropped with IF EXISTS.
+func dropConcurrentIndex(exec *executionContext, name string) (bool, error) {
+	found := false
+	err := queryRowFunc(exec.engineCtx.TxContext.Ctx, exec.db, `DELETE FROM kwild_engine.concurrent_indexes WHERE namespace = $1 AND name = $2 RETURNING name`,
+		[]any{new(string)}, func() error {
+			found = true
+			return nil
+		}, exec.scope.namespace, name)
+	if err != nil || !found {
+		return false, err
+	}
+
+	return true, execute(exec.engineCtx.TxContext.Ctx, exec.db, fmt.Sprintf(`DROP INDEX IF EXISTS %s.%s`, exec.scope.namespace, name))
+}
+
+// updateConcurrentIndexes keeps the indexes created concurrently in sync with
+// an altered table.
+func updateConcurrentIndexes(ctx context.Context, db sql.DB, namespace, table string, action parse.AlterTableAction) error {
+	switch action := action.(type) {
+	case *parse.DropColumn:
+		// Postgres drops the indexes of the column
+		return execute(ctx, db, `DELETE FROM kwild_engine.concurrent_indexes WHERE namespace = $1 AND table_name = $2 AND $3 = ANY(columns)`,
+			namespace, table, action.Name)
+	case *parse.RenameColumn:
+		return execute(ctx, db, `UPDATE kwild_engine.concurrent_indexes SET columns = array_replace(columns, $3, $4) WHERE namespace = $1 AND table_name = $2`,
+			namespace, table, action.OldName, action.NewName)
+	case *parse.RenameTable:
+		return execute(ctx, db, `UPDATE kwild_engine.concurrent_indexes SET table_name = $3 WHERE namespace = $1 AND table_name = $2`,
+			namespace, table, action.Name)
+	}
+	return nil
+}
+
+// dropTableConcurrentIndexes deletes the indexes created concurrently on a
+// dropped table.
+func dropTableConcurrentIndexes(ctx context.Context, db sql.DB, namespace, table string) error {
+	return execute(ctx, db, `DELETE FROM kwild_engine.concurrent_indexes WHERE namespace = $1 AND table_name = $2`, namespace, table)
+}
+
+// pause cancels the builds of the indexes of a namespace, and restarts them
+// once the transaction of db finishes. It must be called before statements
+// that lock the tables of the namespace against the builds, since a build
+// waits for the transaction of the statement to finish.
+func (b *indexBuilds) pause(ctx context.Context, db sql.DB, namespace string) error {
+	var names []string
+	var name string
+	err := queryRowFunc(ctx, db, `SELECT name FROM kwild_engine.concurrent_indexes WHERE namespace = $1 ORDER BY name`,
+		[]any{&name}, func() error {
+			names = append(names, name)
+			return nil
+		}, namespace)
+	if err != nil {
+		return err
+	}
+
+	// builds that are running were started before the transaction, so they
+	// may be of indexes that the transaction deleted
+	b.mu.Lock()
+	for key := range b.builds {
+		if key[0] == namespace && !slices.Contains(names, key[1]) {
+			names = append(names, key[1])
+		}
+	}
+	b.mu.Unlock()
+
+	for _, name := range names {
+		if err = b.scheduleAfter(ctx, db, namespace, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resume restarts the builds of all indexes created concurrently, once the
+// transaction of db finishes. Builds of indexes that are already built finish
+// immediately.
+func (b *indexBuilds) resume(ctx context.Context, db sql.DB) error {
+	var keys [][2]string
+	var namespace, name string
+	err := queryRowFunc(ctx, db, `SELECT namespace, name FROM kwild_engine.concurrent_indexes ORDER BY namespace, name`,
+		[]any{&namespace, &name}, func() error {
+			keys = append(keys, [2]string{namespace, name})
+			return nil
+		})
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		if err = b.scheduleAfter(ctx, db, key[0], key[1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// scheduleAfter cancels the build of an index, if any, and starts a new one
+// that waits for the transaction of db to finish. The build then makes the
+// index match what is committed: it builds the index if it is stored and not
+// built yet, and does nothing otherwise. Failures of the build are only
+// reported by its status, and never fail the transaction.
+func (b *indexBuilds) scheduleAfter(ctx context.Context, db sql.DB, schema, name string) error {
+	var txid int64
+	err := queryRowFunc(ctx, db, `SELECT txid_current()`, []any{&txid}, func() error { return nil })
+	if err != nil {
+		return err
+	}
+
+	key := [2]string{schema, name}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// the builds have been stopped, and are resumed when the node restarts
+	if b.ctx.Err() != nil {
+		return nil
+	}
+
+	if prev, ok := b.builds[key]; ok && prev.cancel != nil {
+		prev.cancel()
+		// the previous build only takes the mutex to record its result, so
+		// it is released while the build stops
+		b.mu.Unlock()
+		<-prev.done
+		b.mu.Lock()
+	}
+
+	buildCtx, cancel := context.WithCancel(b.ctx)
+	build := &indexBuild{
+		phase:  IndexBuildPending,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	b.builds[key] = build
+
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		defer close(build.done)
+		defer cancel()
+
+		err := b.run(buildCtx, build, txid, schema, name)
+		if err != nil && !errors.Is(err, context.Canceled) && b.service != nil && b.service.Logger != nil {
+			b.service.Logger.Errorf("failed to create index %s.%s: %v", schema, name, err)
+		}
+	}()
+
+	return nil
+}
+
+// connect returns the pool used to read the status of builds, connecting it
+// if needed. b.mu must be held.
+func (b *indexBuilds) connect(ctx context.Context) (*pg.Pool, error) {
+	if b.pool != nil {
+		return b.pool, nil
+	}
+	if b.service == nil {
+		return nil, errors.New("the service is required to connect to postgres")
+	}
+
+	pool, err := newServicePool(ctx, b.service)
+	if err != nil {
+		return nil, err
+	}
+	b.pool = pool
+	return pool, nil
+}
+
+// setPhase records the phase of a build.
+func (b *indexBuilds) setPhase(build *indexBuild, phase string, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	build.phase, build.err = phase, err
+	if phase != IndexBuildPending && phase != indexBuilding {
+		build.cancel = nil
+	}
+}
+
+// run waits for the transaction with the ID txid to finish, and builds the
+// index if it is stored and not yet built. CREATE INDEX CONCURRENTLY cannot
+// run in a transaction, and waits for the transactions that are older than
+// it, so each build connects its own pool.
+func (b *indexBuilds) run(ctx context.Context, build *indexBuild, txid int64, schema, name string) (err error) {
+	defer func() {
+		if errors.Is(err, context.Canceled) {
+			// the build is replaced, or the node is stopping
+			b.setPhase(build, IndexBuildCancelled, nil)
+		} else if err != nil {
+			b.setPhase(build, IndexBuildFailed, err)
+		}
+	}()
+
+	if b.service == nil {
+		return errors.New("the service is required to connect to postgres")
+	}
+	pool, err := newServicePool(ctx, b.service)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	for {
+		var status *string
+		err = queryRowFunc(ctx, pool, `SELECT txid_status($1)`, []any{&status}, func() error { return nil }, txid)
+		if err != nil {
+			return err
+		}
+		// the status is null if the transaction is too old to be known, in
+		// which case it has finished
+		if status == nil || *status != "in progress" {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(indexBuildPoll):
+		}
+	}
+
+	var table string
+	var columns []string
+	found := false
+	err = queryRowFunc(ctx, pool, `SELECT table_name, columns FROM kwild_engine.concurrent_indexes WHERE namespace = $1 AND name = $2`,
+		[]any{&table, &columns}, func() error {
+			found = true
+			return nil
+		}, schema, name)
+	if err != nil {
+		return err
+	}
+	if !found {
+		// the transaction that created the index was rolled back, or the
+		// index was dropped
+		b.setPhase(build, IndexBuildCancelled, nil)
+		return nil
+	}
+
+	var valid bool
+	built := false
+	err = queryRowFunc(ctx, pool, `SELECT x.indisvalid FROM pg_index x
+	JOIN pg_class i ON i.oid = x.indexrelid
+	JOIN pg_namespace n ON n.oid = i.relnamespace
+	WHERE n.nspname = $1 AND i.relname = $2`, []any{&valid}, func() error {
+		built = true
+		return nil
+	}, schema, name)
+	if err != nil {
+		return err
+	}
+	if built && valid {
+		b.setPhase(build, IndexBuildValid, nil)
+		return nil
+	}
+
+	// a build that failed or was cancelled leaves the index invalid
+	if built {
+		if _, err = pool.Execute(ctx, fmt.Sprintf(`DROP INDEX CONCURRENTLY IF EXISTS %s.%s`, schema, name)); err != nil {
+			return err
+		}
+	}
+
+	stmt, _, err := pggenerate.GenerateSQL(&parse.CreateIndexStatement{
+		Name:         name,
+		On:           table,
+		Columns:      columns,
+		Type:         parse.IndexTypeBTree,
+		Concurrently: true,
+	}, schema, nil)
+	if err != nil {
+		return fmt.Errorf("%w: %w", engine.ErrPGGen, err)
+	}
+
+	b.setPhase(build, indexBuilding, nil)
+	if _, err = pool.Execute(ctx, stmt); err != nil {
+		return err
+	}
+
+	b.setPhase(build, IndexBuildValid, nil)
+	return nil
+}
+
+// stop cancels the builds, waits for them to stop, and closes the pool.
+func (b *indexBuilds) stop() error {
+	b.cancel()
+	b.wg.Wait()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.pool == nil {
+		return nil
+	}
+	err := b.pool.Close()
+	b.pool = nil
+	return err
+}
+
+// StopIndexBuilds stops building the indexes created with CREATE INDEX
+// CONCURRENTLY. The builds that did not finish are resumed when the node
+// restarts.
+func (t *ThreadSafeInterpreter) StopIndexBuilds() error {
+	t.mu.RLock()
+	b := t.i.indexBuilds
+	t.mu.RUnlock()
+
+	return b.stop()
+}
+
+// IndexBuildStatus returns the status of an index of a table that was created
+// with CREATE INDEX CONCURRENTLY. It reads the progress of the build from
+// pg_stat_progress_create_index while Postgres builds the index.
+func (t *ThreadSafeInterpreter) IndexBuildStatus(ctx context.Context, namespace, table, indexName string) (IndexStatus, error) {
+	t.mu.RLock()
+	b := t.i.indexBuilds
+	t.mu.RUnlock()
+
+	b.mu.Lock()
+	pool, err := b.connect(ctx)
+	if err != nil {
+		b.mu.Unlock()
+		return IndexStatus{}, err
+	}
+	var build indexBuild
+	if bld, ok := b.builds[[2]string{namespace, indexName}]; ok {
+		build = *bld
+	}
+	b.mu.Unlock()
+
+	tx, err := pool.BeginReadTx(ctx)
+	if err != nil {
+		return IndexStatus{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	var status IndexStatus
+	found := false
+	err = queryRowFunc(ctx, tx, `SELECT p.phase, p.blocks_done, p.blocks_total, p.tuples_done, p.tuples_total
+	FROM pg_stat_progress_create_index p
+	JOIN pg_class i ON i.oid = p.index_relid
+	JOIN pg_class t ON t.oid = p.relid
+	JOIN pg_namespace n ON n.oid = t.relnamespace
+	WHERE n.nspname = $1 AND t.relname = $2 AND i.relname = $3`,
+		[]any{&status.Phase, &status.BlocksDone, &status.BlocksTotal, &status.TuplesDone, &status.TuplesTotal},
+		func() error {
+			found = true
+			return nil
+		}, namespace, table, indexName)
+	if err != nil {
+		return IndexStatus{}, err
+	}
+	if found {
+		return status, nil
+	}
+
+	// the index is not being built, so it is either built, left invalid by a
+	// failed build, or not built yet
+	err = queryRowFunc(ctx, tx, `SELECT x.indisvalid
+	FROM pg_index x
+	JOIN pg_class i ON i.oid = x.indexrelid
+	JOIN pg_class t ON t.oid = x.indrelid
+	JOIN pg_namespace n ON n.oid = t.relnamespace
+	WHERE n.nspname = $1 AND t.relname = $2 AND i.relname = $3`,
+		[]any{&status.Valid}, func() error {
+			found = true
+			return nil
+		}, namespace, table, indexName)
+	if err != nil {
+		return IndexStatus{}, err
+	}
+
+	switch {
+	case found && status.Valid:
+		status.Phase = IndexBuildValid
+	case build.phase == IndexBuildPending || build.phase == indexBuilding:
+		// Postgres has not reported the build yet
+		status.Phase = IndexBuildPending
+	case found:
+		status.Phase, status.Err = IndexBuildFailed, build.err
+	case build.phase == IndexBuildFailed || build.phase == IndexBuildCancelled:
+		status.Phase, status.Err = build.phase, build.err
+	default:
+		return IndexStatus{}, fmt.Errorf(`index "%s" of table "%s" does not exist`, indexName, table)
+	}
+
+	return status, nil
+}
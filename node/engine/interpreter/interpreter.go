@@ -7,13 +7,16 @@ import (
 	"fmt"
 	"maps"
 	"regexp"
+	"runtime"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/decred/dcrd/container/lru"
 
 	"github.com/kwilteam/kwil-db/common"
+	"github.com/kwilteam/kwil-db/core/log"
 	"github.com/kwilteam/kwil-db/core/types"
 	"github.com/kwilteam/kwil-db/core/types/validation"
 	"github.com/kwilteam/kwil-db/core/utils/order"
@@ -21,10 +24,85 @@ import (
 	"github.com/kwilteam/kwil-db/extensions/precompiles"
 	"github.com/kwilteam/kwil-db/node/engine"
 	"github.com/kwilteam/kwil-db/node/engine/parse"
+	"github.com/kwilteam/kwil-db/node/metrics"
 	"github.com/kwilteam/kwil-db/node/pg"
 	"github.com/kwilteam/kwil-db/node/types/sql"
 )
 
+// InterpreterOptions are the optional settings of an interpreter.
+type InterpreterOptions struct {
+	// CDCPublisher receives the row changes made by each successful action.
+	CDCPublisher CDCPublisher
+	// MaxConcurrentCalls is the maximum number of calls and executions that
+	// can be in progress at once. Once reached, further calls fail with
+	// engine.ErrBackpressure. If zero, there is no limit. Calls and executions
+	// with a read-write database, such as those of block execution, are not
+	// limited, since whether they fail must not depend on the load of the
+	// node.
+	MaxConcurrentCalls int
+	// CircuitBreaker stops calling extensions whose calls keep failing.
+	CircuitBreaker *CircuitBreaker
+	// FairScheduler shares the interpreter between namespaces under load.
+	FairScheduler *FairScheduler
+	// BlockStore provides the blocks replayed by ReplayTransactions.
+	BlockStore BlockStore
+	// Tenants routes calls to the namespaces of the tenants that make them.
+	Tenants *TenantConfig
+	// AnomalyDetector is given every call, and detects unusual patterns of
+	// calls.
+	AnomalyDetector AnomalyDetector
+	// DisableQueryAdvisor stops the interpreter from logging suggestions for
+	// the queries of calls whose plans are suboptimal. The advisor explains
+	// each distinct query once, so it may be disabled in production.
+	DisableQueryAdvisor bool
+	// GossipEnabled makes the namespaces deployed and undeployed by
+	// executions available from NamespaceEvents, so that they can be
+	// gossiped to other nodes.
+	GossipEnabled bool
+	// ReadCache are the namespaces whose read-only calls are cached, and how
+	// long their results are kept.
+	ReadCache map[string]time.Duration
+	// Clock returns the current time. It is used to expire the results of the
+	// read cache. If nil, time.Now is used.
+	Clock func() time.Time
+}
+
+// InterpreterOpt sets an option of an interpreter.
+type InterpreterOpt func(*InterpreterOptions)
+
+// WithCDCPublisher publishes the row changes made by each successful action to
+// the publisher, once the block containing the action is committed.
+func WithCDCPublisher(p CDCPublisher) InterpreterOpt {
+	return func(o *InterpreterOptions) {
+		o.CDCPublisher = p
+	}
+}
+
+// WithMaxConcurrentCalls limits the number of calls and executions with a
+// read-only database that can be in progress at once.
+func WithMaxConcurrentCalls(n int) InterpreterOpt {
+	return func(o *InterpreterOptions) {
+		o.MaxConcurrentCalls = n
+	}
+}
+
+// WithCircuitBreaker protects the extension calls of read-only calls with the
+// circuit breaker.
+func WithCircuitBreaker(cb *CircuitBreaker) InterpreterOpt {
+	return func(o *InterpreterOptions) {
+		o.CircuitBreaker = cb
+	}
+}
+
+// WithFairScheduler schedules calls so that, under load, each namespace gets a
+// share of the interpreter proportional to its weight. Namespaces without a
+// weight have a weight of 1. At most GOMAXPROCS calls run at once.
+func WithFairScheduler(weights map[string]int) InterpreterOpt {
+	return func(o *InterpreterOptions) {
+		o.FairScheduler = NewFairScheduler(runtime.GOMAXPROCS(0), weights)
+	}
+}
+
 // ThreadSafeInterpreter is a thread-safe interpreter.
 // It is defined as a separate struct because there are time where
 // the interpreter recursively calls itself, and we need to avoid
@@ -32,6 +110,59 @@ import (
 type ThreadSafeInterpreter struct {
 	mu sync.RWMutex
 	i  *baseInterpreter
+
+	// walMu guards wal.
+	walMu sync.Mutex
+	// wal is the stream of committed row changes, if started.
+	wal *walStream
+
+	// dtxMu guards distributedTxs.
+	dtxMu sync.Mutex
+	// distributedTxs are the distributed transactions that have not been
+	// committed or rolled back, keyed by ID.
+	distributedTxs map[string]*distributedTx
+
+	// sagas are the registered sagas, keyed by name. They are guarded by mu.
+	sagas map[string]*saga
+
+	// shards forwards calls to namespaces assigned to other nodes, if sharding
+	// is enabled.
+	shards *shardRouter
+
+	// affinity rejects calls to namespaces whose home node is another node,
+	// if namespace affinity is configured.
+	affinity *namespaceAffinity
+
+	// sem limits the number of concurrent calls and executions. It is nil if
+	// there is no limit.
+	sem chan struct{}
+
+	// scheduler admits calls fairly between namespaces, if enabled. Calls
+	// are admitted before the mutex is locked and released after it is
+	// unlocked, so a call waiting to be admitted never holds the mutex.
+	scheduler *FairScheduler
+
+	// blocks provides the blocks replayed by ReplayTransactions, if set.
+	blocks BlockStore
+
+	// tenants routes calls to the namespaces of tenants, if set.
+	tenants *TenantConfig
+
+	// anomalies detects unusual patterns of calls, if set.
+	anomalies AnomalyDetector
+
+	// baselines are the expected durations of calls to actions.
+	baselines *callBaselines
+
+	// nsEvents receives the namespaces deployed and undeployed by
+	// executions, if gossip is enabled.
+	nsEvents chan NamespaceEvent
+
+	// bridge serves the calls made by Postgres triggers, if started.
+	bridge *bridgeServer
+
+	// readCache caches the results of read-only calls, if enabled.
+	readCache *readCache
 }
 
 // lock locks the interpreter with either a read or write lock, depending on the access mode of the database.
@@ -50,27 +181,149 @@ func (t *ThreadSafeInterpreter) lock(db sql.DB) (unlock func(), err error) {
 	return t.mu.Unlock, nil
 }
 
-func (t *ThreadSafeInterpreter) Call(ctx *common.EngineContext, db sql.DB, namespace string, action string, args []any, resultFn func(*common.Row) error) (*common.CallResult, error) {
+// acquire reserves a slot for a call or execution with db, failing immediately
+// with engine.ErrBackpressure if none are free. Calls with a read-write
+// database are part of consensus, so they do not need a slot.
+func (t *ThreadSafeInterpreter) acquire(ctx context.Context, db sql.DB) (release func(), err error) {
+	if t.sem == nil {
+		return func() {}, nil
+	}
+	if am, ok := db.(sql.AccessModer); ok && am.AccessMode() == sql.ReadWrite {
+		return func() {}, nil
+	}
+
+	select {
+	case t.sem <- struct{}{}:
+	default:
+		metrics.Engine.BackpressureRejected(ctx)
+		return nil, engine.ErrBackpressure
+	}
+	metrics.Engine.Concurrency(ctx, len(t.sem), cap(t.sem))
+
+	return func() {
+		<-t.sem
+		metrics.Engine.Concurrency(ctx, len(t.sem), cap(t.sem))
+	}, nil
+}
+
+// CurrentConcurrency returns the number of calls and executions in progress.
+// It is always zero if the interpreter has no concurrency limit.
+func (t *ThreadSafeInterpreter) CurrentConcurrency() int {
+	return len(t.sem)
+}
+
+// MaxConcurrency returns the maximum number of concurrent calls and
+// executions, or zero if there is no limit.
+func (t *ThreadSafeInterpreter) MaxConcurrency() int {
+	return cap(t.sem)
+}
+
+func (t *ThreadSafeInterpreter) Call(ctx *common.EngineContext, db sql.DB, namespace string, action string, args []any, resultFn func(*common.Row) error) (res *common.CallResult, err error) {
+	span := startCallSpan(ctx, namespace, action)
+	defer func() { endCallSpan(span, res, err) }()
+
+	if t.affinity != nil {
+		if err := t.affinity.check(db, namespace); err != nil {
+			return nil, err
+		}
+	}
+
+	defer t.observeCall(ctx, namespace, action)
+	defer t.recordDuration(ctx, namespace, action, time.Now())
+
+	if ctx.WorkloadClass == common.AdminWorkload {
+		return t.callAdmin(ctx, db, namespace, action, args, resultFn)
+	}
+
+	release, err := t.acquire(ctx.TxContext.Ctx, db)
+	if err != nil {
+		return nil, &engine.QuotaExceededError{Namespace: namespace, Action: action, Caller: ctx.TxContext.Caller, Err: err}
+	}
+	defer release()
+
+	if t.shards != nil {
+		if addr, ok := t.shards.route(db, namespace); ok {
+			return t.shards.forward(ctx, addr, namespace, action, args, resultFn)
+		}
+	}
+
+	if t.scheduler != nil {
+		done, err := t.scheduler.acquire(ctx.TxContext.Ctx, namespace)
+		if err != nil {
+			return nil, err
+		}
+		defer done()
+	}
+
+	if ctx.Simulate {
+		return t.simulate(ctx, db, namespace, action, args, resultFn)
+	}
+
 	unlock, err := t.lock(db)
 	if err != nil {
 		return nil, err
 	}
 	defer unlock()
 
-	return t.i.call(ctx, db, namespace, action, args, resultFn, true)
+	namespace = t.tenantNamespace(ctx, namespace)
+
+	if t.readCache != nil {
+		return t.cachedCall(ctx, db, namespace, action, args, resultFn)
+	}
+
+	return t.i.call(ctx, db, namespace, action, args, resultFn, true, nil)
+}
+
+// simulate makes a simulated call. Since its changes are never kept, it runs
+// on a copy of the interpreter's state with the read lock, rather than taking
+// the write lock like other calls with a read-write database. Its changes to
+// the database are rolled back before the lock is released, so block
+// execution, which takes the write lock, never waits on the rows it locked.
+func (t *ThreadSafeInterpreter) simulate(ctx *common.EngineContext, db sql.DB, namespace string, action string, args []any, resultFn func(*common.Row) error) (*common.CallResult, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	namespace = t.tenantNamespace(ctx, namespace)
+
+	sim := t.i.copy()
+	sim.namespaceRegister = nilNamespaceRegister{}
+	return sim.call(ctx, db, namespace, action, args, resultFn, true, nil)
 }
 
 func (t *ThreadSafeInterpreter) CallWithoutEngineCtx(ctx context.Context, db sql.DB, namespace string, action string, args []any, resultFn func(*common.Row) error) (*common.CallResult, error) {
 	return t.Call(newInvalidEngineCtx(ctx), db, namespace, action, args, resultFn)
 }
 
-func (t *ThreadSafeInterpreter) Execute(ctx *common.EngineContext, db sql.DB, statement string, params map[string]any, fn func(*common.Row) error) error {
+func (t *ThreadSafeInterpreter) Execute(ctx *common.EngineContext, db sql.DB, statement string, params map[string]any, fn func(*common.Row) error) (err error) {
+	release, err := t.acquire(ctx.TxContext.Ctx, db)
+	if err != nil {
+		return &engine.QuotaExceededError{Namespace: engine.DefaultNamespace, Caller: ctx.TxContext.Caller, Err: err}
+	}
+	defer release()
+
 	unlock, err := t.lock(db)
 	if err != nil {
 		return err
 	}
 	defer unlock()
 
+	if t.nsEvents != nil {
+		before := namespaceNames(t.i.namespaces)
+		defer func() {
+			if err == nil {
+				t.publishNamespaceEvents(before)
+			}
+		}()
+	}
+
+	// statements are not captured by CDC, so any of them could have changed
+	// a cached namespace once committed
+	if t.readCache != nil {
+		if am, ok := db.(sql.AccessModer); ok && am.AccessMode() != sql.ReadOnly {
+			t.readCache.markStale()
+		}
+	}
+
 	return t.i.execute(ctx, db, statement, params, fn, true)
 }
 
@@ -85,15 +338,38 @@ type recursiveInterpreter struct {
 	// logs is the slice of logs that the interpreter has written.
 	// It references the slice that will be returned to the caller.
 	logs *[]string
+	// callStack is the call stack of the extension method that calls back
+	// into the interpreter. Calls made by the extension are added to it.
+	callStack []callFrame
 }
 
+// Call calls an action. Its rows are passed to resultFn as they are
+// produced, so they are not held until the call returns. If resultFn returns
+// an error, the call stops and the error is returned. The logs of the call are
+// added to the logs of the caller, even if it fails.
 func (r *recursiveInterpreter) Call(ctx *common.EngineContext, db sql.DB, namespace string, action string, args []any, resultFn func(*common.Row) error) (*common.CallResult, error) {
-	res, err := r.i.call(ctx, db, namespace, action, args, resultFn, false)
+	// the error of resultFn is kept, since the call might report it as the
+	// error of the action
+	var resultErr error
+	fn := resultFn
+	if resultFn != nil {
+		fn = func(row *common.Row) error {
+			resultErr = resultFn(row)
+			return resultErr
+		}
+	}
+
+	res, err := r.i.call(ctx, db, namespace, action, args, fn, false, r.callStack)
+	if res != nil {
+		*r.logs = append(*r.logs, res.Logs...)
+	}
+	if resultErr != nil {
+		return nil, fmt.Errorf(`failed to handle the results of action "%s": %w`, action, resultErr)
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	*r.logs = append(*r.logs, res.Logs...)
 	return res, nil
 }
 
@@ -157,6 +433,17 @@ type namespace struct {
 	// extensionCache is a cache of in-memory state for an extension.
 	// It can be nil if the namespace does not have an extension.
 	extCache precompiles.Cache
+	// errorVerbosity is how much detail the namespace's internal errors give.
+	errorVerbosity ErrorVerbosity
+	// eventSourced is true if the namespace was declared with @event_sourced,
+	// and thus records the changes of its tables in its events table.
+	eventSourced bool
+	// test is true if the namespace was declared with @test_namespace, and
+	// can thus have @test actions.
+	test bool
+	// readOnly is true if only the view actions of the namespace can be
+	// called.
+	readOnly bool
 }
 
 // copy creates a deep copy of the namespace.
@@ -168,6 +455,10 @@ func (n *namespace) copy() *namespace {
 		onUndeploy:         n.onUndeploy,
 		namespaceType:      n.namespaceType,
 		methods:            make(map[string]precompileExecutable), // we need to copy the methods as well, so shallow copy is not enough
+		errorVerbosity:     n.errorVerbosity,
+		eventSourced:       n.eventSourced,
+		test:               n.test,
+		readOnly:           n.readOnly,
 	}
 
 	if n.extCache != nil {
@@ -194,6 +485,10 @@ func (n *namespace) apply(n2 *namespace) {
 	n.onUndeploy = n2.onUndeploy
 	n.namespaceType = n2.namespaceType
 	n.methods = n2.methods
+	n.errorVerbosity = n2.errorVerbosity
+	n.eventSourced = n2.eventSourced
+	n.test = n2.test
+	n.readOnly = n2.readOnly
 
 	if n.extCache != nil {
 		n.extCache.Apply(n2.extCache)
@@ -229,11 +524,17 @@ func (n nilNamespaceRegister) Unlock() {}
 
 // NewInterpreter creates a new interpreter.
 // It reads currently stored namespaces and loads them into memory.
-func NewInterpreter(ctx context.Context, db sql.DB, service *common.Service, accounts common.Accounts, validators common.Validators, nsr engine.NamespaceRegister) (*ThreadSafeInterpreter, error) {
+func NewInterpreter(ctx context.Context, db sql.DB, service *common.Service, accounts common.Accounts, validators common.Validators, nsr engine.NamespaceRegister,
+	opts ...InterpreterOpt) (*ThreadSafeInterpreter, error) {
 	if nsr == nil {
 		nsr = nilNamespaceRegister{}
 	}
 
+	options := &InterpreterOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
 	err := initSQLIfNotInitialized(ctx, db)
 	if err != nil {
 		return nil, err
@@ -252,36 +553,37 @@ func NewInterpreter(ctx context.Context, db sql.DB, service *common.Service, acc
 		return nil, err
 	}
 
-	for _, ns := range namespaces {
-		tables, err := listTablesInNamespace(ctx, db, ns.Name)
-		if err != nil {
-			return nil, err
-		}
+	verbosities, err := listErrorVerbosities(ctx, db)
+	if err != nil {
+		return nil, err
+	}
 
-		tblMap := make(map[string]*engine.Table)
-		for _, tbl := range tables {
-			tblMap[tbl.Name] = tbl
-		}
+	eventSourced, err := listEventSourcedNamespaces(ctx, db)
+	if err != nil {
+		return nil, err
+	}
 
-		actions, err := listActionsInBuiltInNamespace(ctx, db, ns.Name)
+	testNamespaces, err := listTestNamespaces(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	readOnly, err := listReadOnlyNamespaces(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ns := range namespaces {
+		loaded, err := loadNamespace(ctx, db, ns.Name, ns.Type)
 		if err != nil {
 			return nil, err
 		}
 
-		// now, we override the built-in functions with the actions
-		namespaceFunctions := copyBuiltinExecutables()
-		for _, action := range actions {
-			exec := makeActionToExecutable(ns.Name, action)
-			namespaceFunctions[exec.Name] = exec
-		}
-
-		interpreter.namespaces[ns.Name] = &namespace{
-			tables:             tblMap,
-			availableFunctions: namespaceFunctions,
-			namespaceType:      ns.Type,
-			onDeploy:           func(ctx *executionContext) error { return nil },
-			onUndeploy:         func(ctx *executionContext) error { return nil },
-		}
+		loaded.errorVerbosity = verbosities[ns.Name]
+		loaded.eventSourced = eventSourced[ns.Name]
+		loaded.test = testNamespaces[ns.Name]
+		loaded.readOnly = readOnly[ns.Name]
+		interpreter.namespaces[ns.Name] = loaded
 	}
 
 	// we need to add the tables of the info schema manually, since they are not stored in the database
@@ -332,7 +634,67 @@ func NewInterpreter(ctx context.Context, db sql.DB, service *common.Service, acc
 		return nil, err
 	}
 
-	threadSafe := &ThreadSafeInterpreter{i: interpreter}
+	interpreter.breaker = options.CircuitBreaker
+	interpreter.abTests = newABTests()
+	interpreter.indexBuilds = newIndexBuilds(service)
+
+	// builds that did not finish before the node stopped are resumed once
+	// the transaction of db finishes
+	err = interpreter.indexBuilds.resume(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	// the advisor only logs, so there is no point in running it without a logger
+	if !options.DisableQueryAdvisor && service != nil && service.Logger != nil {
+		interpreter.advisor = NewQueryAdvisor(service.Logger)
+	}
+
+	// the read cache is invalidated by the changes captured by CDC, so
+	// changes are captured even without a publisher
+	readCache := newReadCache(options)
+	if options.CDCPublisher != nil || readCache != nil {
+		logger := log.DiscardLogger
+		if service != nil && service.Logger != nil {
+			logger = service.Logger
+		}
+		interpreter.cdc = newCDCCapture(options.CDCPublisher, logger, readCache)
+
+		err = createCDCTriggers(ctx, db, interpreter.namespaces)
+		if err != nil {
+			interpreter.cdc.close()
+			return nil, fmt.Errorf("failed to create CDC triggers: %w", err)
+		}
+	}
+
+	threadSafe := &ThreadSafeInterpreter{
+		i:              interpreter,
+		distributedTxs: make(map[string]*distributedTx),
+		sagas:          make(map[string]*saga),
+	}
+
+	threadSafe.scheduler = options.FairScheduler
+	threadSafe.blocks = options.BlockStore
+	threadSafe.tenants = options.Tenants
+	threadSafe.anomalies = options.AnomalyDetector
+	threadSafe.baselines = newCallBaselines()
+	threadSafe.readCache = readCache
+
+	if options.MaxConcurrentCalls > 0 {
+		threadSafe.sem = make(chan struct{}, options.MaxConcurrentCalls)
+	}
+
+	if options.GossipEnabled {
+		threadSafe.nsEvents = make(chan NamespaceEvent, namespaceEventsBuffer)
+	}
+
+	if service != nil && service.LocalConfig != nil && service.LocalConfig.Sharding.ShardCount > 0 {
+		threadSafe.shards = newShardRouter(service.LocalConfig.Sharding)
+	}
+
+	if service != nil && service.LocalConfig != nil {
+		threadSafe.affinity = newNamespaceAffinity(service.LocalConfig)
+	}
 
 	app := &common.App{
 		Service:    service,
@@ -404,6 +766,10 @@ func (u *userDefinedErr) Error() string {
 	return u.err.Error()
 }
 
+func (u *userDefinedErr) Unwrap() error {
+	return u.err
+}
+
 // unwrapExecutionErr unwraps an error that was returned from user-defined code using the ERROR function, or an error
 // that is the result of user logic / data (e.g. a Postgres primary key violation).
 // The error can either come from an action call to ERROR() or from Kwil's custom ERROR() postgres function.
@@ -424,6 +790,13 @@ func unwrapExecutionErr(e error) (err error, isUserLogicErr bool) {
 		return e, true
 	}
 
+	// an internal error hidden from the caller is classified by the error
+	// it hides
+	internal := new(internalError)
+	if errors.As(e, &internal) && allowedSQLSTATEErrRegex.MatchString(internal.err.Error()) {
+		return e, true
+	}
+
 	return e, false
 }
 
@@ -454,7 +827,7 @@ func funcDefToExecutable(funcName string, funcDef *engine.ScalarFunctionDefiniti
 			}
 
 			// get the expected return type
-			retTyp, err := funcDef.ValidateArgsFunc(argTypes)
+			retTyp, err := funcDef.ValidateArgs(argTypes)
 			if err != nil {
 				return err
 			}
@@ -478,10 +851,81 @@ func funcDefToExecutable(funcName string, funcDef *engine.ScalarFunctionDefiniti
 				return newUserDefinedErr(errors.New(msg))
 			}
 
+			if funcName == "config_get" {
+				res, err := e.configGet(args[0])
+				if err != nil {
+					return err
+				}
+				return fn(&row{
+					columns: []string{funcName},
+					Values:  []value{res},
+				})
+			}
+
+			if funcName == "config_set" {
+				return e.configSet(args[0], args[1])
+			}
+
+			if funcName == "raise_error" {
+				return e.raiseError(args[0], args[1])
+			}
+
+			if funcName == "now" {
+				res, err := e.blockTime()
+				if err != nil {
+					return err
+				}
+				return fn(&row{
+					columns: []string{funcName},
+					Values:  []value{res},
+				})
+			}
+
+			if funcName == "uuid_generate" {
+				res, err := e.uuidGenerate()
+				if err != nil {
+					return err
+				}
+				return fn(&row{
+					columns: []string{funcName},
+					Values:  []value{res},
+				})
+			}
+
+			if funcName == "action_elapsed_ms" {
+				res, err := e.actionElapsedMs(retTyp)
+				if err != nil {
+					return err
+				}
+				return fn(&row{
+					columns: []string{funcName},
+					Values:  []value{res},
+				})
+			}
+
+			if funcName == "seeded_random" {
+				res, err := e.seededRandom(args[0], args[1])
+				if err != nil {
+					return err
+				}
+				return fn(&row{
+					columns: []string{funcName},
+					Values:  []value{res},
+				})
+			}
+
 			if e.queryActive {
 				return fmt.Errorf(`%w: cannot execute function "%s" while a query is active`, engine.ErrQueryActive, funcName)
 			}
 
+			if funcName == "soft_delete_row" || funcName == "restore_row" {
+				return e.setSoftDeleted(args[0], args[1], funcName == "soft_delete_row")
+			}
+
+			if funcName == "get_row_history" {
+				return e.getRowHistory(args[0], args[1], args[2], fn)
+			}
+
 			zeroVal, err := newZeroValue(retTyp)
 			if err != nil {
 				return err
@@ -498,12 +942,13 @@ func funcDefToExecutable(funcName string, funcDef *engine.ScalarFunctionDefiniti
 			// Since for now we are more concerned about expanding functionality than scalability,
 			// we will use the roundtrip.
 			iters := 0
-			err = query(e.engineCtx.TxContext.Ctx, e.db, "SELECT "+pgFormat+";", []any{zeroVal}, func() error {
+			stmt := "SELECT " + pgFormat + ";"
+			err = query(e.engineCtx.TxContext.Ctx, e.db, stmt, []any{zeroVal}, func() error {
 				iters++
 				return nil
 			}, args)
 			if err != nil {
-				return err
+				return e.reportInternalError(stmt, err)
 			}
 			if iters != 1 {
 				return fmt.Errorf("expected 1 row, got %d", iters)
@@ -518,6 +963,40 @@ func funcDefToExecutable(funcName string, funcDef *engine.ScalarFunctionDefiniti
 	}
 }
 
+// loadNamespace reads the tables and actions of a namespace from the database.
+// The settings of the namespace, such as its error verbosity, are not set.
+func loadNamespace(ctx context.Context, db sql.DB, name string, typ namespaceType) (*namespace, error) {
+	tables, err := listTablesInNamespace(ctx, db, name)
+	if err != nil {
+		return nil, err
+	}
+
+	tblMap := make(map[string]*engine.Table)
+	for _, tbl := range tables {
+		tblMap[tbl.Name] = tbl
+	}
+
+	actions, err := listActionsInBuiltInNamespace(ctx, db, name)
+	if err != nil {
+		return nil, err
+	}
+
+	// now, we override the built-in functions with the actions
+	namespaceFunctions := copyBuiltinExecutables()
+	for _, action := range actions {
+		exec := makeActionToExecutable(name, action)
+		namespaceFunctions[exec.Name] = exec
+	}
+
+	return &namespace{
+		tables:             tblMap,
+		availableFunctions: namespaceFunctions,
+		namespaceType:      typ,
+		onDeploy:           func(ctx *executionContext) error { return nil },
+		onUndeploy:         func(ctx *executionContext) error { return nil },
+	}, nil
+}
+
 // baseInterpreter interprets Kwil SQL statements.
 type baseInterpreter struct {
 	namespaces map[string]*namespace
@@ -531,6 +1010,23 @@ type baseInterpreter struct {
 	accounts common.Accounts
 	// namespaceRegister is used to register and unregister namespaces
 	namespaceRegister engine.NamespaceRegister
+	// cdc publishes the row changes made by actions, if enabled
+	cdc *cdcCapture
+	// breaker stops calling extensions whose calls keep failing, if enabled
+	breaker *CircuitBreaker
+	// plans collects the query plans of the queries executed, while an
+	// action's plans are being captured
+	plans *planCapture
+	// analysis collects the execution statistics of the queries executed,
+	// while an action is being analyzed
+	analysis *AnalyzeResult
+	// advisor suggests improvements to the queries of calls, if enabled
+	advisor *QueryAdvisor
+	// abTests are the actions whose calls are split between two variants
+	abTests *abTests
+	// indexBuilds are the indexes being created with CREATE INDEX
+	// CONCURRENTLY
+	indexBuilds *indexBuilds
 }
 
 // copy deep copies the state of the interpreter.
@@ -546,9 +1042,14 @@ func (i *baseInterpreter) copy() *baseInterpreter {
 		namespaces:       namespaces,
 		accessController: i.accessController.copy(),
 		// service, validators, and accounts should have no need to be copied
-		service:    i.service,
-		validators: i.validators,
-		accounts:   i.accounts,
+		service:     i.service,
+		validators:  i.validators,
+		accounts:    i.accounts,
+		cdc:         i.cdc,
+		breaker:     i.breaker,
+		advisor:     i.advisor,
+		abTests:     i.abTests,
+		indexBuilds: i.indexBuilds,
 	}
 }
 
@@ -574,6 +1075,11 @@ func (i *baseInterpreter) apply(copied *baseInterpreter) {
 	i.service = copied.service
 	i.validators = copied.validators
 	i.accounts = copied.accounts
+	i.cdc = copied.cdc
+	i.breaker = copied.breaker
+	i.advisor = copied.advisor
+	i.abTests = copied.abTests
+	i.indexBuilds = copied.indexBuilds
 }
 
 // adhocParseCache is an lru cache for statements that are parsed ad-hoc.
@@ -600,6 +1106,12 @@ func parseAdhoc(statement string) ([]parse.TopLevelStatement, error) {
 
 // Execute executes a statement against the database.
 func (i *baseInterpreter) execute(ctx *common.EngineContext, db sql.DB, statement string, params map[string]any, fn func(*common.Row) error, toplevel bool) (err error) {
+	return i.executeIn(ctx, db, engine.DefaultNamespace, statement, params, fn, toplevel)
+}
+
+// executeIn executes a statement whose objects are in the given namespace
+// unless it names another one.
+func (i *baseInterpreter) executeIn(ctx *common.EngineContext, db sql.DB, namespace, statement string, params map[string]any, fn func(*common.Row) error, toplevel bool) (err error) {
 	copied := i.copy()
 	defer func() {
 		noErrOrPanic := true
@@ -639,26 +1151,18 @@ func (i *baseInterpreter) execute(ctx *common.EngineContext, db sql.DB, statemen
 		return fmt.Errorf("no valid statements provided: %s", statement)
 	}
 
-	execCtx, err := i.newExecCtx(ctx, db, engine.DefaultNamespace, toplevel)
+	execCtx, err := i.newExecCtx(ctx, db, namespace, toplevel)
 	if err != nil {
 		return err
 	}
 
-	for _, param := range order.OrderMap(params) {
-		val, err := newValue(param.Value)
-		if err != nil {
-			return err
-		}
-
-		name := strings.ToLower(param.Key)
-		if !strings.HasPrefix(name, "$") {
-			name = "$" + name
-		}
-		if err := isValidVarName(name); err != nil {
-			return err
-		}
+	vars, err := paramVariables(params)
+	if err != nil {
+		return &engine.ValidationError{Namespace: execCtx.scope.namespace, Err: err}
+	}
 
-		err = execCtx.setVariable(name, val)
+	for _, v := range order.OrderMap(vars) {
+		err = execCtx.setVariable(v.Key, v.Value)
 		if err != nil {
 			return err
 		}
@@ -667,11 +1171,15 @@ func (i *baseInterpreter) execute(ctx *common.EngineContext, db sql.DB, statemen
 	interpPlanner := interpreterPlanner{}
 
 	for _, stmt := range ast {
+		if err = execCtx.checkCanceled(); err != nil {
+			return err
+		}
+
 		err = stmt.Accept(&interpPlanner).(stmtFunc)(execCtx, func(row *row) error {
 			return fn(rowToCommonRow(row))
 		})
 		if err != nil {
-			return err
+			return timeoutErr(execCtx.scope.namespace, "", err)
 		}
 	}
 
@@ -680,6 +1188,40 @@ func (i *baseInterpreter) execute(ctx *common.EngineContext, db sql.DB, statemen
 
 var identRegexp = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
 
+// paramVariables converts the parameters of an ad-hoc statement to the
+// variables they set, keyed by variable name. Parameters can be named with a
+// $ or @ prefix, or without one: $amount, @amount and amount all set $amount.
+// It is an error for two parameters to set the same variable.
+func paramVariables(params map[string]any) (map[string]value, error) {
+	vars := make(map[string]value, len(params))
+	keys := make(map[string]string, len(params))
+	for _, param := range order.OrderMap(params) {
+		name := strings.ToLower(param.Key)
+		switch {
+		case strings.HasPrefix(name, "@"):
+			name = "$" + name[1:]
+		case !strings.HasPrefix(name, "$"):
+			name = "$" + name
+		}
+		if err := isValidVarName(name); err != nil {
+			return nil, err
+		}
+
+		if key, ok := keys[name]; ok {
+			return nil, fmt.Errorf("parameters %s and %s both set variable %s", key, param.Key, name)
+		}
+		keys[name] = param.Key
+
+		val, err := newValue(param.Value)
+		if err != nil {
+			return nil, err
+		}
+		vars[name] = val
+	}
+
+	return vars, nil
+}
+
 // isValidVarName checks if a string is a valid variable name.
 func isValidVarName(s string) error {
 	if !strings.HasPrefix(s, "$") {
@@ -700,8 +1242,12 @@ func isValidVarName(s string) error {
 
 // Call executes an action against the database.
 // The resultFn is called with the result of the action, if any.
-func (i *baseInterpreter) call(ctx *common.EngineContext, db sql.DB, namespace, action string, args []any, resultFn func(*common.Row) error, toplevel bool) (callRes *common.CallResult, err error) {
+// The callStack is the call stack of the caller, if the call is nested.
+func (i *baseInterpreter) call(ctx *common.EngineContext, db sql.DB, namespace, action string, args []any, resultFn func(*common.Row) error, toplevel bool, callStack []callFrame) (callRes *common.CallResult, err error) {
 	copied := i.copy()
+	var refund *callRefund
+	var execCtx *executionContext
+	var simTx sql.Tx
 	defer func() {
 		noErrOrPanic := true
 		if err != nil {
@@ -716,6 +1262,32 @@ func (i *baseInterpreter) call(ctx *common.EngineContext, db sql.DB, namespace,
 			noErrOrPanic = false
 		}
 
+		if refund != nil {
+			if refundErr := refund.finish(!noErrOrPanic); refundErr != nil {
+				err = errors.Join(err, refundErr)
+				noErrOrPanic = false
+			}
+		}
+
+		// a simulation never keeps its changes, and reports its failure
+		// with the logs and gas of the call up to the failure
+		if toplevel && ctx.Simulate {
+			noErrOrPanic = false
+			if simTx != nil {
+				if rbErr := simTx.Rollback(ctx.TxContext.Ctx); rbErr != nil {
+					err = errors.Join(err, rbErr)
+				}
+			}
+			if err != nil {
+				callRes = &common.CallResult{Error: err}
+				if execCtx != nil {
+					callRes.Logs = *execCtx.logs
+					callRes.GasUsed = *execCtx.gasUsed
+				}
+				err = nil
+			}
+		}
+
 		if noErrOrPanic {
 			i.syncNamespaceManager()
 		} else {
@@ -738,21 +1310,44 @@ func (i *baseInterpreter) call(ctx *common.EngineContext, db sql.DB, namespace,
 	namespace = strings.ToLower(namespace)
 	action = strings.ToLower(action)
 
-	execCtx, err := i.newExecCtx(ctx, db, namespace, toplevel)
+	execCtx, err = i.newExecCtx(ctx, db, namespace, toplevel)
 	if err != nil {
 		return nil, err
 	}
+	execCtx.callStack = callStack
+
+	// a simulated call runs in a nested transaction that is rolled back
+	// when it returns
+	if toplevel && ctx.Simulate && execCtx.canMutateState {
+		simTx, err = db.BeginTx(ctx.TxContext.Ctx)
+		if err != nil {
+			return nil, err
+		}
+		db = simTx
+		execCtx.db = simTx
+	}
+
+	// a call that refunds its caller if it fails runs in a nested
+	// transaction, so that its changes are rolled back without the refund
+	if toplevel && ctx.RefundOnFailure && !ctx.Simulate && execCtx.canMutateState {
+		refund, err = i.startRefund(ctx, db, execCtx.gasUsed)
+		if err != nil {
+			return nil, err
+		}
+		db = refund.tx
+		execCtx.db = refund.tx
+	}
 
 	ns, ok := i.namespaces[namespace]
 	if !ok {
-		return nil, fmt.Errorf(`namespace "%s" does not exist`, namespace)
+		return nil, &engine.NamespaceNotFoundError{Namespace: namespace}
 	}
 
 	// now we can call the executable. The executable checks that the caller is allowed to call the action
 	// (e.g. in case of a private action or owner action)
 	exec, ok := ns.availableFunctions[action]
 	if !ok {
-		return nil, fmt.Errorf(`%w: action "%s" does not exist in namespace "%s"`, engine.ErrUnknownAction, action, namespace)
+		return nil, &engine.ActionNotFoundError{Namespace: namespace, Action: action}
 	}
 
 	switch exec.Type {
@@ -764,21 +1359,50 @@ func (i *baseInterpreter) call(ctx *common.EngineContext, db sql.DB, namespace,
 		return nil, fmt.Errorf(`node bug: unknown executable type "%s"`, exec.Type)
 	}
 
+	// test actions are only run by transactions, so that they are never
+	// called through the read-only call RPC
+	if exec.Test && !execCtx.canMutateState {
+		return nil, fmt.Errorf(`action "%s" is a test action and can only be executed in a transaction`, action)
+	}
+
+	// A/B tested actions call the variant that the caller is assigned
+	var variant *abVariant
+	if toplevel && i.abTests != nil {
+		variant, err = i.abTests.route(execCtx, namespace, action)
+		if err != nil {
+			return nil, err
+		}
+		if variant != nil {
+			exec = variant.exec
+		}
+	}
+
+	invalid := func(err error) error {
+		return &engine.ValidationError{Namespace: namespace, Action: action, Err: err}
+	}
+
 	argVals := make([]value, len(args))
 
 	if exec.ExpectedArgs != nil {
 		expect := *exec.ExpectedArgs
 		if len(expect) != len(args) {
-			return nil, fmt.Errorf(`%w: action "%s" expected %d arguments, but got %d`, engine.ErrActionInvocation, action, len(expect), len(args))
+			return nil, invalid(fmt.Errorf(`%w: action "%s" expected %d arguments, but got %d`, engine.ErrActionInvocation, action, len(expect), len(args)))
 		}
 
 		for i, arg := range args {
+			// Go values that the parameter can hold exactly, e.g. an int32
+			// for an INT8, are converted to its type
+			arg, err := coerceArg(arg, expect[i])
+			if err != nil {
+				return nil, invalid(err)
+			}
+
 			val, ok, err := newValueWithSoftCast(arg, expect[i])
 			if err != nil {
-				return nil, err
+				return nil, invalid(err)
 			}
 			if !ok {
-				return nil, fmt.Errorf(`%w: action "%s" expected argument %d to be of type %s, but got %s`, engine.ErrType, action, i, expect[i], val.Type())
+				return nil, invalid(fmt.Errorf(`%w: action "%s" expected argument %d to be of type %s, but got %s`, engine.ErrType, action, i, expect[i], val.Type()))
 			}
 
 			argVals[i] = val
@@ -787,16 +1411,76 @@ func (i *baseInterpreter) call(ctx *common.EngineContext, db sql.DB, namespace,
 		for i, arg := range args {
 			val, err := newValue(arg)
 			if err != nil {
-				return nil, err
+				return nil, invalid(err)
 			}
 
 			argVals[i] = val
 		}
 	}
 
+	// a call to an @idempotent action that was already made within the
+	// idempotency window returns the stored result without executing it
+	var idempotentKey []byte
+	var idempotentRes *idempotentResult
+	if toplevel && exec.Idempotent && execCtx.canMutateState && !ctx.InvalidTxCtx && ctx.TxContext.BlockContext != nil {
+		idempotentKey, err = idempotencyKey(ctx.TxContext.Caller, namespace, action, argVals)
+		if err != nil {
+			return nil, err
+		}
+
+		stored, err := execCtx.storedIdempotentResult(idempotentKey)
+		if err != nil {
+			return nil, err
+		}
+		if stored != nil {
+			if err = stored.replay(resultFn); err != nil {
+				return nil, err
+			}
+			return &common.CallResult{
+				Logs: stored.Logs,
+			}, nil
+		}
+
+		idempotentRes = &idempotentResult{}
+		resultFn = idempotentRes.recorder(resultFn)
+	}
+
+	// only the top level action captures changes, so that changes made by
+	// nested calls are published with the rest of the action's changes
+	captureChanges := i.cdc != nil && toplevel && execCtx.canMutateState && !ctx.Simulate
+	var cdcMarker int64
+	if captureChanges {
+		if cdcMarker, err = i.cdc.start(ctx, db); err != nil {
+			return nil, err
+		}
+	}
+
+	if toplevel && i.advisor != nil {
+		execCtx.advised = &advisedCall{advisor: i.advisor}
+	}
+
+	start := time.Now()
 	err = exec.Func(execCtx, argVals, func(row *row) error {
 		return resultFn(rowToCommonRow(row))
 	})
+	err = timeoutErr(namespace, action, err)
+
+	if variant != nil && !ctx.Simulate {
+		i.abTests.record(variant, time.Since(start), err != nil)
+	}
+
+	if execCtx.advised != nil && err == nil {
+		i.advisor.advise(execCtx.advised.plans, execCtx.getTable)
+	}
+
+	if captureChanges {
+		// the changes staged by a failed action are discarded, since the
+		// caller may keep the rest of its transaction
+		cdcErr := i.cdc.finish(ctx.TxContext.Ctx, db, cdcMarker, err != nil)
+		if err == nil {
+			err = cdcErr
+		}
+	}
 
 	// if the error is an execution error,
 	// then it should be part of the CallResult,
@@ -804,16 +1488,45 @@ func (i *baseInterpreter) call(ctx *common.EngineContext, db sql.DB, namespace,
 	err, ok = unwrapExecutionErr(err)
 	if ok {
 		return &common.CallResult{
-			Logs:  *execCtx.logs,
-			Error: err,
+			Logs:    *execCtx.logs,
+			Error:   err,
+			GasUsed: *execCtx.gasUsed,
 		}, nil
 	}
 
+	// only successful calls are stored, so that failed calls can be retried
+	if err == nil && idempotentKey != nil {
+		idempotentRes.Logs = *execCtx.logs
+		err = execCtx.storeIdempotentResult(idempotentKey, namespace, idempotentRes)
+	}
+
 	return &common.CallResult{
-		Logs: *execCtx.logs,
+		Logs:    *execCtx.logs,
+		GasUsed: *execCtx.gasUsed,
 	}, err
 }
 
+// timeoutErr returns a *engine.StatementTimeoutError if err was returned
+// because a call or statement ran past the deadline of its context, or past
+// Postgres's statement_timeout. Otherwise, it returns err as it is. action is
+// empty for statements.
+func timeoutErr(namespace, action string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var timeout *engine.StatementTimeoutError
+	if errors.As(err, &timeout) {
+		return err
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) || strings.Contains(err.Error(), "canceling statement due to statement timeout") {
+		return &engine.StatementTimeoutError{Namespace: namespace, Action: action, Err: err}
+	}
+
+	return err
+}
+
 // rowToCommonRow converts a row to a common.Row.
 func rowToCommonRow(row *row) *common.Row {
 	// convert the results to any
@@ -847,9 +1560,33 @@ func (i *baseInterpreter) newExecCtx(txCtx *common.EngineContext, db sql.DB, nam
 		db:             db,
 		interpreter:    i,
 		logs:           &logs,
+		plans:          i.plans,
+		analysis:       i.analysis,
+		createdAt:      time.Now(),
+		gasUsed:        new(uint64),
 	}
 	e.scope.isTopLevel = toplevel
 
+	// the history trigger records changes at the block timestamp
+	if e.canMutateState && i.hasHistoryTables() {
+		if err := e.setHistoryTimestamp(); err != nil {
+			return nil, err
+		}
+	}
+
+	// the event trigger records changes at the block height
+	if e.canMutateState && i.hasEventSourcedNamespaces() {
+		if err := e.setEventBlockHeight(); err != nil {
+			return nil, err
+		}
+	}
+
+	if toplevel {
+		if err := e.applyQueryClass(); err != nil {
+			return nil, err
+		}
+	}
+
 	return e, nil
 }
 
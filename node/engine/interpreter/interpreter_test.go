@@ -5,20 +5,34 @@ package interpreter_test
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"math"
+	"math/big"
+	"net/netip"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/kwilteam/kwil-db/common"
+	"github.com/kwilteam/kwil-db/config"
+	"github.com/kwilteam/kwil-db/core/crypto"
+	"github.com/kwilteam/kwil-db/core/crypto/auth"
+	"github.com/kwilteam/kwil-db/core/log"
 	"github.com/kwilteam/kwil-db/core/types"
 	"github.com/kwilteam/kwil-db/extensions/precompiles"
+	"github.com/kwilteam/kwil-db/node/accounts"
 	"github.com/kwilteam/kwil-db/node/engine"
 	"github.com/kwilteam/kwil-db/node/engine/interpreter"
+	"github.com/kwilteam/kwil-db/node/engine/planner/logical"
 	"github.com/kwilteam/kwil-db/node/pg"
 	pgtest "github.com/kwilteam/kwil-db/node/pg/test"
+	"github.com/kwilteam/kwil-db/node/store/memstore"
 	"github.com/kwilteam/kwil-db/node/types/sql"
+	"github.com/kwilteam/kwil-db/node/wal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -241,6 +255,28 @@ func Test_SQL(t *testing.T) {
 				{"Alice", int64(30)},
 			},
 		},
+		{
+			// the action is not changed, and fails once it is called
+			name: "alter table rename column used by an action",
+			sql: []string{
+				"CREATE ACTION get_names() public view { SELECT name FROM users; };",
+				"ALTER TABLE users RENAME COLUMN name TO full_name;",
+			},
+			execSQL: "SELECT name FROM info.columns WHERE namespace = 'main' AND table_name = 'users' ORDER BY name;",
+			results: [][]any{
+				{"age"}, {"full_name"}, {"id"},
+			},
+		},
+		{
+			name:        "alter table rename column to existing column",
+			execSQL:     "ALTER TABLE users RENAME COLUMN name TO age;",
+			errContains: `column "age" already exists`,
+		},
+		{
+			name:        "alter table rename unknown column",
+			execSQL:     "ALTER TABLE users RENAME COLUMN nickname TO alias;",
+			errContains: `column "nickname" does not exist`,
+		},
 
 		// Renaming a table
 		{
@@ -693,6 +729,22 @@ func Test_SQL(t *testing.T) {
 			},
 			execSQL: `SELECT lower(address) FROM wallets GROUP BY lower(address);`,
 		},
+		{
+			name: "window over a grouping term",
+			sql: []string{
+				"INSERT INTO users (id, name, age) VALUES (1, 'Alice', 30), (2, 'Alice', 35), (3, 'Bob', 40);",
+			},
+			execSQL: `SELECT name, max(max(age)) OVER (PARTITION BY name) FROM users GROUP BY name;`,
+			results: [][]any{
+				{"Alice", int64(35)},
+				{"Bob", int64(40)},
+			},
+		},
+		{
+			name:    "window over a column that is not grouped",
+			execSQL: `SELECT name, max(max(age)) OVER (PARTITION BY age) FROM users GROUP BY name;`,
+			err:     engine.ErrWindowGroupByConflict,
+		},
 		{
 			name:    "namespace with hyphen",
 			execSQL: `CREATE NAMESPACE "test-hyphen";`,
@@ -723,6 +775,18 @@ func Test_SQL(t *testing.T) {
 			results:        [][]any{{[]*string{}}},
 			skipInitTables: true,
 		},
+		{
+			name:           "table function",
+			execSQL:        `SELECT s.generate_series FROM generate_series(1, 5, 1) AS s;`,
+			results:        [][]any{{int64(1)}, {int64(2)}, {int64(3)}, {int64(4)}, {int64(5)}},
+			skipInitTables: true,
+		},
+		{
+			name:           "unknown table function",
+			execSQL:        `SELECT * FROM unknown_series(1, 5, 1);`,
+			err:            engine.ErrQueryPlanner,
+			skipInitTables: true,
+		},
 		{
 			// a regression test that tests for a few cases.
 			// 1. it tests for ERROR working properly within queries.
@@ -834,6 +898,11 @@ func Test_Roundtrip(t *testing.T) {
 			datatype: "BYTEA",
 			value:    []byte("hello"),
 		},
+		{
+			name:     "interval",
+			datatype: "INTERVAL",
+			value:    -22*time.Hour - 30*time.Minute,
+		},
 		{
 			name:     "int_array",
 			datatype: "INT[]",
@@ -864,6 +933,11 @@ func Test_Roundtrip(t *testing.T) {
 			datatype: "BYTEA[]",
 			value:    [][]byte{[]byte("hello"), {}, []byte("world"), nil}, //  append(ptrArr([]byte("hello"), []byte{}, []byte("world")), nil),
 		},
+		{
+			name:     "interval_array",
+			datatype: "INTERVAL[]",
+			value:    append(ptrArr(24*time.Hour, -time.Second), nil),
+		},
 	}
 
 	db := newTestDB(t, func(db *pg.DB) {
@@ -1829,6 +1903,90 @@ func Test_Actions(t *testing.T) {
 			error($id::TEXT);
 		}
 		`),
+		rawTest(`decimal functions`, `
+		if decimal_add(0.1, 0.2) != 0.3 {
+			error('0.1 + 0.2 is not 0.3');
+		}
+		if decimal_mul(1.10, 3.0) != 3.3 {
+			error('1.10 * 3.0 is not 3.3');
+		}
+		if decimal_div(1.00, 3.00) != 0.33 {
+			error('1.00 / 3.00 is not 0.33');
+		}
+		if decimal_round(2.345, 2) != 2.35 {
+			error('2.345 is not rounded to 2.35');
+		}
+		if decimal_round(99.9, 0) != 100.0 {
+			error('99.9 is not rounded to 100');
+		}
+		`),
+		rawTest(`interval functions`, `
+		if interval_add(make_interval(1, 0, 0, 0), make_interval(0, 2, 30, 15))::text != '1 day 02:30:15' {
+			error('1 day + 2:30:15 is not 1 day 02:30:15');
+		}
+		$negative := interval_add(make_interval(1, 0, 0, 0), make_interval(-2, 0, 0, 0));
+		if $negative != make_interval(-1, 0, 0, 0) {
+			error('1 day - 2 days is not -1 day');
+		}
+		if $negative::text != '-1 days' {
+			error('-1 day is not formatted as -1 days');
+		}
+		if $negative + '12:00:00'::interval >= '00:00:00'::interval {
+			error('-1 day + 12 hours is not negative');
+		}
+		`),
+		rawTest(`network functions`, `
+		if !inet_contains('192.168.0.0/16', '192.168.1.1') {
+			error('192.168.0.0/16 does not contain 192.168.1.1');
+		}
+		if inet_contains('10.0.0.0/8', '192.168.1.1') {
+			error('10.0.0.0/8 contains 192.168.1.1');
+		}
+		if !inet_contains('2001:db8::/32'::cidr, '2001:db8::1'::inet) {
+			error('2001:db8::/32 does not contain 2001:db8::1');
+		}
+		if host('192.168.1.1'::inet) != '192.168.1.1' {
+			error('the host of 192.168.1.1 is not 192.168.1.1');
+		}
+		`),
+		rawTest(`variadic functions`, `
+		if concat('a', 'b', 'c') != 'abc' {
+			error('concat of a, b and c is not abc');
+		}
+		if coalesce(null, null, 'x') != 'x' {
+			error('coalesce of null, null and x is not x');
+		}
+		if greatest(1, 3, 2) != 3 {
+			error('the greatest of 1, 3 and 2 is not 3');
+		}
+		if least(1, 3, 2) != 1 {
+			error('the least of 1, 3 and 2 is not 1');
+		}
+		$rows := 0;
+		for $row in SELECT concat('a', 'b', 'c') AS c, coalesce(null, null, 'x') AS x {
+			if $row.c != 'abc' {
+				error('concat returned the wrong value in SQL');
+			}
+			if $row.x != 'x' {
+				error('coalesce returned the wrong value in SQL');
+			}
+			$rows := $rows + 1;
+		}
+		if $rows != 1 {
+			error('the query did not return a row');
+		}
+		`),
+		rawTest(`uuid functions`, `
+		if uuid_nil() != '00000000-0000-0000-0000-000000000000'::uuid {
+			error('uuid_nil is not the nil uuid');
+		}
+		if uuid_parse('819AB751-E64C-5259-BBAE-4D36F25BDD84') != uuid_generate_kwil('a') {
+			error('uuid_parse did not parse the uuid');
+		}
+		`),
+		rawTest(`uuid_generate in a call that can change state`, `
+		$id := uuid_generate();
+		`, engine.ErrIllegalFunctionUsage),
 		{
 			name: "action param changes do not reflect in the caller",
 			stmt: []string{
@@ -3123,11 +3281,33 @@ func newTestDB(t *testing.T, cleanUp func(d *pg.DB), schemaFilter func(string) b
 	return pgtest.NewTestDBWithCfg(t, cfg, cleanUp)
 }
 
+// newTestPool creates a connection pool for tests whose setup must be committed,
+// e.g. to be visible to other transactions. The test schemas are dropped on
+// cleanup.
+func newTestPool(t *testing.T) *pg.Pool {
+	newTestDB(t, nil, nil)
+
+	pool, err := pg.NewPool(context.Background(), &pg.PoolConfig{
+		ConnConfig: pg.ConnConfig{
+			Host:   "127.0.0.1",
+			Port:   "5432",
+			User:   "kwild",
+			Pass:   "kwild", // would be ignored if pg_hba.conf set with trust
+			DBName: "kwil_test_db",
+		},
+		MaxConns: 4,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { pool.Close() })
+
+	return pool
+}
+
 // newTestInterp creates a new interpreter for testing purposes.
 // It is seeded with the default tables.
 
-func newTestInterp(t *testing.T, tx sql.DB, seeds []string, includeTestTables bool) *interpreter.ThreadSafeInterpreter {
-	interp, err := interpreter.NewInterpreter(context.Background(), tx, &common.Service{}, nil, nil, nil)
+func newTestInterp(t *testing.T, tx sql.DB, seeds []string, includeTestTables bool, opts ...interpreter.InterpreterOpt) *interpreter.ThreadSafeInterpreter {
+	interp, err := interpreter.NewInterpreter(context.Background(), tx, &common.Service{}, nil, nil, nil, opts...)
 	require.NoError(t, err)
 
 	engCtx := newEngineCtx(defaultCaller)
@@ -3195,3 +3375,2762 @@ func Test_NamespaceDropsCache(t *testing.T) {
 	_, err = interp.CallWithoutEngineCtx(ctx, tx, "test_ns", "smthn", []any{"hello"}, nil)
 	require.NoError(t, err)
 }
+
+func Test_SensitiveColumns(t *testing.T) {
+	db := newTestDB(t, nil, nil)
+
+	ctx := context.Background()
+	tx, err := db.BeginTx(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback(ctx) // always rollback
+
+	interp := newTestInterp(t, tx, []string{`CREATE TABLE accounts (
+		id INT PRIMARY KEY,
+		-- @sensitive(hash)
+		email TEXT,
+		-- @sensitive(partial(3))
+		name TEXT,
+		-- @sensitive(redact)
+		age INT
+	);`, `INSERT INTO accounts (id, email, name, age) VALUES (1, 'alice@example.com', 'alice', 30);`}, false)
+
+	emailHash := sha256.Sum256([]byte("alice@example.com"))
+
+	type testcase struct {
+		name   string
+		caller string
+		query  string
+		want   any
+	}
+
+	tests := []testcase{
+		{name: "hash", caller: "other", query: `SELECT email FROM accounts`, want: hex.EncodeToString(emailHash[:])},
+		{name: "partial", caller: "other", query: `SELECT name FROM accounts`, want: "ali"},
+		{name: "redact", caller: "other", query: `SELECT age FROM accounts`, want: nil},
+		{name: "masked in expressions", caller: "other", query: `SELECT upper(a.name) FROM accounts a WHERE a.id = 1`, want: "ALI"},
+		{name: "owner sees unmasked", caller: defaultCaller, query: `SELECT email FROM accounts`, want: "alice@example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := interp.Execute(newEngineCtx(tt.caller), tx, tt.query, nil, exact(tt.want))
+			require.NoError(t, err)
+		})
+	}
+
+	// the table being updated or deleted from is not masked, so callers
+	// without the SENSITIVE privilege cannot read its sensitive columns
+	err = interp.Execute(newEngineCtx(defaultCaller), tx, `CREATE ROLE writer;
+	GRANT update, delete TO writer;
+	GRANT writer TO 'other';`, nil, nil)
+	require.NoError(t, err)
+
+	updateByEmail := `UPDATE accounts SET age = 31 WHERE email = 'alice@example.com'`
+	err = interp.Execute(newEngineCtx(defaultCaller), tx, updateByEmail, nil, nil)
+	require.NoError(t, err)
+	// the check also applies to the cached statement
+	err = interp.Execute(newEngineCtx("other"), tx, updateByEmail, nil, nil)
+	require.ErrorIs(t, err, engine.ErrDoesNotHavePrivilege)
+
+	err = interp.Execute(newEngineCtx("other"), tx, `UPDATE accounts SET age = age + 1 WHERE id = 1`, nil, nil)
+	require.ErrorIs(t, err, engine.ErrDoesNotHavePrivilege)
+	err = interp.Execute(newEngineCtx("other"), tx, `DELETE FROM accounts WHERE name = 'alice'`, nil, nil)
+	require.ErrorIs(t, err, engine.ErrDoesNotHavePrivilege)
+
+	// sensitive columns can still be set without being read
+	err = interp.Execute(newEngineCtx("other"), tx, `UPDATE accounts SET age = 32 WHERE id = 1`, nil, nil)
+	require.NoError(t, err)
+	err = interp.Execute(newEngineCtx(defaultCaller), tx, `SELECT age FROM accounts`, nil, exact(int64(32)))
+	require.NoError(t, err)
+
+	// dropping the table drops its policies
+	err = interp.Execute(newEngineCtx(defaultCaller), tx, `DROP TABLE accounts;
+	CREATE TABLE accounts (id INT PRIMARY KEY, email TEXT);
+	INSERT INTO accounts (id, email) VALUES (1, 'bob@example.com');`, nil, nil)
+	require.NoError(t, err)
+
+	err = interp.Execute(newEngineCtx("other"), tx, `SELECT email FROM accounts`, nil, exact("bob@example.com"))
+	require.NoError(t, err)
+}
+
+func Test_RetentionPolicies(t *testing.T) {
+	db := newTestDB(t, nil, nil)
+
+	ctx := context.Background()
+	tx, err := db.BeginTx(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback(ctx) // always rollback
+
+	now := time.Now().Unix()
+	hour := int64(time.Hour / time.Second)
+
+	interp := newTestInterp(t, tx, []string{`CREATE TABLE events (
+		id INT PRIMARY KEY,
+		created_at INT8 NOT NULL
+	);`, fmt.Sprintf(`INSERT INTO events (id, created_at) VALUES (1, %d), (2, %d), (3, %d);`,
+		now-48*hour, now-2*hour, now)}, false)
+
+	err = interp.SetRetentionPolicy(ctx, tx, "main", "events", "id", 0)
+	require.Error(t, err)
+	err = interp.SetRetentionPolicy(ctx, tx, "main", "events", "missing", time.Hour)
+	require.ErrorIs(t, err, engine.ErrUnknownColumn)
+
+	// replacing the policy keeps only the latest
+	err = interp.SetRetentionPolicy(ctx, tx, "main", "events", "created_at", time.Hour)
+	require.NoError(t, err)
+	err = interp.SetRetentionPolicy(ctx, tx, "main", "events", "created_at", 24*time.Hour)
+	require.NoError(t, err)
+
+	ids := func() []int64 {
+		var ids []int64
+		err := interp.Execute(newEngineCtx(defaultCaller), tx, `SELECT id FROM events ORDER BY id`, nil, func(r *common.Row) error {
+			ids = append(ids, r.Values[0].(int64))
+			return nil
+		})
+		require.NoError(t, err)
+		return ids
+	}
+
+	// rows are deleted as of the timestamp of the block, rather than the
+	// local clock
+	err = interp.EnforceRetentionPolicies(ctx, tx, &common.BlockContext{Timestamp: now - 30*hour})
+	require.NoError(t, err)
+	require.Equal(t, []int64{1, 2, 3}, ids())
+
+	err = interp.EnforceRetentionPolicies(ctx, tx, &common.BlockContext{Timestamp: now})
+	require.NoError(t, err)
+	require.Equal(t, []int64{2, 3}, ids())
+}
+
+func Test_SoftDelete(t *testing.T) {
+	db := newTestDB(t, nil, nil)
+
+	ctx := context.Background()
+	tx, err := db.BeginTx(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback(ctx) // always rollback
+
+	interp := newTestInterp(t, tx, []string{`-- @soft_delete
+	CREATE TABLE posts (
+		id INT PRIMARY KEY,
+		body TEXT
+	);`, `INSERT INTO posts (id, body) VALUES (1, 'hello'), (2, 'world');`}, false)
+
+	countPosts := func(t *testing.T, want int64) {
+		err := interp.Execute(newEngineCtx(defaultCaller), tx, `SELECT count(*) FROM posts`, nil, exact(want))
+		require.NoError(t, err)
+	}
+	countPosts(t, 2)
+
+	err = interp.Execute(newEngineCtx(defaultCaller), tx, `soft_delete_row('posts', 1);`, nil, nil)
+	require.NoError(t, err)
+	countPosts(t, 1)
+
+	// soft deleted rows are invisible to joins and subqueries as well
+	err = interp.Execute(newEngineCtx(defaultCaller), tx, `SELECT p.body FROM posts p
+	WHERE p.id IN (SELECT id FROM posts)`, nil, exact("world"))
+	require.NoError(t, err)
+
+	err = interp.Execute(newEngineCtx(defaultCaller), tx, `restore_row('posts', 1);`, nil, nil)
+	require.NoError(t, err)
+	countPosts(t, 2)
+
+	// hard deletes are blocked
+	err = interp.Execute(newEngineCtx(defaultCaller), tx, `DELETE FROM posts WHERE id = 2;`, nil, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "soft delete")
+}
+
+type mockCDCPublisher struct {
+	events []interpreter.CDCEvent
+}
+
+func (m *mockCDCPublisher) Publish(event interpreter.CDCEvent) error {
+	m.events = append(m.events, event)
+	return nil
+}
+
+func Test_CDCPublisher(t *testing.T) {
+	db := newTestDB(t, nil, nil)
+
+	ctx := context.Background()
+	tx, err := db.BeginTx(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback(ctx) // always rollback
+
+	pub := &mockCDCPublisher{}
+	interp := newTestInterp(t, tx, []string{`CREATE TABLE items (id INT PRIMARY KEY, name TEXT);`,
+		`CREATE ACTION add_item($id int, $name text) public { INSERT INTO items (id, name) VALUES ($id, $name); };`,
+		`CREATE ACTION rename_item($id int, $name text) public { UPDATE items SET name = $name WHERE id = $id; };`,
+		`CREATE ACTION remove_item($id int) public { DELETE FROM items WHERE id = $id; };`,
+		`CREATE ACTION fail($id int) public { INSERT INTO items (id, name) VALUES ($id, 'x'); error('fail'); };`,
+	}, false, interpreter.WithCDCPublisher(pub))
+
+	call := func(action string, args ...any) {
+		res, err := interp.Call(newEngineCtx(defaultCaller), tx, "", action, args, nil)
+		require.NoError(t, err)
+		require.NoError(t, res.Error)
+	}
+
+	// the changes of a block that is rolled back are discarded
+	call("add_item", int64(3), "c")
+	require.NoError(t, interp.CollectChanges(ctx, tx))
+	interp.BlockRolledBack()
+	interp.BlockCommitted()
+
+	call("add_item", int64(1), "a")
+	call("rename_item", int64(1), "b")
+	call("remove_item", int64(1))
+
+	// failed actions publish nothing
+	res, err := interp.Call(newEngineCtx(defaultCaller), tx, "", "fail", []any{int64(2)}, nil)
+	require.NoError(t, err)
+	require.Error(t, res.Error)
+
+	// changes are only published once the block is committed
+	require.NoError(t, interp.CollectChanges(ctx, tx))
+	require.Empty(t, pub.events)
+	interp.BlockCommitted()
+
+	// stopping the publisher publishes the changes that were committed
+	require.NoError(t, interp.StopCDCPublisher())
+	require.Len(t, pub.events, 3)
+	for i, want := range []wal.Operation{wal.OperationInsert, wal.OperationUpdate, wal.OperationDelete} {
+		require.Equal(t, want, pub.events[i].Operation)
+		require.Equal(t, "main", pub.events[i].Namespace)
+		require.Equal(t, "items", pub.events[i].Table)
+		require.Equal(t, int64(1), pub.events[i].BlockHeight)
+	}
+
+	require.Nil(t, pub.events[0].BeforeImage)
+	require.JSONEq(t, `{"id": 1, "name": "a"}`, string(pub.events[0].AfterImage))
+	require.JSONEq(t, `{"id": 1, "name": "a"}`, string(pub.events[1].BeforeImage))
+	require.JSONEq(t, `{"id": 1, "name": "b"}`, string(pub.events[1].AfterImage))
+	require.JSONEq(t, `{"id": 1, "name": "b"}`, string(pub.events[2].BeforeImage))
+	require.Nil(t, pub.events[2].AfterImage)
+}
+
+func Test_HistoryTables(t *testing.T) {
+	db := newTestDB(t, nil, nil)
+
+	ctx := context.Background()
+	tx, err := db.BeginTx(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback(ctx) // always rollback
+
+	interp := newTestInterp(t, tx, []string{`-- @history
+	CREATE TABLE accounts (
+		id INT PRIMARY KEY,
+		balance INT
+	);`, `INSERT INTO accounts (id, balance) VALUES (1, 100), (2, 0);`,
+		`CREATE ACTION history($id int) public view returns table(balance int, operation text) {
+			for $h in get_row_history('accounts', $id, 10) {
+				RETURN NEXT $h.balance, $h.history_operation;
+			}
+		};`}, false)
+
+	for _, stmt := range []string{
+		`UPDATE accounts SET balance = 200 WHERE id = 1;`,
+		`UPDATE accounts SET balance = 300 WHERE id = 1;`,
+		`UPDATE accounts SET balance = 400 WHERE id = 1;`,
+	} {
+		err = interp.Execute(newEngineCtx(defaultCaller), tx, stmt, nil, nil)
+		require.NoError(t, err)
+	}
+
+	getHistory := func(t *testing.T) [][]any {
+		var history [][]any
+		res, err := interp.Call(newEngineCtx(defaultCaller), tx, "", "history", []any{int64(1)}, func(r *common.Row) error {
+			history = append(history, r.Values)
+			return nil
+		})
+		require.NoError(t, err)
+		require.NoError(t, res.Error)
+		return history
+	}
+
+	// three updates record the three previous states, most recent first
+	require.Equal(t, [][]any{
+		{int64(300), "U"},
+		{int64(200), "U"},
+		{int64(100), "U"},
+	}, getHistory(t))
+
+	err = interp.Execute(newEngineCtx(defaultCaller), tx, `DELETE FROM accounts WHERE id = 1;`, nil, nil)
+	require.NoError(t, err)
+
+	history := getHistory(t)
+	require.Len(t, history, 4)
+	require.Equal(t, []any{int64(400), "D"}, history[0])
+
+	// the history table is not visible as a table
+	err = interp.Execute(newEngineCtx(defaultCaller), tx, `SELECT count(*) FROM info.tables WHERE name = '_history_accounts';`, nil, exact(int64(0)))
+	require.NoError(t, err)
+}
+
+func Test_IdempotentActions(t *testing.T) {
+	db := newTestDB(t, nil, nil)
+
+	ctx := context.Background()
+	tx, err := db.BeginTx(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback(ctx) // always rollback
+
+	interp := newTestInterp(t, tx, []string{`CREATE TABLE payments (
+		id INT PRIMARY KEY,
+		amount INT NOT NULL
+	);`, `-- @idempotent
+	CREATE ACTION pay($amount int) public returns (id int) {
+		$id := 1;
+		for $row in SELECT count(*) AS n FROM payments {
+			$id := $row.n + 1;
+		}
+		INSERT INTO payments (id, amount) VALUES ($id, $amount);
+		notice('paid ' || $amount::text);
+		RETURN $id;
+	};`}, false)
+
+	pay := func(t *testing.T, height, amount int64) (int64, []string) {
+		engCtx := newEngineCtx(defaultCaller)
+		engCtx.TxContext.BlockContext.Height = height
+
+		var id int64
+		res, err := interp.Call(engCtx, tx, "", "pay", []any{amount}, func(r *common.Row) error {
+			id = r.Values[0].(int64)
+			return nil
+		})
+		require.NoError(t, err)
+		require.NoError(t, res.Error)
+		return id, res.Logs
+	}
+
+	countPayments := func(t *testing.T) int64 {
+		var count int64
+		err := interp.Execute(newEngineCtx(defaultCaller), tx, `SELECT count(*) FROM payments;`, nil, func(r *common.Row) error {
+			count = r.Values[0].(int64)
+			return nil
+		})
+		require.NoError(t, err)
+		return count
+	}
+
+	id, logs := pay(t, 10, 100)
+	require.Equal(t, int64(1), id)
+	require.Equal(t, []string{"paid 100"}, logs)
+
+	// the retried call returns the stored result without inserting again
+	id, logs = pay(t, 11, 100)
+	require.Equal(t, int64(1), id)
+	require.Equal(t, []string{"paid 100"}, logs)
+	require.Equal(t, int64(1), countPayments(t))
+
+	// a call with other arguments is executed
+	id, _ = pay(t, 12, 200)
+	require.Equal(t, int64(2), id)
+	require.Equal(t, int64(2), countPayments(t))
+
+	// once the idempotency window has passed, the call is executed again
+	id, _ = pay(t, 10+interpreter.IdempotencyWindowBlocks, 100)
+	require.Equal(t, int64(3), id)
+	require.Equal(t, int64(3), countPayments(t))
+}
+
+func Test_TestActions(t *testing.T) {
+	db := newTestDB(t, nil, nil)
+
+	ctx := context.Background()
+	tx, err := db.BeginTx(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback(ctx) // always rollback
+
+	interp := newTestInterp(t, tx, []string{`-- @test_namespace
+	CREATE NAMESPACE ledger_test;`}, false)
+
+	exec := func(stmt string) error {
+		return interp.Execute(newEngineCtx(defaultCaller), tx, stmt, nil, nil)
+	}
+
+	// test actions can only be created in test namespaces
+	err = exec(`-- @test
+	CREATE ACTION test_main() public {};`)
+	require.Error(t, err)
+
+	err = exec(`{ledger_test}-- @test
+	CREATE ACTION test_passes() public {};`)
+	require.NoError(t, err)
+	err = exec(`{ledger_test}-- @test
+	CREATE ACTION test_fails() public {
+		error('balance is wrong');
+	};`)
+	require.NoError(t, err)
+
+	res, err := interp.Call(newEngineCtx(defaultCaller), tx, "ledger_test", "test_passes", nil, nil)
+	require.NoError(t, err)
+	require.NoError(t, res.Error)
+
+	res, err = interp.Call(newEngineCtx(defaultCaller), tx, "ledger_test", "test_fails", nil, nil)
+	require.NoError(t, err)
+	require.ErrorContains(t, res.Error, "balance is wrong")
+
+	// test actions are never run by read-only calls
+	readTx, err := db.BeginReadTx(ctx)
+	require.NoError(t, err)
+	defer readTx.Rollback(ctx)
+
+	_, err = interp.Call(newEngineCtx(defaultCaller), readTx, "ledger_test", "test_passes", nil, nil)
+	require.ErrorContains(t, err, "test action")
+}
+
+func Test_EventSourcing(t *testing.T) {
+	db := newTestDB(t, nil, nil)
+
+	ctx := context.Background()
+	tx, err := db.BeginTx(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback(ctx) // always rollback
+
+	interp := newTestInterp(t, tx, []string{`-- @event_sourced
+	CREATE NAMESPACE ledger;`, `{ledger}CREATE TABLE accounts (
+		id INT PRIMARY KEY,
+		balance INT
+	);`}, false)
+
+	atBlock := func(height int64) *common.EngineContext {
+		engCtx := newEngineCtx(defaultCaller)
+		engCtx.TxContext.BlockContext.Height = height
+		return engCtx
+	}
+
+	for _, change := range []struct {
+		height int64
+		stmt   string
+	}{
+		{3, `{ledger}INSERT INTO accounts (id, balance) VALUES (1, 100), (2, 50), (3, 10);`},
+		{5, `{ledger}UPDATE accounts SET balance = 150 WHERE id = 1;`},
+		{7, `{ledger}UPDATE accounts SET balance = 200 WHERE id = 1;`},
+		{8, `{ledger}DELETE FROM accounts WHERE id = 2;`},
+		{9, `{ledger}UPDATE accounts SET id = 4 WHERE id = 3;`},
+	} {
+		err = interp.Execute(atBlock(change.height), tx, change.stmt, nil, nil)
+		require.NoError(t, err)
+	}
+
+	project := func(t *testing.T, height int64) []string {
+		var rows []string
+		err := interp.Execute(newEngineCtx(defaultCaller), tx, `SELECT row_data FROM project_events('ledger', 'accounts', $height);`,
+			map[string]any{"height": height}, func(r *common.Row) error {
+				rows = append(rows, r.Values[0].(string))
+				return nil
+			})
+		require.NoError(t, err)
+		return rows
+	}
+
+	// block 5 has the first update, but not the changes after it
+	require.Equal(t, []string{
+		`{"id": 1, "balance": 150}`,
+		`{"id": 2, "balance": 50}`,
+		`{"id": 3, "balance": 10}`,
+	}, project(t, 5))
+
+	// block 10 has every change, and matches the table
+	require.Equal(t, []string{
+		`{"id": 1, "balance": 200}`,
+		`{"id": 4, "balance": 10}`,
+	}, project(t, 10))
+
+	require.Empty(t, project(t, 2))
+
+	// the rows are updated in place as well
+	err = interp.Execute(newEngineCtx(defaultCaller), tx, `SELECT balance FROM ledger.accounts WHERE id = 1;`, nil, exact(int64(200)))
+	require.NoError(t, err)
+
+	err = interp.Execute(newEngineCtx(defaultCaller), tx, `SELECT * FROM project_events('main', 'users', 10);`, nil, nil)
+	require.ErrorContains(t, err, `namespace "main" is not event sourced`)
+
+	// the events are dropped with the namespace
+	err = interp.Execute(newEngineCtx(defaultCaller), tx, `DROP NAMESPACE ledger;`, nil, nil)
+	require.NoError(t, err)
+
+	err = interp.Execute(newEngineCtx(defaultCaller), tx, `SELECT * FROM project_events('ledger', 'accounts', 10);`, nil, nil)
+	require.ErrorContains(t, err, `namespace "ledger" is not event sourced`)
+}
+
+func Test_DistributedTx(t *testing.T) {
+	ctx := context.Background()
+	pool := newTestPool(t)
+
+	// the setup must be committed to be visible to the distributed transactions
+	setup, err := pool.BeginTx(ctx)
+	require.NoError(t, err)
+	interp := newTestInterp(t, setup, []string{`CREATE TABLE transfers (id INT PRIMARY KEY, amount INT);`}, false)
+	require.NoError(t, setup.Commit(ctx))
+
+	// a prepared transaction must not outlive a failed test
+	defer pool.Execute(ctx, `ROLLBACK PREPARED 'kwild_dtx_transfer_1'`)
+
+	err = interp.BeginDistributedTx(ctx, pool, "bad'id")
+	require.Error(t, err)
+
+	err = interp.BeginDistributedTx(ctx, pool, "transfer_1")
+	require.NoError(t, err)
+	err = interp.BeginDistributedTx(ctx, pool, "transfer_1")
+	require.Error(t, err)
+
+	dtx, err := interp.DistributedTx("transfer_1")
+	require.NoError(t, err)
+	err = interp.Execute(newEngineCtx(defaultCaller), dtx, `INSERT INTO transfers (id, amount) VALUES (1, 100);`, nil, nil)
+	require.NoError(t, err)
+
+	err = interp.CommitDistributedTx("transfer_1")
+	require.Error(t, err) // not yet prepared
+
+	err = interp.PrepareDistributedTx("transfer_1")
+	require.NoError(t, err)
+
+	countTransfers := func(t *testing.T, interp *interpreter.ThreadSafeInterpreter) int64 {
+		readTx, err := pool.BeginReadTx(ctx)
+		require.NoError(t, err)
+		defer readTx.Rollback(ctx)
+
+		var count int64
+		err = interp.Execute(newEngineCtx(defaultCaller), readTx, `SELECT count(*) FROM transfers;`, nil, func(r *common.Row) error {
+			count = r.Values[0].(int64)
+			return nil
+		})
+		require.NoError(t, err)
+		return count
+	}
+	require.EqualValues(t, 0, countTransfers(t, interp))
+
+	// Simulate a failure between prepare and commit by discarding the
+	// interpreter, and recovering with a new one.
+	startup, err := pool.BeginTx(ctx)
+	require.NoError(t, err)
+	recovered, err := interpreter.NewInterpreter(ctx, startup, &common.Service{}, nil, nil, nil)
+	require.NoError(t, err)
+	require.NoError(t, startup.Commit(ctx))
+
+	err = recovered.CommitDistributedTx("transfer_1")
+	require.Error(t, err) // unknown until recovered
+
+	txIDs, err := recovered.RecoverDistributedTxs(ctx, pool)
+	require.NoError(t, err)
+	require.Equal(t, []string{"transfer_1"}, txIDs)
+
+	err = recovered.CommitDistributedTx("transfer_1")
+	require.NoError(t, err)
+	require.EqualValues(t, 1, countTransfers(t, recovered))
+
+	txIDs, err = recovered.RecoverDistributedTxs(ctx, pool)
+	require.NoError(t, err)
+	require.Empty(t, txIDs)
+
+	// an unprepared transaction can be rolled back
+	err = recovered.BeginDistributedTx(ctx, pool, "transfer_2")
+	require.NoError(t, err)
+	dtx, err = recovered.DistributedTx("transfer_2")
+	require.NoError(t, err)
+	err = recovered.Execute(newEngineCtx(defaultCaller), dtx, `INSERT INTO transfers (id, amount) VALUES (2, 200);`, nil, nil)
+	require.NoError(t, err)
+	err = recovered.RollbackDistributedTx("transfer_2")
+	require.NoError(t, err)
+	require.EqualValues(t, 1, countTransfers(t, recovered))
+}
+
+func Test_Sagas(t *testing.T) {
+	db := newTestDB(t, nil, nil)
+
+	ctx := context.Background()
+	tx, err := db.BeginTx(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback(ctx) // always rollback
+
+	interp := newTestInterp(t, tx, []string{`CREATE TABLE ledger (id INT PRIMARY KEY, amount INT NOT NULL);`,
+		`CREATE ACTION add_entry($id int, $amount int) public { INSERT INTO ledger (id, amount) VALUES ($id, $amount); };`,
+		`CREATE ACTION undo_entry($id int) public {
+			DELETE FROM ledger WHERE id = $id;
+			notice('undo ' || $id::text);
+		};`,
+		`CREATE ACTION settle($id int) public {
+			INSERT INTO ledger (id, amount) VALUES ($id, 0);
+			if $id > 100 {
+				error('settlement failed');
+			}
+		};`}, false)
+
+	steps := []interpreter.SagaStep{
+		{Action: "add_entry", Args: []any{interpreter.SagaArg(0), int64(-100)}},
+		{Action: "add_entry", Args: []any{interpreter.SagaArg(1), int64(100)}},
+		{Action: "settle", Args: []any{interpreter.SagaArg(2)}},
+	}
+	compensations := []interpreter.SagaStep{
+		{Action: "undo_entry", Args: []any{interpreter.SagaArg(0)}},
+		{Action: "undo_entry", Args: []any{interpreter.SagaArg(1)}},
+		{Action: "undo_entry", Args: []any{interpreter.SagaArg(2)}},
+	}
+
+	err = interp.RegisterSaga("transfer", steps, compensations[:2])
+	require.Error(t, err)
+	err = interp.RegisterSaga("transfer", steps, compensations)
+	require.NoError(t, err)
+	err = interp.RegisterSaga("transfer", steps, compensations)
+	require.Error(t, err)
+
+	_, err = interp.ExecuteSaga(newEngineCtx(defaultCaller), tx, "unknown", nil)
+	require.Error(t, err)
+
+	countEntries := func(t *testing.T) int64 {
+		var count int64
+		err := interp.Execute(newEngineCtx(defaultCaller), tx, `SELECT count(*) FROM ledger;`, nil, func(r *common.Row) error {
+			count = r.Values[0].(int64)
+			return nil
+		})
+		require.NoError(t, err)
+		return count
+	}
+
+	// a failure at step 3 compensates steps 2 and 1, in that order
+	res, err := interp.ExecuteSaga(newEngineCtx(defaultCaller), tx, "transfer", []any{int64(1), int64(2), int64(101)})
+	require.NoError(t, err)
+	require.Equal(t, interpreter.SagaCompensated, res.Status)
+	require.Equal(t, 2, res.FailedStep)
+	require.ErrorContains(t, res.Error, "settlement failed")
+	require.Equal(t, []string{"undo 2", "undo 1"}, res.Logs)
+	require.EqualValues(t, 0, countEntries(t))
+
+	var status string
+	var step int64
+	err = pg.QueryRowFunc(ctx, tx, `SELECT status, step FROM kwild_engine.sagas WHERE id = $1`, []any{&status, &step},
+		func() error { return nil }, pg.QueryModeExec, res.ID)
+	require.NoError(t, err)
+	require.Equal(t, "compensated", status)
+	require.EqualValues(t, 0, step)
+
+	res, err = interp.ExecuteSaga(newEngineCtx(defaultCaller), tx, "transfer", []any{int64(1), int64(2), int64(3)})
+	require.NoError(t, err)
+	require.Equal(t, interpreter.SagaCompleted, res.Status)
+	require.Equal(t, -1, res.FailedStep)
+	require.NoError(t, res.Error)
+	require.EqualValues(t, 3, countEntries(t))
+}
+
+func Test_Backpressure(t *testing.T) {
+	ctx := context.Background()
+	pool := newTestPool(t)
+
+	setup, err := pool.BeginTx(ctx)
+	require.NoError(t, err)
+	interp := newTestInterp(t, setup, []string{`CREATE ACTION one() public view returns table(n int) {
+		RETURN NEXT 1;
+	};`}, false, interpreter.WithMaxConcurrentCalls(2))
+	require.NoError(t, setup.Commit(ctx))
+
+	require.Equal(t, 2, interp.MaxConcurrency())
+	require.Equal(t, 0, interp.CurrentConcurrency())
+
+	// each call blocks in its result callback until released
+	started := make(chan struct{})
+	release := make(chan struct{})
+	errs := make(chan error, 2)
+	for range 2 {
+		go func() {
+			readTx, err := pool.BeginReadTx(ctx)
+			if err != nil {
+				errs <- err
+				return
+			}
+			defer readTx.Rollback(ctx)
+
+			_, err = interp.Call(newEngineCtx(defaultCaller), readTx, "", "one", nil, func(r *common.Row) error {
+				started <- struct{}{}
+				<-release
+				return nil
+			})
+			errs <- err
+		}()
+	}
+	<-started
+	<-started
+	require.Equal(t, 2, interp.CurrentConcurrency())
+
+	readTx, err := pool.BeginReadTx(ctx)
+	require.NoError(t, err)
+	defer readTx.Rollback(ctx)
+
+	_, err = interp.Call(newEngineCtx(defaultCaller), readTx, "", "one", nil, nil)
+	require.ErrorIs(t, err, engine.ErrBackpressure)
+	err = interp.Execute(newEngineCtx(defaultCaller), readTx, `SELECT 1;`, nil, nil)
+	require.ErrorIs(t, err, engine.ErrBackpressure)
+
+	// calls with a read-write database, such as those of block execution,
+	// are not limited. The call waits for the lock held by the others.
+	writeTx, err := pool.BeginTx(ctx)
+	require.NoError(t, err)
+	defer writeTx.Rollback(ctx)
+	writeErr := make(chan error, 1)
+	go func() {
+		_, err := interp.Call(newEngineCtx(defaultCaller), writeTx, "", "one", nil, nil)
+		writeErr <- err
+	}()
+
+	close(release)
+	require.NoError(t, <-errs)
+	require.NoError(t, <-errs)
+	require.NoError(t, <-writeErr)
+	require.Equal(t, 0, interp.CurrentConcurrency())
+
+	_, err = interp.Call(newEngineCtx(defaultCaller), readTx, "", "one", nil, nil)
+	require.NoError(t, err)
+}
+
+func Test_QueryClass(t *testing.T) {
+	ctx := context.Background()
+	pool := newTestPool(t)
+
+	setup, err := pool.BeginTx(ctx)
+	require.NoError(t, err)
+	interp := newTestInterp(t, setup, []string{`CREATE ACTION one() public view returns (n int) {
+		return 1;
+	};`}, false)
+	require.NoError(t, setup.Commit(ctx))
+
+	callWithClass := func(t *testing.T, class common.QueryClass) string {
+		readTx, err := pool.BeginReadTx(ctx)
+		require.NoError(t, err)
+		defer readTx.Rollback(ctx)
+
+		engCtx := newEngineCtx(defaultCaller)
+		engCtx.QueryClass = class
+		_, err = interp.Call(engCtx, readTx, "", "one", nil, nil)
+		require.NoError(t, err)
+
+		// the settings last for the rest of the transaction
+		var workMem string
+		err = pg.QueryRowFunc(ctx, readTx, `SHOW work_mem`, []any{&workMem}, func() error { return nil }, pg.QueryModeExec)
+		require.NoError(t, err)
+		return workMem
+	}
+
+	defaultWorkMem := callWithClass(t, common.Transactional)
+	require.Equal(t, "1MB", callWithClass(t, common.Analytical))
+	require.Equal(t, "64MB", callWithClass(t, common.Reporting))
+
+	// the class does not change the resources of a transaction that can
+	// mutate state
+	tx, err := pool.BeginTx(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback(ctx)
+
+	engCtx := newEngineCtx(defaultCaller)
+	engCtx.QueryClass = common.Analytical
+	_, err = interp.Call(engCtx, tx, "", "one", nil, nil)
+	require.NoError(t, err)
+
+	var workMem string
+	err = pg.QueryRowFunc(ctx, tx, `SHOW work_mem`, []any{&workMem}, func() error { return nil }, pg.QueryModeExec)
+	require.NoError(t, err)
+	require.Equal(t, defaultWorkMem, workMem)
+}
+
+func Test_PlanBaselines(t *testing.T) {
+	db := newTestDB(t, nil, nil)
+
+	ctx := context.Background()
+	tx, err := db.BeginTx(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback(ctx) // always rollback
+
+	interp := newTestInterp(t, tx, []string{`CREATE TABLE users (
+		id INT PRIMARY KEY,
+		name TEXT
+	);`, `CREATE ACTION by_name($name text) public view returns table(id int) {
+		return SELECT id FROM users WHERE name = $name;
+	};`}, false)
+
+	// force index scans once an index exists, since the table is empty
+	_, err = tx.Execute(ctx, `SET LOCAL enable_seqscan = off`, pg.QueryModeExec)
+	require.NoError(t, err)
+
+	err = interp.SavePlanBaseline(ctx, tx, "main", "by_name", []any{"alice"}, "v1")
+	require.NoError(t, err)
+
+	cmp, err := interp.ComparePlans(ctx, tx, "main", "by_name", []any{"alice"}, "v1")
+	require.NoError(t, err)
+	require.False(t, cmp.Changed)
+	require.Empty(t, cmp.AddedNodes)
+	require.Empty(t, cmp.RemovedNodes)
+	require.Zero(t, cmp.CostDelta)
+
+	err = interp.Execute(newEngineCtx(defaultCaller), tx, `CREATE INDEX name_idx ON users (name);`, nil, nil)
+	require.NoError(t, err)
+
+	cmp, err = interp.ComparePlans(ctx, tx, "main", "by_name", []any{"alice"}, "v1")
+	require.NoError(t, err)
+	require.True(t, cmp.Changed)
+	require.Equal(t, []string{"Seq Scan on users"}, cmp.RemovedNodes)
+	require.Len(t, cmp.AddedNodes, 1)
+	require.Contains(t, cmp.AddedNodes[0], "using name_idx")
+	require.Negative(t, cmp.CostDelta)
+
+	// a baseline can only be compared with the action it was saved for
+	_, err = interp.ComparePlans(ctx, tx, "main", "by_name", []any{"alice"}, "unknown")
+	require.Error(t, err)
+	_, err = interp.ComparePlans(ctx, tx, "main", "other", nil, "v1")
+	require.Error(t, err)
+}
+
+func Test_AnalyzeCall(t *testing.T) {
+	db := newTestDB(t, nil, nil)
+
+	ctx := context.Background()
+	tx, err := db.BeginTx(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback(ctx) // always rollback
+
+	interp := newTestInterp(t, tx, []string{`CREATE TABLE users (
+		id INT PRIMARY KEY,
+		name TEXT
+	);`, `INSERT INTO users (id, name) VALUES (1, 'alice'), (2, 'bob'), (3, 'alice');`,
+		`CREATE ACTION add_and_list($id int, $name text) public returns table(id int) {
+		INSERT INTO users (id, name) VALUES ($id, $name);
+		return SELECT id FROM users WHERE name = $name;
+	};`}, false)
+
+	res, err := interp.AnalyzeCall(newEngineCtx(defaultCaller), tx, "main", "add_and_list", []any{4, "alice"})
+	require.NoError(t, err)
+	require.Len(t, res.Queries, 2)
+
+	// the insert is only applied once, so the select sees the three alices
+	require.Equal(t, int64(3), res.Queries[1].Rows)
+	for _, q := range res.Queries {
+		require.NotEmpty(t, q.SQL)
+		require.Positive(t, q.ExecutionTime)
+	}
+
+	// the changes of the call are rolled back
+	r, err := tx.Execute(ctx, `SELECT count(*) FROM main.users`, pg.QueryModeExec)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), r.Rows[0][0])
+
+	// a failing action fails the analysis
+	_, err = interp.AnalyzeCall(newEngineCtx(defaultCaller), tx, "main", "add_and_list", []any{1, "alice"})
+	require.Error(t, err)
+}
+
+func Test_SchemaMigration(t *testing.T) {
+	ctx := context.Background()
+	pool := newTestPool(t)
+
+	setup, err := pool.BeginTx(ctx)
+	require.NoError(t, err)
+	interp := newTestInterp(t, setup, []string{`CREATE TABLE users (id INT PRIMARY KEY, name TEXT);`}, false)
+	require.NoError(t, setup.Commit(ctx))
+
+	// schema returns the tables and indexes of the main namespace in Postgres
+	schema := func(t *testing.T) []string {
+		var objects []string
+		var name string
+		err := pg.QueryRowFunc(ctx, pool, `SELECT relname::text FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace WHERE n.nspname = 'main' ORDER BY relname`,
+			[]any{&name}, func() error {
+				objects = append(objects, name)
+				return nil
+			}, pg.QueryModeExec)
+		require.NoError(t, err)
+		return objects
+	}
+	before := schema(t)
+
+	t.Run("failure rolls back the migration", func(t *testing.T) {
+		err := interp.ApplySchemaMigration(newEngineCtx(defaultCaller), pool, &interpreter.SchemaMigration{
+			Statements: []string{
+				`CREATE TABLE posts (id INT PRIMARY KEY, author INT);`,
+				`CREATE INDEX author_idx ON posts (author);`,
+				`CREATE TABLE users (id INT PRIMARY KEY);`, // already exists
+				`CREATE TABLE comments (id INT PRIMARY KEY);`,
+				`ALTER TABLE users ADD COLUMN age INT;`,
+			},
+		})
+		var migErr *interpreter.MigrationRollbackError
+		require.ErrorAs(t, err, &migErr)
+		require.Equal(t, 2, migErr.Step)
+		require.True(t, migErr.RolledBack)
+
+		require.Equal(t, before, schema(t))
+
+		// the engine's schema was rolled back as well
+		tx, err := pool.BeginTx(ctx)
+		require.NoError(t, err)
+		defer tx.Rollback(ctx)
+		err = interp.Execute(newEngineCtx(defaultCaller), tx, `SELECT * FROM posts;`, nil, nil)
+		require.Error(t, err)
+	})
+
+	t.Run("non-transactional statements are undone by the rollback script", func(t *testing.T) {
+		err := interp.ApplySchemaMigration(newEngineCtx(defaultCaller), pool, &interpreter.SchemaMigration{
+			Statements: []string{
+				`CREATE INDEX CONCURRENTLY name_idx ON main.users (name)`,
+				`CREATE INDEX CONCURRENTLY missing_idx ON main.missing (name)`,
+			},
+			RollbackScript: []string{
+				`DROP INDEX CONCURRENTLY main.name_idx`,
+				`DROP INDEX CONCURRENTLY main.missing_idx`,
+			},
+		})
+		var migErr *interpreter.MigrationRollbackError
+		require.ErrorAs(t, err, &migErr)
+		require.Equal(t, 1, migErr.Step)
+		require.True(t, migErr.RolledBack)
+
+		require.Equal(t, before, schema(t))
+	})
+
+	t.Run("rollback script must match non-transactional statements", func(t *testing.T) {
+		err := interp.ApplySchemaMigration(newEngineCtx(defaultCaller), pool, &interpreter.SchemaMigration{
+			Statements: []string{`CREATE INDEX CONCURRENTLY name_idx ON main.users (name)`},
+		})
+		require.Error(t, err)
+	})
+
+	err = interp.ApplySchemaMigration(newEngineCtx(defaultCaller), pool, &interpreter.SchemaMigration{
+		Statements: []string{
+			`CREATE TABLE posts (id INT PRIMARY KEY, author INT);`,
+			`CREATE INDEX author_idx ON posts (author);`,
+		},
+	})
+	require.NoError(t, err)
+	require.ElementsMatch(t, append([]string{"author_idx", "posts", "posts_pkey"}, before...), schema(t))
+}
+
+func Test_OnlineAddColumn(t *testing.T) {
+	ctx := context.Background()
+	pool := newTestPool(t)
+
+	setup, err := pool.BeginTx(ctx)
+	require.NoError(t, err)
+	interp := newTestInterp(t, setup, []string{`CREATE TABLE users (id INT PRIMARY KEY, name TEXT);`,
+		`CREATE INDEX name_idx ON users (name);`}, false)
+	_, err = setup.Execute(ctx, `INSERT INTO main.users SELECT g, 'user' || g FROM generate_series(1, 2500) g`, pg.QueryModeExec)
+	require.NoError(t, err)
+	require.NoError(t, setup.Commit(ctx))
+
+	// block runs fn in the transaction of a block, and advances the online
+	// schema changes at its end
+	block := func(fn func(tx sql.DB) error) {
+		tx, err := pool.BeginTx(ctx)
+		require.NoError(t, err)
+		defer tx.Rollback(ctx)
+
+		require.NoError(t, fn(tx))
+		require.NoError(t, interp.AdvanceOnlineSchemaChanges(ctx, tx))
+		require.NoError(t, tx.Commit(ctx))
+	}
+	progress := func() (status string, rowsCopied int64) {
+		err := pg.QueryRowFunc(ctx, pool, `SELECT status, rows_copied FROM kwild_engine.online_schema_changes
+		WHERE namespace = 'main' AND table_name = 'users'`, []any{&status, &rowsCopied}, func() error { return nil }, pg.QueryModeExec)
+		require.NoError(t, err)
+		return status, rowsCopied
+	}
+
+	// the change must be made by the transaction of a block
+	readTx, err := pool.BeginReadTx(ctx)
+	require.NoError(t, err)
+	err = interp.OnlineAddColumn(ctx, readTx, "main", "users", &engine.Column{Name: "age", DataType: types.IntType, Nullable: true})
+	require.ErrorIs(t, err, engine.ErrCannotMutateState)
+	require.NoError(t, readTx.Rollback(ctx))
+
+	block(func(tx sql.DB) error {
+		return interp.OnlineAddColumn(ctx, tx, "main", "users", &engine.Column{Name: "age", DataType: types.IntType, Nullable: true})
+	})
+	status, rowsCopied := progress()
+	require.Equal(t, "backfilling", status)
+	require.EqualValues(t, 1000, rowsCopied)
+
+	// a table can only have one change in progress
+	tx, err := pool.BeginTx(ctx)
+	require.NoError(t, err)
+	err = interp.OnlineAddColumn(ctx, tx, "main", "users", &engine.Column{Name: "email", DataType: types.TextType, Nullable: true})
+	require.Error(t, err)
+	require.NoError(t, tx.Rollback(ctx))
+
+	// the table is written while its rows are copied, and the rows changed are
+	// copied by the trigger rather than the backfill
+	block(func(tx sql.DB) error {
+		err := interp.Execute(newEngineCtx(defaultCaller), tx, `UPDATE users SET name = 'satoshi' WHERE id = 2000;`, nil, nil)
+		if err != nil {
+			return err
+		}
+		return interp.Execute(newEngineCtx(defaultCaller), tx, `INSERT INTO users (id, name) VALUES (3000, 'hal');`, nil, nil)
+	})
+	status, rowsCopied = progress()
+	require.Equal(t, "backfilling", status)
+	require.EqualValues(t, 2000, rowsCopied)
+
+	// the table is replaced in the block in which its last rows are copied
+	block(func(tx sql.DB) error { return nil })
+	status, rowsCopied = progress()
+	require.Equal(t, "completed", status)
+	require.EqualValues(t, 2499, rowsCopied)
+
+	tx, err = pool.BeginTx(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback(ctx)
+
+	// the final schema has the new column, and the table's indexes
+	var columns []string
+	err = interp.Execute(newEngineCtx(defaultCaller), tx, `SELECT name FROM info.columns WHERE namespace = 'main' AND table_name = 'users' ORDER BY name;`,
+		nil, func(r *common.Row) error {
+			columns = append(columns, r.Values[0].(string))
+			return nil
+		})
+	require.NoError(t, err)
+	require.Equal(t, []string{"age", "id", "name"}, columns)
+
+	var indexes []string
+	err = interp.Execute(newEngineCtx(defaultCaller), tx, `SELECT name FROM info.indexes WHERE namespace = 'main' AND table_name = 'users' ORDER BY name;`,
+		nil, func(r *common.Row) error {
+			indexes = append(indexes, r.Values[0].(string))
+			return nil
+		})
+	require.NoError(t, err)
+	require.Equal(t, []string{"name_idx", "users_pkey"}, indexes)
+
+	err = interp.Execute(newEngineCtx(defaultCaller), tx, `SELECT count(*) FROM users;`, nil, exact(int64(2501)))
+	require.NoError(t, err)
+	err = interp.Execute(newEngineCtx(defaultCaller), tx, `SELECT name FROM users WHERE id = 2000;`, nil, exact("satoshi"))
+	require.NoError(t, err)
+
+	err = interp.Execute(newEngineCtx(defaultCaller), tx, `UPDATE users SET age = 30 WHERE id = 1;`, nil, nil)
+	require.NoError(t, err)
+	err = interp.Execute(newEngineCtx(defaultCaller), tx, `SELECT age FROM users WHERE id = 1;`, nil, exact(int64(30)))
+	require.NoError(t, err)
+}
+
+func Test_ReplayTransactions(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t, nil, nil)
+
+	tx, err := db.BeginTx(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback(ctx)
+
+	privKey, _, err := crypto.GenerateSecp256k1Key(nil)
+	require.NoError(t, err)
+	signer := auth.GetUserSigner(privKey)
+	caller, err := auth.GetUserIdentifier(signer.PubKey())
+	require.NoError(t, err)
+
+	newTx := func(namespace string, amount int64) *types.Transaction {
+		id, err := types.EncodeValue(amount)
+		require.NoError(t, err)
+		execTx, err := types.CreateTransaction(&types.ActionExecution{
+			Namespace: namespace,
+			Action:    "add",
+			Arguments: [][]*types.EncodedValue{{id, id}},
+		}, "test-chain", uint64(amount))
+		require.NoError(t, err)
+		require.NoError(t, execTx.Sign(signer))
+		return execTx
+	}
+
+	bs := memstore.NewMemBS()
+	var prevHash types.Hash
+	for height := int64(1); height <= 100; height++ {
+		txns := []*types.Transaction{newTx("main", height)}
+		if height%10 == 0 {
+			// a transaction for another namespace, and one whose payload was
+			// changed after it was signed
+			txns = append(txns, newTx("other", height))
+			tampered := newTx("main", height+1000)
+			tampered.Body.Payload = newTx("main", height+2000).Body.Payload
+			txns = append(txns, tampered)
+		}
+
+		block := types.NewBlock(height, prevHash, types.Hash{}, types.Hash{}, types.Hash{}, time.Unix(height, 0), txns)
+		require.NoError(t, bs.Store(block, &types.CommitInfo{}))
+		prevHash = block.Hash()
+	}
+
+	interp := newTestInterp(t, tx, []string{
+		`CREATE TABLE ledger (id INT PRIMARY KEY, amount INT NOT NULL, owner TEXT NOT NULL);`,
+		`CREATE ACTION add($id int, $amount int) public { INSERT INTO ledger (id, amount, owner) VALUES ($id, $amount, @caller); }`,
+	}, false, interpreter.WithBlockStore(bs))
+
+	err = interp.ReplayTransactions(ctx, tx, "main", 1, 100)
+	require.NoError(t, err)
+
+	err = interp.Execute(newEngineCtx(defaultCaller), tx, `SELECT count(*), sum(amount)::INT, count(*) FILTER (WHERE owner = $caller) FROM ledger;`,
+		map[string]any{"caller": caller}, func(r *common.Row) error {
+			require.Equal(t, []any{int64(100), int64(5050), int64(100)}, r.Values)
+			return nil
+		})
+	require.NoError(t, err)
+}
+
+func Test_OptimizerHints(t *testing.T) {
+	db := newTestDB(t, nil, nil)
+
+	ctx := context.Background()
+	tx, err := db.BeginTx(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback(ctx) // always rollback
+
+	interp := newTestInterp(t, tx, []string{`-- @optimizer_hint(enable_seqscan, false)
+	CREATE TABLE hinted (id INT PRIMARY KEY, name TEXT);`,
+		`CREATE INDEX hinted_name_idx ON hinted (name);`,
+		`CREATE TABLE unhinted (id INT PRIMARY KEY, name TEXT);`,
+		`CREATE INDEX unhinted_name_idx ON unhinted (name);`,
+		`CREATE ACTION hinted_by_name($name text) public view returns table(id int) {
+			return SELECT id FROM hinted WHERE name = $name;
+		};`,
+		`CREATE ACTION unhinted_by_name($name text) public view returns table(id int) {
+			return SELECT id FROM unhinted WHERE name = $name;
+		};`,
+		`-- @optimizer_hint(enable_seqscan, false)
+		CREATE ACTION action_hinted_by_name($name text) public view returns table(id int) {
+			return SELECT id FROM unhinted WHERE name = $name;
+		};`,
+	}, false)
+
+	// planNodes returns the plan nodes of an action
+	planNodes := func(action string) string {
+		err := interp.SavePlanBaseline(ctx, tx, "main", action, []any{"alice"}, action)
+		require.NoError(t, err)
+
+		var nodes string
+		err = pg.QueryRowFunc(ctx, tx, `SELECT nodes FROM kwild_engine.plan_baselines WHERE id = $1`, []any{&nodes},
+			func() error { return nil }, pg.QueryModeExec, action)
+		require.NoError(t, err)
+		return nodes
+	}
+
+	// the tables are empty, so Postgres only uses the index if sequential scans are disabled
+	require.Contains(t, planNodes("unhinted_by_name"), "Seq Scan on unhinted")
+	require.Contains(t, planNodes("hinted_by_name"), "using hinted_name_idx")
+	require.Contains(t, planNodes("action_hinted_by_name"), "using unhinted_name_idx")
+
+	// the hints are reset after each query
+	err = interp.Execute(newEngineCtx(defaultCaller), tx, `SELECT id FROM hinted WHERE name = 'alice';`, nil, nil)
+	require.NoError(t, err)
+	var seqScan string
+	err = pg.QueryRowFunc(ctx, tx, `SHOW enable_seqscan`, []any{&seqScan}, func() error { return nil }, pg.QueryModeExec)
+	require.NoError(t, err)
+	require.Equal(t, "on", seqScan)
+
+	// the hints of an action are kept when it is loaded from the database
+	interp2, err := interpreter.NewInterpreter(ctx, tx, &common.Service{}, nil, nil, nil)
+	require.NoError(t, err)
+	err = interp2.SavePlanBaseline(ctx, tx, "main", "action_hinted_by_name", []any{"alice"}, "reloaded")
+	require.NoError(t, err)
+	var nodes string
+	err = pg.QueryRowFunc(ctx, tx, `SELECT nodes FROM kwild_engine.plan_baselines WHERE id = 'reloaded'`, []any{&nodes},
+		func() error { return nil }, pg.QueryModeExec)
+	require.NoError(t, err)
+	require.Contains(t, nodes, "using unhinted_name_idx")
+}
+
+func Test_DistinctResults(t *testing.T) {
+	db := newTestDB(t, nil, nil)
+
+	ctx := context.Background()
+	tx, err := db.BeginTx(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback(ctx) // always rollback
+
+	interp := newTestInterp(t, tx, []string{
+		`CREATE TABLE events (id INT PRIMARY KEY, source TEXT NOT NULL, name TEXT NOT NULL);`,
+		`INSERT INTO events (id, source, name) VALUES (1, 'a', 'bob'), (2, 'b', 'alice'), (3, 'a', 'bob'), (4, 'c', 'alice'), (5, 'a', 'carol');`,
+		`CREATE ACTION names() public view returns table(name text, source text) {
+			return SELECT name, source FROM events ORDER BY id;
+		};`,
+		`-- @distinct
+		CREATE ACTION distinct_names() public view returns table(name text, source text) {
+			return SELECT name, source FROM events ORDER BY id;
+		};`,
+		`-- @distinct_on(name)
+		CREATE ACTION distinct_on_names() public view returns table(name text, source text) {
+			return SELECT name, source FROM events ORDER BY id;
+		};`,
+	}, false)
+
+	results := func(action string) [][]any {
+		var rows [][]any
+		_, err := interp.Call(newEngineCtx(defaultCaller), tx, "", action, nil, func(r *common.Row) error {
+			rows = append(rows, r.Values)
+			return nil
+		})
+		require.NoError(t, err)
+		return rows
+	}
+
+	require.Equal(t, [][]any{{"bob", "a"}, {"alice", "b"}, {"bob", "a"}, {"alice", "c"}, {"carol", "a"}}, results("names"))
+	// the first of each duplicate row is kept, in the order of the query
+	require.Equal(t, [][]any{{"bob", "a"}, {"alice", "b"}, {"alice", "c"}, {"carol", "a"}}, results("distinct_names"))
+	require.Equal(t, [][]any{{"bob", "a"}, {"alice", "b"}, {"carol", "a"}}, results("distinct_on_names"))
+}
+
+func Test_TenantRouting(t *testing.T) {
+	db := newTestDB(t, nil, nil)
+
+	ctx := context.Background()
+	tx, err := db.BeginTx(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback(ctx) // always rollback
+
+	var seeds []string
+	for _, ns := range []string{"app", "t_acme_app", "t_globex_app"} {
+		seeds = append(seeds,
+			fmt.Sprintf(`CREATE NAMESPACE %s;`, ns),
+			fmt.Sprintf(`{%s}CREATE TABLE items (id INT PRIMARY KEY, name TEXT NOT NULL);`, ns),
+			fmt.Sprintf(`{%s}INSERT INTO items (id, name) VALUES (1, '%s');`, ns, ns),
+			fmt.Sprintf(`{%s}CREATE ACTION add_item($id int, $name text) public { INSERT INTO items (id, name) VALUES ($id, $name); };`, ns),
+			fmt.Sprintf(`{%s}CREATE ACTION list_items() public view returns table(name text) { return SELECT name FROM items ORDER BY id; };`, ns),
+		)
+	}
+
+	interp := newTestInterp(t, tx, seeds, false, interpreter.WithTenantConfig(&interpreter.TenantConfig{
+		TenantIDHeader:        "X-Tenant-ID",
+		TenantNamespacePrefix: "t_",
+	}))
+
+	tenantCtx := func(tenant string) *common.EngineContext {
+		engCtx := newEngineCtx(defaultCaller)
+		if tenant != "" {
+			engCtx.Metadata = map[string]string{"x-tenant-id": tenant}
+		}
+		return engCtx
+	}
+
+	listItems := func(tenant string) []string {
+		var names []string
+		_, err := interp.Call(tenantCtx(tenant), tx, "app", "list_items", nil, func(r *common.Row) error {
+			names = append(names, r.Values[0].(string))
+			return nil
+		})
+		require.NoError(t, err)
+		return names
+	}
+
+	_, err = interp.Call(tenantCtx("acme"), tx, "app", "add_item", []any{2, "acme item"}, nil)
+	require.NoError(t, err)
+	_, err = interp.Call(tenantCtx("globex"), tx, "app", "add_item", []any{2, "globex item"}, nil)
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"t_acme_app", "acme item"}, listItems("acme"))
+	require.Equal(t, []string{"t_globex_app", "globex item"}, listItems("GLOBEX"))
+
+	// calls without a tenant, by tenants without a namespace, or with invalid
+	// tenant IDs use the base namespace
+	require.Equal(t, []string{"app"}, listItems(""))
+	require.Equal(t, []string{"app"}, listItems("initech"))
+	require.Equal(t, []string{"app"}, listItems("acme_app"))
+}
+
+// errPoolExhausted is returned by exhaustedPoolDB.
+var errPoolExhausted = errors.New("connection pool exhausted")
+
+// exhaustedPoolDB is a read-only database whose pool has no free connections.
+type exhaustedPoolDB struct{}
+
+func (exhaustedPoolDB) Execute(context.Context, string, ...any) (*sql.ResultSet, error) {
+	return nil, errPoolExhausted
+}
+
+func (exhaustedPoolDB) BeginTx(context.Context) (sql.Tx, error) {
+	return nil, errPoolExhausted
+}
+
+func (exhaustedPoolDB) AccessMode() sql.AccessMode {
+	return sql.ReadOnly
+}
+
+func Test_AdminWorkload(t *testing.T) {
+	db := newTestDB(t, nil, nil)
+
+	ctx := context.Background()
+	tx, err := db.BeginTx(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback(ctx) // always rollback
+
+	interp, err := interpreter.NewInterpreter(ctx, tx, &common.Service{AdminDB: tx}, nil, nil, nil)
+	require.NoError(t, err)
+
+	for _, stmt := range []string{
+		`CREATE TABLE items (id INT PRIMARY KEY, name TEXT NOT NULL);`,
+		`INSERT INTO items (id, name) VALUES (1, 'a'), (2, 'b');`,
+		`CREATE ACTION list_items() public view returns table(name text) { return SELECT name FROM items ORDER BY id; };`,
+	} {
+		err = interp.ExecuteWithoutEngineCtx(ctx, tx, stmt, nil, nil)
+		require.NoError(t, err)
+	}
+
+	listItems := func(class common.WorkloadClass) ([]string, error) {
+		engCtx := newEngineCtx(defaultCaller)
+		engCtx.WorkloadClass = class
+		var names []string
+		res, err := interp.Call(engCtx, exhaustedPoolDB{}, "main", "list_items", nil, func(r *common.Row) error {
+			names = append(names, r.Values[0].(string))
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		return names, res.Error
+	}
+
+	// user calls wait for connections of the exhausted pool
+	_, err = listItems(common.UserWorkload)
+	require.ErrorContains(t, err, errPoolExhausted.Error())
+
+	// admin calls use the admin pool
+	names, err := listItems(common.AdminWorkload)
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b"}, names)
+}
+
+func Test_QueryAdvisor(t *testing.T) {
+	db := newTestDB(t, nil, nil)
+
+	ctx := context.Background()
+	tx, err := db.BeginTx(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback(ctx) // always rollback
+
+	newInterp := func(logs *bytes.Buffer, opts ...interpreter.InterpreterOpt) *interpreter.ThreadSafeInterpreter {
+		interp, err := interpreter.NewInterpreter(ctx, tx, &common.Service{Logger: log.New(log.WithWriter(logs))}, nil, nil, nil, opts...)
+		require.NoError(t, err)
+		return interp
+	}
+
+	var logs bytes.Buffer
+	interp := newInterp(&logs)
+	for _, stmt := range []string{
+		`CREATE TABLE users (id INT PRIMARY KEY, email TEXT NOT NULL);`,
+		`INSERT INTO users (id, email) VALUES (1, 'a@kwil.com'), (2, 'b@kwil.com');`,
+		`CREATE ACTION get_user($email text) public view returns (id int) { for $row in SELECT id FROM users WHERE email = $email { return $row.id; } };`,
+	} {
+		err = interp.ExecuteWithoutEngineCtx(ctx, tx, stmt, nil, nil)
+		require.NoError(t, err)
+	}
+
+	getUser := func(interp *interpreter.ThreadSafeInterpreter) {
+		_, err := interp.Call(newEngineCtx(defaultCaller), tx, "main", "get_user", []any{"b@kwil.com"}, nil)
+		require.NoError(t, err)
+	}
+
+	getUser(interp)
+	require.Contains(t, logs.String(), "Consider adding index on users.email (sequential scan detected)")
+
+	// each suggestion is logged once
+	getUser(interp)
+	require.Equal(t, 1, strings.Count(logs.String(), "Consider adding index on users.email"))
+
+	var disabledLogs bytes.Buffer
+	getUser(newInterp(&disabledLogs, interpreter.WithoutQueryAdvisor()))
+	require.NotContains(t, disabledLogs.String(), "Consider adding")
+}
+
+func Test_ErrorVerbosity(t *testing.T) {
+	db := newTestDB(t, nil, nil)
+
+	ctx := context.Background()
+	tx, err := db.BeginTx(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback(ctx) // always rollback
+
+	interp := newTestInterp(t, tx, []string{
+		`CREATE NAMESPACE app;`,
+		`{app}CREATE ACTION decode_hex($s text) public view returns (b bytea) { return decode($s, 'hex'); };`,
+	}, false)
+
+	callErr := func(interp *interpreter.ThreadSafeInterpreter) error {
+		res, err := interp.Call(newEngineCtx(defaultCaller), tx, "app", "decode_hex", []any{"zz"}, nil)
+		require.NoError(t, err)
+		require.Error(t, res.Error)
+		return res.Error
+	}
+
+	// by default, the Postgres error is returned
+	require.ErrorContains(t, callErr(interp), "invalid hexadecimal digit")
+
+	err = interp.SetErrorVerbosity(ctx, tx, "app", interpreter.ErrorVerbosityDetailed)
+	require.NoError(t, err)
+	require.ErrorContains(t, callErr(interp), "failed to execute SELECT decode(")
+
+	require.Error(t, interp.SetErrorVerbosity(ctx, tx, "app", "verbose"))
+	require.ErrorIs(t, interp.SetErrorVerbosity(ctx, tx, "unknown", interpreter.ErrorVerbosityMinimal), engine.ErrNamespaceNotFound)
+
+	err = interp.SetErrorVerbosity(ctx, tx, "app", interpreter.ErrorVerbosityMinimal)
+	require.NoError(t, err)
+	minimalErr := callErr(interp)
+	require.Regexp(t, `^internal error \(error ID: [0-9a-f]{16}\)$`, minimalErr.Error())
+	require.NotContains(t, minimalErr.Error(), "hexadecimal")
+	require.NotContains(t, minimalErr.Error(), "SQLSTATE")
+
+	// the verbosity is loaded when the interpreter is created
+	interp2, err := interpreter.NewInterpreter(ctx, tx, &common.Service{}, nil, nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, minimalErr.Error(), callErr(interp2).Error())
+}
+
+func Test_NamespaceReadOnly(t *testing.T) {
+	db := newTestDB(t, nil, nil)
+
+	ctx := context.Background()
+	tx, err := db.BeginTx(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback(ctx) // always rollback
+
+	interp := newTestInterp(t, tx, []string{
+		`CREATE NAMESPACE app;`,
+		`{app}CREATE TABLE items (id int primary key);`,
+		`{app}CREATE ACTION add_item($id int) public { INSERT INTO items (id) VALUES ($id); };`,
+		`{app}CREATE ACTION count_items() public view returns (n int) { for $row in SELECT count(*) as n FROM items { return $row.n; } };`,
+	}, false)
+
+	addItem := func(interp *interpreter.ThreadSafeInterpreter, id int64) error {
+		_, err := interp.Call(newEngineCtx(defaultCaller), tx, "app", "add_item", []any{id}, nil)
+		return err
+	}
+	countItems := func(interp *interpreter.ThreadSafeInterpreter) int64 {
+		var n int64
+		_, err := interp.Call(newEngineCtx(defaultCaller), tx, "app", "count_items", nil, func(r *common.Row) error {
+			n = r.Values[0].(int64)
+			return nil
+		})
+		require.NoError(t, err)
+		return n
+	}
+
+	require.NoError(t, addItem(interp, 1))
+
+	err = interp.SetNamespaceReadOnly(ctx, tx, "app", true)
+	require.NoError(t, err)
+	require.ErrorIs(t, addItem(interp, 2), engine.ErrNamespaceReadOnly)
+	require.Equal(t, int64(1), countItems(interp))
+
+	require.ErrorIs(t, interp.SetNamespaceReadOnly(ctx, tx, "unknown", true), engine.ErrNamespaceNotFound)
+
+	// the read-only state is loaded when the interpreter is created
+	interp2, err := interpreter.NewInterpreter(ctx, tx, &common.Service{}, nil, nil, nil)
+	require.NoError(t, err)
+	require.ErrorIs(t, addItem(interp2, 2), engine.ErrNamespaceReadOnly)
+	require.Equal(t, int64(1), countItems(interp2))
+
+	// it can also be changed with ALTER NAMESPACE
+	err = interp.Execute(newEngineCtx(defaultCaller), tx, `ALTER NAMESPACE app SET READ WRITE;`, nil, nil)
+	require.NoError(t, err)
+	require.NoError(t, addItem(interp, 2))
+	require.Equal(t, int64(2), countItems(interp))
+
+	err = interp.Execute(newEngineCtx(defaultCaller), tx, `ALTER NAMESPACE app SET READ ONLY;`, nil, nil)
+	require.NoError(t, err)
+	require.ErrorIs(t, addItem(interp, 3), engine.ErrNamespaceReadOnly)
+}
+
+func Test_BackupAndRestoreNamespace(t *testing.T) {
+	db := newTestDB(t, nil, nil)
+
+	ctx := context.Background()
+	tx, err := db.BeginTx(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback(ctx) // always rollback
+
+	interp := newTestInterp(t, tx, []string{
+		`CREATE NAMESPACE app;`,
+		`{app}CREATE TABLE users (id int primary key, name text not null unique, score int default 0 check (score >= 0), avatar bytea);`,
+		`{app}CREATE INDEX users_score_idx ON users (score);`,
+		`{app}-- @soft_delete
+		CREATE TABLE posts (id int primary key, author int references users(id) on delete cascade, tags text[], price numeric(10,2));`,
+		`{app}CREATE ACTION count_posts() public view returns (n int) { for $row in SELECT count(*) as n FROM posts { return $row.n; } };`,
+		`{app}INSERT INTO users (id, name, score, avatar) VALUES (1, 'alice', 10, decode('0102', 'hex')), (2, 'bob', 0, null);`,
+		`{app}INSERT INTO posts (id, author, tags, price) VALUES (1, 1, ARRAY['a', 'b'], 1.50), (2, 2, null, null), (3, 1, ARRAY[]::text[], 0.99);`,
+		`{app}DELETE FROM posts WHERE id = 3;`,
+	}, false)
+
+	// schema describes the tables, columns, indexes and constraints of the namespace
+	schema := func() string {
+		var desc []string
+		err := interp.Execute(newEngineCtx(defaultCaller), tx, `SELECT name, data_type, is_nullable, default_value FROM info.columns WHERE namespace = 'app' ORDER BY table_name, name;`, nil, func(r *common.Row) error {
+			desc = append(desc, fmt.Sprint(r.Values...))
+			return nil
+		})
+		require.NoError(t, err)
+		err = interp.Execute(newEngineCtx(defaultCaller), tx, `SELECT name, table_name, columns, is_unique FROM info.indexes WHERE namespace = 'app' ORDER BY name;`, nil, func(r *common.Row) error {
+			desc = append(desc, fmt.Sprint(r.Values...))
+			return nil
+		})
+		require.NoError(t, err)
+		err = interp.Execute(newEngineCtx(defaultCaller), tx, `SELECT name, table_name, expression FROM info.constraints WHERE namespace = 'app' ORDER BY name;`, nil, func(r *common.Row) error {
+			desc = append(desc, fmt.Sprint(r.Values...))
+			return nil
+		})
+		require.NoError(t, err)
+		return strings.Join(desc, "\n")
+	}
+	// rows returns the rows of the tables, including soft deleted rows
+	rows := func() string {
+		var res []string
+		for _, stmt := range []string{`SELECT row_to_json(u)::text FROM app.users u ORDER BY id`, `SELECT row_to_json(p)::text FROM app.posts p ORDER BY id`} {
+			r, err := tx.Execute(ctx, stmt, pg.QueryModeExec)
+			require.NoError(t, err)
+			for _, vals := range r.Rows {
+				res = append(res, vals[0].(string))
+			}
+		}
+		return strings.Join(res, "\n")
+	}
+
+	wantSchema, wantRows := schema(), rows()
+
+	var backup bytes.Buffer
+	require.NoError(t, interp.BackupNamespace(ctx, tx, "app", &backup))
+
+	err = interp.Execute(newEngineCtx(defaultCaller), tx, `DROP NAMESPACE app;`, nil, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, interp.RestoreNamespace(ctx, tx, bytes.NewReader(backup.Bytes())))
+	require.Equal(t, wantSchema, schema())
+	require.Equal(t, wantRows, rows())
+
+	var n int64
+	_, err = interp.Call(newEngineCtx(defaultCaller), tx, "app", "count_posts", nil, func(r *common.Row) error {
+		n = r.Values[0].(int64)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), n)
+
+	// the foreign key was restored
+	err = interp.Execute(newEngineCtx(defaultCaller), tx, `{app}INSERT INTO posts (id, author) VALUES (4, 3);`, nil, nil)
+	require.Error(t, err)
+
+	// a namespace cannot be restored over itself
+	require.Error(t, interp.RestoreNamespace(ctx, tx, bytes.NewReader(backup.Bytes())))
+}
+
+func Test_BulkLoad(t *testing.T) {
+	db := newTestDB(t, nil, nil)
+
+	ctx := context.Background()
+	tx, err := db.BeginTx(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback(ctx) // always rollback
+
+	interp := newTestInterp(t, tx, []string{
+		`CREATE TABLE bulk (id int primary key, name text, price numeric(10,2));`,
+		`CREATE TABLE single (id int primary key, name text, price numeric(10,2));`,
+	}, false)
+
+	count := func(table string) int64 {
+		r, err := tx.Execute(ctx, `SELECT count(*) FROM main.`+table, pg.QueryModeExec)
+		require.NoError(t, err)
+		return r.Rows[0][0].(int64)
+	}
+
+	const bulkRows = 100_000
+	rows := make(chan []any, 1000)
+	go func() {
+		defer close(rows)
+		for i := range bulkRows {
+			rows <- []any{i, fmt.Sprintf("item %d", i), float64(i%1000) / 100}
+		}
+	}()
+
+	start := time.Now()
+	n, err := interp.BulkLoad(ctx, tx, "main", "bulk", rows)
+	require.NoError(t, err)
+	bulkPerRow := time.Since(start) / bulkRows
+	require.Equal(t, int64(bulkRows), n)
+	require.Equal(t, int64(bulkRows), count("bulk"))
+
+	// inserting rows one at a time is timed on fewer rows, since it is slow
+	const singleRows = 2_000
+	start = time.Now()
+	for i := range singleRows {
+		err = interp.Execute(newEngineCtx(defaultCaller), tx, `INSERT INTO single (id, name, price) VALUES ($id, $name, $price);`, map[string]any{
+			"id":    int64(i),
+			"name":  fmt.Sprintf("item %d", i),
+			"price": types.MustParseDecimalExplicit(fmt.Sprintf("%d.%02d", i%1000/100, i%100), 10, 2),
+		}, nil)
+		require.NoError(t, err)
+	}
+	singlePerRow := time.Since(start) / singleRows
+	require.Less(t, 10*bulkPerRow, singlePerRow, "bulk load took %s per row, single inserts took %s per row", bulkPerRow, singlePerRow)
+
+	// invalid rows fail the whole load
+	rows = make(chan []any, 2)
+	rows <- []any{bulkRows, "ok", nil}
+	rows <- []any{bulkRows + 1, 42, nil}
+	close(rows)
+	_, err = interp.BulkLoad(ctx, tx, "main", "bulk", rows)
+	require.ErrorIs(t, err, engine.ErrType)
+
+	_, err = interp.BulkLoad(ctx, tx, "main", "unknown", nil)
+	require.ErrorIs(t, err, engine.ErrUnknownTable)
+}
+
+func Test_RefundOnFailure(t *testing.T) {
+	db := newTestDB(t, nil, nil)
+
+	ctx := context.Background()
+	tx, err := db.BeginTx(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback(ctx) // always rollback
+
+	accts, err := accounts.InitializeAccountStore(ctx, tx, log.DiscardLogger)
+	require.NoError(t, err)
+
+	interp, err := interpreter.NewInterpreter(ctx, tx, &common.Service{}, accts, nil, nil)
+	require.NoError(t, err)
+	err = interp.ExecuteWithoutEngineCtx(ctx, tx, "TRANSFER OWNERSHIP TO $user", map[string]any{
+		"user": defaultCaller,
+	}, nil)
+	require.NoError(t, err)
+
+	for _, stmt := range []string{
+		`CREATE TABLE items (id INT PRIMARY KEY);`,
+		`CREATE ACTION add_item($id int) public { INSERT INTO items (id) VALUES ($id); }`,
+		`CREATE ACTION add_twice($id int) public {
+			INSERT INTO items (id) VALUES ($id);
+			INSERT INTO items (id) VALUES ($id);
+		}`,
+	} {
+		require.NoError(t, interp.Execute(newEngineCtx(defaultCaller), tx, stmt, nil, nil))
+	}
+
+	pk, _, err := crypto.GenerateSecp256k1Key(nil)
+	require.NoError(t, err)
+	acct := &types.AccountID{
+		Identifier: pk.Public().Bytes(),
+		KeyType:    crypto.KeyTypeSecp256k1,
+	}
+
+	// the account starts with 1000, and pays a fee of 10 units of gas at 100
+	// for each call
+	require.NoError(t, accts.Credit(ctx, tx, acct, big.NewInt(1000)))
+
+	balance := func() string {
+		r, err := tx.Execute(ctx, `SELECT balance::TEXT FROM kwild_accts.accounts WHERE identifier = $1`, pg.QueryModeExec, acct.Identifier)
+		require.NoError(t, err)
+		require.Len(t, r.Rows, 1)
+		return r.Rows[0][0].(string)
+	}
+
+	call := func(action string) error {
+		require.NoError(t, accts.Credit(ctx, tx, acct, big.NewInt(-1000)))
+
+		engineCtx := newEngineCtx(defaultCaller)
+		engineCtx.TxContext.Signer = acct.Identifier
+		engineCtx.TxContext.Authenticator = auth.Secp256k1Auth
+		engineCtx.RefundOnFailure = true
+		engineCtx.GasLimit = 10
+		engineCtx.GasPrice = big.NewInt(100)
+
+		res, err := interp.Call(engineCtx, tx, "main", action, []any{1}, nil)
+		if err != nil {
+			return err
+		}
+		return res.Error
+	}
+
+	// the failed call used 2 units of gas, so 8 are refunded
+	require.Error(t, call("add_twice"))
+	require.Equal(t, "800", balance())
+
+	// its changes are rolled back
+	r, err := tx.Execute(ctx, `SELECT count(*) FROM main.items`, pg.QueryModeExec)
+	require.NoError(t, err)
+	require.Equal(t, int64(0), r.Rows[0][0])
+
+	// successful calls are not refunded
+	require.NoError(t, call("add_item"))
+	require.Equal(t, "800", balance())
+	require.NoError(t, accts.Credit(ctx, tx, acct, big.NewInt(1000)))
+	require.Equal(t, "1800", balance())
+}
+
+func Test_SimulateCall(t *testing.T) {
+	db := newTestDB(t, nil, nil)
+
+	ctx := context.Background()
+	tx, err := db.BeginTx(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback(ctx) // always rollback
+
+	interp := newTestInterp(t, tx, []string{
+		`CREATE TABLE items (id INT PRIMARY KEY);`,
+		`CREATE ACTION add_item($id int) public returns (id int) {
+			notice('adding item');
+			INSERT INTO items (id) VALUES ($id);
+			RETURN $id;
+		}`,
+		`CREATE ACTION add_twice($id int) public {
+			notice('adding item twice');
+			INSERT INTO items (id) VALUES ($id);
+			INSERT INTO items (id) VALUES ($id);
+		}`,
+	}, false)
+
+	simulate := func(action string) *common.CallResult {
+		engineCtx := newEngineCtx(defaultCaller)
+		engineCtx.Simulate = true
+
+		res, err := interp.Call(engineCtx, tx, "main", action, []any{1}, nil)
+		require.NoError(t, err)
+		return res
+	}
+
+	count := func() int64 {
+		r, err := tx.Execute(ctx, `SELECT count(*) FROM main.items`, pg.QueryModeExec)
+		require.NoError(t, err)
+		return r.Rows[0][0].(int64)
+	}
+
+	res := simulate("add_item")
+	require.NoError(t, res.Error)
+	require.Equal(t, []string{"adding item"}, res.Logs)
+	require.Equal(t, uint64(1), res.GasUsed)
+	require.Equal(t, int64(0), count())
+
+	// a failed simulation reports its error with the logs up to the failure
+	res = simulate("add_twice")
+	require.Error(t, res.Error)
+	require.Equal(t, []string{"adding item twice"}, res.Logs)
+	require.Equal(t, uint64(2), res.GasUsed)
+	require.Equal(t, int64(0), count())
+
+	// the simulations did not keep the row, so it can still be inserted
+	engineCtx := newEngineCtx(defaultCaller)
+	_, err = interp.Call(engineCtx, tx, "main", "add_item", []any{1}, nil)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count())
+}
+
+func Test_RaiseError(t *testing.T) {
+	db := newTestDB(t, nil, nil)
+
+	ctx := context.Background()
+	tx, err := db.BeginTx(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback(ctx) // always rollback
+
+	interp := newTestInterp(t, tx, []string{
+		`-- @error_type(404, 'not found')
+		-- @error_type(409, 'conflict')
+		CREATE ACTION get_item($code int, $msg text) public {
+			raise_error($code, $msg);
+		}`,
+		`CREATE ACTION call_get_item() public {
+			get_item(409, null);
+		}`,
+	}, false)
+
+	call := func(action string, args ...any) error {
+		res, err := interp.Call(newEngineCtx(defaultCaller), tx, "main", action, args, nil)
+		if err != nil {
+			return err
+		}
+		return res.Error
+	}
+
+	appErr := new(engine.ApplicationError)
+	require.ErrorAs(t, call("get_item", 404, "not found"), &appErr)
+	require.Equal(t, &engine.ApplicationError{Code: 404, Message: "not found"}, appErr)
+
+	// errors raised by called actions are returned to the caller, with the
+	// declared message if none is given
+	require.ErrorAs(t, call("call_get_item"), &appErr)
+	require.Equal(t, &engine.ApplicationError{Code: 409, Message: "conflict"}, appErr)
+
+	// codes that are not declared cannot be raised
+	err = call("get_item", 500, "internal")
+	require.Error(t, err)
+	require.False(t, errors.As(err, &appErr))
+}
+
+func Test_NamespaceConfig(t *testing.T) {
+	db := newTestDB(t, nil, nil)
+
+	ctx := context.Background()
+	tx, err := db.BeginTx(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback(ctx) // always rollback
+
+	interp := newTestInterp(t, tx, []string{
+		`CREATE NAMESPACE other;`,
+		`CREATE ACTION set_fee($fee text) public { config_set('fee_rate', $fee); };`,
+		`CREATE ACTION get_fee() public view returns (fee text) { return config_get('fee_rate'); };`,
+		`{other}CREATE ACTION get_fee() public view returns (fee text) { return config_get('fee_rate'); };`,
+	}, false)
+
+	getFee := func(namespace string) any {
+		var fee any
+		res, err := interp.Call(newEngineCtx(defaultCaller), tx, namespace, "get_fee", nil, func(r *common.Row) error {
+			fee = r.Values[0]
+			return nil
+		})
+		require.NoError(t, err)
+		require.NoError(t, res.Error)
+		return fee
+	}
+
+	require.Nil(t, getFee("main"))
+
+	res, err := interp.Call(newEngineCtx(defaultCaller), tx, "main", "set_fee", []any{"0.25"}, nil)
+	require.NoError(t, err)
+	require.NoError(t, res.Error)
+
+	// the value is only visible to the namespace that set it
+	require.Equal(t, "0.25", getFee("main"))
+	require.Nil(t, getFee("other"))
+
+	// it can also be set with ALTER NAMESPACE
+	err = interp.Execute(newEngineCtx(defaultCaller), tx, `ALTER NAMESPACE other SET CONFIG 'fee_rate' = '0.5';`, nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, "0.5", getFee("other"))
+	require.Equal(t, "0.25", getFee("main"))
+}
+
+func Test_Inbox(t *testing.T) {
+	db := newTestDB(t, nil, nil)
+
+	ctx := context.Background()
+	tx, err := db.BeginTx(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback(ctx) // always rollback
+
+	interp := newTestInterp(t, tx, []string{
+		`CREATE TABLE deliveries (
+			id INT PRIMARY KEY,
+			action TEXT NOT NULL,
+			amount INT NOT NULL
+		);`,
+		`CREATE ACTION record($amount int) public {
+			$id := 1;
+			for $row in SELECT count(*) AS n FROM deliveries {
+				$id := $row.n + 1;
+			}
+			INSERT INTO deliveries (id, action, amount) VALUES ($id, 'record', $amount);
+		};`,
+		`-- @idempotent
+		CREATE ACTION record_once($amount int) public {
+			$id := 1;
+			for $row in SELECT count(*) AS n FROM deliveries {
+				$id := $row.n + 1;
+			}
+			INSERT INTO deliveries (id, action, amount) VALUES ($id, 'record_once', $amount);
+		};`,
+	}, false)
+
+	countDeliveries := func(action string) int64 {
+		var count int64
+		err := interp.Execute(newEngineCtx(defaultCaller), tx, `SELECT count(*) FROM deliveries WHERE action = $action;`,
+			map[string]any{"$action": action}, func(r *common.Row) error {
+				count = r.Values[0].(int64)
+				return nil
+			})
+		require.NoError(t, err)
+		return count
+	}
+
+	// redeliver marks the messages of an inbox as processing, as if an earlier
+	// consumer called the action but failed before marking them as done
+	redeliver := func(action string) {
+		_, err := tx.Execute(ctx, `UPDATE kwild_engine.inbox_main_`+action+` SET status = 'processing'`, pg.QueryModeExec)
+		require.NoError(t, err)
+	}
+
+	require.ErrorIs(t, interp.RegisterInbox(ctx, tx, "main", "unknown"), engine.ErrUnknownAction)
+
+	for _, action := range []string{"record", "record_once"} {
+		require.NoError(t, interp.RegisterInbox(ctx, tx, "main", action))
+		// registering an inbox again does nothing
+		require.NoError(t, interp.RegisterInbox(ctx, tx, "main", action))
+
+		for i := range 3 {
+			id := types.NewUUIDV5([]byte(fmt.Sprintf("%s-%d", action, i)))
+			require.NoError(t, interp.SendToInbox(ctx, tx, "main", action, id, []any{int64(100 * (i + 1))}))
+			// a retried send is only stored once
+			require.NoError(t, interp.SendToInbox(ctx, tx, "main", action, id, []any{int64(100 * (i + 1))}))
+		}
+	}
+
+	// messages are delivered in batches
+	require.NoError(t, interp.ConsumeInbox(newEngineCtx(defaultCaller), tx, "main", "record", 2))
+	require.Equal(t, int64(2), countDeliveries("record"))
+	require.NoError(t, interp.ConsumeInbox(newEngineCtx(defaultCaller), tx, "main", "record", 2))
+	require.Equal(t, int64(3), countDeliveries("record"))
+	// done messages are not delivered again
+	require.NoError(t, interp.ConsumeInbox(newEngineCtx(defaultCaller), tx, "main", "record", 10))
+	require.Equal(t, int64(3), countDeliveries("record"))
+
+	// messages that were not marked as done are delivered at least once
+	redeliver("record")
+	require.NoError(t, interp.ConsumeInbox(newEngineCtx(defaultCaller), tx, "main", "record", 10))
+	require.Equal(t, int64(6), countDeliveries("record"))
+
+	// @idempotent actions process each message exactly once
+	require.NoError(t, interp.ConsumeInbox(newEngineCtx(defaultCaller), tx, "main", "record_once", 10))
+	require.Equal(t, int64(3), countDeliveries("record_once"))
+	redeliver("record_once")
+	require.NoError(t, interp.ConsumeInbox(newEngineCtx(defaultCaller), tx, "main", "record_once", 10))
+	require.Equal(t, int64(3), countDeliveries("record_once"))
+}
+
+func Test_BridgeServer(t *testing.T) {
+	ctx := context.Background()
+	pool := newTestPool(t)
+
+	// the http extension, which kwil_call_action uses, is not installed in
+	// every Postgres image
+	if _, err := pool.Execute(ctx, `CREATE EXTENSION IF NOT EXISTS http`); err != nil {
+		t.Skipf("the http extension is not available: %v", err)
+	}
+
+	// the setup must be committed to be visible to the bridge's transactions
+	setup, err := pool.BeginTx(ctx)
+	require.NoError(t, err)
+	newTestInterp(t, setup, []string{
+		`CREATE TABLE orders (id INT PRIMARY KEY, total INT NOT NULL);`,
+		`CREATE TABLE order_taxes (id INT PRIMARY KEY, tax INT NOT NULL);`,
+		`CREATE ACTION tax($total int) public view returns (tax int) { return $total / 10; };`,
+	}, false)
+	require.NoError(t, setup.Commit(ctx))
+
+	// the bridge connects to the database of the node's config
+	cfg := config.DefaultConfig()
+	cfg.DB.Host = "127.0.0.1"
+	cfg.DB.Port = "5432"
+	cfg.DB.User = "kwild"
+	cfg.DB.Pass = "kwild"
+	cfg.DB.DBName = "kwil_test_db"
+	startup, err := pool.BeginTx(ctx)
+	require.NoError(t, err)
+	interp, err := interpreter.NewInterpreter(ctx, startup, &common.Service{LocalConfig: cfg, Logger: log.DiscardLogger}, nil, nil, nil)
+	require.NoError(t, err)
+	require.NoError(t, startup.Commit(ctx))
+
+	require.NoError(t, interp.StartBridgeServer(0))
+	defer interp.StopBridgeServer()
+	require.Error(t, interp.StartBridgeServer(0))
+
+	_, err = pool.Execute(ctx, `CREATE FUNCTION main.record_tax() RETURNS TRIGGER LANGUAGE plpgsql AS $$
+	BEGIN
+		INSERT INTO main.order_taxes (id, tax)
+		VALUES (NEW.id, (kwild_engine.kwil_call_action('main', 'tax', json_build_array(NEW.total))->0->>'tax')::INT8);
+		RETURN NEW;
+	END;
+	$$;
+	CREATE TRIGGER record_tax AFTER INSERT ON main.orders FOR EACH ROW EXECUTE FUNCTION main.record_tax();`)
+	require.NoError(t, err)
+
+	tx, err := pool.BeginTx(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback(ctx)
+
+	// the insert fires the trigger, which calls the action through the bridge
+	err = interp.Execute(newEngineCtx(defaultCaller), tx, `INSERT INTO orders (id, total) VALUES (1, 250);`, nil, nil)
+	require.NoError(t, err)
+
+	var tax int64
+	err = interp.Execute(newEngineCtx(defaultCaller), tx, `SELECT tax FROM order_taxes WHERE id = 1;`, nil, func(r *common.Row) error {
+		tax = r.Values[0].(int64)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(25), tax)
+}
+
+func Test_CallStack(t *testing.T) {
+	db := newTestDB(t, nil, nil)
+
+	ctx := context.Background()
+	tx, err := db.BeginTx(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback(ctx) // always rollback
+
+	interp := newTestInterp(t, tx, []string{
+		`CREATE ACTION level3($n int) public { error('level 3 failed'); };`,
+		`CREATE ACTION level2($n int, $m int) public { level3($n + $m); };`,
+		`CREATE ACTION level1() public { level2(1, 2); };`,
+	}, false)
+
+	res, err := interp.Call(newEngineCtx(defaultCaller), tx, "", "level1", nil, nil)
+	require.NoError(t, err)
+	require.Error(t, res.Error)
+	require.Equal(t, `call stack:
+	0: main.level1 (0 args)
+	1: main.level2 (2 args)
+	2: main.level3 (1 arg)
+level 3 failed`, res.Error.Error())
+
+	// a call that is not nested does not have a call stack
+	res, err = interp.Call(newEngineCtx(defaultCaller), tx, "", "level3", []any{1}, nil)
+	require.NoError(t, err)
+	require.EqualError(t, res.Error, "level 3 failed")
+}
+
+func Test_ContextCancellation(t *testing.T) {
+	db := newTestDB(t, nil, nil)
+
+	ctx := context.Background()
+	tx, err := db.BeginTx(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback(ctx) // always rollback
+
+	interp := newTestInterp(t, tx, []string{
+		`CREATE TABLE events (id INT PRIMARY KEY);`,
+		// the loop does not query the database, so only the interpreter
+		// can notice that the call was canceled
+		`CREATE ACTION slow() public returns table(id int) {
+			for $i in 1..10000000 {
+				notice('tick');
+			}
+			INSERT INTO events (id) VALUES (1);
+			RETURN SELECT id FROM events;
+		};`,
+	}, false)
+
+	callCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	engCtx := newEngineCtx(defaultCaller)
+	engCtx.TxContext.Ctx = callCtx
+
+	var rows int
+	_, err = interp.Call(engCtx, tx, "", "slow", nil, func(*common.Row) error {
+		rows++
+		return nil
+	})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Zero(t, rows)
+
+	// the insert after the loop was not executed
+	var count int64
+	err = interp.Execute(newEngineCtx(defaultCaller), tx, "SELECT count(*) FROM events;", nil, func(r *common.Row) error {
+		count = r.Values[0].(int64)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Zero(t, count)
+}
+
+func Test_Cursors(t *testing.T) {
+	db := newTestDB(t, nil, nil)
+
+	ctx := context.Background()
+	tx, err := db.BeginTx(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback(ctx) // always rollback
+
+	interp := newTestInterp(t, tx, []string{`CREATE TABLE entries (id INT PRIMARY KEY, amount INT NOT NULL);`,
+		`CREATE TABLE processed (id INT PRIMARY KEY);`,
+		`CREATE ACTION process_all() public returns (count int, total int) {
+			$count := 0;
+			$total := 0;
+			declare entries_cur cursor for select id, amount from entries order by id;
+			for $i in 1..10001 {
+				fetch next from entries_cur into $id, $amount;
+				if $id is null {
+					break;
+				}
+				// the row is processed before the next one is fetched
+				insert into processed (id) values ($id);
+				$count := $count + 1;
+				$total := $total + $amount;
+			}
+			close entries_cur;
+			return $count, $total;
+		};`,
+		`CREATE ACTION process_first() public returns (id int) {
+			declare entries_cur cursor for select id from entries order by id;
+			fetch next from entries_cur into $id;
+			close entries_cur;
+			return $id;
+		};`,
+		`CREATE ACTION leave_open() public {
+			declare entries_cur cursor for select id from entries order by id;
+			fetch next from entries_cur into $id;
+		};`,
+		`CREATE ACTION fetch_undeclared() public {
+			fetch next from entries_cur into $id;
+		};`,
+	}, false)
+
+	_, err = tx.Execute(ctx, `INSERT INTO main.entries SELECT g, g FROM generate_series(1, 10000) g`, pg.QueryModeExec)
+	require.NoError(t, err)
+
+	openCursors := func(t *testing.T) int64 {
+		var count int64
+		err := pg.QueryRowFunc(ctx, tx, `SELECT count(*) FROM pg_cursors`, []any{&count}, func() error { return nil }, pg.QueryModeExec)
+		require.NoError(t, err)
+		return count
+	}
+
+	call := func(t *testing.T, action string) []any {
+		var values []any
+		res, err := interp.Call(newEngineCtx(defaultCaller), tx, "", action, nil, func(r *common.Row) error {
+			values = r.Values
+			return nil
+		})
+		require.NoError(t, err)
+		require.NoError(t, res.Error)
+		return values
+	}
+
+	require.Equal(t, []any{int64(10000), int64(50005000)}, call(t, "process_all"))
+	require.Zero(t, openCursors(t))
+
+	var processed int64
+	err = pg.QueryRowFunc(ctx, tx, `SELECT count(*) FROM main.processed`, []any{&processed}, func() error { return nil }, pg.QueryModeExec)
+	require.NoError(t, err)
+	require.Equal(t, int64(10000), processed)
+
+	// closing a cursor before it is exhausted releases it
+	require.Equal(t, []any{int64(1)}, call(t, "process_first"))
+	require.Zero(t, openCursors(t))
+
+	// cursors that are not closed are released when the action returns
+	call(t, "leave_open")
+	require.Zero(t, openCursors(t))
+
+	res, err := interp.Call(newEngineCtx(defaultCaller), tx, "", "fetch_undeclared", nil, nil)
+	require.NoError(t, err)
+	require.ErrorContains(t, res.Error, `cursor "entries_cur" is not declared`)
+}
+
+func Test_LateralJoin(t *testing.T) {
+	db := newTestDB(t, nil, nil)
+
+	ctx := context.Background()
+	tx, err := db.BeginTx(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback(ctx) // always rollback
+
+	interp := newTestInterp(t, tx, []string{
+		`CREATE TABLE counts (id int primary key, n int not null);`,
+		`INSERT INTO counts (id, n) VALUES (1, 1), (2, 2), (3, 3), (4, 0);`,
+	}, false)
+
+	// each row of counts is joined with the n rows generated for it
+	rows := map[int64]int64{}
+	err = interp.Execute(newEngineCtx(defaultCaller), tx, `SELECT c.id, s.i FROM counts AS c
+	INNER JOIN LATERAL (SELECT g.generate_series AS i FROM generate_series(1, c.n, 1) AS g) AS s ON true;`, nil, func(r *common.Row) error {
+		rows[r.Values[0].(int64)]++
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[int64]int64{1: 1, 2: 2, 3: 3}, rows)
+
+	// a LEFT JOIN keeps the rows that generate nothing
+	rows = map[int64]int64{}
+	err = interp.Execute(newEngineCtx(defaultCaller), tx, `SELECT c.id, s.i FROM counts AS c
+	LEFT JOIN LATERAL (SELECT g.generate_series AS i FROM generate_series(1, c.n, 1) AS g) AS s ON true;`, nil, func(r *common.Row) error {
+		rows[r.Values[0].(int64)]++
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[int64]int64{1: 1, 2: 2, 3: 3, 4: 1}, rows)
+}
+
+func Test_MultiRowInsert(t *testing.T) {
+	db := newTestDB(t, nil, nil)
+
+	ctx := context.Background()
+	tx, err := db.BeginTx(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback(ctx) // always rollback
+
+	interp := newTestInterp(t, tx, []string{
+		`CREATE TABLE items (id int primary key, name text);`,
+		`CREATE TABLE copies (id int primary key, name text, copied bool);`,
+	}, false)
+
+	countRows := func(table string) int64 {
+		var n int64
+		err := interp.Execute(newEngineCtx(defaultCaller), tx, `SELECT count(*) FROM `+table, nil, func(r *common.Row) error {
+			n = r.Values[0].(int64)
+			return nil
+		})
+		require.NoError(t, err)
+		return n
+	}
+
+	// all rows are inserted with a single statement
+	rows := make([]string, 100)
+	params := make(map[string]any, 200)
+	for i := range rows {
+		rows[i] = fmt.Sprintf("($id%d, $name%d)", i, i)
+		params[fmt.Sprintf("$id%d", i)] = int64(i)
+		params[fmt.Sprintf("$name%d", i)] = fmt.Sprintf("item %d", i)
+	}
+	err = interp.Execute(newEngineCtx(defaultCaller), tx, `INSERT INTO items (id, name) VALUES `+strings.Join(rows, ", "), params, nil)
+	require.NoError(t, err)
+	require.Equal(t, int64(100), countRows("items"))
+
+	// the rows of a select can be inserted into a subset of the columns
+	err = interp.Execute(newEngineCtx(defaultCaller), tx, `INSERT INTO copies (id, name) SELECT id, name FROM items WHERE id < 10`, nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, int64(10), countRows("copies"))
+
+	// every row must have a value for each column
+	err = interp.Execute(newEngineCtx(defaultCaller), tx, `INSERT INTO items (id, name) VALUES (100, 'a'), (101)`, nil, nil)
+	require.ErrorIs(t, err, logical.ErrInsertValueCount)
+	require.ErrorContains(t, err, "row 2 has 1 values")
+
+	err = interp.Execute(newEngineCtx(defaultCaller), tx, `INSERT INTO copies SELECT id, name FROM items`, nil, nil)
+	require.ErrorIs(t, err, logical.ErrInsertValueCount)
+}
+
+func Test_TruncateTable(t *testing.T) {
+	db := newTestDB(t, nil, nil)
+
+	ctx := context.Background()
+	tx, err := db.BeginTx(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback(ctx) // always rollback
+
+	interp := newTestInterp(t, tx, []string{
+		`CREATE TABLE parents (id int primary key);`,
+		`CREATE TABLE children (id int primary key, parent_id int REFERENCES parents(id));`,
+		`CREATE TABLE grandchildren (id int primary key, child_id int REFERENCES children(id));`,
+		`INSERT INTO parents (id) VALUES (1), (2);`,
+		`INSERT INTO children (id, parent_id) VALUES (1, 1), (2, 2);`,
+		`INSERT INTO grandchildren (id, child_id) VALUES (1, 1);`,
+	}, false)
+
+	countRows := func(table string) int64 {
+		var n int64
+		err := interp.Execute(newEngineCtx(defaultCaller), tx, `SELECT count(*) FROM `+table, nil, func(r *common.Row) error {
+			n = r.Values[0].(int64)
+			return nil
+		})
+		require.NoError(t, err)
+		return n
+	}
+
+	// a table that is referenced by another table can only be truncated with CASCADE
+	err = interp.Execute(newEngineCtx(defaultCaller), tx, `TRUNCATE TABLE parents;`, nil, nil)
+	require.ErrorIs(t, err, engine.ErrForeignKeyViolation)
+	require.Equal(t, int64(2), countRows("parents"))
+
+	// a table that is not referenced can be truncated
+	err = interp.Execute(newEngineCtx(defaultCaller), tx, `TRUNCATE TABLE grandchildren;`, nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, int64(0), countRows("grandchildren"))
+	require.Equal(t, int64(2), countRows("children"))
+
+	err = interp.Execute(newEngineCtx(defaultCaller), tx, `INSERT INTO grandchildren (id, child_id) VALUES (1, 1);`, nil, nil)
+	require.NoError(t, err)
+
+	// CASCADE truncates every table that references the table
+	err = interp.Execute(newEngineCtx(defaultCaller), tx, `TRUNCATE TABLE parents CASCADE;`, nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, int64(0), countRows("parents"))
+	require.Equal(t, int64(0), countRows("children"))
+	require.Equal(t, int64(0), countRows("grandchildren"))
+
+	err = interp.Execute(newEngineCtx(defaultCaller), tx, `TRUNCATE TABLE unknown;`, nil, nil)
+	require.ErrorIs(t, err, engine.ErrUnknownTable)
+}
+
+func Test_ConditionalDDL(t *testing.T) {
+	db := newTestDB(t, nil, nil)
+
+	ctx := context.Background()
+	tx, err := db.BeginTx(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback(ctx) // always rollback
+
+	interp := newTestInterp(t, tx, nil, false)
+
+	exec := func(stmt string) error {
+		return interp.Execute(newEngineCtx(defaultCaller), tx, stmt, nil, nil)
+	}
+	countTables := func() int64 {
+		var n int64
+		err := interp.Execute(newEngineCtx(defaultCaller), tx, `SELECT count(*) FROM info.tables WHERE name = 'tbl' AND namespace = 'main';`, nil, func(r *common.Row) error {
+			n = r.Values[0].(int64)
+			return nil
+		})
+		require.NoError(t, err)
+		return n
+	}
+
+	// creating the table twice with IF NOT EXISTS creates it once
+	require.NoError(t, exec(`CREATE TABLE IF NOT EXISTS tbl (id INT PRIMARY KEY);`))
+	require.NoError(t, exec(`CREATE TABLE IF NOT EXISTS tbl (id INT PRIMARY KEY, name TEXT);`))
+	require.Equal(t, int64(1), countTables())
+	require.Error(t, exec(`CREATE TABLE tbl (id INT PRIMARY KEY);`))
+
+	// the table can be used as soon as it is created, and keeps its first definition
+	require.NoError(t, exec(`INSERT INTO tbl (id) VALUES (1);`))
+	require.Error(t, exec(`INSERT INTO tbl (id, name) VALUES (2, 'a');`))
+
+	// dropping the table twice with IF EXISTS drops it once
+	require.NoError(t, exec(`DROP TABLE IF EXISTS tbl;`))
+	require.NoError(t, exec(`DROP TABLE IF EXISTS tbl;`))
+	require.Equal(t, int64(0), countTables())
+	require.Error(t, exec(`DROP TABLE tbl;`))
+	require.ErrorIs(t, exec(`INSERT INTO tbl (id) VALUES (1);`), engine.ErrUnknownTable)
+}
+
+func Test_CreateIndexConcurrently(t *testing.T) {
+	ctx := context.Background()
+	pool := newTestPool(t)
+
+	// the index is built by its own connection, which needs the node's config
+	cfg := config.DefaultConfig()
+	cfg.DB.Host = "127.0.0.1"
+	cfg.DB.Port = "5432"
+	cfg.DB.User = "kwild"
+	cfg.DB.Pass = "kwild"
+	cfg.DB.DBName = "kwil_test_db"
+	service := &common.Service{LocalConfig: cfg, Logger: log.DiscardLogger}
+	setup, err := pool.BeginTx(ctx)
+	require.NoError(t, err)
+	interp, err := interpreter.NewInterpreter(ctx, setup, service, nil, nil, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, interp.StopIndexBuilds()) })
+	err = interp.ExecuteWithoutEngineCtx(ctx, setup, "TRANSFER OWNERSHIP TO $user", map[string]any{
+		"user": defaultCaller,
+	}, nil)
+	require.NoError(t, err)
+	for _, stmt := range []string{
+		createUsersTable,
+		`INSERT INTO users (id, name, age) VALUES (1, 'satoshi', 42), (2, 'hal', 50);`,
+	} {
+		require.NoError(t, interp.Execute(newEngineCtx(defaultCaller), setup, stmt, nil, nil))
+	}
+	require.NoError(t, setup.Commit(ctx))
+
+	exec := func(stmt string, commit bool) error {
+		tx, err := pool.BeginTx(ctx)
+		require.NoError(t, err)
+		err = interp.Execute(newEngineCtx(defaultCaller), tx, stmt, nil, nil)
+		if commit && err == nil {
+			return tx.Commit(ctx)
+		}
+		require.NoError(t, tx.Rollback(ctx))
+		return err
+	}
+	// listed reports whether info.indexes lists the index
+	listed := func(name string) bool {
+		res, err := pool.Execute(ctx, `SELECT count(*) FROM info.indexes WHERE namespace = 'main' AND name = $1`, pg.QueryModeExec, name)
+		require.NoError(t, err)
+		return res.Rows[0][0].(int64) == 1
+	}
+	phase := func(interp *interpreter.ThreadSafeInterpreter, name string) string {
+		status, err := interp.IndexBuildStatus(ctx, "main", "users", name)
+		require.NoError(t, err)
+		return status.Phase
+	}
+
+	// unique indexes change which writes succeed, so they cannot be built at
+	// a different time on each node
+	require.Error(t, exec(`CREATE UNIQUE INDEX CONCURRENTLY users_name ON users(name);`, true))
+	require.Error(t, exec(`CREATE INDEX CONCURRENTLY ON users(name);`, true))
+
+	// the build waits for the transaction, and is cancelled if it rolls back
+	require.NoError(t, exec(`CREATE INDEX CONCURRENTLY users_age ON users(age);`, false))
+	require.Eventually(t, func() bool {
+		return phase(interp, "users_age") == interpreter.IndexBuildCancelled
+	}, 10*time.Second, 50*time.Millisecond)
+	require.False(t, listed("users_age"))
+
+	// the index exists as soon as its transaction commits, whether or not it
+	// is built
+	require.NoError(t, exec(`CREATE INDEX CONCURRENTLY users_name ON users(name);`, true))
+	require.True(t, listed("users_name"))
+	require.NoError(t, exec(`CREATE INDEX CONCURRENTLY IF NOT EXISTS users_name ON users(name);`, true))
+	require.Error(t, exec(`CREATE INDEX CONCURRENTLY users_name ON users(name);`, true))
+	require.Error(t, exec(`CREATE INDEX users_name ON users(name);`, true))
+	require.Eventually(t, func() bool {
+		status, err := interp.IndexBuildStatus(ctx, "main", "users", "users_name")
+		require.NoError(t, err)
+		require.NoError(t, status.Err)
+		return status.Valid && status.Phase == interpreter.IndexBuildValid
+	}, 10*time.Second, 50*time.Millisecond)
+	require.True(t, listed("users_name"))
+
+	// an index can be dropped whether or not it is built
+	require.NoError(t, exec(`CREATE INDEX CONCURRENTLY users_id_age ON users(id, age);`, true))
+	require.NoError(t, exec(`DROP INDEX users_id_age;`, true))
+	require.False(t, listed("users_id_age"))
+	require.Eventually(t, func() bool {
+		return phase(interp, "users_id_age") == interpreter.IndexBuildCancelled
+	}, 10*time.Second, 50*time.Millisecond)
+
+	// builds that did not finish before the node stopped are resumed when it
+	// restarts
+	require.NoError(t, interp.StopIndexBuilds())
+	require.NoError(t, exec(`CREATE INDEX CONCURRENTLY users_age ON users(age);`, true))
+	require.True(t, listed("users_age"))
+
+	restart, err := pool.BeginTx(ctx)
+	require.NoError(t, err)
+	restarted, err := interpreter.NewInterpreter(ctx, restart, service, nil, nil, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, restarted.StopIndexBuilds()) })
+	require.NoError(t, restart.Commit(ctx))
+	require.Eventually(t, func() bool {
+		return phase(restarted, "users_age") == interpreter.IndexBuildValid
+	}, 10*time.Second, 50*time.Millisecond)
+
+	_, err = restarted.IndexBuildStatus(ctx, "main", "users", "users_missing")
+	require.Error(t, err)
+}
+
+func Test_DeployNamespaceConflict(t *testing.T) {
+	ctx := context.Background()
+	pool := newTestPool(t)
+	t.Cleanup(func() {
+		_, err := pool.Execute(ctx, `DROP SCHEMA IF EXISTS shop CASCADE`)
+		require.NoError(t, err)
+	})
+
+	setup, err := pool.BeginTx(ctx)
+	require.NoError(t, err)
+	interp := newTestInterp(t, setup, nil, false)
+	require.NoError(t, setup.Commit(ctx))
+
+	deploy := func(expected int64, stmts ...string) (int64, error) {
+		tx, err := pool.BeginTx(ctx)
+		require.NoError(t, err)
+
+		version, err := interp.DeployNamespace(newEngineCtx(defaultCaller), tx, "shop", stmts, &expected)
+		if err != nil {
+			require.NoError(t, tx.Rollback(ctx))
+			return 0, err
+		}
+		return version, tx.Commit(ctx)
+	}
+
+	// two clients race to deploy a new namespace
+	errs := make(chan error, 2)
+	for _, table := range []string{"items", "orders"} {
+		go func() {
+			_, err := deploy(0, fmt.Sprintf(`CREATE TABLE %s (id INT PRIMARY KEY);`, table))
+			errs <- err
+		}()
+	}
+
+	var succeeded int
+	for range 2 {
+		err := <-errs
+		if err == nil {
+			succeeded++
+			continue
+		}
+		require.ErrorIs(t, err, engine.ErrVersionConflict)
+	}
+	require.Equal(t, 1, succeeded)
+
+	// a deploy that expects the current version succeeds
+	version, err := deploy(1, `CREATE TABLE customers (id INT PRIMARY KEY);`)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), version)
+
+	_, err = deploy(1, `CREATE TABLE customers2 (id INT PRIMARY KEY);`)
+	require.ErrorIs(t, err, engine.ErrVersionConflict)
+}
+
+// This tests that calls made by extensions stream their rows, and that an
+// error returned for a row stops the call.
+func Test_RecursiveCallStreaming(t *testing.T) {
+	db := newTestDB(t, nil, nil)
+
+	ctx := context.Background()
+	tx, err := db.BeginTx(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback(ctx) // always rollback
+
+	errStop := errors.New("stop")
+	alias := "stream_ext"
+	var rows []int64
+	err = precompiles.RegisterPrecompile("stream", precompiles.Precompile{
+		OnUse: func(ctx *common.EngineContext, app *common.App) error {
+			ctx.OverrideAuthz = true
+			defer func() { ctx.OverrideAuthz = false }()
+			err := app.Engine.Execute(ctx, app.DB, "{"+alias+"}"+`CREATE ACTION numbers() public view returns table(n int) {
+				for $i in 1..5 {
+					notice($i::text);
+					RETURN NEXT $i;
+				}
+			}`, nil, nil)
+			if err != nil {
+				return err
+			}
+			return app.Engine.Execute(ctx, app.DB, "{"+alias+"}"+`CREATE ACTION fail_at_three() public view returns table(n int) {
+				for $i in 1..5 {
+					if $i = 3 {
+						error('failed at 3');
+					}
+					RETURN NEXT $i;
+				}
+			}`, nil, nil)
+		},
+		Methods: []precompiles.Method{
+			{
+				Name: "stop_at_two",
+				Handler: func(ctx *common.EngineContext, app *common.App, inputs []any, resultFn func([]any) error) error {
+					_, err := app.Engine.Call(ctx, app.DB, alias, "numbers", nil, func(r *common.Row) error {
+						rows = append(rows, r.Values[0].(int64))
+						if len(rows) == 2 {
+							return errStop
+						}
+						return nil
+					})
+					if !errors.Is(err, errStop) {
+						return fmt.Errorf("expected the result function's error, got %v", err)
+					}
+					if !strings.Contains(err.Error(), `action "numbers"`) {
+						return fmt.Errorf("expected the error to name the action, got %v", err)
+					}
+					return nil
+				},
+				AccessModifiers: []precompiles.Modifier{precompiles.PUBLIC, precompiles.VIEW},
+			},
+			{
+				Name: "fail_at_three",
+				Handler: func(ctx *common.EngineContext, app *common.App, inputs []any, resultFn func([]any) error) error {
+					res, err := app.Engine.Call(ctx, app.DB, alias, "fail_at_three", nil, func(r *common.Row) error {
+						rows = append(rows, r.Values[0].(int64))
+						return nil
+					})
+					if err != nil {
+						return err
+					}
+					if res.Error == nil {
+						return errors.New("expected the action to fail")
+					}
+					return nil
+				},
+				AccessModifiers: []precompiles.Modifier{precompiles.PUBLIC, precompiles.VIEW},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	interp := newTestInterp(t, tx, nil, true)
+
+	err = interp.Execute(newEngineCtx(defaultCaller), tx, `USE stream AS stream_ext;`, nil, nil)
+	require.NoError(t, err)
+
+	// the call stops at the second row, and its logs are kept
+	res, err := interp.Call(newEngineCtx(defaultCaller), tx, "stream_ext", "stop_at_two", nil, nil)
+	require.NoError(t, err)
+	require.NoError(t, res.Error)
+	require.Equal(t, []int64{1, 2}, rows)
+	require.Equal(t, []string{"1", "2"}, res.Logs)
+
+	// the rows produced before the action fails are received, so they were
+	// passed on as they were produced
+	rows = nil
+	res, err = interp.Call(newEngineCtx(defaultCaller), tx, "stream_ext", "fail_at_three", nil, nil)
+	require.NoError(t, err)
+	require.NoError(t, res.Error)
+	require.Equal(t, []int64{1, 2}, rows)
+}
+
+func Test_UUIDPrimaryKey(t *testing.T) {
+	db := newTestDB(t, nil, nil)
+
+	ctx := context.Background()
+	tx, err := db.BeginTx(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback(ctx) // always rollback
+
+	interp := newTestInterp(t, tx, []string{
+		`CREATE TABLE items (id uuid primary key, name text);`,
+	}, false)
+
+	// uuids are stored from arguments and from the uuid functions
+	id := mustUUID("f47ac10b-58cc-4372-a567-0e02b2c3d479")
+	err = interp.Execute(newEngineCtx(defaultCaller), tx, `INSERT INTO items (id, name) VALUES ($id, 'a'), (uuid_nil(), 'b'),
+		(uuid_parse('819ab751-e64c-5259-bbae-4d36f25bdd84'), 'c')`, map[string]any{"$id": id}, nil)
+	require.NoError(t, err)
+
+	// the primary key is unique
+	err = interp.Execute(newEngineCtx(defaultCaller), tx, `INSERT INTO items (id, name) VALUES ($id, 'd')`, map[string]any{"$id": id}, nil)
+	require.Error(t, err)
+
+	var ids []*types.UUID
+	var names []string
+	err = interp.Execute(newEngineCtx(defaultCaller), tx, `SELECT id, name FROM items ORDER BY name`, nil, func(r *common.Row) error {
+		ids = append(ids, r.Values[0].(*types.UUID))
+		names = append(names, r.Values[1].(string))
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b", "c"}, names)
+	require.Equal(t, []*types.UUID{id, mustUUID("00000000-0000-0000-0000-000000000000"), mustUUID("819ab751-e64c-5259-bbae-4d36f25bdd84")}, ids)
+
+	// a row can be selected by a uuid argument
+	var name string
+	err = interp.Execute(newEngineCtx(defaultCaller), tx, `SELECT name FROM items WHERE id = $id`, map[string]any{"$id": ids[2]}, func(r *common.Row) error {
+		name = r.Values[0].(string)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, "c", name)
+}
+
+func Test_TimeFunctions(t *testing.T) {
+	db := newTestDB(t, nil, nil)
+
+	ctx := context.Background()
+	tx, err := db.BeginTx(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback(ctx) // always rollback
+
+	interp := newTestInterp(t, tx, []string{
+		`CREATE TABLE events (id int primary key, created_at timestamp);`,
+		`CREATE ACTION day() public view returns (day timestamptz) {
+			return date_trunc('day', now());
+		};`,
+	}, false)
+
+	// 2024-03-11 01:30:15 UTC
+	blockTime := time.Date(2024, 3, 11, 1, 30, 15, 0, time.UTC)
+	engineCtx := func() *common.EngineContext {
+		c := newEngineCtx(defaultCaller)
+		c.TxContext.BlockContext.Timestamp = blockTime.Unix()
+		return c
+	}
+
+	err = interp.Execute(engineCtx(), tx, `INSERT INTO events (id, created_at) VALUES (1, now()),
+		(2, '2024-03-10T20:30:00-05:00'::timestamptz)`, nil, nil)
+	require.NoError(t, err)
+
+	// now() is the time of the block, and time zones are converted to UTC
+	var times []time.Time
+	err = interp.Execute(engineCtx(), tx, `SELECT created_at FROM events ORDER BY id`, nil, func(r *common.Row) error {
+		times = append(times, r.Values[0].(time.Time))
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []time.Time{blockTime, time.Date(2024, 3, 11, 1, 30, 0, 0, time.UTC)}, times)
+
+	var day time.Time
+	var text string
+	var hour *types.Decimal
+	err = interp.Execute(engineCtx(), tx, `SELECT date_trunc('day', now()), '2024-03-10T20:30:00-05:00'::timestamptz::text,
+		extract('hour', now())`, nil, func(r *common.Row) error {
+		day = r.Values[0].(time.Time)
+		text = r.Values[1].(string)
+		hour = r.Values[2].(*types.Decimal)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC), day)
+	require.Zero(t, day.Hour())
+	require.Zero(t, day.Minute())
+	require.Zero(t, day.Second())
+	require.Equal(t, "2024-03-11 01:30:00+00", text)
+	require.Equal(t, "1.000000", hour.String())
+
+	// now() in an action is also the time of the block
+	_, err = interp.Call(engineCtx(), tx, "", "day", nil, func(r *common.Row) error {
+		day = r.Values[0].(time.Time)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC), day)
+
+	// a default of now() would not be evaluated in a block
+	err = interp.Execute(engineCtx(), tx, `CREATE TABLE logs (id int primary key, at timestamptz default now())`, nil, nil)
+	require.ErrorIs(t, err, engine.ErrIllegalFunctionUsage)
+}
+
+func Test_InetAccessControl(t *testing.T) {
+	db := newTestDB(t, nil, nil)
+
+	ctx := context.Background()
+	tx, err := db.BeginTx(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback(ctx) // always rollback
+
+	interp := newTestInterp(t, tx, []string{
+		`CREATE TABLE allowed_networks (network cidr primary key);`,
+		`CREATE ACTION is_allowed($addr inet) public view returns (allowed bool) {
+			for $row in SELECT network FROM allowed_networks WHERE inet_contains(network, $addr) {
+				return true;
+			}
+			return false;
+		};`,
+	}, false)
+
+	err = interp.Execute(newEngineCtx(defaultCaller), tx, `INSERT INTO allowed_networks (network) VALUES ($net), ('2001:db8::/32')`,
+		map[string]any{"$net": netip.MustParsePrefix("192.168.0.0/16")}, nil)
+	require.NoError(t, err)
+
+	for addr, want := range map[string]bool{
+		"192.168.1.1": true,
+		"10.0.0.1":    false,
+		"2001:db8::1": true,
+	} {
+		var allowed bool
+		_, err = interp.Call(newEngineCtx(defaultCaller), tx, "", "is_allowed", []any{netip.MustParseAddr(addr)}, func(r *common.Row) error {
+			allowed = r.Values[0].(bool)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, want, allowed, addr)
+	}
+
+	var networks []netip.Prefix
+	err = interp.Execute(newEngineCtx(defaultCaller), tx, `SELECT network FROM allowed_networks ORDER BY network`, nil, func(r *common.Row) error {
+		networks = append(networks, r.Values[0].(netip.Prefix))
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []netip.Prefix{netip.MustParsePrefix("192.168.0.0/16"), netip.MustParsePrefix("2001:db8::/32")}, networks)
+}
+
+func Test_TableFunctionComposite(t *testing.T) {
+	engine.TableFunctions["test_users"] = &engine.TableFunctionDefinition{
+		ValidateArgsFunc: func(args []*types.DataType) (*types.DataType, error) {
+			return types.CompositeDataType([]types.FieldDef{
+				{Name: "id", Type: types.IntType},
+				{Name: "name", Type: types.TextType},
+			}), nil
+		},
+		PGFormatFunc: func(inputs []string) (string, error) {
+			return "SELECT * FROM (VALUES (1::INT8, 'satoshi'::TEXT), (2::INT8, 'vitalik'::TEXT)) AS t(id, name)", nil
+		},
+	}
+	defer delete(engine.TableFunctions, "test_users")
+
+	db := newTestDB(t, nil, nil)
+
+	ctx := context.Background()
+	tx, err := db.BeginTx(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback(ctx) // always rollback
+
+	interp := newTestInterp(t, tx, nil, false)
+
+	// the fields of the composite type are the columns of the rows
+	var ids []int64
+	var names []string
+	err = interp.Execute(newEngineCtx(defaultCaller), tx, `SELECT * FROM test_users() ORDER BY id`, nil, func(r *common.Row) error {
+		require.Equal(t, []string{"id", "name"}, r.ColumnNames)
+		require.Equal(t, []*types.DataType{types.IntType, types.TextType}, r.ColumnTypes)
+		ids = append(ids, r.Values[0].(int64))
+		names = append(names, r.Values[1].(string))
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []int64{1, 2}, ids)
+	require.Equal(t, []string{"satoshi", "vitalik"}, names)
+
+	// and they can be selected by name
+	err = interp.Execute(newEngineCtx(defaultCaller), tx, `SELECT u.name FROM test_users() AS u WHERE u.id = 2`, nil, func(r *common.Row) error {
+		require.Equal(t, []string{"name"}, r.ColumnNames)
+		require.Equal(t, "vitalik", r.Values[0])
+		return nil
+	})
+	require.NoError(t, err)
+}
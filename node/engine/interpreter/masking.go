@@ -0,0 +1,248 @@
+package interpreter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kwilteam/kwil-db/core/types"
+	"github.com/kwilteam/kwil-db/node/engine"
+	"github.com/kwilteam/kwil-db/node/engine/parse"
+)
+
+// sensitiveAccessVar is an internal variable that is true if the caller has the
+// SENSITIVE privilege on the current namespace. It is only referenced by the
+// masking expressions injected by restrictTableReads, which lets the rewritten
+// statement be cached and reused for callers with and without the privilege.
+const sensitiveAccessVar = "@__sensitive_access"
+
+// restrictTableReads rewrites the statement so that every read of a table with
+// sensitive columns or soft deletes reads through a subquery that masks those
+// columns and filters out soft deleted rows:
+//
+//	SELECT * FROM users u
+//
+// becomes
+//
+//	SELECT * FROM (SELECT id, CASE WHEN @__sensitive_access THEN email
+//		ELSE encode(digest(email, 'sha256'), 'hex') END AS email FROM users
+//		WHERE _deleted_at IS NULL) u
+//
+// Restricting the relation rather than the result columns ensures that
+// expressions, wildcards, and subqueries all see the restricted values.
+func (e *executionContext) restrictTableReads(ast *parse.SQLStatement) {
+	cteNames := make(map[string]struct{}, len(ast.CTEs))
+	for _, cte := range ast.CTEs {
+		cteNames[cte.Name] = struct{}{}
+	}
+
+	type relationRef struct {
+		rel *parse.RelationTable
+		set func(parse.Table)
+	}
+
+	// The relations are collected before any are replaced so that the
+	// generated subqueries are not visited. The order of the visit does not
+	// matter since each relation is rewritten independently.
+	var refs []relationRef
+	add := func(t parse.Table, set func(parse.Table)) {
+		if rel, ok := t.(*parse.RelationTable); ok {
+			refs = append(refs, relationRef{rel: rel, set: set})
+		}
+	}
+	parse.RecursivelyVisitPositions(ast, func(gp parse.GetPositioner) {
+		switch n := gp.(type) {
+		case *parse.SelectCore:
+			add(n.From, func(t parse.Table) { n.From = t })
+		case *parse.Join:
+			add(n.Relation, func(t parse.Table) { n.Relation = t })
+		case *parse.UpdateStatement:
+			add(n.From, func(t parse.Table) { n.From = t })
+		case *parse.DeleteStatement:
+			add(n.From, func(t parse.Table) { n.From = t })
+		}
+	})
+
+	for _, ref := range refs {
+		if _, ok := cteNames[ref.rel.Table]; ok && ref.rel.Namespace == "" {
+			continue
+		}
+
+		tbl, err := e.getTable(ref.rel.Namespace, ref.rel.Table)
+		if err != nil {
+			// unknown tables are reported by the planner
+			continue
+		}
+		if !hasReadRestrictions(tbl) {
+			continue
+		}
+
+		alias := ref.rel.Alias
+		if alias == "" {
+			alias = ref.rel.Table
+		}
+
+		ref.set(&parse.RelationSubquery{
+			Subquery: restrictedTableSelect(ref.rel.Namespace, tbl),
+			Alias:    alias,
+		})
+	}
+}
+
+// sensitiveTargetColumns returns the sensitive columns of the table updated or
+// deleted from by the statement that the statement reads, e.g. in its WHERE
+// clause or the values it sets. Unlike other reads, these cannot be masked by
+// restrictTableReads, since the target of an UPDATE or DELETE cannot be
+// replaced by a subquery, so callers without the SENSITIVE privilege are not
+// allowed to run the statement. It must be called before the statement is
+// rewritten by restrictTableReads.
+//
+// References are matched by name, so an unqualified column of a subquery that
+// has the name of a sensitive column of the target is also returned.
+func (e *executionContext) sensitiveTargetColumns(ast *parse.SQLStatement) []string {
+	var table, alias string
+	switch n := ast.SQL.(type) {
+	case *parse.UpdateStatement:
+		table, alias = n.Table, n.Alias
+	case *parse.DeleteStatement:
+		table, alias = n.Table, n.Alias
+	default:
+		return nil
+	}
+
+	tbl, err := e.getTable("", table)
+	if err != nil {
+		// unknown tables are reported by the planner
+		return nil
+	}
+
+	sensitive := make(map[string]struct{})
+	for _, col := range tbl.Columns {
+		if col.SensitivityPolicy != nil {
+			sensitive[strings.ToLower(col.Name)] = struct{}{}
+		}
+	}
+	if len(sensitive) == 0 {
+		return nil
+	}
+
+	if alias == "" {
+		alias = table
+	}
+
+	var cols []string
+	parse.RecursivelyVisitPositions(ast.SQL, func(gp parse.GetPositioner) {
+		col, ok := gp.(*parse.ExpressionColumn)
+		if !ok || (col.Table != "" && !strings.EqualFold(col.Table, alias)) {
+			return
+		}
+		if _, ok := sensitive[strings.ToLower(col.Column)]; ok {
+			cols = append(cols, col.Column)
+		}
+	})
+
+	return cols
+}
+
+// checkSensitiveTargetAccess returns an error if the statement reads sensitive
+// columns of the table it updates or deletes from, and the caller does not
+// have the SENSITIVE privilege.
+func (e *executionContext) checkSensitiveTargetAccess(cols []string) error {
+	if len(cols) == 0 {
+		return nil
+	}
+
+	if err := e.checkPrivilege(_SENSITIVE_PRIVILEGE); err != nil {
+		return fmt.Errorf(`%w: reading sensitive column "%s" of the table being updated or deleted from`, err, cols[0])
+	}
+	return nil
+}
+
+// hasReadRestrictions returns true if reads of the table must be rewritten.
+func hasReadRestrictions(tbl *engine.Table) bool {
+	if tbl.SoftDelete {
+		return true
+	}
+	for _, col := range tbl.Columns {
+		if col.SensitivityPolicy != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// restrictedTableSelect builds a SELECT of all of the table's columns, with each
+// sensitive column masked unless the caller has the SENSITIVE privilege. If the
+// table has soft deletes, soft deleted rows are excluded.
+func restrictedTableSelect(namespace string, tbl *engine.Table) *parse.SelectStatement {
+	core := &parse.SelectCore{
+		From: &parse.RelationTable{
+			Namespace: namespace,
+			Table:     tbl.Name,
+		},
+	}
+
+	for _, col := range tbl.Columns {
+		var expr parse.Expression = &parse.ExpressionColumn{Column: col.Name}
+		if col.SensitivityPolicy != nil {
+			expr = &parse.ExpressionCase{
+				WhenThen: [][2]parse.Expression{{
+					&parse.ExpressionVariable{Name: sensitiveAccessVar, Prefix: parse.VariablePrefixAt},
+					&parse.ExpressionColumn{Column: col.Name},
+				}},
+				Else: maskExpression(col),
+			}
+		}
+
+		core.Columns = append(core.Columns, &parse.ResultColumnExpression{
+			Expression: expr,
+			Alias:      col.Name,
+		})
+	}
+
+	if tbl.SoftDelete {
+		core.Where = &parse.ExpressionIs{
+			Left:  &parse.ExpressionColumn{Column: engine.SoftDeleteColumn},
+			Right: &parse.ExpressionLiteral{Type: types.NullType},
+		}
+	}
+
+	return &parse.SelectStatement{
+		SelectCores: []*parse.SelectCore{core},
+	}
+}
+
+// maskExpression returns the expression that masks the column's value.
+func maskExpression(col *engine.Column) parse.Expression {
+	column := &parse.ExpressionColumn{Column: col.Name}
+	text := func(s string) parse.Expression {
+		return &parse.ExpressionLiteral{Type: types.TextType, Value: s}
+	}
+
+	switch col.SensitivityPolicy.Mask {
+	case engine.MaskHash:
+		return &parse.ExpressionFunctionCall{
+			Name: "encode",
+			Args: []parse.Expression{
+				&parse.ExpressionFunctionCall{
+					Name: "digest",
+					Args: []parse.Expression{column, text("sha256")},
+				},
+				text("hex"),
+			},
+		}
+	case engine.MaskPartial:
+		return &parse.ExpressionFunctionCall{
+			Name: "substring",
+			Args: []parse.Expression{
+				column,
+				&parse.ExpressionLiteral{Type: types.IntType, Value: int64(1)},
+				&parse.ExpressionLiteral{Type: types.IntType, Value: col.SensitivityPolicy.PartialLength},
+			},
+		}
+	default: // engine.MaskRedact, and any unknown policy fails closed
+		return &parse.ExpressionLiteral{
+			Typecastable: parse.Typecastable{TypeCast: col.DataType},
+			Type:         types.NullType,
+		}
+	}
+}
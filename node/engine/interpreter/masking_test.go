@@ -0,0 +1,116 @@
+package interpreter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kwilteam/kwil-db/core/types"
+	"github.com/kwilteam/kwil-db/node/engine"
+	"github.com/kwilteam/kwil-db/node/engine/parse"
+	pggenerate "github.com/kwilteam/kwil-db/node/engine/pg_generate"
+	"github.com/kwilteam/kwil-db/node/engine/planner/logical"
+)
+
+func Test_MaskedTableSelect(t *testing.T) {
+	tbl := &engine.Table{
+		Name: "users",
+		Columns: []*engine.Column{
+			{Name: "id", DataType: types.IntType, IsPrimaryKey: true},
+			{Name: "email", DataType: types.TextType, Nullable: true,
+				SensitivityPolicy: &engine.SensitivityPolicy{Mask: engine.MaskHash}},
+			{Name: "name", DataType: types.TextType, Nullable: true,
+				SensitivityPolicy: &engine.SensitivityPolicy{Mask: engine.MaskPartial, PartialLength: 3}},
+			{Name: "age", DataType: types.IntType, Nullable: true,
+				SensitivityPolicy: &engine.SensitivityPolicy{Mask: engine.MaskRedact}},
+		},
+	}
+
+	getVar := func(name string) (*types.DataType, error) {
+		if name == sensitiveAccessVar {
+			return types.BoolType, nil
+		}
+		return nil, engine.ErrUnknownVariable
+	}
+
+	ast, err := getAST("SELECT u.email, name, age FROM users u WHERE id = 1")
+	require.NoError(t, err)
+	core := ast.SQL.(*parse.SelectStatement).SelectCores[0]
+	core.From = &parse.RelationSubquery{
+		Subquery: restrictedTableSelect("", tbl),
+		Alias:    "u",
+	}
+
+	plan, err := logical.CreateLogicalPlan(ast,
+		func(namespace, tableName string) (*engine.Table, error) {
+			if tableName != tbl.Name {
+				return nil, engine.ErrUnknownTable
+			}
+			return tbl, nil
+		}, getVar,
+		func(string) (map[string]*types.DataType, error) { return nil, engine.ErrUnknownVariable },
+		func(string) bool { return false },
+		true, "main")
+	require.NoError(t, err)
+
+	// masking must not change the types of the columns
+	fields := plan.Plan.Relation().Fields
+	require.Len(t, fields, 3)
+	for i, want := range []*types.DataType{types.TextType, types.TextType, types.IntType} {
+		dt, err := fields[i].Scalar()
+		require.NoError(t, err)
+		require.True(t, want.EqualsStrict(dt), "column %d: want %s, got %s", i, want, dt)
+	}
+
+	stmt, params, err := pggenerate.GenerateSQL(ast, "main", getVar)
+	require.NoError(t, err)
+	require.Equal(t, []string{sensitiveAccessVar}, params)
+
+	stmt = strings.Join(strings.Fields(stmt), " ")
+	require.Contains(t, stmt, "CASE WHEN $1::BOOL THEN email ELSE encode(digest(email, 'sha256'), 'hex') END AS email")
+	require.Contains(t, stmt, "CASE WHEN $1::BOOL THEN name ELSE substring(name from 1::INT4 for 3::INT4) END AS name")
+	require.Contains(t, stmt, "CASE WHEN $1::BOOL THEN age ELSE NULL::INT8 END AS age")
+}
+
+func Test_SoftDeleteTableSelect(t *testing.T) {
+	tbl := &engine.Table{
+		Name: "posts",
+		Columns: []*engine.Column{
+			{Name: "id", DataType: types.IntType, IsPrimaryKey: true},
+			{Name: "body", DataType: types.TextType, Nullable: true},
+		},
+		SoftDelete: true,
+	}
+
+	noVars := func(string) (*types.DataType, error) { return nil, engine.ErrUnknownVariable }
+
+	ast, err := getAST("SELECT * FROM posts")
+	require.NoError(t, err)
+	ast.SQL.(*parse.SelectStatement).SelectCores[0].From = &parse.RelationSubquery{
+		Subquery: restrictedTableSelect("", tbl),
+		Alias:    "posts",
+	}
+
+	plan, err := logical.CreateLogicalPlan(ast,
+		func(namespace, tableName string) (*engine.Table, error) {
+			if tableName != tbl.Name {
+				return nil, engine.ErrUnknownTable
+			}
+			return tbl, nil
+		}, noVars,
+		func(string) (map[string]*types.DataType, error) { return nil, engine.ErrUnknownVariable },
+		func(string) bool { return false },
+		true, "main")
+	require.NoError(t, err)
+
+	// the soft delete column must not be visible outside of the filter
+	fields := plan.Plan.Relation().Fields
+	require.Len(t, fields, 2)
+	require.Equal(t, "id", fields[0].Name)
+	require.Equal(t, "body", fields[1].Name)
+
+	stmt, _, err := pggenerate.GenerateSQL(ast, "main", noVars)
+	require.NoError(t, err)
+	require.Contains(t, strings.Join(strings.Fields(stmt), " "), "WHERE _deleted_at IS NULL")
+}
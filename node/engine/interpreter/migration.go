@@ -0,0 +1,169 @@
+package interpreter
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/kwilteam/kwil-db/common"
+	"github.com/kwilteam/kwil-db/node/types/sql"
+)
+
+// SchemaMigration is an ordered set of DDL statements that are applied
+// together by ApplySchemaMigration.
+type SchemaMigration struct {
+	// Statements are the statements of the migration, in order. Statements
+	// that cannot be run in a transaction, such as CREATE INDEX CONCURRENTLY,
+	// are run directly in Postgres, and must qualify tables with their
+	// namespace. All other statements are run by the engine.
+	Statements []string
+	// RollbackScript undoes the statements that cannot be run in a
+	// transaction. It has one statement for each of them, in the same order,
+	// and is run in reverse if a later statement fails.
+	RollbackScript []string
+}
+
+// MigrationRollbackError is returned by ApplySchemaMigration if a statement
+// fails.
+type MigrationRollbackError struct {
+	// Step is the index of the statement that failed.
+	Step int
+	// Statement is the statement that failed.
+	Statement string
+	// Err is the error of the failed statement.
+	Err error
+	// RolledBack is true if the migration was completely rolled back.
+	RolledBack bool
+	// RollbackErr is the error that stopped the migration from being rolled
+	// back, if any.
+	RollbackErr error
+}
+
+func (e *MigrationRollbackError) Error() string {
+	status := "rolled back"
+	if !e.RolledBack {
+		status = "not rolled back"
+		if e.RollbackErr != nil {
+			status += ": " + e.RollbackErr.Error()
+		}
+	}
+	return fmt.Sprintf("migration failed at step %d (%s): %v; migration %s", e.Step, e.Statement, e.Err, status)
+}
+
+func (e *MigrationRollbackError) Unwrap() error {
+	return e.Err
+}
+
+// nonTransactionalStmt matches the Postgres statements that cannot be run in a
+// transaction block.
+var nonTransactionalStmt = regexp.MustCompile(`(?is)^\s*(CREATE\s+(UNIQUE\s+)?INDEX\s+CONCURRENTLY|DROP\s+INDEX\s+CONCURRENTLY|REINDEX\s.*\sCONCURRENTLY|VACUUM)\b`)
+
+// ApplySchemaMigration applies the statements of a migration. If a statement
+// fails, the migration is rolled back and a *MigrationRollbackError is
+// returned.
+//
+// Consecutive transactional statements are run in a single transaction. Since
+// a statement that cannot be run in a transaction must see the changes of the
+// statements before it, that transaction is committed before it is run, and
+// is not rolled back if a later statement fails. Such statements should
+// therefore be at the end of the migration. If db is itself a transaction,
+// the migration cannot have any of them.
+func (t *ThreadSafeInterpreter) ApplySchemaMigration(ctx *common.EngineContext, db sql.DB, migration *SchemaMigration) error {
+	var nonTransactional int
+	for _, stmt := range migration.Statements {
+		if nonTransactionalStmt.MatchString(stmt) {
+			nonTransactional++
+		}
+	}
+	if nonTransactional != len(migration.RollbackScript) {
+		return fmt.Errorf("migration has %d statements that cannot be run in a transaction, but %d rollback statements",
+			nonTransactional, len(migration.RollbackScript))
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	pgCtx := ctx.TxContext.Ctx
+	var tx sql.Tx
+	copied := t.i.copy()
+	// rollbacks are the rollback statements of the applied non-transactional statements
+	var rollbacks []string
+	// committed is true if transactional statements have been committed
+	var committed bool
+
+	// commit commits the open transaction, if any.
+	commit := func() error {
+		if tx == nil {
+			return nil
+		}
+		err := tx.Commit(pgCtx)
+		tx = nil
+		if err != nil {
+			t.i.apply(copied)
+			return err
+		}
+		committed = true
+		copied = t.i.copy()
+		return nil
+	}
+
+	fail := func(step int, err error) error {
+		migErr := &MigrationRollbackError{
+			Step:      step,
+			Statement: migration.Statements[step],
+			Err:       err,
+		}
+
+		if tx != nil {
+			if err := tx.Rollback(pgCtx); err != nil {
+				migErr.RollbackErr = err
+				return migErr
+			}
+			t.i.apply(copied)
+		}
+
+		for i := len(rollbacks) - 1; i >= 0; i-- {
+			if err := execute(pgCtx, db, rollbacks[i]); err != nil {
+				migErr.RollbackErr = fmt.Errorf("rollback statement %s: %w", rollbacks[i], err)
+				return migErr
+			}
+		}
+
+		migErr.RolledBack = !committed
+		if committed {
+			migErr.RollbackErr = errors.New("statements before a non-transactional statement were committed")
+		}
+		return migErr
+	}
+
+	for step, stmt := range migration.Statements {
+		if nonTransactionalStmt.MatchString(stmt) {
+			if err := commit(); err != nil {
+				return fail(step, fmt.Errorf("commit previous statements: %w", err))
+			}
+			if err := execute(pgCtx, db, stmt); err != nil {
+				return fail(step, err)
+			}
+			rollbacks = append(rollbacks, migration.RollbackScript[len(rollbacks)])
+			continue
+		}
+
+		if tx == nil {
+			var err error
+			tx, err = db.BeginTx(pgCtx)
+			if err != nil {
+				return fail(step, err)
+			}
+		}
+
+		if err := t.i.execute(ctx, tx, stmt, nil, nil, true); err != nil {
+			return fail(step, err)
+		}
+	}
+
+	if err := commit(); err != nil {
+		return fail(len(migration.Statements)-1, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,465 @@
+package interpreter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/kwilteam/kwil-db/common"
+	"github.com/kwilteam/kwil-db/core/types"
+	"github.com/kwilteam/kwil-db/extensions/hooks"
+	"github.com/kwilteam/kwil-db/node/engine"
+	"github.com/kwilteam/kwil-db/node/engine/parse"
+	"github.com/kwilteam/kwil-db/node/types/sql"
+)
+
+const (
+	// onlineSchemaShadowPrefix prefixes the shadow table of a table that is
+	// being changed online. Like history tables, it starts with an underscore
+	// so that it is hidden from the engine.
+	onlineSchemaShadowPrefix = "_osc_"
+	// onlineSchemaCaptureTrigger is the trigger that copies the row changes of a
+	// table to its shadow table.
+	onlineSchemaCaptureTrigger = "kwild_osc_capture"
+	// onlineSchemaBatchSize is the number of rows backfilled per block.
+	onlineSchemaBatchSize = 1000
+	// onlineSchemaEndBlockHook is the name of the end block hook that
+	// advances online schema changes.
+	onlineSchemaEndBlockHook = "online_schema_changes"
+)
+
+func init() {
+	err := hooks.RegisterEndBlockHook(onlineSchemaEndBlockHook, advanceOnlineSchemaChanges)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// advanceOnlineSchemaChanges advances the online schema changes at the end of
+// each block.
+func advanceOnlineSchemaChanges(ctx context.Context, app *common.App, _ *common.BlockContext) error {
+	interp, ok := app.Engine.(*ThreadSafeInterpreter)
+	if !ok {
+		return nil
+	}
+
+	return interp.AdvanceOnlineSchemaChanges(ctx, app.DB)
+}
+
+// OnlineAddColumn adds a nullable column to a table without rewriting the table
+// in a single block. It creates a shadow table with the new column, and a
+// trigger that copies the changes made to the table to it. At the end of each
+// block, AdvanceOnlineSchemaChanges copies the next 1000 rows of the table to
+// the shadow table, in primary key order. In the block in which the last rows
+// are copied, the shadow table replaces the table. The table can be read and
+// written throughout.
+//
+// Since the change is part of the state of the network, db must be the
+// transaction of a block, and every step of the change is made in the
+// transaction of a block. Tables that are referenced by foreign keys cannot be
+// changed online, and a table can only have one change in progress. If the
+// table is altered or dropped before the change completes, the change fails.
+// The progress of the change is recorded in kwild_engine.online_schema_changes.
+func (t *ThreadSafeInterpreter) OnlineAddColumn(ctx context.Context, db sql.DB, namespace, table string, col *engine.Column) error {
+	if col.IsPrimaryKey || !col.Nullable {
+		return fmt.Errorf(`column "%s" must be nullable and cannot be a primary key to be added online`, col.Name)
+	}
+	if col.SensitivityPolicy != nil {
+		return fmt.Errorf(`column "%s" cannot be added online with a sensitivity policy`, col.Name)
+	}
+	colType, err := col.DataType.PGString()
+	if err != nil {
+		return err
+	}
+	if am, ok := db.(sql.AccessModer); !ok || am.AccessMode() != sql.ReadWrite {
+		return engine.ErrCannotMutateState
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	tbl, err := t.getTable(namespace, table)
+	if err != nil {
+		return err
+	}
+	if _, ok := tbl.Column(col.Name); ok {
+		return fmt.Errorf(`column "%s" already exists`, col.Name)
+	}
+
+	change := &onlineSchemaChange{
+		db:        db,
+		namespace: namespace,
+		table:     table,
+		shadow:    onlineSchemaShadowPrefix + table,
+		column:    col.Name,
+	}
+
+	return change.prepare(ctx, colType, col.DataType.String(), primaryKeyNames(tbl))
+}
+
+// primaryKeyNames returns the names of the primary key columns of a table.
+func primaryKeyNames(tbl *engine.Table) []string {
+	var pkCols []string
+	for _, pk := range tbl.PrimaryKeyCols() {
+		pkCols = append(pkCols, pk.Name)
+	}
+	return pkCols
+}
+
+// AdvanceOnlineSchemaChanges copies the next batch of rows of each online
+// schema change in progress to its shadow table, and replaces the tables whose
+// rows have all been copied with their shadow tables. It is run at the end of
+// each block, with the block's transaction. Changes that Postgres rejects, e.g.
+// because their table was altered, fail without failing the block.
+func (t *ThreadSafeInterpreter) AdvanceOnlineSchemaChanges(ctx context.Context, db sql.DB) error {
+	if am, ok := db.(sql.AccessModer); !ok || am.AccessMode() != sql.ReadWrite {
+		return engine.ErrCannotMutateState
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var changes []*onlineSchemaChange
+	var colTypes []string
+	var namespace, table, column, colType string
+	err := queryRowFunc(ctx, db, `SELECT namespace, table_name, column_name, column_type FROM kwild_engine.online_schema_changes
+	WHERE status = 'backfilling' ORDER BY namespace, table_name`, []any{&namespace, &table, &column, &colType}, func() error {
+		changes = append(changes, &onlineSchemaChange{
+			db:        db,
+			namespace: namespace,
+			table:     table,
+			shadow:    onlineSchemaShadowPrefix + table,
+			column:    column,
+		})
+		colTypes = append(colTypes, colType)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// the changes are not captured by CDC, so any cached result could read
+	// the changed tables
+	if len(changes) > 0 && t.readCache != nil {
+		t.readCache.markStale()
+	}
+
+	for i, change := range changes {
+		err = t.advance(ctx, change, colTypes[i])
+		if pgErr := new(pgconn.PgError); errors.As(err, &pgErr) || errors.Is(err, errOnlineSchemaTableChanged) {
+			err = change.abort(ctx)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to add column %s to %s.%s online: %w", change.column, change.namespace, change.table, err)
+		}
+	}
+
+	return nil
+}
+
+// errOnlineSchemaTableChanged is returned when the table of an online schema
+// change was altered or dropped while its rows were copied.
+var errOnlineSchemaTableChanged = errors.New("table changed during online schema change")
+
+// advance copies the next batch of rows of a change, and replaces the table
+// with its shadow table once all rows are copied. The caller must hold the
+// lock.
+func (t *ThreadSafeInterpreter) advance(ctx context.Context, change *onlineSchemaChange, colType string) error {
+	tbl, err := t.getTable(change.namespace, change.table)
+	if errors.Is(err, engine.ErrUnknownTable) || errors.Is(err, engine.ErrNamespaceNotFound) {
+		return errOnlineSchemaTableChanged
+	}
+	if err != nil {
+		return err
+	}
+	if _, ok := tbl.Column(change.column); ok {
+		return errOnlineSchemaTableChanged
+	}
+
+	copied, err := change.backfill(ctx, primaryKeyNames(tbl))
+	if err != nil || copied == onlineSchemaBatchSize {
+		return err
+	}
+
+	dataType, err := types.ParseDataType(colType)
+	if err != nil {
+		return err
+	}
+	return t.swapShadowTable(ctx, change, tbl.History, &parse.AddColumn{Name: change.column, Type: dataType})
+}
+
+// getTable gets a table of a namespace. The caller must hold the lock.
+func (t *ThreadSafeInterpreter) getTable(namespace, table string) (*engine.Table, error) {
+	ns, ok := t.i.namespaces[namespace]
+	if !ok {
+		return nil, fmt.Errorf(`%w: "%s"`, engine.ErrNamespaceNotFound, namespace)
+	}
+	tbl, ok := ns.tables[table]
+	if !ok {
+		return nil, fmt.Errorf(`%w: "%s"`, engine.ErrUnknownTable, table)
+	}
+	return tbl, nil
+}
+
+// onlineSchemaChange is an online change of a table using a shadow table.
+type onlineSchemaChange struct {
+	// db is the transaction of the block.
+	db        sql.DB
+	namespace string
+	table     string
+	shadow    string
+	// column is the column being added.
+	column string
+}
+
+// inTx runs fn in a nested transaction of the block, which is committed if fn
+// succeeds, so that a failed step leaves the block's transaction as it was.
+func (o *onlineSchemaChange) inTx(ctx context.Context, fn func(tx sql.Tx) error) error {
+	tx, err := o.db.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err = fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// prepare records the change, and creates the shadow table with the new column
+// and the trigger that copies changes to it.
+func (o *onlineSchemaChange) prepare(ctx context.Context, colType, dataType string, pkCols []string) error {
+	return o.inTx(ctx, func(tx sql.Tx) error {
+		var referenced bool
+		err := queryRowFunc(ctx, tx, `SELECT EXISTS (SELECT 1 FROM pg_constraint
+		WHERE contype = 'f' AND confrelid = format('%I.%I', $1::TEXT, $2::TEXT)::regclass)`,
+			[]any{&referenced}, func() error { return nil }, o.namespace, o.table)
+		if err != nil {
+			return err
+		}
+		if referenced {
+			return fmt.Errorf(`table "%s" is referenced by a foreign key and cannot be changed online`, o.table)
+		}
+
+		var inProgress bool
+		err = queryRowFunc(ctx, tx, `SELECT EXISTS (SELECT 1 FROM kwild_engine.online_schema_changes
+		WHERE namespace = $1 AND table_name = $2 AND status = 'backfilling')`,
+			[]any{&inProgress}, func() error { return nil }, o.namespace, o.table)
+		if err != nil {
+			return err
+		}
+		if inProgress {
+			return fmt.Errorf(`table "%s" already has an online schema change in progress`, o.table)
+		}
+
+		err = execute(ctx, tx, `INSERT INTO kwild_engine.online_schema_changes (namespace, table_name, column_name, column_type, status)
+		VALUES ($1, $2, $3, $4, 'backfilling')
+		ON CONFLICT (namespace, table_name) DO UPDATE SET column_name = $3, column_type = $4, status = 'backfilling', rows_copied = 0`,
+			o.namespace, o.table, o.column, dataType)
+		if err != nil {
+			return err
+		}
+
+		for _, stmt := range []string{
+			// a shadow table may be left over from a change that failed
+			fmt.Sprintf(`DROP TABLE IF EXISTS %s.%s`, o.namespace, o.shadow),
+			fmt.Sprintf(`CREATE TABLE %s.%s (LIKE %s.%s INCLUDING ALL)`, o.namespace, o.shadow, o.namespace, o.table),
+			fmt.Sprintf(`ALTER TABLE %s.%s ADD COLUMN %s %s`, o.namespace, o.shadow, o.column, colType),
+			fmt.Sprintf(`CREATE OR REPLACE TRIGGER %s AFTER INSERT OR UPDATE OR DELETE ON %s.%s
+			FOR EACH ROW EXECUTE FUNCTION kwild_engine.online_schema_change_capture('%s', '%s')`,
+				onlineSchemaCaptureTrigger, o.namespace, o.table, o.shadow, strings.Join(pkCols, ", ")),
+		} {
+			if err = execute(ctx, tx, stmt); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// backfill copies the next batch of rows of the table that are not yet in the
+// shadow table, and returns the number of rows copied. Rows are copied in
+// primary key order, so that every node copies the same rows in each block.
+// Rows that were changed since the change started were already copied by the
+// trigger, so they are skipped.
+func (o *onlineSchemaChange) backfill(ctx context.Context, pkCols []string) (copied int64, err error) {
+	tablePK := make([]string, len(pkCols))
+	shadowPK := make([]string, len(pkCols))
+	for i, col := range pkCols {
+		tablePK[i] = "t." + col
+		shadowPK[i] = "s." + col
+	}
+
+	err = o.inTx(ctx, func(tx sql.Tx) error {
+		// the table must not have been altered since the shadow table was
+		// created from it, or the swap would lose the change
+		var changed bool
+		err := queryRowFunc(ctx, tx, `SELECT array(SELECT attname || ' ' || format_type(atttypid, atttypmod) FROM pg_attribute
+			WHERE attrelid = format('%I.%I', $1::TEXT, $2::TEXT)::regclass AND attnum > 0 AND NOT attisdropped ORDER BY attname)
+		IS DISTINCT FROM array(SELECT attname || ' ' || format_type(atttypid, atttypmod) FROM pg_attribute
+			WHERE attrelid = format('%I.%I', $1::TEXT, $3::TEXT)::regclass AND attnum > 0 AND NOT attisdropped AND attname <> $4 ORDER BY attname)`,
+			[]any{&changed}, func() error { return nil }, o.namespace, o.table, o.shadow, o.column)
+		if err != nil {
+			return err
+		}
+		if changed {
+			return errOnlineSchemaTableChanged
+		}
+
+		err = queryRowFunc(ctx, tx, fmt.Sprintf(`WITH batch AS (
+			SELECT t.* FROM %[1]s.%[2]s t
+			WHERE NOT EXISTS (SELECT 1 FROM %[1]s.%[3]s s WHERE (%[4]s) = (%[5]s))
+			ORDER BY %[6]s LIMIT %[7]d
+		), copied AS (
+			INSERT INTO %[1]s.%[3]s SELECT (jsonb_populate_record(NULL::%[1]s.%[3]s, to_jsonb(batch))).* FROM batch
+		)
+		SELECT count(*) FROM batch`, o.namespace, o.table, o.shadow, strings.Join(shadowPK, ", "), strings.Join(tablePK, ", "),
+			strings.Join(pkCols, ", "), onlineSchemaBatchSize),
+			[]any{&copied}, func() error { return nil })
+		if err != nil {
+			return err
+		}
+
+		return execute(ctx, tx, `UPDATE kwild_engine.online_schema_changes SET rows_copied = rows_copied + $3
+		WHERE namespace = $1 AND table_name = $2`, o.namespace, o.table, copied)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to backfill shadow table: %w", err)
+	}
+
+	return copied, nil
+}
+
+// indexDefTarget matches the name and table of an index definition returned by
+// pg_get_indexdef, so that the indexes of the shadow table can be matched with
+// those of the table.
+var indexDefTarget = regexp.MustCompile(`^(CREATE (?:UNIQUE )?INDEX) \S+ ON \S+`)
+
+// swapShadowTable replaces the table with its shadow table. The indexes of the
+// shadow table are renamed to those of the table, and the table's triggers and
+// foreign keys are recreated on it. The caller must hold the lock.
+func (t *ThreadSafeInterpreter) swapShadowTable(ctx context.Context, o *onlineSchemaChange, history bool, action parse.AlterTableAction) error {
+	// the table is dropped, so the builds of its indexes must not hold it
+	err := t.i.indexBuilds.pause(ctx, o.db, o.namespace)
+	if err != nil {
+		return err
+	}
+
+	var tables []*engine.Table
+	err = o.inTx(ctx, func(tx sql.Tx) error {
+		err := execute(ctx, tx, fmt.Sprintf(`LOCK TABLE %s.%s IN ACCESS EXCLUSIVE MODE`, o.namespace, o.table))
+		if err != nil {
+			return err
+		}
+
+		// the definitions reference the table by name, so they can be run as is
+		// once the shadow table has been renamed
+		var recreate []string
+		var def, name string
+		err = queryRowFunc(ctx, tx, `SELECT pg_get_triggerdef(oid) FROM pg_trigger
+		WHERE tgrelid = format('%I.%I', $1::TEXT, $2::TEXT)::regclass AND NOT tgisinternal AND tgname <> $3
+		ORDER BY tgname`, []any{&def}, func() error {
+			recreate = append(recreate, def)
+			return nil
+		}, o.namespace, o.table, onlineSchemaCaptureTrigger)
+		if err != nil {
+			return err
+		}
+		err = queryRowFunc(ctx, tx, `SELECT conname::TEXT, pg_get_constraintdef(oid) FROM pg_constraint
+		WHERE conrelid = format('%I.%I', $1::TEXT, $2::TEXT)::regclass AND contype = 'f'
+		ORDER BY conname`, []any{&name, &def}, func() error {
+			recreate = append(recreate, fmt.Sprintf(`ALTER TABLE %s.%s ADD CONSTRAINT %s %s`, o.namespace, o.table, name, def))
+			return nil
+		}, o.namespace, o.table)
+		if err != nil {
+			return err
+		}
+
+		indexNames := make(map[string][]string) // normalized definition => names
+		var shadowIndexes, shadowDefs []string
+		var table string
+		err = queryRowFunc(ctx, tx, `SELECT tablename::TEXT, indexname::TEXT, indexdef FROM pg_indexes
+		WHERE schemaname = $1 AND tablename IN ($2, $3) ORDER BY indexname`, []any{&table, &name, &def}, func() error {
+			def = indexDefTarget.ReplaceAllString(def, "$1")
+			if table == o.table {
+				indexNames[def] = append(indexNames[def], name)
+			} else {
+				shadowIndexes = append(shadowIndexes, name)
+				shadowDefs = append(shadowDefs, def)
+			}
+			return nil
+		}, o.namespace, o.table, o.shadow)
+		if err != nil {
+			return err
+		}
+
+		stmts := []string{
+			fmt.Sprintf(`DROP TABLE %s.%s`, o.namespace, o.table),
+			fmt.Sprintf(`ALTER TABLE %s.%s RENAME TO %s`, o.namespace, o.shadow, o.table),
+		}
+		for i, shadowIndex := range shadowIndexes {
+			names := indexNames[shadowDefs[i]]
+			if len(names) == 0 {
+				return fmt.Errorf(`no index of table "%s" matches shadow index "%s"`, o.table, shadowIndex)
+			}
+			stmts = append(stmts, fmt.Sprintf(`ALTER INDEX %s.%s RENAME TO %s`, o.namespace, shadowIndex, names[0]))
+			indexNames[shadowDefs[i]] = names[1:]
+		}
+		stmts = append(stmts, recreate...)
+
+		for _, stmt := range stmts {
+			if err = execute(ctx, tx, stmt); err != nil {
+				return err
+			}
+		}
+
+		if history {
+			if err = alterHistoryTable(ctx, tx, o.namespace, o.table, action); err != nil {
+				return err
+			}
+		}
+
+		err = execute(ctx, tx, `UPDATE kwild_engine.online_schema_changes SET status = 'completed'
+		WHERE namespace = $1 AND table_name = $2`, o.namespace, o.table)
+		if err != nil {
+			return err
+		}
+
+		tables, err = listTablesInNamespace(ctx, tx, o.namespace)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	ns := t.i.namespaces[o.namespace]
+	ns.tables = make(map[string]*engine.Table, len(tables))
+	for _, tbl := range tables {
+		ns.tables[tbl.Name] = tbl
+	}
+	statementCache.clear()
+
+	return nil
+}
+
+// abort removes the shadow table and trigger of a change that failed.
+func (o *onlineSchemaChange) abort(ctx context.Context) error {
+	return o.inTx(ctx, func(tx sql.Tx) error {
+		for _, stmt := range []string{
+			fmt.Sprintf(`DROP TRIGGER IF EXISTS %s ON %s.%s`, onlineSchemaCaptureTrigger, o.namespace, o.table),
+			fmt.Sprintf(`DROP TABLE IF EXISTS %s.%s`, o.namespace, o.shadow),
+		} {
+			if err := execute(ctx, tx, stmt); err != nil {
+				return err
+			}
+		}
+
+		return execute(ctx, tx, `UPDATE kwild_engine.online_schema_changes SET status = 'failed'
+		WHERE namespace = $1 AND table_name = $2`, o.namespace, o.table)
+	})
+}
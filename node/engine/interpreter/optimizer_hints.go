@@ -0,0 +1,147 @@
+package interpreter
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kwilteam/kwil-db/node/engine"
+	"github.com/kwilteam/kwil-db/node/engine/parse"
+	"github.com/kwilteam/kwil-db/node/types/sql"
+)
+
+// storeOptimizerHints stores the optimizer hints of a table declared with
+// @optimizer_hint.
+func storeOptimizerHints(ctx context.Context, db sql.DB, namespace, table string, hints []*engine.OptimizerHint) error {
+	for _, hint := range hints {
+		err := execute(ctx, db, `INSERT INTO kwild_engine.optimizer_hints (namespace, table_name, hint, value)
+		VALUES ($1, $2, $3, $4)`, namespace, table, hint.Name, hint.Value)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// listOptimizerHints lists the optimizer hints in a namespace, keyed by table.
+func listOptimizerHints(ctx context.Context, db sql.DB, namespace string) (map[string][]*engine.OptimizerHint, error) {
+	hints := make(map[string][]*engine.OptimizerHint)
+	var tableName, hint, value string
+	err := queryRowFunc(ctx, db, `SELECT table_name, hint, value
+	FROM kwild_engine.optimizer_hints WHERE namespace = $1 ORDER BY id`, []any{&tableName, &hint, &value},
+		func() error {
+			hints[tableName] = append(hints[tableName], &engine.OptimizerHint{Name: hint, Value: value})
+			return nil
+		}, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	return hints, nil
+}
+
+// deleteOptimizerHints deletes the optimizer hints of a table.
+func deleteOptimizerHints(ctx context.Context, db sql.DB, namespace, table string) error {
+	return execute(ctx, db, `DELETE FROM kwild_engine.optimizer_hints WHERE namespace = $1 AND table_name = $2`, namespace, table)
+}
+
+// renameOptimizerHints updates the optimizer hints of a renamed table.
+func renameOptimizerHints(ctx context.Context, db sql.DB, namespace, table, newName string) error {
+	return execute(ctx, db, `UPDATE kwild_engine.optimizer_hints SET table_name = $3 WHERE namespace = $1 AND table_name = $2`,
+		namespace, table, newName)
+}
+
+// tableOptimizerHints returns the optimizer hints of the tables a statement
+// reads or writes. If tables have conflicting hints, the first one found is
+// used.
+func (e *executionContext) tableOptimizerHints(ast *parse.SQLStatement) []*engine.OptimizerHint {
+	cteNames := make(map[string]struct{}, len(ast.CTEs))
+	for _, cte := range ast.CTEs {
+		cteNames[cte.Name] = struct{}{}
+	}
+
+	var hints []*engine.OptimizerHint
+	seen := make(map[string]struct{})
+	add := func(namespace, table string) {
+		if _, ok := cteNames[table]; ok && namespace == "" {
+			return
+		}
+
+		tbl, err := e.getTable(namespace, table)
+		if err != nil {
+			// unknown tables are reported by the planner
+			return
+		}
+		for _, hint := range tbl.OptimizerHints {
+			if _, ok := seen[hint.Name]; ok {
+				continue
+			}
+			seen[hint.Name] = struct{}{}
+			hints = append(hints, hint)
+		}
+	}
+
+	parse.RecursivelyVisitPositions(ast, func(gp parse.GetPositioner) {
+		switch n := gp.(type) {
+		case *parse.RelationTable:
+			add(n.Namespace, n.Table)
+		case *parse.InsertStatement:
+			add("", n.Table)
+		case *parse.UpdateStatement:
+			add("", n.Table)
+		case *parse.DeleteStatement:
+			add("", n.Table)
+		}
+	})
+
+	return hints
+}
+
+// mergeOptimizerHints returns the hints of a query: the hints of the action
+// running it, followed by the hints of the tables it uses that the action does
+// not override.
+func mergeOptimizerHints(actionHints, tableHints []*engine.OptimizerHint) []*engine.OptimizerHint {
+	if len(actionHints) == 0 {
+		return tableHints
+	}
+
+	hints := append([]*engine.OptimizerHint{}, actionHints...)
+	for _, hint := range tableHints {
+		overridden := false
+		for _, actionHint := range actionHints {
+			if actionHint.Name == hint.Name {
+				overridden = true
+				break
+			}
+		}
+		if !overridden {
+			hints = append(hints, hint)
+		}
+	}
+
+	return hints
+}
+
+// setOptimizerHints sets the planner settings of hints for the rest of the
+// transaction. The hint names are validated by the parser, so they are safe
+// to use as identifiers.
+func setOptimizerHints(ctx context.Context, db sql.DB, hints []*engine.OptimizerHint) error {
+	for _, hint := range hints {
+		if err := execute(ctx, db, fmt.Sprintf(`SET LOCAL %s = %s`, hint.Name, hint.Value)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// resetOptimizerHints resets the planner settings of hints to their defaults.
+func resetOptimizerHints(ctx context.Context, db sql.DB, hints []*engine.OptimizerHint) error {
+	for _, hint := range hints {
+		if err := execute(ctx, db, fmt.Sprintf(`RESET %s`, hint.Name)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package interpreter
+
+import (
+	"testing"
+
+	"github.com/kwilteam/kwil-db/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParamVariables(t *testing.T) {
+	t.Run("prefixes", func(t *testing.T) {
+		vars, err := paramVariables(map[string]any{
+			"@amount": int64(10),
+			"$From":   "alice",
+			"to":      "bob",
+		})
+		require.NoError(t, err)
+		require.Len(t, vars, 3)
+
+		require.Equal(t, int64(10), vars["$amount"].RawValue())
+		require.True(t, vars["$amount"].Type().Equals(types.IntType))
+		require.Equal(t, "alice", vars["$from"].RawValue())
+		require.Equal(t, "bob", vars["$to"].RawValue())
+	})
+
+	t.Run("collision", func(t *testing.T) {
+		_, err := paramVariables(map[string]any{
+			"@amount": int64(10),
+			"$amount": int64(20),
+		})
+		require.ErrorContains(t, err, "both set variable $amount")
+	})
+
+	t.Run("invalid name", func(t *testing.T) {
+		_, err := paramVariables(map[string]any{
+			"@amount-due": int64(10),
+		})
+		require.Error(t, err)
+	})
+}
@@ -23,6 +23,8 @@ func makeActionToExecutable(namespace string, act *action) *executable {
 		stmtFns[j] = stmt.Accept(planner).(stmtFunc)
 	}
 
+	distinct := newDistinctResults(act)
+
 	var expectedArgs []*types.DataType
 	for _, p := range act.Parameters {
 		expectedArgs = append(expectedArgs, p.Type)
@@ -53,7 +55,12 @@ func makeActionToExecutable(namespace string, act *action) *executable {
 	return &executable{
 		Name:         act.Name,
 		ExpectedArgs: &expectedArgs,
-		Func: func(exec *executionContext, args []value, fn resultFunc) error {
+		Func: func(exec *executionContext, args []value, fn resultFunc) (callErr error) {
+			callStack := pushCallFrame(exec.callStack, namespace, act.Name, len(args))
+			defer func() {
+				callErr = withCallStack(callStack, callErr)
+			}()
+
 			if err := exec.canExecute(namespace, act.Name, act.Modifiers); err != nil {
 				return err
 			}
@@ -79,6 +86,17 @@ func makeActionToExecutable(namespace string, act *action) *executable {
 			}
 
 			exec2 := exec.subscope(namespace)
+			exec2.callStack = callStack
+			exec2.optimizerHints = act.OptimizerHints
+			exec2.errorTypes = act.ErrorTypes
+			exec2.distinct = distinct
+			// cursors that the action did not close are closed when it
+			// returns, so that they do not stay open in the transaction
+			defer func() {
+				if err := exec2.closeCursors(); err != nil && callErr == nil {
+					callErr = err
+				}
+			}()
 
 			for j, param := range act.Parameters {
 				err = exec2.allocateVariable(param.Name, args[j])
@@ -89,6 +107,10 @@ func makeActionToExecutable(namespace string, act *action) *executable {
 
 			// execute the statements
 			for _, stmt := range stmtFns {
+				if err := exec2.checkCanceled(); err != nil {
+					return err
+				}
+
 				err := stmt(exec2, func(row *row) error {
 					row.columns = returnColNames
 
@@ -133,7 +155,9 @@ func makeActionToExecutable(namespace string, act *action) *executable {
 
 			return nil
 		},
-		Type: executableTypeAction,
+		Type:       executableTypeAction,
+		Idempotent: act.Idempotent,
+		Test:       act.Test,
 	}
 }
 
@@ -500,6 +524,10 @@ func executeBlock(exec *executionContext, fn resultFunc,
 	defer exec.scope.popScope()
 
 	for _, stmt := range stmtFuncs {
+		if err := exec.checkCanceled(); err != nil {
+			return err
+		}
+
 		err := stmt(exec, fn)
 		if err != nil {
 			return err
@@ -527,6 +555,10 @@ func (i *interpreterPlanner) VisitActionStmtForLoop(p0 *parse.ActionStmtForLoop)
 			}
 
 			for _, stmt := range stmtFns {
+				if err := exec.checkCanceled(); err != nil {
+					return err
+				}
+
 				err := stmt(exec, fn)
 				if err != nil {
 					return err
@@ -841,10 +873,12 @@ func (i *interpreterPlanner) VisitActionStmtReturn(p0 *parse.ActionStmtReturn) a
 
 		if sqlStmt != nil {
 			// otherwise, we execute the SQL statement.
+			exec.returning = true
 			err := sqlStmt(exec, func(row *row) error {
 				row.fillUnnamed()
 				return fn(row)
 			})
+			exec.returning = false
 			if err != nil {
 				return err
 			}
@@ -884,6 +918,56 @@ func (i *interpreterPlanner) VisitActionStmtReturnNext(p0 *parse.ActionStmtRetur
 	})
 }
 
+func (i *interpreterPlanner) VisitActionStmtDeclareCursor(p0 *parse.ActionStmtDeclareCursor) any {
+	return stmtFunc(func(exec *executionContext, fn resultFunc) error {
+		raw, err := p0.Query.Raw()
+		if err != nil {
+			return err
+		}
+
+		return exec.declareCursor(p0.Name, raw)
+	})
+}
+
+func (i *interpreterPlanner) VisitActionStmtFetchCursor(p0 *parse.ActionStmtFetchCursor) any {
+	return stmtFunc(func(exec *executionContext, fn resultFunc) error {
+		r, err := exec.fetchCursor(p0.Name)
+		if err != nil {
+			return err
+		}
+
+		// once the cursor has no more rows, the variables are set to null
+		if r == nil {
+			r, err = exec.cursors[p0.Name].nullRow()
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(p0.Into) > len(r.Values) {
+			return fmt.Errorf(`%w: expected cursor "%s" to return at least %d values, but it returned %d`, engine.ErrReturnShape, p0.Name, len(p0.Into), len(r.Values))
+		}
+
+		for j, v := range p0.Into {
+			if v == nil {
+				continue
+			}
+
+			if err = exec.setVariable(v.Name, r.Values[j]); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (i *interpreterPlanner) VisitActionStmtCloseCursor(p0 *parse.ActionStmtCloseCursor) any {
+	return stmtFunc(func(exec *executionContext, fn resultFunc) error {
+		return exec.closeCursor(p0.Name)
+	})
+}
+
 // everything in this section is for expressions, which evaluate to exactly one value.
 
 // handleTypeCast is a helper function that handles type casting.
@@ -1586,6 +1670,12 @@ func genAndExec(exec *executionContext, stmt parse.TopLevelStatement) error {
 		return fmt.Errorf("%w: %w", engine.ErrPGGen, err)
 	}
 
+	// Postgres evaluates defaults and checks when rows are written, when the
+	// block timestamp might not be set
+	if strings.Contains(sql, engine.BlockTimestampSQL) {
+		return fmt.Errorf(`%w: "now" cannot be used in table definitions`, engine.ErrIllegalFunctionUsage)
+	}
+
 	return execute(exec.engineCtx.TxContext.Ctx, exec.db, sql)
 }
 
@@ -1624,6 +1714,49 @@ func (i *interpreterPlanner) VisitCreateTableStatement(p0 *parse.CreateTableStat
 			return err
 		}
 
+		err = storeColumnPolicies(exec.engineCtx.TxContext.Ctx, exec.db, exec.scope.namespace, p0.Name, p0.Columns)
+		if err != nil {
+			return err
+		}
+
+		err = storeOptimizerHints(exec.engineCtx.TxContext.Ctx, exec.db, exec.scope.namespace, p0.Name, p0.OptimizerHints)
+		if err != nil {
+			return err
+		}
+
+		if p0.SoftDelete {
+			err = createSoftDeleteTrigger(exec.engineCtx.TxContext.Ctx, exec.db, exec.scope.namespace, p0.Name)
+			if err != nil {
+				return err
+			}
+		}
+
+		if p0.History {
+			err = createHistoryTable(exec.engineCtx.TxContext.Ctx, exec.db, exec.scope.namespace, p0)
+			if err != nil {
+				return err
+			}
+
+			// the timestamp is otherwise only set if history tables existed when execution began
+			if err = exec.setHistoryTimestamp(); err != nil {
+				return err
+			}
+		}
+
+		if exec.interpreter.namespaces[exec.scope.namespace].eventSourced {
+			err = createEventTrigger(exec.engineCtx.TxContext.Ctx, exec.db, exec.scope.namespace, p0)
+			if err != nil {
+				return err
+			}
+		}
+
+		if exec.interpreter.cdc != nil {
+			err = createCDCTrigger(exec.engineCtx.TxContext.Ctx, exec.db, exec.scope.namespace, p0.Name)
+			if err != nil {
+				return err
+			}
+		}
+
 		return exec.reloadNamespaceCache()
 	})
 }
@@ -1645,6 +1778,8 @@ func (i *interpreterPlanner) VisitDropTableStatement(p0 *parse.DropTableStatemen
 			return err
 		}
 
+		// the tables that do not exist are skipped if IF EXISTS is used
+		var dropped []string
 		for _, table := range p0.Tables {
 			// ensure the table exists
 			_, err := exec.getTable("", table)
@@ -1659,16 +1794,140 @@ func (i *interpreterPlanner) VisitDropTableStatement(p0 *parse.DropTableStatemen
 
 				return err
 			}
+
+			dropped = append(dropped, table)
+		}
+
+		if len(dropped) == 0 {
+			return nil
+		}
+
+		if err := exec.interpreter.indexBuilds.pause(exec.engineCtx.TxContext.Ctx, exec.db, exec.scope.namespace); err != nil {
+			return err
 		}
 
 		if err := genAndExec(exec, p0); err != nil {
 			return err
 		}
 
+		for _, table := range dropped {
+			err = dropTableConcurrentIndexes(exec.engineCtx.TxContext.Ctx, exec.db, exec.scope.namespace, table)
+			if err != nil {
+				return err
+			}
+
+			err = deleteColumnPolicies(exec.engineCtx.TxContext.Ctx, exec.db, exec.scope.namespace, table, "")
+			if err != nil {
+				return err
+			}
+
+			err = dropHistoryTable(exec.engineCtx.TxContext.Ctx, exec.db, exec.scope.namespace, table)
+			if err != nil {
+				return err
+			}
+
+			if exec.interpreter.namespaces[exec.scope.namespace].eventSourced {
+				err = dropTableEvents(exec.engineCtx.TxContext.Ctx, exec.db, exec.scope.namespace, table)
+				if err != nil {
+					return err
+				}
+			}
+
+			err = deleteOptimizerHints(exec.engineCtx.TxContext.Ctx, exec.db, exec.scope.namespace, table)
+			if err != nil {
+				return err
+			}
+		}
+
 		return exec.reloadNamespaceCache()
 	})
 }
 
+func (i *interpreterPlanner) VisitTruncateTableStatement(p0 *parse.TruncateTableStatement) any {
+	return stmtFunc(func(exec *executionContext, fn resultFunc) error {
+		reset, err := handleNamespaced(exec, p0)
+		if err != nil {
+			return err
+		}
+		defer reset()
+
+		if err := exec.checkNamespaceMutatbility(); err != nil {
+			return err
+		}
+
+		// truncating a table deletes all of its rows
+		if err := exec.checkPrivilege(_DELETE_PRIVILEGE); err != nil {
+			return err
+		}
+
+		if !exec.canMutateState {
+			return fmt.Errorf(`%w: cannot truncate table "%s"`, engine.ErrCannotMutateState, p0.Table)
+		}
+
+		tbl, err := exec.getTable("", p0.Table)
+		if err != nil {
+			return err
+		}
+
+		if err := checkTruncatable(exec.interpreter.namespaces[exec.scope.namespace], exec.scope.namespace, tbl); err != nil {
+			return err
+		}
+
+		refs, err := referencingTables(exec.engineCtx.TxContext.Ctx, exec.db, exec.scope.namespace, p0.Table)
+		if err != nil {
+			return err
+		}
+
+		if len(refs) > 0 && !p0.Cascade {
+			return fmt.Errorf(`%w: table "%s" is referenced by table "%s.%s", use CASCADE to truncate it as well`, engine.ErrForeignKeyViolation, p0.Table, refs[0][0], refs[0][1])
+		}
+
+		// CASCADE also truncates the tables that reference the table, so they
+		// are checked as if they were truncated directly
+		for _, ref := range refs {
+			refNs, ok := exec.interpreter.namespaces[ref[0]]
+			if !ok {
+				return fmt.Errorf(`%w: "%s"`, engine.ErrNamespaceNotFound, ref[0])
+			}
+			if ref[0] == engine.InfoNamespace {
+				return engine.ErrCannotMutateInfoNamespace
+			}
+			if refNs.namespaceType == namespaceTypeExtension && !exec.engineCtx.OverrideAuthz {
+				return fmt.Errorf(`%w: "%s"`, engine.ErrCannotMutateExtension, ref[0])
+			}
+			if !exec.engineCtx.OverrideAuthz && !exec.interpreter.accessController.HasPrivilege(exec.engineCtx.TxContext.Caller, &ref[0], _DELETE_PRIVILEGE) {
+				return fmt.Errorf(`%w %s on namespace "%s"`, engine.ErrDoesNotHavePrivilege, _DELETE_PRIVILEGE, ref[0])
+			}
+
+			refTbl, ok := refNs.tables[ref[1]]
+			if !ok {
+				return fmt.Errorf(`%w: table "%s" not found in namespace "%s"`, engine.ErrUnknownTable, ref[1], ref[0])
+			}
+			if err := checkTruncatable(refNs, ref[0], refTbl); err != nil {
+				return err
+			}
+		}
+
+		return genAndExec(exec, p0)
+	})
+}
+
+// checkTruncatable checks that a table can be truncated. TRUNCATE does not
+// fire the row triggers that soft deletes, history tables and event sourcing
+// rely on, so their tables can only be cleared with DELETE.
+func checkTruncatable(ns *namespace, nsName string, tbl *engine.Table) error {
+	switch {
+	case tbl.SoftDelete:
+		return fmt.Errorf(`table "%s.%s" is a soft delete table and cannot be truncated`, nsName, tbl.Name)
+	case tbl.History:
+		return fmt.Errorf(`table "%s.%s" records its history and cannot be truncated`, nsName, tbl.Name)
+	case ns.eventSourced:
+		return fmt.Errorf(`table "%s.%s" is in an event sourced namespace and cannot be truncated`, nsName, tbl.Name)
+	}
+
+	return nil
+}
+
 func (i *interpreterPlanner) VisitCreateIndexStatement(p0 *parse.CreateIndexStatement) any {
 	return stmtFunc(func(exec *executionContext, fn resultFunc) error {
 		reset, err := handleNamespaced(exec, p0)
@@ -1704,6 +1963,28 @@ func (i *interpreterPlanner) VisitCreateIndexStatement(p0 *parse.CreateIndexStat
 			}
 		}
 
+		if p0.Concurrently {
+			return createIndexConcurrently(exec, p0)
+		}
+
+		// an index created concurrently may not be built on this node yet
+		if p0.Name != "" {
+			exists, err := concurrentIndexExists(exec, p0.Name)
+			if err != nil {
+				return err
+			}
+			if exists {
+				if p0.IfNotExists {
+					return nil
+				}
+				return fmt.Errorf(`relation "%s" already exists`, p0.Name)
+			}
+		}
+
+		if err := exec.interpreter.indexBuilds.pause(exec.engineCtx.TxContext.Ctx, exec.db, exec.scope.namespace); err != nil {
+			return err
+		}
+
 		if err := genAndExec(exec, p0); err != nil {
 			return err
 		}
@@ -1730,10 +2011,20 @@ func (i *interpreterPlanner) VisitDropIndexStatement(p0 *parse.DropIndexStatemen
 			return err
 		}
 
-		if err := genAndExec(exec, p0); err != nil {
+		if err := exec.interpreter.indexBuilds.pause(exec.engineCtx.TxContext.Ctx, exec.db, exec.scope.namespace); err != nil {
 			return err
 		}
 
+		dropped, err := dropConcurrentIndex(exec, p0.Name)
+		if err != nil {
+			return err
+		}
+		if !dropped {
+			if err := genAndExec(exec, p0); err != nil {
+				return err
+			}
+		}
+
 		// we reload tables here because we track indexes in the table object
 		return exec.reloadNamespaceCache()
 	})
@@ -1867,6 +2158,10 @@ func (i *interpreterPlanner) VisitCreateActionStatement(p0 *parse.CreateActionSt
 		}
 		namespace := exec.interpreter.namespaces[exec.scope.namespace]
 
+		if p0.Test && !namespace.test {
+			return fmt.Errorf(`test action "%s" can only be created in a namespace declared with @test_namespace`, p0.Name)
+		}
+
 		// we check in the available functions map because there is a chance that the user is overwriting an existing function.
 		if existingExec, exists := namespace.availableFunctions[p0.Name]; exists {
 			if p0.IfNotExists {
@@ -1901,6 +2196,10 @@ func (i *interpreterPlanner) VisitCreateActionStatement(p0 *parse.CreateActionSt
 		execute := makeActionToExecutable(exec.scope.namespace, &act)
 		namespace.availableFunctions[p0.Name] = execute
 
+		if exec.interpreter.advisor != nil {
+			exec.interpreter.advisor.adviseIndexCoverage(p0, exec.getTable)
+		}
+
 		return nil
 	})
 }
@@ -2007,11 +2306,30 @@ func (i *interpreterPlanner) VisitCreateNamespaceStatement(p0 *parse.CreateNames
 			return err
 		}
 
+		if p0.Test {
+			if err := createTestNamespace(exec.engineCtx.TxContext.Ctx, exec.db, p0.Namespace); err != nil {
+				return err
+			}
+		}
+
+		if p0.EventSourced {
+			if err := createEventsTable(exec.engineCtx.TxContext.Ctx, exec.db, p0.Namespace); err != nil {
+				return err
+			}
+
+			// the block height is otherwise only set if event sourced namespaces existed when execution began
+			if err := exec.setEventBlockHeight(); err != nil {
+				return err
+			}
+		}
+
 		exec.interpreter.namespaces[p0.Namespace] = &namespace{
 			availableFunctions: copyBuiltinExecutables(),
 			tables:             make(map[string]*engine.Table),
 			onDeploy:           func(*executionContext) error { return nil },
 			onUndeploy:         func(*executionContext) error { return nil },
+			eventSourced:       p0.EventSourced,
+			test:               p0.Test,
 		}
 		exec.interpreter.accessController.registerNamespace(p0.Namespace)
 
@@ -2041,10 +2359,18 @@ func (i *interpreterPlanner) VisitDropNamespaceStatement(p0 *parse.DropNamespace
 			return fmt.Errorf(`%w: cannot drop extension namespace "%s" using DROP NAMESPACE. use UNUSE instead`, engine.ErrCannotMutateExtension, p0.Namespace)
 		}
 
+		if err := exec.interpreter.indexBuilds.pause(exec.engineCtx.TxContext.Ctx, exec.db, p0.Namespace); err != nil {
+			return err
+		}
+
 		if err := dropNamespace(exec.engineCtx.TxContext.Ctx, exec.db, p0.Namespace); err != nil {
 			return err
 		}
 
+		if err := dropEventsTable(exec.engineCtx.TxContext.Ctx, exec.db, p0.Namespace); err != nil {
+			return err
+		}
+
 		delete(exec.interpreter.namespaces, p0.Namespace)
 		exec.interpreter.accessController.unregisterNamespace(p0.Namespace)
 
@@ -2068,6 +2394,31 @@ func (i *interpreterPlanner) VisitSetCurrentNamespaceStatement(p0 *parse.SetCurr
 	})
 }
 
+func (i *interpreterPlanner) VisitAlterNamespaceStatement(p0 *parse.AlterNamespaceStatement) any {
+	return stmtFunc(func(exec *executionContext, fn resultFunc) error {
+		if !exec.engineCtx.OverrideAuthz && !exec.interpreter.accessController.HasPrivilege(exec.engineCtx.TxContext.Caller, &p0.Namespace, _ALTER_PRIVILEGE) {
+			return fmt.Errorf(`%w %s on namespace "%s"`, engine.ErrDoesNotHavePrivilege, _ALTER_PRIVILEGE, p0.Namespace)
+		}
+
+		ns, exists := exec.interpreter.namespaces[p0.Namespace]
+		if !exists {
+			return fmt.Errorf(`%w: namespace "%s" does not exist`, engine.ErrNamespaceNotFound, p0.Namespace)
+		}
+
+		if p0.Config != nil {
+			return setNamespaceConfig(exec.engineCtx.TxContext.Ctx, exec.db, p0.Namespace, p0.Config.Key, p0.Config.Value)
+		}
+
+		if err := setReadOnly(exec.engineCtx.TxContext.Ctx, exec.db, p0.Namespace, p0.ReadOnly); err != nil {
+			return err
+		}
+
+		ns.readOnly = p0.ReadOnly
+
+		return nil
+	})
+}
+
 func (i *interpreterPlanner) VisitAlterTableStatement(p0 *parse.AlterTableStatement) any {
 	var alterTableActions []alterTableActionFunc
 	for _, action := range p0.Actions {
@@ -2106,11 +2457,56 @@ func (i *interpreterPlanner) VisitAlterTableStatement(p0 *parse.AlterTableStatem
 		// generate the SQL and execute it, and then completely refresh the in-memory objects for this schema.
 		// This isn't the most efficient way to do it, but it's the easiest to implement, and since DDL isn't
 		// really a hotpath, it's fine.
+		ctx := exec.engineCtx.TxContext.Ctx
+		err = exec.interpreter.indexBuilds.pause(ctx, exec.db, exec.scope.namespace)
+		if err != nil {
+			return err
+		}
+
 		err = genAndExec(exec, p0)
 		if err != nil {
 			return err
 		}
 
+		// keep the masking policies of sensitive columns, the optimizer hints, the indexes created concurrently,
+		// the history table, and the events in sync with the table
+		tableName := p0.Table
+		for _, action := range p0.Actions {
+			err = updateConcurrentIndexes(ctx, exec.db, exec.scope.namespace, tableName, action)
+			if err != nil {
+				return err
+			}
+
+			if tbl.History {
+				err = alterHistoryTable(ctx, exec.db, exec.scope.namespace, tableName, action)
+				if err != nil {
+					return err
+				}
+			}
+
+			switch action := action.(type) {
+			case *parse.DropColumn:
+				err = deleteColumnPolicies(ctx, exec.db, exec.scope.namespace, tableName, action.Name)
+			case *parse.RenameColumn:
+				err = renameColumnPolicies(ctx, exec.db, exec.scope.namespace, tableName, action.OldName, action.NewName)
+				if err == nil {
+					err = exec.warnRenamedColumn(tableName, action.OldName)
+				}
+			case *parse.RenameTable:
+				err = renameColumnPolicies(ctx, exec.db, exec.scope.namespace, tableName, "", action.Name)
+				if err == nil {
+					err = renameOptimizerHints(ctx, exec.db, exec.scope.namespace, tableName, action.Name)
+				}
+				if err == nil && exec.interpreter.namespaces[exec.scope.namespace].eventSourced {
+					err = renameTableEvents(ctx, exec.db, exec.scope.namespace, tableName, action.Name)
+				}
+				tableName = action.Name
+			}
+			if err != nil {
+				return err
+			}
+		}
+
 		return exec.reloadNamespaceCache()
 	})
 }
@@ -2319,6 +2715,10 @@ func (i *interpreterPlanner) VisitRelationSubquery(p0 *parse.RelationSubquery) a
 	panic("intepreter planner should not be called for SQL expressions")
 }
 
+func (i *interpreterPlanner) VisitRelationFunctionCall(p0 *parse.RelationFunctionCall) any {
+	panic("intepreter planner should not be called for SQL expressions")
+}
+
 func (i *interpreterPlanner) VisitJoin(p0 *parse.Join) any {
 	panic("intepreter planner should not be called for SQL expressions")
 }
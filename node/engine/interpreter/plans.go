@@ -0,0 +1,206 @@
+package interpreter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/kwilteam/kwil-db/core/types"
+	"github.com/kwilteam/kwil-db/node/types/sql"
+)
+
+// planCapture collects the query plans of the queries executed by an action.
+type planCapture struct {
+	// nodes are the descriptions of every node of every plan.
+	nodes []string
+	// totalCost is the sum of the estimated total costs of the plans.
+	totalCost float64
+}
+
+// explainedPlan is a node of the JSON output of EXPLAIN.
+type explainedPlan struct {
+	NodeType     string          `json:"Node Type"`
+	RelationName string          `json:"Relation Name"`
+	IndexName    string          `json:"Index Name"`
+	TotalCost    float64         `json:"Total Cost"`
+	Plans        []explainedPlan `json:"Plans"`
+
+	// The fields below are used by the query advisor. Schema is only set
+	// by EXPLAIN VERBOSE.
+	Schema     string `json:"Schema"`
+	Alias      string `json:"Alias"`
+	Filter     string `json:"Filter"`
+	JoinFilter string `json:"Join Filter"`
+	IndexCond  string `json:"Index Cond"`
+	HashCond   string `json:"Hash Cond"`
+	MergeCond  string `json:"Merge Cond"`
+}
+
+// explain records the plan of a query that is about to be executed.
+func (p *planCapture) explain(ctx context.Context, db sql.DB, stmt string, args []value) error {
+	var out []byte
+	var explained []byte
+	err := query(ctx, db, "EXPLAIN (FORMAT JSON) "+stmt, []any{&out}, func() error {
+		explained = bytes.Clone(out)
+		return nil
+	}, args)
+	if err != nil {
+		return fmt.Errorf("failed to explain query: %w", err)
+	}
+
+	var plans []struct {
+		Plan explainedPlan `json:"Plan"`
+	}
+	if err = json.Unmarshal(explained, &plans); err != nil {
+		return fmt.Errorf("failed to decode query plan: %w", err)
+	}
+
+	for _, plan := range plans {
+		p.totalCost += plan.Plan.TotalCost
+		p.addNodes(&plan.Plan)
+	}
+	return nil
+}
+
+// addNodes adds the description of a plan node and its children.
+func (p *planCapture) addNodes(node *explainedPlan) {
+	desc := node.NodeType
+	if node.RelationName != "" {
+		desc += " on " + node.RelationName
+	}
+	if node.IndexName != "" {
+		desc += " using " + node.IndexName
+	}
+	p.nodes = append(p.nodes, desc)
+
+	for i := range node.Plans {
+		p.addNodes(&node.Plans[i])
+	}
+}
+
+// capturePlans calls an action in a transaction that is rolled back, and
+// returns the plans of the queries it executes. Since the action is called
+// without a transaction context, it cannot use variables such as @caller.
+// If db is read-only, only view actions can be called.
+func (t *ThreadSafeInterpreter) capturePlans(ctx context.Context, db sql.DB, namespace, action string, args []any) (*planCapture, error) {
+	tx, err := db.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// any change to the interpreter's state is rolled back with the tx
+	copied := t.i.copy()
+	capture := &planCapture{}
+	t.i.plans = capture
+	defer func() {
+		t.i.plans = nil
+		t.i.apply(copied)
+	}()
+
+	res, err := t.i.call(newInvalidEngineCtx(ctx), tx, namespace, action, args, nil, true, nil)
+	if err != nil {
+		return nil, err
+	}
+	if res.Error != nil {
+		return nil, res.Error
+	}
+
+	return capture, nil
+}
+
+// SavePlanBaseline stores the query plans of an action called with the given
+// arguments as a baseline for ComparePlans. Saving a baseline with an existing
+// ID replaces it.
+func (t *ThreadSafeInterpreter) SavePlanBaseline(ctx context.Context, db sql.DB, namespace, action string, args []any, baselineID string) error {
+	if baselineID == "" {
+		return errors.New("baseline ID cannot be empty")
+	}
+
+	capture, err := t.capturePlans(ctx, db, namespace, action, args)
+	if err != nil {
+		return err
+	}
+
+	nodes, err := json.Marshal(capture.nodes)
+	if err != nil {
+		return err
+	}
+
+	return execute(ctx, db, `INSERT INTO kwild_engine.plan_baselines (id, namespace, action_name, nodes, total_cost)
+	VALUES ($1, $2, $3, $4, $5)
+	ON CONFLICT (id) DO UPDATE SET namespace = $2, action_name = $3, nodes = $4, total_cost = $5`,
+		baselineID, namespace, action, string(nodes), strconv.FormatFloat(capture.totalCost, 'f', -1, 64))
+}
+
+// ComparePlans compares the query plans of an action called with the given
+// arguments against a baseline stored with SavePlanBaseline.
+func (t *ThreadSafeInterpreter) ComparePlans(ctx context.Context, db sql.DB, namespace, action string, args []any, baselineID string) (*types.PlanComparison, error) {
+	var found bool
+	var baseNamespace, baseAction, nodesJSON, costStr string
+	err := queryRowFunc(ctx, db, `SELECT namespace, action_name, nodes, total_cost FROM kwild_engine.plan_baselines WHERE id = $1`,
+		[]any{&baseNamespace, &baseAction, &nodesJSON, &costStr}, func() error {
+			found = true
+			return nil
+		}, baselineID)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf(`unknown plan baseline "%s"`, baselineID)
+	}
+	if baseNamespace != namespace || baseAction != action {
+		return nil, fmt.Errorf(`plan baseline "%s" is for action "%s" in namespace "%s"`, baselineID, baseAction, baseNamespace)
+	}
+
+	var baseNodes []string
+	if err = json.Unmarshal([]byte(nodesJSON), &baseNodes); err != nil {
+		return nil, fmt.Errorf("failed to decode plan baseline: %w", err)
+	}
+	baseCost, err := strconv.ParseFloat(costStr, 64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode plan baseline cost: %w", err)
+	}
+
+	capture, err := t.capturePlans(ctx, db, namespace, action, args)
+	if err != nil {
+		return nil, err
+	}
+
+	added := nodeDifference(capture.nodes, baseNodes)
+	removed := nodeDifference(baseNodes, capture.nodes)
+	return &types.PlanComparison{
+		Changed:      len(added) > 0 || len(removed) > 0,
+		AddedNodes:   added,
+		RemovedNodes: removed,
+		CostDelta:    capture.totalCost - baseCost,
+	}, nil
+}
+
+// nodeDifference returns the nodes in a that are not in b, counting duplicates,
+// in sorted order.
+func nodeDifference(a, b []string) []string {
+	counts := make(map[string]int, len(b))
+	for _, node := range b {
+		counts[node]++
+	}
+
+	diff := []string{}
+	for _, node := range a {
+		if counts[node] > 0 {
+			counts[node]--
+			continue
+		}
+		diff = append(diff, node)
+	}
+
+	slices.Sort(diff)
+	return diff
+}
@@ -0,0 +1,37 @@
+package interpreter
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/kwilteam/kwil-db/node/engine"
+)
+
+// raiseError implements the raise_error built-in. It returns an
+// engine.ApplicationError with the given code, which must be one of the error
+// types the action declared with @error_type. If the message is null or
+// empty, the message of the error type is used.
+func (e *executionContext) raiseError(code, msg value) error {
+	if code.Null() {
+		return errors.New("raise_error: code cannot be null")
+	}
+
+	c := code.RawValue().(int64)
+	var declared *engine.ErrorType
+	for _, et := range e.errorTypes {
+		if int64(et.Code) == c {
+			declared = et
+			break
+		}
+	}
+	if declared == nil {
+		return fmt.Errorf("raise_error: error type %d is not declared with @error_type", c)
+	}
+
+	appErr := &engine.ApplicationError{Code: declared.Code, Message: declared.Message}
+	if !msg.Null() && msg.RawValue().(string) != "" {
+		appErr.Message = msg.RawValue().(string)
+	}
+
+	return newUserDefinedErr(appErr)
+}
@@ -0,0 +1,106 @@
+package interpreter
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/bits"
+
+	"github.com/kwilteam/kwil-db/core/types"
+	"github.com/kwilteam/kwil-db/node/engine"
+)
+
+// seededRandom implements the seeded_random built-in. It returns a
+// pseudo-random number in [0, n) that is determined by the seed and the
+// height of the current block, so that every node computes the same number.
+// Unlike most built-ins, it is computed without a round trip to Postgres.
+func (e *executionContext) seededRandom(seed, n value) (value, error) {
+	if seed.Null() || n.Null() {
+		return makeNull(types.IntType)
+	}
+
+	bound := n.RawValue().(int64)
+	if bound <= 0 {
+		return nil, errors.New("seeded_random: n must be greater than 0")
+	}
+
+	var height int64
+	if e.engineCtx.TxContext.BlockContext != nil {
+		height = e.engineCtx.TxContext.BlockContext.Height
+	}
+
+	rng := newXoshiro256(uint64(seed.RawValue().(int64)), uint64(height))
+	return makeInt8(int64(rng.bounded(uint64(bound)))), nil
+}
+
+// uuidGenerate implements the uuid_generate built-in. It returns a random
+// version 4 UUID. Since the result differs on every node, it cannot be used by
+// calls that can change state; uuid_generate_kwil returns the same UUID on
+// every node.
+func (e *executionContext) uuidGenerate() (value, error) {
+	if e.canMutateState {
+		return nil, fmt.Errorf(`%w: "uuid_generate" cannot be used in calls that can change state`, engine.ErrIllegalFunctionUsage)
+	}
+
+	var u types.UUID
+	if _, err := rand.Read(u[:]); err != nil {
+		return nil, err
+	}
+	u[6] = (u[6] & 0x0f) | 0x40 // version 4
+	u[8] = (u[8] & 0x3f) | 0x80 // RFC 4122 variant
+
+	return makeUUID(&u), nil
+}
+
+// xoshiro256 is the xoshiro256** pseudo-random number generator.
+// See https://prng.di.unimi.it/xoshiro256starstar.c.
+type xoshiro256 struct {
+	s [4]uint64
+}
+
+// newXoshiro256 returns a generator whose state is derived from the seed and
+// the block height with splitmix64, which is the recommended way to seed
+// xoshiro256**, and never yields the all-zero state.
+func newXoshiro256(seed, height uint64) *xoshiro256 {
+	x := seed ^ bits.RotateLeft64(height, 32)
+
+	var r xoshiro256
+	for i := range r.s {
+		x += 0x9e3779b97f4a7c15
+		z := x
+		z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+		z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+		r.s[i] = z ^ (z >> 31)
+	}
+	return &r
+}
+
+// next returns the next number of the sequence.
+func (r *xoshiro256) next() uint64 {
+	result := bits.RotateLeft64(r.s[1]*5, 7) * 9
+	t := r.s[1] << 17
+
+	r.s[2] ^= r.s[0]
+	r.s[3] ^= r.s[1]
+	r.s[1] ^= r.s[2]
+	r.s[0] ^= r.s[3]
+
+	r.s[2] ^= t
+	r.s[3] = bits.RotateLeft64(r.s[3], 45)
+
+	return result
+}
+
+// bounded returns a number in [0, n), without the bias of taking the modulus,
+// by rejecting the numbers that would make some results more likely.
+func (r *xoshiro256) bounded(n uint64) uint64 {
+	// products whose low bits are below 2^64 mod n are rejected, so that
+	// every result is equally likely
+	limit := -n % n
+	for {
+		hi, lo := bits.Mul64(r.next(), n)
+		if lo >= limit {
+			return hi
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package interpreter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kwilteam/kwil-db/common"
+	"github.com/kwilteam/kwil-db/core/types"
+	"github.com/kwilteam/kwil-db/node/engine"
+)
+
+func Test_Xoshiro256(t *testing.T) {
+	// the outputs of the reference implementation for the state {1, 2, 3, 4}
+	r := &xoshiro256{s: [4]uint64{1, 2, 3, 4}}
+	for _, want := range []uint64{11520, 0, 1509978240, 1215971899390074240} {
+		require.Equal(t, want, r.next())
+	}
+}
+
+func Test_SeededRandom(t *testing.T) {
+	atHeight := func(height int64) *executionContext {
+		return &executionContext{engineCtx: &common.EngineContext{
+			TxContext: &common.TxContext{BlockContext: &common.BlockContext{Height: height}},
+		}}
+	}
+
+	random := func(e *executionContext, seed, n int64) int64 {
+		v, err := e.seededRandom(makeInt8(seed), makeInt8(n))
+		require.NoError(t, err)
+		return v.RawValue().(int64)
+	}
+
+	// the number is determined by the seed and the block height
+	require.Equal(t, random(atHeight(10), 42, 1000000), random(atHeight(10), 42, 1000000))
+	require.NotEqual(t, random(atHeight(10), 42, 1000000), random(atHeight(11), 42, 1000000))
+	require.NotEqual(t, random(atHeight(10), 42, 1000000), random(atHeight(10), 43, 1000000))
+
+	for seed := range int64(100) {
+		v := random(atHeight(10), seed, 6)
+		require.GreaterOrEqual(t, v, int64(0))
+		require.Less(t, v, int64(6))
+	}
+
+	_, err := atHeight(10).seededRandom(makeInt8(42), makeInt8(0))
+	require.Error(t, err)
+
+	null, err := makeNull(types.IntType)
+	require.NoError(t, err)
+	v, err := atHeight(10).seededRandom(null, makeInt8(6))
+	require.NoError(t, err)
+	require.True(t, v.Null())
+}
+
+func Test_UUIDGenerate(t *testing.T) {
+	e := &executionContext{}
+
+	a, err := e.uuidGenerate()
+	require.NoError(t, err)
+	b, err := e.uuidGenerate()
+	require.NoError(t, err)
+	require.NotEqual(t, a.RawValue(), b.RawValue())
+
+	u := a.RawValue().(*types.UUID)
+	require.Equal(t, byte(0x40), u[6]&0xf0)
+	require.Equal(t, byte(0x80), u[8]&0xc0)
+
+	// the result differs on every node
+	e.canMutateState = true
+	_, err = e.uuidGenerate()
+	require.ErrorIs(t, err, engine.ErrIllegalFunctionUsage)
+}
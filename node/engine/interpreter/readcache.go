@@ -0,0 +1,367 @@
+package interpreter
+
+import (
+	"bytes"
+	"encoding/hex"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kwilteam/kwil-db/common"
+	"github.com/kwilteam/kwil-db/core/types"
+	"github.com/kwilteam/kwil-db/node/engine"
+	"github.com/kwilteam/kwil-db/node/types/sql"
+)
+
+// WithReadCache caches the results of read-only calls to the actions of the
+// namespace for ttl. The cached results of a namespace are invalidated when a
+// block that changes any of its tables is committed, and all results are
+// invalidated when a block that executes a statement is committed. It can be
+// given once for each namespace to cache.
+func WithReadCache(namespace string, ttl time.Duration) InterpreterOpt {
+	return func(o *InterpreterOptions) {
+		if o.ReadCache == nil {
+			o.ReadCache = make(map[string]time.Duration)
+		}
+		o.ReadCache[strings.ToLower(namespace)] = ttl
+	}
+}
+
+// WithClock sets the clock used to expire the results of the read cache. It
+// is only needed by tests.
+func WithClock(clock func() time.Time) InterpreterOpt {
+	return func(o *InterpreterOptions) {
+		o.Clock = clock
+	}
+}
+
+// actionCallKey identifies a call to an action by its arguments and the block
+// it is made at, since actions can read the block's context.
+type actionCallKey struct {
+	namespace string
+	action    string
+	height    int64
+	timestamp int64
+	// args is the hex encoded hash of the caller and arguments of the call.
+	args string
+}
+
+// CachedResult is the result of a read-only call held by the read cache.
+type CachedResult struct {
+	// Rows are the rows returned by the call.
+	Rows []*common.Row
+	// Logs are the logs of the call.
+	Logs []string
+	// GasUsed is the gas used by the call.
+	GasUsed uint64
+	// Expires is when the result is evicted.
+	Expires time.Time
+}
+
+// replay passes copies of the cached rows to fn, and returns the result of
+// the call.
+func (c *CachedResult) replay(fn func(*common.Row) error) (*common.CallResult, error) {
+	if fn != nil {
+		for _, row := range c.Rows {
+			if err := fn(cloneRow(row)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return &common.CallResult{
+		Logs:    slices.Clone(c.Logs),
+		GasUsed: c.GasUsed,
+	}, nil
+}
+
+// cloneRow copies a row, so that the rows held by the cache are never shared
+// with callers, which may modify them.
+func cloneRow(row *common.Row) *common.Row {
+	values := make([]any, len(row.Values))
+	for i, v := range row.Values {
+		values[i] = cloneValue(v)
+	}
+
+	return &common.Row{
+		ColumnNames: slices.Clone(row.ColumnNames),
+		ColumnTypes: slices.Clone(row.ColumnTypes),
+		Values:      values,
+	}
+}
+
+// cloneValue copies the memory referenced by a row value.
+func cloneValue(v any) any {
+	switch v := v.(type) {
+	case []byte:
+		return bytes.Clone(v)
+	case *types.UUID:
+		return cloneUUID(v)
+	case *types.Decimal:
+		return cloneDecimal(v)
+	case []*string:
+		return clonePtrs(v)
+	case []*int64:
+		return clonePtrs(v)
+	case []*bool:
+		return clonePtrs(v)
+	case [][]byte:
+		c := make([][]byte, len(v))
+		for i, b := range v {
+			c[i] = bytes.Clone(b)
+		}
+		return c
+	case []*types.UUID:
+		c := make([]*types.UUID, len(v))
+		for i, u := range v {
+			c[i] = cloneUUID(u)
+		}
+		return c
+	case []*types.Decimal:
+		c := make([]*types.Decimal, len(v))
+		for i, d := range v {
+			c[i] = cloneDecimal(d)
+		}
+		return c
+	default:
+		return v
+	}
+}
+
+// clonePtrs copies a slice of pointers, and the values they point to.
+func clonePtrs[T any](s []*T) []*T {
+	c := make([]*T, len(s))
+	for i, p := range s {
+		if p != nil {
+			v := *p
+			c[i] = &v
+		}
+	}
+	return c
+}
+
+func cloneUUID(u *types.UUID) *types.UUID {
+	if u == nil {
+		return nil
+	}
+	c := *u
+	return &c
+}
+
+func cloneDecimal(d *types.Decimal) *types.Decimal {
+	if d == nil {
+		return nil
+	}
+	c, err := types.ParseDecimalExplicit(d.String(), d.Precision(), d.Scale())
+	if err != nil {
+		// NaN cannot be parsed, and is never modified in place
+		return d
+	}
+	return c
+}
+
+// readCache caches the results of read-only calls to the actions of the
+// namespaces it is enabled for. It is safe for concurrent use, since read-only
+// calls are made concurrently.
+type readCache struct {
+	// ttls are how long the results of each cached namespace are kept.
+	ttls map[string]time.Duration
+	// now returns the current time. It is time.Now, other than in tests.
+	now func() time.Time
+
+	mu      sync.Mutex
+	results map[actionCallKey]*CachedResult
+	// stale is true if a statement was executed by the block being
+	// executed, so that all results are invalidated once it is committed.
+	stale bool
+}
+
+// newReadCache creates the read cache of the options. It returns nil if no
+// namespace is cached.
+func newReadCache(opts *InterpreterOptions) *readCache {
+	if len(opts.ReadCache) == 0 {
+		return nil
+	}
+
+	now := opts.Clock
+	if now == nil {
+		now = time.Now
+	}
+
+	return &readCache{
+		ttls:    opts.ReadCache,
+		now:     now,
+		results: make(map[actionCallKey]*CachedResult),
+	}
+}
+
+// key returns the key of a call made at the block, and whether its namespace
+// is cached. The caller is part of the key, since actions can return different
+// results to different callers. The block may be nil.
+func (c *readCache) key(caller, namespace, action string, block *common.BlockContext, args []any) (actionCallKey, bool, error) {
+	if namespace == "" {
+		namespace = engine.DefaultNamespace
+	}
+	namespace = strings.ToLower(namespace)
+	if _, ok := c.ttls[namespace]; !ok {
+		return actionCallKey{}, false, nil
+	}
+
+	argVals := make([]value, len(args))
+	for i, arg := range args {
+		val, err := newValue(arg)
+		if err != nil {
+			return actionCallKey{}, false, err
+		}
+		argVals[i] = val
+	}
+
+	h, err := idempotencyKey(caller, "", "", argVals)
+	if err != nil {
+		return actionCallKey{}, false, err
+	}
+
+	key := actionCallKey{
+		namespace: namespace,
+		action:    strings.ToLower(action),
+		args:      hex.EncodeToString(h),
+	}
+	if block != nil {
+		key.height = block.Height
+		key.timestamp = block.Timestamp
+	}
+	return key, true, nil
+}
+
+// get returns the cached result of a call, evicting it if it has expired. It
+// returns nil if the result is not cached.
+func (c *readCache) get(key actionCallKey) *CachedResult {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	res, ok := c.results[key]
+	if !ok {
+		return nil
+	}
+	if !c.now().Before(res.Expires) {
+		delete(c.results, key)
+		return nil
+	}
+	return res
+}
+
+// put caches the result of a call until the ttl of its namespace passes.
+func (c *readCache) put(key actionCallKey, res *CachedResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	res.Expires = now.Add(c.ttls[key.namespace])
+	c.results[key] = res
+
+	// expired results that are not called again are evicted as results are
+	// added, so that they do not accumulate
+	for k, r := range c.results {
+		if !now.Before(r.Expires) {
+			delete(c.results, k)
+		}
+	}
+}
+
+// invalidate evicts the cached results of the namespaces whose tables were
+// changed by the events.
+func (c *readCache) invalidate(events []CDCEvent) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, event := range events {
+		ns := strings.ToLower(event.Namespace)
+		if _, ok := c.ttls[ns]; !ok {
+			continue
+		}
+		for k := range c.results {
+			if k.namespace == ns {
+				delete(c.results, k)
+			}
+		}
+	}
+}
+
+// invalidateAll evicts all cached results.
+func (c *readCache) invalidateAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	clear(c.results)
+}
+
+// markStale marks all cached results to be invalidated once the block being
+// executed is committed.
+func (c *readCache) markStale() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.stale = true
+}
+
+// commit invalidates the cached results made stale by the committed block,
+// whose changes are the events.
+func (c *readCache) commit(events []CDCEvent) {
+	c.mu.Lock()
+	stale := c.stale
+	c.stale = false
+	c.mu.Unlock()
+
+	if stale {
+		c.invalidateAll()
+		return
+	}
+	c.invalidate(events)
+}
+
+// rollback forgets that the block that was rolled back made results stale.
+func (c *readCache) rollback() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.stale = false
+}
+
+// cachedCall calls an action, or returns its cached result if it is cached.
+// Only the successful calls made with a read-only database are cached, so
+// calls made by transactions never depend on the cache. It must be called
+// with the lock held.
+func (t *ThreadSafeInterpreter) cachedCall(ctx *common.EngineContext, db sql.DB, namespace string, action string, args []any, resultFn func(*common.Row) error) (*common.CallResult, error) {
+	if am, ok := db.(sql.AccessModer); !ok || am.AccessMode() != sql.ReadOnly || ctx.Simulate {
+		return t.i.call(ctx, db, namespace, action, args, resultFn, true, nil)
+	}
+
+	key, ok, err := t.readCache.key(ctx.TxContext.Caller, namespace, action, ctx.TxContext.BlockContext, args)
+	if err != nil || !ok {
+		// calls whose arguments cannot be hashed are not cached
+		return t.i.call(ctx, db, namespace, action, args, resultFn, true, nil)
+	}
+
+	if cached := t.readCache.get(key); cached != nil {
+		return cached.replay(resultFn)
+	}
+
+	cached := &CachedResult{}
+	res, err := t.i.call(ctx, db, namespace, action, args, func(row *common.Row) error {
+		cached.Rows = append(cached.Rows, cloneRow(row))
+		if resultFn != nil {
+			return resultFn(row)
+		}
+		return nil
+	}, true, nil)
+	if err != nil || res.Error != nil {
+		return res, err
+	}
+
+	cached.Logs = slices.Clone(res.Logs)
+	cached.GasUsed = res.GasUsed
+	t.readCache.put(key, cached)
+
+	return res, nil
+}
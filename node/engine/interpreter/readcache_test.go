@@ -0,0 +1,115 @@
+package interpreter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kwilteam/kwil-db/common"
+	"github.com/kwilteam/kwil-db/node/wal"
+)
+
+func Test_ReadCache(t *testing.T) {
+	now := time.Unix(1000, 0)
+	opts := &InterpreterOptions{}
+	for _, opt := range []InterpreterOpt{
+		WithReadCache("Main", time.Minute),
+		WithReadCache("other", time.Hour),
+		WithClock(func() time.Time { return now }),
+	} {
+		opt(opts)
+	}
+	c := newReadCache(opts)
+
+	block := &common.BlockContext{Height: 5, Timestamp: 1000}
+	key := func(caller, namespace, action string, args ...any) actionCallKey {
+		k, ok, err := c.key(caller, namespace, action, block, args)
+		require.NoError(t, err)
+		require.True(t, ok)
+		return k
+	}
+	result := func(v int64) *CachedResult {
+		return &CachedResult{Rows: []*common.Row{{ColumnNames: []string{"v"}, Values: []any{v}}}}
+	}
+	rows := func(res *CachedResult) []any {
+		var vals []any
+		_, err := res.replay(func(row *common.Row) error {
+			vals = append(vals, row.Values...)
+			return nil
+		})
+		require.NoError(t, err)
+		return vals
+	}
+
+	// namespaces without a ttl are not cached
+	_, ok, err := c.key("alice", "unknown", "get", block, nil)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	// the namespace defaults to main, and names are case insensitive
+	require.Equal(t, key("alice", "", "get", int64(1)), key("alice", "MAIN", "GET", int64(1)))
+
+	// calls are cached by caller and arguments
+	c.put(key("alice", "main", "get", int64(1)), result(10))
+	require.Equal(t, []any{int64(10)}, rows(c.get(key("alice", "main", "get", int64(1)))))
+	require.Nil(t, c.get(key("alice", "main", "get", int64(2))))
+	require.Nil(t, c.get(key("bob", "main", "get", int64(1))))
+
+	// calls made at another block are not shared, since actions can read the
+	// block's context
+	block = &common.BlockContext{Height: 6, Timestamp: 1000}
+	require.Nil(t, c.get(key("alice", "main", "get", int64(1))))
+	block = &common.BlockContext{Height: 5, Timestamp: 1000}
+
+	// replayed rows are copies of the cached rows
+	_, err = c.get(key("alice", "main", "get", int64(1))).replay(func(row *common.Row) error {
+		row.Values[0] = int64(11)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []any{int64(10)}, rows(c.get(key("alice", "main", "get", int64(1)))))
+
+	// a write to another namespace does not invalidate the namespace
+	c.put(key("alice", "other", "get"), result(20))
+	c.invalidate([]CDCEvent{{Namespace: "other", Table: "items", Operation: wal.OperationInsert}})
+	require.NotNil(t, c.get(key("alice", "main", "get", int64(1))))
+	require.Nil(t, c.get(key("alice", "other", "get")))
+
+	// a write to any table of the namespace invalidates all of its results
+	c.put(key("alice", "main", "list"), result(30))
+	c.put(key("alice", "other", "get"), result(20))
+	c.invalidate([]CDCEvent{{Namespace: "main", Table: "users", Operation: wal.OperationDelete}})
+	require.Nil(t, c.get(key("alice", "main", "get", int64(1))))
+	require.Nil(t, c.get(key("alice", "main", "list")))
+	require.NotNil(t, c.get(key("alice", "other", "get")))
+
+	// results are evicted once the ttl of their namespace passes
+	c.put(key("alice", "main", "get", int64(1)), result(10))
+	now = now.Add(59 * time.Second)
+	require.NotNil(t, c.get(key("alice", "main", "get", int64(1))))
+	now = now.Add(time.Second)
+	require.Nil(t, c.get(key("alice", "main", "get", int64(1))))
+	require.NotNil(t, c.get(key("alice", "other", "get")))
+
+	// expired results are also evicted when others are added
+	c.put(key("alice", "main", "get", int64(1)), result(10))
+	now = now.Add(time.Minute)
+	c.put(key("alice", "main", "list"), result(30))
+	require.Len(t, c.results, 2)
+
+	// committing a block invalidates the namespaces changed by it, or all
+	// results if it executed a statement
+	c.commit([]CDCEvent{{Namespace: "main", Table: "users", Operation: wal.OperationInsert}})
+	require.Nil(t, c.get(key("alice", "main", "list")))
+	require.NotNil(t, c.get(key("alice", "other", "get")))
+
+	c.markStale()
+	c.rollback()
+	c.commit(nil)
+	require.NotNil(t, c.get(key("alice", "other", "get")))
+
+	c.markStale()
+	c.commit(nil)
+	require.Empty(t, c.results)
+}
@@ -0,0 +1,51 @@
+package interpreter
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kwilteam/kwil-db/node/engine"
+	"github.com/kwilteam/kwil-db/node/types/sql"
+)
+
+// SetNamespaceReadOnly sets whether a namespace is read-only. While it is,
+// only its view actions can be called. It takes effect for calls made after
+// it returns.
+func (t *ThreadSafeInterpreter) SetNamespaceReadOnly(ctx context.Context, db sql.DB, namespace string, readOnly bool) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	ns, ok := t.i.namespaces[namespace]
+	if !ok {
+		return fmt.Errorf(`%w: "%s"`, engine.ErrNamespaceNotFound, namespace)
+	}
+
+	if err := setReadOnly(ctx, db, namespace, readOnly); err != nil {
+		return err
+	}
+
+	ns.readOnly = readOnly
+	return nil
+}
+
+// setReadOnly stores whether a namespace is read-only.
+func setReadOnly(ctx context.Context, db sql.DB, namespace string, readOnly bool) error {
+	return execute(ctx, db, `INSERT INTO kwild_engine.namespace_config (namespace, read_only) VALUES ($1, $2)
+	ON CONFLICT (namespace) DO UPDATE SET read_only = $2`, namespace, readOnly)
+}
+
+// listReadOnlyNamespaces lists the namespaces that are read-only.
+func listReadOnlyNamespaces(ctx context.Context, db sql.DB) (map[string]bool, error) {
+	readOnly := make(map[string]bool)
+	var namespace string
+	err := queryRowFunc(ctx, db, `SELECT namespace FROM kwild_engine.namespace_config WHERE read_only`,
+		[]any{&namespace}, func() error {
+			readOnly[namespace] = true
+			return nil
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	return readOnly, nil
+}
@@ -0,0 +1,86 @@
+package interpreter
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/kwilteam/kwil-db/common"
+	"github.com/kwilteam/kwil-db/core/types"
+	authExt "github.com/kwilteam/kwil-db/extensions/auth"
+	"github.com/kwilteam/kwil-db/node/types/sql"
+)
+
+// callRefund refunds the caller of a call the part of the fee that the call
+// did not use, if the call fails. The call runs in tx, which is nested in db,
+// so that the changes of the call can be rolled back and the refund credited
+// in db.
+type callRefund struct {
+	ctx      *common.EngineContext
+	db       sql.DB
+	tx       sql.Tx
+	accounts common.Accounts
+	// gasUsed is the gas used by the call, which is counted by its execution
+	// context.
+	gasUsed *uint64
+}
+
+// startRefund begins the nested transaction of a call that refunds its caller
+// if it fails.
+func (i *baseInterpreter) startRefund(ctx *common.EngineContext, db sql.DB, gasUsed *uint64) (*callRefund, error) {
+	if i.accounts == nil {
+		return nil, errors.New("cannot refund failed calls without accounts")
+	}
+	if ctx.InvalidTxCtx {
+		return nil, errors.New("cannot refund a call without a transaction")
+	}
+
+	tx, err := db.BeginTx(ctx.TxContext.Ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &callRefund{
+		ctx:      ctx,
+		db:       db,
+		tx:       tx,
+		accounts: i.accounts,
+		gasUsed:  gasUsed,
+	}, nil
+}
+
+// finish commits the nested transaction of the call if it succeeded.
+// Otherwise, it rolls it back and credits the refund to the caller.
+func (r *callRefund) finish(failed bool) error {
+	ctx := r.ctx.TxContext.Ctx
+	if !failed {
+		return r.tx.Commit(ctx)
+	}
+	if err := r.tx.Rollback(ctx); err != nil {
+		return err
+	}
+
+	amt := refundAmount(r.ctx.GasLimit, *r.gasUsed, r.ctx.GasPrice)
+	if amt.Sign() == 0 {
+		return nil
+	}
+
+	keyType, err := authExt.GetAuthenticatorKeyType(r.ctx.TxContext.Authenticator)
+	if err != nil {
+		return err
+	}
+
+	return r.accounts.Credit(ctx, r.db, &types.AccountID{
+		Identifier: r.ctx.TxContext.Signer,
+		KeyType:    keyType,
+	}, amt)
+}
+
+// refundAmount returns the part of a fee of gasLimit * gasPrice that was not
+// used. It is zero if all of the gas was used.
+func refundAmount(gasLimit, gasUsed uint64, gasPrice *big.Int) *big.Int {
+	if gasPrice == nil || gasUsed >= gasLimit {
+		return new(big.Int)
+	}
+
+	return new(big.Int).Mul(new(big.Int).SetUint64(gasLimit-gasUsed), gasPrice)
+}
@@ -0,0 +1,18 @@
+package interpreter
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_RefundAmount(t *testing.T) {
+	require.Equal(t, big.NewInt(800), refundAmount(10, 2, big.NewInt(100)))
+	require.Equal(t, big.NewInt(1000), refundAmount(10, 0, big.NewInt(100)))
+
+	// nothing is refunded if all of the gas was used, or there is no price
+	require.Zero(t, refundAmount(10, 10, big.NewInt(100)).Sign())
+	require.Zero(t, refundAmount(10, 12, big.NewInt(100)).Sign())
+	require.Zero(t, refundAmount(10, 2, nil).Sign())
+}
@@ -0,0 +1,178 @@
+package interpreter
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kwilteam/kwil-db/common"
+	"github.com/kwilteam/kwil-db/node/engine"
+	"github.com/kwilteam/kwil-db/node/metrics"
+)
+
+// baselineSamples is the number of recent call durations of an action that its
+// P95 duration is computed from.
+const baselineSamples = 100
+
+// RegressionEntry compares the recent durations of calls to an action with its
+// baseline.
+type RegressionEntry struct {
+	Namespace string
+	Action    string
+	// Expected is the expected duration of a call, and Tolerance the fraction
+	// by which a call may exceed it before it is a regression.
+	Expected  time.Duration
+	Tolerance float64
+	// P95 is the 95th percentile duration of the recent calls. It is zero if
+	// there have been no calls.
+	P95 time.Duration
+	// Calls is the number of calls since the baseline was registered, and
+	// Regressions the number of them that took longer than allowed.
+	Calls       int64
+	Regressions int64
+}
+
+// callBaselines are the registered duration baselines of actions.
+type callBaselines struct {
+	mu        sync.Mutex
+	baselines map[baselineKey]*callBaseline
+}
+
+// baselineKey identifies an action.
+type baselineKey struct {
+	namespace string
+	action    string
+}
+
+// callBaseline is the baseline of an action and its recent call durations.
+type callBaseline struct {
+	expected  time.Duration
+	tolerance float64
+	// durations are the most recent call durations, used as a ring buffer.
+	durations []time.Duration
+	// next is the position of durations that the next duration is written to.
+	next        int
+	calls       int64
+	regressions int64
+}
+
+func newCallBaselines() *callBaselines {
+	return &callBaselines{
+		baselines: make(map[baselineKey]*callBaseline),
+	}
+}
+
+// newBaselineKey returns the key of an action, using the same default namespace
+// and case as calls.
+func newBaselineKey(namespace, action string) baselineKey {
+	if namespace == "" {
+		namespace = engine.DefaultNamespace
+	}
+	return baselineKey{namespace: strings.ToLower(namespace), action: strings.ToLower(action)}
+}
+
+// record records the duration of a call to an action, returning true if the
+// action has a baseline that the call exceeded.
+func (c *callBaselines) record(key baselineKey, d time.Duration) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	b, ok := c.baselines[key]
+	if !ok {
+		return false
+	}
+
+	if len(b.durations) < baselineSamples {
+		b.durations = append(b.durations, d)
+	} else {
+		b.durations[b.next] = d
+	}
+	b.next = (b.next + 1) % baselineSamples
+	b.calls++
+
+	if float64(d) <= float64(b.expected)*(1+b.tolerance) {
+		return false
+	}
+	b.regressions++
+	return true
+}
+
+// RegisterBaseline registers the expected duration of calls to an action. A
+// call that takes longer than expectedDuration * (1 + tolerance) is counted as
+// a regression, and logged as a warning. Registering a baseline for an action
+// that already has one replaces it, and resets its statistics.
+func (t *ThreadSafeInterpreter) RegisterBaseline(namespace, action string, expectedDuration time.Duration, tolerance float64) error {
+	if expectedDuration <= 0 {
+		return errors.New("expected duration must be positive")
+	}
+	if tolerance < 0 {
+		return errors.New("tolerance cannot be negative")
+	}
+
+	t.baselines.mu.Lock()
+	defer t.baselines.mu.Unlock()
+
+	t.baselines.baselines[newBaselineKey(namespace, action)] = &callBaseline{
+		expected:  expectedDuration,
+		tolerance: tolerance,
+	}
+	return nil
+}
+
+// GetRegressionReport returns the baseline and recent P95 duration of every
+// action with a baseline, ordered by namespace and action.
+func (t *ThreadSafeInterpreter) GetRegressionReport() []RegressionEntry {
+	t.baselines.mu.Lock()
+	defer t.baselines.mu.Unlock()
+
+	report := make([]RegressionEntry, 0, len(t.baselines.baselines))
+	for key, b := range t.baselines.baselines {
+		report = append(report, RegressionEntry{
+			Namespace:   key.namespace,
+			Action:      key.action,
+			Expected:    b.expected,
+			Tolerance:   b.tolerance,
+			P95:         p95(b.durations),
+			Calls:       b.calls,
+			Regressions: b.regressions,
+		})
+	}
+
+	slices.SortFunc(report, func(a, b RegressionEntry) int {
+		if c := strings.Compare(a.Namespace, b.Namespace); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Action, b.Action)
+	})
+	return report
+}
+
+// p95 returns the 95th percentile of durations, using the nearest rank.
+func p95(durations []time.Duration) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+
+	sorted := slices.Clone(durations)
+	slices.Sort(sorted)
+	rank := (len(sorted)*95 + 99) / 100 // ceil(0.95 * n)
+	return sorted[rank-1]
+}
+
+// recordDuration records the duration of a call that started at start, logging
+// a warning if the action has a baseline that the call exceeded.
+func (t *ThreadSafeInterpreter) recordDuration(ctx *common.EngineContext, namespace, action string, start time.Time) {
+	d := time.Since(start)
+	key := newBaselineKey(namespace, action)
+	if !t.baselines.record(key, d) {
+		return
+	}
+
+	metrics.Engine.PerformanceRegression(ctx.TxContext.Ctx, key.namespace, key.action)
+	if t.i.service != nil && t.i.service.Logger != nil {
+		t.i.service.Logger.Warn("action call exceeded its duration baseline", "namespace", key.namespace,
+			"action", key.action, "duration", d)
+	}
+}
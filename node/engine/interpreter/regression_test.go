@@ -0,0 +1,56 @@
+package interpreter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kwilteam/kwil-db/common"
+)
+
+func Test_RegressionBaselines(t *testing.T) {
+	interp := &ThreadSafeInterpreter{
+		i:         &baseInterpreter{},
+		baselines: newCallBaselines(),
+	}
+	engCtx := &common.EngineContext{TxContext: &common.TxContext{Ctx: context.Background()}}
+
+	require.Error(t, interp.RegisterBaseline("main", "slow", 0, 0.1))
+	require.Error(t, interp.RegisterBaseline("main", "slow", time.Millisecond, -1))
+	require.NoError(t, interp.RegisterBaseline("", "Slow", time.Millisecond, 0.5))
+	require.NoError(t, interp.RegisterBaseline("main", "fast", time.Second, 0))
+
+	// a call that takes 10ms exceeds a 1ms baseline
+	interp.recordDuration(engCtx, "main", "slow", time.Now().Add(-10*time.Millisecond))
+	interp.recordDuration(engCtx, "main", "fast", time.Now().Add(-10*time.Millisecond))
+	// actions without a baseline are not recorded
+	interp.recordDuration(engCtx, "main", "other", time.Now().Add(-10*time.Millisecond))
+
+	report := interp.GetRegressionReport()
+	require.Len(t, report, 2)
+
+	require.Equal(t, "fast", report[0].Action)
+	require.EqualValues(t, 1, report[0].Calls)
+	require.Zero(t, report[0].Regressions)
+
+	slow := report[1]
+	require.Equal(t, "main", slow.Namespace)
+	require.Equal(t, "slow", slow.Action)
+	require.Equal(t, time.Millisecond, slow.Expected)
+	require.EqualValues(t, 1, slow.Calls)
+	require.EqualValues(t, 1, slow.Regressions)
+	require.GreaterOrEqual(t, slow.P95, 10*time.Millisecond)
+}
+
+func Test_P95(t *testing.T) {
+	require.Zero(t, p95(nil))
+
+	var durations []time.Duration
+	for i := 100; i >= 1; i-- {
+		durations = append(durations, time.Duration(i)*time.Millisecond)
+	}
+	require.Equal(t, 95*time.Millisecond, p95(durations))
+	require.Equal(t, 7*time.Millisecond, p95([]time.Duration{7 * time.Millisecond, time.Millisecond}))
+}
@@ -0,0 +1,74 @@
+package interpreter
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/kwilteam/kwil-db/node/engine/parse"
+)
+
+// warnRenamedColumn warns about the actions of the current namespace that
+// reference a column that was renamed. Their statements are not rewritten, so
+// they fail once they use the old name. The rename is not blocked, since the
+// actions can be replaced after it.
+func (e *executionContext) warnRenamedColumn(table, column string) error {
+	actions, err := listActionsInBuiltInNamespace(e.engineCtx.TxContext.Ctx, e.db, e.scope.namespace)
+	if err != nil {
+		return err
+	}
+
+	ns := e.interpreter.namespaces[e.scope.namespace]
+	available := actions[:0]
+	for _, act := range actions {
+		if _, ok := ns.availableFunctions[act.Name]; ok {
+			available = append(available, act)
+		}
+	}
+
+	for _, name := range actionsReferencingColumn(available, table, column) {
+		msg := fmt.Sprintf(`warning: action "%s" references column "%s" of table "%s", which was renamed`, name, column, table)
+		*e.logs = append(*e.logs, msg)
+		if e.interpreter.service != nil && e.interpreter.service.Logger != nil {
+			e.interpreter.service.Logger.Warn(msg, "namespace", e.scope.namespace)
+		}
+	}
+
+	return nil
+}
+
+// actionsReferencingColumn returns the sorted names of the actions whose
+// statements use a table and a column with the given name. Columns are not
+// resolved to their tables, so an action can be reported if it uses a column
+// with the same name in another table.
+func actionsReferencingColumn(actions []*action, table, column string) []string {
+	var names []string
+	for _, act := range actions {
+		var usesTable, usesColumn bool
+		for _, stmt := range act.Body {
+			parse.RecursivelyVisitPositions(stmt, func(gp parse.GetPositioner) {
+				switch n := gp.(type) {
+				case *parse.RelationTable:
+					usesTable = usesTable || n.Table == table
+				case *parse.InsertStatement:
+					usesTable = usesTable || n.Table == table
+					usesColumn = usesColumn || slices.Contains(n.Columns, column)
+				case *parse.UpdateStatement:
+					usesTable = usesTable || n.Table == table
+				case *parse.DeleteStatement:
+					usesTable = usesTable || n.Table == table
+				case *parse.UpdateSetClause:
+					usesColumn = usesColumn || n.Column == column
+				case *parse.ExpressionColumn:
+					usesColumn = usesColumn || n.Column == column
+				}
+			})
+		}
+
+		if usesTable && usesColumn {
+			names = append(names, act.Name)
+		}
+	}
+
+	slices.Sort(names)
+	return names
+}
@@ -0,0 +1,31 @@
+package interpreter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kwilteam/kwil-db/node/engine/parse"
+)
+
+func Test_ActionsReferencingColumn(t *testing.T) {
+	var actions []*action
+	for _, raw := range []string{
+		`CREATE ACTION get_name($id int) public view { SELECT name FROM users WHERE id = $id; };`,
+		`CREATE ACTION set_name($id int, $name text) public { UPDATE users SET name = $name WHERE id = $id; };`,
+		`CREATE ACTION add_user($id int, $name text) public { INSERT INTO users (id, name) VALUES ($id, $name); };`,
+		`CREATE ACTION get_post_name($id int) public view { SELECT name FROM posts WHERE id = $id; };`,
+		`CREATE ACTION count_users() public view { SELECT count(*) FROM users; };`,
+	} {
+		stmts, err := parse.Parse(raw)
+		require.NoError(t, err)
+
+		act := &action{}
+		require.NoError(t, act.FromAST(stmts[0].(*parse.CreateActionStatement)))
+		actions = append(actions, act)
+	}
+
+	require.Equal(t, []string{"add_user", "get_name", "set_name"}, actionsReferencingColumn(actions, "users", "name"))
+	require.Equal(t, []string{"get_post_name"}, actionsReferencingColumn(actions, "posts", "name"))
+	require.Empty(t, actionsReferencingColumn(actions, "users", "age"))
+}
@@ -0,0 +1,166 @@
+package interpreter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/kwilteam/kwil-db/common"
+	"github.com/kwilteam/kwil-db/core/log"
+	"github.com/kwilteam/kwil-db/core/types"
+	authExt "github.com/kwilteam/kwil-db/extensions/auth"
+	"github.com/kwilteam/kwil-db/node/engine"
+	"github.com/kwilteam/kwil-db/node/types/sql"
+)
+
+// BlockStore provides the committed blocks whose transactions are replayed by
+// ReplayTransactions.
+type BlockStore interface {
+	GetByHeight(height int64) (types.Hash, *types.Block, *types.CommitInfo, error)
+}
+
+// WithBlockStore sets the block store that ReplayTransactions reads
+// transactions from.
+func WithBlockStore(bs BlockStore) InterpreterOpt {
+	return func(o *InterpreterOptions) {
+		o.BlockStore = bs
+	}
+}
+
+// ReplayTransactions replays the action executions of the blocks from fromBlock
+// to toBlock, inclusive, that call actions in the namespace. They are replayed
+// in block order with the block context they were originally executed with,
+// but without charging fees or updating nonces.
+//
+// It is used for point-in-time recovery of a namespace: db should hold a clean
+// copy of the namespace as of the block before fromBlock, such as one restored
+// from a snapshot. Transactions whose signatures are invalid are skipped, as
+// are transactions whose action fails, since their changes were rolled back
+// when the block was executed. Only action executions are replayed; raw SQL
+// transactions are not, since the namespaces they touch are not known.
+func (t *ThreadSafeInterpreter) ReplayTransactions(ctx context.Context, db sql.DB, namespace string, fromBlock, toBlock int64) error {
+	if t.blocks == nil {
+		return errors.New("cannot replay transactions without a block store")
+	}
+	if fromBlock < 1 || toBlock < fromBlock {
+		return fmt.Errorf("invalid block range %d to %d", fromBlock, toBlock)
+	}
+
+	logger := log.DiscardLogger
+	if t.i.service != nil && t.i.service.Logger != nil {
+		logger = t.i.service.Logger
+	}
+
+	for height := fromBlock; height <= toBlock; height++ {
+		blockHash, block, _, err := t.blocks.GetByHeight(height)
+		if err != nil {
+			return fmt.Errorf("failed to get block %d: %w", height, err)
+		}
+
+		blockCtx := &common.BlockContext{
+			ChainContext: &common.ChainContext{
+				NetworkParameters: &common.NetworkParameters{},
+				MigrationParams:   &common.MigrationContext{},
+			},
+			Height:    height,
+			Hash:      blockHash,
+			Timestamp: block.Header.Timestamp.Unix(),
+		}
+
+		for _, tx := range block.Txns {
+			if tx.Body.PayloadType != types.PayloadTypeExecute {
+				continue
+			}
+			action := &types.ActionExecution{}
+			if err = action.UnmarshalBinary(tx.Body.Payload); err != nil {
+				continue
+			}
+			actionNamespace := action.Namespace
+			if actionNamespace == "" {
+				actionNamespace = engine.DefaultNamespace
+			}
+			if actionNamespace != namespace {
+				continue
+			}
+
+			txHash := tx.Hash()
+			if err = verifyReplayedTx(tx); err != nil {
+				logger.Warnf("skipping replayed transaction %s with invalid signature: %v", txHash, err)
+				continue
+			}
+
+			err = t.replayTx(ctx, db, blockCtx, tx, txHash, action)
+			if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+				return err
+			}
+			if err != nil {
+				logger.Debugf("replayed transaction %s failed: %v", txHash, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// verifyReplayedTx verifies the signature of a transaction.
+func verifyReplayedTx(tx *types.Transaction) error {
+	if tx.Signature == nil {
+		return errors.New("transaction is not signed")
+	}
+	msg, err := tx.SerializeMsg()
+	if err != nil {
+		return err
+	}
+
+	return authExt.VerifySignature(tx.Sender, msg, tx.Signature)
+}
+
+// replayTx calls the action of a transaction once for each set of arguments,
+// in a nested transaction that is only committed if every call succeeds.
+func (t *ThreadSafeInterpreter) replayTx(ctx context.Context, db sql.DB, blockCtx *common.BlockContext, tx *types.Transaction, txHash types.Hash, action *types.ActionExecution) error {
+	caller, err := authExt.GetIdentifier(tx.Signature.Type, tx.Sender)
+	if err != nil {
+		return err
+	}
+
+	args := make([][]any, len(action.Arguments))
+	for i, tuple := range action.Arguments {
+		args[i] = make([]any, len(tuple))
+		for j, val := range tuple {
+			if args[i][j], err = val.Decode(); err != nil {
+				return err
+			}
+		}
+	}
+	// an execution without arguments calls the action once
+	if len(args) == 0 {
+		args = make([][]any, 1)
+	}
+
+	nested, err := db.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer nested.Rollback(ctx)
+
+	for _, tuple := range args {
+		res, err := t.Call(&common.EngineContext{
+			TxContext: &common.TxContext{
+				Ctx:           ctx,
+				BlockContext:  blockCtx,
+				TxID:          txHash.String(),
+				Signer:        tx.Sender,
+				Caller:        caller,
+				Authenticator: tx.Signature.Type,
+			},
+		}, nested, action.Namespace, action.Action, tuple, nil)
+		if err != nil {
+			return err
+		}
+		if res.Error != nil {
+			return res.Error
+		}
+	}
+
+	return nested.Commit(ctx)
+}
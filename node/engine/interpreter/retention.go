@@ -0,0 +1,160 @@
+package interpreter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/kwilteam/kwil-db/common"
+	"github.com/kwilteam/kwil-db/core/types"
+	"github.com/kwilteam/kwil-db/extensions/hooks"
+	"github.com/kwilteam/kwil-db/node/engine"
+	"github.com/kwilteam/kwil-db/node/pg"
+	"github.com/kwilteam/kwil-db/node/types/sql"
+)
+
+// SetRetentionPolicy registers a policy that deletes rows of a table once the
+// value of the given column is older than the retention duration. The column
+// must be an int8 holding a unix timestamp in seconds, such as one populated
+// with @block_timestamp. Setting a policy for a table that already has one
+// replaces it.
+func (t *ThreadSafeInterpreter) SetRetentionPolicy(ctx context.Context, db sql.DB, namespace, table, column string, retentionDuration time.Duration) error {
+	if retentionDuration < time.Second {
+		return fmt.Errorf("retention duration must be at least one second, got %s", retentionDuration)
+	}
+
+	unlock, err := t.lock(db)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	ns, ok := t.i.namespaces[namespace]
+	if !ok {
+		return fmt.Errorf(`%w: "%s"`, engine.ErrNamespaceNotFound, namespace)
+	}
+	tbl, ok := ns.tables[table]
+	if !ok {
+		return fmt.Errorf(`%w: "%s"`, engine.ErrUnknownTable, table)
+	}
+	col, ok := tbl.Column(column)
+	if !ok {
+		return fmt.Errorf(`%w: "%s"`, engine.ErrUnknownColumn, column)
+	}
+	if !col.DataType.EqualsStrict(types.IntType) {
+		return fmt.Errorf(`retention column "%s" must be of type int8, got %s`, column, col.DataType)
+	}
+
+	return execute(ctx, db, `INSERT INTO kwild_engine.retention_policies (namespace, table_name, column_name, retention_seconds)
+	VALUES ($1, $2, $3, $4)
+	ON CONFLICT (namespace, table_name) DO UPDATE SET column_name = $3, retention_seconds = $4`,
+		namespace, table, column, int64(retentionDuration/time.Second))
+}
+
+// retentionEndBlockHook is the name of the end block hook that enforces
+// retention policies.
+const retentionEndBlockHook = "retention_policies"
+
+func init() {
+	err := hooks.RegisterEndBlockHook(retentionEndBlockHook, enforceRetentionPolicies)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// enforceRetentionPolicies enforces the retention policies at the end of each
+// block.
+func enforceRetentionPolicies(ctx context.Context, app *common.App, block *common.BlockContext) error {
+	interp, ok := app.Engine.(*ThreadSafeInterpreter)
+	if !ok {
+		return nil
+	}
+
+	return interp.EnforceRetentionPolicies(ctx, app.DB, block)
+}
+
+// EnforceRetentionPolicies deletes the rows of every table with a retention
+// policy whose timestamp column is older than the policy's retention duration,
+// as of the timestamp of the block. It is run at the end of each block, with
+// the block's transaction, so that every node deletes the same rows in the
+// same block. A policy whose rows cannot be deleted, e.g. because they are
+// referenced by another table, is skipped.
+func (t *ThreadSafeInterpreter) EnforceRetentionPolicies(ctx context.Context, db sql.DB, block *common.BlockContext) error {
+	if am, ok := db.(sql.AccessModer); !ok || am.AccessMode() != sql.ReadWrite {
+		return engine.ErrCannotMutateState
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var namespace, table, column string
+	var retentionSeconds int64
+	type policy struct {
+		namespace, table, column string
+		retentionSeconds         int64
+	}
+	var policies []policy
+	err := queryRowFunc(ctx, db, `SELECT namespace, table_name, column_name, retention_seconds
+	FROM kwild_engine.retention_policies ORDER BY namespace, table_name`,
+		[]any{&namespace, &table, &column, &retentionSeconds}, func() error {
+			policies = append(policies, policy{namespace, table, column, retentionSeconds})
+			return nil
+		})
+	if err != nil {
+		return err
+	}
+
+	// the deleted rows are not captured by CDC, so any cached result could
+	// read them
+	if len(policies) > 0 && t.readCache != nil {
+		t.readCache.markStale()
+	}
+
+	for _, p := range policies {
+		// Policies of dropped tables are not removed with the table, so they
+		// are skipped if the table or column no longer exists.
+		ns, ok := t.i.namespaces[p.namespace]
+		if !ok {
+			continue
+		}
+		tbl, ok := ns.tables[p.table]
+		if !ok {
+			continue
+		}
+		if _, ok = tbl.Column(p.column); !ok {
+			continue
+		}
+
+		cutoff := block.Timestamp - p.retentionSeconds
+		if err = deleteExpiredRows(ctx, db, p.namespace, p.table, p.column, cutoff); err != nil {
+			return fmt.Errorf("failed to enforce retention policy on %s.%s: %w", p.namespace, p.table, err)
+		}
+	}
+
+	return nil
+}
+
+// deleteExpiredRows deletes the rows of a table whose column is older than the
+// cutoff. The deletion is made in a nested transaction, so that a deletion
+// rejected by Postgres leaves the table as it was. Since every node rejects the
+// same deletions, they are not returned, and are retried in the next block.
+func deleteExpiredRows(ctx context.Context, db sql.DB, namespace, table, column string, cutoff int64) error {
+	tx, err := db.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Execute(ctx, fmt.Sprintf(`DELETE FROM %s.%s WHERE %s < $1`, namespace, table, column), pg.QueryModeExec, cutoff)
+	if pgErr := new(pgconn.PgError); errors.As(err, &pgErr) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
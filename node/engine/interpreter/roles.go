@@ -553,16 +553,17 @@ func unassignRole(ctx context.Context, db sql.DB, roleName, user string) error {
 }
 
 var privilegeNames = map[privilege]struct{}{
-	_CALL_PRIVILEGE:   {},
-	_SELECT_PRIVILEGE: {},
-	_INSERT_PRIVILEGE: {},
-	_UPDATE_PRIVILEGE: {},
-	_DELETE_PRIVILEGE: {},
-	_CREATE_PRIVILEGE: {},
-	_DROP_PRIVILEGE:   {},
-	_ALTER_PRIVILEGE:  {},
-	_ROLES_PRIVILEGE:  {},
-	_USE_PRIVILEGE:    {},
+	_CALL_PRIVILEGE:      {},
+	_SELECT_PRIVILEGE:    {},
+	_INSERT_PRIVILEGE:    {},
+	_UPDATE_PRIVILEGE:    {},
+	_DELETE_PRIVILEGE:    {},
+	_CREATE_PRIVILEGE:    {},
+	_DROP_PRIVILEGE:      {},
+	_ALTER_PRIVILEGE:     {},
+	_ROLES_PRIVILEGE:     {},
+	_USE_PRIVILEGE:       {},
+	_SENSITIVE_PRIVILEGE: {},
 }
 
 type privilege string
@@ -594,6 +595,10 @@ const (
 	// can manage roles.
 	// roles are global, and are not tied to a specific namespace or object.
 	_ROLES_PRIVILEGE privilege = "ROLES"
+	// can read the unmasked values of sensitive columns.
+	// TODO: the grammar needs a SENSITIVE keyword in the privilege rule
+	// before this can be granted with GRANT.
+	_SENSITIVE_PRIVILEGE privilege = "SENSITIVE"
 )
 
 // perms is a struct that holds the permissions for a role.
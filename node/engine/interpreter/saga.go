@@ -0,0 +1,227 @@
+package interpreter
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/kwilteam/kwil-db/common"
+	"github.com/kwilteam/kwil-db/node/types/sql"
+)
+
+// SagaArg is a placeholder in the arguments of a SagaStep for the argument of
+// the saga at the given index.
+type SagaArg int
+
+// SagaStep is an action called as part of a saga.
+type SagaStep struct {
+	// Namespace is the namespace of the action. If empty, the default
+	// namespace is used.
+	Namespace string
+	// Action is the name of the action.
+	Action string
+	// Args is the template of the action's arguments. A SagaArg is replaced by
+	// the corresponding argument of the saga, and any other value is passed
+	// as is.
+	Args []any
+}
+
+// bind returns the arguments of the step for the given saga arguments.
+func (s *SagaStep) bind(sagaArgs []any) ([]any, error) {
+	args := make([]any, len(s.Args))
+	for i, arg := range s.Args {
+		idx, ok := arg.(SagaArg)
+		if !ok {
+			args[i] = arg
+			continue
+		}
+		if idx < 0 || int(idx) >= len(sagaArgs) {
+			return nil, fmt.Errorf(`action "%s" references saga argument %d, but the saga has %d arguments`, s.Action, idx, len(sagaArgs))
+		}
+		args[i] = sagaArgs[idx]
+	}
+	return args, nil
+}
+
+// saga is a registered saga.
+type saga struct {
+	steps         []SagaStep
+	compensations []SagaStep
+}
+
+// SagaStatus is the status of an execution of a saga.
+type SagaStatus string
+
+const (
+	SagaRunning            SagaStatus = "running"
+	SagaCompensating       SagaStatus = "compensating"
+	SagaCompleted          SagaStatus = "completed"
+	SagaCompensated        SagaStatus = "compensated"
+	SagaCompensationFailed SagaStatus = "compensation_failed"
+)
+
+// SagaResult is the result of executing a saga.
+type SagaResult struct {
+	// ID identifies the execution in kwild_engine.sagas.
+	ID int64
+	// Status is the final status of the execution.
+	Status SagaStatus
+	// FailedStep is the index of the step that failed, or -1 if every step
+	// succeeded.
+	FailedStep int
+	// Error is the error of the failed step, joined with the error of the
+	// failed compensation, if any.
+	Error error
+	// Logs are the logs of the steps and compensations that were executed.
+	Logs []string
+}
+
+// RegisterSaga registers a saga, a sequence of actions in which a failure at
+// one step undoes the steps before it by calling their compensations in reverse
+// order. compensations[i] compensates steps[i]; a step that needs no
+// compensation has a compensation with an empty Action.
+func (t *ThreadSafeInterpreter) RegisterSaga(name string, steps []SagaStep, compensations []SagaStep) error {
+	if name == "" {
+		return errors.New("saga name cannot be empty")
+	}
+	if len(steps) == 0 {
+		return fmt.Errorf(`saga "%s" must have at least one step`, name)
+	}
+	if len(compensations) != len(steps) {
+		return fmt.Errorf(`saga "%s" has %d steps but %d compensations`, name, len(steps), len(compensations))
+	}
+	for _, step := range steps {
+		if step.Action == "" {
+			return fmt.Errorf(`saga "%s" has a step without an action`, name)
+		}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, ok := t.sagas[name]; ok {
+		return fmt.Errorf(`saga "%s" is already registered`, name)
+	}
+
+	t.sagas[name] = &saga{
+		steps:         steps,
+		compensations: compensations,
+	}
+	return nil
+}
+
+// ExecuteSaga executes the steps of a saga in order. If step N fails, its
+// changes are rolled back, and the compensations of steps N-1 through 0 are
+// executed in reverse order. The failure of a step is reported in the result,
+// while the returned error is for failures of the saga itself. The progress
+// of the saga is recorded in kwild_engine.sagas.
+func (t *ThreadSafeInterpreter) ExecuteSaga(ctx *common.EngineContext, db sql.DB, sagaName string, args []any) (*SagaResult, error) {
+	if err := ctx.Valid(); err != nil {
+		return nil, err
+	}
+
+	unlock, err := t.lock(db)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	s, ok := t.sagas[sagaName]
+	if !ok {
+		return nil, fmt.Errorf(`unknown saga "%s"`, sagaName)
+	}
+
+	var txID any
+	if !ctx.InvalidTxCtx {
+		txID = ctx.TxContext.TxID
+	}
+
+	res := &SagaResult{
+		Status:     SagaRunning,
+		FailedStep: -1,
+	}
+	err = queryRowFunc(ctx.TxContext.Ctx, db, `INSERT INTO kwild_engine.sagas (name, tx_id, status)
+	VALUES ($1, $2, $3) RETURNING id`, []any{&res.ID}, func() error { return nil }, sagaName, txID, string(SagaRunning))
+	if err != nil {
+		return nil, err
+	}
+
+	for i, step := range s.steps {
+		if err = t.setSagaProgress(ctx, db, res.ID, SagaRunning, i, nil); err != nil {
+			return nil, err
+		}
+
+		stepErr := t.runSagaStep(ctx, db, &step, args, res)
+		if stepErr != nil {
+			res.FailedStep = i
+			res.Error = fmt.Errorf(`saga step %d (action "%s") failed: %w`, i, step.Action, stepErr)
+			break
+		}
+	}
+
+	if res.FailedStep == -1 {
+		res.Status = SagaCompleted
+		return res, t.setSagaProgress(ctx, db, res.ID, res.Status, len(s.steps)-1, nil)
+	}
+
+	res.Status = SagaCompensated
+	for i := res.FailedStep - 1; i >= 0; i-- {
+		comp := &s.compensations[i]
+		if comp.Action == "" {
+			continue
+		}
+
+		if err = t.setSagaProgress(ctx, db, res.ID, SagaCompensating, i, res.Error); err != nil {
+			return nil, err
+		}
+
+		compErr := t.runSagaStep(ctx, db, comp, args, res)
+		if compErr != nil {
+			res.Status = SagaCompensationFailed
+			res.Error = errors.Join(res.Error, fmt.Errorf(`compensation of saga step %d (action "%s") failed: %w`, i, comp.Action, compErr))
+			// the remaining compensations are not executed, so that they can
+			// be executed once the failed compensation is resolved
+			return res, t.setSagaProgress(ctx, db, res.ID, res.Status, i, res.Error)
+		}
+	}
+
+	return res, t.setSagaProgress(ctx, db, res.ID, res.Status, 0, res.Error)
+}
+
+// runSagaStep calls the action of a saga step in a nested transaction, so that
+// the changes of a failed step are rolled back.
+func (t *ThreadSafeInterpreter) runSagaStep(ctx *common.EngineContext, db sql.DB, step *SagaStep, sagaArgs []any, res *SagaResult) error {
+	args, err := step.bind(sagaArgs)
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.BeginTx(ctx.TxContext.Ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx.TxContext.Ctx)
+
+	callRes, err := t.i.call(ctx, tx, step.Namespace, step.Action, args, nil, true, nil)
+	if callRes != nil {
+		res.Logs = append(res.Logs, callRes.Logs...)
+	}
+	if err != nil {
+		return err
+	}
+	if callRes.Error != nil {
+		return callRes.Error
+	}
+
+	return tx.Commit(ctx.TxContext.Ctx)
+}
+
+// setSagaProgress records the status and current step of a saga execution.
+func (t *ThreadSafeInterpreter) setSagaProgress(ctx *common.EngineContext, db sql.DB, id int64, status SagaStatus, step int, sagaErr error) error {
+	var errMsg any
+	if sagaErr != nil {
+		errMsg = sagaErr.Error()
+	}
+
+	return execute(ctx.TxContext.Ctx, db, `UPDATE kwild_engine.sagas SET status = $2, step = $3, error = $4 WHERE id = $1`,
+		id, string(status), int64(step), errMsg)
+}
@@ -0,0 +1,152 @@
+package interpreter
+
+import (
+	"container/heap"
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/kwilteam/kwil-db/node/engine"
+)
+
+// FairScheduler admits calls so that, under saturation, each namespace gets a
+// share of the interpreter's capacity proportional to its weight. It uses
+// start-time fair queuing, a variant of weighted fair queuing: each queued
+// call is tagged with a virtual start time that advances by 1/weight with each
+// call of its namespace, and calls are admitted in order of their tags. A
+// namespace that has used more than its share is therefore deferred until the
+// other namespaces have had theirs.
+type FairScheduler struct {
+	capacity int
+	weights  map[string]int
+
+	mu      sync.Mutex
+	running int
+	queue   waiterQueue
+	// vtime is the virtual time, the start tag of the last admitted call.
+	vtime float64
+	// lastFinish is the finish tag of the last call of each namespace.
+	lastFinish map[string]float64
+	seq        uint64
+}
+
+// NewFairScheduler creates a scheduler that runs at most capacity calls at
+// once. Namespaces not in weights have a weight of 1.
+func NewFairScheduler(capacity int, weights map[string]int) *FairScheduler {
+	w := make(map[string]int, len(weights))
+	for ns, weight := range weights {
+		w[strings.ToLower(ns)] = weight
+	}
+
+	return &FairScheduler{
+		capacity:   max(capacity, 1),
+		weights:    w,
+		lastFinish: make(map[string]float64),
+	}
+}
+
+// waiter is a call waiting to be admitted.
+type waiter struct {
+	start float64
+	seq   uint64
+	ready chan struct{}
+	// admitted and cancelled are guarded by the scheduler's mutex.
+	admitted  bool
+	cancelled bool
+}
+
+// waiterQueue is a priority queue of waiters, ordered by start tag, and then
+// by arrival.
+type waiterQueue []*waiter
+
+func (q waiterQueue) Len() int { return len(q) }
+func (q waiterQueue) Less(i, j int) bool {
+	if q[i].start != q[j].start {
+		return q[i].start < q[j].start
+	}
+	return q[i].seq < q[j].seq
+}
+func (q waiterQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+func (q *waiterQueue) Push(x any)   { *q = append(*q, x.(*waiter)) }
+func (q *waiterQueue) Pop() any {
+	old := *q
+	w := old[len(old)-1]
+	old[len(old)-1] = nil
+	*q = old[:len(old)-1]
+	return w
+}
+
+// tag returns the start tag of a new call of a namespace. s.mu must be held.
+func (s *FairScheduler) tag(namespace string) float64 {
+	weight := 1
+	if w, ok := s.weights[namespace]; ok && w > 0 {
+		weight = w
+	}
+
+	start := max(s.vtime, s.lastFinish[namespace])
+	s.lastFinish[namespace] = start + 1/float64(weight)
+	return start
+}
+
+// acquire blocks until a call of the namespace is admitted, or ctx is done.
+// The returned function must be called once the call finishes.
+func (s *FairScheduler) acquire(ctx context.Context, namespace string) (release func(), err error) {
+	if namespace == "" {
+		namespace = engine.DefaultNamespace
+	}
+	namespace = strings.ToLower(namespace)
+
+	s.mu.Lock()
+	start := s.tag(namespace)
+	if s.running < s.capacity && s.queue.Len() == 0 {
+		s.running++
+		s.vtime = start
+		s.mu.Unlock()
+		return s.release, nil
+	}
+
+	s.seq++
+	w := &waiter{
+		start: start,
+		seq:   s.seq,
+		ready: make(chan struct{}),
+	}
+	heap.Push(&s.queue, w)
+	s.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		return s.release, nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		admitted := w.admitted
+		w.cancelled = true
+		s.mu.Unlock()
+
+		if admitted {
+			// admitted concurrently with the cancellation, so give the
+			// slot to the next waiter
+			s.release()
+		}
+		return nil, ctx.Err()
+	}
+}
+
+// release frees the slot of a finished call, and admits the next waiters.
+func (s *FairScheduler) release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.running--
+	for s.running < s.capacity && s.queue.Len() > 0 {
+		w := heap.Pop(&s.queue).(*waiter)
+		if w.cancelled {
+			continue
+		}
+
+		s.running++
+		s.vtime = w.start
+		w.admitted = true
+		close(w.ready)
+	}
+}
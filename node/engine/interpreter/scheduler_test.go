@@ -0,0 +1,114 @@
+package interpreter
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_FairScheduler(t *testing.T) {
+	ctx := context.Background()
+	s := NewFairScheduler(1, map[string]int{"a": 1, "b": 1})
+
+	// hold the only slot so that every following call is queued
+	release, err := s.acquire(ctx, "a")
+	require.NoError(t, err)
+
+	const callsPerNamespace = 100
+	var mu sync.Mutex
+	var order []string
+	var wg sync.WaitGroup
+	enqueue := func(ns string) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			done, err := s.acquire(ctx, ns)
+			require.NoError(t, err)
+			mu.Lock()
+			order = append(order, ns)
+			mu.Unlock()
+			done()
+		}()
+	}
+
+	// namespace a saturates the scheduler before b makes any calls
+	for range callsPerNamespace {
+		enqueue("a")
+	}
+	require.Eventually(t, func() bool { return queued(s) == callsPerNamespace }, 5*time.Second, time.Millisecond)
+	for range callsPerNamespace {
+		enqueue("b")
+	}
+	require.Eventually(t, func() bool { return queued(s) == 2*callsPerNamespace }, 5*time.Second, time.Millisecond)
+
+	release()
+	wg.Wait()
+	require.Len(t, order, 2*callsPerNamespace)
+
+	// while both namespaces have calls queued, each gets about half of the
+	// capacity, even though a queued its calls first
+	var countA int
+	for _, ns := range order[:callsPerNamespace] {
+		if ns == "a" {
+			countA++
+		}
+	}
+	require.InDelta(t, callsPerNamespace/2, countA, callsPerNamespace/20)
+}
+
+func Test_FairSchedulerWeights(t *testing.T) {
+	ctx := context.Background()
+	s := NewFairScheduler(1, map[string]int{"heavy": 3})
+
+	release, err := s.acquire(ctx, "heavy")
+	require.NoError(t, err)
+
+	// tags are assigned on arrival, so queueing in order is deterministic
+	var admitted []string
+	var wg sync.WaitGroup
+	for i, ns := range []string{"heavy", "heavy", "heavy", "heavy", "heavy", "heavy", "light", "light"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			done, err := s.acquire(ctx, ns)
+			require.NoError(t, err)
+			admitted = append(admitted, ns) // serialized by the single slot
+			done()
+		}()
+		require.Eventually(t, func() bool { return queued(s) == i+1 }, 5*time.Second, time.Millisecond)
+	}
+
+	release()
+	wg.Wait()
+
+	// heavy gets three calls for each call of light
+	require.Equal(t, []string{"light", "heavy", "heavy", "heavy", "light", "heavy", "heavy", "heavy"}, admitted)
+}
+
+func Test_FairSchedulerCancel(t *testing.T) {
+	s := NewFairScheduler(1, nil)
+
+	release, err := s.acquire(context.Background(), "a")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = s.acquire(ctx, "b")
+	require.ErrorIs(t, err, context.Canceled)
+
+	// the cancelled call does not take the slot
+	release()
+	done, err := s.acquire(context.Background(), "c")
+	require.NoError(t, err)
+	done()
+}
+
+// queued returns the number of calls waiting to be admitted.
+func queued(s *FairScheduler) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.queue.Len()
+}
@@ -27,7 +27,7 @@ BEGIN
     -- privilege_type is an enumeration of all privilege types that can be applied to a role
     BEGIN
         CREATE TYPE kwild_engine.privilege_type AS ENUM (
-            'SELECT', 'INSERT', 'UPDATE', 'DELETE', 'CREATE', 'DROP', 'ALTER', 'CALL', 'ROLES', 'USE'
+            'SELECT', 'INSERT', 'UPDATE', 'DELETE', 'CREATE', 'DROP', 'ALTER', 'CALL', 'ROLES', 'USE', 'SENSITIVE'
         );
     EXCEPTION
         WHEN duplicate_object THEN NULL;
@@ -128,6 +128,150 @@ CREATE TABLE IF NOT EXISTS kwild_engine.user_roles (
 -- an index here helps with performance when querying for a user's roles
 CREATE INDEX IF NOT EXISTS user_roles_user_identifier_idx ON kwild_engine.user_roles(user_identifier);
 
+-- column_policies stores the masking policies of sensitive columns, which are
+-- declared with the @sensitive annotation
+CREATE TABLE IF NOT EXISTS kwild_engine.column_policies (
+    id BIGSERIAL PRIMARY KEY,
+    namespace TEXT NOT NULL REFERENCES kwild_engine.namespaces(name) ON UPDATE CASCADE ON DELETE CASCADE,
+    table_name TEXT NOT NULL,
+    column_name TEXT NOT NULL,
+    mask_function TEXT NOT NULL,
+    partial_length INT8 NOT NULL DEFAULT 0,
+    UNIQUE (namespace, table_name, column_name)
+);
+
+-- optimizer_hints stores the planner settings of tables declared with the
+-- @optimizer_hint annotation
+CREATE TABLE IF NOT EXISTS kwild_engine.optimizer_hints (
+    id BIGSERIAL PRIMARY KEY,
+    namespace TEXT NOT NULL REFERENCES kwild_engine.namespaces(name) ON UPDATE CASCADE ON DELETE CASCADE,
+    table_name TEXT NOT NULL,
+    hint TEXT NOT NULL,
+    value TEXT NOT NULL,
+    UNIQUE (namespace, table_name, hint)
+);
+
+-- retention_policies stores the policies for deleting rows older than a retention
+-- duration, based on a unix timestamp column
+CREATE TABLE IF NOT EXISTS kwild_engine.retention_policies (
+    id BIGSERIAL PRIMARY KEY,
+    namespace TEXT NOT NULL REFERENCES kwild_engine.namespaces(name) ON UPDATE CASCADE ON DELETE CASCADE,
+    table_name TEXT NOT NULL,
+    column_name TEXT NOT NULL,
+    retention_seconds INT8 NOT NULL CHECK (retention_seconds > 0),
+    UNIQUE (namespace, table_name)
+);
+
+-- cdc_changes stages the row changes made by actions until they are collected at the
+-- end of the block, to be published to the node's CDC publisher once the block is
+-- committed. It is unlogged since it is not part of the database's state.
+CREATE UNLOGGED TABLE IF NOT EXISTS kwild_engine.cdc_changes (
+    id BIGSERIAL PRIMARY KEY,
+    namespace TEXT NOT NULL,
+    table_name TEXT NOT NULL,
+    operation TEXT NOT NULL,
+    before_image JSONB,
+    after_image JSONB,
+    tx_hash TEXT, -- the hex encoded hash of the transaction, if any
+    block_height INT8 -- the height of the block, if any
+);
+
+-- sagas records the progress of each execution of a saga, a sequence of actions
+-- whose completed steps are compensated if a later step fails
+CREATE TABLE IF NOT EXISTS kwild_engine.sagas (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    tx_id TEXT,
+    status TEXT NOT NULL CHECK (status IN ('running', 'compensating', 'completed', 'compensated', 'compensation_failed')),
+    step INT8 NOT NULL DEFAULT 0, -- the step being executed, or compensated
+    error TEXT
+);
+
+-- plan_baselines stores the query plans of actions, to detect plan regressions
+-- after schema changes or upgrades
+CREATE TABLE IF NOT EXISTS kwild_engine.plan_baselines (
+    id TEXT PRIMARY KEY,
+    namespace TEXT NOT NULL,
+    action_name TEXT NOT NULL,
+    nodes TEXT NOT NULL, -- JSON array of plan node descriptions
+    total_cost TEXT NOT NULL
+);
+
+-- online_schema_changes tracks the progress of online schema changes, which copy the rows of
+-- a table to its shadow table in batches at the end of each block
+CREATE TABLE IF NOT EXISTS kwild_engine.online_schema_changes (
+    namespace TEXT NOT NULL,
+    table_name TEXT NOT NULL,
+    column_name TEXT NOT NULL,
+    column_type TEXT NOT NULL,
+    status TEXT NOT NULL, -- backfilling, completed, or failed
+    rows_copied INT8 NOT NULL DEFAULT 0,
+    PRIMARY KEY (namespace, table_name)
+);
+
+-- namespace_config stores the settings of namespaces
+CREATE TABLE IF NOT EXISTS kwild_engine.namespace_config (
+    namespace TEXT PRIMARY KEY REFERENCES kwild_engine.namespaces(name) ON UPDATE CASCADE ON DELETE CASCADE,
+    error_verbosity TEXT NOT NULL DEFAULT 'normal' CHECK (error_verbosity IN ('minimal', 'normal', 'detailed')),
+    read_only BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+-- namespace_config_kv stores the configuration values of namespaces, which their actions
+-- read with config_get and write with config_set
+CREATE TABLE IF NOT EXISTS kwild_engine.namespace_config_kv (
+    namespace TEXT NOT NULL REFERENCES kwild_engine.namespaces(name) ON UPDATE CASCADE ON DELETE CASCADE,
+    key TEXT NOT NULL,
+    value TEXT NOT NULL,
+    PRIMARY KEY (namespace, key)
+);
+
+-- test_namespaces stores the namespaces declared with @test_namespace, which can have @test actions
+CREATE TABLE IF NOT EXISTS kwild_engine.test_namespaces (
+    namespace TEXT PRIMARY KEY REFERENCES kwild_engine.namespaces(name) ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+-- ab_assignments stores the variant of an A/B tested action that each caller was assigned,
+-- so that callers keep calling the same variant
+CREATE TABLE IF NOT EXISTS kwild_engine.ab_assignments (
+    namespace TEXT NOT NULL REFERENCES kwild_engine.namespaces(name) ON UPDATE CASCADE ON DELETE CASCADE,
+    action_name TEXT NOT NULL,
+    caller TEXT NOT NULL,
+    variant CHAR(1) NOT NULL CHECK (variant IN ('A', 'B')),
+    PRIMARY KEY (namespace, action_name, caller)
+);
+
+-- call_history stores the results of calls to actions declared with @idempotent, keyed by the
+-- hash of the caller, the action and the arguments, so that a retried call returns the stored
+-- result instead of executing the action again. Results older than the idempotency window are
+-- pruned by block height.
+CREATE TABLE IF NOT EXISTS kwild_engine.call_history (
+    call_hash BYTEA PRIMARY KEY,
+    namespace TEXT NOT NULL REFERENCES kwild_engine.namespaces(name) ON UPDATE CASCADE ON DELETE CASCADE,
+    block_height INT8 NOT NULL,
+    result BYTEA NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS call_history_block_height_idx ON kwild_engine.call_history(block_height);
+
+-- namespace_versions stores the number of times each namespace was deployed with DeployNamespace.
+-- It does not reference the namespaces, so that a version is claimed before its namespace is
+-- created, and is kept when the namespace is dropped.
+CREATE TABLE IF NOT EXISTS kwild_engine.namespace_versions (
+    namespace TEXT PRIMARY KEY,
+    version INT8 NOT NULL
+);
+
+-- concurrent_indexes stores the indexes created with CREATE INDEX CONCURRENTLY. An index exists
+-- from the commit of the transaction that creates it on, and each node builds it in the background
+-- afterwards, so info.indexes reads these indexes from here rather than from the catalog.
+CREATE TABLE IF NOT EXISTS kwild_engine.concurrent_indexes (
+    namespace TEXT NOT NULL REFERENCES kwild_engine.namespaces(name) ON UPDATE CASCADE ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    table_name TEXT NOT NULL,
+    columns TEXT[] NOT NULL,
+    PRIMARY KEY (namespace, name)
+);
+
 -- create a single default role that will be used for all users
 INSERT INTO kwild_engine.roles (name, built_in) VALUES ('default', true) ON CONFLICT DO NOTHING;
 -- default role can select and call by default
@@ -185,6 +329,10 @@ INSERT INTO kwild_engine.role_privileges (privilege_type, role_id) VALUES ('SELE
     SELECT id
     FROM kwild_engine.roles
     WHERE name = 'owner'
+)), ('SENSITIVE', (
+    SELECT id
+    FROM kwild_engine.roles
+    WHERE name = 'owner'
 )) ON CONFLICT DO NOTHING;
 
 
@@ -245,6 +393,130 @@ BEGIN
 END;
 $$ LANGUAGE plpgsql;
 
+-- block_hard_delete is the trigger function that prevents rows from being deleted
+-- from tables declared with @soft_delete. Rows must be soft deleted instead.
+CREATE OR REPLACE FUNCTION kwild_engine.block_hard_delete()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'cannot delete from soft delete table %.%, use soft_delete_row instead', TG_TABLE_SCHEMA, TG_TABLE_NAME;
+END;
+$$ LANGUAGE plpgsql;
+
+-- record_history is the trigger function that records the previous state of a row of
+-- a table declared with @history in its history table. Columns are matched by name,
+-- so the history table may have columns that the table no longer has. The change
+-- time is the block timestamp set by the engine, so that it is deterministic.
+CREATE OR REPLACE FUNCTION kwild_engine.record_history()
+RETURNS TRIGGER AS $$
+BEGIN
+    EXECUTE format('INSERT INTO %1$I.%2$I SELECT * FROM jsonb_populate_record(NULL::%1$I.%2$I,
+        $1 || jsonb_build_object(
+            ''_history_seq'', (SELECT COALESCE(max(_history_seq), 0) + 1 FROM %1$I.%2$I),
+            ''_changed_at'', to_timestamp(NULLIF(current_setting(''kwild.block_timestamp'', true), '''')::INT8),
+            ''_operation'', $2))',
+        TG_TABLE_SCHEMA, '_history_' || TG_TABLE_NAME)
+    USING to_jsonb(OLD), left(TG_OP, 1);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+-- record_event is the trigger function that appends the changes of a table of a namespace
+-- declared with @event_sourced to the namespace's events table. Rows are keyed by their
+-- primary key, so an UPDATE that changes the primary key is recorded as a DELETE of the
+-- old key followed by an UPDATE of the new one. The block height is set by the engine.
+CREATE OR REPLACE FUNCTION kwild_engine.record_event()
+RETURNS TRIGGER AS $$
+DECLARE
+    pk_cols TEXT[];
+    old_key JSONB;
+    new_key JSONB;
+    events_table TEXT := 'events_' || TG_TABLE_SCHEMA;
+    height INT8 := COALESCE(NULLIF(current_setting('kwild.block_height', true), '')::INT8, 0);
+BEGIN
+    SELECT array_agg(a.attname::TEXT ORDER BY k.ord) INTO pk_cols
+    FROM pg_index i
+    CROSS JOIN unnest(i.indkey) WITH ORDINALITY AS k(attnum, ord)
+    JOIN pg_attribute a ON a.attrelid = i.indrelid AND a.attnum = k.attnum
+    WHERE i.indrelid = TG_RELID AND i.indisprimary;
+
+    IF TG_OP <> 'INSERT' THEN
+        SELECT jsonb_agg(to_jsonb(OLD) -> c ORDER BY n) INTO old_key FROM unnest(pk_cols) WITH ORDINALITY AS p(c, n);
+    END IF;
+    IF TG_OP <> 'DELETE' THEN
+        SELECT jsonb_agg(to_jsonb(NEW) -> c ORDER BY n) INTO new_key FROM unnest(pk_cols) WITH ORDINALITY AS p(c, n);
+    END IF;
+
+    IF TG_OP = 'DELETE' OR (TG_OP = 'UPDATE' AND old_key <> new_key) THEN
+        EXECUTE format('INSERT INTO kwild_engine.%1$I (seq, block_height, table_name, operation, row_key, row_data)
+            VALUES ((SELECT COALESCE(max(seq), 0) + 1 FROM kwild_engine.%1$I), $1, $2, ''D'', $3, $4)', events_table)
+        USING height, TG_TABLE_NAME, old_key, to_jsonb(OLD);
+    END IF;
+    IF TG_OP <> 'DELETE' THEN
+        EXECUTE format('INSERT INTO kwild_engine.%1$I (seq, block_height, table_name, operation, row_key, row_data)
+            VALUES ((SELECT COALESCE(max(seq), 0) + 1 FROM kwild_engine.%1$I), $1, $2, $3, $4, $5)', events_table)
+        USING height, TG_TABLE_NAME, left(TG_OP, 1), new_key, to_jsonb(NEW);
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+-- project_events replays the events of a table of an event sourced namespace up to and
+-- including a block height, and returns the state of the table at that height. Each row
+-- is returned as its primary key and its columns, as JSON, ordered by primary key.
+CREATE OR REPLACE FUNCTION kwild_engine.project_events(ns TEXT, tbl TEXT, as_of_block INT8)
+RETURNS TABLE(row_key TEXT, row_data TEXT) AS $$
+DECLARE
+    events_table TEXT := 'events_' || lower(ns);
+BEGIN
+    IF to_regclass(format('kwild_engine.%I', events_table)) IS NULL THEN
+        RAISE EXCEPTION 'namespace "%" is not event sourced', ns;
+    END IF;
+
+    RETURN QUERY EXECUTE format('SELECT e.row_key::TEXT, e.row_data::TEXT FROM (
+            SELECT DISTINCT ON (row_key) row_key, row_data, operation FROM kwild_engine.%I
+            WHERE table_name = $1 AND block_height <= $2
+            ORDER BY row_key, seq DESC
+        ) e WHERE e.operation <> ''D'' ORDER BY e.row_key', events_table)
+    USING lower(tbl), as_of_block;
+END;
+$$ LANGUAGE plpgsql STABLE;
+
+-- capture_change is the trigger function that stages row changes for CDC. Changes are
+-- only captured while the transaction has set kwild.cdc_capture, which is done for the
+-- duration of each action, along with the transaction and block making the changes.
+CREATE OR REPLACE FUNCTION kwild_engine.capture_change()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF current_setting('kwild.cdc_capture', true) = 'on' THEN
+        INSERT INTO kwild_engine.cdc_changes (namespace, table_name, operation, before_image, after_image, tx_hash, block_height)
+        VALUES (TG_TABLE_SCHEMA, TG_TABLE_NAME, TG_OP, to_jsonb(OLD), to_jsonb(NEW),
+            NULLIF(current_setting('kwild.cdc_tx_hash', true), ''),
+            NULLIF(current_setting('kwild.cdc_block_height', true), '')::INT8);
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+-- online_schema_change_capture is the trigger function that copies the row changes of a
+-- table to its shadow table while an online schema change backfills it. The first
+-- argument is the shadow table, and the second is the comma separated primary key columns.
+CREATE OR REPLACE FUNCTION kwild_engine.online_schema_change_capture()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        EXECUTE format('DELETE FROM %1$I.%2$I WHERE (%3$s) = (SELECT %3$s FROM jsonb_populate_record(NULL::%1$I.%2$I, $1))',
+            TG_TABLE_SCHEMA, TG_ARGV[0], TG_ARGV[1])
+        USING to_jsonb(OLD);
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        EXECUTE format('INSERT INTO %1$I.%2$I SELECT * FROM jsonb_populate_record(NULL::%1$I.%2$I, $1)',
+            TG_TABLE_SCHEMA, TG_ARGV[0])
+        USING to_jsonb(NEW);
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
 /*
     This section creates the schema the `kwild` schema, which is the public user-facing schema.
     End users can access the views in this schema to get information about the database.
@@ -272,6 +544,8 @@ SELECT
 FROM pg_tables t
 JOIN kwild_engine.namespaces us
     ON t.schemaname = us.name
+-- tables starting with an underscore are managed by the engine, e.g. history tables
+WHERE t.tablename NOT LIKE '\_%'
 
 UNION ALL
 
@@ -324,28 +598,39 @@ JOIN pg_type t
 JOIN 
     kwild_engine.namespaces us ON n.nspname::TEXT = us.name
 WHERE cl.relkind IN ('r', 'v') -- only tables and views
+    AND c.column_name != '_deleted_at' -- soft delete columns are managed by the engine
 ORDER BY table_name, ordinal_position;
     
 
 -- info.indexes is a public view that provides a list of all indexes in the database
 CREATE VIEW info.indexes AS
-SELECT 
-    n.nspname::TEXT AS namespace,
-    c.relname::TEXT AS table_name,
-    ic.relname::TEXT AS name,
-    i.indisprimary AS is_primary_key,
-    i.indisunique AS is_unique,
-    array_agg(a.attname ORDER BY x.ordinality)::TEXT[] AS columns
-FROM pg_index i
-JOIN pg_class c ON c.oid = i.indrelid
-JOIN pg_class ic ON ic.oid = i.indexrelid
-JOIN pg_namespace n ON c.relnamespace = n.oid
-JOIN pg_am am ON ic.relam = am.oid
-JOIN pg_attribute a ON a.attnum = ANY(i.indkey) AND a.attrelid = c.oid
-JOIN LATERAL unnest(i.indkey) WITH ORDINALITY AS x(colnum, ordinality) ON x.colnum = a.attnum
-JOIN 
-    kwild_engine.namespaces us ON n.nspname::TEXT = us.name
-GROUP BY n.nspname, c.relname, ic.relname, i.indisprimary, i.indisunique
+SELECT * FROM (
+    SELECT 
+        n.nspname::TEXT AS namespace,
+        c.relname::TEXT AS table_name,
+        ic.relname::TEXT AS name,
+        i.indisprimary AS is_primary_key,
+        i.indisunique AS is_unique,
+        array_agg(a.attname ORDER BY x.ordinality)::TEXT[] AS columns
+    FROM pg_index i
+    JOIN pg_class c ON c.oid = i.indrelid
+    JOIN pg_class ic ON ic.oid = i.indexrelid
+    JOIN pg_namespace n ON c.relnamespace = n.oid
+    JOIN pg_am am ON ic.relam = am.oid
+    JOIN pg_attribute a ON a.attnum = ANY(i.indkey) AND a.attrelid = c.oid
+    JOIN LATERAL unnest(i.indkey) WITH ORDINALITY AS x(colnum, ordinality) ON x.colnum = a.attnum
+    JOIN 
+        kwild_engine.namespaces us ON n.nspname::TEXT = us.name
+    -- indexes created concurrently are built at different times on each node
+    WHERE NOT EXISTS (
+        SELECT 1 FROM kwild_engine.concurrent_indexes ci
+        WHERE ci.namespace = n.nspname::TEXT AND ci.name = ic.relname::TEXT
+    )
+    GROUP BY n.nspname, c.relname, ic.relname, i.indisprimary, i.indisunique
+    UNION ALL
+    SELECT namespace, table_name, name, false, false, columns
+    FROM kwild_engine.concurrent_indexes
+) AS indexes
 ORDER BY 
     table_name, name,
     1,2,3,4,5,6;
@@ -514,6 +799,17 @@ LEFT JOIN
 ORDER BY
     1, 2, 3, 4;
 
+-- namespace_config is a public view that provides the configuration values of all namespaces
+CREATE VIEW info.namespace_config AS
+SELECT
+    namespace,
+    key,
+    value
+FROM
+    kwild_engine.namespace_config_kv
+ORDER BY
+    1, 2;
+
 CREATE VIEW info.extensions AS
 SELECT 
     n.name AS namespace,
@@ -0,0 +1,124 @@
+package interpreter
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/kwilteam/kwil-db/common"
+	"github.com/kwilteam/kwil-db/config"
+	"github.com/kwilteam/kwil-db/core/client"
+	clientType "github.com/kwilteam/kwil-db/core/client/types"
+	"github.com/kwilteam/kwil-db/node/engine"
+	"github.com/kwilteam/kwil-db/node/types/sql"
+)
+
+// shardRouter forwards calls to namespaces that are assigned to other nodes.
+type shardRouter struct {
+	cfg config.ShardingConfig
+	// dial creates a client for a node. It is client.NewClient, other than
+	// in tests.
+	dial func(ctx context.Context, address string) (clientType.Client, error)
+
+	mu      sync.Mutex
+	clients map[string]clientType.Client // keyed by address
+}
+
+func newShardRouter(cfg config.ShardingConfig) *shardRouter {
+	return &shardRouter{
+		cfg: cfg,
+		dial: func(ctx context.Context, address string) (clientType.Client, error) {
+			return client.NewClient(ctx, address, nil)
+		},
+		clients: make(map[string]clientType.Client),
+	}
+}
+
+// route returns the address of the node to forward a call to, if the namespace
+// is assigned to another node. Only read-only calls are forwarded, since calls
+// that can change state are part of consensus and must be executed by every
+// node.
+func (s *shardRouter) route(db sql.DB, namespace string) (string, bool) {
+	am, ok := db.(sql.AccessModer)
+	if !ok || am.AccessMode() != sql.ReadOnly {
+		return "", false
+	}
+
+	if namespace == "" {
+		namespace = engine.DefaultNamespace
+	}
+
+	addr := s.cfg.AssignShard(namespace)
+	if addr == "" || addr == s.cfg.LocalAddress {
+		return "", false
+	}
+	return addr, true
+}
+
+// client returns the client for a node, creating it if needed.
+func (s *shardRouter) client(ctx context.Context, address string) (clientType.Client, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if clt, ok := s.clients[address]; ok {
+		return clt, nil
+	}
+
+	clt, err := s.dial(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+	s.clients[address] = clt
+	return clt, nil
+}
+
+// forward calls an action on the node at the given address. The call is made
+// by the client of this node, not the caller in the engine context.
+func (s *shardRouter) forward(ctx *common.EngineContext, address, namespace, action string, args []any, resultFn func(*common.Row) error) (*common.CallResult, error) {
+	clt, err := s.client(ctx.TxContext.Ctx, address)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := clt.Call(ctx.TxContext.Ctx, namespace, action, args)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.QueryResult != nil && resultFn != nil {
+		for _, vals := range res.QueryResult.Values {
+			err = resultFn(&common.Row{
+				ColumnNames: res.QueryResult.ColumnNames,
+				ColumnTypes: res.QueryResult.ColumnTypes,
+				Values:      vals,
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	callRes := &common.CallResult{
+		Logs: parseForwardedLogs(res.Logs),
+	}
+	if res.Error != nil {
+		callRes.Error = errors.New(*res.Error)
+	}
+	return callRes, nil
+}
+
+// parseForwardedLogs reverses common.CallResult.FormatLogs.
+func parseForwardedLogs(logs string) []string {
+	if logs == "" {
+		return nil
+	}
+
+	lines := strings.Split(logs, "\n")
+	for i, line := range lines {
+		if _, msg, ok := strings.Cut(line, ". "); ok {
+			lines[i] = msg
+		}
+	}
+	return lines
+}
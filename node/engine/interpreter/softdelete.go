@@ -0,0 +1,69 @@
+package interpreter
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/kwilteam/kwil-db/node/engine"
+)
+
+// setSoftDeleted soft deletes or restores the row of a soft delete table with
+// the given primary key. It implements the soft_delete_row and restore_row
+// functions. Rows are soft deleted at the block timestamp so that the result is
+// deterministic. Soft deleting a row that is already soft deleted keeps its
+// original deletion time.
+func (e *executionContext) setSoftDeleted(tableName, pk value, deleted bool) error {
+	if tableName.Null() {
+		return errors.New("table name cannot be null")
+	}
+	if pk.Null() {
+		return errors.New("primary key cannot be null")
+	}
+
+	if err := e.checkNamespaceMutatbility(); err != nil {
+		return err
+	}
+
+	priv := _UPDATE_PRIVILEGE
+	if deleted {
+		priv = _DELETE_PRIVILEGE
+	}
+	if err := e.checkPrivilege(priv); err != nil {
+		return err
+	}
+
+	tbl, err := e.getTable("", tableName.RawValue().(string))
+	if err != nil {
+		return err
+	}
+	if !tbl.SoftDelete {
+		return fmt.Errorf(`table "%s" is not a soft delete table`, tbl.Name)
+	}
+
+	pkCols := tbl.PrimaryKeyCols()
+	if len(pkCols) != 1 {
+		return fmt.Errorf(`table "%s" must have a single column primary key to soft delete rows`, tbl.Name)
+	}
+
+	pk, err = pk.Cast(pkCols[0].DataType)
+	if err != nil {
+		return err
+	}
+	pkCast, err := engine.MakeTypeCast(pkCols[0].DataType)
+	if err != nil {
+		return err
+	}
+
+	if !deleted {
+		return execute(e.engineCtx.TxContext.Ctx, e.db, fmt.Sprintf(`UPDATE %s.%s SET %s = NULL WHERE %s = $1%s`,
+			e.scope.namespace, tbl.Name, engine.SoftDeleteColumn, pkCols[0].Name, pkCast), pk)
+	}
+
+	if e.engineCtx.InvalidTxCtx {
+		return engine.ErrInvalidTxCtx
+	}
+
+	return execute(e.engineCtx.TxContext.Ctx, e.db, fmt.Sprintf(`UPDATE %s.%s SET %s = to_timestamp($1::INT8) WHERE %s = $2%s AND %s IS NULL`,
+		e.scope.namespace, tbl.Name, engine.SoftDeleteColumn, pkCols[0].Name, pkCast, engine.SoftDeleteColumn),
+		makeInt8(e.engineCtx.TxContext.BlockContext.Timestamp), pk)
+}
@@ -9,6 +9,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/kwilteam/kwil-db/common"
 	"github.com/kwilteam/kwil-db/core/types"
 	"github.com/kwilteam/kwil-db/core/utils/order"
 	"github.com/kwilteam/kwil-db/extensions/precompiles"
@@ -235,88 +236,173 @@ func listTablesInNamespace(ctx context.Context, db sql.DB, namespace string) ([]
 	WHERE t.namespace = $1`, scans,
 		func() error {
 			tbl := &engine.Table{
-				Name:        tblName,
-				Constraints: make(map[string]*engine.Constraint),
+				Name: tblName,
 			}
 
-			tables = append(tables, tbl)
+			var err error
+			tbl.Columns, err = convertColumnsToEngine(tblName, colNames, dataTypes, isNullables, isPrimaryKeys)
+			if err != nil {
+				return err
+			}
 
-			// add columns
-			for i, colName := range colNames {
-				dt, err := types.ParseDataType(dataTypes[i])
-				if err != nil {
-					return err
-				}
+			tbl.Indexes, err = convertIndexesToEngine(tblName, indexNames, indexCols, isPKs, isUniques)
+			if err != nil {
+				return err
+			}
 
-				tbl.Columns = append(tbl.Columns, &engine.Column{
-					Name:         colName,
-					DataType:     dt,
-					Nullable:     isNullables[i],
-					IsPrimaryKey: isPrimaryKeys[i],
-				})
+			tbl.Constraints, err = convertConstraintsToEngine(tblName, constraintNames, constraintTypes, constraintCols, fkNames, fkCols)
+			if err != nil {
+				return err
 			}
 
-			// add indexes
-			for i, indexName := range indexNames {
-				indexType := engine.BTREE
-				if isPKs[i] {
-					indexType = engine.PRIMARY
-				} else if isUniques[i] {
-					indexType = engine.UNIQUE_BTREE
-				}
+			tables = append(tables, tbl)
+			return nil
+		}, namespace,
+	)
+	if err != nil {
+		return nil, err
+	}
 
-				tbl.Indexes = append(tbl.Indexes, &engine.Index{
-					Name:    indexName,
-					Columns: indexCols[i],
-					Type:    indexType,
-				})
-			}
+	policies, err := listColumnPolicies(ctx, db, namespace)
+	if err != nil {
+		return nil, err
+	}
+	softDeleteTables, err := listSoftDeleteTables(ctx, db, namespace)
+	if err != nil {
+		return nil, err
+	}
+	historyTables, err := listHistoryTables(ctx, db, namespace)
+	if err != nil {
+		return nil, err
+	}
+	optimizerHints, err := listOptimizerHints(ctx, db, namespace)
+	if err != nil {
+		return nil, err
+	}
+	for _, tbl := range tables {
+		for _, col := range tbl.Columns {
+			col.SensitivityPolicy = policies[tbl.Name][col.Name]
+		}
+		tbl.SoftDelete = softDeleteTables[tbl.Name]
+		tbl.History = historyTables[tbl.Name]
+		tbl.OptimizerHints = optimizerHints[tbl.Name]
+	}
 
-			// add constraints
-			for i, constraintName := range constraintNames {
-				var constraintType engine.ConstraintType
-				switch strings.ToLower(constraintTypes[i]) {
-				case "unique":
-					constraintType = engine.ConstraintUnique
-				case "check":
-					constraintType = engine.ConstraintCheck
-				default:
-					return fmt.Errorf("unknown constraint type %s", constraintTypes[i])
-				}
+	return tables, nil
+}
 
-				_, ok := tbl.Constraints[constraintName]
-				if ok {
-					return fmt.Errorf("duplicate constraint %s", constraintName)
-				}
+// listSoftDeleteTables lists the tables in a namespace that have a soft delete column.
+// Since identifiers cannot start with an underscore, the column can only have been
+// added by the engine.
+func listSoftDeleteTables(ctx context.Context, db sql.DB, namespace string) (map[string]bool, error) {
+	tables := make(map[string]bool)
+	var tableName string
+	err := queryRowFunc(ctx, db, `SELECT table_name::text FROM information_schema.columns
+	WHERE table_schema = $1 AND column_name = $2`, []any{&tableName},
+		func() error {
+			tables[tableName] = true
+			return nil
+		}, namespace, engine.SoftDeleteColumn)
+	if err != nil {
+		return nil, err
+	}
 
-				tbl.Constraints[constraintName] = &engine.Constraint{
-					Type:    constraintType,
-					Columns: constraintCols[i],
-				}
-			}
+	return tables, nil
+}
 
-			// add foreign keys
-			for i, fkName := range fkNames {
-				_, ok := tbl.Constraints[fkName]
-				if ok {
-					return fmt.Errorf("duplicate foreign key %s", fkName)
-				}
+// referencingTables returns the namespaces and names of the tables that
+// reference a table with foreign keys, directly or through other tables. These
+// are the tables that TRUNCATE ... CASCADE also truncates.
+func referencingTables(ctx context.Context, db sql.DB, namespace, table string) ([][2]string, error) {
+	var refs [][2]string
+	var refNamespace, refTable string
+	err := queryRowFunc(ctx, db, `WITH RECURSIVE refs AS (
+		SELECT format('%I.%I', $1::TEXT, $2::TEXT)::regclass::oid AS relid
+		UNION
+		SELECT c.conrelid FROM pg_constraint c JOIN refs r ON c.confrelid = r.relid WHERE c.contype = 'f'
+	)
+	SELECT n.nspname::TEXT, t.relname::TEXT FROM refs r
+	JOIN pg_class t ON t.oid = r.relid
+	JOIN pg_namespace n ON n.oid = t.relnamespace
+	WHERE r.relid <> format('%I.%I', $1::TEXT, $2::TEXT)::regclass::oid
+	ORDER BY 1, 2`, []any{&refNamespace, &refTable},
+		func() error {
+			refs = append(refs, [2]string{refNamespace, refTable})
+			return nil
+		}, namespace, table)
+	if err != nil {
+		return nil, err
+	}
 
-				fk := &engine.Constraint{
-					Type:    engine.ConstraintFK,
-					Columns: fkCols[i],
-				}
+	return refs, nil
+}
+
+// createSoftDeleteTrigger creates the trigger that blocks hard deletes on a soft delete table.
+func createSoftDeleteTrigger(ctx context.Context, db sql.DB, namespace, table string) error {
+	return execute(ctx, db, fmt.Sprintf(`CREATE OR REPLACE TRIGGER block_hard_delete BEFORE DELETE ON %s.%s
+	FOR EACH ROW EXECUTE FUNCTION kwild_engine.block_hard_delete()`, namespace, table))
+}
 
-				tbl.Constraints[fkName] = fk
+// storeColumnPolicies stores the masking policies of a table's sensitive columns.
+func storeColumnPolicies(ctx context.Context, db sql.DB, namespace, table string, columns []*parse.Column) error {
+	for _, col := range columns {
+		if col.SensitivityPolicy == nil {
+			continue
+		}
+
+		err := execute(ctx, db, `INSERT INTO kwild_engine.column_policies (namespace, table_name, column_name, mask_function, partial_length)
+		VALUES ($1, $2, $3, $4, $5)`, namespace, table, col.Name, string(col.SensitivityPolicy.Mask), col.SensitivityPolicy.PartialLength)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// listColumnPolicies lists the masking policies in a namespace, keyed by table and then column.
+func listColumnPolicies(ctx context.Context, db sql.DB, namespace string) (map[string]map[string]*engine.SensitivityPolicy, error) {
+	policies := make(map[string]map[string]*engine.SensitivityPolicy)
+	var tableName, columnName, mask string
+	var partialLength int64
+	err := queryRowFunc(ctx, db, `SELECT table_name, column_name, mask_function, partial_length
+	FROM kwild_engine.column_policies WHERE namespace = $1`, []any{&tableName, &columnName, &mask, &partialLength},
+		func() error {
+			if _, ok := policies[tableName]; !ok {
+				policies[tableName] = make(map[string]*engine.SensitivityPolicy)
+			}
+			policies[tableName][columnName] = &engine.SensitivityPolicy{
+				Mask:          engine.MaskFunction(mask),
+				PartialLength: partialLength,
 			}
 			return nil
-		}, namespace,
-	)
+		}, namespace)
 	if err != nil {
 		return nil, err
 	}
 
-	return tables, nil
+	return policies, nil
+}
+
+// deleteColumnPolicies deletes the masking policies of a table.
+// If column is not empty, only the policy for that column is deleted.
+func deleteColumnPolicies(ctx context.Context, db sql.DB, namespace, table, column string) error {
+	if column == "" {
+		return execute(ctx, db, `DELETE FROM kwild_engine.column_policies WHERE namespace = $1 AND table_name = $2`, namespace, table)
+	}
+	return execute(ctx, db, `DELETE FROM kwild_engine.column_policies WHERE namespace = $1 AND table_name = $2 AND column_name = $3`,
+		namespace, table, column)
+}
+
+// renameColumnPolicies updates the masking policies of a renamed table or column.
+// If oldColumn is empty, the table was renamed.
+func renameColumnPolicies(ctx context.Context, db sql.DB, namespace, table, oldColumn, newName string) error {
+	if oldColumn == "" {
+		return execute(ctx, db, `UPDATE kwild_engine.column_policies SET table_name = $3 WHERE namespace = $1 AND table_name = $2`,
+			namespace, table, newName)
+	}
+	return execute(ctx, db, `UPDATE kwild_engine.column_policies SET column_name = $4 WHERE namespace = $1 AND table_name = $2 AND column_name = $3`,
+		namespace, table, oldColumn, newName)
 }
 
 // listActionsInBuiltInNamespace lists all actions in a namespace.
@@ -571,6 +657,27 @@ func queryRowFunc(ctx context.Context, tx sql.Executor, stmt string,
 	return pg.QueryRowFunc(ctx, tx, stmt, scans, fn, append([]any{pg.QueryModeExec}, args...)...)
 }
 
+// newServicePool connects a small connection pool using the node's configured
+// database settings. It is used by background tasks that run outside of the
+// transactions given to the interpreter.
+func newServicePool(ctx context.Context, service *common.Service) (*pg.Pool, error) {
+	if service.LocalConfig == nil {
+		return nil, errors.New("local config is required to connect to postgres")
+	}
+	dbCfg := service.LocalConfig.DB
+
+	return pg.NewPool(ctx, &pg.PoolConfig{
+		ConnConfig: pg.ConnConfig{
+			Host:   dbCfg.Host,
+			Port:   dbCfg.Port,
+			User:   dbCfg.User,
+			Pass:   dbCfg.Pass,
+			DBName: dbCfg.DBName,
+		},
+		MaxConns: 2,
+	})
+}
+
 // execute executes a SQL statement with the given values.
 func execute(ctx context.Context, db sql.DB, stmt string, args ...any) error {
 	return queryRowFunc(ctx, db, stmt, nil, func() error { return nil }, args...)
@@ -0,0 +1,64 @@
+package interpreter
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/kwilteam/kwil-db/common"
+	"github.com/kwilteam/kwil-db/node/engine"
+)
+
+// TenantConfig routes calls from different tenants of an application to
+// their own namespaces. The namespace of a tenant is TenantNamespacePrefix,
+// followed by the tenant ID, an underscore, and the namespace that was called.
+// For example, with the prefix "t_", a call to the namespace "app" by the
+// tenant "acme" is routed to "t_acme_app".
+//
+// The tenant ID is taken from the call's metadata, so it is only as trusted as
+// the metadata. Nodes that route tenants should be behind a gateway that sets
+// it.
+type TenantConfig struct {
+	// TenantIDHeader is the name of the metadata, such as an RPC request
+	// header, that holds the tenant ID. If it is empty, calls are not routed.
+	TenantIDHeader string
+	// TenantNamespacePrefix prefixes the namespaces of tenants.
+	TenantNamespacePrefix string
+}
+
+// WithTenantConfig routes calls to the namespaces of the tenants that make
+// them.
+func WithTenantConfig(cfg *TenantConfig) InterpreterOpt {
+	return func(o *InterpreterOptions) {
+		o.Tenants = cfg
+	}
+}
+
+// validTenantID matches tenant IDs. Underscores are not allowed, so that the
+// namespaces of different tenants cannot collide.
+var validTenantID = regexp.MustCompile(`^[a-z0-9]+$`)
+
+// tenantNamespace returns the namespace a call should be routed to. If the
+// call has a valid tenant ID, and the tenant's namespace exists, it is the
+// tenant's namespace. Otherwise, it is the namespace that was called. It must
+// be called with the mutex held.
+func (t *ThreadSafeInterpreter) tenantNamespace(ctx *common.EngineContext, namespace string) string {
+	if t.tenants == nil || t.tenants.TenantIDHeader == "" {
+		return namespace
+	}
+
+	tenantID := strings.ToLower(ctx.Metadata[strings.ToLower(t.tenants.TenantIDHeader)])
+	if !validTenantID.MatchString(tenantID) {
+		return namespace
+	}
+
+	base := namespace
+	if base == "" {
+		base = engine.DefaultNamespace
+	}
+
+	tenantNamespace := t.tenants.TenantNamespacePrefix + tenantID + "_" + strings.ToLower(base)
+	if _, ok := t.i.namespaces[tenantNamespace]; !ok {
+		return namespace
+	}
+	return tenantNamespace
+}
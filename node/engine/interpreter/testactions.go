@@ -0,0 +1,30 @@
+package interpreter
+
+import (
+	"context"
+
+	"github.com/kwilteam/kwil-db/node/types/sql"
+)
+
+// createTestNamespace records that a namespace was declared with
+// @test_namespace, and can thus have actions declared with @test. The record
+// is deleted with the namespace.
+func createTestNamespace(ctx context.Context, db sql.DB, namespace string) error {
+	return execute(ctx, db, `INSERT INTO kwild_engine.test_namespaces (namespace) VALUES ($1)`, namespace)
+}
+
+// listTestNamespaces lists the namespaces declared with @test_namespace.
+func listTestNamespaces(ctx context.Context, db sql.DB) (map[string]bool, error) {
+	namespaces := make(map[string]bool)
+	var namespace string
+	err := queryRowFunc(ctx, db, `SELECT namespace FROM kwild_engine.test_namespaces`,
+		[]any{&namespace}, func() error {
+			namespaces[namespace] = true
+			return nil
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	return namespaces, nil
+}
@@ -0,0 +1,66 @@
+package interpreter
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kwilteam/kwil-db/node/engine"
+)
+
+// timestamptzTextLayout is the text format of timestamptz values. It matches
+// the format Postgres uses in the UTC time zone, so that casting to text gives
+// the same result in the engine and in SQL.
+const timestamptzTextLayout = "2006-01-02 15:04:05.999999-07"
+
+// timestamptzParseLayouts are the layouts of text that can be cast to a
+// timestamptz. Text without a time zone is in UTC.
+var timestamptzParseLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999Z07",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999",
+	time.DateOnly,
+}
+
+// formatTimestamptz formats a timestamptz as text.
+func formatTimestamptz(t time.Time) string {
+	return t.UTC().Format(timestamptzTextLayout)
+}
+
+// parseTimestamptz parses text as a timestamptz.
+func parseTimestamptz(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range timestamptzParseLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.UTC(), nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf(`invalid timestamptz "%s"`, s)
+}
+
+// blockTime implements now(). It returns the timestamp of the block, so that
+// every node gets the same time.
+func (e *executionContext) blockTime() (value, error) {
+	if e.engineCtx.InvalidTxCtx || e.engineCtx.TxContext.BlockContext == nil {
+		return nil, fmt.Errorf(`%w: "now" requires a block`, engine.ErrInvalidTxCtx)
+	}
+
+	return makeTimestamptz(time.Unix(e.engineCtx.TxContext.BlockContext.Timestamp, 0)), nil
+}
+
+// prepareBlockTime sets the block timestamp if a SQL statement reads it with
+// now().
+func (e *executionContext) prepareBlockTime(stmt string) error {
+	if !strings.Contains(stmt, engine.BlockTimestampSQL) {
+		return nil
+	}
+	if e.engineCtx.InvalidTxCtx || e.engineCtx.TxContext.BlockContext == nil {
+		return fmt.Errorf(`%w: "now" requires a block`, engine.ErrInvalidTxCtx)
+	}
+
+	// it is the setting that the history trigger reads
+	return e.setHistoryTimestamp()
+}
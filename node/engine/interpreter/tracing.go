@@ -0,0 +1,46 @@
+package interpreter
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/kwilteam/kwil-db/common"
+)
+
+// tracerName is the name of the tracer of the interpreter's spans.
+const tracerName = "github.com/kwilteam/kwil-db/node/engine/interpreter"
+
+// traceContext extracts W3C trace context from the metadata of a call.
+var traceContext = propagation.TraceContext{}
+
+// startCallSpan starts the span of a call. If the call's metadata has W3C trace
+// context headers, such as those of the RPC request that made it, the span is a
+// child of the remote span they identify, so that the call is part of the
+// caller's trace. Otherwise, it is a child of the span of the call's context,
+// if any.
+func startCallSpan(ctx *common.EngineContext, namespace, action string) trace.Span {
+	parent := ctx.TxContext.Ctx
+	if len(ctx.Metadata) > 0 {
+		// metadata keys are lower case, as are the W3C header names
+		parent = traceContext.Extract(parent, propagation.MapCarrier(ctx.Metadata))
+	}
+
+	_, span := otel.Tracer(tracerName).Start(parent, "engine.call",
+		trace.WithAttributes(attribute.String("namespace", namespace), attribute.String("action", action)))
+	return span
+}
+
+// endCallSpan ends the span of a call, recording its error, if any.
+func endCallSpan(span trace.Span, res *common.CallResult, err error) {
+	if err == nil && res != nil && res.Error != nil {
+		err = res.Error
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,61 @@
+package interpreter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/kwilteam/kwil-db/common"
+)
+
+func Test_CallTraceContext(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	prevProvider := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(prevProvider) })
+
+	interp := &ThreadSafeInterpreter{
+		i:         &baseInterpreter{},
+		baselines: newCallBaselines(),
+	}
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	const parentID = "00f067aa0ba902b7"
+	engCtx := &common.EngineContext{
+		TxContext: &common.TxContext{Ctx: context.Background()},
+		Metadata: map[string]string{
+			"traceparent": "00-" + traceID + "-" + parentID + "-01",
+			"tracestate":  "kwil=1",
+		},
+	}
+
+	// the call fails, since the database cannot be locked, but its span is
+	// still recorded
+	_, err := interp.Call(engCtx, nil, "main", "get_user", nil, nil)
+	require.Error(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	span := spans[0]
+	require.Equal(t, "engine.call", span.Name())
+	require.Equal(t, traceID, span.SpanContext().TraceID().String())
+	require.Equal(t, parentID, span.Parent().SpanID().String())
+	require.True(t, span.Parent().IsRemote())
+	require.Equal(t, "kwil=1", span.SpanContext().TraceState().String())
+	require.Equal(t, codes.Error, span.Status().Code)
+
+	// without trace context, the call starts a new trace
+	engCtx.Metadata = nil
+	_, err = interp.Call(engCtx, nil, "main", "get_user", nil, nil)
+	require.Error(t, err)
+
+	spans = recorder.Ended()
+	require.Len(t, spans, 2)
+	require.NotEqual(t, traceID, spans[1].SpanContext().TraceID().String())
+	require.False(t, spans[1].Parent().IsValid())
+}
@@ -27,6 +27,28 @@ type action struct {
 
 	// Returns specifies the return types of the action.
 	Returns *actionReturn `json:"return_types"`
+
+	// OptimizerHints are the optimizer hints used by the action's queries.
+	OptimizerHints []*engine.OptimizerHint `json:"optimizer_hints"`
+
+	// Distinct is true if the rows returned with RETURN SELECT are
+	// de-duplicated.
+	Distinct bool `json:"distinct"`
+	// DistinctOn are the returned columns that rows are de-duplicated on. If
+	// it is empty, rows are de-duplicated on all columns.
+	DistinctOn []string `json:"distinct_on"`
+
+	// Idempotent is true if repeated calls with the same caller and arguments
+	// return the stored result of the first call.
+	Idempotent bool `json:"idempotent"`
+
+	// Test is true if the action is a test action, run by the schema test
+	// command.
+	Test bool `json:"test"`
+
+	// ErrorTypes are the application errors the action can raise with
+	// raise_error.
+	ErrorTypes []*engine.ErrorType `json:"error_types"`
 }
 
 func (a *action) GetName() string {
@@ -40,6 +62,12 @@ func (a *action) FromAST(ast *parse.CreateActionStatement) error {
 	a.Body = ast.Statements
 
 	a.Parameters = ast.Parameters
+	a.OptimizerHints = ast.OptimizerHints
+	a.Distinct = ast.Distinct
+	a.DistinctOn = ast.DistinctOn
+	a.Idempotent = ast.Idempotent
+	a.Test = ast.Test
+	a.ErrorTypes = ast.ErrorTypes
 
 	if ast.Returns != nil {
 		a.Returns = &actionReturn{
@@ -48,32 +76,11 @@ func (a *action) FromAST(ast *parse.CreateActionStatement) error {
 		}
 	}
 
-	modSet := make(map[precompiles.Modifier]struct{})
-	a.Modifiers = []precompiles.Modifier{}
-	hasPublicPrivateOrSystem := false
-	for _, m := range ast.Modifiers {
-		mod, err := stringToMod(m)
-		if err != nil {
-			return err
-		}
-
-		if mod == precompiles.PUBLIC || mod == precompiles.PRIVATE || mod == precompiles.SYSTEM {
-			if hasPublicPrivateOrSystem {
-				return fmt.Errorf("only one of PUBLIC, PRIVATE, or SYSTEM is allowed")
-			}
-
-			hasPublicPrivateOrSystem = true
-		}
-
-		if _, ok := modSet[mod]; !ok {
-			modSet[mod] = struct{}{}
-			a.Modifiers = append(a.Modifiers, mod)
-		}
-	}
-
-	if !hasPublicPrivateOrSystem {
-		return fmt.Errorf(`one of PUBLIC, PRIVATE, or SYSTEM access modifier is required. received: "%s"`, strings.Join(ast.Modifiers, ", "))
+	mods, err := convertModifiersToEngine(ast.Modifiers)
+	if err != nil {
+		return err
 	}
+	a.Modifiers = mods
 
 	return nil
 }
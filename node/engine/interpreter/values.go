@@ -1,14 +1,18 @@
 package interpreter
 
 import (
+	"cmp"
 	"database/sql/driver"
 	"errors"
 	"fmt"
 	"math"
+	"net/netip"
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/cockroachdb/apd/v3"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/kwilteam/kwil-db/core/types"
 	"github.com/kwilteam/kwil-db/node/engine"
@@ -114,6 +118,50 @@ func init() {
 				}, nil
 			},
 		},
+		valueMapping{
+			KwilType: types.TimestampTZType,
+			ZeroValue: func(t *types.DataType) (value, error) {
+				return makeTimestamptz(time.Unix(0, 0)), nil
+			},
+			NullValue: func(t *types.DataType) (value, error) {
+				return &timestamptzValue{
+					Timestamptz: pgtype.Timestamptz{
+						Valid: false,
+					},
+				}, nil
+			},
+		},
+		valueMapping{
+			KwilType: types.IntervalType,
+			ZeroValue: func(t *types.DataType) (value, error) {
+				return makeInterval(0), nil
+			},
+			NullValue: func(t *types.DataType) (value, error) {
+				return &intervalValue{
+					Interval: pgtype.Interval{
+						Valid: false,
+					},
+				}, nil
+			},
+		},
+		valueMapping{
+			KwilType: types.InetType,
+			ZeroValue: func(t *types.DataType) (value, error) {
+				return makeInet(netip.IPv4Unspecified()), nil
+			},
+			NullValue: func(t *types.DataType) (value, error) {
+				return &inetValue{}, nil
+			},
+		},
+		valueMapping{
+			KwilType: types.CIDRType,
+			ZeroValue: func(t *types.DataType) (value, error) {
+				return makeCIDR(netip.PrefixFrom(netip.IPv4Unspecified(), 0)), nil
+			},
+			NullValue: func(t *types.DataType) (value, error) {
+				return &cidrValue{}, nil
+			},
+		},
 		valueMapping{
 			KwilType: types.NumericType,
 			ZeroValue: func(t *types.DataType) (value, error) {
@@ -230,6 +278,58 @@ func init() {
 				}, nil
 			},
 		},
+		valueMapping{
+			KwilType: types.TimestampTZArrayType,
+			ZeroValue: func(t *types.DataType) (value, error) {
+				return &timestamptzArrayValue{
+					singleDimArray: newValidArr([]pgtype.Timestamptz{}),
+				}, nil
+			},
+			NullValue: func(t *types.DataType) (value, error) {
+				return &timestamptzArrayValue{
+					singleDimArray: newNullArray[pgtype.Timestamptz](),
+				}, nil
+			},
+		},
+		valueMapping{
+			KwilType: types.IntervalArrayType,
+			ZeroValue: func(t *types.DataType) (value, error) {
+				return &intervalArrayValue{
+					singleDimArray: newValidArr([]pgtype.Interval{}),
+				}, nil
+			},
+			NullValue: func(t *types.DataType) (value, error) {
+				return &intervalArrayValue{
+					singleDimArray: newNullArray[pgtype.Interval](),
+				}, nil
+			},
+		},
+		valueMapping{
+			KwilType: types.InetArrayType,
+			ZeroValue: func(t *types.DataType) (value, error) {
+				return &inetArrayValue{
+					singleDimArray: newValidArr([]netip.Addr{}),
+				}, nil
+			},
+			NullValue: func(t *types.DataType) (value, error) {
+				return &inetArrayValue{
+					singleDimArray: newNullArray[netip.Addr](),
+				}, nil
+			},
+		},
+		valueMapping{
+			KwilType: types.CIDRArrayType,
+			ZeroValue: func(t *types.DataType) (value, error) {
+				return &cidrArrayValue{
+					singleDimArray: newValidArr([]netip.Prefix{}),
+				}, nil
+			},
+			NullValue: func(t *types.DataType) (value, error) {
+				return &cidrArrayValue{
+					singleDimArray: newNullArray[netip.Prefix](),
+				}, nil
+			},
+		},
 		valueMapping{
 			KwilType: types.NullType,
 			ZeroValue: func(t *types.DataType) (value, error) {
@@ -273,8 +373,9 @@ type value interface {
 	// Type returns the type of the variable.
 	Type() *types.DataType
 	// RawValue returns the value of the variable.
-	// This is one of: nil, int64, string, bool, []byte, *types.UUID, *decimal.Decimal,
-	// []*int64, []*string, []*bool, [][]byte, []*decimal.Decimal, []*types.UUID
+	// This is one of: nil, int64, string, bool, []byte, *types.UUID, *decimal.Decimal, time.Time, time.Duration,
+	// netip.Addr, netip.Prefix, []*int64, []*string, []*bool, [][]byte, []*decimal.Decimal, []*types.UUID,
+	// []*time.Time, []*time.Duration, []*netip.Addr, []*netip.Prefix
 	RawValue() any
 	// Null returns true if the variable is null.
 	Null() bool
@@ -387,6 +488,47 @@ func newValue(v any) (value, error) {
 		return makeDecimal(v), nil
 	case types.Decimal:
 		return makeDecimal(&v), nil
+	case time.Time:
+		return makeTimestamptz(v), nil
+	case *time.Time:
+		if v == nil {
+			return makeNull(types.TimestampTZType)
+		}
+		return makeTimestamptz(*v), nil
+	case time.Duration:
+		return makeInterval(v), nil
+	case *time.Duration:
+		if v == nil {
+			return makeNull(types.IntervalType)
+		}
+		return makeInterval(*v), nil
+	case netip.Addr:
+		if !v.IsValid() {
+			return makeNull(types.InetType)
+		}
+		return makeInet(v), nil
+	case *netip.Addr:
+		if v == nil || !v.IsValid() {
+			return makeNull(types.InetType)
+		}
+		return makeInet(*v), nil
+	case netip.Prefix:
+		if !v.IsValid() {
+			return makeNull(types.CIDRType)
+		}
+		return newCIDR(v)
+	case *netip.Prefix:
+		if v == nil || !v.IsValid() {
+			return makeNull(types.CIDRType)
+		}
+		return newCIDR(*v)
+	case *apd.Decimal:
+		if v == nil {
+			return makeDecimal(nil), nil
+		}
+		return newDecimalFromAPD(v)
+	case apd.Decimal:
+		return newDecimalFromAPD(&v)
 	case []int64:
 		if v == nil {
 			return makeNull(types.IntArrayType)
@@ -590,6 +732,70 @@ func newValue(v any) (value, error) {
 		return &uuidArrayValue{
 			singleDimArray: newValidArr(pgUUIDs),
 		}, nil
+	case []*time.Time:
+		if v == nil {
+			return makeNull(types.TimestampTZArrayType)
+		}
+
+		return newTimestamptzArrayValue(v), nil
+	case []time.Time:
+		if v == nil {
+			return makeNull(types.TimestampTZArrayType)
+		}
+
+		ptrs := make([]*time.Time, len(v))
+		for i := range v {
+			ptrs[i] = &v[i]
+		}
+		return newTimestamptzArrayValue(ptrs), nil
+	case []*time.Duration:
+		if v == nil {
+			return makeNull(types.IntervalArrayType)
+		}
+
+		return newIntervalArrayValue(v), nil
+	case []time.Duration:
+		if v == nil {
+			return makeNull(types.IntervalArrayType)
+		}
+
+		ptrs := make([]*time.Duration, len(v))
+		for i := range v {
+			ptrs[i] = &v[i]
+		}
+		return newIntervalArrayValue(ptrs), nil
+	case []*netip.Addr:
+		if v == nil {
+			return makeNull(types.InetArrayType)
+		}
+
+		return newInetArrayValue(v), nil
+	case []netip.Addr:
+		if v == nil {
+			return makeNull(types.InetArrayType)
+		}
+
+		ptrs := make([]*netip.Addr, len(v))
+		for i := range v {
+			ptrs[i] = &v[i]
+		}
+		return newInetArrayValue(ptrs), nil
+	case []*netip.Prefix:
+		if v == nil {
+			return makeNull(types.CIDRArrayType)
+		}
+
+		return newCIDRArrayValue(v)
+	case []netip.Prefix:
+		if v == nil {
+			return makeNull(types.CIDRArrayType)
+		}
+
+		ptrs := make([]*netip.Prefix, len(v))
+		for i := range v {
+			ptrs[i] = &v[i]
+		}
+		return newCIDRArrayValue(ptrs)
 	case nil:
 		return &nullValue{}, nil
 	case []any:
@@ -608,6 +814,9 @@ func newValue(v any) (value, error) {
 		// TODO: handle this with a type switch
 		ref := reflect.ValueOf(v)
 		if ref.Kind() == reflect.Ptr {
+			if ref.IsNil() {
+				return &nullValue{}, nil
+			}
 			return newValue(ref.Elem().Interface())
 		}
 		return nil, fmt.Errorf("unexpected type %T", v)
@@ -977,6 +1186,34 @@ func (s *textValue) Cast(t *types.DataType) (value, error) {
 		}
 
 		return makeUUID(u), nil
+	case *types.TimestampTZType:
+		ts, err := parseTimestamptz(s.String)
+		if err != nil {
+			return nil, castErr(err)
+		}
+
+		return makeTimestamptz(ts), nil
+	case *types.IntervalType:
+		d, err := types.ParseInterval(s.String)
+		if err != nil {
+			return nil, castErr(err)
+		}
+
+		return makeInterval(d), nil
+	case *types.InetType:
+		addr, err := types.ParseInet(s.String)
+		if err != nil {
+			return nil, castErr(err)
+		}
+
+		return makeInet(addr), nil
+	case *types.CIDRType:
+		prefix, err := types.ParseCIDR(s.String)
+		if err != nil {
+			return nil, castErr(err)
+		}
+
+		return makeCIDR(prefix), nil
 	case *types.ByteaType:
 		return makeBlob([]byte(s.String)), nil
 	default:
@@ -1254,45 +1491,415 @@ func (u *uuidValue) Compare(v value, op comparisonOp) (*boolValue, error) {
 		return nil, fmt.Errorf("%w: cannot use comparison operator %s with type %s", engine.ErrComparison, u.Type(), op)
 	}
 
-	return makeBool(b), nil
+	return makeBool(b), nil
+}
+
+func (u *uuidValue) Arithmetic(v scalarValue, op arithmeticOp) (scalarValue, error) {
+	return nil, fmt.Errorf("%w: cannot perform arithmetic operation on uuid", engine.ErrArithmetic)
+}
+
+func (u *uuidValue) Unary(op unaryOp) (scalarValue, error) {
+	return nil, fmt.Errorf("%w: cannot perform unary operation on uuid", engine.ErrUnary)
+}
+
+func (u *uuidValue) Type() *types.DataType {
+	return types.UUIDType
+}
+
+func (u *uuidValue) RawValue() any {
+	if !u.Valid {
+		return nil
+	}
+
+	// kwil always handled uuids as pointers
+	u2 := types.UUID(u.Bytes)
+	return &u2
+}
+
+func (u *uuidValue) Cast(t *types.DataType) (value, error) {
+	if u.Null() {
+		return makeNull(t)
+	}
+
+	switch *t {
+	case *types.TextType:
+		return makeText(types.UUID(u.Bytes).String()), nil
+	case *types.ByteaType:
+		return makeBlob(u.Bytes[:]), nil
+	case *types.UUIDType:
+		return u, nil
+	default:
+		return nil, castErr(fmt.Errorf("cannot cast uuid to %s", t))
+	}
+}
+
+// makeTimestamptz creates a timestamptz value. It is rounded to microseconds,
+// which is the precision of timestamps in Postgres.
+func makeTimestamptz(t time.Time) *timestamptzValue {
+	return &timestamptzValue{
+		Timestamptz: pgtype.Timestamptz{
+			Time:  t.UTC().Round(time.Microsecond),
+			Valid: true,
+		},
+	}
+}
+
+type timestamptzValue struct {
+	pgtype.Timestamptz
+}
+
+func (ts *timestamptzValue) Null() bool {
+	return !ts.Valid
+}
+
+func (ts *timestamptzValue) Compare(v value, op comparisonOp) (*boolValue, error) {
+	if res, early := nullCmp(ts, v, op); early {
+		return res, nil
+	}
+
+	val2, ok := v.(*timestamptzValue)
+	if !ok {
+		return nil, makeTypeErr(ts, v)
+	}
+
+	return cmpIntegers(ts.Time.Compare(val2.Time), 0, op)
+}
+
+func (ts *timestamptzValue) Arithmetic(v scalarValue, op arithmeticOp) (scalarValue, error) {
+	return nil, fmt.Errorf("%w: cannot perform arithmetic operation on timestamptz", engine.ErrArithmetic)
+}
+
+func (ts *timestamptzValue) Unary(op unaryOp) (scalarValue, error) {
+	return nil, fmt.Errorf("%w: cannot perform unary operation on timestamptz", engine.ErrUnary)
+}
+
+func (ts *timestamptzValue) Type() *types.DataType {
+	return types.TimestampTZType
+}
+
+func (ts *timestamptzValue) RawValue() any {
+	if !ts.Valid {
+		return nil
+	}
+
+	return ts.Time.UTC()
+}
+
+func (ts *timestamptzValue) Cast(t *types.DataType) (value, error) {
+	if ts.Null() {
+		return makeNull(t)
+	}
+
+	switch *t {
+	case *types.TextType:
+		return makeText(formatTimestamptz(ts.Time)), nil
+	case *types.TimestampTZType:
+		return ts, nil
+	default:
+		return nil, castErr(fmt.Errorf("cannot cast timestamptz to %s", t))
+	}
+}
+
+// makeInterval creates an interval value. It is rounded to microseconds, and
+// split into days and microseconds, like intervals are formatted.
+func makeInterval(d time.Duration) *intervalValue {
+	micros := d.Round(time.Microsecond).Microseconds()
+	return &intervalValue{
+		Interval: pgtype.Interval{
+			Days:         int32(micros / microsPerDay),
+			Microseconds: micros % microsPerDay,
+			Valid:        true,
+		},
+	}
+}
+
+// microsPerDay is the number of microseconds in a day of an interval.
+const microsPerDay = 24 * 60 * 60 * 1_000_000
+
+type intervalValue struct {
+	pgtype.Interval
+}
+
+// duration returns the length of the interval.
+func (iv *intervalValue) duration() time.Duration {
+	return time.Duration(int64(iv.Days)*microsPerDay+iv.Microseconds) * time.Microsecond
+}
+
+func (iv *intervalValue) Null() bool {
+	return !iv.Valid
+}
+
+func (iv *intervalValue) Compare(v value, op comparisonOp) (*boolValue, error) {
+	if res, early := nullCmp(iv, v, op); early {
+		return res, nil
+	}
+
+	val2, ok := v.(*intervalValue)
+	if !ok {
+		return nil, makeTypeErr(iv, v)
+	}
+
+	return cmpIntegers(cmp.Compare(iv.duration(), val2.duration()), 0, op)
+}
+
+func (iv *intervalValue) Arithmetic(v scalarValue, op arithmeticOp) (scalarValue, error) {
+	if res, early := checkScalarNulls(iv, v); early {
+		return res, nil
+	}
+
+	val2, ok := v.(*intervalValue)
+	if !ok {
+		return nil, makeTypeErr(iv, v)
+	}
+
+	a, b := iv.duration(), val2.duration()
+	var r time.Duration
+	switch op {
+	case _ADD:
+		r = a + b
+		if (b > 0 && r < a) || (b < 0 && r > a) {
+			return nil, fmt.Errorf("%w: interval out of range", engine.ErrArithmetic)
+		}
+	case _SUB:
+		r = a - b
+		if (b > 0 && r > a) || (b < 0 && r < a) {
+			return nil, fmt.Errorf("%w: interval out of range", engine.ErrArithmetic)
+		}
+	default:
+		return nil, fmt.Errorf("%w: cannot perform arithmetic operation %s on type interval", engine.ErrArithmetic, op)
+	}
+
+	return makeInterval(r), nil
+}
+
+func (iv *intervalValue) Unary(op unaryOp) (scalarValue, error) {
+	return nil, fmt.Errorf("%w: cannot perform unary operation on interval", engine.ErrUnary)
+}
+
+func (iv *intervalValue) Type() *types.DataType {
+	return types.IntervalType
+}
+
+func (iv *intervalValue) RawValue() any {
+	if !iv.Valid {
+		return nil
+	}
+
+	return iv.duration()
+}
+
+func (iv *intervalValue) Cast(t *types.DataType) (value, error) {
+	if iv.Null() {
+		return makeNull(t)
+	}
+
+	switch *t {
+	case *types.TextType:
+		return makeText(types.FormatInterval(iv.duration())), nil
+	case *types.IntervalType:
+		return iv, nil
+	default:
+		return nil, castErr(fmt.Errorf("cannot cast interval to %s", t))
+	}
+}
+
+// makeInet creates an inet value.
+func makeInet(addr netip.Addr) *inetValue {
+	return &inetValue{Addr: addr}
+}
+
+// inetValue is an IPv4 or IPv6 address. pgtype does not have a type for inets,
+// so it implements the netip interfaces of pgx itself. An invalid address is
+// null.
+type inetValue struct {
+	Addr netip.Addr
+}
+
+// ScanNetipPrefix implements pgtype.NetipPrefixScanner. Inets with a netmask
+// are not supported.
+func (iv *inetValue) ScanNetipPrefix(v netip.Prefix) error {
+	if !v.IsValid() {
+		iv.Addr = netip.Addr{}
+		return nil
+	}
+	if v.Bits() != v.Addr().BitLen() {
+		return fmt.Errorf("inet %s has a netmask, which is not supported", v)
+	}
+
+	iv.Addr = v.Addr()
+	return nil
+}
+
+// NetipPrefixValue implements pgtype.NetipPrefixValuer.
+func (iv *inetValue) NetipPrefixValue() (netip.Prefix, error) {
+	if !iv.Addr.IsValid() {
+		return netip.Prefix{}, nil
+	}
+
+	return netip.PrefixFrom(iv.Addr, iv.Addr.BitLen()), nil
+}
+
+// Value implements driver.Valuer, which pgx uses to encode arguments whose type
+// is not known.
+func (iv *inetValue) Value() (driver.Value, error) {
+	if !iv.Addr.IsValid() {
+		return nil, nil
+	}
+
+	return iv.Addr.String(), nil
+}
+
+func (iv *inetValue) Null() bool {
+	return !iv.Addr.IsValid()
+}
+
+func (iv *inetValue) Compare(v value, op comparisonOp) (*boolValue, error) {
+	if res, early := nullCmp(iv, v, op); early {
+		return res, nil
+	}
+
+	val2, ok := v.(*inetValue)
+	if !ok {
+		return nil, makeTypeErr(iv, v)
+	}
+
+	return cmpIntegers(iv.Addr.Compare(val2.Addr), 0, op)
+}
+
+func (iv *inetValue) Arithmetic(v scalarValue, op arithmeticOp) (scalarValue, error) {
+	return nil, fmt.Errorf("%w: cannot perform arithmetic operation on inet", engine.ErrArithmetic)
+}
+
+func (iv *inetValue) Unary(op unaryOp) (scalarValue, error) {
+	return nil, fmt.Errorf("%w: cannot perform unary operation on inet", engine.ErrUnary)
+}
+
+func (iv *inetValue) Type() *types.DataType {
+	return types.InetType
+}
+
+func (iv *inetValue) RawValue() any {
+	if !iv.Addr.IsValid() {
+		return nil
+	}
+
+	return iv.Addr
+}
+
+func (iv *inetValue) Cast(t *types.DataType) (value, error) {
+	if iv.Null() {
+		return makeNull(t)
+	}
+
+	switch *t {
+	case *types.TextType:
+		return makeText(iv.Addr.String()), nil
+	case *types.InetType:
+		return iv, nil
+	case *types.CIDRType:
+		return makeCIDR(netip.PrefixFrom(iv.Addr, iv.Addr.BitLen())), nil
+	default:
+		return nil, castErr(fmt.Errorf("cannot cast inet to %s", t))
+	}
+}
+
+// makeCIDR creates a cidr value. The prefix must be masked.
+func makeCIDR(prefix netip.Prefix) *cidrValue {
+	return &cidrValue{Prefix: prefix}
+}
+
+// newCIDR creates a cidr value. Like Postgres, it does not allow bits to be set
+// to the right of the netmask.
+func newCIDR(prefix netip.Prefix) (*cidrValue, error) {
+	if prefix != prefix.Masked() {
+		return nil, fmt.Errorf("invalid cidr %s: bits are set to the right of the netmask", prefix)
+	}
+
+	return makeCIDR(prefix), nil
+}
+
+// cidrValue is an IPv4 or IPv6 network. Like inetValue, it implements the
+// netip interfaces of pgx. An invalid prefix is null.
+type cidrValue struct {
+	Prefix netip.Prefix
+}
+
+// ScanNetipPrefix implements pgtype.NetipPrefixScanner.
+func (c *cidrValue) ScanNetipPrefix(v netip.Prefix) error {
+	c.Prefix = v
+	return nil
+}
+
+// NetipPrefixValue implements pgtype.NetipPrefixValuer.
+func (c *cidrValue) NetipPrefixValue() (netip.Prefix, error) {
+	return c.Prefix, nil
+}
+
+// Value implements driver.Valuer.
+func (c *cidrValue) Value() (driver.Value, error) {
+	if !c.Prefix.IsValid() {
+		return nil, nil
+	}
+
+	return c.Prefix.String(), nil
+}
+
+func (c *cidrValue) Null() bool {
+	return !c.Prefix.IsValid()
+}
+
+func (c *cidrValue) Compare(v value, op comparisonOp) (*boolValue, error) {
+	if res, early := nullCmp(c, v, op); early {
+		return res, nil
+	}
+
+	val2, ok := v.(*cidrValue)
+	if !ok {
+		return nil, makeTypeErr(c, v)
+	}
+
+	// like Postgres, networks are ordered by their address, and then by the
+	// length of their netmask
+	res := c.Prefix.Addr().Compare(val2.Prefix.Addr())
+	if res == 0 {
+		res = cmp.Compare(c.Prefix.Bits(), val2.Prefix.Bits())
+	}
+
+	return cmpIntegers(res, 0, op)
 }
 
-func (u *uuidValue) Arithmetic(v scalarValue, op arithmeticOp) (scalarValue, error) {
-	return nil, fmt.Errorf("%w: cannot perform arithmetic operation on uuid", engine.ErrArithmetic)
+func (c *cidrValue) Arithmetic(v scalarValue, op arithmeticOp) (scalarValue, error) {
+	return nil, fmt.Errorf("%w: cannot perform arithmetic operation on cidr", engine.ErrArithmetic)
 }
 
-func (u *uuidValue) Unary(op unaryOp) (scalarValue, error) {
-	return nil, fmt.Errorf("%w: cannot perform unary operation on uuid", engine.ErrUnary)
+func (c *cidrValue) Unary(op unaryOp) (scalarValue, error) {
+	return nil, fmt.Errorf("%w: cannot perform unary operation on cidr", engine.ErrUnary)
 }
 
-func (u *uuidValue) Type() *types.DataType {
-	return types.UUIDType
+func (c *cidrValue) Type() *types.DataType {
+	return types.CIDRType
 }
 
-func (u *uuidValue) RawValue() any {
-	if !u.Valid {
+func (c *cidrValue) RawValue() any {
+	if !c.Prefix.IsValid() {
 		return nil
 	}
 
-	// kwil always handled uuids as pointers
-	u2 := types.UUID(u.Bytes)
-	return &u2
+	return c.Prefix
 }
 
-func (u *uuidValue) Cast(t *types.DataType) (value, error) {
-	if u.Null() {
+func (c *cidrValue) Cast(t *types.DataType) (value, error) {
+	if c.Null() {
 		return makeNull(t)
 	}
 
 	switch *t {
 	case *types.TextType:
-		return makeText(types.UUID(u.Bytes).String()), nil
-	case *types.ByteaType:
-		return makeBlob(u.Bytes[:]), nil
-	case *types.UUIDType:
-		return u, nil
+		return makeText(c.Prefix.String()), nil
+	case *types.CIDRType:
+		return c, nil
 	default:
-		return nil, castErr(fmt.Errorf("cannot cast uuid to %s", t))
+		return nil, castErr(fmt.Errorf("cannot cast cidr to %s", t))
 	}
 }
 
@@ -1365,6 +1972,29 @@ func makeDecimal(d *types.Decimal) *decimalValue {
 	}
 }
 
+// newDecimalFromAPD creates a decimal value from an apd decimal, with the
+// precision and scale of its digits.
+func newDecimalFromAPD(d *apd.Decimal) (*decimalValue, error) {
+	if d.Form == apd.NaN {
+		return makeDecimal(types.NewNaNDecimal()), nil
+	}
+	if d.Form != apd.Finite {
+		return nil, fmt.Errorf("cannot convert infinite decimal %s", d.String())
+	}
+
+	coeff := d.Coeff.MathBigInt()
+	if d.Negative {
+		coeff.Neg(coeff)
+	}
+
+	dec, err := types.NewDecimalFromBigInt(coeff, d.Exponent)
+	if err != nil {
+		return nil, err
+	}
+
+	return makeDecimal(dec), nil
+}
+
 type decimalValue struct {
 	pgtype.Numeric
 	metadata *precAndScale // can be nil
@@ -1898,6 +2528,18 @@ func (a *textArrayValue) Cast(t *types.DataType) (value, error) {
 		return castArr(a, strconv.ParseBool, newBoolArrayValue)
 	case *types.UUIDArrayType:
 		return castArrWithPtr(a, types.ParseUUID, newUUIDArrayValue)
+	case *types.TimestampTZArrayType:
+		return castArr(a, parseTimestamptz, newTimestamptzArrayValue)
+	case *types.IntervalArrayType:
+		return castArr(a, types.ParseInterval, newIntervalArrayValue)
+	case *types.InetArrayType:
+		return castArr(a, types.ParseInet, newInetArrayValue)
+	case *types.CIDRArrayType:
+		return castArr(a, types.ParseCIDR, func(prefixes []*netip.Prefix) *cidrArrayValue {
+			// the prefixes are masked by ParseCIDR
+			arr, _ := newCIDRArrayValue(prefixes)
+			return arr
+		})
 	case *types.TextArrayType:
 		return a, nil
 	case *types.ByteaArrayType:
@@ -2479,6 +3121,318 @@ func (a *uuidArrayValue) Cast(t *types.DataType) (value, error) {
 	}
 }
 
+func newTimestamptzArrayValue(ts []*time.Time) *timestamptzArrayValue {
+	vals := make([]pgtype.Timestamptz, len(ts))
+	for i, v := range ts {
+		if v == nil {
+			vals[i] = pgtype.Timestamptz{Valid: false}
+		} else {
+			vals[i] = makeTimestamptz(*v).Timestamptz
+		}
+	}
+
+	return &timestamptzArrayValue{
+		singleDimArray: newValidArr(vals),
+	}
+}
+
+type timestamptzArrayValue struct {
+	singleDimArray[pgtype.Timestamptz]
+}
+
+func (a *timestamptzArrayValue) Null() bool {
+	return !a.Valid
+}
+
+func (a *timestamptzArrayValue) Compare(v value, op comparisonOp) (*boolValue, error) {
+	return cmpArrs(a, v, op)
+}
+
+func (a *timestamptzArrayValue) Len() int32 {
+	return int32(len(a.Elements))
+}
+
+func (a *timestamptzArrayValue) Get(i int32) (scalarValue, error) {
+	return getArr(a, i, func(ts pgtype.Timestamptz) scalarValue {
+		return &timestamptzValue{ts}
+	})
+}
+
+func (a *timestamptzArrayValue) Set(i int32, v scalarValue) error {
+	return setArr(a, i, v, func(v2 *timestamptzValue) pgtype.Timestamptz {
+		return v2.Timestamptz
+	})
+}
+
+func (a *timestamptzArrayValue) Type() *types.DataType {
+	return types.TimestampTZArrayType
+}
+
+func (a *timestamptzArrayValue) RawValue() any {
+	if !a.Valid {
+		return nil
+	}
+
+	res := make([]*time.Time, len(a.Elements))
+	for i, v := range a.Elements {
+		if v.Valid {
+			ts := v.Time.UTC()
+			res[i] = &ts
+		}
+	}
+
+	return res
+}
+
+func (a *timestamptzArrayValue) Cast(t *types.DataType) (value, error) {
+	if a.Null() {
+		return makeNull(t)
+	}
+
+	switch *t {
+	case *types.TextArrayType:
+		return castArr(a, func(ts time.Time) (string, error) { return formatTimestamptz(ts), nil }, newTextArrayValue)
+	case *types.TimestampTZArrayType:
+		return a, nil
+	default:
+		return nil, castErr(fmt.Errorf("cannot cast timestamptz array to %s", t))
+	}
+}
+
+func newIntervalArrayValue(ds []*time.Duration) *intervalArrayValue {
+	vals := make([]pgtype.Interval, len(ds))
+	for i, v := range ds {
+		if v == nil {
+			vals[i] = pgtype.Interval{Valid: false}
+		} else {
+			vals[i] = makeInterval(*v).Interval
+		}
+	}
+
+	return &intervalArrayValue{
+		singleDimArray: newValidArr(vals),
+	}
+}
+
+type intervalArrayValue struct {
+	singleDimArray[pgtype.Interval]
+}
+
+func (a *intervalArrayValue) Null() bool {
+	return !a.Valid
+}
+
+func (a *intervalArrayValue) Compare(v value, op comparisonOp) (*boolValue, error) {
+	return cmpArrs(a, v, op)
+}
+
+func (a *intervalArrayValue) Len() int32 {
+	return int32(len(a.Elements))
+}
+
+func (a *intervalArrayValue) Get(i int32) (scalarValue, error) {
+	return getArr(a, i, func(iv pgtype.Interval) scalarValue {
+		return &intervalValue{iv}
+	})
+}
+
+func (a *intervalArrayValue) Set(i int32, v scalarValue) error {
+	return setArr(a, i, v, func(v2 *intervalValue) pgtype.Interval {
+		return v2.Interval
+	})
+}
+
+func (a *intervalArrayValue) Type() *types.DataType {
+	return types.IntervalArrayType
+}
+
+func (a *intervalArrayValue) RawValue() any {
+	if !a.Valid {
+		return nil
+	}
+
+	res := make([]*time.Duration, len(a.Elements))
+	for i, v := range a.Elements {
+		if v.Valid {
+			d := (&intervalValue{v}).duration()
+			res[i] = &d
+		}
+	}
+
+	return res
+}
+
+func (a *intervalArrayValue) Cast(t *types.DataType) (value, error) {
+	if a.Null() {
+		return makeNull(t)
+	}
+
+	switch *t {
+	case *types.TextArrayType:
+		return castArr(a, func(d time.Duration) (string, error) { return types.FormatInterval(d), nil }, newTextArrayValue)
+	case *types.IntervalArrayType:
+		return a, nil
+	default:
+		return nil, castErr(fmt.Errorf("cannot cast interval array to %s", t))
+	}
+}
+
+func newInetArrayValue(addrs []*netip.Addr) *inetArrayValue {
+	vals := make([]netip.Addr, len(addrs))
+	for i, v := range addrs {
+		// the zero address is null
+		if v != nil {
+			vals[i] = *v
+		}
+	}
+
+	return &inetArrayValue{
+		singleDimArray: newValidArr(vals),
+	}
+}
+
+type inetArrayValue struct {
+	singleDimArray[netip.Addr]
+}
+
+func (a *inetArrayValue) Null() bool {
+	return !a.Valid
+}
+
+func (a *inetArrayValue) Compare(v value, op comparisonOp) (*boolValue, error) {
+	return cmpArrs(a, v, op)
+}
+
+func (a *inetArrayValue) Len() int32 {
+	return int32(len(a.Elements))
+}
+
+func (a *inetArrayValue) Get(i int32) (scalarValue, error) {
+	return getArr(a, i, func(addr netip.Addr) scalarValue {
+		return makeInet(addr)
+	})
+}
+
+func (a *inetArrayValue) Set(i int32, v scalarValue) error {
+	return setArr(a, i, v, func(v2 *inetValue) netip.Addr {
+		return v2.Addr
+	})
+}
+
+func (a *inetArrayValue) Type() *types.DataType {
+	return types.InetArrayType
+}
+
+func (a *inetArrayValue) RawValue() any {
+	if !a.Valid {
+		return nil
+	}
+
+	res := make([]*netip.Addr, len(a.Elements))
+	for i, v := range a.Elements {
+		if v.IsValid() {
+			res[i] = &v
+		}
+	}
+
+	return res
+}
+
+func (a *inetArrayValue) Cast(t *types.DataType) (value, error) {
+	if a.Null() {
+		return makeNull(t)
+	}
+
+	switch *t {
+	case *types.TextArrayType:
+		return castArr(a, func(addr netip.Addr) (string, error) { return addr.String(), nil }, newTextArrayValue)
+	case *types.InetArrayType:
+		return a, nil
+	default:
+		return nil, castErr(fmt.Errorf("cannot cast inet array to %s", t))
+	}
+}
+
+func newCIDRArrayValue(prefixes []*netip.Prefix) (*cidrArrayValue, error) {
+	vals := make([]netip.Prefix, len(prefixes))
+	for i, v := range prefixes {
+		// the zero prefix is null
+		if v != nil {
+			c, err := newCIDR(*v)
+			if err != nil {
+				return nil, err
+			}
+			vals[i] = c.Prefix
+		}
+	}
+
+	return &cidrArrayValue{
+		singleDimArray: newValidArr(vals),
+	}, nil
+}
+
+type cidrArrayValue struct {
+	singleDimArray[netip.Prefix]
+}
+
+func (a *cidrArrayValue) Null() bool {
+	return !a.Valid
+}
+
+func (a *cidrArrayValue) Compare(v value, op comparisonOp) (*boolValue, error) {
+	return cmpArrs(a, v, op)
+}
+
+func (a *cidrArrayValue) Len() int32 {
+	return int32(len(a.Elements))
+}
+
+func (a *cidrArrayValue) Get(i int32) (scalarValue, error) {
+	return getArr(a, i, func(prefix netip.Prefix) scalarValue {
+		return makeCIDR(prefix)
+	})
+}
+
+func (a *cidrArrayValue) Set(i int32, v scalarValue) error {
+	return setArr(a, i, v, func(v2 *cidrValue) netip.Prefix {
+		return v2.Prefix
+	})
+}
+
+func (a *cidrArrayValue) Type() *types.DataType {
+	return types.CIDRArrayType
+}
+
+func (a *cidrArrayValue) RawValue() any {
+	if !a.Valid {
+		return nil
+	}
+
+	res := make([]*netip.Prefix, len(a.Elements))
+	for i, v := range a.Elements {
+		if v.IsValid() {
+			res[i] = &v
+		}
+	}
+
+	return res
+}
+
+func (a *cidrArrayValue) Cast(t *types.DataType) (value, error) {
+	if a.Null() {
+		return makeNull(t)
+	}
+
+	switch *t {
+	case *types.TextArrayType:
+		return castArr(a, func(prefix netip.Prefix) (string, error) { return prefix.String(), nil }, newTextArrayValue)
+	case *types.CIDRArrayType:
+		return a, nil
+	default:
+		return nil, castErr(fmt.Errorf("cannot cast cidr array to %s", t))
+	}
+}
+
 // emptyRecordValue creates a new empty record value.
 func emptyRecordValue() *recordValue {
 	return &recordValue{
@@ -2734,6 +3688,14 @@ func (n *arrayOfNulls) Cast(t *types.DataType) (value, error) {
 		return newBoolArrayValue(make([]*bool, n.length)), nil
 	case *types.UUIDArrayType:
 		return newUUIDArrayValue(make([]*types.UUID, n.length)), nil
+	case *types.TimestampTZArrayType:
+		return newTimestamptzArrayValue(make([]*time.Time, n.length)), nil
+	case *types.IntervalArrayType:
+		return newIntervalArrayValue(make([]*time.Duration, n.length)), nil
+	case *types.InetArrayType:
+		return newInetArrayValue(make([]*netip.Addr, n.length)), nil
+	case *types.CIDRArrayType:
+		return newCIDRArrayValue(make([]*netip.Prefix, n.length))
 	case *types.ByteaArrayType:
 		return newBlobArrayValue(make([][]byte, n.length)), nil
 	default:
@@ -2796,6 +3758,14 @@ func stringifyValue(v value) (string, error) {
 		return strconv.FormatBool(val.Bool.Bool), nil
 	case *uuidValue:
 		return types.UUID(val.UUID.Bytes).String(), nil
+	case *timestamptzValue:
+		return formatTimestamptz(val.Time), nil
+	case *intervalValue:
+		return types.FormatInterval(val.duration()), nil
+	case *inetValue:
+		return val.Addr.String(), nil
+	case *cidrValue:
+		return val.Prefix.String(), nil
 	case *decimalValue:
 		dec, err := val.dec()
 		if err != nil {
@@ -2856,6 +3826,34 @@ func parseValue(s string, t *types.DataType) (value, error) {
 		}
 
 		return makeUUID(u), nil
+	case *types.TimestampTZType:
+		ts, err := parseTimestamptz(s)
+		if err != nil {
+			return nil, err
+		}
+
+		return makeTimestamptz(ts), nil
+	case *types.IntervalType:
+		d, err := types.ParseInterval(s)
+		if err != nil {
+			return nil, err
+		}
+
+		return makeInterval(d), nil
+	case *types.InetType:
+		addr, err := types.ParseInet(s)
+		if err != nil {
+			return nil, err
+		}
+
+		return makeInet(addr), nil
+	case *types.CIDRType:
+		prefix, err := types.ParseCIDR(s)
+		if err != nil {
+			return nil, err
+		}
+
+		return makeCIDR(prefix), nil
 	case *types.ByteaType:
 		return makeBlob([]byte(s)), nil
 	default:
@@ -1,8 +1,12 @@
 package interpreter
 
 import (
+	"math"
+	"net/netip"
 	"testing"
+	"time"
 
+	"github.com/cockroachdb/apd/v3"
 	"github.com/kwilteam/kwil-db/core/types"
 	"github.com/kwilteam/kwil-db/node/engine"
 	"github.com/stretchr/testify/assert"
@@ -843,6 +847,158 @@ func Test_SetArrayNull(t *testing.T) {
 	}
 }
 
+func Test_NewValueAPD(t *testing.T) {
+	for _, str := range []string{"0.3", "-12.50", "1000", "0.0001"} {
+		d, _, err := apd.NewFromString(str)
+		require.NoError(t, err)
+
+		v, err := newValue(d)
+		require.NoError(t, err)
+
+		want := mustDec(str)
+		wantType, err := types.NewNumericType(want.Precision(), want.Scale())
+		require.NoError(t, err)
+		assert.True(t, v.Type().EqualsStrict(wantType), "%s: got type %s", str, v.Type())
+		assert.Equal(t, want.String(), v.RawValue().(*types.Decimal).String())
+
+		v, err = newValue(*d)
+		require.NoError(t, err)
+		assert.Equal(t, want.String(), v.RawValue().(*types.Decimal).String())
+	}
+
+	v, err := newValue((*apd.Decimal)(nil))
+	require.NoError(t, err)
+	assert.True(t, v.Null())
+	assert.Equal(t, types.NumericStr, v.Type().Name)
+
+	_, err = newValue(&apd.Decimal{Form: apd.Infinite})
+	require.Error(t, err)
+}
+
+func Test_Timestamptz(t *testing.T) {
+	// values are stored in UTC with microsecond precision, whatever their
+	// time zone
+	est := time.FixedZone("EST", -5*60*60)
+	v, err := newValue(time.Date(2024, 3, 10, 20, 30, 0, 1500, est))
+	require.NoError(t, err)
+	assert.True(t, v.Type().Equals(types.TimestampTZType))
+	assert.Equal(t, time.Date(2024, 3, 11, 1, 30, 0, 2000, time.UTC), v.RawValue())
+
+	txt, err := v.Cast(types.TextType)
+	require.NoError(t, err)
+	assert.Equal(t, "2024-03-11 01:30:00.000002+00", txt.RawValue())
+
+	// text is parsed in its own time zone, or in UTC if it has none
+	for _, str := range []string{"2024-03-11 01:30:00.000002+00", "2024-03-10T20:30:00.000002-05:00", "2024-03-11 01:30:00.000002"} {
+		ts, err := makeText(str).Cast(types.TimestampTZType)
+		require.NoError(t, err, str)
+		cmp, err := ts.Compare(v, _EQUAL)
+		require.NoError(t, err, str)
+		assert.True(t, cmp.RawValue().(bool), str)
+	}
+
+	_, err = makeText("yesterday").Cast(types.TimestampTZType)
+	require.Error(t, err)
+
+	later := makeTimestamptz(time.Date(2024, 3, 12, 0, 0, 0, 0, time.UTC))
+	cmp, err := v.Compare(later, _LESS_THAN)
+	require.NoError(t, err)
+	assert.True(t, cmp.RawValue().(bool))
+
+	_, err = later.Arithmetic(later, _ADD)
+	require.Error(t, err)
+
+	arr, err := newValue([]time.Time{time.Unix(0, 0).In(est)})
+	require.NoError(t, err)
+	assert.True(t, arr.Type().Equals(types.TimestampTZArrayType))
+	assert.Equal(t, []*time.Time{ptr(time.Unix(0, 0).UTC())}, arr.RawValue())
+}
+
+func Test_Interval(t *testing.T) {
+	day, err := newValue(24 * time.Hour)
+	require.NoError(t, err)
+	assert.True(t, day.Type().Equals(types.IntervalType))
+
+	twoDays := makeInterval(48 * time.Hour)
+
+	// 1 day - 2 days is -1 day
+	res, err := day.(scalarValue).Arithmetic(twoDays, _SUB)
+	require.NoError(t, err)
+	assert.Equal(t, -24*time.Hour, res.RawValue())
+
+	txt, err := res.Cast(types.TextType)
+	require.NoError(t, err)
+	assert.Equal(t, "-1 days", txt.RawValue())
+
+	res, err = res.Arithmetic(makeInterval(90*time.Minute), _ADD)
+	require.NoError(t, err)
+	assert.Equal(t, -(22*time.Hour + 30*time.Minute), res.RawValue())
+
+	cmp, err := res.Compare(day, _LESS_THAN)
+	require.NoError(t, err)
+	assert.True(t, cmp.RawValue().(bool))
+
+	parsed, err := makeText("1 day").Cast(types.IntervalType)
+	require.NoError(t, err)
+	cmp, err = parsed.Compare(day, _EQUAL)
+	require.NoError(t, err)
+	assert.True(t, cmp.RawValue().(bool))
+
+	_, err = makeInterval(time.Duration(math.MaxInt64)).Arithmetic(day.(scalarValue), _ADD)
+	require.ErrorIs(t, err, engine.ErrArithmetic)
+
+	_, err = day.(scalarValue).Arithmetic(twoDays, _MUL)
+	require.ErrorIs(t, err, engine.ErrArithmetic)
+
+	arr, err := newValue([]time.Duration{time.Second})
+	require.NoError(t, err)
+	assert.True(t, arr.Type().Equals(types.IntervalArrayType))
+	assert.Equal(t, []*time.Duration{ptr(time.Second)}, arr.RawValue())
+}
+
+func Test_InetAndCIDR(t *testing.T) {
+	addr, err := newValue(netip.MustParseAddr("192.168.1.1"))
+	require.NoError(t, err)
+	assert.True(t, addr.Type().Equals(types.InetType))
+
+	parsed, err := makeText("192.168.1.1/32").Cast(types.InetType)
+	require.NoError(t, err)
+	cmp, err := parsed.Compare(addr, _EQUAL)
+	require.NoError(t, err)
+	assert.True(t, cmp.RawValue().(bool))
+
+	network, err := makeText("192.168.0.0/16").Cast(types.CIDRType)
+	require.NoError(t, err)
+	assert.Equal(t, netip.MustParsePrefix("192.168.0.0/16"), network.RawValue())
+
+	txt, err := network.Cast(types.TextType)
+	require.NoError(t, err)
+	assert.Equal(t, "192.168.0.0/16", txt.RawValue())
+
+	// networks are ordered by their address, and then by their netmask
+	cmp, err = network.Compare(makeCIDR(netip.MustParsePrefix("192.168.0.0/24")), _LESS_THAN)
+	require.NoError(t, err)
+	assert.True(t, cmp.RawValue().(bool))
+
+	_, err = makeText("192.168.1.1/16").Cast(types.CIDRType)
+	require.Error(t, err)
+	_, err = newValue(netip.MustParsePrefix("192.168.1.1/16"))
+	require.Error(t, err)
+
+	// pgx scans inets as prefixes, and nulls as invalid prefixes
+	var iv inetValue
+	require.NoError(t, iv.ScanNetipPrefix(netip.MustParsePrefix("10.0.0.1/32")))
+	assert.Equal(t, netip.MustParseAddr("10.0.0.1"), iv.RawValue())
+	require.Error(t, iv.ScanNetipPrefix(netip.MustParsePrefix("10.0.0.1/8")))
+	require.NoError(t, iv.ScanNetipPrefix(netip.Prefix{}))
+	assert.True(t, iv.Null())
+
+	arr, err := newValue([]*netip.Addr{ptr(netip.MustParseAddr("::1")), nil})
+	require.NoError(t, err)
+	assert.True(t, arr.Type().Equals(types.InetArrayType))
+	assert.Equal(t, []*netip.Addr{ptr(netip.MustParseAddr("::1")), nil}, arr.RawValue())
+}
+
 // ptrArr is a helper function that converts a slice of values to a slice of pointers to those values.
 // Since Kwil returns pointers to account for nulls, we need to convert the slice of values to pointers
 func ptrArr[T any](arr []T) []*T {
@@ -0,0 +1,120 @@
+package interpreter
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/kwilteam/kwil-db/node/engine"
+	"github.com/kwilteam/kwil-db/node/types/sql"
+)
+
+// ErrorVerbosity is how much detail the errors of a namespace's calls give
+// about failures inside the engine, such as errors returned by Postgres.
+type ErrorVerbosity string
+
+const (
+	// ErrorVerbosityMinimal replaces internal errors with a generic error
+	// and an opaque error ID. The full error is logged with the ID, so that
+	// operators can investigate it.
+	ErrorVerbosityMinimal ErrorVerbosity = "minimal"
+	// ErrorVerbosityNormal returns internal errors as they are. It is the
+	// default.
+	ErrorVerbosityNormal ErrorVerbosity = "normal"
+	// ErrorVerbosityDetailed adds the statement that failed to internal
+	// errors.
+	ErrorVerbosityDetailed ErrorVerbosity = "detailed"
+)
+
+func (v ErrorVerbosity) valid() bool {
+	switch v {
+	case ErrorVerbosityMinimal, ErrorVerbosityNormal, ErrorVerbosityDetailed:
+		return true
+	default:
+		return false
+	}
+}
+
+// SetErrorVerbosity sets the error verbosity of a namespace. It takes effect
+// for calls made after it returns.
+func (t *ThreadSafeInterpreter) SetErrorVerbosity(ctx context.Context, db sql.DB, namespace string, verbosity ErrorVerbosity) error {
+	if !verbosity.valid() {
+		return fmt.Errorf(`unknown error verbosity "%s"`, verbosity)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	ns, ok := t.i.namespaces[namespace]
+	if !ok {
+		return fmt.Errorf(`%w: "%s"`, engine.ErrNamespaceNotFound, namespace)
+	}
+
+	err := execute(ctx, db, `INSERT INTO kwild_engine.namespace_config (namespace, error_verbosity) VALUES ($1, $2)
+	ON CONFLICT (namespace) DO UPDATE SET error_verbosity = $2`, namespace, string(verbosity))
+	if err != nil {
+		return err
+	}
+
+	ns.errorVerbosity = verbosity
+	return nil
+}
+
+// listErrorVerbosities lists the error verbosity of each namespace that has one
+// set.
+func listErrorVerbosities(ctx context.Context, db sql.DB) (map[string]ErrorVerbosity, error) {
+	verbosities := make(map[string]ErrorVerbosity)
+	var namespace, verbosity string
+	err := queryRowFunc(ctx, db, `SELECT namespace, error_verbosity FROM kwild_engine.namespace_config`,
+		[]any{&namespace, &verbosity}, func() error {
+			verbosities[namespace] = ErrorVerbosity(verbosity)
+			return nil
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	return verbosities, nil
+}
+
+// internalError is an internal error hidden from the caller by
+// ErrorVerbosityMinimal.
+type internalError struct {
+	id  string
+	err error
+}
+
+func (i *internalError) Error() string {
+	return fmt.Sprintf("internal error (error ID: %s)", i.id)
+}
+
+func (i *internalError) Unwrap() error {
+	return i.err
+}
+
+// reportInternalError returns the error of a failed statement with the detail
+// of the error verbosity of the current namespace. For ErrorVerbosityMinimal,
+// it logs the error with an ID derived from the transaction and the error, so
+// that every node reports the same ID.
+func (e *executionContext) reportInternalError(stmt string, err error) error {
+	var verbosity ErrorVerbosity
+	if ns, ok := e.interpreter.namespaces[e.scope.namespace]; ok {
+		verbosity = ns.errorVerbosity
+	}
+
+	switch verbosity {
+	case ErrorVerbosityMinimal:
+		sum := sha256.Sum256([]byte(e.engineCtx.TxContext.TxID + "\x00" + err.Error()))
+		id := hex.EncodeToString(sum[:8])
+		if e.interpreter.service != nil && e.interpreter.service.Logger != nil {
+			e.interpreter.service.Logger.Warn("internal error hidden from caller", "error_id", id,
+				"namespace", e.scope.namespace, "statement", stmt, "error", err)
+		}
+		return &internalError{id: id, err: err}
+	case ErrorVerbosityDetailed:
+		return fmt.Errorf("failed to execute %s: %w", stmt, err)
+	default:
+		return err
+	}
+}
@@ -0,0 +1,98 @@
+package interpreter
+
+import (
+	"context"
+	"errors"
+
+	"github.com/kwilteam/kwil-db/common"
+	"github.com/kwilteam/kwil-db/node/pg"
+	"github.com/kwilteam/kwil-db/node/wal"
+)
+
+// walSlotName is the name of the replication slot used by the engine's WAL
+// stream. It is distinct from the slot used by the pg.DB to compute commit IDs.
+const walSlotName = "kwild_engine_wal"
+
+// walEventBuffer is the capacity of the WAL event channel. If a consumer falls
+// behind by more than this, the stream stops polling until it catches up.
+const walEventBuffer = 1000
+
+// walStream is the engine's stream of committed row changes. It uses its own
+// connection pool since the DB given to NewInterpreter is typically a
+// transaction that is committed after startup.
+type walStream struct {
+	pool   *pg.Pool
+	events chan wal.WALEvent
+}
+
+func startWALStream(ctx context.Context, service *common.Service) (*walStream, error) {
+	pool, err := newServicePool(ctx, service)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan wal.WALEvent, walEventBuffer)
+	err = wal.StartWALStream(ctx, pool, walSlotName, events)
+	if err != nil {
+		pool.Close()
+		return nil, err
+	}
+
+	return &walStream{pool: pool, events: events}, nil
+}
+
+// StartWALStream starts the engine's stream of committed row changes. It
+// runs until ctx is cancelled or StopWALStream is called, which must be
+// called in either case to drop the stream's replication slot.
+func (t *ThreadSafeInterpreter) StartWALStream(ctx context.Context) error {
+	t.walMu.Lock()
+	defer t.walMu.Unlock()
+	if t.wal != nil {
+		return wal.ErrStreamExists
+	}
+
+	s, err := startWALStream(ctx, t.i.service)
+	if err != nil {
+		return err
+	}
+	t.wal = s
+	return nil
+}
+
+// WALEvents returns the stream of committed row changes. It is nil unless
+// StartWALStream was called.
+func (t *ThreadSafeInterpreter) WALEvents() <-chan wal.WALEvent {
+	t.walMu.Lock()
+	defer t.walMu.Unlock()
+	if t.wal == nil {
+		return nil
+	}
+	return t.wal.events
+}
+
+// StopWALStream stops the engine's WAL stream, if it was started, and drops
+// its replication slot.
+func (t *ThreadSafeInterpreter) StopWALStream() error {
+	t.walMu.Lock()
+	defer t.walMu.Unlock()
+	if t.wal == nil {
+		return nil
+	}
+	err := wal.StopWALStream(walSlotName)
+	err = errors.Join(err, t.wal.pool.Close())
+	t.wal = nil
+	return err
+}
+
+// DropWALStreamSlot drops the replication slot of the engine's WAL stream, if
+// one was left by a node that streamed changes and did not stop the stream.
+// Otherwise, postgres would retain WAL for the slot indefinitely.
+func (t *ThreadSafeInterpreter) DropWALStreamSlot(ctx context.Context) error {
+	pool, err := newServicePool(ctx, t.i.service)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	return wal.DropSlot(ctx, pool, walSlotName)
+}
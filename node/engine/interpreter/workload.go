@@ -0,0 +1,81 @@
+package interpreter
+
+import (
+	"fmt"
+
+	"github.com/kwilteam/kwil-db/common"
+	"github.com/kwilteam/kwil-db/node/types/sql"
+)
+
+// queryClassSettings are the Postgres settings applied for the rest of the
+// transaction of a call of each query class.
+var queryClassSettings = map[common.QueryClass][]string{
+	common.Analytical: {
+		`SET LOCAL work_mem = '1MB'`,
+		`SET LOCAL statement_timeout = '10s'`,
+	},
+	common.Reporting: {
+		`SET LOCAL work_mem = '64MB'`,
+	},
+}
+
+// applyQueryClass applies the settings of the call's query class. They are only
+// applied to read-only transactions, such as those of the read pool used for
+// RPC calls: a statement timeout or memory limit that depends on the node must
+// not change the outcome of a transaction that is part of consensus.
+func (e *executionContext) applyQueryClass() error {
+	if e.canMutateState {
+		return nil
+	}
+
+	for _, stmt := range queryClassSettings[e.engineCtx.QueryClass] {
+		if err := execute(e.engineCtx.TxContext.Ctx, e.db, stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// callAdmin calls an action for an admin. It is not limited by the concurrency
+// limit or the fair scheduler, and holds the write lock so that no other call
+// runs at the same time. Read-only calls run in a read transaction of the
+// service's AdminDB, if one is set, so that they do not wait for connections
+// of the pool db was made from. Calls that can change state always use db.
+func (t *ThreadSafeInterpreter) callAdmin(ctx *common.EngineContext, db sql.DB, namespace string, action string, args []any, resultFn func(*common.Row) error) (*common.CallResult, error) {
+	am, ok := db.(sql.AccessModer)
+	if !ok {
+		return nil, fmt.Errorf("database does not implement AccessModer")
+	}
+
+	if t.shards != nil {
+		if addr, ok := t.shards.route(db, namespace); ok {
+			return t.shards.forward(ctx, addr, namespace, action, args, resultFn)
+		}
+	}
+
+	if am.AccessMode() == sql.ReadOnly && t.i.service != nil && t.i.service.AdminDB != nil {
+		tx, err := beginAdminTx(ctx, t.i.service.AdminDB)
+		if err != nil {
+			return nil, err
+		}
+		defer tx.Rollback(ctx.TxContext.Ctx)
+		db = tx
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	namespace = t.tenantNamespace(ctx, namespace)
+
+	return t.i.call(ctx, db, namespace, action, args, resultFn, true, nil)
+}
+
+// beginAdminTx begins a transaction for an admin call, which is read-only if
+// the admin database supports it. It is always rolled back.
+func beginAdminTx(ctx *common.EngineContext, adminDB sql.DB) (sql.Tx, error) {
+	if rtm, ok := adminDB.(sql.ReadTxMaker); ok {
+		return rtm.BeginReadTx(ctx.TxContext.Ctx)
+	}
+
+	return adminDB.BeginTx(ctx.TxContext.Ctx)
+}
@@ -0,0 +1,90 @@
+package parse
+
+import (
+	"slices"
+	"strings"
+)
+
+// ActionGraph is the graph of the calls that the actions of a namespace make
+// to each other.
+type ActionGraph struct {
+	// Actions are the names of the actions, in sorted order.
+	Actions []string `json:"actions"`
+	// Calls are the calls between the actions, sorted by caller and callee.
+	Calls []*ActionGraphCall `json:"calls"`
+}
+
+// ActionGraphCall is an action calling another action.
+type ActionGraphCall struct {
+	Caller string `json:"caller"`
+	Callee string `json:"callee"`
+	// Cyclic is true if the callee, directly or indirectly, calls the
+	// caller, so that the call is part of a cycle.
+	Cyclic bool `json:"cyclic"`
+}
+
+// HasCycle returns true if any of the calls is part of a cycle.
+func (g *ActionGraph) HasCycle() bool {
+	return slices.ContainsFunc(g.Calls, func(c *ActionGraphCall) bool { return c.Cyclic })
+}
+
+// NewActionGraph returns the graph of the calls that the actions make to each
+// other. Calls qualified with a namespace only count if it is namespace.
+// Calls to anything other than the given actions, such as functions and
+// extension methods, are ignored.
+func NewActionGraph(namespace string, actions []*CreateActionStatement) *ActionGraph {
+	names := make(map[string]struct{}, len(actions))
+	for _, act := range actions {
+		names[strings.ToLower(act.Name)] = struct{}{}
+	}
+
+	graph := &ActionGraph{Actions: []string{}, Calls: []*ActionGraphCall{}}
+	callees := make(map[string][]string)
+	for _, act := range actions {
+		caller := strings.ToLower(act.Name)
+		graph.Actions = append(graph.Actions, caller)
+
+		RecursivelyVisitPositions(act.Statements, func(gp GetPositioner) {
+			call, ok := gp.(*ExpressionFunctionCall)
+			if !ok || (call.Namespace != "" && !strings.EqualFold(call.Namespace, namespace)) {
+				return
+			}
+			callee := strings.ToLower(call.Name)
+			if _, ok := names[callee]; !ok || slices.Contains(callees[caller], callee) {
+				return
+			}
+			callees[caller] = append(callees[caller], callee)
+			graph.Calls = append(graph.Calls, &ActionGraphCall{Caller: caller, Callee: callee})
+		})
+	}
+
+	for _, call := range graph.Calls {
+		call.Cyclic = reaches(callees, call.Callee, call.Caller)
+	}
+
+	slices.Sort(graph.Actions)
+	slices.SortFunc(graph.Calls, func(a, b *ActionGraphCall) int {
+		if c := strings.Compare(a.Caller, b.Caller); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Callee, b.Callee)
+	})
+	return graph
+}
+
+// reaches returns true if there is a path of calls from one action to another.
+func reaches(callees map[string][]string, from, to string) bool {
+	visited := make(map[string]bool)
+	var visit func(name string) bool
+	visit = func(name string) bool {
+		if name == to {
+			return true
+		}
+		if visited[name] {
+			return false
+		}
+		visited[name] = true
+		return slices.ContainsFunc(callees[name], visit)
+	}
+	return visit(from)
+}
@@ -0,0 +1,82 @@
+package parse_test
+
+import (
+	"testing"
+
+	"github.com/kwilteam/kwil-db/node/engine/parse"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_NewActionGraph(t *testing.T) {
+	parseActions := func(t *testing.T, schema string) []*parse.CreateActionStatement {
+		stmts, err := parse.Parse(schema)
+		require.NoError(t, err)
+
+		var actions []*parse.CreateActionStatement
+		for _, stmt := range stmts {
+			if act, ok := stmt.(*parse.CreateActionStatement); ok {
+				actions = append(actions, act)
+			}
+		}
+		return actions
+	}
+
+	tests := []struct {
+		name   string
+		schema string
+		want   []*parse.ActionGraphCall
+		cyclic bool
+	}{
+		{
+			name: "chain",
+			schema: `CREATE ACTION a() public { b(); };
+			CREATE ACTION b() public { $x := c(); };
+			CREATE ACTION c() public returns (x int) { return 1; };`,
+			want: []*parse.ActionGraphCall{
+				{Caller: "a", Callee: "b"},
+				{Caller: "b", Callee: "c"},
+			},
+		},
+		{
+			name: "cycle",
+			schema: `CREATE ACTION a() public { b(); };
+			CREATE ACTION b() public { c(); };
+			CREATE ACTION c() public { a(); d(); };
+			CREATE ACTION d() public {};`,
+			want: []*parse.ActionGraphCall{
+				{Caller: "a", Callee: "b", Cyclic: true},
+				{Caller: "b", Callee: "c", Cyclic: true},
+				{Caller: "c", Callee: "a", Cyclic: true},
+				{Caller: "c", Callee: "d"},
+			},
+			cyclic: true,
+		},
+		{
+			name: "recursion",
+			schema: `CREATE ACTION a($n int) public {
+				if $n > 0 {
+					a($n - 1);
+				}
+			};`,
+			want: []*parse.ActionGraphCall{
+				{Caller: "a", Callee: "a", Cyclic: true},
+			},
+			cyclic: true,
+		},
+		{
+			name: "repeated calls, functions, and other namespaces are not edges",
+			schema: `CREATE ACTION a() public { b(); b(); $x := abs(-1); other.b(); main.b(); };
+			CREATE ACTION b() public {};`,
+			want: []*parse.ActionGraphCall{
+				{Caller: "a", Callee: "b"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			graph := parse.NewActionGraph("main", parseActions(t, tt.schema))
+			require.Equal(t, tt.want, graph.Calls)
+			require.Equal(t, tt.cyclic, graph.HasCycle())
+		})
+	}
+}
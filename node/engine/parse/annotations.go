@@ -0,0 +1,298 @@
+package parse
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	antlr "github.com/antlr4-go/antlr/v4"
+	"github.com/kwilteam/kwil-db/node/engine"
+	"github.com/kwilteam/kwil-db/node/engine/parse/gen"
+)
+
+// Annotation is a directive attached to a statement or column definition. It
+// is written as a comment beginning with "@", placed immediately before the
+// element it applies to:
+//
+//	CREATE TABLE users (
+//		id INT PRIMARY KEY,
+//		-- @sensitive(partial(3))
+//		email TEXT
+//	);
+//
+// Since annotations are comments, they do not change the grammar, and tools
+// that are unaware of them will simply ignore them. Comments that are not
+// annotations, and annotations that are not known for an element, are ignored.
+type Annotation struct {
+	// Name is the name of the annotation, without the @.
+	Name string
+	// Args are the comma separated arguments passed in parentheses,
+	// with surrounding whitespace trimmed. Nested parentheses are
+	// kept as part of a single argument.
+	Args []string
+}
+
+// getAnnotations returns the annotations in the comments directly preceding
+// the given rule.
+func (s *schemaVisitor) getAnnotations(ctx antlr.ParserRuleContext) []*Annotation {
+	if s.tokens == nil || ctx.GetStart() == nil {
+		return nil
+	}
+
+	var annotations []*Annotation
+	for _, tok := range s.tokens.GetHiddenTokensToLeft(ctx.GetStart().GetTokenIndex(), antlr.TokenHiddenChannel) {
+		text, ok := commentText(tok)
+		if !ok || !strings.HasPrefix(text, "@") {
+			continue
+		}
+		// import directives apply to the schema, not to a statement
+		if _, ok := importDirective(text); ok {
+			continue
+		}
+
+		a, ok := parseAnnotation(text[1:])
+		if !ok {
+			s.errs.TokenErr(tok, ErrAnnotation, "malformed annotation: %s", text)
+			continue
+		}
+		annotations = append(annotations, a)
+	}
+
+	return annotations
+}
+
+// commentText returns the text of a comment token, without its delimiters and
+// surrounding whitespace. It returns false if the token is not a comment.
+func commentText(tok antlr.Token) (string, bool) {
+	var text string
+	switch tok.GetTokenType() {
+	case gen.KuneiformLexerBLOCK_COMMENT:
+		text = strings.TrimSuffix(strings.TrimPrefix(tok.GetText(), "/*"), "*/")
+	case gen.KuneiformLexerLINE_COMMENT:
+		text = strings.TrimPrefix(tok.GetText(), "//")
+	case gen.KuneiformLexerSQL_COMMENT:
+		text = strings.TrimPrefix(tok.GetText(), "--")
+	default:
+		return "", false
+	}
+
+	return strings.TrimSpace(text), true
+}
+
+// leadingComments returns the source text of the comments directly preceding
+// the given rule, up to the start of the rule, as they were written.
+func (s *schemaVisitor) leadingComments(ctx antlr.ParserRuleContext) string {
+	if s.tokens == nil || ctx.GetStart() == nil {
+		return ""
+	}
+
+	for _, tok := range s.tokens.GetHiddenTokensToLeft(ctx.GetStart().GetTokenIndex(), antlr.TokenHiddenChannel) {
+		switch tok.GetTokenType() {
+		case gen.KuneiformLexerBLOCK_COMMENT, gen.KuneiformLexerLINE_COMMENT, gen.KuneiformLexerSQL_COMMENT:
+			return s.getTextFromStream(tok.GetStart(), ctx.GetStart().GetStart()-1)
+		}
+	}
+
+	return ""
+}
+
+// parseAnnotation parses the text of an annotation following the @, e.g.
+// "sensitive(partial(3))".
+func parseAnnotation(text string) (*Annotation, bool) {
+	name, rest, hasArgs := strings.Cut(text, "(")
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" || strings.ContainsAny(name, " \t") {
+		return nil, false
+	}
+
+	a := &Annotation{Name: name}
+	if !hasArgs {
+		return a, true
+	}
+
+	rest = strings.TrimSpace(rest)
+	if !strings.HasSuffix(rest, ")") {
+		return nil, false
+	}
+	rest = rest[:len(rest)-1]
+
+	depth, start := 0, 0
+	for i, r := range rest {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth < 0 {
+				return nil, false
+			}
+		case ',':
+			if depth == 0 {
+				a.Args = append(a.Args, strings.TrimSpace(rest[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	if depth != 0 {
+		return nil, false
+	}
+	if last := strings.TrimSpace(rest[start:]); last != "" || len(a.Args) > 0 {
+		a.Args = append(a.Args, last)
+	}
+
+	return a, true
+}
+
+// sensitivityPolicy converts a @sensitive annotation to a column's policy. The
+// single argument is the mask function: hash, partial(n), or redact.
+func sensitivityPolicy(a *Annotation) (*engine.SensitivityPolicy, error) {
+	if len(a.Args) != 1 {
+		return nil, fmt.Errorf("@sensitive expects one mask function, got %d arguments", len(a.Args))
+	}
+
+	mask, ok := parseAnnotation(a.Args[0])
+	if !ok {
+		return nil, fmt.Errorf("invalid mask function %s", a.Args[0])
+	}
+
+	switch engine.MaskFunction(mask.Name) {
+	case engine.MaskHash, engine.MaskRedact:
+		if len(mask.Args) != 0 {
+			return nil, fmt.Errorf("mask function %s takes no arguments", mask.Name)
+		}
+		return &engine.SensitivityPolicy{Mask: engine.MaskFunction(mask.Name)}, nil
+	case engine.MaskPartial:
+		if len(mask.Args) != 1 {
+			return nil, errors.New("mask function partial expects the number of characters to show")
+		}
+		n, err := strconv.ParseInt(mask.Args[0], 10, 64)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid partial length %s", mask.Args[0])
+		}
+		return &engine.SensitivityPolicy{Mask: engine.MaskPartial, PartialLength: n}, nil
+	default:
+		return nil, fmt.Errorf("unknown mask function %s", mask.Name)
+	}
+}
+
+// addOptimizerHint validates an @optimizer_hint annotation and adds its hint to
+// hints. The arguments are the name of a setting in engine.OptimizerHintSettings
+// and a boolean value, e.g. @optimizer_hint(enable_seqscan, false).
+func (s *schemaVisitor) addOptimizerHint(ctx antlr.ParserRuleContext, hints []*engine.OptimizerHint, a *Annotation) []*engine.OptimizerHint {
+	if len(a.Args) != 2 {
+		s.errs.RuleErr(ctx, ErrAnnotation, "@optimizer_hint expects a setting and a value, got %d arguments", len(a.Args))
+		return hints
+	}
+
+	name := strings.ToLower(a.Args[0])
+	if _, ok := engine.OptimizerHintSettings[name]; !ok {
+		s.errs.RuleErr(ctx, ErrAnnotation, "unsupported optimizer hint %s", a.Args[0])
+		return hints
+	}
+
+	value, err := strconv.ParseBool(strings.ToLower(a.Args[1]))
+	if err != nil {
+		s.errs.RuleErr(ctx, ErrAnnotation, "optimizer hint %s must be true or false, got %s", name, a.Args[1])
+		return hints
+	}
+
+	for _, hint := range hints {
+		if hint.Name == name {
+			s.errs.RuleErr(ctx, ErrAnnotation, "optimizer hint %s declared more than once", name)
+			return hints
+		}
+	}
+
+	return append(hints, &engine.OptimizerHint{Name: name, Value: strconv.FormatBool(value)})
+}
+
+// addErrorType validates an @error_type annotation of an action and adds its
+// error type to errTypes. The arguments are an integer code and a message,
+// which may be quoted, e.g. @error_type(404, 'not found').
+func (s *schemaVisitor) addErrorType(ctx antlr.ParserRuleContext, errTypes []*engine.ErrorType, a *Annotation) []*engine.ErrorType {
+	if len(a.Args) != 2 {
+		s.errs.RuleErr(ctx, ErrAnnotation, "@error_type expects a code and a message, got %d arguments", len(a.Args))
+		return errTypes
+	}
+
+	code, err := strconv.Atoi(a.Args[0])
+	if err != nil {
+		s.errs.RuleErr(ctx, ErrAnnotation, "error type code must be an integer, got %s", a.Args[0])
+		return errTypes
+	}
+
+	for _, et := range errTypes {
+		if et.Code == code {
+			s.errs.RuleErr(ctx, ErrAnnotation, "error type %d declared more than once", code)
+			return errTypes
+		}
+	}
+
+	msg := a.Args[1]
+	if len(msg) >= 2 && (msg[0] == '\'' || msg[0] == '"') && msg[len(msg)-1] == msg[0] {
+		msg = msg[1 : len(msg)-1]
+	}
+
+	return append(errTypes, &engine.ErrorType{Code: code, Message: msg})
+}
+
+// setDistinct validates a @distinct or @distinct_on annotation of an action
+// and sets it on the action. @distinct takes no arguments, and @distinct_on
+// takes the names of the columns the action returns to de-duplicate on.
+func (s *schemaVisitor) setDistinct(ctx antlr.ParserRuleContext, cas *CreateActionStatement, a *Annotation) {
+	if cas.Returns == nil {
+		s.errs.RuleErr(ctx, ErrAnnotation, "@%s can only be used on actions that return rows", a.Name)
+		return
+	}
+
+	if a.Name == "distinct" {
+		if len(a.Args) != 0 {
+			s.errs.RuleErr(ctx, ErrAnnotation, "@distinct takes no arguments")
+			return
+		}
+		cas.Distinct = true
+		return
+	}
+
+	if len(a.Args) == 0 {
+		s.errs.RuleErr(ctx, ErrAnnotation, "@distinct_on expects at least one column")
+		return
+	}
+
+	var columns []string
+	for _, arg := range a.Args {
+		col := strings.ToLower(arg)
+		found := false
+		for _, field := range cas.Returns.Fields {
+			if field.Name == col {
+				found = true
+				break
+			}
+		}
+		if !found {
+			s.errs.RuleErr(ctx, ErrAnnotation, "@distinct_on column %s is not returned by the action", arg)
+			return
+		}
+		columns = append(columns, col)
+	}
+
+	cas.Distinct = true
+	cas.DistinctOn = columns
+}
+
+// tableAnnotations returns the annotations of a table, each on its own line.
+func tableAnnotations(cts *CreateTableStatement) string {
+	var str strings.Builder
+	if cts.SoftDelete {
+		str.WriteString("-- @soft_delete\n")
+	}
+	if cts.History {
+		str.WriteString("-- @history\n")
+	}
+	for _, hint := range cts.OptimizerHints {
+		fmt.Fprintf(&str, "-- @optimizer_hint(%s, %s)\n", hint.Name, hint.Value)
+	}
+
+	return str.String()
+}
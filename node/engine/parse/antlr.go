@@ -24,6 +24,9 @@ type schemaVisitor struct {
 	errs *errorListener
 	// stream is the input stream
 	stream *antlr.InputStream
+	// tokens is the token stream, which includes hidden channel tokens
+	// such as comments, which carry annotations.
+	tokens *antlr.CommonTokenStream
 }
 
 // getTextFromStream gets the text from the input stream for a given range.
@@ -40,10 +43,11 @@ func (s *schemaVisitor) getTextFromStream(start, stop int) (str string) {
 }
 
 // newSchemaVisitor creates a new schema visitor.
-func newSchemaVisitor(stream *antlr.InputStream, errLis *errorListener) *schemaVisitor {
+func newSchemaVisitor(stream *antlr.InputStream, tokens *antlr.CommonTokenStream, errLis *errorListener) *schemaVisitor {
 	return &schemaVisitor{
 		errs:   errLis,
 		stream: stream,
+		tokens: tokens,
 	}
 }
 
@@ -64,11 +68,16 @@ func (s *schemaVisitor) VisitStatement(ctx *gen.StatementContext) any {
 	case ctx.Sql_statement() != nil:
 		s2 = ctx.Sql_statement().Accept(s).(*SQLStatement)
 	case ctx.Create_table_statement() != nil:
-		s2 = ctx.Create_table_statement().Accept(s).(TopLevelStatement)
+		s3 := ctx.Create_table_statement().Accept(s).(*CreateTableStatement)
+		r := s.getTextFromStream(ctx.Create_table_statement().GetStart().GetStart(), ctx.GetStop().GetStop()) + ";"
+		s3.Raw = tableAnnotations(s3) + r
+		s2 = s3
 	case ctx.Alter_table_statement() != nil:
 		s2 = ctx.Alter_table_statement().Accept(s).(TopLevelStatement)
 	case ctx.Drop_table_statement() != nil:
 		s2 = ctx.Drop_table_statement().Accept(s).(TopLevelStatement)
+	case ctx.Truncate_table_statement() != nil:
+		s2 = ctx.Truncate_table_statement().Accept(s).(TopLevelStatement)
 	case ctx.Create_index_statement() != nil:
 		s2 = ctx.Create_index_statement().Accept(s).(TopLevelStatement)
 	case ctx.Drop_index_statement() != nil:
@@ -86,7 +95,10 @@ func (s *schemaVisitor) VisitStatement(ctx *gen.StatementContext) any {
 	case ctx.Create_action_statement() != nil:
 		s3 := ctx.Create_action_statement().Accept(s).(*CreateActionStatement)
 		r := s.getTextFromStream(ctx.GetStart().GetStart(), ctx.GetStop().GetStop()) + ";"
-		s3.Raw = r
+		// the comments preceding the action are kept as they were written, so
+		// that its source can be introspected, and its annotations are parsed
+		// again when the stored action is loaded
+		s3.Raw = s.leadingComments(ctx.Create_action_statement()) + r
 		s2 = s3
 	case ctx.Drop_action_statement() != nil:
 		s2 = ctx.Drop_action_statement().Accept(s).(TopLevelStatement)
@@ -100,6 +112,8 @@ func (s *schemaVisitor) VisitStatement(ctx *gen.StatementContext) any {
 		s2 = ctx.Unuse_extension_statement().Accept(s).(TopLevelStatement)
 	case ctx.Set_current_namespace_statement() != nil:
 		s2 = ctx.Set_current_namespace_statement().Accept(s).(TopLevelStatement)
+	case ctx.Alter_namespace_statement() != nil:
+		s2 = ctx.Alter_namespace_statement().Accept(s).(TopLevelStatement)
 	default:
 		panic(fmt.Sprintf("unknown parser entry: %s", ctx.GetText()))
 	}
@@ -131,6 +145,34 @@ func (s *schemaVisitor) VisitCreate_action_statement(ctx *gen.Create_action_stat
 		return cas
 	}
 
+	var distinctAnnotation *Annotation
+	for _, a := range s.getAnnotations(ctx) {
+		switch a.Name {
+		case "optimizer_hint":
+			cas.OptimizerHints = s.addOptimizerHint(ctx, cas.OptimizerHints, a)
+		case "distinct", "distinct_on":
+			if distinctAnnotation != nil {
+				s.errs.RuleErr(ctx, ErrAnnotation, "@%s cannot be used with @%s", a.Name, distinctAnnotation.Name)
+				continue
+			}
+			distinctAnnotation = a
+		case "idempotent":
+			if len(a.Args) != 0 {
+				s.errs.RuleErr(ctx, ErrAnnotation, "@idempotent takes no arguments")
+				continue
+			}
+			cas.Idempotent = true
+		case "test":
+			if len(a.Args) != 0 {
+				s.errs.RuleErr(ctx, ErrAnnotation, "@test takes no arguments")
+				continue
+			}
+			cas.Test = true
+		case "error_type":
+			cas.ErrorTypes = s.addErrorType(ctx, cas.ErrorTypes, a)
+		}
+	}
+
 	allIdents := ctx.AllIdentifier()
 	foundMods := make(map[string]struct{})
 	for _, id := range allIdents[1:] {
@@ -143,6 +185,11 @@ func (s *schemaVisitor) VisitCreate_action_statement(ctx *gen.Create_action_stat
 		cas.Modifiers = append(cas.Modifiers, modText)
 	}
 
+	// the results of idempotent calls are stored, which view actions cannot do
+	if _, ok := foundMods["view"]; ok && cas.Idempotent {
+		s.errs.RuleErr(ctx, ErrAnnotation, "@idempotent cannot be used on view actions")
+	}
+
 	paramSet := make(map[string]struct{})
 	for i, t := range ctx.AllType_() {
 		name := s.cleanStringIdent(ctx, ctx.VARIABLE(i).GetText())
@@ -169,6 +216,10 @@ func (s *schemaVisitor) VisitCreate_action_statement(ctx *gen.Create_action_stat
 		cas.Returns = ctx.Action_return().Accept(s).(*ActionReturn)
 	}
 
+	if distinctAnnotation != nil {
+		s.setDistinct(ctx, cas, distinctAnnotation)
+	}
+
 	for i, stmt := range ctx.AllAction_statement() {
 		cas.Statements[i] = stmt.Accept(s).(ActionStmt)
 	}
@@ -192,6 +243,23 @@ func (s *schemaVisitor) VisitCreate_namespace_statement(ctx *gen.Create_namespac
 		Namespace:   s.getIdent(ctx.Identifier()),
 	}
 
+	for _, a := range s.getAnnotations(ctx) {
+		switch a.Name {
+		case "event_sourced":
+			if len(a.Args) != 0 {
+				s.errs.RuleErr(ctx, ErrAnnotation, "@event_sourced takes no arguments")
+				continue
+			}
+			cns.EventSourced = true
+		case "test_namespace":
+			if len(a.Args) != 0 {
+				s.errs.RuleErr(ctx, ErrAnnotation, "@test_namespace takes no arguments")
+				continue
+			}
+			cns.Test = true
+		}
+	}
+
 	cns.Set(ctx)
 	return cns
 }
@@ -215,6 +283,41 @@ func (s *schemaVisitor) VisitSet_current_namespace_statement(ctx *gen.Set_curren
 	return sns
 }
 
+func (s *schemaVisitor) VisitAlter_namespace_statement(ctx *gen.Alter_namespace_statementContext) any {
+	ans := &AlterNamespaceStatement{
+		Namespace: s.getIdent(ctx.Identifier()),
+	}
+
+	// READ, ONLY, WRITE and CONFIG are not keywords, so they are matched as
+	// identifiers
+	setting := strings.ToUpper(ctx.IDENTIFIER(0).GetText())
+	if ctx.EQUALS() != nil {
+		if setting != "CONFIG" {
+			s.errs.RuleErr(ctx, ErrSyntax, "expected CONFIG, got %s", setting)
+		} else if ctx.STRING_(1) != nil {
+			ans.Config = &NamespaceConfigValue{
+				Key:   parseStringLiteral(ctx.STRING_(0).GetText()),
+				Value: parseStringLiteral(ctx.STRING_(1).GetText()),
+			}
+		}
+
+		ans.Set(ctx)
+		return ans
+	}
+
+	mode := setting + " " + strings.ToUpper(ctx.IDENTIFIER(1).GetText())
+	switch mode {
+	case "READ ONLY":
+		ans.ReadOnly = true
+	case "READ WRITE":
+	default:
+		s.errs.RuleErr(ctx, ErrSyntax, "expected READ ONLY or READ WRITE, got %s", mode)
+	}
+
+	ans.Set(ctx)
+	return ans
+}
+
 // unknownExpression creates a new literal with an unknown type and null value.
 // It should be used when we have to return early from a visitor method that
 // returns an expression.
@@ -704,6 +807,27 @@ func (s *schemaVisitor) VisitCreate_table_statement(ctx *gen.Create_table_statem
 		s.errs.RuleErr(ctx, ErrNoPrimaryKey, "no primary key declared")
 	}
 
+	for _, a := range s.getAnnotations(ctx) {
+		var set *bool
+		switch a.Name {
+		case "soft_delete":
+			set = &stmt.SoftDelete
+		case "history":
+			set = &stmt.History
+		case "optimizer_hint":
+			stmt.OptimizerHints = s.addOptimizerHint(ctx, stmt.OptimizerHints, a)
+			continue
+		default:
+			continue
+		}
+
+		if len(a.Args) != 0 {
+			s.errs.RuleErr(ctx, ErrAnnotation, "@%s takes no arguments", a.Name)
+			continue
+		}
+		*set = true
+	}
+
 	stmt.Set(ctx)
 	return stmt
 }
@@ -719,6 +843,24 @@ func (s *schemaVisitor) VisitTable_column_def(ctx *gen.Table_column_defContext)
 		column.Constraints[i] = c.Accept(s).(InlineConstraint)
 	}
 
+	for _, a := range s.getAnnotations(ctx) {
+		if a.Name != "sensitive" {
+			continue
+		}
+
+		policy, err := sensitivityPolicy(a)
+		if err != nil {
+			s.errs.RuleErr(ctx, ErrAnnotation, "%s", err.Error())
+			continue
+		}
+		// hashing and partial masking produce text, so they can only be used on text columns
+		if policy.Mask != engine.MaskRedact && !column.Type.EqualsStrict(types.TextType) {
+			s.errs.RuleErr(ctx, ErrAnnotation, "mask function %s can only be used on text columns", policy.Mask)
+			continue
+		}
+		column.SensitivityPolicy = policy
+	}
+
 	column.Set(ctx)
 	return column
 }
@@ -854,6 +996,21 @@ func (s *schemaVisitor) VisitDrop_table_statement(ctx *gen.Drop_table_statementC
 	return stmt
 }
 
+func (s *schemaVisitor) VisitTruncate_table_statement(ctx *gen.Truncate_table_statementContext) any {
+	stmt := &TruncateTableStatement{
+		Table:   s.getIdent(ctx.Identifier()),
+		Cascade: ctx.CASCADE() != nil,
+	}
+
+	// TRUNCATE is not a keyword, so it is matched as an identifier
+	if !strings.EqualFold(ctx.IDENTIFIER().GetText(), "truncate") {
+		s.errs.RuleErr(ctx, ErrSyntax, "unexpected %s, expected TRUNCATE", ctx.IDENTIFIER().GetText())
+	}
+
+	stmt.Set(ctx)
+	return stmt
+}
+
 func (s *schemaVisitor) VisitOpt_drop_behavior(ctx *gen.Opt_drop_behaviorContext) any {
 	switch {
 	case ctx.CASCADE() != nil:
@@ -992,6 +1149,20 @@ func (s *schemaVisitor) VisitCreate_index_statement(ctx *gen.Create_index_statem
 		a.Name = s.getIdent(ctx.GetName())
 	}
 
+	// CONCURRENTLY is not a keyword, so the identifier after INDEX is either
+	// CONCURRENTLY or, if no name follows it, the name of the index. Like in
+	// Postgres, an index cannot be named concurrently.
+	if ident := ctx.IDENTIFIER(); ident != nil {
+		switch {
+		case strings.EqualFold(ident.GetText(), "concurrently"):
+			a.Concurrently = true
+		case ctx.GetName() == nil && !a.IfNotExists:
+			a.Name = s.cleanStringIdent(ctx, ident.GetText())
+		default:
+			s.errs.RuleErr(ctx, ErrSyntax, "unexpected %s, expected CONCURRENTLY", ident.GetText())
+		}
+	}
+
 	if ctx.UNIQUE() != nil {
 		a.Type = IndexTypeUnique
 	}
@@ -1293,6 +1464,14 @@ func (s *schemaVisitor) VisitSubquery_relation(ctx *gen.Subquery_relationContext
 		Subquery: ctx.Select_statement().Accept(s).(*SelectStatement),
 	}
 
+	// LATERAL is not a keyword, so it is matched as an identifier
+	if ctx.IDENTIFIER() != nil {
+		if !strings.EqualFold(ctx.IDENTIFIER().GetText(), "lateral") {
+			s.errs.RuleErr(ctx, ErrSyntax, "unexpected %s, expected LATERAL", ctx.IDENTIFIER().GetText())
+		}
+		t.Lateral = true
+	}
+
 	// alias is technially required here, but we allow it in the grammar
 	// to throw a better error message here.
 	if ctx.Identifier() != nil {
@@ -1303,6 +1482,23 @@ func (s *schemaVisitor) VisitSubquery_relation(ctx *gen.Subquery_relationContext
 	return t
 }
 
+func (s *schemaVisitor) VisitFunction_relation(ctx *gen.Function_relationContext) any {
+	t := &RelationFunctionCall{
+		Name: s.getIdent(ctx.GetFunction_name()),
+	}
+
+	if ctx.GetArgs() != nil {
+		t.Args = ctx.GetArgs().Accept(s).([]Expression)
+	}
+
+	if ctx.GetAlias() != nil {
+		t.Alias = s.getIdent(ctx.GetAlias())
+	}
+
+	t.Set(ctx)
+	return t
+}
+
 func (s *schemaVisitor) VisitJoin(ctx *gen.JoinContext) any {
 	j := &Join{
 		Relation: ctx.Relation().Accept(s).(Table),
@@ -2364,6 +2560,65 @@ func (s *schemaVisitor) VisitStmt_return_next(ctx *gen.Stmt_return_nextContext)
 	return stmt
 }
 
+func (s *schemaVisitor) VisitStmt_declare_cursor(ctx *gen.Stmt_declare_cursorContext) any {
+	s.checkCursorKeyword(ctx, ctx.IDENTIFIER(0), "DECLARE")
+	s.checkCursorKeyword(ctx, ctx.IDENTIFIER(1), "CURSOR")
+
+	stmt := &ActionStmtDeclareCursor{
+		Name:  s.getIdent(ctx.GetCursor()),
+		Query: ctx.Sql_statement().Accept(s).(*SQLStatement),
+	}
+
+	if _, ok := stmt.Query.SQL.(*SelectStatement); !ok {
+		s.errs.RuleErr(ctx.Sql_statement(), ErrSyntax, "cursor must be declared for a SELECT statement")
+	}
+
+	stmt.Set(ctx)
+	return stmt
+}
+
+func (s *schemaVisitor) VisitStmt_fetch_cursor(ctx *gen.Stmt_fetch_cursorContext) any {
+	s.checkCursorKeyword(ctx, ctx.IDENTIFIER(), "FETCH")
+
+	stmt := &ActionStmtFetchCursor{
+		Name: s.getIdent(ctx.GetCursor()),
+	}
+
+	for i, v := range ctx.AllVariable_or_underscore() {
+		// check for nil since nil pointer will fail *string type assertion
+		if v.Accept(s) == nil {
+			stmt.Into = append(stmt.Into, nil)
+			continue
+		}
+
+		stmt.Into = append(stmt.Into, varFromString(*v.Accept(s).(*string)))
+		stmt.Into[i].Set(v)
+	}
+
+	stmt.Set(ctx)
+	return stmt
+}
+
+func (s *schemaVisitor) VisitStmt_close_cursor(ctx *gen.Stmt_close_cursorContext) any {
+	s.checkCursorKeyword(ctx, ctx.IDENTIFIER(), "CLOSE")
+
+	stmt := &ActionStmtCloseCursor{
+		Name: s.getIdent(ctx.GetCursor()),
+	}
+
+	stmt.Set(ctx)
+	return stmt
+}
+
+// checkCursorKeyword checks that an identifier of a cursor statement is the
+// expected keyword. The keywords of cursor statements are not reserved, so the
+// grammar matches them as identifiers.
+func (s *schemaVisitor) checkCursorKeyword(ctx antlr.ParserRuleContext, ident antlr.TerminalNode, keyword string) {
+	if !strings.EqualFold(ident.GetText(), keyword) {
+		s.errs.RuleErr(ctx, ErrSyntax, "unexpected %s, expected %s", ident.GetText(), keyword)
+	}
+}
+
 func (s *schemaVisitor) VisitNormal_call_action(ctx *gen.Normal_call_actionContext) any {
 	call := &ExpressionFunctionCall{}
 
@@ -603,8 +603,32 @@ type CreateActionStatement struct {
 	Returns *ActionReturn
 	// Statements are the statements in the action.
 	Statements []ActionStmt
-	// Raw is the raw CREATE ACTION statement.
+	// Raw is the raw CREATE ACTION statement, preceded by the comments that
+	// directly precede it in the source, such as its annotations. Comments
+	// and formatting are kept as they were written.
 	Raw string
+	// OptimizerHints are the hints the action was annotated with using
+	// @optimizer_hint.
+	OptimizerHints []*engine.OptimizerHint
+	// Distinct is true if the action was annotated with @distinct or
+	// @distinct_on, and the rows it returns with RETURN SELECT are
+	// de-duplicated.
+	Distinct bool
+	// DistinctOn are the returned columns that rows are de-duplicated on, if
+	// the action was annotated with @distinct_on. If it is empty and Distinct
+	// is true, rows are de-duplicated on all columns.
+	DistinctOn []string
+	// Idempotent is true if the action was annotated with @idempotent, and
+	// repeated calls with the same caller and arguments return the result of
+	// the first call instead of executing it again.
+	Idempotent bool
+	// Test is true if the action was annotated with @test. Test actions can
+	// only be created in test namespaces, and are run by the schema test
+	// command.
+	Test bool
+	// ErrorTypes are the application errors the action declared with
+	// @error_type, which are the only errors it can raise with raise_error.
+	ErrorTypes []*engine.ErrorType
 }
 
 func (c *CreateActionStatement) topLevelStatement() {}
@@ -646,6 +670,16 @@ type CreateTableStatement struct {
 	Columns     []*Column
 	// Constraints contains the non-inline constraints
 	Constraints []*OutOfLineConstraint
+	// SoftDelete is true if the table was annotated with @soft_delete.
+	SoftDelete bool
+	// History is true if the table was annotated with @history.
+	History bool
+	// OptimizerHints are the hints the table was annotated with using
+	// @optimizer_hint.
+	OptimizerHints []*engine.OptimizerHint
+	// Raw is the raw CREATE TABLE statement, preceded by the annotations of
+	// the table.
+	Raw string
 }
 
 func (c *CreateTableStatement) topLevelStatement() {}
@@ -661,6 +695,9 @@ type Column struct {
 	Name        string
 	Type        *types.DataType
 	Constraints []InlineConstraint
+	// SensitivityPolicy is set by a @sensitive annotation. It is nil if the
+	// column is not sensitive.
+	SensitivityPolicy *engine.SensitivityPolicy
 }
 
 func (c *Column) Accept(v Visitor) any {
@@ -896,6 +933,22 @@ func (s *DropTableStatement) Accept(v Visitor) any {
 	return v.VisitDropTableStatement(s)
 }
 
+// TruncateTableStatement is a TRUNCATE TABLE statement.
+type TruncateTableStatement struct {
+	Position
+	Namespacing
+	Table string
+	// Cascade is true if the tables that reference the table with foreign
+	// keys are also truncated.
+	Cascade bool
+}
+
+func (s *TruncateTableStatement) topLevelStatement() {}
+
+func (s *TruncateTableStatement) Accept(v Visitor) any {
+	return v.VisitTruncateTableStatement(s)
+}
+
 type AlterTableAction interface {
 	Node
 
@@ -1069,6 +1122,9 @@ type CreateIndexStatement struct {
 	On          string
 	Columns     []string
 	Type        IndexType
+	// Concurrently is true if the index is built without locking the
+	// table against writes.
+	Concurrently bool
 }
 
 func (s *CreateIndexStatement) topLevelStatement() {}
@@ -1202,6 +1258,11 @@ type CreateNamespaceStatement struct {
 	IfNotExists bool
 	// Namespace is the namespace that is being created.
 	Namespace string
+	// EventSourced is true if the namespace was annotated with @event_sourced.
+	EventSourced bool
+	// Test is true if the namespace was annotated with @test_namespace, and
+	// can have @test actions.
+	Test bool
 }
 
 func (c *CreateNamespaceStatement) topLevelStatement() {}
@@ -1235,6 +1296,32 @@ func (s *SetCurrentNamespaceStatement) Accept(v Visitor) any {
 	return v.VisitSetCurrentNamespaceStatement(s)
 }
 
+// AlterNamespaceStatement is an ALTER NAMESPACE ... SET READ ONLY,
+// SET READ WRITE, or SET CONFIG 'key' = 'value' statement.
+type AlterNamespaceStatement struct {
+	Position
+	// Namespace is the namespace that is being altered.
+	Namespace string
+	// ReadOnly is true if the namespace is set to read-only.
+	ReadOnly bool
+	// Config is the configuration value that is set. If it is set, the
+	// statement does not change whether the namespace is read-only.
+	Config *NamespaceConfigValue
+}
+
+// NamespaceConfigValue is a configuration value of a namespace, which its
+// actions read with config_get.
+type NamespaceConfigValue struct {
+	Key   string
+	Value string
+}
+
+func (a *AlterNamespaceStatement) topLevelStatement() {}
+
+func (a *AlterNamespaceStatement) Accept(v Visitor) any {
+	return v.VisitAlterNamespaceStatement(a)
+}
+
 // SelectStatement is a SELECT statement.
 type SelectStatement struct {
 	Position
@@ -1373,6 +1460,9 @@ type RelationSubquery struct {
 	// Alias cannot be empty, as our syntax
 	// forces it for subqueries.
 	Alias string
+	// Lateral is true if the subquery is LATERAL, and can thus reference
+	// the columns of the relations that precede it in the FROM clause.
+	Lateral bool
 }
 
 func (r *RelationSubquery) Accept(v Visitor) any {
@@ -1381,6 +1471,22 @@ func (r *RelationSubquery) Accept(v Visitor) any {
 
 func (RelationSubquery) table() {}
 
+// RelationFunctionCall is a call to a table function, such as generate_series.
+type RelationFunctionCall struct {
+	Position
+	// Name is the name of the function.
+	Name string
+	// Args are the arguments to the function.
+	Args  []Expression
+	Alias string // can be empty
+}
+
+func (r *RelationFunctionCall) Accept(v Visitor) any {
+	return v.VisitRelationFunctionCall(r)
+}
+
+func (RelationFunctionCall) table() {}
+
 // Join is a join in a SELECT statement.
 type Join struct {
 	Position
@@ -1669,6 +1775,46 @@ func (p *ActionStmtReturnNext) Accept(v Visitor) any {
 	return v.VisitActionStmtReturnNext(p)
 }
 
+// ActionStmtDeclareCursor declares a cursor over the rows of a query:
+// DECLARE name CURSOR FOR SELECT ...
+type ActionStmtDeclareCursor struct {
+	baseActionStmt
+	// Name is the name of the cursor.
+	Name string
+	// Query is the query the cursor iterates over.
+	Query *SQLStatement
+}
+
+func (p *ActionStmtDeclareCursor) Accept(v Visitor) any {
+	return v.VisitActionStmtDeclareCursor(p)
+}
+
+// ActionStmtFetchCursor fetches the next row of a cursor:
+// FETCH NEXT FROM name INTO $a, $b
+type ActionStmtFetchCursor struct {
+	baseActionStmt
+	// Name is the name of the cursor.
+	Name string
+	// Into are the variables the columns of the row are assigned to. If nil,
+	// then the column can be ignored.
+	Into []*ExpressionVariable
+}
+
+func (p *ActionStmtFetchCursor) Accept(v Visitor) any {
+	return v.VisitActionStmtFetchCursor(p)
+}
+
+// ActionStmtCloseCursor closes a cursor: CLOSE name
+type ActionStmtCloseCursor struct {
+	baseActionStmt
+	// Name is the name of the cursor.
+	Name string
+}
+
+func (p *ActionStmtCloseCursor) Accept(v Visitor) any {
+	return v.VisitActionStmtCloseCursor(p)
+}
+
 /*
 	There are three types of visitors, all which compose on each other:
 	- Visitor: top-level visitor capable of visiting actions, DDL, and SQL.
@@ -1689,6 +1835,7 @@ type DDLVisitor interface {
 	VisitCreateTableStatement(*CreateTableStatement) any
 	VisitAlterTableStatement(*AlterTableStatement) any
 	VisitDropTableStatement(*DropTableStatement) any
+	VisitTruncateTableStatement(*TruncateTableStatement) any
 	VisitCreateIndexStatement(*CreateIndexStatement) any
 	VisitDropIndexStatement(*DropIndexStatement) any
 	VisitGrantOrRevokeStatement(*GrantOrRevokeStatement) any
@@ -1709,6 +1856,7 @@ type DDLVisitor interface {
 	VisitCreateNamespaceStatement(*CreateNamespaceStatement) any
 	VisitDropNamespaceStatement(*DropNamespaceStatement) any
 	VisitSetCurrentNamespaceStatement(*SetCurrentNamespaceStatement) any
+	VisitAlterNamespaceStatement(*AlterNamespaceStatement) any
 	VisitCreateActionStatement(*CreateActionStatement) any
 	VisitDropActionStatement(*DropActionStatement) any
 	// Constraints
@@ -1740,6 +1888,9 @@ type ActionVisitor interface {
 	VisitActionStmtLoopControl(*ActionStmtLoopControl) any
 	VisitActionStmtReturn(*ActionStmtReturn) any
 	VisitActionStmtReturnNext(*ActionStmtReturnNext) any
+	VisitActionStmtDeclareCursor(*ActionStmtDeclareCursor) any
+	VisitActionStmtFetchCursor(*ActionStmtFetchCursor) any
+	VisitActionStmtCloseCursor(*ActionStmtCloseCursor) any
 }
 
 // SQLVisitor is a visitor that only has methods for SQL nodes.
@@ -1774,6 +1925,7 @@ type SQLVisitor interface {
 	VisitResultColumnWildcard(*ResultColumnWildcard) any
 	VisitRelationTable(*RelationTable) any
 	VisitRelationSubquery(*RelationSubquery) any
+	VisitRelationFunctionCall(*RelationFunctionCall) any
 	VisitJoin(*Join) any
 	VisitUpdateStatement(*UpdateStatement) any
 	VisitUpdateSetClause(*UpdateSetClause) any
@@ -1837,6 +1989,18 @@ func (s *UnimplementedActionVisitor) VisitActionStmtReturnNext(p0 *ActionStmtRet
 	panic(fmt.Sprintf("api misuse: cannot visit %T in constrained visitor", s))
 }
 
+func (s *UnimplementedActionVisitor) VisitActionStmtDeclareCursor(p0 *ActionStmtDeclareCursor) any {
+	panic(fmt.Sprintf("api misuse: cannot visit %T in constrained visitor", s))
+}
+
+func (s *UnimplementedActionVisitor) VisitActionStmtFetchCursor(p0 *ActionStmtFetchCursor) any {
+	panic(fmt.Sprintf("api misuse: cannot visit %T in constrained visitor", s))
+}
+
+func (s *UnimplementedActionVisitor) VisitActionStmtCloseCursor(p0 *ActionStmtCloseCursor) any {
+	panic(fmt.Sprintf("api misuse: cannot visit %T in constrained visitor", s))
+}
+
 type UnimplementedDDLVisitor struct{}
 
 func (u *UnimplementedDDLVisitor) VisitCreateTableStatement(p0 *CreateTableStatement) any {
@@ -1851,6 +2015,10 @@ func (u *UnimplementedDDLVisitor) VisitDropTableStatement(p0 *DropTableStatement
 	panic(fmt.Sprintf("api misuse: cannot visit %T in constrained visitor", u))
 }
 
+func (u *UnimplementedDDLVisitor) VisitTruncateTableStatement(p0 *TruncateTableStatement) any {
+	panic(fmt.Sprintf("api misuse: cannot visit %T in constrained visitor", u))
+}
+
 func (u *UnimplementedDDLVisitor) VisitCreateIndexStatement(p0 *CreateIndexStatement) any {
 	panic(fmt.Sprintf("api misuse: cannot visit %T in constrained visitor", u))
 }
@@ -0,0 +1,287 @@
+package parse
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/kwilteam/kwil-db/node/engine"
+)
+
+// SchemaChangeType is the type of a change to a schema.
+type SchemaChangeType string
+
+const (
+	SchemaChangeCreateTable SchemaChangeType = "create table"
+	SchemaChangeDropTable   SchemaChangeType = "drop table"
+	SchemaChangeAddColumn   SchemaChangeType = "add column"
+	SchemaChangeDropColumn  SchemaChangeType = "drop column"
+	SchemaChangeSetNotNull  SchemaChangeType = "set not null"
+	SchemaChangeDropNotNull SchemaChangeType = "drop not null"
+	SchemaChangeCreateIndex SchemaChangeType = "create index"
+	SchemaChangeDropIndex   SchemaChangeType = "drop index"
+)
+
+// SchemaChange is a change that migrates a namespace to a new schema.
+type SchemaChange struct {
+	Type SchemaChangeType
+	// Table is the table that is changed.
+	Table string
+	// Name is the name of the column or index that is changed. It is empty
+	// for changes to tables.
+	Name string
+	// Statement is the statement that makes the change. It does not specify
+	// the namespace.
+	Statement string
+}
+
+// Destructive returns true if the change drops a table or column, deleting
+// its data.
+func (c *SchemaChange) Destructive() bool {
+	return c.Type == SchemaChangeDropTable || c.Type == SchemaChangeDropColumn
+}
+
+func (c *SchemaChange) String() string {
+	if c.Name == "" {
+		return fmt.Sprintf("%s %s", c.Type, c.Table)
+	}
+	return fmt.Sprintf("%s %s.%s", c.Type, c.Table, c.Name)
+}
+
+// DiffSchema returns the changes that migrate the tables of a namespace to the
+// tables and indexes created by the statements of a new schema. Other
+// statements are ignored. Changes that cannot be made without recreating a
+// table, such as changing the type of a column or the primary key, return an
+// error. Column constraints other than NOT NULL are not compared.
+//
+// The indexes of the current tables should not include the indexes created by
+// constraints, since they are not created by CREATE INDEX statements.
+//
+// The changes are ordered so that they can be applied in order: indexes and
+// tables are dropped first, then tables are created or altered, and then
+// indexes are created.
+func DiffSchema(current []*engine.Table, schema []TopLevelStatement) ([]*SchemaChange, error) {
+	var tables []*CreateTableStatement
+	indexes := make(map[string][]*CreateIndexStatement)
+	for _, stmt := range schema {
+		switch stmt := stmt.(type) {
+		case *CreateTableStatement:
+			tables = append(tables, stmt)
+		case *CreateIndexStatement:
+			indexes[stmt.On] = append(indexes[stmt.On], stmt)
+		}
+	}
+	slices.SortFunc(tables, func(a, b *CreateTableStatement) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	currentTables := make(map[string]*engine.Table, len(current))
+	for _, tbl := range current {
+		currentTables[tbl.Name] = tbl
+	}
+	newTables := make(map[string]*CreateTableStatement, len(tables))
+	for _, tbl := range tables {
+		newTables[tbl.Name] = tbl
+	}
+
+	var drops, alters, creates []*SchemaChange
+
+	sortedCurrent := slices.Clone(current)
+	slices.SortFunc(sortedCurrent, func(a, b *engine.Table) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	for _, tbl := range sortedCurrent {
+		if _, ok := newTables[tbl.Name]; ok {
+			continue
+		}
+		drops = append(drops, &SchemaChange{
+			Type:      SchemaChangeDropTable,
+			Table:     tbl.Name,
+			Statement: fmt.Sprintf("DROP TABLE %s;", tbl.Name),
+		})
+	}
+
+	for _, newTbl := range tables {
+		tbl, ok := currentTables[newTbl.Name]
+		if !ok {
+			if newTbl.Raw == "" {
+				return nil, fmt.Errorf("table %s: statement text is not set", newTbl.Name)
+			}
+			alters = append(alters, &SchemaChange{
+				Type:      SchemaChangeCreateTable,
+				Table:     newTbl.Name,
+				Statement: newTbl.Raw,
+			})
+			for _, idx := range indexes[newTbl.Name] {
+				creates = append(creates, createIndexChange(idx))
+			}
+			continue
+		}
+
+		changes, err := diffColumns(tbl, newTbl)
+		if err != nil {
+			return nil, err
+		}
+		alters = append(alters, changes...)
+
+		dropped, created := diffIndexes(tbl, indexes[newTbl.Name])
+		drops = append(dropped, drops...)
+		creates = append(creates, created...)
+	}
+
+	return append(append(drops, alters...), creates...), nil
+}
+
+// diffColumns returns the changes to the columns of a table.
+func diffColumns(tbl *engine.Table, newTbl *CreateTableStatement) ([]*SchemaChange, error) {
+	var changes []*SchemaChange
+	for _, col := range newTbl.Columns {
+		current, ok := tbl.Column(col.Name)
+		if !ok {
+			for _, c := range col.Constraints {
+				if _, ok := c.(*NotNullConstraint); !ok {
+					return nil, fmt.Errorf("column %s.%s: cannot add a column with constraints other than NOT NULL", tbl.Name, col.Name)
+				}
+			}
+			changes = append(changes, &SchemaChange{
+				Type:      SchemaChangeAddColumn,
+				Table:     tbl.Name,
+				Name:      col.Name,
+				Statement: fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s;", tbl.Name, col.Name, col.Type),
+			})
+			if !columnNullable(newTbl, col) {
+				changes = append(changes, &SchemaChange{
+					Type:      SchemaChangeSetNotNull,
+					Table:     tbl.Name,
+					Name:      col.Name,
+					Statement: fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET NOT NULL;", tbl.Name, col.Name),
+				})
+			}
+			continue
+		}
+
+		if !current.DataType.Equals(col.Type) {
+			return nil, fmt.Errorf("column %s.%s: cannot change type from %s to %s", tbl.Name, col.Name, current.DataType, col.Type)
+		}
+		if current.IsPrimaryKey != columnIsPrimaryKey(newTbl, col) {
+			return nil, fmt.Errorf("column %s.%s: cannot change the primary key", tbl.Name, col.Name)
+		}
+
+		nullable := columnNullable(newTbl, col)
+		switch {
+		case current.Nullable && !nullable:
+			changes = append(changes, &SchemaChange{
+				Type:      SchemaChangeSetNotNull,
+				Table:     tbl.Name,
+				Name:      col.Name,
+				Statement: fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET NOT NULL;", tbl.Name, col.Name),
+			})
+		case !current.Nullable && nullable:
+			changes = append(changes, &SchemaChange{
+				Type:      SchemaChangeDropNotNull,
+				Table:     tbl.Name,
+				Name:      col.Name,
+				Statement: fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s DROP NOT NULL;", tbl.Name, col.Name),
+			})
+		}
+	}
+
+	for _, col := range tbl.Columns {
+		if slices.ContainsFunc(newTbl.Columns, func(c *Column) bool { return c.Name == col.Name }) {
+			continue
+		}
+		if col.IsPrimaryKey {
+			return nil, fmt.Errorf("column %s.%s: cannot drop a primary key column", tbl.Name, col.Name)
+		}
+		changes = append(changes, &SchemaChange{
+			Type:      SchemaChangeDropColumn,
+			Table:     tbl.Name,
+			Name:      col.Name,
+			Statement: fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s;", tbl.Name, col.Name),
+		})
+	}
+
+	return changes, nil
+}
+
+// columnIsPrimaryKey returns true if the column is part of the primary key of
+// the table.
+func columnIsPrimaryKey(tbl *CreateTableStatement, col *Column) bool {
+	for _, c := range col.Constraints {
+		if _, ok := c.(*PrimaryKeyInlineConstraint); ok {
+			return true
+		}
+	}
+	for _, c := range tbl.Constraints {
+		if pk, ok := c.Constraint.(*PrimaryKeyOutOfLineConstraint); ok && slices.Contains(pk.Columns, col.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// columnNullable returns true if the column can be null.
+func columnNullable(tbl *CreateTableStatement, col *Column) bool {
+	for _, c := range col.Constraints {
+		if _, ok := c.(*NotNullConstraint); ok {
+			return false
+		}
+	}
+	return !columnIsPrimaryKey(tbl, col)
+}
+
+// diffIndexes returns the indexes of a table that are dropped and created.
+// An index whose columns or type changed is dropped and created again. Since
+// Postgres names unnamed indexes, an unnamed index in the new schema is the
+// same as an existing index with the same columns and type.
+func diffIndexes(tbl *engine.Table, newIndexes []*CreateIndexStatement) (dropped, created []*SchemaChange) {
+	matched := make(map[*CreateIndexStatement]bool)
+	for _, idx := range tbl.Indexes {
+		if idx.Type == engine.PRIMARY {
+			continue
+		}
+		i := slices.IndexFunc(newIndexes, func(n *CreateIndexStatement) bool {
+			return !matched[n] && (n.Name == idx.Name || n.Name == "") && slices.Equal(n.Columns, idx.Columns) &&
+				(n.Type == IndexTypeUnique) == (idx.Type == engine.UNIQUE_BTREE)
+		})
+		if i >= 0 {
+			matched[newIndexes[i]] = true
+			continue
+		}
+		dropped = append(dropped, &SchemaChange{
+			Type:      SchemaChangeDropIndex,
+			Table:     tbl.Name,
+			Name:      idx.Name,
+			Statement: fmt.Sprintf("DROP INDEX %s;", idx.Name),
+		})
+	}
+
+	for _, idx := range newIndexes {
+		if !matched[idx] {
+			created = append(created, createIndexChange(idx))
+		}
+	}
+
+	return dropped, created
+}
+
+// createIndexChange returns the change that creates an index.
+func createIndexChange(idx *CreateIndexStatement) *SchemaChange {
+	var str strings.Builder
+	str.WriteString("CREATE ")
+	if idx.Type == IndexTypeUnique {
+		str.WriteString("UNIQUE ")
+	}
+	str.WriteString("INDEX ")
+	if idx.Name != "" {
+		str.WriteString(idx.Name + " ")
+	}
+	fmt.Fprintf(&str, "ON %s(%s);", idx.On, strings.Join(idx.Columns, ", "))
+
+	return &SchemaChange{
+		Type:      SchemaChangeCreateIndex,
+		Table:     idx.On,
+		Name:      idx.Name,
+		Statement: str.String(),
+	}
+}
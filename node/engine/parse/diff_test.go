@@ -0,0 +1,106 @@
+package parse_test
+
+import (
+	"testing"
+
+	"github.com/kwilteam/kwil-db/core/types"
+	"github.com/kwilteam/kwil-db/node/engine"
+	"github.com/kwilteam/kwil-db/node/engine/parse"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DiffSchema(t *testing.T) {
+	current := []*engine.Table{
+		{
+			Name: "users",
+			Columns: []*engine.Column{
+				{Name: "id", DataType: types.IntType, IsPrimaryKey: true},
+				{Name: "name", DataType: types.TextType, Nullable: true},
+				{Name: "age", DataType: types.IntType, Nullable: true},
+			},
+			Indexes: []*engine.Index{
+				{Name: "users_pkey", Columns: []string{"id"}, Type: engine.PRIMARY},
+				{Name: "name_idx", Columns: []string{"name"}, Type: engine.BTREE},
+				{Name: "users_age_idx", Columns: []string{"age"}, Type: engine.BTREE},
+			},
+		},
+		{
+			Name: "old_posts",
+			Columns: []*engine.Column{
+				{Name: "id", DataType: types.IntType, IsPrimaryKey: true},
+			},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		schema string
+		want   []string // statements of the changes
+		err    bool
+	}{
+		{
+			name: "no changes",
+			schema: `CREATE TABLE users (id int primary key, name text, age int);
+			CREATE INDEX name_idx ON users(name);
+			CREATE INDEX ON users(age);
+			CREATE TABLE old_posts (id int primary key);`,
+		},
+		{
+			name: "create and drop tables and columns",
+			schema: `CREATE TABLE users (id int primary key, name text not null, email text);
+			CREATE INDEX name_idx ON users(name, email);
+			-- @soft_delete
+			CREATE TABLE posts (id int primary key, author int);
+			CREATE UNIQUE INDEX author_idx ON posts(author);`,
+			want: []string{
+				"DROP INDEX name_idx;",
+				"DROP INDEX users_age_idx;",
+				"DROP TABLE old_posts;",
+				"-- @soft_delete\nCREATE TABLE posts (id int primary key, author int);",
+				"ALTER TABLE users ALTER COLUMN name SET NOT NULL;",
+				"ALTER TABLE users ADD COLUMN email text;",
+				"ALTER TABLE users DROP COLUMN age;",
+				"CREATE UNIQUE INDEX author_idx ON posts(author);",
+				"CREATE INDEX name_idx ON users(name, email);",
+			},
+		},
+		{
+			name: "change column type",
+			schema: `CREATE TABLE users (id int primary key, name int, age int);
+			CREATE TABLE old_posts (id int primary key);`,
+			err: true,
+		},
+		{
+			name: "add column with unique constraint",
+			schema: `CREATE TABLE users (id int primary key, name text, age int, email text unique);
+			CREATE TABLE old_posts (id int primary key);`,
+			err: true,
+		},
+		{
+			name: "drop primary key column",
+			schema: `CREATE TABLE users (uid int primary key, name text, age int);
+			CREATE TABLE old_posts (id int primary key);`,
+			err: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stmts, err := parse.Parse(tt.schema)
+			require.NoError(t, err)
+
+			changes, err := parse.DiffSchema(current, stmts)
+			if tt.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			var got []string
+			for _, change := range changes {
+				got = append(got, change.Statement)
+			}
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -247,4 +247,7 @@ var (
 	ErrRedeclaredPrimaryKey      = errors.New("redeclare primary key")
 	ErrRedeclaredConstraint      = errors.New("redeclared constraint")
 	ErrGrantOrRevoke             = errors.New("grant or revoke error")
+	ErrAnnotation                = errors.New("annotation error")
+	ErrImport                    = errors.New("import error")
+	ErrCircularImport            = errors.New("circular import")
 )
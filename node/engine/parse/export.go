@@ -0,0 +1,115 @@
+package parse
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/kwilteam/kwil-db/node/engine"
+)
+
+// GenerateSchema returns the CREATE TABLE and CREATE INDEX statements, with
+// their annotations, that create the tables. It is the reverse of creating
+// tables from a schema: DiffSchema of the tables and the parsed schema returns
+// no changes. Primary key indexes, and the indexes of unique constraints, are
+// created by the constraints of the tables. Check and foreign key constraints
+// are not included, since the tables do not keep their expressions and
+// referenced tables.
+func GenerateSchema(tables []*engine.Table) (string, error) {
+	var str strings.Builder
+	for i, tbl := range tables {
+		if i > 0 {
+			str.WriteString("\n")
+		}
+		if err := writeCreateTable(&str, tbl); err != nil {
+			return "", fmt.Errorf("table %s: %w", tbl.Name, err)
+		}
+	}
+
+	return str.String(), nil
+}
+
+// writeCreateTable writes the statements that create a table and its indexes.
+func writeCreateTable(str *strings.Builder, tbl *engine.Table) error {
+	if len(tbl.Columns) == 0 {
+		return fmt.Errorf("table has no columns")
+	}
+
+	str.WriteString(tableAnnotations(&CreateTableStatement{
+		SoftDelete:     tbl.SoftDelete,
+		History:        tbl.History,
+		OptimizerHints: tbl.OptimizerHints,
+	}))
+
+	var defs []string
+	for _, col := range tbl.Columns {
+		var def strings.Builder
+		if col.SensitivityPolicy != nil {
+			annotation, err := sensitiveAnnotation(col.SensitivityPolicy)
+			if err != nil {
+				return fmt.Errorf("column %s: %w", col.Name, err)
+			}
+			def.WriteString(annotation + "\n\t")
+		}
+		fmt.Fprintf(&def, "%s %s", col.Name, col.DataType)
+		if !col.Nullable && !col.IsPrimaryKey {
+			def.WriteString(" NOT NULL")
+		}
+		defs = append(defs, def.String())
+	}
+
+	if pk := tbl.PrimaryKeyCols(); len(pk) > 0 {
+		names := make([]string, len(pk))
+		for i, col := range pk {
+			names[i] = col.Name
+		}
+		defs = append(defs, fmt.Sprintf("PRIMARY KEY (%s)", strings.Join(names, ", ")))
+	}
+
+	// constraints are sorted so that the output is deterministic
+	constraintNames := make([]string, 0, len(tbl.Constraints))
+	for name, c := range tbl.Constraints {
+		if c.Type == engine.ConstraintUnique {
+			constraintNames = append(constraintNames, name)
+		}
+	}
+	slices.Sort(constraintNames)
+	for _, name := range constraintNames {
+		defs = append(defs, fmt.Sprintf("CONSTRAINT %s UNIQUE (%s)", name, strings.Join(tbl.Constraints[name].Columns, ", ")))
+	}
+
+	fmt.Fprintf(str, "CREATE TABLE %s (\n\t%s\n);\n", tbl.Name, strings.Join(defs, ",\n\t"))
+
+	for _, idx := range tbl.Indexes {
+		if _, ok := tbl.Constraints[idx.Name]; ok {
+			continue
+		}
+
+		var stmt *CreateIndexStatement
+		switch idx.Type {
+		case engine.PRIMARY:
+			continue
+		case engine.BTREE:
+			stmt = &CreateIndexStatement{Name: idx.Name, On: tbl.Name, Columns: idx.Columns, Type: IndexTypeBTree}
+		case engine.UNIQUE_BTREE:
+			stmt = &CreateIndexStatement{Name: idx.Name, On: tbl.Name, Columns: idx.Columns, Type: IndexTypeUnique}
+		default:
+			return fmt.Errorf("index %s: unknown index type %s", idx.Name, idx.Type)
+		}
+		str.WriteString(createIndexChange(stmt).Statement + "\n")
+	}
+
+	return nil
+}
+
+// sensitiveAnnotation returns the @sensitive annotation of a column policy.
+func sensitiveAnnotation(policy *engine.SensitivityPolicy) (string, error) {
+	switch policy.Mask {
+	case engine.MaskHash, engine.MaskRedact:
+		return fmt.Sprintf("-- @sensitive(%s)", policy.Mask), nil
+	case engine.MaskPartial:
+		return fmt.Sprintf("-- @sensitive(%s(%d))", policy.Mask, policy.PartialLength), nil
+	default:
+		return "", fmt.Errorf("unknown mask function %s", policy.Mask)
+	}
+}
@@ -0,0 +1,124 @@
+package parse_test
+
+import (
+	"testing"
+
+	"github.com/kwilteam/kwil-db/core/types"
+	"github.com/kwilteam/kwil-db/node/engine"
+	"github.com/kwilteam/kwil-db/node/engine/parse"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_GenerateSchema(t *testing.T) {
+	tables := []*engine.Table{
+		{
+			Name: "users",
+			Columns: []*engine.Column{
+				{Name: "id", DataType: types.UUIDType, IsPrimaryKey: true},
+				{Name: "name", DataType: types.TextType},
+				{Name: "email", DataType: types.TextType, SensitivityPolicy: &engine.SensitivityPolicy{Mask: engine.MaskPartial, PartialLength: 3}},
+				{Name: "ssn", DataType: types.TextType, Nullable: true, SensitivityPolicy: &engine.SensitivityPolicy{Mask: engine.MaskRedact}},
+				{Name: "tags", DataType: types.TextArrayType, Nullable: true},
+				{Name: "balance", DataType: mustNumeric(t, 20, 5), Nullable: true},
+			},
+			Indexes: []*engine.Index{
+				{Name: "users_pkey", Columns: []string{"id"}, Type: engine.PRIMARY},
+				{Name: "name_idx", Columns: []string{"name"}, Type: engine.BTREE},
+			},
+			Constraints: map[string]*engine.Constraint{
+				"email_unique": {Type: engine.ConstraintUnique, Columns: []string{"email"}},
+			},
+			SoftDelete: true,
+			History:    true,
+			OptimizerHints: []*engine.OptimizerHint{
+				{Name: "enable_seqscan", Value: "false"},
+			},
+		},
+		{
+			Name: "follows",
+			Columns: []*engine.Column{
+				{Name: "follower", DataType: types.UUIDType, IsPrimaryKey: true},
+				{Name: "followee", DataType: types.UUIDType, IsPrimaryKey: true},
+				{Name: "note", DataType: types.TextType, Nullable: true, SensitivityPolicy: &engine.SensitivityPolicy{Mask: engine.MaskHash}},
+			},
+			Indexes: []*engine.Index{
+				{Name: "followee_idx", Columns: []string{"followee", "follower"}, Type: engine.UNIQUE_BTREE},
+			},
+		},
+	}
+
+	schema, err := parse.GenerateSchema(tables)
+	require.NoError(t, err)
+	require.Equal(t, `-- @soft_delete
+-- @history
+-- @optimizer_hint(enable_seqscan, false)
+CREATE TABLE users (
+	id uuid,
+	name text NOT NULL,
+	-- @sensitive(partial(3))
+	email text NOT NULL,
+	-- @sensitive(redact)
+	ssn text,
+	tags text[],
+	balance numeric(20,5),
+	PRIMARY KEY (id),
+	CONSTRAINT email_unique UNIQUE (email)
+);
+CREATE INDEX name_idx ON users(name);
+
+CREATE TABLE follows (
+	follower uuid,
+	followee uuid,
+	-- @sensitive(hash)
+	note text,
+	PRIMARY KEY (follower, followee)
+);
+CREATE UNIQUE INDEX followee_idx ON follows(followee, follower);
+`, schema)
+
+	// the generated schema creates the same tables
+	stmts, err := parse.Parse(schema)
+	require.NoError(t, err)
+
+	changes, err := parse.DiffSchema(tables, stmts)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+
+	var created []*parse.CreateTableStatement
+	for _, stmt := range stmts {
+		if cts, ok := stmt.(*parse.CreateTableStatement); ok {
+			created = append(created, cts)
+		}
+	}
+	require.Len(t, created, len(tables))
+	for i, tbl := range tables {
+		cts := created[i]
+		require.Equal(t, tbl.Name, cts.Name)
+		require.Equal(t, tbl.SoftDelete, cts.SoftDelete)
+		require.Equal(t, tbl.History, cts.History)
+		require.Equal(t, tbl.OptimizerHints, cts.OptimizerHints)
+		require.Len(t, cts.Columns, len(tbl.Columns))
+		for j, col := range tbl.Columns {
+			require.Equal(t, col.SensitivityPolicy, cts.Columns[j].SensitivityPolicy, col.Name)
+		}
+	}
+}
+
+func Test_GenerateSchemaErrors(t *testing.T) {
+	_, err := parse.GenerateSchema([]*engine.Table{{Name: "empty"}})
+	require.ErrorContains(t, err, "table empty: table has no columns")
+
+	_, err = parse.GenerateSchema([]*engine.Table{{
+		Name: "users",
+		Columns: []*engine.Column{
+			{Name: "id", DataType: types.IntType, IsPrimaryKey: true, SensitivityPolicy: &engine.SensitivityPolicy{Mask: "shuffle"}},
+		},
+	}})
+	require.ErrorContains(t, err, "table users: column id: unknown mask function shuffle")
+}
+
+func mustNumeric(t *testing.T, precision, scale uint16) *types.DataType {
+	dt, err := types.NewNumericType(precision, scale)
+	require.NoError(t, err)
+	return dt
+}
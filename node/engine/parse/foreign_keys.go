@@ -0,0 +1,75 @@
+package parse
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ForeignKeyIndexWarnings returns a warning for each foreign key of the tables
+// created by a schema whose child columns are not covered by an index. Without
+// such an index, deleting or updating a referenced row scans the child table.
+// An index covers the columns if they are its leading columns. Primary keys and
+// unique constraints create indexes, and so do the CREATE INDEX statements of
+// the schema.
+func ForeignKeyIndexWarnings(schema []TopLevelStatement) []string {
+	indexes := make(map[string][][]string)
+	var tables []*CreateTableStatement
+	for _, stmt := range schema {
+		switch stmt := stmt.(type) {
+		case *CreateTableStatement:
+			tables = append(tables, stmt)
+		case *CreateIndexStatement:
+			indexes[stmt.On] = append(indexes[stmt.On], stmt.Columns)
+		}
+	}
+
+	var warnings []string
+	for _, tbl := range tables {
+		tblIndexes := indexes[tbl.Name]
+		var foreignKeys [][]string
+
+		for _, col := range tbl.Columns {
+			for _, c := range col.Constraints {
+				switch c.(type) {
+				case *PrimaryKeyInlineConstraint, *UniqueInlineConstraint:
+					tblIndexes = append(tblIndexes, []string{col.Name})
+				case *ForeignKeyReferences:
+					foreignKeys = append(foreignKeys, []string{col.Name})
+				}
+			}
+		}
+		for _, c := range tbl.Constraints {
+			switch c := c.Constraint.(type) {
+			case *PrimaryKeyOutOfLineConstraint:
+				tblIndexes = append(tblIndexes, c.Columns)
+			case *UniqueOutOfLineConstraint:
+				tblIndexes = append(tblIndexes, c.Columns)
+			case *ForeignKeyOutOfLineConstraint:
+				foreignKeys = append(foreignKeys, c.Columns)
+			}
+		}
+
+		for _, fk := range foreignKeys {
+			if !slices.ContainsFunc(tblIndexes, func(idx []string) bool { return indexCovers(idx, fk) }) {
+				warnings = append(warnings, fmt.Sprintf("table %s: foreign key on (%s) has no index on its columns", tbl.Name, strings.Join(fk, ", ")))
+			}
+		}
+	}
+
+	return warnings
+}
+
+// indexCovers returns true if the columns are the leading columns of an index,
+// in any order.
+func indexCovers(index, columns []string) bool {
+	if len(index) < len(columns) {
+		return false
+	}
+	for _, col := range columns {
+		if !slices.Contains(index[:len(columns)], col) {
+			return false
+		}
+	}
+	return true
+}
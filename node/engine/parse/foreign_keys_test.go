@@ -0,0 +1,57 @@
+package parse_test
+
+import (
+	"testing"
+
+	"github.com/kwilteam/kwil-db/node/engine/parse"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ForeignKeyIndexWarnings(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+		want   []string
+	}{
+		{
+			name: "no index on child column",
+			schema: `CREATE TABLE users (id int primary key);
+			CREATE TABLE posts (id int primary key, author int references users(id));`,
+			want: []string{"table posts: foreign key on (author) has no index on its columns"},
+		},
+		{
+			name: "index on child column",
+			schema: `CREATE TABLE users (id int primary key);
+			CREATE TABLE posts (id int primary key, author int references users(id));
+			CREATE INDEX ON posts(author, id);`,
+		},
+		{
+			name: "child column is the primary key",
+			schema: `CREATE TABLE users (id int primary key);
+			CREATE TABLE profiles (user_id int primary key references users(id));`,
+		},
+		{
+			name: "out of line foreign key covered by unique constraint",
+			schema: `CREATE TABLE users (id int, org int, primary key (id, org));
+			CREATE TABLE posts (id int primary key, author int, org int,
+				unique (org, author),
+				foreign key (author, org) references users(id, org));`,
+		},
+		{
+			name: "child columns are not the leading columns of the index",
+			schema: `CREATE TABLE users (id int primary key);
+			CREATE TABLE posts (id int primary key, author int, foreign key (author) references users(id));
+			CREATE INDEX ON posts(id, author);`,
+			want: []string{"table posts: foreign key on (author) has no index on its columns"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stmts, err := parse.Parse(tt.schema)
+			require.NoError(t, err)
+
+			require.Equal(t, tt.want, parse.ForeignKeyIndexWarnings(stmts))
+		})
+	}
+}
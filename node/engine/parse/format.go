@@ -0,0 +1,1099 @@
+package parse
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/kwilteam/kwil-db/core/types"
+)
+
+// Format returns the canonical Kuneiform of the statements. Keywords are
+// upper case, identifiers and types are lower case, each statement is
+// terminated by a semicolon and separated from the next by a blank line,
+// table columns and action statements are indented with tabs, and the
+// clauses of SQL statements start on their own lines. Subqueries, and the
+// SQL statements that FOR loops iterate over, are kept on a single line.
+//
+// Since the statements are formatted from their AST, comments that are not
+// annotations are not kept. Annotations are written as SQL comments directly
+// preceding the element they apply to. Formatting is idempotent, and the
+// formatted statements parse to the same AST as the statements that were
+// formatted.
+func Format(stmts []TopLevelStatement) (str string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			if e, ok := r.(error); ok {
+				err = e
+			} else {
+				err = fmt.Errorf("%v", r)
+			}
+		}
+	}()
+
+	f := &kuneiformFormatter{}
+	var res strings.Builder
+	for i, stmt := range stmts {
+		if i > 0 {
+			res.WriteString("\n")
+		}
+		res.WriteString(stmt.Accept(f).(string))
+		res.WriteString(";\n")
+	}
+
+	return res.String(), nil
+}
+
+// kuneiformFormatter is a visitor that formats the AST as Kuneiform. Each
+// visit returns the formatted node as a string.
+type kuneiformFormatter struct {
+	// indent is the indentation level of the statement being formatted.
+	indent int
+	// inline is greater than zero if SQL is being formatted on a single
+	// line, such as in a subquery.
+	inline int
+}
+
+var _ Visitor = (*kuneiformFormatter)(nil)
+
+// nl returns the separator that starts a new clause of a SQL statement.
+func (f *kuneiformFormatter) nl() string {
+	if f.inline > 0 {
+		return " "
+	}
+	return "\n" + strings.Repeat("\t", f.indent)
+}
+
+// inlined formats the node on a single line.
+func (f *kuneiformFormatter) inlined(n Node) string {
+	f.inline++
+	defer func() { f.inline-- }()
+	return n.Accept(f).(string)
+}
+
+// join formats the nodes and joins them with the separator.
+func join[T Node](f *kuneiformFormatter, nodes []T, sep string) string {
+	strs := make([]string, len(nodes))
+	for i, n := range nodes {
+		strs[i] = n.Accept(f).(string)
+	}
+	return strings.Join(strs, sep)
+}
+
+// block formats the statements of a block, such as the body of an action,
+// one level deeper than the current indentation, followed by the closing
+// brace.
+func (f *kuneiformFormatter) block(stmts []ActionStmt) string {
+	if len(stmts) == 0 {
+		return "{}"
+	}
+
+	var str strings.Builder
+	str.WriteString("{\n")
+	f.indent++
+	for _, stmt := range stmts {
+		str.WriteString(strings.Repeat("\t", f.indent))
+		str.WriteString(stmt.Accept(f).(string))
+		str.WriteString("\n")
+	}
+	f.indent--
+	str.WriteString(strings.Repeat("\t", f.indent) + "}")
+	return str.String()
+}
+
+// namespacePrefix returns the {namespace} prefix of a statement. If the
+// statement has annotations, they follow the prefix on their own lines so
+// that they still directly precede the statement.
+func namespacePrefix(n Namespacing, annotations string) string {
+	if n.NamespacePrefix == "" {
+		return annotations
+	}
+	if annotations == "" {
+		return "{" + n.NamespacePrefix + "}"
+	}
+	return "{" + n.NamespacePrefix + "}\n" + annotations
+}
+
+// formatTypeCast returns the type cast of the expression, if any.
+func formatTypeCast(t Typecasted) string {
+	if t.GetTypeCast() == nil {
+		return ""
+	}
+	return "::" + t.GetTypeCast().String()
+}
+
+func (f *kuneiformFormatter) VisitExpressionLiteral(p0 *ExpressionLiteral) any {
+	var str string
+	switch v := p0.Value.(type) {
+	case nil:
+		str = "NULL"
+	case string:
+		// string literals keep their escape sequences, so they are
+		// written as they were parsed
+		str = "'" + v + "'"
+	case int64, int, int32:
+		str = fmt.Sprint(v)
+	case *types.Decimal:
+		str = v.FullString()
+	case bool:
+		str = "FALSE"
+		if v {
+			str = "TRUE"
+		}
+	case []byte:
+		str = "0x" + hex.EncodeToString(v)
+	default:
+		panic(fmt.Errorf("unsupported literal type: %T", v))
+	}
+
+	return str + formatTypeCast(p0)
+}
+
+func (f *kuneiformFormatter) VisitExpressionFunctionCall(p0 *ExpressionFunctionCall) any {
+	var str strings.Builder
+	if p0.Namespace != "" {
+		str.WriteString(p0.Namespace + ".")
+	}
+	str.WriteString(p0.Name + "(")
+	switch {
+	case p0.Star:
+		str.WriteString("*")
+	case p0.Distinct:
+		str.WriteString("DISTINCT ")
+		fallthrough
+	default:
+		str.WriteString(join(f, p0.Args, ", "))
+	}
+	str.WriteString(")")
+
+	return str.String() + formatTypeCast(p0)
+}
+
+func (f *kuneiformFormatter) VisitExpressionWindowFunctionCall(p0 *ExpressionWindowFunctionCall) any {
+	str := p0.FunctionCall.Accept(f).(string)
+	if p0.Filter != nil {
+		str += " FILTER (WHERE " + p0.Filter.Accept(f).(string) + ")"
+	}
+
+	return str + " OVER " + p0.Window.Accept(f).(string)
+}
+
+func (f *kuneiformFormatter) VisitWindowImpl(p0 *WindowImpl) any {
+	var clauses []string
+	if len(p0.PartitionBy) > 0 {
+		clauses = append(clauses, "PARTITION BY "+join(f, p0.PartitionBy, ", "))
+	}
+	if len(p0.OrderBy) > 0 {
+		clauses = append(clauses, "ORDER BY "+join(f, p0.OrderBy, ", "))
+	}
+
+	return "(" + strings.Join(clauses, " ") + ")"
+}
+
+func (f *kuneiformFormatter) VisitWindowReference(p0 *WindowReference) any {
+	return p0.Name
+}
+
+func (f *kuneiformFormatter) VisitExpressionVariable(p0 *ExpressionVariable) any {
+	return p0.Name + formatTypeCast(p0)
+}
+
+func (f *kuneiformFormatter) VisitExpressionArrayAccess(p0 *ExpressionArrayAccess) any {
+	var index string
+	switch {
+	case p0.Index != nil:
+		index = p0.Index.Accept(f).(string)
+	case p0.FromTo != nil:
+		if p0.FromTo[0] != nil {
+			index = p0.FromTo[0].Accept(f).(string)
+		}
+		index += ":"
+		if p0.FromTo[1] != nil {
+			index += p0.FromTo[1].Accept(f).(string)
+		}
+	default:
+		index = ":"
+	}
+
+	return p0.Array.Accept(f).(string) + "[" + index + "]" + formatTypeCast(p0)
+}
+
+func (f *kuneiformFormatter) VisitExpressionMakeArray(p0 *ExpressionMakeArray) any {
+	return "ARRAY[" + join(f, p0.Values, ", ") + "]" + formatTypeCast(p0)
+}
+
+func (f *kuneiformFormatter) VisitExpressionFieldAccess(p0 *ExpressionFieldAccess) any {
+	return p0.Record.Accept(f).(string) + "." + p0.Field + formatTypeCast(p0)
+}
+
+func (f *kuneiformFormatter) VisitExpressionParenthesized(p0 *ExpressionParenthesized) any {
+	return "(" + p0.Inner.Accept(f).(string) + ")" + formatTypeCast(p0)
+}
+
+// operand formats the operand of a comparison or an arithmetic expression. In
+// actions, ! negates the expression that directly follows it, while NOT
+// negates the whole comparison, so negated operands are written with !. In
+// SQL, a negated operand is always parenthesized.
+func (f *kuneiformFormatter) operand(e Expression) string {
+	if u, ok := e.(*ExpressionUnary); ok && u.Operator == UnaryOperatorNot {
+		return "!" + u.Expression.Accept(f).(string)
+	}
+	return e.Accept(f).(string)
+}
+
+func (f *kuneiformFormatter) VisitExpressionComparison(p0 *ExpressionComparison) any {
+	return f.operand(p0.Left) + " " + string(p0.Operator) + " " + f.operand(p0.Right)
+}
+
+func (f *kuneiformFormatter) VisitExpressionLogical(p0 *ExpressionLogical) any {
+	return p0.Left.Accept(f).(string) + " " + string(p0.Operator) + " " + p0.Right.Accept(f).(string)
+}
+
+func (f *kuneiformFormatter) VisitExpressionArithmetic(p0 *ExpressionArithmetic) any {
+	return f.operand(p0.Left) + " " + string(p0.Operator) + " " + f.operand(p0.Right)
+}
+
+func (f *kuneiformFormatter) VisitExpressionUnary(p0 *ExpressionUnary) any {
+	expr := p0.Expression.Accept(f).(string)
+	if p0.Operator == UnaryOperatorNot {
+		return "NOT " + expr
+	}
+	// a space is needed between signs, since -- starts a comment
+	if strings.HasPrefix(expr, "-") || strings.HasPrefix(expr, "+") {
+		return string(p0.Operator) + " " + expr
+	}
+	return string(p0.Operator) + expr
+}
+
+func (f *kuneiformFormatter) VisitExpressionColumn(p0 *ExpressionColumn) any {
+	return p0.String() + formatTypeCast(p0)
+}
+
+func (f *kuneiformFormatter) VisitExpressionCollate(p0 *ExpressionCollate) any {
+	return p0.Expression.Accept(f).(string) + " COLLATE " + p0.Collation
+}
+
+func (f *kuneiformFormatter) VisitExpressionStringComparison(p0 *ExpressionStringComparison) any {
+	op := " "
+	if p0.Not {
+		op += "NOT "
+	}
+	op += string(p0.Operator) + " "
+
+	return p0.Left.Accept(f).(string) + op + p0.Right.Accept(f).(string)
+}
+
+func (f *kuneiformFormatter) VisitExpressionIs(p0 *ExpressionIs) any {
+	op := " IS "
+	if p0.Not {
+		op += "NOT "
+	}
+	if p0.Distinct {
+		op += "DISTINCT FROM "
+	}
+
+	return f.operand(p0.Left) + op + p0.Right.Accept(f).(string)
+}
+
+func (f *kuneiformFormatter) VisitExpressionIn(p0 *ExpressionIn) any {
+	op := " IN "
+	if p0.Not {
+		op = " NOT IN "
+	}
+
+	var list string
+	if p0.Subquery != nil {
+		list = f.inlined(p0.Subquery)
+	} else {
+		list = join(f, p0.List, ", ")
+	}
+
+	return p0.Expression.Accept(f).(string) + op + "(" + list + ")"
+}
+
+func (f *kuneiformFormatter) VisitExpressionBetween(p0 *ExpressionBetween) any {
+	op := " BETWEEN "
+	if p0.Not {
+		op = " NOT BETWEEN "
+	}
+
+	return p0.Expression.Accept(f).(string) + op + p0.Lower.Accept(f).(string) + " AND " + p0.Upper.Accept(f).(string)
+}
+
+func (f *kuneiformFormatter) VisitExpressionSubquery(p0 *ExpressionSubquery) any {
+	var str string
+	if p0.Not {
+		str = "NOT "
+	}
+	if p0.Exists {
+		str += "EXISTS "
+	}
+
+	return str + "(" + f.inlined(p0.Subquery) + ")" + formatTypeCast(p0)
+}
+
+func (f *kuneiformFormatter) VisitExpressionCase(p0 *ExpressionCase) any {
+	var str strings.Builder
+	str.WriteString("CASE")
+	if p0.Case != nil {
+		str.WriteString(" " + p0.Case.Accept(f).(string))
+	}
+	for _, wt := range p0.WhenThen {
+		str.WriteString(" WHEN " + wt[0].Accept(f).(string) + " THEN " + wt[1].Accept(f).(string))
+	}
+	if p0.Else != nil {
+		str.WriteString(" ELSE " + p0.Else.Accept(f).(string))
+	}
+	str.WriteString(" END")
+
+	return str.String()
+}
+
+func (f *kuneiformFormatter) VisitCommonTableExpression(p0 *CommonTableExpression) any {
+	str := p0.Name
+	if len(p0.Columns) > 0 {
+		str += " (" + strings.Join(p0.Columns, ", ") + ")"
+	}
+
+	return str + " AS (" + f.inlined(p0.Query) + ")"
+}
+
+func (f *kuneiformFormatter) VisitSQLStatement(p0 *SQLStatement) any {
+	var str strings.Builder
+	str.WriteString(namespacePrefix(p0.Namespacing, ""))
+	if len(p0.CTEs) > 0 {
+		str.WriteString("WITH ")
+		if p0.Recursive {
+			str.WriteString("RECURSIVE ")
+		}
+		str.WriteString(join(f, p0.CTEs, ", "))
+		str.WriteString(f.nl())
+	}
+	str.WriteString(p0.SQL.Accept(f).(string))
+
+	return str.String()
+}
+
+func (f *kuneiformFormatter) VisitSelectStatement(p0 *SelectStatement) any {
+	var str strings.Builder
+	for i, core := range p0.SelectCores {
+		if i > 0 {
+			str.WriteString(f.nl() + string(p0.CompoundOperators[i-1]) + f.nl())
+		}
+		str.WriteString(core.Accept(f).(string))
+	}
+
+	if len(p0.Ordering) > 0 {
+		str.WriteString(f.nl() + "ORDER BY " + join(f, p0.Ordering, ", "))
+	}
+	if p0.Limit != nil {
+		str.WriteString(f.nl() + "LIMIT " + p0.Limit.Accept(f).(string))
+	}
+	if p0.Offset != nil {
+		str.WriteString(f.nl() + "OFFSET " + p0.Offset.Accept(f).(string))
+	}
+
+	return str.String()
+}
+
+func (f *kuneiformFormatter) VisitSelectCore(p0 *SelectCore) any {
+	var str strings.Builder
+	str.WriteString("SELECT ")
+	if p0.Distinct {
+		str.WriteString("DISTINCT ")
+	}
+	str.WriteString(join(f, p0.Columns, ", "))
+
+	if p0.From != nil {
+		str.WriteString(f.nl() + "FROM " + p0.From.Accept(f).(string))
+	}
+	for _, j := range p0.Joins {
+		str.WriteString(f.nl() + j.Accept(f).(string))
+	}
+	if p0.Where != nil {
+		str.WriteString(f.nl() + "WHERE " + p0.Where.Accept(f).(string))
+	}
+	if len(p0.GroupBy) > 0 {
+		str.WriteString(f.nl() + "GROUP BY " + join(f, p0.GroupBy, ", "))
+	}
+	if p0.Having != nil {
+		str.WriteString(f.nl() + "HAVING " + p0.Having.Accept(f).(string))
+	}
+	if len(p0.Windows) > 0 {
+		windows := make([]string, len(p0.Windows))
+		for i, w := range p0.Windows {
+			windows[i] = w.Name + " AS " + w.Window.Accept(f).(string)
+		}
+		str.WriteString(f.nl() + "WINDOW " + strings.Join(windows, ", "))
+	}
+
+	return str.String()
+}
+
+func (f *kuneiformFormatter) VisitResultColumnExpression(p0 *ResultColumnExpression) any {
+	str := p0.Expression.Accept(f).(string)
+	if p0.Alias != "" {
+		str += " AS " + p0.Alias
+	}
+	return str
+}
+
+func (f *kuneiformFormatter) VisitResultColumnWildcard(p0 *ResultColumnWildcard) any {
+	if p0.Table != "" {
+		return p0.Table + ".*"
+	}
+	return "*"
+}
+
+func (f *kuneiformFormatter) VisitRelationTable(p0 *RelationTable) any {
+	str := p0.Table
+	if p0.Namespace != "" {
+		str = p0.Namespace + "." + str
+	}
+	if p0.Alias != "" {
+		str += " AS " + p0.Alias
+	}
+	return str
+}
+
+func (f *kuneiformFormatter) VisitRelationSubquery(p0 *RelationSubquery) any {
+	str := "(" + f.inlined(p0.Subquery) + ")"
+	if p0.Lateral {
+		str = "LATERAL " + str
+	}
+	if p0.Alias != "" {
+		str += " AS " + p0.Alias
+	}
+	return str
+}
+
+func (f *kuneiformFormatter) VisitRelationFunctionCall(p0 *RelationFunctionCall) any {
+	str := p0.Name + "(" + join(f, p0.Args, ", ") + ")"
+	if p0.Alias != "" {
+		str += " AS " + p0.Alias
+	}
+	return str
+}
+
+func (f *kuneiformFormatter) VisitJoin(p0 *Join) any {
+	str := "JOIN "
+	if p0.Type != "" {
+		str = string(p0.Type) + " JOIN "
+	}
+
+	return str + p0.Relation.Accept(f).(string) + " ON " + p0.On.Accept(f).(string)
+}
+
+func (f *kuneiformFormatter) VisitUpdateStatement(p0 *UpdateStatement) any {
+	var str strings.Builder
+	str.WriteString("UPDATE " + p0.Table)
+	if p0.Alias != "" {
+		str.WriteString(" AS " + p0.Alias)
+	}
+	str.WriteString(f.nl() + "SET " + join(f, p0.SetClause, ", "))
+
+	if p0.From != nil {
+		str.WriteString(f.nl() + "FROM " + p0.From.Accept(f).(string))
+	}
+	for _, j := range p0.Joins {
+		str.WriteString(f.nl() + j.Accept(f).(string))
+	}
+	if p0.Where != nil {
+		str.WriteString(f.nl() + "WHERE " + p0.Where.Accept(f).(string))
+	}
+
+	return str.String()
+}
+
+func (f *kuneiformFormatter) VisitUpdateSetClause(p0 *UpdateSetClause) any {
+	return p0.Column + " = " + p0.Value.Accept(f).(string)
+}
+
+func (f *kuneiformFormatter) VisitDeleteStatement(p0 *DeleteStatement) any {
+	var str strings.Builder
+	str.WriteString("DELETE FROM " + p0.Table)
+	if p0.Alias != "" {
+		str.WriteString(" AS " + p0.Alias)
+	}
+	if p0.From != nil || len(p0.Joins) > 0 {
+		panic(fmt.Errorf("DELETE with FROM or JOIN cannot be formatted"))
+	}
+	if p0.Where != nil {
+		str.WriteString(f.nl() + "WHERE " + p0.Where.Accept(f).(string))
+	}
+
+	return str.String()
+}
+
+func (f *kuneiformFormatter) VisitInsertStatement(p0 *InsertStatement) any {
+	var str strings.Builder
+	str.WriteString("INSERT INTO " + p0.Table)
+	if p0.Alias != "" {
+		str.WriteString(" AS " + p0.Alias)
+	}
+	if len(p0.Columns) > 0 {
+		str.WriteString(" (" + strings.Join(p0.Columns, ", ") + ")")
+	}
+
+	if p0.Select != nil {
+		str.WriteString(f.nl() + p0.Select.Accept(f).(string))
+	} else {
+		rows := make([]string, len(p0.Values))
+		for i, row := range p0.Values {
+			rows[i] = "(" + join(f, row, ", ") + ")"
+		}
+		str.WriteString(f.nl() + "VALUES " + strings.Join(rows, ", "))
+	}
+
+	if p0.OnConflict != nil {
+		str.WriteString(f.nl() + p0.OnConflict.Accept(f).(string))
+	}
+
+	return str.String()
+}
+
+func (f *kuneiformFormatter) VisitUpsertClause(p0 *OnConflict) any {
+	var str strings.Builder
+	str.WriteString("ON CONFLICT")
+	if len(p0.ConflictColumns) > 0 {
+		str.WriteString(" (" + strings.Join(p0.ConflictColumns, ", ") + ")")
+		if p0.ConflictWhere != nil {
+			str.WriteString(" WHERE " + p0.ConflictWhere.Accept(f).(string))
+		}
+	}
+
+	if len(p0.DoUpdate) == 0 {
+		str.WriteString(" DO NOTHING")
+		return str.String()
+	}
+
+	str.WriteString(" DO UPDATE SET " + join(f, p0.DoUpdate, ", "))
+	if p0.UpdateWhere != nil {
+		str.WriteString(" WHERE " + p0.UpdateWhere.Accept(f).(string))
+	}
+
+	return str.String()
+}
+
+func (f *kuneiformFormatter) VisitOrderingTerm(p0 *OrderingTerm) any {
+	str := p0.Expression.Accept(f).(string)
+	if p0.Order != "" {
+		str += " " + string(p0.Order)
+	}
+	if p0.Nulls != "" {
+		str += " NULLS " + string(p0.Nulls)
+	}
+	return str
+}
+
+func (f *kuneiformFormatter) VisitCreateTableStatement(p0 *CreateTableStatement) any {
+	var defs []string
+	for _, col := range p0.Columns {
+		defs = append(defs, col.Accept(f).(string))
+	}
+	for _, c := range p0.Constraints {
+		defs = append(defs, f.outOfLineConstraint(c))
+	}
+
+	var str strings.Builder
+	str.WriteString(namespacePrefix(p0.Namespacing, tableAnnotations(p0)))
+	str.WriteString("CREATE TABLE ")
+	if p0.IfNotExists {
+		str.WriteString("IF NOT EXISTS ")
+	}
+	str.WriteString(p0.Name + " (\n\t" + strings.Join(defs, ",\n\t") + "\n)")
+
+	return str.String()
+}
+
+// outOfLineConstraint formats a table constraint, with its name if it has
+// one.
+func (f *kuneiformFormatter) outOfLineConstraint(c *OutOfLineConstraint) string {
+	str := c.Constraint.Accept(f).(string)
+	if c.Name != "" {
+		str = "CONSTRAINT " + c.Name + " " + str
+	}
+	return str
+}
+
+func (f *kuneiformFormatter) VisitAlterTableStatement(p0 *AlterTableStatement) any {
+	return namespacePrefix(p0.Namespacing, "") + "ALTER TABLE " + p0.Table + " " + join(f, p0.Actions, ", ")
+}
+
+func (f *kuneiformFormatter) VisitDropTableStatement(p0 *DropTableStatement) any {
+	var str strings.Builder
+	str.WriteString(namespacePrefix(p0.Namespacing, ""))
+	str.WriteString("DROP TABLE ")
+	if p0.IfExists {
+		str.WriteString("IF EXISTS ")
+	}
+	str.WriteString(strings.Join(p0.Tables, ", "))
+	if p0.Behavior != DropBehaviorDefault {
+		str.WriteString(" " + string(p0.Behavior))
+	}
+
+	return str.String()
+}
+
+func (f *kuneiformFormatter) VisitTruncateTableStatement(p0 *TruncateTableStatement) any {
+	str := namespacePrefix(p0.Namespacing, "") + "TRUNCATE TABLE " + p0.Table
+	if p0.Cascade {
+		str += " CASCADE"
+	}
+	return str
+}
+
+func (f *kuneiformFormatter) VisitCreateIndexStatement(p0 *CreateIndexStatement) any {
+	var str strings.Builder
+	str.WriteString(namespacePrefix(p0.Namespacing, ""))
+	str.WriteString("CREATE ")
+	if p0.Type == IndexTypeUnique {
+		str.WriteString("UNIQUE ")
+	}
+	str.WriteString("INDEX ")
+	if p0.Concurrently {
+		str.WriteString("CONCURRENTLY ")
+	}
+	if p0.IfNotExists {
+		str.WriteString("IF NOT EXISTS ")
+	}
+	if p0.Name != "" {
+		str.WriteString(p0.Name + " ")
+	}
+	fmt.Fprintf(&str, "ON %s(%s)", p0.On, strings.Join(p0.Columns, ", "))
+
+	return str.String()
+}
+
+func (f *kuneiformFormatter) VisitDropIndexStatement(p0 *DropIndexStatement) any {
+	str := namespacePrefix(p0.Namespacing, "") + "DROP INDEX "
+	if p0.CheckExist {
+		str += "IF EXISTS "
+	}
+	return str + p0.Name
+}
+
+func (f *kuneiformFormatter) VisitGrantOrRevokeStatement(p0 *GrantOrRevokeStatement) any {
+	var str strings.Builder
+	if p0.IsGrant {
+		str.WriteString("GRANT ")
+		if p0.If {
+			str.WriteString("IF NOT GRANTED ")
+		}
+	} else {
+		str.WriteString("REVOKE ")
+		if p0.If {
+			str.WriteString("IF GRANTED ")
+		}
+	}
+
+	if len(p0.Privileges) > 0 {
+		privs := make([]string, len(p0.Privileges))
+		for i, p := range p0.Privileges {
+			privs[i] = strings.ToUpper(p)
+		}
+		str.WriteString(strings.Join(privs, ", "))
+	} else {
+		str.WriteString(p0.GrantRole)
+	}
+	if p0.Namespace != nil {
+		str.WriteString(" ON " + *p0.Namespace)
+	}
+
+	if p0.IsGrant {
+		str.WriteString(" TO ")
+	} else {
+		str.WriteString(" FROM ")
+	}
+	switch {
+	case p0.ToRole != "":
+		str.WriteString(p0.ToRole)
+	case p0.ToVariable != nil:
+		str.WriteString(p0.ToVariable.Accept(f).(string))
+	default:
+		str.WriteString("'" + p0.ToUser + "'")
+	}
+
+	return str.String()
+}
+
+func (f *kuneiformFormatter) VisitTransferOwnershipStatement(p0 *TransferOwnershipStatement) any {
+	if p0.ToVariable != nil {
+		return "TRANSFER OWNERSHIP TO " + p0.ToVariable.Accept(f).(string)
+	}
+	return "TRANSFER OWNERSHIP TO '" + p0.ToUser + "'"
+}
+
+func (f *kuneiformFormatter) VisitAlterColumnSet(p0 *AlterColumnSet) any {
+	str := "ALTER COLUMN " + p0.Column + " SET " + p0.Type.String()
+	if p0.Type == ConstraintTypeDefault {
+		str += " " + p0.Value.Accept(f).(string)
+	}
+	return str
+}
+
+func (f *kuneiformFormatter) VisitAlterColumnDrop(p0 *AlterColumnDrop) any {
+	return "ALTER COLUMN " + p0.Column + " DROP " + p0.Type.String()
+}
+
+func (f *kuneiformFormatter) VisitAddColumn(p0 *AddColumn) any {
+	str := "ADD COLUMN "
+	if p0.IfNotExists {
+		str += "IF NOT EXISTS "
+	}
+	return str + p0.Name + " " + p0.Type.String()
+}
+
+func (f *kuneiformFormatter) VisitDropColumn(p0 *DropColumn) any {
+	str := "DROP COLUMN "
+	if p0.IfExists {
+		str += "IF EXISTS "
+	}
+	return str + p0.Name
+}
+
+func (f *kuneiformFormatter) VisitRenameColumn(p0 *RenameColumn) any {
+	return "RENAME COLUMN " + p0.OldName + " TO " + p0.NewName
+}
+
+func (f *kuneiformFormatter) VisitRenameTable(p0 *RenameTable) any {
+	return "RENAME TO " + p0.Name
+}
+
+func (f *kuneiformFormatter) VisitAddTableConstraint(p0 *AddTableConstraint) any {
+	return "ADD " + f.outOfLineConstraint(p0.Constraint)
+}
+
+func (f *kuneiformFormatter) VisitDropTableConstraint(p0 *DropTableConstraint) any {
+	str := "DROP CONSTRAINT "
+	if p0.IfExists {
+		str += "IF EXISTS "
+	}
+	return str + p0.Name
+}
+
+func (f *kuneiformFormatter) VisitColumn(p0 *Column) any {
+	var str strings.Builder
+	if p0.SensitivityPolicy != nil {
+		annotation, err := sensitiveAnnotation(p0.SensitivityPolicy)
+		if err != nil {
+			panic(fmt.Errorf("column %s: %w", p0.Name, err))
+		}
+		str.WriteString(annotation + "\n\t")
+	}
+
+	str.WriteString(p0.Name + " " + p0.Type.String())
+	for _, c := range p0.Constraints {
+		str.WriteString(" " + c.Accept(f).(string))
+	}
+
+	return str.String()
+}
+
+func (f *kuneiformFormatter) VisitCreateRoleStatement(p0 *CreateRoleStatement) any {
+	if p0.IfNotExists {
+		return "CREATE ROLE IF NOT EXISTS " + p0.Role
+	}
+	return "CREATE ROLE " + p0.Role
+}
+
+func (f *kuneiformFormatter) VisitDropRoleStatement(p0 *DropRoleStatement) any {
+	if p0.IfExists {
+		return "DROP ROLE IF EXISTS " + p0.Role
+	}
+	return "DROP ROLE " + p0.Role
+}
+
+func (f *kuneiformFormatter) VisitUseExtensionStatement(p0 *UseExtensionStatement) any {
+	var str strings.Builder
+	str.WriteString("USE ")
+	if p0.IfNotExists {
+		str.WriteString("IF NOT EXISTS ")
+	}
+	str.WriteString(p0.ExtName)
+
+	if len(p0.Config) > 0 {
+		config := make([]string, len(p0.Config))
+		for i, c := range p0.Config {
+			config[i] = c.Key + ": " + c.Value.Accept(f).(string)
+		}
+		str.WriteString(" {\n\t" + strings.Join(config, ",\n\t") + "\n}")
+	}
+	str.WriteString(" AS " + p0.Alias)
+
+	return str.String()
+}
+
+func (f *kuneiformFormatter) VisitUnuseExtensionStatement(p0 *UnuseExtensionStatement) any {
+	if p0.IfExists {
+		return "UNUSE " + p0.Alias + " IF EXISTS"
+	}
+	return "UNUSE " + p0.Alias
+}
+
+func (f *kuneiformFormatter) VisitCreateNamespaceStatement(p0 *CreateNamespaceStatement) any {
+	var annotations string
+	if p0.EventSourced {
+		annotations += "-- @event_sourced\n"
+	}
+	if p0.Test {
+		annotations += "-- @test_namespace\n"
+	}
+
+	if p0.IfNotExists {
+		return annotations + "CREATE NAMESPACE IF NOT EXISTS " + p0.Namespace
+	}
+	return annotations + "CREATE NAMESPACE " + p0.Namespace
+}
+
+func (f *kuneiformFormatter) VisitDropNamespaceStatement(p0 *DropNamespaceStatement) any {
+	if p0.IfExists {
+		return "DROP NAMESPACE IF EXISTS " + p0.Namespace
+	}
+	return "DROP NAMESPACE " + p0.Namespace
+}
+
+func (f *kuneiformFormatter) VisitSetCurrentNamespaceStatement(p0 *SetCurrentNamespaceStatement) any {
+	return "SET CURRENT NAMESPACE TO " + p0.Namespace
+}
+
+func (f *kuneiformFormatter) VisitAlterNamespaceStatement(p0 *AlterNamespaceStatement) any {
+	if p0.Config != nil {
+		return "ALTER NAMESPACE " + p0.Namespace + " SET CONFIG '" + p0.Config.Key + "' = '" + p0.Config.Value + "'"
+	}
+	if p0.ReadOnly {
+		return "ALTER NAMESPACE " + p0.Namespace + " SET READ ONLY"
+	}
+	return "ALTER NAMESPACE " + p0.Namespace + " SET READ WRITE"
+}
+
+func (f *kuneiformFormatter) VisitCreateActionStatement(p0 *CreateActionStatement) any {
+	var annotations strings.Builder
+	for _, hint := range p0.OptimizerHints {
+		fmt.Fprintf(&annotations, "-- @optimizer_hint(%s, %s)\n", hint.Name, hint.Value)
+	}
+	switch {
+	case len(p0.DistinctOn) > 0:
+		fmt.Fprintf(&annotations, "-- @distinct_on(%s)\n", strings.Join(p0.DistinctOn, ", "))
+	case p0.Distinct:
+		annotations.WriteString("-- @distinct\n")
+	}
+	if p0.Idempotent {
+		annotations.WriteString("-- @idempotent\n")
+	}
+	if p0.Test {
+		annotations.WriteString("-- @test\n")
+	}
+
+	var str strings.Builder
+	str.WriteString(namespacePrefix(p0.Namespacing, annotations.String()))
+	str.WriteString("CREATE ")
+	if p0.OrReplace {
+		str.WriteString("OR REPLACE ")
+	}
+	str.WriteString("ACTION ")
+	if p0.IfNotExists {
+		str.WriteString("IF NOT EXISTS ")
+	}
+
+	params := make([]string, len(p0.Parameters))
+	for i, param := range p0.Parameters {
+		params[i] = param.Name + " " + param.Type.String()
+	}
+	str.WriteString(p0.Name + "(" + strings.Join(params, ", ") + ")")
+
+	for _, mod := range p0.Modifiers {
+		str.WriteString(" " + mod)
+	}
+
+	if p0.Returns != nil {
+		fields := make([]string, len(p0.Returns.Fields))
+		for i, field := range p0.Returns.Fields {
+			fields[i] = field.Type.String()
+			if field.Name != "" {
+				fields[i] = field.Name + " " + fields[i]
+			}
+		}
+
+		str.WriteString(" RETURNS ")
+		if p0.Returns.IsTable {
+			str.WriteString("TABLE")
+		}
+		str.WriteString("(" + strings.Join(fields, ", ") + ")")
+	}
+
+	str.WriteString(" " + f.block(p0.Statements))
+
+	return str.String()
+}
+
+func (f *kuneiformFormatter) VisitDropActionStatement(p0 *DropActionStatement) any {
+	str := namespacePrefix(p0.Namespacing, "") + "DROP ACTION "
+	if p0.IfExists {
+		str += "IF EXISTS "
+	}
+	return str + p0.Name
+}
+
+func (f *kuneiformFormatter) VisitPrimaryKeyInlineConstraint(p0 *PrimaryKeyInlineConstraint) any {
+	return "PRIMARY KEY"
+}
+
+func (f *kuneiformFormatter) VisitPrimaryKeyOutOfLineConstraint(p0 *PrimaryKeyOutOfLineConstraint) any {
+	return "PRIMARY KEY (" + strings.Join(p0.Columns, ", ") + ")"
+}
+
+func (f *kuneiformFormatter) VisitUniqueInlineConstraint(p0 *UniqueInlineConstraint) any {
+	return "UNIQUE"
+}
+
+func (f *kuneiformFormatter) VisitUniqueOutOfLineConstraint(p0 *UniqueOutOfLineConstraint) any {
+	return "UNIQUE (" + strings.Join(p0.Columns, ", ") + ")"
+}
+
+func (f *kuneiformFormatter) VisitDefaultConstraint(p0 *DefaultConstraint) any {
+	return "DEFAULT " + p0.Value.Accept(f).(string)
+}
+
+func (f *kuneiformFormatter) VisitNotNullConstraint(p0 *NotNullConstraint) any {
+	return "NOT NULL"
+}
+
+func (f *kuneiformFormatter) VisitCheckConstraint(p0 *CheckConstraint) any {
+	return "CHECK (" + p0.Expression.Accept(f).(string) + ")"
+}
+
+func (f *kuneiformFormatter) VisitForeignKeyReferences(p0 *ForeignKeyReferences) any {
+	var str strings.Builder
+	str.WriteString("REFERENCES ")
+	if p0.RefTableNamespace != "" {
+		str.WriteString(p0.RefTableNamespace + ".")
+	}
+	str.WriteString(p0.RefTable + "(" + strings.Join(p0.RefColumns, ", ") + ")")
+	for _, action := range p0.Actions {
+		str.WriteString(" ON " + string(action.On) + " " + string(action.Do))
+	}
+
+	return str.String()
+}
+
+func (f *kuneiformFormatter) VisitForeignKeyOutOfLineConstraint(p0 *ForeignKeyOutOfLineConstraint) any {
+	return "FOREIGN KEY (" + strings.Join(p0.Columns, ", ") + ") " + p0.References.Accept(f).(string)
+}
+
+func (f *kuneiformFormatter) VisitActionStmtDeclaration(p0 *ActionStmtDeclaration) any {
+	return p0.Variable.Accept(f).(string) + " " + p0.Type.String() + ";"
+}
+
+func (f *kuneiformFormatter) VisitActionStmtAssignment(p0 *ActionStmtAssign) any {
+	str := p0.Variable.Accept(f).(string)
+	if p0.Type != nil {
+		str += " " + p0.Type.String()
+	}
+	return str + " := " + p0.Value.Accept(f).(string) + ";"
+}
+
+func (f *kuneiformFormatter) VisitActionStmtCall(p0 *ActionStmtCall) any {
+	call := p0.Call.Accept(f).(string) + ";"
+	if len(p0.Receivers) == 0 {
+		return call
+	}
+
+	receivers := make([]string, len(p0.Receivers))
+	for i, r := range p0.Receivers {
+		if r == nil {
+			receivers[i] = "_"
+			continue
+		}
+		receivers[i] = r.Accept(f).(string)
+	}
+
+	return strings.Join(receivers, ", ") + " := " + call
+}
+
+func (f *kuneiformFormatter) VisitActionStmtForLoop(p0 *ActionStmtForLoop) any {
+	return "FOR " + p0.Receiver.Accept(f).(string) + " IN " + p0.LoopTerm.Accept(f).(string) + " " + f.block(p0.Body)
+}
+
+func (f *kuneiformFormatter) VisitLoopTermRange(p0 *LoopTermRange) any {
+	return p0.Start.Accept(f).(string) + ".." + p0.End.Accept(f).(string)
+}
+
+func (f *kuneiformFormatter) VisitLoopTermSQL(p0 *LoopTermSQL) any {
+	return f.inlined(p0.Statement)
+}
+
+func (f *kuneiformFormatter) VisitLoopTermExpression(p0 *LoopTermExpression) any {
+	if p0.Array {
+		return "ARRAY " + p0.Expression.Accept(f).(string)
+	}
+	return p0.Expression.Accept(f).(string)
+}
+
+func (f *kuneiformFormatter) VisitActionStmtIf(p0 *ActionStmtIf) any {
+	var str strings.Builder
+	for i, ifThen := range p0.IfThens {
+		if i > 0 {
+			str.WriteString(" ELSEIF ")
+		} else {
+			str.WriteString("IF ")
+		}
+		str.WriteString(ifThen.Accept(f).(string))
+	}
+	if len(p0.Else) > 0 {
+		str.WriteString(" ELSE " + f.block(p0.Else))
+	}
+
+	return str.String()
+}
+
+func (f *kuneiformFormatter) VisitIfThen(p0 *IfThen) any {
+	return p0.If.Accept(f).(string) + " " + f.block(p0.Then)
+}
+
+func (f *kuneiformFormatter) VisitActionStmtSQL(p0 *ActionStmtSQL) any {
+	return p0.SQL.Accept(f).(string) + ";"
+}
+
+func (f *kuneiformFormatter) VisitActionStmtLoopControl(p0 *ActionStmtLoopControl) any {
+	return string(p0.Type) + ";"
+}
+
+func (f *kuneiformFormatter) VisitActionStmtReturn(p0 *ActionStmtReturn) any {
+	switch {
+	case p0.SQL != nil:
+		return "RETURN " + p0.SQL.Accept(f).(string) + ";"
+	case len(p0.Values) > 0:
+		return "RETURN " + join(f, p0.Values, ", ") + ";"
+	default:
+		return "RETURN;"
+	}
+}
+
+func (f *kuneiformFormatter) VisitActionStmtReturnNext(p0 *ActionStmtReturnNext) any {
+	return "RETURN NEXT " + join(f, p0.Values, ", ") + ";"
+}
+
+func (f *kuneiformFormatter) VisitActionStmtDeclareCursor(p0 *ActionStmtDeclareCursor) any {
+	return "DECLARE " + p0.Name + " CURSOR FOR " + p0.Query.Accept(f).(string) + ";"
+}
+
+func (f *kuneiformFormatter) VisitActionStmtFetchCursor(p0 *ActionStmtFetchCursor) any {
+	str := "FETCH NEXT FROM " + p0.Name
+	if len(p0.Into) > 0 {
+		into := make([]string, len(p0.Into))
+		for i, v := range p0.Into {
+			if v == nil {
+				into[i] = "_"
+				continue
+			}
+			into[i] = v.Accept(f).(string)
+		}
+		str += " INTO " + strings.Join(into, ", ")
+	}
+	return str + ";"
+}
+
+func (f *kuneiformFormatter) VisitActionStmtCloseCursor(p0 *ActionStmtCloseCursor) any {
+	return "CLOSE " + p0.Name + ";"
+}
@@ -0,0 +1,176 @@
+package parse
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/kwilteam/kwil-db/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+// formatSample covers the statements and expressions that are not in the
+// sample schemas of the repository.
+const formatSample = `
+-- @soft_delete
+-- @optimizer_hint(enable_seqscan, false)
+create table if not exists items (
+	id int primary key,
+	-- @sensitive(partial(3))
+	name text not null default 'item',
+	price numeric(10, 2) check (price >= 0.50),
+	tags text[],
+	owner uuid references ns.owners(id) on delete set null,
+	constraint items_name unique (name, price),
+	foreign key (owner) references owners(id) on update cascade
+);
+
+{other}create unique index if not exists items_idx on items(name, price);
+create index concurrently items_owner on items(owner);
+alter table items add column if not exists note text, alter column note set default 'n',
+	alter column note drop not null, rename column note to comment, add constraint c check (id > 0),
+	drop constraint if exists c, drop column comment;
+alter table items rename to things;
+drop index if exists items_idx;
+drop table if exists a, b cascade;
+truncate table a cascade;
+create role if not exists writer;
+grant if not granted select, insert on main to writer;
+grant writer to '0xabc';
+revoke if granted writer from $user;
+drop role writer;
+use if not exists ext {key: 'value', other: 1 + 2} as e;
+unuse e if exists;
+create namespace if not exists ns;
+drop namespace ns;
+set current namespace to main;
+alter namespace main set read only;
+alter namespace main set read write;
+alter namespace main set config 'fee_rate' = '0.25';
+transfer ownership to '0xdef';
+drop action if exists old;
+
+-- @distinct_on(id)
+create or replace action list($from int, $to int) public view returns table(id int, total numeric(10, 2)) {
+	$total numeric(10,2) := 0.00;
+	$arr int[];
+	$arr[1] = -(-1);
+	$a, _ := other.fn($from::text, [1, 2], !$b = $c, not $b = $c);
+	for $i in $from..$to {
+		if $i % 2 == 0 { continue; } else if $i > 10 { break; } else { $total := $total + $i; }
+	}
+	for $v in array $arr {
+		return next $v, 1.50;
+	}
+	for $r in select id from items where name like 'a%' and id not in (1, 2) {
+		return next $r.id, null;
+	}
+	declare c cursor for select id, name from items order by id;
+	fetch next from c into $id, _;
+	fetch next from c;
+	close c;
+	with recursive r (n) as (select 1 union all select n + 1 from r where n < 10)
+	insert into items as i (id, name) select n, 'x' || n::text from r
+	on conflict (id) where id > 0 do update set name = excluded.name where i.id is not distinct from 1;
+	insert into items (id) values (1), (2) on conflict do nothing;
+	update items as i set price = price * 2 from owners o join tags t on t.id = o.id where i.owner = o.id;
+	delete from items where id between 1 and 10 or name is null;
+	return select distinct i.id, sum(price) filter (where price > 0) over w as total,
+		case when price > 1 then 'a' when price is null then 'b' else 'c' end,
+		rank() over (partition by owner order by price desc nulls last), count(distinct id), count(*),
+		exists (select 1 from items), tags[1:2], tags[:], name collate nocase
+	from items as i
+	left join (select * from owners) as o on o.id = i.owner
+	left join lateral (select * from owners where owners.id = i.owner) as l on true
+	join generate_series(1, 10) as g on g = i.id
+	where i.id > $from
+	group by i.id
+	having count(*) > 1
+	window w as (order by id)
+	order by i.id asc, total
+	limit 10 offset 5;
+};
+`
+
+func Test_Format(t *testing.T) {
+	samples := map[string]string{"sample": formatSample}
+	for _, file := range []string{
+		"../../../test/acceptance/users.sql",
+		"../../../testing/proxy/seed_1.sql",
+		"../../_exts/erc20-bridge/erc20/meta_schema.sql",
+	} {
+		bts, err := os.ReadFile(file)
+		require.NoError(t, err)
+		samples[file] = string(bts)
+	}
+
+	for name, sample := range samples {
+		t.Run(name, func(t *testing.T) {
+			stmts, err := Parse(sample)
+			require.NoError(t, err)
+
+			formatted, err := Format(stmts)
+			require.NoError(t, err)
+
+			// the formatted schema must parse to the same AST
+			reparsed, err := Parse(formatted)
+			require.NoError(t, err, formatted)
+			require.True(t, cmp.Equal(stmts, reparsed, formatCmpOpts()...), cmp.Diff(stmts, reparsed, formatCmpOpts()...))
+
+			// and formatting it again must not change it
+			again, err := Format(reparsed)
+			require.NoError(t, err)
+			require.Equal(t, formatted, again)
+		})
+	}
+}
+
+func Test_FormatLayout(t *testing.T) {
+	stmts, err := Parse(`create table t (id int primary key, name text);
+	-- not an annotation
+	create action get($id int) public view returns (text) {
+		if $id is null { error('no id'); }
+		for $row in select name from t where id = $id { return $row.name; }
+		return select name from t join u on t.id = u.id where id = $id and (select count(*) from u) > 0;
+	}`)
+	require.NoError(t, err)
+
+	formatted, err := Format(stmts)
+	require.NoError(t, err)
+	require.Equal(t, `CREATE TABLE t (
+	id int8 PRIMARY KEY,
+	name text
+);
+
+CREATE ACTION get($id int8) public view RETURNS (text) {
+	IF $id IS NULL {
+		error('no id');
+	}
+	FOR $row IN SELECT name FROM t WHERE id = $id {
+		RETURN $row.name;
+	}
+	RETURN SELECT name
+	FROM t
+	INNER JOIN u ON t.id = u.id
+	WHERE id = $id AND (SELECT count(*) FROM u) > 0;
+};
+`, formatted)
+}
+
+// formatCmpOpts compares ASTs, ignoring the raw text of statements. Since
+// privileges are case insensitive, and formatted in upper case, they are
+// compared without case.
+func formatCmpOpts() []cmp.Option {
+	return append(cmpOpts(),
+		cmp.FilterPath(func(p cmp.Path) bool {
+			return len(p) > 1 && p.Index(-2).String() == ".Privileges"
+		}, cmp.Comparer(strings.EqualFold)),
+		cmp.Comparer(func(x, y *types.Decimal) bool {
+			return x.FullString() == y.FullString()
+		}),
+		cmpopts.IgnoreFields(CreateActionStatement{}, "Raw"),
+		cmpopts.IgnoreFields(CreateTableStatement{}, "Raw"),
+	)
+}
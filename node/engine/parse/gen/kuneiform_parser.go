@@ -95,11 +95,11 @@ func kuneiformparserParserInit() {
 		"upsert_clause", "delete_statement", "sql_expr", "window", "when_then_clause",
 		"sql_expr_list", "sql_function_call", "action_expr", "action_expr_list",
 		"action_statement", "variable_or_underscore", "action_function_call",
-		"if_then_block", "range",
+		"if_then_block", "range", "alter_namespace_statement", "truncate_table_statement",
 	}
 	staticData.PredictionContextCache = antlr.NewPredictionContextCache()
 	staticData.serializedATN = []int32{
-		4, 1, 155, 1387, 2, 0, 7, 0, 2, 1, 7, 1, 2, 2, 7, 2, 2, 3, 7, 3, 2, 4,
+		4, 1, 155, 1460, 2, 0, 7, 0, 2, 1, 7, 1, 2, 2, 7, 2, 2, 3, 7, 3, 2, 4,
 		7, 4, 2, 5, 7, 5, 2, 6, 7, 6, 2, 7, 7, 7, 2, 8, 7, 8, 2, 9, 7, 9, 2, 10,
 		7, 10, 2, 11, 7, 11, 2, 12, 7, 12, 2, 13, 7, 13, 2, 14, 7, 14, 2, 15, 7,
 		15, 2, 16, 7, 16, 2, 17, 7, 17, 2, 18, 7, 18, 2, 19, 7, 19, 2, 20, 7, 20,
@@ -249,131 +249,139 @@ func kuneiformparserParserInit() {
 		60, 1, 60, 1, 61, 1, 61, 1, 61, 3, 61, 1364, 8, 61, 1, 61, 1, 61, 1, 61,
 		3, 61, 1369, 8, 61, 1, 61, 1, 61, 1, 62, 1, 62, 1, 62, 5, 62, 1376, 8,
 		62, 10, 62, 12, 62, 1379, 9, 62, 1, 62, 1, 62, 1, 63, 1, 63, 1, 63, 1,
-		63, 1, 63, 0, 2, 104, 114, 64, 0, 2, 4, 6, 8, 10, 12, 14, 16, 18, 20, 22,
-		24, 26, 28, 30, 32, 34, 36, 38, 40, 42, 44, 46, 48, 50, 52, 54, 56, 58,
-		60, 62, 64, 66, 68, 70, 72, 74, 76, 78, 80, 82, 84, 86, 88, 90, 92, 94,
-		96, 98, 100, 102, 104, 106, 108, 110, 112, 114, 116, 118, 120, 122, 124,
-		126, 0, 17, 1, 0, 20, 21, 1, 0, 138, 139, 13, 0, 34, 35, 37, 39, 41, 43,
-		46, 49, 52, 52, 54, 54, 56, 56, 63, 63, 87, 87, 112, 118, 125, 129, 131,
-		136, 148, 148, 1, 0, 149, 150, 1, 0, 58, 59, 1, 0, 53, 54, 6, 0, 34, 34,
-		38, 39, 42, 42, 58, 59, 98, 99, 135, 136, 1, 0, 79, 80, 1, 0, 106, 107,
-		2, 0, 75, 77, 101, 101, 3, 0, 14, 14, 19, 19, 22, 22, 1, 0, 66, 67, 2,
-		0, 15, 16, 24, 28, 2, 0, 11, 11, 20, 21, 2, 0, 15, 15, 31, 31, 1, 0, 116,
-		117, 2, 0, 30, 30, 149, 149, 1605, 0, 128, 1, 0, 0, 0, 2, 145, 1, 0, 0,
-		0, 4, 181, 1, 0, 0, 0, 6, 188, 1, 0, 0, 0, 8, 190, 1, 0, 0, 0, 10, 192,
-		1, 0, 0, 0, 12, 200, 1, 0, 0, 0, 14, 214, 1, 0, 0, 0, 16, 217, 1, 0, 0,
-		0, 18, 219, 1, 0, 0, 0, 20, 227, 1, 0, 0, 0, 22, 235, 1, 0, 0, 0, 24, 259,
-		1, 0, 0, 0, 26, 261, 1, 0, 0, 0, 28, 273, 1, 0, 0, 0, 30, 289, 1, 0, 0,
-		0, 32, 315, 1, 0, 0, 0, 34, 323, 1, 0, 0, 0, 36, 343, 1, 0, 0, 0, 38, 370,
-		1, 0, 0, 0, 40, 397, 1, 0, 0, 0, 42, 399, 1, 0, 0, 0, 44, 409, 1, 0, 0,
-		0, 46, 474, 1, 0, 0, 0, 48, 476, 1, 0, 0, 0, 50, 495, 1, 0, 0, 0, 52, 503,
-		1, 0, 0, 0, 54, 512, 1, 0, 0, 0, 56, 520, 1, 0, 0, 0, 58, 540, 1, 0, 0,
-		0, 60, 559, 1, 0, 0, 0, 62, 566, 1, 0, 0, 0, 64, 574, 1, 0, 0, 0, 66, 576,
-		1, 0, 0, 0, 68, 620, 1, 0, 0, 0, 70, 628, 1, 0, 0, 0, 72, 657, 1, 0, 0,
-		0, 74, 663, 1, 0, 0, 0, 76, 672, 1, 0, 0, 0, 78, 680, 1, 0, 0, 0, 80, 686,
-		1, 0, 0, 0, 82, 721, 1, 0, 0, 0, 84, 723, 1, 0, 0, 0, 86, 731, 1, 0, 0,
-		0, 88, 803, 1, 0, 0, 0, 90, 806, 1, 0, 0, 0, 92, 826, 1, 0, 0, 0, 94, 828,
-		1, 0, 0, 0, 96, 859, 1, 0, 0, 0, 98, 863, 1, 0, 0, 0, 100, 898, 1, 0, 0,
-		0, 102, 927, 1, 0, 0, 0, 104, 1022, 1, 0, 0, 0, 106, 1115, 1, 0, 0, 0,
-		108, 1135, 1, 0, 0, 0, 110, 1140, 1, 0, 0, 0, 112, 1148, 1, 0, 0, 0, 114,
-		1193, 1, 0, 0, 0, 116, 1256, 1, 0, 0, 0, 118, 1356, 1, 0, 0, 0, 120, 1358,
-		1, 0, 0, 0, 122, 1363, 1, 0, 0, 0, 124, 1372, 1, 0, 0, 0, 126, 1382, 1,
-		0, 0, 0, 128, 133, 3, 2, 1, 0, 129, 130, 5, 6, 0, 0, 130, 132, 3, 2, 1,
-		0, 131, 129, 1, 0, 0, 0, 132, 135, 1, 0, 0, 0, 133, 131, 1, 0, 0, 0, 133,
-		134, 1, 0, 0, 0, 134, 137, 1, 0, 0, 0, 135, 133, 1, 0, 0, 0, 136, 138,
-		5, 6, 0, 0, 137, 136, 1, 0, 0, 0, 137, 138, 1, 0, 0, 0, 138, 139, 1, 0,
-		0, 0, 139, 140, 5, 0, 0, 1, 140, 1, 1, 0, 0, 0, 141, 142, 5, 1, 0, 0, 142,
-		143, 3, 6, 3, 0, 143, 144, 5, 2, 0, 0, 144, 146, 1, 0, 0, 0, 145, 141,
-		1, 0, 0, 0, 145, 146, 1, 0, 0, 0, 146, 165, 1, 0, 0, 0, 147, 166, 3, 32,
-		16, 0, 148, 166, 3, 36, 18, 0, 149, 166, 3, 44, 22, 0, 150, 166, 3, 42,
-		21, 0, 151, 166, 3, 48, 24, 0, 152, 166, 3, 50, 25, 0, 153, 166, 3, 52,
-		26, 0, 154, 166, 3, 54, 27, 0, 155, 166, 3, 56, 28, 0, 156, 166, 3, 58,
-		29, 0, 157, 166, 3, 60, 30, 0, 158, 166, 3, 66, 33, 0, 159, 166, 3, 68,
-		34, 0, 160, 166, 3, 70, 35, 0, 161, 166, 3, 72, 36, 0, 162, 166, 3, 74,
-		37, 0, 163, 166, 3, 76, 38, 0, 164, 166, 3, 78, 39, 0, 165, 147, 1, 0,
-		0, 0, 165, 148, 1, 0, 0, 0, 165, 149, 1, 0, 0, 0, 165, 150, 1, 0, 0, 0,
-		165, 151, 1, 0, 0, 0, 165, 152, 1, 0, 0, 0, 165, 153, 1, 0, 0, 0, 165,
-		154, 1, 0, 0, 0, 165, 155, 1, 0, 0, 0, 165, 156, 1, 0, 0, 0, 165, 157,
-		1, 0, 0, 0, 165, 158, 1, 0, 0, 0, 165, 159, 1, 0, 0, 0, 165, 160, 1, 0,
-		0, 0, 165, 161, 1, 0, 0, 0, 165, 162, 1, 0, 0, 0, 165, 163, 1, 0, 0, 0,
-		165, 164, 1, 0, 0, 0, 166, 3, 1, 0, 0, 0, 167, 182, 5, 137, 0, 0, 168,
-		170, 7, 0, 0, 0, 169, 168, 1, 0, 0, 0, 169, 170, 1, 0, 0, 0, 170, 171,
-		1, 0, 0, 0, 171, 182, 5, 140, 0, 0, 172, 174, 7, 0, 0, 0, 173, 172, 1,
-		0, 0, 0, 173, 174, 1, 0, 0, 0, 174, 175, 1, 0, 0, 0, 175, 176, 5, 140,
-		0, 0, 176, 177, 5, 12, 0, 0, 177, 182, 5, 140, 0, 0, 178, 182, 7, 1, 0,
-		0, 179, 182, 5, 57, 0, 0, 180, 182, 5, 141, 0, 0, 181, 167, 1, 0, 0, 0,
-		181, 169, 1, 0, 0, 0, 181, 173, 1, 0, 0, 0, 181, 178, 1, 0, 0, 0, 181,
-		179, 1, 0, 0, 0, 181, 180, 1, 0, 0, 0, 182, 5, 1, 0, 0, 0, 183, 184, 5,
-		33, 0, 0, 184, 185, 3, 8, 4, 0, 185, 186, 5, 33, 0, 0, 186, 189, 1, 0,
-		0, 0, 187, 189, 3, 8, 4, 0, 188, 183, 1, 0, 0, 0, 188, 187, 1, 0, 0, 0,
-		189, 7, 1, 0, 0, 0, 190, 191, 7, 2, 0, 0, 191, 9, 1, 0, 0, 0, 192, 197,
-		3, 6, 3, 0, 193, 194, 5, 9, 0, 0, 194, 196, 3, 6, 3, 0, 195, 193, 1, 0,
-		0, 0, 196, 199, 1, 0, 0, 0, 197, 195, 1, 0, 0, 0, 197, 198, 1, 0, 0, 0,
-		198, 11, 1, 0, 0, 0, 199, 197, 1, 0, 0, 0, 200, 208, 3, 6, 3, 0, 201, 202,
-		5, 7, 0, 0, 202, 205, 5, 140, 0, 0, 203, 204, 5, 9, 0, 0, 204, 206, 5,
-		140, 0, 0, 205, 203, 1, 0, 0, 0, 205, 206, 1, 0, 0, 0, 206, 207, 1, 0,
-		0, 0, 207, 209, 5, 8, 0, 0, 208, 201, 1, 0, 0, 0, 208, 209, 1, 0, 0, 0,
-		209, 212, 1, 0, 0, 0, 210, 211, 5, 3, 0, 0, 211, 213, 5, 4, 0, 0, 212,
-		210, 1, 0, 0, 0, 212, 213, 1, 0, 0, 0, 213, 13, 1, 0, 0, 0, 214, 215, 5,
-		29, 0, 0, 215, 216, 3, 12, 6, 0, 216, 15, 1, 0, 0, 0, 217, 218, 7, 3, 0,
-		0, 218, 17, 1, 0, 0, 0, 219, 220, 3, 6, 3, 0, 220, 224, 3, 12, 6, 0, 221,
-		223, 3, 24, 12, 0, 222, 221, 1, 0, 0, 0, 223, 226, 1, 0, 0, 0, 224, 222,
-		1, 0, 0, 0, 224, 225, 1, 0, 0, 0, 225, 19, 1, 0, 0, 0, 226, 224, 1, 0,
-		0, 0, 227, 232, 3, 12, 6, 0, 228, 229, 5, 9, 0, 0, 229, 231, 3, 12, 6,
-		0, 230, 228, 1, 0, 0, 0, 231, 234, 1, 0, 0, 0, 232, 230, 1, 0, 0, 0, 232,
-		233, 1, 0, 0, 0, 233, 21, 1, 0, 0, 0, 234, 232, 1, 0, 0, 0, 235, 236, 3,
-		6, 3, 0, 236, 243, 3, 12, 6, 0, 237, 238, 5, 9, 0, 0, 238, 239, 3, 6, 3,
-		0, 239, 240, 3, 12, 6, 0, 240, 242, 1, 0, 0, 0, 241, 237, 1, 0, 0, 0, 242,
-		245, 1, 0, 0, 0, 243, 241, 1, 0, 0, 0, 243, 244, 1, 0, 0, 0, 244, 23, 1,
-		0, 0, 0, 245, 243, 1, 0, 0, 0, 246, 247, 5, 48, 0, 0, 247, 260, 5, 49,
-		0, 0, 248, 260, 5, 52, 0, 0, 249, 250, 5, 62, 0, 0, 250, 260, 5, 57, 0,
-		0, 251, 252, 5, 56, 0, 0, 252, 260, 3, 114, 57, 0, 253, 260, 3, 28, 14,
-		0, 254, 255, 5, 46, 0, 0, 255, 256, 5, 7, 0, 0, 256, 257, 3, 104, 52, 0,
-		257, 258, 5, 8, 0, 0, 258, 260, 1, 0, 0, 0, 259, 246, 1, 0, 0, 0, 259,
-		248, 1, 0, 0, 0, 259, 249, 1, 0, 0, 0, 259, 251, 1, 0, 0, 0, 259, 253,
-		1, 0, 0, 0, 259, 254, 1, 0, 0, 0, 260, 25, 1, 0, 0, 0, 261, 262, 5, 50,
-		0, 0, 262, 271, 7, 4, 0, 0, 263, 264, 5, 55, 0, 0, 264, 272, 5, 57, 0,
-		0, 265, 266, 5, 55, 0, 0, 266, 272, 5, 56, 0, 0, 267, 272, 5, 54, 0, 0,
-		268, 269, 5, 88, 0, 0, 269, 272, 5, 37, 0, 0, 270, 272, 5, 53, 0, 0, 271,
-		263, 1, 0, 0, 0, 271, 265, 1, 0, 0, 0, 271, 267, 1, 0, 0, 0, 271, 268,
-		1, 0, 0, 0, 271, 270, 1, 0, 0, 0, 272, 27, 1, 0, 0, 0, 273, 277, 5, 60,
-		0, 0, 274, 275, 3, 6, 3, 0, 275, 276, 5, 12, 0, 0, 276, 278, 1, 0, 0, 0,
-		277, 274, 1, 0, 0, 0, 277, 278, 1, 0, 0, 0, 278, 279, 1, 0, 0, 0, 279,
-		280, 3, 6, 3, 0, 280, 281, 5, 7, 0, 0, 281, 282, 3, 10, 5, 0, 282, 287,
-		5, 8, 0, 0, 283, 285, 3, 26, 13, 0, 284, 286, 3, 26, 13, 0, 285, 284, 1,
-		0, 0, 0, 285, 286, 1, 0, 0, 0, 286, 288, 1, 0, 0, 0, 287, 283, 1, 0, 0,
-		0, 287, 288, 1, 0, 0, 0, 288, 29, 1, 0, 0, 0, 289, 301, 5, 87, 0, 0, 290,
-		292, 5, 36, 0, 0, 291, 290, 1, 0, 0, 0, 291, 292, 1, 0, 0, 0, 292, 293,
-		1, 0, 0, 0, 293, 294, 5, 7, 0, 0, 294, 295, 3, 22, 11, 0, 295, 296, 5,
-		8, 0, 0, 296, 302, 1, 0, 0, 0, 297, 298, 5, 7, 0, 0, 298, 299, 3, 20, 10,
-		0, 299, 300, 5, 8, 0, 0, 300, 302, 1, 0, 0, 0, 301, 291, 1, 0, 0, 0, 301,
-		297, 1, 0, 0, 0, 302, 31, 1, 0, 0, 0, 303, 305, 5, 89, 0, 0, 304, 306,
-		5, 124, 0, 0, 305, 304, 1, 0, 0, 0, 305, 306, 1, 0, 0, 0, 306, 307, 1,
-		0, 0, 0, 307, 312, 3, 34, 17, 0, 308, 309, 5, 9, 0, 0, 309, 311, 3, 34,
-		17, 0, 310, 308, 1, 0, 0, 0, 311, 314, 1, 0, 0, 0, 312, 310, 1, 0, 0, 0,
-		312, 313, 1, 0, 0, 0, 313, 316, 1, 0, 0, 0, 314, 312, 1, 0, 0, 0, 315,
-		303, 1, 0, 0, 0, 315, 316, 1, 0, 0, 0, 316, 321, 1, 0, 0, 0, 317, 322,
-		3, 80, 40, 0, 318, 322, 3, 94, 47, 0, 319, 322, 3, 98, 49, 0, 320, 322,
-		3, 102, 51, 0, 321, 317, 1, 0, 0, 0, 321, 318, 1, 0, 0, 0, 321, 319, 1,
-		0, 0, 0, 321, 320, 1, 0, 0, 0, 322, 33, 1, 0, 0, 0, 323, 336, 3, 6, 3,
-		0, 324, 333, 5, 7, 0, 0, 325, 330, 3, 6, 3, 0, 326, 327, 5, 9, 0, 0, 327,
-		329, 3, 6, 3, 0, 328, 326, 1, 0, 0, 0, 329, 332, 1, 0, 0, 0, 330, 328,
-		1, 0, 0, 0, 330, 331, 1, 0, 0, 0, 331, 334, 1, 0, 0, 0, 332, 330, 1, 0,
-		0, 0, 333, 325, 1, 0, 0, 0, 333, 334, 1, 0, 0, 0, 334, 335, 1, 0, 0, 0,
-		335, 337, 5, 8, 0, 0, 336, 324, 1, 0, 0, 0, 336, 337, 1, 0, 0, 0, 337,
-		338, 1, 0, 0, 0, 338, 339, 5, 78, 0, 0, 339, 340, 5, 7, 0, 0, 340, 341,
-		3, 80, 40, 0, 341, 342, 5, 8, 0, 0, 342, 35, 1, 0, 0, 0, 343, 344, 5, 38,
-		0, 0, 344, 348, 5, 36, 0, 0, 345, 346, 5, 113, 0, 0, 346, 347, 5, 62, 0,
-		0, 347, 349, 5, 71, 0, 0, 348, 345, 1, 0, 0, 0, 348, 349, 1, 0, 0, 0, 349,
-		350, 1, 0, 0, 0, 350, 351, 3, 6, 3, 0, 351, 354, 5, 7, 0, 0, 352, 355,
-		3, 18, 9, 0, 353, 355, 3, 38, 19, 0, 354, 352, 1, 0, 0, 0, 354, 353, 1,
-		0, 0, 0, 355, 363, 1, 0, 0, 0, 356, 359, 5, 9, 0, 0, 357, 360, 3, 18, 9,
-		0, 358, 360, 3, 38, 19, 0, 359, 357, 1, 0, 0, 0, 359, 358, 1, 0, 0, 0,
-		360, 362, 1, 0, 0, 0, 361, 356, 1, 0, 0, 0, 362, 365, 1, 0, 0, 0, 363,
-		361, 1, 0, 0, 0, 363, 364, 1, 0, 0, 0, 364, 366, 1, 0, 0, 0, 365, 363,
-		1, 0, 0, 0, 366, 367, 5, 8, 0, 0, 367, 37, 1, 0, 0, 0, 368, 369, 5, 45,
-		0, 0, 369, 371, 3, 6, 3, 0, 370, 368, 1, 0, 0, 0, 370, 371, 1, 0, 0, 0,
-		371, 395, 1, 0, 0, 0, 372, 373, 5, 52, 0, 0, 373, 374, 5, 7, 0, 0, 374,
+		63, 1, 63, 1, 44, 1, 44, 3, 44, 1392, 1, 44, 1, 44, 8, 44, 1, 44, 3, 44,
+		1399, 3, 44, 1397, 1, 44, 8, 44, 1, 44, 8, 44, 1, 59, 1, 59, 1, 59, 1,
+		59, 1, 59, 1, 59, 1, 59, 1, 59, 1, 59, 1, 59, 1, 59, 1, 59, 1, 59, 1, 59,
+		1, 59, 5, 59, 1416, 8, 59, 10, 59, 12, 59, 1419, 9, 59, 3, 59, 1421, 8,
+		59, 1, 59, 1, 59, 1, 59, 1, 59, 1, 59, 1, 59, 2, 64, 7, 64, 1, 64, 1, 64,
+		1, 64, 1, 64, 1, 64, 1, 64, 1, 64, 1, 1, 8, 44, 3, 44, 1438, 1, 44, 2,
+		65, 7, 65, 1, 65, 1, 65, 1, 65, 1, 65, 8, 65, 3, 65, 1447, 1, 65, 1, 1,
+		8, 24, 3, 24, 1451, 1, 24, 8, 64, 3, 64, 1454, 1, 64, 1, 64, 1, 64, 1,
+		64, 0, 2, 104, 114, 66, 0, 2, 4, 6, 8, 10, 12, 14, 16, 18, 20, 22, 24,
+		26, 28, 30, 32, 34, 36, 38, 40, 42, 44, 46, 48, 50, 52, 54, 56, 58, 60,
+		62, 64, 66, 68, 70, 72, 74, 76, 78, 80, 82, 84, 86, 88, 90, 92, 94, 96,
+		98, 100, 102, 104, 106, 108, 110, 112, 114, 116, 118, 120, 122, 124, 126,
+		1428, 1441, 0, 17, 1, 0, 20, 21, 1, 0, 138, 139, 13, 0, 34, 35, 37, 39,
+		41, 43, 46, 49, 52, 52, 54, 54, 56, 56, 63, 63, 87, 87, 112, 118, 125,
+		129, 131, 136, 148, 148, 1, 0, 149, 150, 1, 0, 58, 59, 1, 0, 53, 54, 6,
+		0, 34, 34, 38, 39, 42, 42, 58, 59, 98, 99, 135, 136, 1, 0, 79, 80, 1, 0,
+		106, 107, 2, 0, 75, 77, 101, 101, 3, 0, 14, 14, 19, 19, 22, 22, 1, 0, 66,
+		67, 2, 0, 15, 16, 24, 28, 2, 0, 11, 11, 20, 21, 2, 0, 15, 15, 31, 31, 1,
+		0, 116, 117, 2, 0, 30, 30, 149, 149, 1691, 0, 128, 1, 0, 0, 0, 2, 145,
+		1, 0, 0, 0, 4, 181, 1, 0, 0, 0, 6, 188, 1, 0, 0, 0, 8, 190, 1, 0, 0, 0,
+		10, 192, 1, 0, 0, 0, 12, 200, 1, 0, 0, 0, 14, 214, 1, 0, 0, 0, 16, 217,
+		1, 0, 0, 0, 18, 219, 1, 0, 0, 0, 20, 227, 1, 0, 0, 0, 22, 235, 1, 0, 0,
+		0, 24, 259, 1, 0, 0, 0, 26, 261, 1, 0, 0, 0, 28, 273, 1, 0, 0, 0, 30, 289,
+		1, 0, 0, 0, 32, 315, 1, 0, 0, 0, 34, 323, 1, 0, 0, 0, 36, 343, 1, 0, 0,
+		0, 38, 370, 1, 0, 0, 0, 40, 397, 1, 0, 0, 0, 42, 399, 1, 0, 0, 0, 44, 409,
+		1, 0, 0, 0, 46, 474, 1, 0, 0, 0, 48, 476, 1, 0, 0, 0, 50, 495, 1, 0, 0,
+		0, 52, 503, 1, 0, 0, 0, 54, 512, 1, 0, 0, 0, 56, 520, 1, 0, 0, 0, 58, 540,
+		1, 0, 0, 0, 60, 559, 1, 0, 0, 0, 62, 566, 1, 0, 0, 0, 64, 574, 1, 0, 0,
+		0, 66, 576, 1, 0, 0, 0, 68, 620, 1, 0, 0, 0, 70, 628, 1, 0, 0, 0, 72, 657,
+		1, 0, 0, 0, 74, 663, 1, 0, 0, 0, 76, 672, 1, 0, 0, 0, 78, 680, 1, 0, 0,
+		0, 80, 686, 1, 0, 0, 0, 82, 721, 1, 0, 0, 0, 84, 723, 1, 0, 0, 0, 86, 731,
+		1, 0, 0, 0, 88, 803, 1, 0, 0, 0, 90, 806, 1, 0, 0, 0, 92, 826, 1, 0, 0,
+		0, 94, 828, 1, 0, 0, 0, 96, 859, 1, 0, 0, 0, 98, 863, 1, 0, 0, 0, 100,
+		898, 1, 0, 0, 0, 102, 927, 1, 0, 0, 0, 104, 1022, 1, 0, 0, 0, 106, 1115,
+		1, 0, 0, 0, 108, 1135, 1, 0, 0, 0, 110, 1140, 1, 0, 0, 0, 112, 1148, 1,
+		0, 0, 0, 114, 1193, 1, 0, 0, 0, 116, 1256, 1, 0, 0, 0, 118, 1356, 1, 0,
+		0, 0, 120, 1358, 1, 0, 0, 0, 122, 1363, 1, 0, 0, 0, 124, 1372, 1, 0, 0,
+		0, 126, 1382, 1, 0, 0, 0, 128, 133, 3, 2, 1, 0, 129, 130, 5, 6, 0, 0, 130,
+		132, 3, 2, 1, 0, 131, 129, 1, 0, 0, 0, 132, 135, 1, 0, 0, 0, 133, 131,
+		1, 0, 0, 0, 133, 134, 1, 0, 0, 0, 134, 137, 1, 0, 0, 0, 135, 133, 1, 0,
+		0, 0, 136, 138, 5, 6, 0, 0, 137, 136, 1, 0, 0, 0, 137, 138, 1, 0, 0, 0,
+		138, 139, 1, 0, 0, 0, 139, 140, 5, 0, 0, 1, 140, 1, 1, 0, 0, 0, 141, 142,
+		5, 1, 0, 0, 142, 143, 3, 6, 3, 0, 143, 144, 5, 2, 0, 0, 144, 146, 1, 0,
+		0, 0, 145, 141, 1, 0, 0, 0, 145, 146, 1, 0, 0, 0, 146, 165, 1, 0, 0, 0,
+		147, 166, 3, 32, 16, 0, 148, 166, 3, 36, 18, 0, 149, 166, 3, 44, 22, 0,
+		150, 166, 3, 42, 21, 0, 151, 166, 3, 48, 24, 0, 152, 166, 3, 50, 25, 0,
+		153, 166, 3, 52, 26, 0, 154, 166, 3, 54, 27, 0, 155, 166, 3, 56, 28, 0,
+		156, 166, 3, 58, 29, 0, 157, 166, 3, 60, 30, 0, 158, 166, 3, 66, 33, 0,
+		159, 166, 3, 68, 34, 0, 160, 166, 3, 70, 35, 0, 161, 166, 3, 72, 36, 0,
+		162, 166, 3, 74, 37, 0, 163, 166, 3, 76, 38, 0, 164, 166, 3, 78, 39, 0,
+		165, 147, 1, 0, 0, 0, 165, 148, 1, 0, 0, 0, 165, 149, 1, 0, 0, 0, 165,
+		150, 1, 0, 0, 0, 165, 151, 1, 0, 0, 0, 165, 152, 1, 0, 0, 0, 165, 153,
+		1, 0, 0, 0, 165, 154, 1, 0, 0, 0, 165, 155, 1, 0, 0, 0, 165, 156, 1, 0,
+		0, 0, 165, 157, 1, 0, 0, 0, 165, 158, 1, 0, 0, 0, 165, 159, 1, 0, 0, 0,
+		165, 160, 1, 0, 0, 0, 165, 161, 1, 0, 0, 0, 165, 162, 1, 0, 0, 0, 165,
+		163, 1, 0, 0, 0, 165, 164, 1, 0, 0, 0, 166, 3, 1, 0, 0, 0, 167, 182, 5,
+		137, 0, 0, 168, 170, 7, 0, 0, 0, 169, 168, 1, 0, 0, 0, 169, 170, 1, 0,
+		0, 0, 170, 171, 1, 0, 0, 0, 171, 182, 5, 140, 0, 0, 172, 174, 7, 0, 0,
+		0, 173, 172, 1, 0, 0, 0, 173, 174, 1, 0, 0, 0, 174, 175, 1, 0, 0, 0, 175,
+		176, 5, 140, 0, 0, 176, 177, 5, 12, 0, 0, 177, 182, 5, 140, 0, 0, 178,
+		182, 7, 1, 0, 0, 179, 182, 5, 57, 0, 0, 180, 182, 5, 141, 0, 0, 181, 167,
+		1, 0, 0, 0, 181, 169, 1, 0, 0, 0, 181, 173, 1, 0, 0, 0, 181, 178, 1, 0,
+		0, 0, 181, 179, 1, 0, 0, 0, 181, 180, 1, 0, 0, 0, 182, 5, 1, 0, 0, 0, 183,
+		184, 5, 33, 0, 0, 184, 185, 3, 8, 4, 0, 185, 186, 5, 33, 0, 0, 186, 189,
+		1, 0, 0, 0, 187, 189, 3, 8, 4, 0, 188, 183, 1, 0, 0, 0, 188, 187, 1, 0,
+		0, 0, 189, 7, 1, 0, 0, 0, 190, 191, 7, 2, 0, 0, 191, 9, 1, 0, 0, 0, 192,
+		197, 3, 6, 3, 0, 193, 194, 5, 9, 0, 0, 194, 196, 3, 6, 3, 0, 195, 193,
+		1, 0, 0, 0, 196, 199, 1, 0, 0, 0, 197, 195, 1, 0, 0, 0, 197, 198, 1, 0,
+		0, 0, 198, 11, 1, 0, 0, 0, 199, 197, 1, 0, 0, 0, 200, 208, 3, 6, 3, 0,
+		201, 202, 5, 7, 0, 0, 202, 205, 5, 140, 0, 0, 203, 204, 5, 9, 0, 0, 204,
+		206, 5, 140, 0, 0, 205, 203, 1, 0, 0, 0, 205, 206, 1, 0, 0, 0, 206, 207,
+		1, 0, 0, 0, 207, 209, 5, 8, 0, 0, 208, 201, 1, 0, 0, 0, 208, 209, 1, 0,
+		0, 0, 209, 212, 1, 0, 0, 0, 210, 211, 5, 3, 0, 0, 211, 213, 5, 4, 0, 0,
+		212, 210, 1, 0, 0, 0, 212, 213, 1, 0, 0, 0, 213, 13, 1, 0, 0, 0, 214, 215,
+		5, 29, 0, 0, 215, 216, 3, 12, 6, 0, 216, 15, 1, 0, 0, 0, 217, 218, 7, 3,
+		0, 0, 218, 17, 1, 0, 0, 0, 219, 220, 3, 6, 3, 0, 220, 224, 3, 12, 6, 0,
+		221, 223, 3, 24, 12, 0, 222, 221, 1, 0, 0, 0, 223, 226, 1, 0, 0, 0, 224,
+		222, 1, 0, 0, 0, 224, 225, 1, 0, 0, 0, 225, 19, 1, 0, 0, 0, 226, 224, 1,
+		0, 0, 0, 227, 232, 3, 12, 6, 0, 228, 229, 5, 9, 0, 0, 229, 231, 3, 12,
+		6, 0, 230, 228, 1, 0, 0, 0, 231, 234, 1, 0, 0, 0, 232, 230, 1, 0, 0, 0,
+		232, 233, 1, 0, 0, 0, 233, 21, 1, 0, 0, 0, 234, 232, 1, 0, 0, 0, 235, 236,
+		3, 6, 3, 0, 236, 243, 3, 12, 6, 0, 237, 238, 5, 9, 0, 0, 238, 239, 3, 6,
+		3, 0, 239, 240, 3, 12, 6, 0, 240, 242, 1, 0, 0, 0, 241, 237, 1, 0, 0, 0,
+		242, 245, 1, 0, 0, 0, 243, 241, 1, 0, 0, 0, 243, 244, 1, 0, 0, 0, 244,
+		23, 1, 0, 0, 0, 245, 243, 1, 0, 0, 0, 246, 247, 5, 48, 0, 0, 247, 260,
+		5, 49, 0, 0, 248, 260, 5, 52, 0, 0, 249, 250, 5, 62, 0, 0, 250, 260, 5,
+		57, 0, 0, 251, 252, 5, 56, 0, 0, 252, 260, 3, 114, 57, 0, 253, 260, 3,
+		28, 14, 0, 254, 255, 5, 46, 0, 0, 255, 256, 5, 7, 0, 0, 256, 257, 3, 104,
+		52, 0, 257, 258, 5, 8, 0, 0, 258, 260, 1, 0, 0, 0, 259, 246, 1, 0, 0, 0,
+		259, 248, 1, 0, 0, 0, 259, 249, 1, 0, 0, 0, 259, 251, 1, 0, 0, 0, 259,
+		253, 1, 0, 0, 0, 259, 254, 1, 0, 0, 0, 260, 25, 1, 0, 0, 0, 261, 262, 5,
+		50, 0, 0, 262, 271, 7, 4, 0, 0, 263, 264, 5, 55, 0, 0, 264, 272, 5, 57,
+		0, 0, 265, 266, 5, 55, 0, 0, 266, 272, 5, 56, 0, 0, 267, 272, 5, 54, 0,
+		0, 268, 269, 5, 88, 0, 0, 269, 272, 5, 37, 0, 0, 270, 272, 5, 53, 0, 0,
+		271, 263, 1, 0, 0, 0, 271, 265, 1, 0, 0, 0, 271, 267, 1, 0, 0, 0, 271,
+		268, 1, 0, 0, 0, 271, 270, 1, 0, 0, 0, 272, 27, 1, 0, 0, 0, 273, 277, 5,
+		60, 0, 0, 274, 275, 3, 6, 3, 0, 275, 276, 5, 12, 0, 0, 276, 278, 1, 0,
+		0, 0, 277, 274, 1, 0, 0, 0, 277, 278, 1, 0, 0, 0, 278, 279, 1, 0, 0, 0,
+		279, 280, 3, 6, 3, 0, 280, 281, 5, 7, 0, 0, 281, 282, 3, 10, 5, 0, 282,
+		287, 5, 8, 0, 0, 283, 285, 3, 26, 13, 0, 284, 286, 3, 26, 13, 0, 285, 284,
+		1, 0, 0, 0, 285, 286, 1, 0, 0, 0, 286, 288, 1, 0, 0, 0, 287, 283, 1, 0,
+		0, 0, 287, 288, 1, 0, 0, 0, 288, 29, 1, 0, 0, 0, 289, 301, 5, 87, 0, 0,
+		290, 292, 5, 36, 0, 0, 291, 290, 1, 0, 0, 0, 291, 292, 1, 0, 0, 0, 292,
+		293, 1, 0, 0, 0, 293, 294, 5, 7, 0, 0, 294, 295, 3, 22, 11, 0, 295, 296,
+		5, 8, 0, 0, 296, 302, 1, 0, 0, 0, 297, 298, 5, 7, 0, 0, 298, 299, 3, 20,
+		10, 0, 299, 300, 5, 8, 0, 0, 300, 302, 1, 0, 0, 0, 301, 291, 1, 0, 0, 0,
+		301, 297, 1, 0, 0, 0, 302, 31, 1, 0, 0, 0, 303, 305, 5, 89, 0, 0, 304,
+		306, 5, 124, 0, 0, 305, 304, 1, 0, 0, 0, 305, 306, 1, 0, 0, 0, 306, 307,
+		1, 0, 0, 0, 307, 312, 3, 34, 17, 0, 308, 309, 5, 9, 0, 0, 309, 311, 3,
+		34, 17, 0, 310, 308, 1, 0, 0, 0, 311, 314, 1, 0, 0, 0, 312, 310, 1, 0,
+		0, 0, 312, 313, 1, 0, 0, 0, 313, 316, 1, 0, 0, 0, 314, 312, 1, 0, 0, 0,
+		315, 303, 1, 0, 0, 0, 315, 316, 1, 0, 0, 0, 316, 321, 1, 0, 0, 0, 317,
+		322, 3, 80, 40, 0, 318, 322, 3, 94, 47, 0, 319, 322, 3, 98, 49, 0, 320,
+		322, 3, 102, 51, 0, 321, 317, 1, 0, 0, 0, 321, 318, 1, 0, 0, 0, 321, 319,
+		1, 0, 0, 0, 321, 320, 1, 0, 0, 0, 322, 33, 1, 0, 0, 0, 323, 336, 3, 6,
+		3, 0, 324, 333, 5, 7, 0, 0, 325, 330, 3, 6, 3, 0, 326, 327, 5, 9, 0, 0,
+		327, 329, 3, 6, 3, 0, 328, 326, 1, 0, 0, 0, 329, 332, 1, 0, 0, 0, 330,
+		328, 1, 0, 0, 0, 330, 331, 1, 0, 0, 0, 331, 334, 1, 0, 0, 0, 332, 330,
+		1, 0, 0, 0, 333, 325, 1, 0, 0, 0, 333, 334, 1, 0, 0, 0, 334, 335, 1, 0,
+		0, 0, 335, 337, 5, 8, 0, 0, 336, 324, 1, 0, 0, 0, 336, 337, 1, 0, 0, 0,
+		337, 338, 1, 0, 0, 0, 338, 339, 5, 78, 0, 0, 339, 340, 5, 7, 0, 0, 340,
+		341, 3, 80, 40, 0, 341, 342, 5, 8, 0, 0, 342, 35, 1, 0, 0, 0, 343, 344,
+		5, 38, 0, 0, 344, 348, 5, 36, 0, 0, 345, 346, 5, 113, 0, 0, 346, 347, 5,
+		62, 0, 0, 347, 349, 5, 71, 0, 0, 348, 345, 1, 0, 0, 0, 348, 349, 1, 0,
+		0, 0, 349, 350, 1, 0, 0, 0, 350, 351, 3, 6, 3, 0, 351, 354, 5, 7, 0, 0,
+		352, 355, 3, 18, 9, 0, 353, 355, 3, 38, 19, 0, 354, 352, 1, 0, 0, 0, 354,
+		353, 1, 0, 0, 0, 355, 363, 1, 0, 0, 0, 356, 359, 5, 9, 0, 0, 357, 360,
+		3, 18, 9, 0, 358, 360, 3, 38, 19, 0, 359, 357, 1, 0, 0, 0, 359, 358, 1,
+		0, 0, 0, 360, 362, 1, 0, 0, 0, 361, 356, 1, 0, 0, 0, 362, 365, 1, 0, 0,
+		0, 363, 361, 1, 0, 0, 0, 363, 364, 1, 0, 0, 0, 364, 366, 1, 0, 0, 0, 365,
+		363, 1, 0, 0, 0, 366, 367, 5, 8, 0, 0, 367, 37, 1, 0, 0, 0, 368, 369, 5,
+		45, 0, 0, 369, 371, 3, 6, 3, 0, 370, 368, 1, 0, 0, 0, 370, 371, 1, 0, 0,
+		0, 371, 395, 1, 0, 0, 0, 372, 373, 5, 52, 0, 0, 373, 374, 5, 7, 0, 0, 374,
 		375, 3, 10, 5, 0, 375, 376, 5, 8, 0, 0, 376, 396, 1, 0, 0, 0, 377, 378,
 		5, 46, 0, 0, 378, 379, 5, 7, 0, 0, 379, 380, 3, 104, 52, 0, 380, 381, 5,
 		8, 0, 0, 381, 396, 1, 0, 0, 0, 382, 383, 5, 47, 0, 0, 383, 384, 5, 49,
@@ -412,21 +420,21 @@ func kuneiformparserParserInit() {
 		439, 1, 0, 0, 0, 474, 449, 1, 0, 0, 0, 474, 456, 1, 0, 0, 0, 474, 462,
 		1, 0, 0, 0, 474, 465, 1, 0, 0, 0, 474, 467, 1, 0, 0, 0, 475, 47, 1, 0,
 		0, 0, 476, 478, 5, 38, 0, 0, 477, 479, 5, 52, 0, 0, 478, 477, 1, 0, 0,
-		0, 478, 479, 1, 0, 0, 0, 479, 480, 1, 0, 0, 0, 480, 484, 5, 63, 0, 0, 481,
-		482, 5, 113, 0, 0, 482, 483, 5, 62, 0, 0, 483, 485, 5, 71, 0, 0, 484, 481,
-		1, 0, 0, 0, 484, 485, 1, 0, 0, 0, 485, 487, 1, 0, 0, 0, 486, 488, 3, 6,
-		3, 0, 487, 486, 1, 0, 0, 0, 487, 488, 1, 0, 0, 0, 488, 489, 1, 0, 0, 0,
-		489, 490, 5, 50, 0, 0, 490, 491, 3, 6, 3, 0, 491, 492, 5, 7, 0, 0, 492,
-		493, 3, 10, 5, 0, 493, 494, 5, 8, 0, 0, 494, 49, 1, 0, 0, 0, 495, 496,
-		5, 42, 0, 0, 496, 499, 5, 63, 0, 0, 497, 498, 5, 113, 0, 0, 498, 500, 5,
-		71, 0, 0, 499, 497, 1, 0, 0, 0, 499, 500, 1, 0, 0, 0, 500, 501, 1, 0, 0,
-		0, 501, 502, 3, 6, 3, 0, 502, 51, 1, 0, 0, 0, 503, 504, 5, 38, 0, 0, 504,
-		508, 5, 128, 0, 0, 505, 506, 5, 113, 0, 0, 506, 507, 5, 62, 0, 0, 507,
-		509, 5, 71, 0, 0, 508, 505, 1, 0, 0, 0, 508, 509, 1, 0, 0, 0, 509, 510,
-		1, 0, 0, 0, 510, 511, 3, 6, 3, 0, 511, 53, 1, 0, 0, 0, 512, 513, 5, 42,
-		0, 0, 513, 516, 5, 128, 0, 0, 514, 515, 5, 113, 0, 0, 515, 517, 5, 71,
-		0, 0, 516, 514, 1, 0, 0, 0, 516, 517, 1, 0, 0, 0, 517, 518, 1, 0, 0, 0,
-		518, 519, 3, 6, 3, 0, 519, 55, 1, 0, 0, 0, 520, 524, 5, 125, 0, 0, 521,
+		0, 478, 479, 1, 0, 0, 0, 479, 480, 1, 0, 0, 0, 480, 1452, 5, 63, 0, 0,
+		481, 482, 5, 113, 0, 0, 482, 483, 5, 62, 0, 0, 483, 485, 5, 71, 0, 0, 484,
+		481, 1, 0, 0, 0, 484, 485, 1, 0, 0, 0, 485, 487, 1, 0, 0, 0, 486, 488,
+		3, 6, 3, 0, 487, 486, 1, 0, 0, 0, 487, 488, 1, 0, 0, 0, 488, 489, 1, 0,
+		0, 0, 489, 490, 5, 50, 0, 0, 490, 491, 3, 6, 3, 0, 491, 492, 5, 7, 0, 0,
+		492, 493, 3, 10, 5, 0, 493, 494, 5, 8, 0, 0, 494, 49, 1, 0, 0, 0, 495,
+		496, 5, 42, 0, 0, 496, 499, 5, 63, 0, 0, 497, 498, 5, 113, 0, 0, 498, 500,
+		5, 71, 0, 0, 499, 497, 1, 0, 0, 0, 499, 500, 1, 0, 0, 0, 500, 501, 1, 0,
+		0, 0, 501, 502, 3, 6, 3, 0, 502, 51, 1, 0, 0, 0, 503, 504, 5, 38, 0, 0,
+		504, 508, 5, 128, 0, 0, 505, 506, 5, 113, 0, 0, 506, 507, 5, 62, 0, 0,
+		507, 509, 5, 71, 0, 0, 508, 505, 1, 0, 0, 0, 508, 509, 1, 0, 0, 0, 509,
+		510, 1, 0, 0, 0, 510, 511, 3, 6, 3, 0, 511, 53, 1, 0, 0, 0, 512, 513, 5,
+		42, 0, 0, 513, 516, 5, 128, 0, 0, 514, 515, 5, 113, 0, 0, 515, 517, 5,
+		71, 0, 0, 516, 514, 1, 0, 0, 0, 516, 517, 1, 0, 0, 0, 517, 518, 1, 0, 0,
+		0, 518, 519, 3, 6, 3, 0, 519, 55, 1, 0, 0, 0, 520, 524, 5, 125, 0, 0, 521,
 		522, 5, 113, 0, 0, 522, 523, 5, 62, 0, 0, 523, 525, 5, 126, 0, 0, 524,
 		521, 1, 0, 0, 0, 524, 525, 1, 0, 0, 0, 525, 528, 1, 0, 0, 0, 526, 529,
 		3, 62, 31, 0, 527, 529, 3, 6, 3, 0, 528, 526, 1, 0, 0, 0, 528, 527, 1,
@@ -530,7 +538,7 @@ func kuneiformparserParserInit() {
 		0, 0, 794, 795, 5, 7, 0, 0, 795, 796, 3, 80, 40, 0, 796, 801, 5, 8, 0,
 		0, 797, 799, 5, 78, 0, 0, 798, 797, 1, 0, 0, 0, 798, 799, 1, 0, 0, 0, 799,
 		800, 1, 0, 0, 0, 800, 802, 3, 6, 3, 0, 801, 798, 1, 0, 0, 0, 801, 802,
-		1, 0, 0, 0, 802, 804, 1, 0, 0, 0, 803, 785, 1, 0, 0, 0, 803, 794, 1, 0,
+		1, 0, 0, 0, 802, 804, 1, 0, 0, 0, 803, 785, 1, 0, 0, 0, 803, 1439, 1, 0,
 		0, 0, 804, 89, 1, 0, 0, 0, 805, 807, 7, 9, 0, 0, 806, 805, 1, 0, 0, 0,
 		806, 807, 1, 0, 0, 0, 807, 808, 1, 0, 0, 0, 808, 809, 5, 74, 0, 0, 809,
 		810, 3, 88, 44, 0, 810, 811, 5, 50, 0, 0, 811, 812, 3, 104, 52, 0, 812,
@@ -771,21 +779,50 @@ func kuneiformparserParserInit() {
 		0, 0, 1377, 1378, 1, 0, 0, 0, 1378, 1380, 1, 0, 0, 0, 1379, 1377, 1, 0,
 		0, 0, 1380, 1381, 5, 2, 0, 0, 1381, 125, 1, 0, 0, 0, 1382, 1383, 3, 114,
 		57, 0, 1383, 1384, 5, 32, 0, 0, 1384, 1385, 3, 114, 57, 0, 1385, 127, 1,
-		0, 0, 0, 195, 133, 137, 145, 165, 169, 173, 181, 188, 197, 205, 208, 212,
-		224, 232, 243, 259, 271, 277, 285, 287, 291, 301, 305, 312, 315, 321, 330,
-		333, 336, 348, 354, 359, 363, 370, 395, 403, 407, 417, 428, 437, 444, 453,
-		471, 474, 478, 484, 487, 499, 508, 516, 524, 528, 532, 538, 543, 547, 551,
-		557, 564, 571, 579, 585, 596, 599, 605, 609, 615, 624, 632, 646, 649, 652,
-		661, 668, 676, 692, 702, 705, 709, 713, 717, 721, 725, 729, 733, 740, 748,
-		751, 755, 762, 764, 777, 780, 785, 789, 792, 798, 801, 803, 806, 815, 818,
-		823, 826, 831, 834, 842, 850, 853, 857, 867, 870, 876, 889, 893, 896, 905,
-		907, 918, 923, 925, 931, 934, 938, 945, 951, 960, 965, 969, 973, 978, 982,
-		987, 991, 995, 1000, 1004, 1009, 1012, 1018, 1022, 1038, 1044, 1064, 1070,
-		1074, 1076, 1080, 1087, 1093, 1100, 1108, 1110, 1112, 1119, 1128, 1131,
-		1145, 1151, 1155, 1164, 1170, 1174, 1178, 1181, 1185, 1189, 1193, 1220,
-		1226, 1230, 1232, 1236, 1241, 1249, 1251, 1253, 1261, 1273, 1278, 1285,
-		1297, 1300, 1306, 1311, 1318, 1323, 1331, 1335, 1338, 1348, 1356, 1363,
-		1368, 1377,
+		0, 0, 0, 803, 1387, 1, 0, 0, 0, 1387, 1388, 3, 6, 3, 0, 1388, 1389, 5,
+		7, 0, 0, 1389, 1390, 1, 0, 0, 0, 1389, 1392, 1, 0, 0, 0, 1390, 1391, 3,
+		110, 55, 0, 1391, 1392, 1, 0, 0, 0, 1392, 1393, 1, 0, 0, 0, 1393, 1394,
+		5, 8, 0, 0, 1394, 1395, 1, 0, 0, 0, 1394, 1399, 1, 0, 0, 0, 1395, 1396,
+		1, 0, 0, 0, 1395, 1397, 1, 0, 0, 0, 1396, 1397, 5, 78, 0, 0, 1397, 1398,
+		1, 0, 0, 0, 1398, 1399, 3, 6, 3, 0, 1399, 804, 1, 0, 0, 0, 1400, 1401,
+		5, 148, 0, 0, 1401, 1402, 3, 6, 3, 0, 1402, 1403, 5, 148, 0, 0, 1403, 1404,
+		5, 112, 0, 0, 1404, 1405, 3, 32, 16, 0, 1405, 1406, 5, 6, 0, 0, 1406, 1357,
+		1, 0, 0, 0, 1407, 1408, 5, 148, 0, 0, 1408, 1409, 5, 119, 0, 0, 1409, 1410,
+		5, 95, 0, 0, 1410, 1420, 3, 6, 3, 0, 1411, 1412, 5, 109, 0, 0, 1412, 1417,
+		3, 120, 60, 0, 1413, 1414, 5, 9, 0, 0, 1414, 1416, 3, 120, 60, 0, 1415,
+		1413, 1, 0, 0, 0, 1416, 1419, 1, 0, 0, 0, 1417, 1415, 1, 0, 0, 0, 1417,
+		1418, 1, 0, 0, 0, 1418, 1421, 1, 0, 0, 0, 1419, 1417, 1, 0, 0, 0, 1420,
+		1411, 1, 0, 0, 0, 1420, 1421, 1, 0, 0, 0, 1421, 1422, 1, 0, 0, 0, 1422,
+		1423, 5, 6, 0, 0, 1423, 1357, 1, 0, 0, 0, 1424, 1425, 5, 148, 0, 0, 1425,
+		1426, 3, 6, 3, 0, 1426, 1427, 5, 6, 0, 0, 1427, 1357, 1, 0, 0, 0, 1356,
+		1400, 1, 0, 0, 0, 1356, 1407, 1, 0, 0, 0, 1356, 1424, 1, 0, 0, 0, 1428,
+		1430, 1, 0, 0, 0, 1430, 1431, 5, 39, 0, 0, 1431, 1432, 5, 132, 0, 0, 1432,
+		1433, 3, 6, 3, 0, 1433, 1434, 5, 55, 0, 0, 1434, 1435, 5, 148, 0, 0, 1435,
+		1455, 1, 0, 0, 0, 1436, 1429, 1, 0, 0, 0, 1437, 166, 3, 1428, 64, 0, 165,
+		1437, 1, 0, 0, 0, 1439, 1440, 1, 0, 0, 0, 1439, 1438, 1, 0, 0, 0, 1440,
+		1438, 5, 148, 0, 0, 1438, 794, 1, 0, 0, 0, 1441, 1443, 1, 0, 0, 0, 1443,
+		1444, 5, 148, 0, 0, 1444, 1445, 5, 36, 0, 0, 1445, 1446, 3, 6, 3, 0, 1446,
+		1448, 1, 0, 0, 0, 1448, 1449, 1, 0, 0, 0, 1448, 1447, 1, 0, 0, 0, 1449,
+		1447, 5, 53, 0, 0, 1447, 1442, 1, 0, 0, 0, 1450, 166, 3, 1441, 65, 0, 165,
+		1450, 1, 0, 0, 0, 1452, 1453, 1, 0, 0, 0, 1452, 1451, 1, 0, 0, 0, 1453,
+		1451, 5, 148, 0, 0, 1451, 484, 1, 0, 0, 0, 1455, 1456, 1, 0, 0, 0, 1455,
+		1457, 1, 0, 0, 0, 1456, 1454, 5, 148, 0, 0, 1457, 1458, 5, 137, 0, 0, 1458,
+		1459, 5, 15, 0, 0, 1459, 1454, 5, 137, 0, 0, 1454, 1436, 1, 0, 0, 0, 204,
+		133, 137, 145, 165, 169, 173, 181, 188, 197, 205, 208, 212, 224, 232, 243,
+		259, 271, 277, 285, 287, 291, 301, 305, 312, 315, 321, 330, 333, 336, 348,
+		354, 359, 363, 370, 395, 403, 407, 417, 428, 437, 444, 453, 471, 474, 478,
+		484, 487, 499, 508, 516, 524, 528, 532, 538, 543, 547, 551, 557, 564, 571,
+		579, 585, 596, 599, 605, 609, 615, 624, 632, 646, 649, 652, 661, 668, 676,
+		692, 702, 705, 709, 713, 717, 721, 725, 729, 733, 740, 748, 751, 755, 762,
+		764, 777, 780, 785, 789, 792, 798, 801, 803, 806, 815, 818, 823, 826, 831,
+		834, 842, 850, 853, 857, 867, 870, 876, 889, 893, 896, 905, 907, 918, 923,
+		925, 931, 934, 938, 945, 951, 960, 965, 969, 973, 978, 982, 987, 991, 995,
+		1000, 1004, 1009, 1012, 1018, 1022, 1038, 1044, 1064, 1070, 1074, 1076,
+		1080, 1087, 1093, 1100, 1108, 1110, 1112, 1119, 1128, 1131, 1145, 1151,
+		1155, 1164, 1170, 1174, 1178, 1181, 1185, 1189, 1193, 1220, 1226, 1230,
+		1232, 1236, 1241, 1249, 1251, 1253, 1261, 1273, 1278, 1285, 1297, 1300,
+		1306, 1311, 1318, 1323, 1331, 1335, 1338, 1348, 1356, 1363, 1368, 1377,
+		1389, 1394, 1395, 1417, 1420, 1439, 1448, 1452, 1455,
 	}
 	deserializer := antlr.NewATNDeserializer(nil)
 	staticData.atn = deserializer.Deserialize(staticData.serializedATN)
@@ -1047,6 +1084,8 @@ const (
 	KuneiformParserRULE_action_function_call            = 61
 	KuneiformParserRULE_if_then_block                   = 62
 	KuneiformParserRULE_range                           = 63
+	KuneiformParserRULE_alter_namespace_statement       = 64
+	KuneiformParserRULE_truncate_table_statement        = 65
 )
 
 // IEntryContext is an interface to support dynamic dispatch.
@@ -1289,6 +1328,8 @@ type IStatementContext interface {
 	Create_namespace_statement() ICreate_namespace_statementContext
 	Drop_namespace_statement() IDrop_namespace_statementContext
 	Set_current_namespace_statement() ISet_current_namespace_statementContext
+	Alter_namespace_statement() IAlter_namespace_statementContext
+	Truncate_table_statement() ITruncate_table_statementContext
 	LBRACE() antlr.TerminalNode
 	RBRACE() antlr.TerminalNode
 	Identifier() IIdentifierContext
@@ -1622,6 +1663,38 @@ func (s *StatementContext) Set_current_namespace_statement() ISet_current_namesp
 	return t.(ISet_current_namespace_statementContext)
 }
 
+func (s *StatementContext) Alter_namespace_statement() IAlter_namespace_statementContext {
+	var t antlr.RuleContext
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(IAlter_namespace_statementContext); ok {
+			t = ctx.(antlr.RuleContext)
+			break
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(IAlter_namespace_statementContext)
+}
+
+func (s *StatementContext) Truncate_table_statement() ITruncate_table_statementContext {
+	var t antlr.RuleContext
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(ITruncate_table_statementContext); ok {
+			t = ctx.(antlr.RuleContext)
+			break
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(ITruncate_table_statementContext)
+}
+
 func (s *StatementContext) LBRACE() antlr.TerminalNode {
 	return s.GetToken(KuneiformParserLBRACE, 0)
 }
@@ -1818,6 +1891,18 @@ func (p *KuneiformParser) Statement() (localctx IStatementContext) {
 			p.Set_current_namespace_statement()
 		}
 
+	case 19:
+		{
+			p.SetState(1437)
+			p.Alter_namespace_statement()
+		}
+
+	case 20:
+		{
+			p.SetState(1450)
+			p.Truncate_table_statement()
+		}
+
 	case antlr.ATNInvalidAltNumber:
 		goto errorExit
 	}
@@ -7986,6 +8071,7 @@ type ICreate_index_statementContext interface {
 	Identifier(i int) IIdentifierContext
 	Identifier_list() IIdentifier_listContext
 	UNIQUE() antlr.TerminalNode
+	IDENTIFIER() antlr.TerminalNode
 	IF() antlr.TerminalNode
 	NOT() antlr.TerminalNode
 	EXISTS() antlr.TerminalNode
@@ -8122,6 +8208,10 @@ func (s *Create_index_statementContext) UNIQUE() antlr.TerminalNode {
 	return s.GetToken(KuneiformParserUNIQUE, 0)
 }
 
+func (s *Create_index_statementContext) IDENTIFIER() antlr.TerminalNode {
+	return s.GetToken(KuneiformParserIDENTIFIER, 0)
+}
+
 func (s *Create_index_statementContext) IF() antlr.TerminalNode {
 	return s.GetToken(KuneiformParserIF, 0)
 }
@@ -8192,6 +8282,22 @@ func (p *KuneiformParser) Create_index_statement() (localctx ICreate_index_state
 			goto errorExit
 		}
 	}
+	p.SetState(1452)
+	p.GetErrorHandler().Sync(p)
+
+	if p.GetInterpreter().AdaptivePredict(p.BaseParser, p.GetTokenStream(), 202, p.GetParserRuleContext()) == 1 {
+		{
+			p.SetState(1453)
+			p.Match(KuneiformParserIDENTIFIER)
+			if p.HasError() {
+				// Recognition error - abort rule
+				goto errorExit
+			}
+		}
+
+	} else if p.HasError() { // JIM
+		goto errorExit
+	}
 	p.SetState(484)
 	p.GetErrorHandler().Sync(p)
 
@@ -13475,6 +13581,10 @@ func (s *Subquery_relationContext) GetRuleContext() antlr.RuleContext {
 	return s
 }
 
+func (s *Subquery_relationContext) IDENTIFIER() antlr.TerminalNode {
+	return s.GetToken(KuneiformParserIDENTIFIER, 0)
+}
+
 func (s *Subquery_relationContext) LPAREN() antlr.TerminalNode {
 	return s.GetToken(KuneiformParserLPAREN, 0)
 }
@@ -13529,6 +13639,118 @@ func (s *Subquery_relationContext) Accept(visitor antlr.ParseTreeVisitor) interf
 	}
 }
 
+type Function_relationContext struct {
+	RelationContext
+	function_name IIdentifierContext
+	args          ISql_expr_listContext
+	alias         IIdentifierContext
+}
+
+func NewFunction_relationContext(parser antlr.Parser, ctx antlr.ParserRuleContext) *Function_relationContext {
+	var p = new(Function_relationContext)
+
+	InitEmptyRelationContext(&p.RelationContext)
+	p.parser = parser
+	p.CopyAll(ctx.(*RelationContext))
+
+	return p
+}
+
+func (s *Function_relationContext) GetFunction_name() IIdentifierContext { return s.function_name }
+
+func (s *Function_relationContext) GetArgs() ISql_expr_listContext { return s.args }
+
+func (s *Function_relationContext) GetAlias() IIdentifierContext { return s.alias }
+
+func (s *Function_relationContext) SetFunction_name(v IIdentifierContext) { s.function_name = v }
+
+func (s *Function_relationContext) SetArgs(v ISql_expr_listContext) { s.args = v }
+
+func (s *Function_relationContext) SetAlias(v IIdentifierContext) { s.alias = v }
+
+func (s *Function_relationContext) GetRuleContext() antlr.RuleContext {
+	return s
+}
+
+func (s *Function_relationContext) LPAREN() antlr.TerminalNode {
+	return s.GetToken(KuneiformParserLPAREN, 0)
+}
+
+func (s *Function_relationContext) RPAREN() antlr.TerminalNode {
+	return s.GetToken(KuneiformParserRPAREN, 0)
+}
+
+func (s *Function_relationContext) AllIdentifier() []IIdentifierContext {
+	children := s.GetChildren()
+	len := 0
+	for _, ctx := range children {
+		if _, ok := ctx.(IIdentifierContext); ok {
+			len++
+		}
+	}
+
+	tst := make([]IIdentifierContext, len)
+	i := 0
+	for _, ctx := range children {
+		if t, ok := ctx.(IIdentifierContext); ok {
+			tst[i] = t.(IIdentifierContext)
+			i++
+		}
+	}
+
+	return tst
+}
+
+func (s *Function_relationContext) Identifier(i int) IIdentifierContext {
+	var t antlr.RuleContext
+	j := 0
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(IIdentifierContext); ok {
+			if j == i {
+				t = ctx.(antlr.RuleContext)
+				break
+			}
+			j++
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(IIdentifierContext)
+}
+
+func (s *Function_relationContext) Sql_expr_list() ISql_expr_listContext {
+	var t antlr.RuleContext
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(ISql_expr_listContext); ok {
+			t = ctx.(antlr.RuleContext)
+			break
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(ISql_expr_listContext)
+}
+
+func (s *Function_relationContext) AS() antlr.TerminalNode {
+	return s.GetToken(KuneiformParserAS, 0)
+}
+
+func (s *Function_relationContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
+	switch t := visitor.(type) {
+	case KuneiformParserVisitor:
+		return t.VisitFunction_relation(s)
+
+	default:
+		return t.VisitChildren(s)
+	}
+}
+
 func (p *KuneiformParser) Relation() (localctx IRelationContext) {
 	localctx = NewRelationContext(p, p.GetParserRuleContext(), p.GetState())
 	p.EnterRule(localctx, 88, KuneiformParserRULE_relation)
@@ -13540,8 +13762,8 @@ func (p *KuneiformParser) Relation() (localctx IRelationContext) {
 		goto errorExit
 	}
 
-	switch p.GetTokenStream().LA(1) {
-	case KuneiformParserDOUBLE_QUOTE, KuneiformParserUSE, KuneiformParserUNUSE, KuneiformParserACTION, KuneiformParserCREATE, KuneiformParserALTER, KuneiformParserADD, KuneiformParserDROP, KuneiformParserRENAME, KuneiformParserCHECK, KuneiformParserFOREIGN, KuneiformParserPRIMARY, KuneiformParserKEY, KuneiformParserUNIQUE, KuneiformParserRESTRICT, KuneiformParserDEFAULT, KuneiformParserINDEX, KuneiformParserRETURNS, KuneiformParserFOR, KuneiformParserIF, KuneiformParserELSEIF, KuneiformParserELSE, KuneiformParserBREAK, KuneiformParserCONTINUE, KuneiformParserRETURN, KuneiformParserGRANT, KuneiformParserGRANTED, KuneiformParserREVOKE, KuneiformParserROLE, KuneiformParserREPLACE, KuneiformParserCURRENT, KuneiformParserNAMESPACE, KuneiformParserTRANSFER, KuneiformParserOWNERSHIP, KuneiformParserROLES, KuneiformParserCALL, KuneiformParserIDENTIFIER:
+	switch p.GetInterpreter().AdaptivePredict(p.BaseParser, p.GetTokenStream(), 98, p.GetParserRuleContext()) {
+	case 1:
 		localctx = NewTable_relationContext(p, localctx)
 		p.EnterOuterAlt(localctx, 1)
 		p.SetState(785)
@@ -13610,9 +13832,27 @@ func (p *KuneiformParser) Relation() (localctx IRelationContext) {
 
 		}
 
-	case KuneiformParserLPAREN:
+	case 2:
 		localctx = NewSubquery_relationContext(p, localctx)
 		p.EnterOuterAlt(localctx, 2)
+		p.SetState(1439)
+		p.GetErrorHandler().Sync(p)
+		if p.HasError() {
+			goto errorExit
+		}
+		_la = p.GetTokenStream().LA(1)
+
+		if _la == KuneiformParserIDENTIFIER {
+			{
+				p.SetState(1440)
+				p.Match(KuneiformParserIDENTIFIER)
+				if p.HasError() {
+					// Recognition error - abort rule
+					goto errorExit
+				}
+			}
+
+		}
 		{
 			p.SetState(794)
 			p.Match(KuneiformParserLPAREN)
@@ -13669,8 +13909,86 @@ func (p *KuneiformParser) Relation() (localctx IRelationContext) {
 
 		}
 
-	default:
-		p.SetError(antlr.NewNoViableAltException(p, nil, nil, nil, nil, nil))
+	case 3:
+		localctx = NewFunction_relationContext(p, localctx)
+		p.EnterOuterAlt(localctx, 3)
+		{
+			p.SetState(1387)
+
+			var _x = p.Identifier()
+
+			localctx.(*Function_relationContext).function_name = _x
+		}
+		{
+			p.SetState(1388)
+			p.Match(KuneiformParserLPAREN)
+			if p.HasError() {
+				// Recognition error - abort rule
+				goto errorExit
+			}
+		}
+		p.SetState(1389)
+		p.GetErrorHandler().Sync(p)
+		if p.HasError() {
+			goto errorExit
+		}
+		_la = p.GetTokenStream().LA(1)
+
+		if ((int64(_la) & ^0x3f) == 0 && ((int64(1)<<_la)&-4371923291645935488) != 0) || ((int64((_la-71)) & ^0x3f) == 0 && ((int64(1)<<(_la-71))&-17735122555437055) != 0) || ((int64((_la-135)) & ^0x3f) == 0 && ((int64(1)<<(_la-135))&57471) != 0) {
+			{
+				p.SetState(1390)
+
+				var _x = p.Sql_expr_list()
+
+				localctx.(*Function_relationContext).args = _x
+			}
+
+		}
+		{
+			p.SetState(1393)
+			p.Match(KuneiformParserRPAREN)
+			if p.HasError() {
+				// Recognition error - abort rule
+				goto errorExit
+			}
+		}
+		p.SetState(1394)
+		p.GetErrorHandler().Sync(p)
+		if p.HasError() {
+			goto errorExit
+		}
+		_la = p.GetTokenStream().LA(1)
+
+		if ((int64((_la-33)) & ^0x3f) == 0 && ((int64(1)<<(_la-33))&18049583966447479) != 0) || ((int64((_la-112)) & ^0x3f) == 0 && ((int64(1)<<(_la-112))&68752760959) != 0) {
+			p.SetState(1395)
+			p.GetErrorHandler().Sync(p)
+			if p.HasError() {
+				goto errorExit
+			}
+			_la = p.GetTokenStream().LA(1)
+
+			if _la == KuneiformParserAS {
+				{
+					p.SetState(1396)
+					p.Match(KuneiformParserAS)
+					if p.HasError() {
+						// Recognition error - abort rule
+						goto errorExit
+					}
+				}
+
+			}
+			{
+				p.SetState(1398)
+
+				var _x = p.Identifier()
+
+				localctx.(*Function_relationContext).alias = _x
+			}
+
+		}
+
+	case antlr.ATNInvalidAltNumber:
 		goto errorExit
 	}
 
@@ -22581,31 +22899,287 @@ func (s *Stmt_sqlContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
 	}
 }
 
-func (p *KuneiformParser) Action_statement() (localctx IAction_statementContext) {
-	localctx = NewAction_statementContext(p, p.GetParserRuleContext(), p.GetState())
-	p.EnterRule(localctx, 118, KuneiformParserRULE_action_statement)
-	var _la int
+type Stmt_declare_cursorContext struct {
+	Action_statementContext
+	cursor IIdentifierContext
+}
 
-	var _alt int
+func NewStmt_declare_cursorContext(parser antlr.Parser, ctx antlr.ParserRuleContext) *Stmt_declare_cursorContext {
+	var p = new(Stmt_declare_cursorContext)
 
-	p.SetState(1356)
-	p.GetErrorHandler().Sync(p)
-	if p.HasError() {
-		goto errorExit
-	}
+	InitEmptyAction_statementContext(&p.Action_statementContext)
+	p.parser = parser
+	p.CopyAll(ctx.(*Action_statementContext))
 
-	switch p.GetInterpreter().AdaptivePredict(p.BaseParser, p.GetTokenStream(), 191, p.GetParserRuleContext()) {
-	case 1:
-		localctx = NewStmt_variable_declarationContext(p, localctx)
-		p.EnterOuterAlt(localctx, 1)
-		{
-			p.SetState(1264)
-			p.Match(KuneiformParserVARIABLE)
-			if p.HasError() {
-				// Recognition error - abort rule
-				goto errorExit
-			}
-		}
+	return p
+}
+
+func (s *Stmt_declare_cursorContext) GetCursor() IIdentifierContext { return s.cursor }
+
+func (s *Stmt_declare_cursorContext) SetCursor(v IIdentifierContext) { s.cursor = v }
+
+func (s *Stmt_declare_cursorContext) GetRuleContext() antlr.RuleContext {
+	return s
+}
+
+func (s *Stmt_declare_cursorContext) AllIDENTIFIER() []antlr.TerminalNode {
+	return s.GetTokens(KuneiformParserIDENTIFIER)
+}
+
+func (s *Stmt_declare_cursorContext) IDENTIFIER(i int) antlr.TerminalNode {
+	return s.GetToken(KuneiformParserIDENTIFIER, i)
+}
+
+func (s *Stmt_declare_cursorContext) FOR() antlr.TerminalNode {
+	return s.GetToken(KuneiformParserFOR, 0)
+}
+
+func (s *Stmt_declare_cursorContext) Sql_statement() ISql_statementContext {
+	var t antlr.RuleContext
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(ISql_statementContext); ok {
+			t = ctx.(antlr.RuleContext)
+			break
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(ISql_statementContext)
+}
+
+func (s *Stmt_declare_cursorContext) SCOL() antlr.TerminalNode {
+	return s.GetToken(KuneiformParserSCOL, 0)
+}
+
+func (s *Stmt_declare_cursorContext) Identifier() IIdentifierContext {
+	var t antlr.RuleContext
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(IIdentifierContext); ok {
+			t = ctx.(antlr.RuleContext)
+			break
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(IIdentifierContext)
+}
+
+func (s *Stmt_declare_cursorContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
+	switch t := visitor.(type) {
+	case KuneiformParserVisitor:
+		return t.VisitStmt_declare_cursor(s)
+
+	default:
+		return t.VisitChildren(s)
+	}
+}
+
+type Stmt_fetch_cursorContext struct {
+	Action_statementContext
+	cursor IIdentifierContext
+}
+
+func NewStmt_fetch_cursorContext(parser antlr.Parser, ctx antlr.ParserRuleContext) *Stmt_fetch_cursorContext {
+	var p = new(Stmt_fetch_cursorContext)
+
+	InitEmptyAction_statementContext(&p.Action_statementContext)
+	p.parser = parser
+	p.CopyAll(ctx.(*Action_statementContext))
+
+	return p
+}
+
+func (s *Stmt_fetch_cursorContext) GetCursor() IIdentifierContext { return s.cursor }
+
+func (s *Stmt_fetch_cursorContext) SetCursor(v IIdentifierContext) { s.cursor = v }
+
+func (s *Stmt_fetch_cursorContext) GetRuleContext() antlr.RuleContext {
+	return s
+}
+
+func (s *Stmt_fetch_cursorContext) IDENTIFIER() antlr.TerminalNode {
+	return s.GetToken(KuneiformParserIDENTIFIER, 0)
+}
+
+func (s *Stmt_fetch_cursorContext) NEXT() antlr.TerminalNode {
+	return s.GetToken(KuneiformParserNEXT, 0)
+}
+
+func (s *Stmt_fetch_cursorContext) FROM() antlr.TerminalNode {
+	return s.GetToken(KuneiformParserFROM, 0)
+}
+
+func (s *Stmt_fetch_cursorContext) SCOL() antlr.TerminalNode {
+	return s.GetToken(KuneiformParserSCOL, 0)
+}
+
+func (s *Stmt_fetch_cursorContext) Identifier() IIdentifierContext {
+	var t antlr.RuleContext
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(IIdentifierContext); ok {
+			t = ctx.(antlr.RuleContext)
+			break
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(IIdentifierContext)
+}
+
+func (s *Stmt_fetch_cursorContext) INTO() antlr.TerminalNode {
+	return s.GetToken(KuneiformParserINTO, 0)
+}
+
+func (s *Stmt_fetch_cursorContext) AllVariable_or_underscore() []IVariable_or_underscoreContext {
+	children := s.GetChildren()
+	len := 0
+	for _, ctx := range children {
+		if _, ok := ctx.(IVariable_or_underscoreContext); ok {
+			len++
+		}
+	}
+
+	tst := make([]IVariable_or_underscoreContext, len)
+	i := 0
+	for _, ctx := range children {
+		if t, ok := ctx.(IVariable_or_underscoreContext); ok {
+			tst[i] = t.(IVariable_or_underscoreContext)
+			i++
+		}
+	}
+
+	return tst
+}
+
+func (s *Stmt_fetch_cursorContext) Variable_or_underscore(i int) IVariable_or_underscoreContext {
+	var t antlr.RuleContext
+	j := 0
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(IVariable_or_underscoreContext); ok {
+			if j == i {
+				t = ctx.(antlr.RuleContext)
+				break
+			}
+			j++
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(IVariable_or_underscoreContext)
+}
+
+func (s *Stmt_fetch_cursorContext) AllCOMMA() []antlr.TerminalNode {
+	return s.GetTokens(KuneiformParserCOMMA)
+}
+
+func (s *Stmt_fetch_cursorContext) COMMA(i int) antlr.TerminalNode {
+	return s.GetToken(KuneiformParserCOMMA, i)
+}
+
+func (s *Stmt_fetch_cursorContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
+	switch t := visitor.(type) {
+	case KuneiformParserVisitor:
+		return t.VisitStmt_fetch_cursor(s)
+
+	default:
+		return t.VisitChildren(s)
+	}
+}
+
+type Stmt_close_cursorContext struct {
+	Action_statementContext
+	cursor IIdentifierContext
+}
+
+func NewStmt_close_cursorContext(parser antlr.Parser, ctx antlr.ParserRuleContext) *Stmt_close_cursorContext {
+	var p = new(Stmt_close_cursorContext)
+
+	InitEmptyAction_statementContext(&p.Action_statementContext)
+	p.parser = parser
+	p.CopyAll(ctx.(*Action_statementContext))
+
+	return p
+}
+
+func (s *Stmt_close_cursorContext) GetCursor() IIdentifierContext { return s.cursor }
+
+func (s *Stmt_close_cursorContext) SetCursor(v IIdentifierContext) { s.cursor = v }
+
+func (s *Stmt_close_cursorContext) GetRuleContext() antlr.RuleContext {
+	return s
+}
+
+func (s *Stmt_close_cursorContext) IDENTIFIER() antlr.TerminalNode {
+	return s.GetToken(KuneiformParserIDENTIFIER, 0)
+}
+
+func (s *Stmt_close_cursorContext) SCOL() antlr.TerminalNode {
+	return s.GetToken(KuneiformParserSCOL, 0)
+}
+
+func (s *Stmt_close_cursorContext) Identifier() IIdentifierContext {
+	var t antlr.RuleContext
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(IIdentifierContext); ok {
+			t = ctx.(antlr.RuleContext)
+			break
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(IIdentifierContext)
+}
+
+func (s *Stmt_close_cursorContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
+	switch t := visitor.(type) {
+	case KuneiformParserVisitor:
+		return t.VisitStmt_close_cursor(s)
+
+	default:
+		return t.VisitChildren(s)
+	}
+}
+
+func (p *KuneiformParser) Action_statement() (localctx IAction_statementContext) {
+	localctx = NewAction_statementContext(p, p.GetParserRuleContext(), p.GetState())
+	p.EnterRule(localctx, 118, KuneiformParserRULE_action_statement)
+	var _la int
+
+	var _alt int
+
+	p.SetState(1356)
+	p.GetErrorHandler().Sync(p)
+	if p.HasError() {
+		goto errorExit
+	}
+
+	switch p.GetInterpreter().AdaptivePredict(p.BaseParser, p.GetTokenStream(), 191, p.GetParserRuleContext()) {
+	case 1:
+		localctx = NewStmt_variable_declarationContext(p, localctx)
+		p.EnterOuterAlt(localctx, 1)
+		{
+			p.SetState(1264)
+			p.Match(KuneiformParserVARIABLE)
+			if p.HasError() {
+				// Recognition error - abort rule
+				goto errorExit
+			}
+		}
 		{
 			p.SetState(1265)
 			p.Type_()
@@ -23129,29 +23703,198 @@ func (p *KuneiformParser) Action_statement() (localctx IAction_statementContext)
 			}
 		}
 
-	case antlr.ATNInvalidAltNumber:
-		goto errorExit
-	}
-
-errorExit:
-	if p.HasError() {
-		v := p.GetError()
-		localctx.SetException(v)
-		p.GetErrorHandler().ReportError(p, v)
-		p.GetErrorHandler().Recover(p, v)
-		p.SetError(nil)
-	}
-	p.ExitRule()
-	return localctx
-	goto errorExit // Trick to prevent compiler error if the label is not used
-}
-
-// IVariable_or_underscoreContext is an interface to support dynamic dispatch.
-type IVariable_or_underscoreContext interface {
-	antlr.ParserRuleContext
+	case 10:
+		localctx = NewStmt_declare_cursorContext(p, localctx)
+		p.EnterOuterAlt(localctx, 10)
+		{
+			p.SetState(1400)
+			p.Match(KuneiformParserIDENTIFIER)
+			if p.HasError() {
+				// Recognition error - abort rule
+				goto errorExit
+			}
+		}
+		{
+			p.SetState(1401)
 
-	// GetParser returns the parser.
-	GetParser() antlr.Parser
+			var _x = p.Identifier()
+
+			localctx.(*Stmt_declare_cursorContext).cursor = _x
+		}
+		{
+			p.SetState(1402)
+			p.Match(KuneiformParserIDENTIFIER)
+			if p.HasError() {
+				// Recognition error - abort rule
+				goto errorExit
+			}
+		}
+		{
+			p.SetState(1403)
+			p.Match(KuneiformParserFOR)
+			if p.HasError() {
+				// Recognition error - abort rule
+				goto errorExit
+			}
+		}
+		{
+			p.SetState(1404)
+			p.Sql_statement()
+		}
+		{
+			p.SetState(1405)
+			p.Match(KuneiformParserSCOL)
+			if p.HasError() {
+				// Recognition error - abort rule
+				goto errorExit
+			}
+		}
+
+	case 11:
+		localctx = NewStmt_fetch_cursorContext(p, localctx)
+		p.EnterOuterAlt(localctx, 11)
+		{
+			p.SetState(1407)
+			p.Match(KuneiformParserIDENTIFIER)
+			if p.HasError() {
+				// Recognition error - abort rule
+				goto errorExit
+			}
+		}
+		{
+			p.SetState(1408)
+			p.Match(KuneiformParserNEXT)
+			if p.HasError() {
+				// Recognition error - abort rule
+				goto errorExit
+			}
+		}
+		{
+			p.SetState(1409)
+			p.Match(KuneiformParserFROM)
+			if p.HasError() {
+				// Recognition error - abort rule
+				goto errorExit
+			}
+		}
+		{
+			p.SetState(1410)
+
+			var _x = p.Identifier()
+
+			localctx.(*Stmt_fetch_cursorContext).cursor = _x
+		}
+		p.SetState(1420)
+		p.GetErrorHandler().Sync(p)
+		if p.HasError() {
+			goto errorExit
+		}
+		_la = p.GetTokenStream().LA(1)
+
+		if _la == KuneiformParserINTO {
+			{
+				p.SetState(1411)
+				p.Match(KuneiformParserINTO)
+				if p.HasError() {
+					// Recognition error - abort rule
+					goto errorExit
+				}
+			}
+			{
+				p.SetState(1412)
+				p.Variable_or_underscore()
+			}
+
+			p.SetState(1417)
+			p.GetErrorHandler().Sync(p)
+			if p.HasError() {
+				goto errorExit
+			}
+			_la = p.GetTokenStream().LA(1)
+
+			for _la == KuneiformParserCOMMA {
+				{
+					p.SetState(1413)
+					p.Match(KuneiformParserCOMMA)
+					if p.HasError() {
+						// Recognition error - abort rule
+						goto errorExit
+					}
+				}
+
+				{
+					p.SetState(1414)
+					p.Variable_or_underscore()
+				}
+
+				p.SetState(1419)
+				p.GetErrorHandler().Sync(p)
+				if p.HasError() {
+					goto errorExit
+				}
+				_la = p.GetTokenStream().LA(1)
+			}
+
+		}
+		{
+			p.SetState(1422)
+			p.Match(KuneiformParserSCOL)
+			if p.HasError() {
+				// Recognition error - abort rule
+				goto errorExit
+			}
+		}
+
+	case 12:
+		localctx = NewStmt_close_cursorContext(p, localctx)
+		p.EnterOuterAlt(localctx, 12)
+		{
+			p.SetState(1424)
+			p.Match(KuneiformParserIDENTIFIER)
+			if p.HasError() {
+				// Recognition error - abort rule
+				goto errorExit
+			}
+		}
+		{
+			p.SetState(1425)
+
+			var _x = p.Identifier()
+
+			localctx.(*Stmt_close_cursorContext).cursor = _x
+		}
+		{
+			p.SetState(1426)
+			p.Match(KuneiformParserSCOL)
+			if p.HasError() {
+				// Recognition error - abort rule
+				goto errorExit
+			}
+		}
+
+	case antlr.ATNInvalidAltNumber:
+		goto errorExit
+	}
+
+errorExit:
+	if p.HasError() {
+		v := p.GetError()
+		localctx.SetException(v)
+		p.GetErrorHandler().ReportError(p, v)
+		p.GetErrorHandler().Recover(p, v)
+		p.SetError(nil)
+	}
+	p.ExitRule()
+	return localctx
+	goto errorExit // Trick to prevent compiler error if the label is not used
+}
+
+// IVariable_or_underscoreContext is an interface to support dynamic dispatch.
+type IVariable_or_underscoreContext interface {
+	antlr.ParserRuleContext
+
+	// GetParser returns the parser.
+	GetParser() antlr.Parser
 
 	// Getter signatures
 	VARIABLE() antlr.TerminalNode
@@ -23830,6 +24573,380 @@ errorExit:
 	goto errorExit // Trick to prevent compiler error if the label is not used
 }
 
+// IAlter_namespace_statementContext is an interface to support dynamic dispatch.
+type IAlter_namespace_statementContext interface {
+	antlr.ParserRuleContext
+
+	// GetParser returns the parser.
+	GetParser() antlr.Parser
+
+	// Getter signatures
+	ALTER() antlr.TerminalNode
+	NAMESPACE() antlr.TerminalNode
+	Identifier() IIdentifierContext
+	SET() antlr.TerminalNode
+	AllIDENTIFIER() []antlr.TerminalNode
+	IDENTIFIER(i int) antlr.TerminalNode
+	AllSTRING_() []antlr.TerminalNode
+	STRING_(i int) antlr.TerminalNode
+	EQUALS() antlr.TerminalNode
+
+	// IsAlter_namespace_statementContext differentiates from other interfaces.
+	IsAlter_namespace_statementContext()
+}
+
+type Alter_namespace_statementContext struct {
+	antlr.BaseParserRuleContext
+	parser antlr.Parser
+}
+
+func NewEmptyAlter_namespace_statementContext() *Alter_namespace_statementContext {
+	var p = new(Alter_namespace_statementContext)
+	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
+	p.RuleIndex = KuneiformParserRULE_alter_namespace_statement
+	return p
+}
+
+func InitEmptyAlter_namespace_statementContext(p *Alter_namespace_statementContext) {
+	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
+	p.RuleIndex = KuneiformParserRULE_alter_namespace_statement
+}
+
+func (*Alter_namespace_statementContext) IsAlter_namespace_statementContext() {}
+
+func NewAlter_namespace_statementContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *Alter_namespace_statementContext {
+	var p = new(Alter_namespace_statementContext)
+
+	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, parent, invokingState)
+
+	p.parser = parser
+	p.RuleIndex = KuneiformParserRULE_alter_namespace_statement
+
+	return p
+}
+
+func (s *Alter_namespace_statementContext) GetParser() antlr.Parser { return s.parser }
+
+func (s *Alter_namespace_statementContext) ALTER() antlr.TerminalNode {
+	return s.GetToken(KuneiformParserALTER, 0)
+}
+
+func (s *Alter_namespace_statementContext) NAMESPACE() antlr.TerminalNode {
+	return s.GetToken(KuneiformParserNAMESPACE, 0)
+}
+
+func (s *Alter_namespace_statementContext) Identifier() IIdentifierContext {
+	var t antlr.RuleContext
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(IIdentifierContext); ok {
+			t = ctx.(antlr.RuleContext)
+			break
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(IIdentifierContext)
+}
+
+func (s *Alter_namespace_statementContext) SET() antlr.TerminalNode {
+	return s.GetToken(KuneiformParserSET, 0)
+}
+
+func (s *Alter_namespace_statementContext) AllIDENTIFIER() []antlr.TerminalNode {
+	return s.GetTokens(KuneiformParserIDENTIFIER)
+}
+
+func (s *Alter_namespace_statementContext) IDENTIFIER(i int) antlr.TerminalNode {
+	return s.GetToken(KuneiformParserIDENTIFIER, i)
+}
+
+func (s *Alter_namespace_statementContext) AllSTRING_() []antlr.TerminalNode {
+	return s.GetTokens(KuneiformParserSTRING_)
+}
+
+func (s *Alter_namespace_statementContext) STRING_(i int) antlr.TerminalNode {
+	return s.GetToken(KuneiformParserSTRING_, i)
+}
+
+func (s *Alter_namespace_statementContext) EQUALS() antlr.TerminalNode {
+	return s.GetToken(KuneiformParserEQUALS, 0)
+}
+
+func (s *Alter_namespace_statementContext) GetRuleContext() antlr.RuleContext {
+	return s
+}
+
+func (s *Alter_namespace_statementContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
+	return antlr.TreesStringTree(s, ruleNames, recog)
+}
+
+func (s *Alter_namespace_statementContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
+	switch t := visitor.(type) {
+	case KuneiformParserVisitor:
+		return t.VisitAlter_namespace_statement(s)
+
+	default:
+		return t.VisitChildren(s)
+	}
+}
+
+func (p *KuneiformParser) Alter_namespace_statement() (localctx IAlter_namespace_statementContext) {
+	localctx = NewAlter_namespace_statementContext(p, p.GetParserRuleContext(), p.GetState())
+	p.EnterRule(localctx, 1428, KuneiformParserRULE_alter_namespace_statement)
+	p.EnterOuterAlt(localctx, 1)
+	{
+		p.SetState(1430)
+		p.Match(KuneiformParserALTER)
+		if p.HasError() {
+			// Recognition error - abort rule
+			goto errorExit
+		}
+	}
+	{
+		p.SetState(1431)
+		p.Match(KuneiformParserNAMESPACE)
+		if p.HasError() {
+			// Recognition error - abort rule
+			goto errorExit
+		}
+	}
+	{
+		p.SetState(1432)
+		p.Identifier()
+	}
+	{
+		p.SetState(1433)
+		p.Match(KuneiformParserSET)
+		if p.HasError() {
+			// Recognition error - abort rule
+			goto errorExit
+		}
+	}
+	{
+		p.SetState(1434)
+		p.Match(KuneiformParserIDENTIFIER)
+		if p.HasError() {
+			// Recognition error - abort rule
+			goto errorExit
+		}
+	}
+	p.SetState(1455)
+	p.GetErrorHandler().Sync(p)
+	if p.HasError() {
+		goto errorExit
+	}
+
+	switch p.GetTokenStream().LA(1) {
+	case KuneiformParserIDENTIFIER:
+		{
+			p.SetState(1456)
+			p.Match(KuneiformParserIDENTIFIER)
+			if p.HasError() {
+				// Recognition error - abort rule
+				goto errorExit
+			}
+		}
+
+	case KuneiformParserSTRING_:
+		{
+			p.SetState(1457)
+			p.Match(KuneiformParserSTRING_)
+			if p.HasError() {
+				// Recognition error - abort rule
+				goto errorExit
+			}
+		}
+		{
+			p.SetState(1458)
+			p.Match(KuneiformParserEQUALS)
+			if p.HasError() {
+				// Recognition error - abort rule
+				goto errorExit
+			}
+		}
+		{
+			p.SetState(1459)
+			p.Match(KuneiformParserSTRING_)
+			if p.HasError() {
+				// Recognition error - abort rule
+				goto errorExit
+			}
+		}
+
+	default:
+		p.SetError(antlr.NewNoViableAltException(p, nil, nil, nil, nil, nil))
+		goto errorExit
+	}
+
+errorExit:
+	if p.HasError() {
+		v := p.GetError()
+		localctx.SetException(v)
+		p.GetErrorHandler().ReportError(p, v)
+		p.GetErrorHandler().Recover(p, v)
+		p.SetError(nil)
+	}
+	p.ExitRule()
+	return localctx
+	goto errorExit // Trick to prevent compiler error if the label is not used
+}
+
+// ITruncate_table_statementContext is an interface to support dynamic dispatch.
+type ITruncate_table_statementContext interface {
+	antlr.ParserRuleContext
+
+	// GetParser returns the parser.
+	GetParser() antlr.Parser
+
+	// Getter signatures
+	IDENTIFIER() antlr.TerminalNode
+	TABLE() antlr.TerminalNode
+	Identifier() IIdentifierContext
+	CASCADE() antlr.TerminalNode
+
+	// IsTruncate_table_statementContext differentiates from other interfaces.
+	IsTruncate_table_statementContext()
+}
+
+type Truncate_table_statementContext struct {
+	antlr.BaseParserRuleContext
+	parser antlr.Parser
+}
+
+func NewEmptyTruncate_table_statementContext() *Truncate_table_statementContext {
+	var p = new(Truncate_table_statementContext)
+	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
+	p.RuleIndex = KuneiformParserRULE_truncate_table_statement
+	return p
+}
+
+func InitEmptyTruncate_table_statementContext(p *Truncate_table_statementContext) {
+	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, nil, -1)
+	p.RuleIndex = KuneiformParserRULE_truncate_table_statement
+}
+
+func (*Truncate_table_statementContext) IsTruncate_table_statementContext() {}
+
+func NewTruncate_table_statementContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *Truncate_table_statementContext {
+	var p = new(Truncate_table_statementContext)
+
+	antlr.InitBaseParserRuleContext(&p.BaseParserRuleContext, parent, invokingState)
+
+	p.parser = parser
+	p.RuleIndex = KuneiformParserRULE_truncate_table_statement
+
+	return p
+}
+
+func (s *Truncate_table_statementContext) GetParser() antlr.Parser { return s.parser }
+
+func (s *Truncate_table_statementContext) IDENTIFIER() antlr.TerminalNode {
+	return s.GetToken(KuneiformParserIDENTIFIER, 0)
+}
+
+func (s *Truncate_table_statementContext) TABLE() antlr.TerminalNode {
+	return s.GetToken(KuneiformParserTABLE, 0)
+}
+
+func (s *Truncate_table_statementContext) Identifier() IIdentifierContext {
+	var t antlr.RuleContext
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(IIdentifierContext); ok {
+			t = ctx.(antlr.RuleContext)
+			break
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(IIdentifierContext)
+}
+
+func (s *Truncate_table_statementContext) CASCADE() antlr.TerminalNode {
+	return s.GetToken(KuneiformParserCASCADE, 0)
+}
+
+func (s *Truncate_table_statementContext) GetRuleContext() antlr.RuleContext {
+	return s
+}
+
+func (s *Truncate_table_statementContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
+	return antlr.TreesStringTree(s, ruleNames, recog)
+}
+
+func (s *Truncate_table_statementContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
+	switch t := visitor.(type) {
+	case KuneiformParserVisitor:
+		return t.VisitTruncate_table_statement(s)
+
+	default:
+		return t.VisitChildren(s)
+	}
+}
+
+func (p *KuneiformParser) Truncate_table_statement() (localctx ITruncate_table_statementContext) {
+	localctx = NewTruncate_table_statementContext(p, p.GetParserRuleContext(), p.GetState())
+	p.EnterRule(localctx, 1441, KuneiformParserRULE_truncate_table_statement)
+	var _la int
+
+	p.EnterOuterAlt(localctx, 1)
+	{
+		p.SetState(1443)
+		p.Match(KuneiformParserIDENTIFIER)
+		if p.HasError() {
+			// Recognition error - abort rule
+			goto errorExit
+		}
+	}
+	{
+		p.SetState(1444)
+		p.Match(KuneiformParserTABLE)
+		if p.HasError() {
+			// Recognition error - abort rule
+			goto errorExit
+		}
+	}
+	{
+		p.SetState(1445)
+		p.Identifier()
+	}
+	p.SetState(1448)
+	p.GetErrorHandler().Sync(p)
+	if p.HasError() {
+		goto errorExit
+	}
+	_la = p.GetTokenStream().LA(1)
+
+	if _la == KuneiformParserCASCADE {
+		{
+			p.SetState(1449)
+			p.Match(KuneiformParserCASCADE)
+			if p.HasError() {
+				// Recognition error - abort rule
+				goto errorExit
+			}
+		}
+
+	}
+
+errorExit:
+	if p.HasError() {
+		v := p.GetError()
+		localctx.SetException(v)
+		p.GetErrorHandler().ReportError(p, v)
+		p.GetErrorHandler().Recover(p, v)
+		p.SetError(nil)
+	}
+	p.ExitRule()
+	return localctx
+	goto errorExit // Trick to prevent compiler error if the label is not used
+}
+
 func (p *KuneiformParser) Sempred(localctx antlr.RuleContext, ruleIndex, predIndex int) bool {
 	switch ruleIndex {
 	case 52:
@@ -239,6 +239,10 @@ func (v *BaseKuneiformParserVisitor) VisitSubquery_relation(ctx *Subquery_relati
 	return v.VisitChildren(ctx)
 }
 
+func (v *BaseKuneiformParserVisitor) VisitFunction_relation(ctx *Function_relationContext) interface{} {
+	return v.VisitChildren(ctx)
+}
+
 func (v *BaseKuneiformParserVisitor) VisitJoin(ctx *JoinContext) interface{} {
 	return v.VisitChildren(ctx)
 }
@@ -455,6 +459,18 @@ func (v *BaseKuneiformParserVisitor) VisitStmt_return_next(ctx *Stmt_return_next
 	return v.VisitChildren(ctx)
 }
 
+func (v *BaseKuneiformParserVisitor) VisitStmt_declare_cursor(ctx *Stmt_declare_cursorContext) interface{} {
+	return v.VisitChildren(ctx)
+}
+
+func (v *BaseKuneiformParserVisitor) VisitStmt_fetch_cursor(ctx *Stmt_fetch_cursorContext) interface{} {
+	return v.VisitChildren(ctx)
+}
+
+func (v *BaseKuneiformParserVisitor) VisitStmt_close_cursor(ctx *Stmt_close_cursorContext) interface{} {
+	return v.VisitChildren(ctx)
+}
+
 func (v *BaseKuneiformParserVisitor) VisitVariable_or_underscore(ctx *Variable_or_underscoreContext) interface{} {
 	return v.VisitChildren(ctx)
 }
@@ -470,3 +486,11 @@ func (v *BaseKuneiformParserVisitor) VisitIf_then_block(ctx *If_then_blockContex
 func (v *BaseKuneiformParserVisitor) VisitRange(ctx *RangeContext) interface{} {
 	return v.VisitChildren(ctx)
 }
+
+func (v *BaseKuneiformParserVisitor) VisitAlter_namespace_statement(ctx *Alter_namespace_statementContext) interface{} {
+	return v.VisitChildren(ctx)
+}
+
+func (v *BaseKuneiformParserVisitor) VisitTruncate_table_statement(ctx *Truncate_table_statementContext) interface{} {
+	return v.VisitChildren(ctx)
+}
@@ -181,6 +181,9 @@ type KuneiformParserVisitor interface {
 	// Visit a parse tree produced by KuneiformParser#subquery_relation.
 	VisitSubquery_relation(ctx *Subquery_relationContext) interface{}
 
+	// Visit a parse tree produced by KuneiformParser#function_relation.
+	VisitFunction_relation(ctx *Function_relationContext) interface{}
+
 	// Visit a parse tree produced by KuneiformParser#join.
 	VisitJoin(ctx *JoinContext) interface{}
 
@@ -343,6 +346,15 @@ type KuneiformParserVisitor interface {
 	// Visit a parse tree produced by KuneiformParser#stmt_return_next.
 	VisitStmt_return_next(ctx *Stmt_return_nextContext) interface{}
 
+	// Visit a parse tree produced by KuneiformParser#stmt_declare_cursor.
+	VisitStmt_declare_cursor(ctx *Stmt_declare_cursorContext) interface{}
+
+	// Visit a parse tree produced by KuneiformParser#stmt_fetch_cursor.
+	VisitStmt_fetch_cursor(ctx *Stmt_fetch_cursorContext) interface{}
+
+	// Visit a parse tree produced by KuneiformParser#stmt_close_cursor.
+	VisitStmt_close_cursor(ctx *Stmt_close_cursorContext) interface{}
+
 	// Visit a parse tree produced by KuneiformParser#variable_or_underscore.
 	VisitVariable_or_underscore(ctx *Variable_or_underscoreContext) interface{}
 
@@ -354,4 +366,10 @@ type KuneiformParserVisitor interface {
 
 	// Visit a parse tree produced by KuneiformParser#range.
 	VisitRange(ctx *RangeContext) interface{}
+
+	// Visit a parse tree produced by KuneiformParser#alter_namespace_statement.
+	VisitAlter_namespace_statement(ctx *Alter_namespace_statementContext) interface{}
+
+	// Visit a parse tree produced by KuneiformParser#truncate_table_statement.
+	VisitTruncate_table_statement(ctx *Truncate_table_statementContext) interface{}
 }
@@ -35,6 +35,8 @@ statement:
         | create_namespace_statement
         | drop_namespace_statement
         | set_current_namespace_statement
+        | alter_namespace_statement
+        | truncate_table_statement
     )
 ;
 
@@ -195,6 +197,11 @@ drop_table_statement:
     DROP TABLE (IF EXISTS)? tables=identifier_list opt_drop_behavior?
 ;
 
+// TRUNCATE is not a keyword, and is checked by the visitor
+truncate_table_statement:
+    IDENTIFIER TABLE identifier CASCADE?
+;
+
 alter_table_statement:
     ALTER TABLE table=identifier
     alter_table_action (COMMA alter_table_action)*
@@ -211,8 +218,11 @@ alter_table_action:
     | DROP CONSTRAINT (IF EXISTS)? identifier                                        # drop_table_constraint
 ;
 
+// the optional identifier after INDEX is CONCURRENTLY, which is not a keyword.
+// In CREATE INDEX idx ON ..., it is the name of the index, which is resolved
+// by the visitor.
 create_index_statement:
-    CREATE UNIQUE? INDEX (IF NOT EXISTS)? name=identifier?
+    CREATE UNIQUE? INDEX IDENTIFIER? (IF NOT EXISTS)? name=identifier?
     ON table=identifier LPAREN  columns=identifier_list RPAREN
 ;
 
@@ -282,6 +292,12 @@ set_current_namespace_statement:
     SET CURRENT NAMESPACE TO identifier
 ;
 
+// READ, ONLY, WRITE and CONFIG are not keywords, and are checked by the visitor
+alter_namespace_statement:
+    ALTER NAMESPACE identifier SET IDENTIFIER
+    (IDENTIFIER | STRING_ EQUALS STRING_)
+;
+
 select_statement:
     select_core
     (compound_operator select_core)*
@@ -315,7 +331,9 @@ relation:
     // aliases are technically required in Kuneiform for subquery and function calls,
     // but we allow it to pass here since it is standard SQL to not require it, and
     // we can throw a better error message after parsing.
-    | LPAREN select_statement RPAREN (AS? alias=identifier)?    # subquery_relation
+    // the optional identifier is LATERAL, which is not a keyword
+    | IDENTIFIER? LPAREN select_statement RPAREN (AS? alias=identifier)?    # subquery_relation
+    | function_name=identifier LPAREN (args=sql_expr_list)? RPAREN (AS? alias=identifier)?  # function_relation
 ;
 
 join:
@@ -481,6 +499,11 @@ action_statement:
     | (BREAK|CONTINUE) SCOL                                                                                        # stmt_loop_control
     | RETURN (action_expr_list|sql_statement)? SCOL                                                   # stmt_return
     | RETURN NEXT action_expr_list SCOL                                                              # stmt_return_next
+    // the DECLARE, CURSOR, FETCH and CLOSE keywords are matched as identifiers, so
+    // that they can still be used as names. They are checked when the statement is visited.
+    | IDENTIFIER cursor=identifier IDENTIFIER FOR sql_statement SCOL                                  # stmt_declare_cursor
+    | IDENTIFIER NEXT FROM cursor=identifier (INTO variable_or_underscore (COMMA variable_or_underscore)*)? SCOL # stmt_fetch_cursor
+    | IDENTIFIER cursor=identifier SCOL                                                               # stmt_close_cursor
 ;
 
 variable_or_underscore:
@@ -0,0 +1,121 @@
+package parse
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/antlr4-go/antlr/v4"
+)
+
+// importDirectives returns the files imported by the @import directives in
+// the comments of the token stream, in the order they are written. An import
+// directive is a comment with a quoted path, and can be anywhere in a schema:
+//
+//	-- @import "tables.kf"
+//
+// Malformed directives are added as errors to the error listener.
+func importDirectives(tokens *antlr.CommonTokenStream, errs *errorListener) []string {
+	var imports []string
+	for _, tok := range tokens.GetAllTokens() {
+		text, ok := commentText(tok)
+		if !ok {
+			continue
+		}
+		arg, ok := importDirective(text)
+		if !ok {
+			continue
+		}
+
+		path, err := strconv.Unquote(arg)
+		if err != nil || path == "" || !strings.HasPrefix(arg, `"`) {
+			errs.TokenErr(tok, ErrImport, `@import expects a quoted path, e.g. @import "tables.kf", got %s`, arg)
+			continue
+		}
+		imports = append(imports, path)
+	}
+
+	return imports
+}
+
+// importDirective returns the argument of an @import directive, if the text of
+// a comment is one.
+func importDirective(text string) (arg string, ok bool) {
+	name, arg, _ := strings.Cut(text, " ")
+	if !strings.EqualFold(name, "@import") {
+		return "", false
+	}
+	return strings.TrimSpace(arg), true
+}
+
+// ParseWithImports parses the schema file at path, along with the files it
+// imports with @import directives. Imported paths are relative to the
+// directory of the file that imports them, and each file is read with
+// resolver, which is usually os.ReadFile. The statements of imported files
+// come before the statements of the files that import them, and a file that
+// is imported more than once is only included once. An error is returned if
+// files import each other.
+//
+// Positions of the returned statements are relative to the file they were
+// parsed from.
+func ParseWithImports(path string, resolver func(name string) ([]byte, error)) ([]TopLevelStatement, error) {
+	r := &importResolver{
+		resolver: resolver,
+		parsed:   make(map[string]bool),
+	}
+	if err := r.parse(filepath.Clean(path)); err != nil {
+		return nil, err
+	}
+
+	return r.statements, nil
+}
+
+// importResolver parses a schema file and the files it imports.
+type importResolver struct {
+	resolver func(name string) ([]byte, error)
+	// parsed tracks the files that have been parsed.
+	parsed map[string]bool
+	// stack is the chain of imports of the file being parsed.
+	stack      []string
+	statements []TopLevelStatement
+}
+
+func (r *importResolver) parse(name string) error {
+	for i, n := range r.stack {
+		if n == name {
+			return fmt.Errorf("%w: %s", ErrCircularImport, strings.Join(append(r.stack[i:], name), " -> "))
+		}
+	}
+	if r.parsed[name] {
+		return nil
+	}
+
+	source, err := r.resolver(name)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", name, err)
+	}
+
+	res, err := ParseWithErrListener(string(source))
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	if res.Err() != nil {
+		return fmt.Errorf("%s: %w", name, res.Err())
+	}
+
+	r.stack = append(r.stack, name)
+	for _, imp := range res.Imports {
+		if !filepath.IsAbs(imp) {
+			imp = filepath.Join(filepath.Dir(name), imp)
+		}
+		if err = r.parse(filepath.Clean(imp)); err != nil {
+			return err
+		}
+	}
+	r.stack = r.stack[:len(r.stack)-1]
+
+	r.parsed[name] = true
+	r.statements = append(r.statements, res.Statements...)
+	return nil
+}
@@ -0,0 +1,102 @@
+package parse_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kwilteam/kwil-db/node/engine/parse"
+	"github.com/stretchr/testify/require"
+)
+
+// mapResolver resolves files from a map of paths to their contents.
+func mapResolver(files map[string]string) func(string) ([]byte, error) {
+	return func(name string) ([]byte, error) {
+		src, ok := files[filepath.ToSlash(name)]
+		if !ok {
+			return nil, fmt.Errorf("%s: %w", name, os.ErrNotExist)
+		}
+		return []byte(src), nil
+	}
+}
+
+func Test_ParseWithImports(t *testing.T) {
+	files := map[string]string{
+		"schema/main.kf": `-- @import "tables/posts.kf"
+-- @import "tables/users.kf"
+CREATE ACTION get_posts($author int) public view returns table(id int) {
+	RETURN SELECT id FROM posts WHERE author = $author;
+};`,
+		"schema/tables/users.kf": `CREATE TABLE users (id int primary key);`,
+		// posts also imports users, which is only included once
+		"schema/tables/posts.kf": `/* @import "users.kf" */
+CREATE TABLE posts (id int primary key, author int references users(id));`,
+	}
+
+	stmts, err := parse.ParseWithImports("schema/main.kf", mapResolver(files))
+	require.NoError(t, err)
+	require.Len(t, stmts, 3)
+
+	// imported statements come before the statements that depend on them
+	require.Equal(t, "users", stmts[0].(*parse.CreateTableStatement).Name)
+	require.Equal(t, "posts", stmts[1].(*parse.CreateTableStatement).Name)
+	require.Equal(t, "get_posts", stmts[2].(*parse.CreateActionStatement).Name)
+
+	// the merged schema is valid, since posts references users
+	require.Empty(t, parse.ValidateSchema(stmts))
+}
+
+func Test_ParseWithImportsErrors(t *testing.T) {
+	t.Run("circular import", func(t *testing.T) {
+		files := map[string]string{
+			"a.kf": `-- @import "b.kf"
+CREATE TABLE a (id int primary key);`,
+			"b.kf": `-- @import "a.kf"
+CREATE TABLE b (id int primary key);`,
+		}
+
+		_, err := parse.ParseWithImports("a.kf", mapResolver(files))
+		require.ErrorIs(t, err, parse.ErrCircularImport)
+		require.ErrorContains(t, err, "a.kf -> b.kf -> a.kf")
+	})
+
+	t.Run("self import", func(t *testing.T) {
+		files := map[string]string{
+			"a.kf": `-- @import "./a.kf"
+CREATE TABLE a (id int primary key);`,
+		}
+
+		_, err := parse.ParseWithImports("a.kf", mapResolver(files))
+		require.ErrorIs(t, err, parse.ErrCircularImport)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		files := map[string]string{
+			"a.kf": `-- @import "missing.kf"
+CREATE TABLE a (id int primary key);`,
+		}
+
+		_, err := parse.ParseWithImports("a.kf", mapResolver(files))
+		require.True(t, errors.Is(err, os.ErrNotExist))
+	})
+
+	t.Run("malformed directive", func(t *testing.T) {
+		_, err := parse.Parse(`-- @import missing_quotes.kf
+CREATE TABLE a (id int primary key);`)
+		require.ErrorIs(t, err, parse.ErrImport)
+	})
+}
+
+func Test_ParseImportDirectives(t *testing.T) {
+	// Parse does not resolve imports, but returns them in the result
+	res, err := parse.ParseWithErrListener(`-- @import "a.kf"
+-- a comment that is not a directive
+// @import "b.kf"
+CREATE TABLE t (id int primary key);`)
+	require.NoError(t, err)
+	require.NoError(t, res.Err())
+	require.Equal(t, []string{"a.kf", "b.kf"}, res.Imports)
+	require.Len(t, res.Statements, 1)
+}
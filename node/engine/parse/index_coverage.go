@@ -0,0 +1,134 @@
+package parse
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/kwilteam/kwil-db/node/engine"
+)
+
+// IndexCoverageWarning is a query of an action that filters a table by a
+// combination of columns that no index of the table covers.
+type IndexCoverageWarning struct {
+	// Action is the name of the action.
+	Action string
+	// Table is the name of the table.
+	Table string
+	// Columns are the columns compared for equality, in the order they
+	// appear in the WHERE clause.
+	Columns []string
+}
+
+func (w IndexCoverageWarning) String() string {
+	return fmt.Sprintf("Consider adding index on %s(%s) (action %s filters by columns not covered by an index)",
+		w.Table, strings.Join(w.Columns, ", "), w.Action)
+}
+
+// IndexCoverageWarnings returns a warning for each combination of two or more
+// columns of a table that the WHERE clauses of an action's queries compare
+// for equality, when no primary key, index, or unique constraint of the table
+// has those columns as its leading columns. Tables are read with getTable,
+// where an empty namespace is the namespace of the action. Columns of joined
+// queries are only considered if they are qualified.
+func IndexCoverageWarnings(act *CreateActionStatement, getTable func(namespace, table string) (*engine.Table, error)) []IndexCoverageWarning {
+	var warnings []IndexCoverageWarning
+	check := func(rel *RelationTable, joined bool, where Expression) {
+		if where == nil {
+			return
+		}
+		tbl, err := getTable(rel.Namespace, rel.Table)
+		if err != nil {
+			return
+		}
+
+		qualifier := rel.Table
+		if rel.Alias != "" {
+			qualifier = rel.Alias
+		}
+
+		var columns []string
+		for _, col := range equalityColumns(where) {
+			if (col.Table == "" && joined) || (col.Table != "" && col.Table != qualifier) {
+				continue
+			}
+			if _, ok := tbl.Column(col.Column); ok && !slices.Contains(columns, col.Column) {
+				columns = append(columns, col.Column)
+			}
+		}
+
+		if len(columns) < 2 || indexCoversColumns(tbl, columns) {
+			return
+		}
+		warning := IndexCoverageWarning{Action: act.Name, Table: tbl.Name, Columns: columns}
+		if !slices.ContainsFunc(warnings, func(w IndexCoverageWarning) bool {
+			return w.Table == warning.Table && slices.Equal(w.Columns, warning.Columns)
+		}) {
+			warnings = append(warnings, warning)
+		}
+	}
+
+	RecursivelyVisitPositions(act.Statements, func(gp GetPositioner) {
+		switch node := gp.(type) {
+		case *SelectCore:
+			if rel, ok := node.From.(*RelationTable); ok {
+				check(rel, len(node.Joins) > 0, node.Where)
+			}
+		case *UpdateStatement:
+			check(&RelationTable{Table: node.Table, Alias: node.Alias}, node.From != nil, node.Where)
+		case *DeleteStatement:
+			check(&RelationTable{Table: node.Table, Alias: node.Alias}, node.From != nil, node.Where)
+		}
+	})
+
+	return warnings
+}
+
+// equalityColumns returns the columns that are compared for equality by the
+// conditions that are joined with AND in an expression.
+func equalityColumns(expr Expression) []*ExpressionColumn {
+	switch expr := expr.(type) {
+	case *ExpressionParenthesized:
+		return equalityColumns(expr.Inner)
+	case *ExpressionLogical:
+		if expr.Operator != LogicalOperatorAnd {
+			return nil
+		}
+		return append(equalityColumns(expr.Left), equalityColumns(expr.Right)...)
+	case *ExpressionComparison:
+		if expr.Operator != ComparisonOperatorEqual {
+			return nil
+		}
+		if col, ok := expr.Left.(*ExpressionColumn); ok {
+			return []*ExpressionColumn{col}
+		}
+		if col, ok := expr.Right.(*ExpressionColumn); ok {
+			return []*ExpressionColumn{col}
+		}
+	}
+	return nil
+}
+
+// indexCoversColumns returns true if the columns are the leading columns, in
+// any order, of the table's primary key, or of one of its indexes or unique
+// constraints.
+func indexCoversColumns(tbl *engine.Table, columns []string) bool {
+	var pk []string
+	for _, col := range tbl.PrimaryKeyCols() {
+		pk = append(pk, col.Name)
+	}
+	if indexCovers(pk, columns) {
+		return true
+	}
+	for _, idx := range tbl.Indexes {
+		if indexCovers(idx.Columns, columns) {
+			return true
+		}
+	}
+	for _, constraint := range tbl.Constraints {
+		if constraint.Type == engine.ConstraintUnique && indexCovers(constraint.Columns, columns) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,93 @@
+package parse_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/kwilteam/kwil-db/core/types"
+	"github.com/kwilteam/kwil-db/node/engine"
+	"github.com/kwilteam/kwil-db/node/engine/parse"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_IndexCoverageWarnings(t *testing.T) {
+	tables := map[string]*engine.Table{
+		"users": {
+			Name: "users",
+			Columns: []*engine.Column{
+				{Name: "id", DataType: types.IntType, IsPrimaryKey: true},
+				{Name: "org", DataType: types.TextType},
+				{Name: "name", DataType: types.TextType},
+				{Name: "age", DataType: types.IntType},
+			},
+			Indexes: []*engine.Index{
+				{Name: "users_org_idx", Columns: []string{"org"}, Type: engine.BTREE},
+				{Name: "users_name_idx", Columns: []string{"name"}, Type: engine.BTREE},
+				{Name: "users_age_name_idx", Columns: []string{"age", "name", "org"}, Type: engine.BTREE},
+			},
+		},
+	}
+	getTable := func(namespace, table string) (*engine.Table, error) {
+		if tbl, ok := tables[table]; ok && namespace == "" {
+			return tbl, nil
+		}
+		return nil, fmt.Errorf("unknown table %s", table)
+	}
+
+	tests := []struct {
+		name string
+		body string
+		want []parse.IndexCoverageWarning
+	}{
+		{
+			name: "two columns with only single column indexes",
+			body: `SELECT * FROM users WHERE org = $org AND name = $name;`,
+			want: []parse.IndexCoverageWarning{{Action: "act", Table: "users", Columns: []string{"org", "name"}}},
+		},
+		{
+			name: "columns covered by the leading columns of an index",
+			body: `SELECT * FROM users WHERE name = $name AND (age = 1);`,
+		},
+		{
+			name: "columns covered by the primary key",
+			body: `SELECT * FROM users WHERE id = 1 AND org = $org;`,
+			want: []parse.IndexCoverageWarning{{Action: "act", Table: "users", Columns: []string{"id", "org"}}},
+		},
+		{
+			name: "or is not an index combination",
+			body: `SELECT * FROM users WHERE org = $org OR name = $name;`,
+		},
+		{
+			name: "qualified columns of a joined table",
+			body: `SELECT * FROM users u JOIN users u2 ON u.id = u2.id WHERE u.org = $org AND u.name = $name AND org = 'a';`,
+			want: []parse.IndexCoverageWarning{{Action: "act", Table: "users", Columns: []string{"org", "name"}}},
+		},
+		{
+			name: "update and delete in nested blocks",
+			body: `if $org != '' {
+				UPDATE users SET age = 1 WHERE org = $org AND name = $name;
+			}
+			for $row IN SELECT * FROM users WHERE age = 1 {
+				DELETE FROM users WHERE name = $name AND org = $row.org;
+			}`,
+			want: []parse.IndexCoverageWarning{
+				{Action: "act", Table: "users", Columns: []string{"org", "name"}},
+				{Action: "act", Table: "users", Columns: []string{"name", "org"}},
+			},
+		},
+		{
+			name: "unknown table",
+			body: `SELECT * FROM posts WHERE org = $org AND name = $name;`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := parse.Parse(`CREATE ACTION act($org text, $name text) public { ` + tt.body + ` };`)
+			require.NoError(t, err)
+
+			got := parse.IndexCoverageWarnings(res[0].(*parse.CreateActionStatement), getTable)
+			require.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package parse
+
+import (
+	"fmt"
+
+	"github.com/kwilteam/kwil-db/node/engine"
+)
+
+// LintSeverity is the severity of a lint issue.
+type LintSeverity string
+
+const (
+	// LintError is an issue that prevents the schema from being used.
+	LintError LintSeverity = "error"
+	// LintWarning is an issue that should be reviewed, such as a missing
+	// index.
+	LintWarning LintSeverity = "warning"
+)
+
+// LintIssue is an issue found by LintSchema.
+type LintIssue struct {
+	Severity LintSeverity `json:"severity"`
+	Message  string       `json:"message"`
+	// Position is the position of the statement or clause in the schema. It
+	// can be nil.
+	Position *Position `json:"position,omitempty"`
+}
+
+// LintRules are the configurable rules of LintSchema.
+type LintRules struct {
+	// MaxColumnsPerTable is the maximum number of columns of a table. If it is
+	// zero, the number of columns is not limited.
+	MaxColumnsPerTable int
+	// RequireIndexOnForeignKey makes foreign keys whose columns are not
+	// covered by an index errors, rather than warnings.
+	RequireIndexOnForeignKey bool
+}
+
+// LintSchema checks a schema before it is deployed. The errors of
+// ValidateSchema, and tables with more columns than allowed, are errors.
+// Foreign keys without an index on their columns, and the index coverage
+// warnings of the schema's actions, are warnings.
+func LintSchema(schema []TopLevelStatement, rules LintRules) []*LintIssue {
+	var issues []*LintIssue
+	for _, err := range ValidateSchema(schema) {
+		issues = append(issues, &LintIssue{Severity: LintError, Message: err.Message, Position: err.Position})
+	}
+
+	var indexes []TopLevelStatement
+	for _, stmt := range schema {
+		if idx, ok := stmt.(*CreateIndexStatement); ok {
+			indexes = append(indexes, idx)
+		}
+	}
+
+	fkSeverity := LintWarning
+	if rules.RequireIndexOnForeignKey {
+		fkSeverity = LintError
+	}
+
+	tables := tablesFromSchema(schema)
+	getTable := func(namespace, table string) (*engine.Table, error) {
+		tbl, ok := tables[table]
+		if !ok || namespace != "" {
+			return nil, fmt.Errorf("%w: %s", engine.ErrUnknownTable, table)
+		}
+		return tbl, nil
+	}
+
+	for _, stmt := range schema {
+		switch stmt := stmt.(type) {
+		case *CreateTableStatement:
+			if rules.MaxColumnsPerTable > 0 && len(stmt.Columns) > rules.MaxColumnsPerTable {
+				issues = append(issues, &LintIssue{
+					Severity: LintError,
+					Message:  fmt.Sprintf("table %s has %d columns, more than the maximum of %d", stmt.Name, len(stmt.Columns), rules.MaxColumnsPerTable),
+					Position: stmt.GetPosition(),
+				})
+			}
+
+			// the warnings are checked per table, so that they have its position
+			for _, warning := range ForeignKeyIndexWarnings(append([]TopLevelStatement{stmt}, indexes...)) {
+				issues = append(issues, &LintIssue{Severity: fkSeverity, Message: warning, Position: stmt.GetPosition()})
+			}
+		case *CreateActionStatement:
+			for _, warning := range IndexCoverageWarnings(stmt, getTable) {
+				issues = append(issues, &LintIssue{Severity: LintWarning, Message: warning.String(), Position: stmt.GetPosition()})
+			}
+		}
+	}
+
+	return issues
+}
+
+// tablesFromSchema returns the tables created by a schema, with the columns,
+// indexes and unique constraints that index coverage is checked against.
+func tablesFromSchema(schema []TopLevelStatement) map[string]*engine.Table {
+	tables := make(map[string]*engine.Table)
+	for _, stmt := range schema {
+		cts, ok := stmt.(*CreateTableStatement)
+		if !ok {
+			continue
+		}
+
+		tbl := &engine.Table{Name: cts.Name, Constraints: make(map[string]*engine.Constraint)}
+		var pk []string
+		for _, col := range cts.Columns {
+			isPK := columnIsPrimaryKey(cts, col)
+			if isPK {
+				pk = append(pk, col.Name)
+			}
+			tbl.Columns = append(tbl.Columns, &engine.Column{Name: col.Name, DataType: col.Type, IsPrimaryKey: isPK})
+
+			for _, c := range col.Constraints {
+				if _, ok := c.(*UniqueInlineConstraint); ok {
+					tbl.Constraints[col.Name+"_unique"] = &engine.Constraint{Type: engine.ConstraintUnique, Columns: []string{col.Name}}
+				}
+			}
+		}
+		for i, c := range cts.Constraints {
+			switch c := c.Constraint.(type) {
+			case *PrimaryKeyOutOfLineConstraint:
+				// the order of the primary key's columns is that of its index
+				pk = c.Columns
+			case *UniqueOutOfLineConstraint:
+				tbl.Constraints[fmt.Sprintf("unique_%d", i)] = &engine.Constraint{Type: engine.ConstraintUnique, Columns: c.Columns}
+			}
+		}
+		if len(pk) > 0 {
+			tbl.Indexes = append(tbl.Indexes, &engine.Index{Name: cts.Name + "_pkey", Columns: pk, Type: engine.PRIMARY})
+		}
+
+		tables[cts.Name] = tbl
+	}
+
+	for _, stmt := range schema {
+		idx, ok := stmt.(*CreateIndexStatement)
+		if !ok {
+			continue
+		}
+		if tbl, ok := tables[idx.On]; ok {
+			indexType := engine.BTREE
+			if idx.Type == IndexTypeUnique {
+				indexType = engine.UNIQUE_BTREE
+			}
+			tbl.Indexes = append(tbl.Indexes, &engine.Index{Name: idx.Name, Columns: idx.Columns, Type: indexType})
+		}
+	}
+
+	return tables
+}
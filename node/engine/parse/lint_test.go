@@ -0,0 +1,89 @@
+package parse_test
+
+import (
+	"testing"
+
+	"github.com/kwilteam/kwil-db/node/engine/parse"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_LintSchema(t *testing.T) {
+	const fkSchema = `CREATE TABLE users (id int primary key, org text, name text);
+CREATE TABLE posts (id int primary key, author int references users(id));`
+
+	tests := []struct {
+		name   string
+		schema string
+		rules  parse.LintRules
+		want   []*parse.LintIssue
+	}{
+		{
+			name: "valid schema",
+			schema: `CREATE TABLE users (id int primary key, org text, name text);
+			CREATE INDEX org_name_idx ON users(org, name);
+			CREATE ACTION get_user($org text, $name text) public view {
+				SELECT * FROM users WHERE org = $org AND name = $name;
+			};`,
+		},
+		{
+			name:   "missing foreign key index is a warning",
+			schema: fkSchema,
+			want: []*parse.LintIssue{{
+				Severity: parse.LintWarning,
+				Message:  "table posts: foreign key on (author) has no index on its columns",
+			}},
+		},
+		{
+			name:   "missing foreign key index is an error if required",
+			schema: fkSchema,
+			rules:  parse.LintRules{RequireIndexOnForeignKey: true},
+			want: []*parse.LintIssue{{
+				Severity: parse.LintError,
+				Message:  "table posts: foreign key on (author) has no index on its columns",
+			}},
+		},
+		{
+			name:   "too many columns",
+			schema: `CREATE TABLE users (id int primary key, org text, name text);`,
+			rules:  parse.LintRules{MaxColumnsPerTable: 2},
+			want: []*parse.LintIssue{{
+				Severity: parse.LintError,
+				Message:  "table users has 3 columns, more than the maximum of 2",
+			}},
+		},
+		{
+			name:   "invalid schema",
+			schema: `CREATE INDEX ON users(name);`,
+			want: []*parse.LintIssue{{
+				Severity: parse.LintError,
+				Message:  "index on unknown table users",
+			}},
+		},
+		{
+			name: "uncovered index combination",
+			schema: `CREATE TABLE users (id int primary key, org text, name text);
+			CREATE ACTION get_user($org text, $name text) public view {
+				SELECT * FROM users WHERE org = $org AND name = $name;
+			};`,
+			want: []*parse.LintIssue{{
+				Severity: parse.LintWarning,
+				Message:  "Consider adding index on users(org, name) (action get_user filters by columns not covered by an index)",
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, err := parse.Parse(tt.schema)
+			require.NoError(t, err)
+
+			issues := parse.LintSchema(schema, tt.rules)
+			for _, issue := range issues {
+				require.NotNil(t, issue.Position)
+				require.NotNil(t, issue.Position.StartLine)
+				issue.Position = nil
+			}
+			require.Equal(t, tt.want, issues)
+		})
+	}
+}
@@ -23,6 +23,9 @@ type ParseResult struct {
 	Statements []TopLevelStatement `json:"statements,omitempty"`
 	// ParseErrs is the error listener that contains all the errors that occurred during parsing.
 	ParseErrs ParseErrs `json:"parse_errs,omitempty"`
+	// Imports are the paths of the @import directives, in the order they were
+	// encountered. They are not resolved.
+	Imports []string `json:"imports,omitempty"`
 }
 
 func (r *ParseResult) Err() error {
@@ -30,6 +33,8 @@ func (r *ParseResult) Err() error {
 }
 
 // Parse parses a statement or set of statements separated by semicolons.
+// @import directives are validated, but the files they import are not parsed;
+// use ParseWithImports for that.
 func Parse(sql string) (t []TopLevelStatement, err error) {
 	defer func() {
 		if r := recover(); r != nil {
@@ -74,6 +79,7 @@ func ParseWithErrListener(sql string) (p *ParseResult, err error) {
 	}()
 
 	p.Statements = parser.Entry().Accept(parseVisitor).([]TopLevelStatement)
+	p.Imports = importDirectives(parseVisitor.tokens, errLis)
 
 	return p, nil
 }
@@ -132,7 +138,7 @@ func setupParser(sql string) (parser *gen.KuneiformParser, errList *errorListene
 		return nil
 	}
 
-	parserVisitor = newSchemaVisitor(stream, errList)
+	parserVisitor = newSchemaVisitor(stream, tokens, errList)
 
 	return parser, errList, parserVisitor, deferFn, err
 }
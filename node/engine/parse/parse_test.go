@@ -1,6 +1,7 @@
 package parse
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -277,6 +278,193 @@ func Test_DDL(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "create table with sensitive columns",
+			sql: `CREATE TABLE users (
+		-- a regular comment
+		id int primary key,
+		-- @sensitive(hash)
+		email text,
+		/* @sensitive(partial(3)) */
+		name text,
+		// @sensitive(redact)
+		age int
+		);`,
+			want: &CreateTableStatement{
+				Name: "users",
+				Columns: []*Column{
+					{
+						Name: "id",
+						Type: types.IntType,
+						Constraints: []InlineConstraint{
+							&PrimaryKeyInlineConstraint{},
+						},
+					},
+					{
+						Name:              "email",
+						Type:              types.TextType,
+						SensitivityPolicy: &engine.SensitivityPolicy{Mask: engine.MaskHash},
+					},
+					{
+						Name:              "name",
+						Type:              types.TextType,
+						SensitivityPolicy: &engine.SensitivityPolicy{Mask: engine.MaskPartial, PartialLength: 3},
+					},
+					{
+						Name:              "age",
+						Type:              types.IntType,
+						SensitivityPolicy: &engine.SensitivityPolicy{Mask: engine.MaskRedact},
+					},
+				},
+			},
+		},
+		{
+			name: "hash mask on non-text column",
+			sql: `CREATE TABLE users (id int primary key,
+		-- @sensitive(hash)
+		age int);`,
+			err: ErrAnnotation,
+		},
+		{
+			name: "unknown mask function",
+			sql: `CREATE TABLE users (id int primary key,
+		-- @sensitive(scramble)
+		name text);`,
+			err: ErrAnnotation,
+		},
+		{
+			name: "create soft delete table",
+			sql: `-- @soft_delete
+		CREATE TABLE posts (id int primary key);`,
+			want: &CreateTableStatement{
+				Name: "posts",
+				Columns: []*Column{
+					{
+						Name: "id",
+						Type: types.IntType,
+						Constraints: []InlineConstraint{
+							&PrimaryKeyInlineConstraint{},
+						},
+					},
+				},
+				SoftDelete: true,
+			},
+		},
+		{
+			name: "create history table",
+			sql:  `/* @history */ CREATE TABLE posts (id int primary key);`,
+			want: &CreateTableStatement{
+				Name: "posts",
+				Columns: []*Column{
+					{
+						Name: "id",
+						Type: types.IntType,
+						Constraints: []InlineConstraint{
+							&PrimaryKeyInlineConstraint{},
+						},
+					},
+				},
+				History: true,
+			},
+		},
+		{
+			name: "create table with optimizer hints",
+			sql: `-- @optimizer_hint(enable_seqscan, false)
+		-- @optimizer_hint(ENABLE_NESTLOOP, FALSE)
+		CREATE TABLE posts (id int primary key);`,
+			want: &CreateTableStatement{
+				Name: "posts",
+				Columns: []*Column{
+					{
+						Name: "id",
+						Type: types.IntType,
+						Constraints: []InlineConstraint{
+							&PrimaryKeyInlineConstraint{},
+						},
+					},
+				},
+				OptimizerHints: []*engine.OptimizerHint{
+					{Name: "enable_seqscan", Value: "false"},
+					{Name: "enable_nestloop", Value: "false"},
+				},
+			},
+		},
+		{
+			name: "unsupported optimizer hint",
+			sql: `-- @optimizer_hint(work_mem, 1000)
+		CREATE TABLE posts (id int primary key);`,
+			err: ErrAnnotation,
+		},
+		{
+			name: "optimizer hint with non-boolean value",
+			sql: `-- @optimizer_hint(enable_seqscan, never)
+		CREATE TABLE posts (id int primary key);`,
+			err: ErrAnnotation,
+		},
+		{
+			name: "soft delete with arguments",
+			sql: `-- @soft_delete(true)
+		CREATE TABLE posts (id int primary key);`,
+			err: ErrAnnotation,
+		},
+		{
+			name: "create event sourced namespace",
+			sql: `-- @event_sourced
+		CREATE NAMESPACE IF NOT EXISTS ledger;`,
+			want: &CreateNamespaceStatement{
+				IfNotExists:  true,
+				Namespace:    "ledger",
+				EventSourced: true,
+			},
+		},
+		{
+			name: "event sourced with arguments",
+			sql: `-- @event_sourced(true)
+		CREATE NAMESPACE ledger;`,
+			err: ErrAnnotation,
+		},
+		{
+			name: "create test namespace",
+			sql: `-- @test_namespace
+		CREATE NAMESPACE ledger_test;`,
+			want: &CreateNamespaceStatement{
+				Namespace: "ledger_test",
+				Test:      true,
+			},
+		},
+		{
+			name: "alter namespace set read only",
+			sql:  `ALTER NAMESPACE ledger SET READ ONLY;`,
+			want: &AlterNamespaceStatement{
+				Namespace: "ledger",
+				ReadOnly:  true,
+			},
+		},
+		{
+			name: "alter namespace set read write",
+			sql:  `alter namespace ledger set read write;`,
+			want: &AlterNamespaceStatement{
+				Namespace: "ledger",
+			},
+		},
+		{
+			name: "alter namespace unknown mode",
+			sql:  `ALTER NAMESPACE ledger SET READ SOMETIMES;`,
+			err:  ErrSyntax,
+		},
+		{
+			name: "alter namespace set config",
+			sql:  `ALTER NAMESPACE ledger SET CONFIG 'fee_rate' = '0.25';`,
+			want: &AlterNamespaceStatement{
+				Namespace: "ledger",
+				Config:    &NamespaceConfigValue{Key: "fee_rate", Value: "0.25"},
+			},
+		},
+		{
+			name: "alter namespace set unknown setting",
+			sql:  `ALTER NAMESPACE ledger SET OPTION 'fee_rate' = '0.25';`,
+			err:  ErrSyntax,
+		},
 		{
 			name: "alter table add column constraint NOT NULL",
 			sql:  `ALTER TABLE user ALTER COLUMN name SET NOT NULL;`,
@@ -463,6 +651,26 @@ func Test_DDL(t *testing.T) {
 				Behavior: DropBehaviorRestrict,
 			},
 		},
+		{
+			name: "truncate table",
+			sql:  `TRUNCATE TABLE users;`,
+			want: &TruncateTableStatement{
+				Table: "users",
+			},
+		},
+		{
+			name: "truncate table cascade",
+			sql:  `truncate table users cascade;`,
+			want: &TruncateTableStatement{
+				Table:   "users",
+				Cascade: true,
+			},
+		},
+		{
+			name: "truncate table with unknown keyword",
+			sql:  `TRUNCATED TABLE users;`,
+			err:  ErrSyntax,
+		},
 		{
 			name: "create index",
 			sql:  `CREATE INDEX abc ON user(name);`,
@@ -503,6 +711,34 @@ func Test_DDL(t *testing.T) {
 				Type:        IndexTypeBTree,
 			},
 		},
+		{
+			name: "create index concurrently",
+			sql:  `CREATE INDEX CONCURRENTLY IF NOT EXISTS abc ON user(name);`,
+			want: &CreateIndexStatement{
+				IfNotExists:  true,
+				Name:         "abc",
+				On:           "user",
+				Columns:      []string{"name"},
+				Type:         IndexTypeBTree,
+				Concurrently: true,
+			},
+		},
+		{
+			// like in Postgres, concurrently is never the name of the index
+			name: "create index concurrently with no name",
+			sql:  `CREATE INDEX concurrently ON user(name);`,
+			want: &CreateIndexStatement{
+				On:           "user",
+				Columns:      []string{"name"},
+				Type:         IndexTypeBTree,
+				Concurrently: true,
+			},
+		},
+		{
+			name: "create index with unknown keyword",
+			sql:  `CREATE INDEX CONCURRENT abc ON user(name);`,
+			err:  ErrSyntax,
+		},
 		{
 			name: "drop index",
 			sql:  `DROP INDEX abc;`,
@@ -542,6 +778,10 @@ func Test_DDL(t *testing.T) {
 
 			require.Len(t, res.Statements, 1)
 
+			if cts, ok := res.Statements[0].(*CreateTableStatement); ok {
+				tt.want.(*CreateTableStatement).Raw = cts.Raw
+			}
+
 			assertPositionsAreSet(t, res.Statements[0])
 
 			if !deepCompare(tt.want, res.Statements[0]) {
@@ -955,6 +1195,87 @@ func Test_SQL(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "table function",
+			sql:  `SELECT s.generate_series FROM generate_series(1, 5, 1) AS s;`,
+			want: &SQLStatement{
+				SQL: &SelectStatement{
+					SelectCores: []*SelectCore{
+						{
+							Columns: []ResultColumn{
+								&ResultColumnExpression{
+									Expression: exprColumn("s", "generate_series"),
+								},
+							},
+							From: &RelationFunctionCall{
+								Name:  "generate_series",
+								Args:  []Expression{exprLit(1), exprLit(5), exprLit(1)},
+								Alias: "s",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "lateral join",
+			sql:  `SELECT u.id, s.n FROM users AS u LEFT JOIN LATERAL (SELECT id AS n FROM posts WHERE author_id = u.id) AS s ON true;`,
+			want: &SQLStatement{
+				SQL: &SelectStatement{
+					SelectCores: []*SelectCore{
+						{
+							Columns: []ResultColumn{
+								&ResultColumnExpression{
+									Expression: exprColumn("u", "id"),
+								},
+								&ResultColumnExpression{
+									Expression: exprColumn("s", "n"),
+								},
+							},
+							From: &RelationTable{
+								Table: "users",
+								Alias: "u",
+							},
+							Joins: []*Join{
+								{
+									Type: JoinTypeLeft,
+									Relation: &RelationSubquery{
+										Subquery: &SelectStatement{
+											SelectCores: []*SelectCore{
+												{
+													Columns: []ResultColumn{
+														&ResultColumnExpression{
+															Expression: exprColumn("", "id"),
+															Alias:      "n",
+														},
+													},
+													From: &RelationTable{
+														Table: "posts",
+													},
+													Where: &ExpressionComparison{
+														Left:     exprColumn("", "author_id"),
+														Operator: ComparisonOperatorEqual,
+														Right:    exprColumn("u", "id"),
+													},
+												},
+											},
+										},
+										Alias:   "s",
+										Lateral: true,
+									},
+									On: exprLit(true),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "unknown keyword before join subquery",
+			sql:  `SELECT * FROM users AS u JOIN sideways (SELECT id FROM posts) AS s ON true;`,
+			err:  ErrSyntax,
+		},
 		{name: "non utf-8", sql: "\xbd\xb2\x3d\xbc\x20\xe2\x8c\x98;", err: ErrSyntax},
 		{
 			// this select doesn't make much sense, however
@@ -1220,6 +1541,9 @@ func cmpOpts() []cmp.Option {
 			ActionStmtLoopControl{},
 			ActionStmtReturn{},
 			ActionStmtReturnNext{},
+			ActionStmtDeclareCursor{},
+			ActionStmtFetchCursor{},
+			ActionStmtCloseCursor{},
 			LoopTermRange{},
 			LoopTermSQL{},
 			LoopTermExpression{},
@@ -1964,6 +2288,167 @@ func TestCreateActionStatements(t *testing.T) {
 			input: `CREATE ACTION duplicate_returns() PUBLIC RETURNS (id int, id text) {};`,
 			err:   ErrDuplicateResultColumnName,
 		},
+		{
+			name: "create action with distinct on",
+			input: `-- @distinct_on(ID)
+			CREATE ACTION distinct_on() PUBLIC RETURNS table(id int, name text) {};`,
+			expect: &CreateActionStatement{
+				Name:      "distinct_on",
+				Modifiers: []string{"public"},
+				Returns: &ActionReturn{
+					IsTable: true,
+					Fields: []*engine.NamedType{
+						{Name: "id", Type: types.IntType},
+						{Name: "name", Type: &types.DataType{Name: "text"}},
+					},
+				},
+				Distinct:   true,
+				DistinctOn: []string{"id"},
+			},
+		},
+		{
+			name:  "distinct on a column that is not returned",
+			input: `/* @distinct_on(age) */ CREATE ACTION distinct_on() PUBLIC RETURNS table(id int) {};`,
+			err:   ErrAnnotation,
+		},
+		{
+			name:  "distinct on an action that does not return rows",
+			input: `/* @distinct */ CREATE ACTION distinct_none() PUBLIC {};`,
+			err:   ErrAnnotation,
+		},
+		{
+			name: "distinct and distinct on",
+			input: `-- @distinct
+			-- @distinct_on(id)
+			CREATE ACTION distinct_both() PUBLIC RETURNS table(id int) {};`,
+			err: ErrAnnotation,
+		},
+		{
+			name: "create idempotent action",
+			input: `-- @idempotent
+			CREATE ACTION pay($amount int) PUBLIC {};`,
+			expect: &CreateActionStatement{
+				Name:      "pay",
+				Modifiers: []string{"public"},
+				Parameters: []*engine.NamedType{
+					{Name: "$amount", Type: types.IntType},
+				},
+				Idempotent: true,
+			},
+		},
+		{
+			name:  "idempotent view action",
+			input: `/* @idempotent */ CREATE ACTION get_balance() PUBLIC VIEW {};`,
+			err:   ErrAnnotation,
+		},
+		{
+			name:  "idempotent with arguments",
+			input: `/* @idempotent(10) */ CREATE ACTION pay() PUBLIC {};`,
+			err:   ErrAnnotation,
+		},
+		{
+			name: "create action with a cursor",
+			input: `
+				CREATE ACTION read_users() PUBLIC {
+					declare users_cur cursor for select id, name from users;
+					FETCH NEXT FROM users_cur INTO $id, _;
+					fetch next from users_cur;
+					close users_cur;
+				};
+			`,
+			expect: &CreateActionStatement{
+				Name:      "read_users",
+				Modifiers: []string{"public"},
+				Statements: []ActionStmt{
+					&ActionStmtDeclareCursor{
+						Name: "users_cur",
+						Query: &SQLStatement{
+							SQL: &SelectStatement{
+								SelectCores: []*SelectCore{
+									{
+										Columns: []ResultColumn{
+											&ResultColumnExpression{
+												Expression: exprColumn("", "id"),
+											},
+											&ResultColumnExpression{
+												Expression: exprColumn("", "name"),
+											},
+										},
+										From: &RelationTable{
+											Table: "users",
+										},
+									},
+								},
+							},
+						},
+					},
+					&ActionStmtFetchCursor{
+						Name: "users_cur",
+						Into: []*ExpressionVariable{
+							{Name: "$id", Prefix: VariablePrefixDollar},
+							nil,
+						},
+					},
+					&ActionStmtFetchCursor{
+						Name: "users_cur",
+					},
+					&ActionStmtCloseCursor{
+						Name: "users_cur",
+					},
+				},
+			},
+		},
+		{
+			name: "cursor for an insert",
+			input: `CREATE ACTION insert_cursor() PUBLIC {
+				declare c cursor for insert into users (id) values (1);
+			};`,
+			err: ErrSyntax,
+		},
+		{
+			name: "misspelled cursor keyword",
+			input: `CREATE ACTION misspelled_cursor() PUBLIC {
+				declare c cursor for select id from users;
+				fetc next from c into $id;
+			};`,
+			err: ErrSyntax,
+		},
+		{
+			name: "create test action",
+			input: `-- @test
+			CREATE ACTION test_pay() PRIVATE {};`,
+			expect: &CreateActionStatement{
+				Name:      "test_pay",
+				Modifiers: []string{"private"},
+				Test:      true,
+			},
+		}, {
+			name: "action with error types",
+			input: `-- @error_type(404, 'not found')
+			-- @error_type(409, conflict)
+			CREATE ACTION get_user() PUBLIC {};`,
+			expect: &CreateActionStatement{
+				Name:      "get_user",
+				Modifiers: []string{"public"},
+				ErrorTypes: []*engine.ErrorType{
+					{Code: 404, Message: "not found"},
+					{Code: 409, Message: "conflict"},
+				},
+			},
+		},
+		{
+			name: "error type with non-integer code",
+			input: `-- @error_type(not_found, 'not found')
+			CREATE ACTION get_user() PUBLIC {};`,
+			err: ErrAnnotation,
+		},
+		{
+			name: "duplicate error type",
+			input: `-- @error_type(404, 'not found')
+			-- @error_type(404, 'missing')
+			CREATE ACTION get_user() PUBLIC {};`,
+			err: ErrAnnotation,
+		},
 	}
 
 	for _, tt := range tests {
@@ -1987,3 +2472,31 @@ func TestCreateActionStatements(t *testing.T) {
 		})
 	}
 }
+
+func Test_CreateActionRawSource(t *testing.T) {
+	input := `CREATE TABLE users (id int primary key, name text);
+
+/*
+ * get_users returns all users,
+ * ordered by name.
+ */
+-- @distinct
+CREATE ACTION get_users() PUBLIC VIEW RETURNS table(id int, name text) {
+	-- the rows are ordered so that pagination is stable
+	RETURN SELECT id,   name FROM users ORDER BY name;
+};`
+
+	res, err := Parse(input)
+	require.NoError(t, err)
+	require.Len(t, res, 2)
+
+	cas := res[1].(*CreateActionStatement)
+	require.Equal(t, input[strings.Index(input, "/*"):], cas.Raw)
+
+	// the annotations are parsed again from the raw statement
+	reparsed, err := Parse(cas.Raw)
+	require.NoError(t, err)
+	require.Len(t, reparsed, 1)
+	require.True(t, reparsed[0].(*CreateActionStatement).Distinct)
+	require.Equal(t, cas.Raw, reparsed[0].(*CreateActionStatement).Raw)
+}
@@ -0,0 +1,143 @@
+package parse
+
+import (
+	"fmt"
+	"slices"
+)
+
+// ValidationErrorKind is the kind of inconsistency in a schema.
+type ValidationErrorKind string
+
+const (
+	// ValidationDuplicateTable is a table that is created more than once.
+	ValidationDuplicateTable ValidationErrorKind = "duplicate_table"
+	// ValidationUnknownTable is a reference to a table that the schema does
+	// not create, by a foreign key or an index.
+	ValidationUnknownTable ValidationErrorKind = "unknown_table"
+	// ValidationUnknownColumn is an index on a column that its table does not
+	// have.
+	ValidationUnknownColumn ValidationErrorKind = "unknown_column"
+	// ValidationInvalidType is an action parameter with an invalid data type.
+	ValidationInvalidType ValidationErrorKind = "invalid_type"
+)
+
+// ValidationError is an inconsistency in a schema.
+type ValidationError struct {
+	Kind    ValidationErrorKind `json:"kind"`
+	Message string              `json:"message"`
+	// Position is the position of the statement or clause in the schema.
+	Position *Position `json:"position,omitempty"`
+}
+
+func (v ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", v.Kind, v.Message)
+}
+
+// ValidateSchema checks that a schema is self-consistent: table names are
+// unique, the parent tables of foreign keys and the tables of indexes are
+// created by the schema, the columns of indexes exist in their table, and the
+// parameters of actions have valid data types. Foreign keys that reference a
+// table in another namespace are not checked. It returns an empty slice if the
+// schema is valid.
+func ValidateSchema(schema []TopLevelStatement) []ValidationError {
+	errs := []ValidationError{}
+
+	tables := make(map[string]*CreateTableStatement)
+	for _, stmt := range schema {
+		tbl, ok := stmt.(*CreateTableStatement)
+		if !ok {
+			continue
+		}
+		if _, ok := tables[tbl.Name]; ok {
+			errs = append(errs, ValidationError{
+				Kind:     ValidationDuplicateTable,
+				Message:  fmt.Sprintf("table %s is created more than once", tbl.Name),
+				Position: tbl.GetPosition(),
+			})
+			continue
+		}
+		tables[tbl.Name] = tbl
+	}
+
+	for _, stmt := range schema {
+		switch stmt := stmt.(type) {
+		case *CreateTableStatement:
+			var refs []*ForeignKeyReferences
+			for _, col := range stmt.Columns {
+				for _, c := range col.Constraints {
+					if fk, ok := c.(*ForeignKeyReferences); ok {
+						refs = append(refs, fk)
+					}
+				}
+			}
+			for _, c := range stmt.Constraints {
+				if fk, ok := c.Constraint.(*ForeignKeyOutOfLineConstraint); ok {
+					refs = append(refs, fk.References)
+				}
+			}
+
+			for _, ref := range refs {
+				if ref.RefTableNamespace != "" {
+					continue
+				}
+				if _, ok := tables[ref.RefTable]; !ok {
+					errs = append(errs, ValidationError{
+						Kind:     ValidationUnknownTable,
+						Message:  fmt.Sprintf("table %s: foreign key references unknown table %s", stmt.Name, ref.RefTable),
+						Position: ref.GetPosition(),
+					})
+				}
+			}
+		case *CreateIndexStatement:
+			tbl, ok := tables[stmt.On]
+			if !ok {
+				errs = append(errs, ValidationError{
+					Kind:     ValidationUnknownTable,
+					Message:  fmt.Sprintf("index %son unknown table %s", indexName(stmt), stmt.On),
+					Position: stmt.GetPosition(),
+				})
+				continue
+			}
+
+			for _, col := range stmt.Columns {
+				if !slices.ContainsFunc(tbl.Columns, func(c *Column) bool { return c.Name == col }) {
+					errs = append(errs, ValidationError{
+						Kind:     ValidationUnknownColumn,
+						Message:  fmt.Sprintf("index %son unknown column %s of table %s", indexName(stmt), col, stmt.On),
+						Position: stmt.GetPosition(),
+					})
+				}
+			}
+		case *CreateActionStatement:
+			for _, param := range stmt.Parameters {
+				if param.Type == nil {
+					errs = append(errs, ValidationError{
+						Kind:     ValidationInvalidType,
+						Message:  fmt.Sprintf("action %s: parameter %s has no type", stmt.Name, param.Name),
+						Position: stmt.GetPosition(),
+					})
+					continue
+				}
+				// Clean normalizes the type name, so a copy is checked
+				if err := param.Type.Copy().Clean(); err != nil {
+					errs = append(errs, ValidationError{
+						Kind:     ValidationInvalidType,
+						Message:  fmt.Sprintf("action %s: parameter %s: %v", stmt.Name, param.Name, err),
+						Position: stmt.GetPosition(),
+					})
+				}
+			}
+		}
+	}
+
+	return errs
+}
+
+// indexName returns the name of an index followed by a space, or nothing if
+// the index is not named.
+func indexName(idx *CreateIndexStatement) string {
+	if idx.Name == "" {
+		return ""
+	}
+	return idx.Name + " "
+}
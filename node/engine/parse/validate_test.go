@@ -0,0 +1,107 @@
+package parse_test
+
+import (
+	"testing"
+
+	"github.com/kwilteam/kwil-db/core/types"
+	"github.com/kwilteam/kwil-db/node/engine"
+	"github.com/kwilteam/kwil-db/node/engine/parse"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ValidateSchema(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+		want   []parse.ValidationError
+	}{
+		{
+			name: "valid schema",
+			schema: `CREATE TABLE users (id int primary key, name text);
+			CREATE TABLE posts (id int primary key, author int references users(id));
+			CREATE INDEX author_idx ON posts(author);
+			CREATE ACTION get_user($id int) public view { SELECT * FROM users WHERE id = $id; };`,
+			want: []parse.ValidationError{},
+		},
+		{
+			name: "duplicate table",
+			schema: `CREATE TABLE users (id int primary key);
+			CREATE TABLE users (id int primary key, name text);`,
+			want: []parse.ValidationError{{
+				Kind:    parse.ValidationDuplicateTable,
+				Message: "table users is created more than once",
+			}},
+		},
+		{
+			name:   "inline foreign key to unknown table",
+			schema: `CREATE TABLE posts (id int primary key, author int references users(id));`,
+			want: []parse.ValidationError{{
+				Kind:    parse.ValidationUnknownTable,
+				Message: "table posts: foreign key references unknown table users",
+			}},
+		},
+		{
+			name: "out of line foreign key to unknown table",
+			schema: `CREATE TABLE posts (id int primary key, author int,
+				foreign key (author) references users(id));`,
+			want: []parse.ValidationError{{
+				Kind:    parse.ValidationUnknownTable,
+				Message: "table posts: foreign key references unknown table users",
+			}},
+		},
+		{
+			name:   "index on unknown table",
+			schema: `CREATE INDEX name_idx ON users(name);`,
+			want: []parse.ValidationError{{
+				Kind:    parse.ValidationUnknownTable,
+				Message: "index name_idx on unknown table users",
+			}},
+		},
+		{
+			name: "index on unknown column",
+			schema: `CREATE TABLE users (id int primary key);
+			CREATE INDEX ON users(id, name);`,
+			want: []parse.ValidationError{{
+				Kind:    parse.ValidationUnknownColumn,
+				Message: "index on unknown column name of table users",
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, err := parse.Parse(tt.schema)
+			require.NoError(t, err)
+
+			errs := parse.ValidateSchema(schema)
+			for i := range errs {
+				require.NotNil(t, errs[i].Position)
+				require.NotNil(t, errs[i].Position.StartLine)
+				errs[i].Position = nil
+			}
+			require.Equal(t, tt.want, errs)
+		})
+	}
+}
+
+func Test_ValidateSchemaActionParameterTypes(t *testing.T) {
+	schema := []parse.TopLevelStatement{
+		&parse.CreateActionStatement{
+			Name: "get_user",
+			Parameters: []*engine.NamedType{
+				{Name: "$id", Type: types.IntType},
+				{Name: "$email", Type: &types.DataType{Name: "text2"}},
+				{Name: "$name"},
+			},
+		},
+	}
+
+	errs := parse.ValidateSchema(schema)
+	for i := range errs {
+		errs[i].Position = nil
+	}
+	require.Equal(t, []parse.ValidationError{
+		{Kind: parse.ValidationInvalidType, Message: "action get_user: parameter $email: unknown type: text2"},
+		{Kind: parse.ValidationInvalidType, Message: "action get_user: parameter $name has no type"},
+	}, errs)
+}
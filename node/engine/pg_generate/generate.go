@@ -594,6 +594,9 @@ func (s *sqlGenerator) VisitRelationTable(p0 *parse.RelationTable) any {
 
 func (s *sqlGenerator) VisitRelationSubquery(p0 *parse.RelationSubquery) any {
 	str := strings.Builder{}
+	if p0.Lateral {
+		str.WriteString("LATERAL ")
+	}
 	str.WriteString("(")
 	str.WriteString(p0.Subquery.Accept(s).(string))
 	str.WriteString(") ")
@@ -604,6 +607,35 @@ func (s *sqlGenerator) VisitRelationSubquery(p0 *parse.RelationSubquery) any {
 	return str.String()
 }
 
+func (s *sqlGenerator) VisitRelationFunctionCall(p0 *parse.RelationFunctionCall) any {
+	fn, ok := engine.TableFunctions[p0.Name]
+	if !ok {
+		panic("table function " + p0.Name + " not found")
+	}
+
+	args := make([]string, len(p0.Args))
+	for i, arg := range p0.Args {
+		args[i] = arg.Accept(s).(string)
+	}
+
+	pgFmt, err := fn.PGFormatFunc(args)
+	if err != nil {
+		panic(err)
+	}
+
+	str := strings.Builder{}
+	str.WriteString("(")
+	str.WriteString(pgFmt)
+	str.WriteString(") AS ")
+	// like a table, a table function without an alias is referenced by its name
+	if p0.Alias != "" {
+		str.WriteString(p0.Alias)
+	} else {
+		str.WriteString(p0.Name)
+	}
+	return str.String()
+}
+
 func (s *sqlGenerator) VisitJoin(p0 *parse.Join) any {
 	str := strings.Builder{}
 	str.WriteString(string(p0.Type))
@@ -831,6 +863,12 @@ func (s *sqlGenerator) VisitCreateTableStatement(p0 *parse.CreateTableStatement)
 		str.WriteString(col.Accept(s).(string))
 	}
 
+	if p0.SoftDelete {
+		str.WriteString(",\n")
+		str.WriteString(engine.SoftDeleteColumn)
+		str.WriteString(" TIMESTAMPTZ")
+	}
+
 	for _, con := range p0.Constraints {
 		str.WriteString(",\n")
 
@@ -912,6 +950,9 @@ func (s *sqlGenerator) VisitCreateIndexStatement(p0 *parse.CreateIndexStatement)
 		panic("unknown index type")
 	}
 
+	if p0.Concurrently {
+		str.WriteString("CONCURRENTLY ")
+	}
 	if p0.IfNotExists {
 		str.WriteString("IF NOT EXISTS ")
 	}
@@ -1281,6 +1322,21 @@ func (s *sqlGenerator) VisitActionStmtReturnNext(p0 *parse.ActionStmtReturnNext)
 	return nil
 }
 
+func (s *sqlGenerator) VisitActionStmtDeclareCursor(p0 *parse.ActionStmtDeclareCursor) any {
+	generateErr(s)
+	return nil
+}
+
+func (s *sqlGenerator) VisitActionStmtFetchCursor(p0 *parse.ActionStmtFetchCursor) any {
+	generateErr(s)
+	return nil
+}
+
+func (s *sqlGenerator) VisitActionStmtCloseCursor(p0 *parse.ActionStmtCloseCursor) any {
+	generateErr(s)
+	return nil
+}
+
 func (s *sqlGenerator) VisitCreateNamespaceStatement(p0 *parse.CreateNamespaceStatement) any {
 	generateErr(s)
 	return nil
@@ -1296,11 +1352,31 @@ func (s *sqlGenerator) VisitTransferOwnershipStatement(p0 *parse.TransferOwnersh
 	return nil
 }
 
+func (s *sqlGenerator) VisitTruncateTableStatement(p0 *parse.TruncateTableStatement) any {
+	str := strings.Builder{}
+	str.WriteString("TRUNCATE TABLE ")
+	if s.pgSchema != "" {
+		str.WriteString(s.pgSchema)
+		str.WriteString(".")
+	}
+	str.WriteString(p0.Table)
+	if p0.Cascade {
+		str.WriteString(" CASCADE")
+	}
+
+	return str.String()
+}
+
 func (s *sqlGenerator) VisitSetCurrentNamespaceStatement(p0 *parse.SetCurrentNamespaceStatement) any {
 	generateErr(s)
 	return nil
 }
 
+func (s *sqlGenerator) VisitAlterNamespaceStatement(p0 *parse.AlterNamespaceStatement) any {
+	generateErr(s)
+	return nil
+}
+
 // generateErr is a helper function that panics when a Visit method that is unexpected is called.
 func generateErr(t any) {
 	panic(fmt.Sprintf("SQL generate should never be called on %T", t))
@@ -63,6 +63,11 @@ func Test_PgGenerate(t *testing.T) {
 			},
 			params: []string{"$x", "$y", "$z"},
 		},
+		{
+			name: "lateral join",
+			sql:  "SELECT u.id, s.n FROM users AS u INNER JOIN LATERAL (SELECT g.generate_series AS n FROM generate_series(1, u.id, 1) AS g) AS s ON true;",
+			want: "SELECT u.id, s.n FROM users AS u INNER JOIN LATERAL (SELECT g.generate_series AS n FROM (SELECT * FROM generate_series(1::INT8, u.id::INT8, 1::INT8)) AS g) AS s ON true;",
+		},
 		{
 			name: "Select with one param",
 			sql:  "SELECT * FROM tbl WHERE col = $param;",
@@ -332,11 +337,26 @@ func Test_PgGenerate(t *testing.T) {
 			sql:  `DROP TABLE IF EXISTS departments CASCADE;`,
 			want: `DROP TABLE IF EXISTS kwil.departments CASCADE;`,
 		},
+		{
+			name: "truncate table",
+			sql:  `TRUNCATE TABLE departments;`,
+			want: `TRUNCATE TABLE kwil.departments;`,
+		},
+		{
+			name: "truncate table cascade",
+			sql:  `TRUNCATE TABLE departments CASCADE;`,
+			want: `TRUNCATE TABLE kwil.departments CASCADE;`,
+		},
 		{
 			name: "create index",
 			sql:  `CREATE INDEX IF NOT EXISTS idx_department_name_id ON departments (department_name, department_id);`,
 			want: `CREATE INDEX IF NOT EXISTS idx_department_name_id ON kwil.departments (department_name, department_id);`,
 		},
+		{
+			name: "create index concurrently",
+			sql:  `CREATE INDEX CONCURRENTLY idx_department_name ON departments (department_name);`,
+			want: `CREATE INDEX CONCURRENTLY idx_department_name ON kwil.departments (department_name);`,
+		},
 		{
 			name: "drop index",
 			sql:  `DROP INDEX IF EXISTS idx_department_name_id;`,
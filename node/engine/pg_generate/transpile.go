@@ -0,0 +1,64 @@
+package pggenerate
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kwilteam/kwil-db/core/types"
+	"github.com/kwilteam/kwil-db/node/engine"
+	"github.com/kwilteam/kwil-db/node/engine/parse"
+	"github.com/kwilteam/kwil-db/node/engine/planner/logical"
+)
+
+// Transpile converts a parsed SQL statement to the Postgres SQL that an action
+// executes for it. It is meant for debugging and for tools that generate SQL
+// scripts, and does not connect to a database.
+//
+// The statement is planned against tables, which are the tables of the
+// namespace by name, and is rewritten with the default ordering that
+// deterministic queries are given. Table names are qualified with namespace.
+// Variables are numbered as Postgres parameters ($1, $2, ...) in the order
+// they first appear. Since their types are not known, they are only cast if
+// the statement casts them.
+//
+// The rewrites that depend on the caller, such as masking sensitive columns
+// and hiding soft deleted rows, are not applied.
+func Transpile(stmt parse.TopLevelStatement, namespace string, tables map[string]*engine.Table) (string, error) {
+	sqlStmt, ok := stmt.(*parse.SQLStatement)
+	if !ok {
+		return "", fmt.Errorf("cannot transpile %T, only SQL statements can be transpiled", stmt)
+	}
+
+	getTable := func(ns, tableName string) (*engine.Table, error) {
+		if ns != "" && ns != namespace {
+			return nil, fmt.Errorf(`%w: "%s"."%s"`, engine.ErrUnknownTable, ns, tableName)
+		}
+		tbl, ok := tables[tableName]
+		if !ok {
+			return nil, fmt.Errorf(`%w: "%s"`, engine.ErrUnknownTable, tableName)
+		}
+		return tbl, nil
+	}
+	// variables are given the null type, which is not cast
+	getVar := func(string) (*types.DataType, error) {
+		return types.NullType, nil
+	}
+
+	_, err := logical.CreateLogicalPlan(sqlStmt, getTable, getVar,
+		func(string) (map[string]*types.DataType, error) {
+			return nil, engine.ErrUnknownVariable
+		},
+		func(string) bool { return false },
+		true, namespace,
+	)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", engine.ErrQueryPlanner, err)
+	}
+
+	sql, _, err := GenerateSQL(sqlStmt, namespace, getVar)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", engine.ErrPGGen, err)
+	}
+
+	return strings.TrimSpace(sql), nil
+}
@@ -0,0 +1,91 @@
+package pggenerate_test
+
+import (
+	"testing"
+
+	"github.com/kwilteam/kwil-db/core/types"
+	"github.com/kwilteam/kwil-db/node/engine"
+	"github.com/kwilteam/kwil-db/node/engine/parse"
+	pggenerate "github.com/kwilteam/kwil-db/node/engine/pg_generate"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Transpile(t *testing.T) {
+	tables := map[string]*engine.Table{
+		"users": {
+			Name: "users",
+			Columns: []*engine.Column{
+				{Name: "id", DataType: types.IntType, IsPrimaryKey: true},
+				{Name: "name", DataType: types.TextType},
+			},
+		},
+		"posts": {
+			Name: "posts",
+			Columns: []*engine.Column{
+				{Name: "id", DataType: types.IntType, IsPrimaryKey: true},
+				{Name: "author", DataType: types.IntType},
+				{Name: "title", DataType: types.TextType},
+			},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		sql     string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "select with where",
+			sql:  "SELECT name FROM users WHERE id = $id;",
+			want: `SELECT name
+FROM main.users
+WHERE id = $1
+ORDER BY 1;`,
+		},
+		{
+			name: "join with order by",
+			sql: `SELECT p.title, u.name FROM posts p JOIN users u ON p.author = u.id
+				WHERE u.name = $name::text ORDER BY p.title DESC;`,
+			// the default ordering is added after the given ordering
+			want: `SELECT p.title, u.name
+FROM main.posts AS p
+INNER JOIN main.users AS u ON p.author = u.id
+WHERE u.name = $1::TEXT
+ORDER BY p.title DESC NULLS LAST, 1, 2;`,
+		},
+		{
+			name: "variables are numbered once",
+			sql:  "UPDATE posts SET title = $title WHERE author = @caller AND title != $title;",
+			want: `UPDATE main.posts
+SET title = $1
+WHERE author = $2 AND title <> $1;`,
+		},
+		{
+			name:    "unknown table",
+			sql:     "SELECT * FROM comments;",
+			wantErr: true,
+		},
+		{
+			name:    "not a SQL statement",
+			sql:     "CREATE ROLE writer;",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stmts, err := parse.Parse(tt.sql)
+			require.NoError(t, err)
+			require.Len(t, stmts, 1)
+
+			got, err := pggenerate.Transpile(stmts[0], "main", tables)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -20,4 +20,6 @@ var (
 	ErrFunctionDoesNotExist       = errors.New("function does not exist")
 	ErrActionInSQLStmt            = errors.New("actions cannot be used in SQL statements")
 	ErrUntypedEmptyArray          = errors.New("cannot detect type for empty array")
+	ErrIllegalLateral             = errors.New("illegal LATERAL subquery")
+	ErrInsertValueCount           = errors.New("number of insert values does not match the number of columns")
 )
@@ -1012,11 +1012,21 @@ func (s *scopeContext) selectCoreWithoutFrom(cols []parse.ResultColumn, isDistin
 
 // planWindow plans a window function.
 func (s *scopeContext) planWindow(plan Plan, rel *Relation, win *parse.WindowImpl, groupingTerms map[string]*IdentifiedExpr) (*Window, error) {
+	// in a grouped query, windows are applied to the groups, so they can only
+	// use the grouping terms and aggregates. This is checked here so that it
+	// is not left to Postgres.
+	groupConflict := func(err error) error {
+		if len(groupingTerms) > 0 && errors.Is(err, ErrIllegalAggregate) {
+			return fmt.Errorf("%w: %w", engine.ErrWindowGroupByConflict, err)
+		}
+		return err
+	}
+
 	var partitionBy []Expression
 	for _, partition := range win.PartitionBy {
 		partition, _, err := s.expr(partition, rel, groupingTerms)
 		if err != nil {
-			return nil, err
+			return nil, groupConflict(err)
 		}
 
 		partitionBy = append(partitionBy, partition)
@@ -1040,7 +1050,7 @@ func (s *scopeContext) planWindow(plan Plan, rel *Relation, win *parse.WindowImp
 	if len(win.OrderBy) > 0 {
 		sort, err := s.buildSort(plan, rel, win.OrderBy, groupingTerms)
 		if err != nil {
-			return nil, err
+			return nil, groupConflict(err)
 		}
 
 		orderBy = sort.SortExpressions
@@ -2117,6 +2127,17 @@ func (s *scopeContext) table(node parse.Table) (*Scan, *Relation, error) {
 
 			scanTblType = TableSourcePhysical
 			rel = relationFromTable(physicalTbl)
+			if physicalTbl.SoftDelete {
+				// The soft delete column is only readable so that the filter
+				// injected by the engine can reference it. Kwil has no
+				// timestamp type, so it is typed as an int8, and it should
+				// only be compared to NULL.
+				rel.Fields = append(rel.Fields, &Field{
+					Parent: physicalTbl.Name,
+					Name:   engine.SoftDeleteColumn,
+					val:    types.IntType.Copy(),
+				})
+			}
 		}
 
 		for _, col := range rel.Fields {
@@ -2136,6 +2157,9 @@ func (s *scopeContext) table(node parse.Table) (*Scan, *Relation, error) {
 		if node.Alias == "" {
 			return nil, nil, fmt.Errorf("join against subquery must have an alias")
 		}
+		if node.Lateral {
+			return nil, nil, fmt.Errorf("%w: LATERAL subqueries can only be joined", ErrIllegalLateral)
+		}
 
 		// we pass an empty relation because the subquery can't
 		// refer to the current relation, but they can be correlated against some
@@ -2157,12 +2181,73 @@ func (s *scopeContext) table(node parse.Table) (*Scan, *Relation, error) {
 			Source:       subq,
 			RelationName: node.Alias,
 		}, rel, nil
+	case *parse.RelationFunctionCall:
+		funcDef, ok := engine.TableFunctions[node.Name]
+		if !ok {
+			return nil, nil, fmt.Errorf(`%w: "%s"`, ErrFunctionDoesNotExist, node.Name)
+		}
+
+		alias := node.Name
+		if node.Alias != "" {
+			alias = node.Alias
+		}
+
+		// like subqueries, the arguments cannot refer to the other relations
+		// in the FROM clause
+		var args []Expression
+		var fields []*Field
+		for _, arg := range node.Args {
+			expr, field, err := s.expr(arg, &Relation{}, nil)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			args = append(args, expr)
+			fields = append(fields, field)
+		}
+
+		argTypes, err := dataTypes(fields)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		ret, err := funcDef.ValidateArgs(argTypes)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// the fields of the composite type are unpacked into the columns of
+		// the relation, so that each is a value of the rows
+		rel := &Relation{}
+		for _, col := range ret.CompositeFields() {
+			rel.Fields = append(rel.Fields, &Field{
+				Parent: alias,
+				Name:   col.Name,
+				val:    col.Type.Copy(),
+			})
+		}
+
+		return &Scan{
+			Source: &ProcedureScanSource{
+				ProcedureName: node.Name,
+				Args:          args,
+				rel:           rel.Copy(),
+			},
+			RelationName: alias,
+		}, rel, nil
 	}
 }
 
 // join wraps the given plan in a join node.
 func (s *scopeContext) join(child Plan, childRel *Relation, join *parse.Join) (Plan, *Relation, error) {
-	tbl, tblRel, err := s.table(join.Relation)
+	var tbl *Scan
+	var tblRel *Relation
+	var err error
+	if subq, ok := join.Relation.(*parse.RelationSubquery); ok && subq.Lateral {
+		tbl, tblRel, err = s.lateralSubquery(subq, childRel, join.Type)
+	} else {
+		tbl, tblRel, err = s.table(join.Relation)
+	}
 	if err != nil {
 		return nil, nil, err
 	}
@@ -2193,6 +2278,37 @@ func (s *scopeContext) join(child Plan, childRel *Relation, join *parse.Join) (P
 	return plan, newRel, nil
 }
 
+// lateralSubquery plans a LATERAL subquery that is joined to the relations
+// that precede it in the FROM clause. Unlike other subqueries in the FROM
+// clause, it can reference their columns, which are passed to it as
+// correlated columns.
+func (s *scopeContext) lateralSubquery(node *parse.RelationSubquery, precedingRel *Relation, joinType parse.JoinType) (*Scan, *Relation, error) {
+	if node.Alias == "" {
+		return nil, nil, fmt.Errorf("join against subquery must have an alias")
+	}
+
+	// like Postgres, the columns of the preceding relations are null for
+	// the unmatched rows of RIGHT and FULL joins, so LATERAL cannot be used
+	// with them
+	if joinType != parse.JoinTypeInner && joinType != parse.JoinTypeLeft {
+		return nil, nil, fmt.Errorf("%w: LATERAL subqueries can only be used with INNER or LEFT joins", ErrIllegalLateral)
+	}
+
+	subq, rel, err := s.planSubquery(node.Subquery, precedingRel)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, col := range rel.Fields {
+		col.Parent = node.Alias
+	}
+
+	return &Scan{
+		Source:       subq,
+		RelationName: node.Alias,
+	}, rel, nil
+}
+
 // update builds a plan for an update
 func (s *scopeContext) update(node *parse.UpdateStatement) (*Update, error) {
 	plan, targetRel, cartesianRel, err := s.cartesian(node.Table, node.Alias, node.From, node.Joins, node.Where)
@@ -2250,8 +2366,10 @@ func (s *scopeContext) insert(node *parse.InsertStatement) (*Insert, error) {
 	// dimension of Values must exactly match the number of columns in the table.
 	var expectedColLen int
 	var expectedColTypes []*types.DataType
+	var expectedColNames []string
 	if len(node.Columns) > 0 {
 		expectedColLen = len(node.Columns)
+		expectedColNames = node.Columns
 
 		// check if the columns are valid
 		var err error
@@ -2306,6 +2424,7 @@ func (s *scopeContext) insert(node *parse.InsertStatement) (*Insert, error) {
 		expectedColLen = len(tbl.Columns)
 		for _, col := range tbl.Columns {
 			expectedColTypes = append(expectedColTypes, col.DataType.Copy())
+			expectedColNames = append(expectedColNames, col.Name)
 		}
 	}
 
@@ -2320,8 +2439,19 @@ func (s *scopeContext) insert(node *parse.InsertStatement) (*Insert, error) {
 		}
 
 		// check that the select statement returns the correct number of columns and types
-		if err = equalShape(rel, newRel); err != nil {
-			return nil, err
+		if len(newRel.Fields) != expectedColLen {
+			return nil, fmt.Errorf(`%w: insert has %d columns but the select returns %d`, ErrInsertValueCount, expectedColLen, len(newRel.Fields))
+		}
+
+		for j, field := range newRel.Fields {
+			scalar, err := field.Scalar()
+			if err != nil {
+				return nil, err
+			}
+
+			if !scalar.Equals(expectedColTypes[j]) {
+				return nil, fmt.Errorf(`insert column "%s" must be of type %s, received %s`, expectedColNames[j], expectedColTypes[j], scalar)
+			}
 		}
 
 		ins.InsertionValues = plan
@@ -2332,7 +2462,7 @@ func (s *scopeContext) insert(node *parse.InsertStatement) (*Insert, error) {
 		// check the value types and lengths
 		for i, vals := range node.Values {
 			if len(vals) != expectedColLen {
-				return nil, fmt.Errorf(`insert has %d columns but %d values were supplied`, expectedColLen, len(vals))
+				return nil, fmt.Errorf(`%w: insert has %d columns but row %d has %d values`, ErrInsertValueCount, expectedColLen, i+1, len(vals))
 			}
 
 			var row []*exprFieldPair[Expression]
@@ -2352,7 +2482,7 @@ func (s *scopeContext) insert(node *parse.InsertStatement) (*Insert, error) {
 					return nil, fmt.Errorf(`insert value %d must be of type %s, received %s`, j+1, expectedColTypes[j], field.val)
 				}
 
-				field.Name = tbl.Columns[j].Name
+				field.Name = expectedColNames[j]
 				field.Parent = tbl.Name
 				row = append(row, &exprFieldPair[Expression]{
 					Expr:  expr,
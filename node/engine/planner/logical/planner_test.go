@@ -72,6 +72,20 @@ func Test_Planner(t *testing.T) {
 				"└─Project: posts.owner_id\n" +
 				"  └─Scan Table: posts [physical]\n",
 		},
+		{
+			name: "table function",
+			sql:  "select s.generate_series from generate_series(1, 5, 1) as s",
+			wt: "Return: generate_series [int8]\n" +
+				"└─Project: s.generate_series\n" +
+				"  └─Scan Procedure [alias=\"s\"]: [foreign=false] generate_series(1, 5, 1)\n",
+		},
+		{
+			name: "table function with several columns",
+			sql:  "select * from project_events('main', 'users', 1) as e",
+			wt: "Return: row_key [text], row_data [text]\n" +
+				"└─Project: e.row_key; e.row_data\n" +
+				"  └─Scan Procedure [alias=\"e\"]: [foreign=false] project_events('main', 'users', 1)\n",
+		},
 		{
 			name: "correlated joined subquery",
 			sql:  "select name from users u where id = (select owner_id from posts inner join (select age from users where id = u.id) as u2 on u2.age=length(posts.content))",
@@ -89,6 +103,33 @@ func Test_Planner(t *testing.T) {
 				"  └─Filter: users.id = u.id\n" +
 				"    └─Scan Table: users [physical]\n",
 		},
+		{
+			name: "lateral join",
+			sql:  "select u.name, s.n from users u inner join lateral (select g.generate_series as n from generate_series(1, u.age, 1) as g) as s on true",
+			wt: "Return: name [text], n [int8]\n" +
+				"└─Project: u.name; s.n\n" +
+				"  └─Join [inner]: true\n" +
+				"    ├─Scan Table [alias=\"u\"]: users [physical]\n" +
+				"    └─Scan Subquery [alias=\"s\"]: [subplan_id=0] (correlated: u.age)\n" +
+				"Subplan [subquery] [id=0]\n" +
+				"└─Project: g.generate_series AS n\n" +
+				"  └─Scan Procedure [alias=\"g\"]: [foreign=false] generate_series(1, u.age, 1)\n",
+		},
+		{
+			name: "lateral join referencing a later relation",
+			sql:  "select u.name from users u inner join lateral (select p.id from posts p where p.owner_id = p2.owner_id) as s on true inner join posts p2 on true",
+			err:  logical.ErrColumnNotFound,
+		},
+		{
+			name: "lateral right join",
+			sql:  "select u.name from users u right join lateral (select p.id from posts p where p.owner_id = u.id) as s on true",
+			err:  logical.ErrIllegalLateral,
+		},
+		{
+			name: "lateral in from",
+			sql:  "select s.id from lateral (select id from posts) as s",
+			err:  logical.ErrIllegalLateral,
+		},
 		{
 			name: "scalar subquery in where clause",
 			sql:  "select name from users where id = (select id from posts where content = 'hello')",
@@ -296,6 +337,39 @@ func Test_Planner(t *testing.T) {
 				"      └─Scan Table: users [physical]\n",
 			defaultOrdering: true,
 		},
+		{
+			name: "window over a grouping term",
+			sql:  "select name, sum(sum(age)) over (partition by name) from users group by name",
+			wt: "Return: name [text], sum [numeric(1000,0)]\n" +
+				"└─Project: {#ref(A)}; {#ref(C)}\n" +
+				"  └─Window [partition_by={#ref(A)}]: {#ref(C) = sum({#ref(B)})}\n" +
+				"    └─Aggregate [{#ref(A) = users.name}]: {#ref(B) = sum(users.age)}\n" +
+				"      └─Scan Table: users [physical]\n",
+		},
+		{
+			name: "window over an aggregate",
+			sql:  "select name, sum(sum(age)) over (partition by sum(age)) from users group by name",
+			wt: "Return: name [text], sum [numeric(1000,0)]\n" +
+				"└─Project: {#ref(A)}; {#ref(C)}\n" +
+				"  └─Window [partition_by={#ref(B)}]: {#ref(C) = sum({#ref(B)})}\n" +
+				"    └─Aggregate [{#ref(A) = users.name}]: {#ref(B) = sum(users.age)}\n" +
+				"      └─Scan Table: users [physical]\n",
+		},
+		{
+			name: "window over a column that is not grouped",
+			sql:  "select name, sum(sum(age)) over (partition by age) from users group by name",
+			err:  engine.ErrWindowGroupByConflict,
+		},
+		{
+			name: "named window over a column that is not grouped",
+			sql:  "select name, sum(sum(age)) over w from users group by name window w as (partition by age)",
+			err:  engine.ErrWindowGroupByConflict,
+		},
+		{
+			name: "window ordered by a column that is not grouped",
+			sql:  "select name, sum(sum(age)) over (partition by name order by age) from users group by name",
+			err:  engine.ErrWindowGroupByConflict,
+		},
 		{
 			name: "common table expressions",
 			sql: `with a (id2, name2) as (select id, name from users),
@@ -609,6 +683,23 @@ func Test_Planner(t *testing.T) {
 				"└─Project: users.id; users.name; users.age\n" +
 				"  └─Scan Table: users [physical]\n",
 		},
+		{
+			name: "insert columns with select",
+			sql:  "insert into users (id, name) select id, name from users",
+			wt: "Insert [users]: id [uuid], name [text], age [int8]\n" +
+				"└─Project: users.id; users.name\n" +
+				"  └─Scan Table: users [physical]\n",
+		},
+		{
+			name: "insert row with missing value",
+			sql:  "insert into users values ('123e4567-e89b-12d3-a456-426614174000'::uuid, 'satoshi', 1), ('123e4567-e89b-12d3-a456-426614174001'::uuid, 'satoshi2')",
+			err:  logical.ErrInsertValueCount,
+		},
+		{
+			name: "insert select with wrong number of columns",
+			sql:  "insert into users (id, name) select id from users",
+			err:  logical.ErrInsertValueCount,
+		},
 		{
 			name: "recursive CTE",
 			sql: `with recursive r as (
@@ -16,8 +16,25 @@ type Table struct {
 	Indexes []*Index
 	// Constraints are constraints on the table.
 	Constraints map[string]*Constraint
+	// SoftDelete is true if rows of the table are deleted by setting
+	// SoftDeleteColumn rather than being removed.
+	SoftDelete bool
+	// History is true if the previous states of the table's rows are
+	// recorded in a history table.
+	History bool
+	// OptimizerHints are the planner settings used by queries on the table.
+	OptimizerHints []*OptimizerHint
 }
 
+// SoftDeleteColumn is the column added to tables declared with @soft_delete.
+// It holds the time at which a row was soft deleted, or NULL if it was not.
+// It is a Postgres timestamptz, and is not part of the table's columns.
+const SoftDeleteColumn = "_deleted_at"
+
+// HistoryTablePrefix prefixes the name of the history table of a table declared
+// with @history. The history table is not visible as a table in the namespace.
+const HistoryTablePrefix = "_history_"
+
 // Copy deep copies the table.
 func (t *Table) Copy() *Table {
 	table := &Table{
@@ -25,6 +42,12 @@ func (t *Table) Copy() *Table {
 		Columns:     make([]*Column, len(t.Columns)),
 		Indexes:     make([]*Index, len(t.Indexes)),
 		Constraints: make(map[string]*Constraint),
+		SoftDelete:  t.SoftDelete,
+		History:     t.History,
+	}
+
+	for _, hint := range t.OptimizerHints {
+		table.OptimizerHints = append(table.OptimizerHints, &OptimizerHint{Name: hint.Name, Value: hint.Value})
 	}
 
 	for i, col := range t.Columns {
@@ -100,17 +123,84 @@ type Column struct {
 	Nullable bool
 	// IsPrimaryKey is true if the column is part of the primary key.
 	IsPrimaryKey bool
+	// SensitivityPolicy is the masking policy applied to the column when it is
+	// read by a caller without the SENSITIVE privilege. It is nil if the column
+	// is not sensitive.
+	SensitivityPolicy *SensitivityPolicy
 }
 
 func (c *Column) Copy() *Column {
+	var policy *SensitivityPolicy
+	if c.SensitivityPolicy != nil {
+		p := *c.SensitivityPolicy
+		policy = &p
+	}
+
 	return &Column{
-		Name:         c.Name,
-		DataType:     c.DataType.Copy(),
-		Nullable:     c.Nullable,
-		IsPrimaryKey: c.IsPrimaryKey,
+		Name:              c.Name,
+		DataType:          c.DataType.Copy(),
+		Nullable:          c.Nullable,
+		IsPrimaryKey:      c.IsPrimaryKey,
+		SensitivityPolicy: policy,
 	}
 }
 
+// MaskFunction is a function used to mask the value of a sensitive column.
+type MaskFunction string
+
+const (
+	// MaskHash replaces the value with the hex encoded sha256 hash of its text.
+	MaskHash MaskFunction = "hash"
+	// MaskPartial shows only the first PartialLength characters of the value.
+	MaskPartial MaskFunction = "partial"
+	// MaskRedact replaces the value with NULL.
+	MaskRedact MaskFunction = "redact"
+)
+
+// SensitivityPolicy describes how a sensitive column is masked.
+type SensitivityPolicy struct {
+	// Mask is the function used to mask the column.
+	Mask MaskFunction
+	// PartialLength is the number of leading characters shown by MaskPartial.
+	PartialLength int64
+}
+
+// OptimizerHint is a Postgres planner setting declared with @optimizer_hint.
+// It is set for the duration of each query that reads a table, or that is run
+// by an action, declared with it.
+type OptimizerHint struct {
+	// Name is the name of the setting, e.g. enable_seqscan.
+	Name string
+	// Value is the value of the setting, either "true" or "false".
+	Value string
+}
+
+// ErrorType is an application error that an action declared with
+// @error_type, and can raise with raise_error.
+type ErrorType struct {
+	// Code identifies the error to the caller.
+	Code int
+	// Message describes the error. It is the message of a raised error that
+	// does not have one.
+	Message string
+}
+
+// OptimizerHintSettings are the planner settings that can be used as
+// optimizer hints. They only change the plans Postgres chooses, and never the
+// results of a query.
+var OptimizerHintSettings = map[string]struct{}{
+	"enable_bitmapscan":    {},
+	"enable_hashagg":       {},
+	"enable_hashjoin":      {},
+	"enable_indexonlyscan": {},
+	"enable_indexscan":     {},
+	"enable_material":      {},
+	"enable_mergejoin":     {},
+	"enable_nestloop":      {},
+	"enable_seqscan":       {},
+	"enable_sort":          {},
+}
+
 // Constraint is a constraint in the schema.
 type Constraint struct {
 	// Type is the type of the constraint.
@@ -193,6 +283,10 @@ const (
 	DefaultNamespace       = "main"
 	InfoNamespace          = "info"
 	InternalEnginePGSchema = "kwild_engine"
+	// HealthAction is the name of the action that reports the health of a
+	// namespace. If a namespace has a view action with this name and no
+	// parameters, the namespace is healthy if calling it does not error.
+	HealthAction = "__health__"
 )
 
 // NamedType is a parameter in an action.
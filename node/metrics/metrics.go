@@ -17,6 +17,7 @@ var (
 	Consensus ConsensusMetrics = consensusMetrics{}
 	Node      NodeMetrics      = nodeMetrics{}
 	Store     StoreMetrics     = storeMetrics{}
+	Engine    EngineMetrics    = engineMetrics{}
 )
 
 // If we do not want to use the otel global meter provider, we can create our
@@ -48,6 +49,11 @@ var (
 	dbQueryErrorCount  metric.Int64Counter
 
 	// Engine metrics
+	engineConcurrencyGauge    metric.Int64Gauge
+	engineMaxConcurrencyGauge metric.Int64Gauge
+	engineBackpressureCounter metric.Int64Counter
+	engineAnomalyCounter      metric.Int64Counter
+	engineRegressionCounter   metric.Int64Counter
 	// engineNumNamespaces metric.Int64Gauge // TODO
 	// engineStatementParseCount metric.Int64Counter
 
@@ -134,6 +140,14 @@ func init() {
 	txReannounceBytesCounter, _ = nodeMeter.Int64Counter("node.tx_reannounce.bytes")
 	// rebroadcasts etc...
 
+	// Engine metrics
+	engineMeter := otel.Meter(EngineMeterName)
+	engineConcurrencyGauge, _ = engineMeter.Int64Gauge("engine.calls.concurrent")
+	engineMaxConcurrencyGauge, _ = engineMeter.Int64Gauge("engine.calls.max_concurrent")
+	engineBackpressureCounter, _ = engineMeter.Int64Counter("engine.calls.backpressure.count")
+	engineAnomalyCounter, _ = engineMeter.Int64Counter("engine.calls.anomalies.count")
+	engineRegressionCounter, _ = engineMeter.Int64Counter("engine.calls.regressions.count")
+
 	// Consensus metrics
 	consensusMeter := otel.Meter(ConsensusMeterName)
 	commitLatencyHist, _ = consensusMeter.Float64Histogram("consensus.commit.latency")
@@ -150,6 +164,41 @@ func init() {
 	bsTransactionsRetrievedCounter, _ = storeMeter.Int64Counter("transactions.retrieved.count")
 }
 
+type EngineMetrics interface {
+	Concurrency(ctx context.Context, current, max int)
+	BackpressureRejected(ctx context.Context)
+	AnomalyDetected(ctx context.Context)
+	PerformanceRegression(ctx context.Context, namespace, action string)
+}
+
+type engineMetrics struct{}
+
+// Concurrency logs the number of calls in progress in the engine, and the
+// maximum allowed.
+func (engineMetrics) Concurrency(ctx context.Context, current, max int) {
+	engineConcurrencyGauge.Record(ctx, int64(current))
+	engineMaxConcurrencyGauge.Record(ctx, int64(max))
+}
+
+// BackpressureRejected logs a call that was rejected because the engine was
+// at its maximum concurrency.
+func (engineMetrics) BackpressureRejected(ctx context.Context) {
+	engineBackpressureCounter.Add(ctx, 1)
+}
+
+// AnomalyDetected logs an alert raised for an unusual pattern of calls.
+func (engineMetrics) AnomalyDetected(ctx context.Context) {
+	engineAnomalyCounter.Add(ctx, 1)
+}
+
+// PerformanceRegression logs a call that took longer than the duration
+// baseline of its action.
+func (engineMetrics) PerformanceRegression(ctx context.Context, namespace, action string) {
+	engineRegressionCounter.Add(ctx, 1,
+		metric.WithAttributes(attribute.String("namespace", namespace), attribute.String("action", action)),
+	)
+}
+
 type storeMetrics struct{}
 
 type StoreMetrics interface {
@@ -256,3 +256,11 @@ func (m *mockAccounts) GetAccount(ctx context.Context, tx sql.Executor, account
 func (m *mockAccounts) ApplySpend(ctx context.Context, tx sql.Executor, account *types.AccountID, amount *big.Int, nonce int64) error {
 	return nil
 }
+
+func (m *mockAccounts) TransferToken(ctx context.Context, tx sql.TxMaker, from, to *types.AccountID, token []byte, amt *big.Int) error {
+	return nil
+}
+
+func (m *mockAccounts) TokenBalance(ctx context.Context, tx sql.Executor, account *types.AccountID, token []byte) (*big.Int, error) {
+	return big.NewInt(0), nil
+}
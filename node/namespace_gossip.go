@@ -0,0 +1,161 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/kwilteam/kwil-db/core/log"
+	"github.com/kwilteam/kwil-db/node/engine/interpreter"
+	"github.com/kwilteam/kwil-db/node/peers"
+	"github.com/kwilteam/kwil-db/node/types/sql"
+
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+// TopicNamespaces is the gossip topic of namespace deployments.
+const TopicNamespaces = "namespaces"
+
+// NamespaceEngine is the engine whose namespace deployments are gossiped.
+type NamespaceEngine interface {
+	NamespaceEvents() <-chan interpreter.NamespaceEvent
+	LoadNamespace(ctx context.Context, db sql.DB, name string) (bool, error)
+}
+
+// NamespaceGossip announces the namespaces deployed and undeployed by the
+// engine to other nodes, and loads the namespaces they announce. Namespaces
+// are loaded from the node's own database, so an announcement never adds a
+// namespace that the node has not committed.
+type NamespaceGossip struct {
+	engine NamespaceEngine
+	db     sql.ReadTxMaker
+	log    log.Logger
+}
+
+// NewNamespaceGossip creates the namespace gossip of an engine, which must
+// have been created with gossip enabled.
+func NewNamespaceGossip(engine NamespaceEngine, db sql.ReadTxMaker, logger log.Logger) (*NamespaceGossip, error) {
+	if engine.NamespaceEvents() == nil {
+		return nil, errors.New("namespace gossip is not enabled in the engine")
+	}
+	if logger == nil {
+		logger = log.DiscardLogger
+	}
+
+	return &NamespaceGossip{
+		engine: engine,
+		db:     db,
+		log:    logger,
+	}, nil
+}
+
+// namespaceAnn is the gossiped announcement of a namespace event.
+type namespaceAnn interpreter.NamespaceEvent
+
+func (a namespaceAnn) MarshalBinary() ([]byte, error) {
+	var deployed byte
+	if a.Deployed {
+		deployed = 1
+	}
+	return append([]byte{deployed}, a.Namespace...), nil
+}
+
+func (a *namespaceAnn) UnmarshalBinary(data []byte) error {
+	if len(data) < 2 {
+		return errors.New("namespace announcement too short")
+	}
+	if data[0] > 1 {
+		return fmt.Errorf("invalid namespace announcement flag %d", data[0])
+	}
+
+	a.Deployed = data[0] == 1
+	a.Namespace = string(data[1:])
+	return nil
+}
+
+// start publishes the engine's namespace events, and loads the deployed
+// namespaces announced by other nodes.
+func (g *NamespaceGossip) start(ctx context.Context, ps *pubsub.PubSub, me peer.ID, wg *sync.WaitGroup) error {
+	topic, sub, err := subTopic(ctx, ps, TopicNamespaces)
+	if err != nil {
+		return err
+	}
+
+	subCanceled := make(chan struct{})
+
+	wg.Add(1)
+	go func() {
+		defer func() {
+			<-subCanceled
+			topic.Close()
+			wg.Done()
+		}()
+		for {
+			var ev interpreter.NamespaceEvent
+			select {
+			case <-ctx.Done():
+				return
+			case ev = <-g.engine.NamespaceEvents():
+			}
+
+			msg, _ := namespaceAnn(ev).MarshalBinary()
+			if err := topic.Publish(ctx, msg); err != nil {
+				g.log.Warnf("failed to publish namespace announcement for %s: %v", ev.Namespace, err)
+			}
+		}
+	}()
+
+	go func() {
+		defer close(subCanceled)
+		defer sub.Cancel()
+		for {
+			msg, err := sub.Next(ctx)
+			if err != nil {
+				if !errors.Is(err, context.Canceled) {
+					g.log.Errorf("Stopping namespace gossip: %v", err)
+				}
+				return
+			}
+
+			if msg.GetFrom() == me {
+				continue
+			}
+
+			var ann namespaceAnn
+			if err := ann.UnmarshalBinary(msg.Data); err != nil {
+				g.log.Infof("failed to decode namespace announcement: %v", err)
+				continue
+			}
+
+			// namespaces are only removed by the transactions that drop them
+			if !ann.Deployed {
+				continue
+			}
+
+			loaded, err := g.load(ctx, ann.Namespace)
+			if err != nil {
+				g.log.Warnf("failed to load namespace %s announced by %s: %v", ann.Namespace,
+					peers.PeerIDStringer(msg.GetFrom()), err)
+				continue
+			}
+			if loaded {
+				g.log.Infof("loaded namespace %s announced by %s", ann.Namespace, peers.PeerIDStringer(msg.GetFrom()))
+			}
+		}
+	}()
+
+	return nil
+}
+
+// load loads an announced namespace from the committed state of the database.
+func (g *NamespaceGossip) load(ctx context.Context, namespace string) (bool, error) {
+	tx, err := g.db.BeginReadTx(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	return g.engine.LoadNamespace(ctx, tx, namespace)
+}
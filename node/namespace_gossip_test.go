@@ -0,0 +1,126 @@
+package node
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kwilteam/kwil-db/node/engine/interpreter"
+	"github.com/kwilteam/kwil-db/node/types/sql"
+
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	mock "github.com/libp2p/go-libp2p/p2p/net/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// gossipEngine is an engine whose namespaces are the ones committed to its
+// database.
+type gossipEngine struct {
+	events chan interpreter.NamespaceEvent
+
+	mu        sync.Mutex
+	committed map[string]bool
+	loaded    map[string]bool
+}
+
+func newGossipEngine() *gossipEngine {
+	return &gossipEngine{
+		events:    make(chan interpreter.NamespaceEvent, 1),
+		committed: make(map[string]bool),
+		loaded:    make(map[string]bool),
+	}
+}
+
+func (e *gossipEngine) NamespaceEvents() <-chan interpreter.NamespaceEvent {
+	return e.events
+}
+
+func (e *gossipEngine) LoadNamespace(ctx context.Context, db sql.DB, name string) (bool, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if !e.committed[name] || e.loaded[name] {
+		return false, nil
+	}
+	e.loaded[name] = true
+	return true, nil
+}
+
+func (e *gossipEngine) isLoaded(name string) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.loaded[name]
+}
+
+type gossipReadTx struct {
+	sql.OuterReadTx
+}
+
+func (gossipReadTx) Rollback(context.Context) error { return nil }
+
+type gossipDB struct{}
+
+func (gossipDB) BeginReadTx(context.Context) (sql.OuterReadTx, error) {
+	return gossipReadTx{}, nil
+}
+
+func TestNamespaceAnn(t *testing.T) {
+	for _, ann := range []namespaceAnn{
+		{Namespace: "ledger", Deployed: true},
+		{Namespace: "ledger"},
+	} {
+		data, err := ann.MarshalBinary()
+		require.NoError(t, err)
+
+		var decoded namespaceAnn
+		require.NoError(t, decoded.UnmarshalBinary(data))
+		require.Equal(t, ann, decoded)
+	}
+
+	var ann namespaceAnn
+	require.Error(t, ann.UnmarshalBinary([]byte{1}))
+	require.Error(t, ann.UnmarshalBinary([]byte{2, 'a'}))
+}
+
+func TestNamespaceGossip(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	t.Cleanup(func() {
+		cancel()
+		wg.Wait()
+	})
+
+	mn := mock.New()
+	engines := []*gossipEngine{newGossipEngine(), newGossipEngine()}
+	for _, engine := range engines {
+		_, h := newTestHost(t, mn)
+
+		ps, err := pubsub.NewGossipSub(ctx, h)
+		require.NoError(t, err)
+
+		g, err := NewNamespaceGossip(engine, gossipDB{}, nil)
+		require.NoError(t, err)
+		require.NoError(t, g.start(ctx, ps, h.ID(), &wg))
+	}
+	linkAll(t, mn)
+
+	// node 1 has committed the namespace that node 0 deployed, and loads it
+	// once it is announced
+	for _, engine := range engines {
+		engine.mu.Lock()
+		engine.committed["ledger"] = true
+		engine.mu.Unlock()
+	}
+
+	// the announcement is repeated until the gossip mesh is formed
+	require.Eventually(t, func() bool {
+		select {
+		case engines[0].events <- interpreter.NamespaceEvent{Namespace: "ledger", Deployed: true}:
+		default:
+		}
+		return engines[1].isLoaded("ledger")
+	}, 5*time.Second, 100*time.Millisecond)
+
+	// a node does not load the namespaces it announces itself
+	require.False(t, engines[0].isLoaded("ledger"))
+}
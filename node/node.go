@@ -134,6 +134,8 @@ type Node struct {
 	ss  SnapshotStore
 	bp  BlockProcessor
 
+	nsGossip *NamespaceGossip // nil if namespace gossip is disabled
+
 	// broadcast channels
 	ackChan  chan AckRes         // from consensus engine, to gossip to leader
 	resetMsg chan ConsensusReset // gossiped in from peers, to consensus engine
@@ -186,6 +188,8 @@ func NewNode(cfg *Config, opts ...Option) (*Node, error) {
 		ss:      cfg.Snapshotter,
 		bp:      cfg.BlockProc,
 
+		nsGossip: cfg.NamespaceGossip,
+
 		ackChan:         make(chan AckRes, 1),
 		resetMsg:        make(chan ConsensusReset, 1),
 		txQueue:         make(chan orderedTxn, txQueueSize),
@@ -325,6 +329,13 @@ func (n *Node) Start(ctx context.Context) error {
 		return err
 	}
 
+	if n.nsGossip != nil {
+		if err := n.nsGossip.start(ctx, ps, n.host.ID(), &n.wg); err != nil {
+			cancel()
+			return err
+		}
+	}
+
 	n.startOrderedTxQueueAnns(ctx)
 
 	/*
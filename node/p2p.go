@@ -7,6 +7,7 @@ import (
 	"net"
 	"path/filepath"
 	"strconv"
+	"time"
 
 	"github.com/kwilteam/kwil-db/config"
 	"github.com/kwilteam/kwil-db/core/crypto"
@@ -105,6 +106,9 @@ func NewP2PService(ctx context.Context, cfg *P2PServiceConfig, host host.Host) (
 		TargetConnections: cfg.KwilCfg.P2P.TargetConnections,
 		ConnGater:         wcg,
 		RequiredProtocols: RequiredStreamProtocols,
+		DialTimeout:       time.Duration(cfg.KwilCfg.P2P.DialTimeout),
+		ConnectRetries:    &cfg.KwilCfg.P2P.MaxRetries,
+		RetryBackoff:      time.Duration(cfg.KwilCfg.P2P.RetryBackoff),
 	}
 	pm, err := peers.NewPeerMan(pmCfg)
 	if err != nil {
@@ -2,6 +2,7 @@ package peers
 
 import (
 	"bytes"
+	"cmp"
 	"context"
 	"encoding/json"
 	"errors"
@@ -45,6 +46,8 @@ const (
 	reconnectRetries   = 58
 	baseReconnectDelay = 2 * time.Second
 	maxReconnectDelay  = 1 * time.Hour
+
+	defaultDialTimeout = 10 * time.Second
 )
 
 // PeerIDStringer provides lazy lazy conversion of a libp2p peer ID into a Kwil
@@ -100,6 +103,10 @@ type PeerMan struct {
 	seedMode          bool
 	crawlPeerInfos    map[peer.ID]crawlPeerInfo
 
+	dialTimeout    time.Duration
+	connectRetries int
+	retryBackoff   time.Duration
+
 	done  chan struct{}
 	close func()
 	wg    sync.WaitGroup
@@ -129,6 +136,18 @@ type Config struct {
 	Logger            log.Logger
 	ConnGater         *WhitelistGater
 	RequiredProtocols []protocol.ID
+
+	// DialTimeout limits each attempt to connect to a peer. If zero, 10
+	// seconds is used.
+	DialTimeout time.Duration
+	// ConnectRetries is the number of times a failed connection to a known
+	// peer is retried before the peer is removed. If nil, 58 is used, which
+	// with the default RetryBackoff keeps trying for about 48 hours.
+	ConnectRetries *int
+	// RetryBackoff is the delay before the first retry of a failed
+	// connection, which doubles with each retry up to an hour. If zero, 2
+	// seconds is used.
+	RetryBackoff time.Duration
 }
 
 type idService interface {
@@ -167,10 +186,16 @@ func NewPeerMan(cfg *Config) (*PeerMan, error) {
 		addrBook:          cfg.AddrBook,
 		crawlPeerInfos:    make(map[peer.ID]crawlPeerInfo),
 		targetConnections: cfg.TargetConnections,
+		dialTimeout:       cmp.Or(cfg.DialTimeout, defaultDialTimeout),
+		connectRetries:    reconnectRetries,
+		retryBackoff:      cmp.Or(cfg.RetryBackoff, baseReconnectDelay),
 		lastAttempt:       make(map[peer.ID]time.Time),
 		disconnects:       make(map[peer.ID]time.Time),
 		noReconnect:       make(map[peer.ID]bool),
 	}
+	if cfg.ConnectRetries != nil {
+		pm.connectRetries = max(*cfg.ConnectRetries, 0)
+	}
 
 	numPeers, err := pm.loadAddrBook()
 	if err != nil && !errors.Is(err, os.ErrNotExist) {
@@ -307,7 +332,8 @@ func (pm *PeerMan) maintainMinPeers(ctx context.Context) {
 				}
 				bk := lastAttempts[pid]
 				if bk == nil {
-					bk = newBackoffer(reconnectRetries, baseReconnectDelay, maxReconnectDelay, true)
+					// the first attempt is not a retry
+					bk = newBackoffer(pm.connectRetries+1, pm.retryBackoff, maxReconnectDelay, true)
 					lastAttempts[pid] = bk
 				}
 				if !bk.try() {
@@ -318,7 +344,7 @@ func (pm *PeerMan) maintainMinPeers(ctx context.Context) {
 					continue
 				}
 				pm.log.Infof("Connecting to peer %s", peerIDStringer(pid))
-				err := pm.h.Connect(ctx, peer.AddrInfo{ID: pid})
+				err := pm.dial(ctx, peer.AddrInfo{ID: pid})
 				if err != nil {
 					// NOTE: if this fails because of security protocol
 					// handshake failure (e.g. chain ID mismatch), we can't tell the precise reason.
@@ -356,7 +382,7 @@ func (pm *PeerMan) startPex(ctx context.Context) {
 					if pm.addPeerAddrs(peer) {
 						pm.log.Infof("Found new peer %v, connecting", peerIDStringer(peer.ID))
 						// TODO: connection manager, with limits
-						if err = pm.c.Connect(ctx, peer); err != nil {
+						if err = pm.dial(ctx, peer); err != nil {
 							pm.log.Warnf("Failed to connect to %s: %v", peerIDStringer(peer.ID), CompressDialError(err))
 						}
 					}
@@ -776,7 +802,7 @@ func (pm *PeerMan) loadAddrBook() (int, error) {
 			Protos: pInfo.Protos,
 		}
 
-		ttl := calculateBackoffTTL(baseReconnectDelay, maxReconnectDelay, reconnectRetries, true)
+		ttl := calculateBackoffTTL(pm.retryBackoff, maxReconnectDelay, pm.connectRetries, true)
 		if pm.addPeer(peerInfo, ttl) {
 			count++
 		}
@@ -818,7 +844,14 @@ func (pm *PeerMan) Connect(ctx context.Context, info AddrInfo) error {
 	if !pm.cg.IsAllowed(info.ID) {
 		return errors.New("peer not whitelisted while in private mode")
 	} // else it still wouldn't pass the connection gater, but we don't want to try or touch the peerstore
-	return CompressDialError(pm.c.Connect(ctx, peer.AddrInfo(info)))
+	return CompressDialError(pm.dial(ctx, peer.AddrInfo(info)))
+}
+
+// dial connects to a peer, giving up after the dial timeout.
+func (pm *PeerMan) dial(ctx context.Context, info peer.AddrInfo) error {
+	ctx, cancel := context.WithTimeout(ctx, pm.dialTimeout)
+	defer cancel()
+	return pm.c.Connect(ctx, info)
 }
 
 func (pm *PeerMan) Allow(p peer.ID) {
@@ -1092,14 +1125,11 @@ func (pm *PeerMan) reconnectWithRetry(ctx context.Context, peerID peer.ID) {
 		}
 
 		pm.log.Infof("Attempting reconnection to peer %s (attempt %d/%d)", peerIDStringer(peerID), attempt, maxRetries)
-		ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
-		err := pm.c.Connect(ctx, addrInfo)
+		err := pm.dial(ctx, addrInfo)
 		if err == nil {
-			cancel()
 			pm.log.Infof("Successfully reconnected to peer %s", peerIDStringer(peerID))
 			return
 		}
-		cancel()
 
 		if attempt >= maxRetries { // or bo.maxedOut
 			break
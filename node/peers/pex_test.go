@@ -183,3 +183,43 @@ func TestPeerDiscoverStream(t *testing.T) {
 		}
 	})
 }
+
+func TestMaintainMinPeersRetries(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		retries *int
+		removed bool
+	}{
+		{"no retries", new(int), true},
+		{"default retries", nil, false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			// the hosts are not linked, so they cannot connect
+			hosts, _ := makeTestHosts(t, 2)
+			h1, h2 := hosts[0], hosts[1]
+
+			pm, err := NewPeerMan(&Config{
+				AddrBook:          filepath.Join(t.TempDir(), "addrbook.json"),
+				Host:              h1,
+				TargetConnections: 1,
+				DialTimeout:       100 * time.Millisecond,
+				ConnectRetries:    tt.retries,
+			})
+			require.NoError(t, err)
+			h1.Peerstore().AddAddrs(h2.ID(), h2.Addrs(), time.Hour)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go pm.maintainMinPeers(ctx)
+
+			removed := func() bool {
+				return len(h1.Peerstore().Addrs(h2.ID())) == 0
+			}
+			if tt.removed {
+				require.Eventually(t, removed, 5*time.Second, 100*time.Millisecond)
+			} else {
+				require.Never(t, removed, 3*time.Second, 100*time.Millisecond)
+			}
+		})
+	}
+}
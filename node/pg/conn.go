@@ -124,6 +124,10 @@ func NewPool(ctx context.Context, cfg *PoolConfig) (*Pool, error) {
 		return nil, err
 	}
 
+	// timestamps are converted to and from text in the time zone of the
+	// session, so every node must use the same one
+	pCfg.ConnConfig.RuntimeParams["timezone"] = "UTC"
+
 	subscribers := syncmap.New[int64, chan<- string]()
 
 	// NOTE: we can consider changing the default exec mode at construction e.g.:
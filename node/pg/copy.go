@@ -0,0 +1,136 @@
+package pg
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/kwilteam/kwil-db/node/types/sql"
+)
+
+var _ sql.Copier = (*nestedTx)(nil)
+
+// CopyRows satisfies sql.Copier. The rows are sent in the text format of
+// COPY, and each value is encoded as text like the arguments of a query in
+// QueryModeExec, so that Postgres parses it with the type of its column.
+func (tx *nestedTx) CopyRows(ctx context.Context, schema, table string, columns []string,
+	next func() ([]any, bool, error)) (int64, error) {
+	return copyRows(ctx, tx.Conn(), schema, table, columns, next)
+}
+
+func copyRows(ctx context.Context, conn *pgx.Conn, schema, table string, columns []string,
+	next func() ([]any, bool, error)) (int64, error) {
+	stmt := fmt.Sprintf(`COPY %s (%s) FROM STDIN`,
+		pgx.Identifier{schema, table}.Sanitize(), strings.Join(quoteIdentifiers(columns), ", "))
+
+	// the rows are encoded as COPY reads them, so that they are not all held
+	// in memory
+	pr, pw := io.Pipe()
+	writeErr := make(chan error, 1)
+	go func() {
+		err := writeCopyRows(pw, conn.TypeMap(), len(columns), next)
+		// the error is sent before the pipe is closed, so that it is
+		// received if it failed the COPY
+		writeErr <- err
+		pw.CloseWithError(err)
+	}()
+
+	tag, err := conn.PgConn().CopyFrom(ctx, pr, stmt)
+	// unblocks the writer if COPY failed before reading all rows
+	pr.CloseWithError(io.ErrClosedPipe)
+	if err != nil {
+		// Postgres only returns the message of the error that failed the
+		// COPY, so the error itself is returned instead
+		select {
+		case wErr := <-writeErr:
+			if wErr != nil {
+				return 0, wErr
+			}
+		default:
+		}
+		if sql.IsFatalDBError(err) {
+			err = errors.Join(err, sql.ErrDBFailure)
+		}
+		return 0, err
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// writeCopyRows writes rows in the text format of COPY.
+func writeCopyRows(w io.Writer, m *pgtype.Map, numColumns int, next func() ([]any, bool, error)) error {
+	var line []byte
+	for {
+		row, ok, err := next()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+		if len(row) != numColumns {
+			return fmt.Errorf("expected %d values, got %d", numColumns, len(row))
+		}
+
+		line, err = appendCopyRow(line[:0], m, row)
+		if err != nil {
+			return err
+		}
+		if _, err = w.Write(line); err != nil {
+			return err
+		}
+	}
+}
+
+// appendCopyRow appends a row in the text format of COPY, with its values
+// separated by tabs and ended by a newline.
+func appendCopyRow(buf []byte, m *pgtype.Map, row []any) ([]byte, error) {
+	for i, v := range row {
+		if i > 0 {
+			buf = append(buf, '\t')
+		}
+
+		var oid uint32
+		if typ, ok := m.TypeForValue(v); ok {
+			oid = typ.OID
+		}
+		text, err := m.Encode(oid, pgtype.TextFormatCode, v, nil)
+		if err != nil {
+			return nil, fmt.Errorf("value %d: %w", i+1, err)
+		}
+		if text == nil {
+			buf = append(buf, `\N`...)
+			continue
+		}
+		buf = appendCopyText(buf, text)
+	}
+
+	return append(buf, '\n'), nil
+}
+
+// copyTextEscaper escapes the characters that have a special meaning in the
+// text format of COPY.
+var copyTextEscaper = strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`)
+
+// appendCopyText appends a value in the text format of COPY.
+func appendCopyText(buf, text []byte) []byte {
+	if !bytes.ContainsAny(text, "\\\t\n\r") {
+		return append(buf, text...)
+	}
+	return append(buf, copyTextEscaper.Replace(string(text))...)
+}
+
+// quoteIdentifiers quotes identifiers for use in a statement.
+func quoteIdentifiers(idents []string) []string {
+	quoted := make([]string, len(idents))
+	for i, ident := range idents {
+		quoted[i] = pgx.Identifier{ident}.Sanitize()
+	}
+	return quoted
+}
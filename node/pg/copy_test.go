@@ -0,0 +1,63 @@
+package pg
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kwilteam/kwil-db/core/types"
+)
+
+func Test_AppendCopyRow(t *testing.T) {
+	m := pgtype.NewMap()
+
+	tests := []struct {
+		name string
+		row  []any
+		want string
+	}{
+		{name: "scalars", row: []any{int64(1), "a", true}, want: "1\ta\tt\n"},
+		{name: "null", row: []any{int64(1), nil}, want: "1\t\\N\n"},
+		{name: "special characters", row: []any{"tab\there", "new\nline\r", `back\slash`}, want: "tab\\there\tnew\\nline\\r\tback\\\\slash\n"},
+		{name: "bytea", row: []any{[]byte{1, 2}}, want: "\\\\x0102\n"},
+		{name: "decimal", row: []any{types.MustParseDecimal("1.50")}, want: "1.50\n"},
+		{name: "array", row: []any{[]string{"a", "b"}}, want: "{a,b}\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := appendCopyRow(nil, m, tt.row)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, string(got))
+		})
+	}
+}
+
+func Test_WriteCopyRows(t *testing.T) {
+	rows := [][]any{{int64(1), "a"}, {int64(2), nil}}
+	next := func() ([]any, bool, error) {
+		if len(rows) == 0 {
+			return nil, false, nil
+		}
+		row := rows[0]
+		rows = rows[1:]
+		return row, true, nil
+	}
+
+	var buf strings.Builder
+	require.NoError(t, writeCopyRows(&buf, pgtype.NewMap(), 2, next))
+	require.Equal(t, "1\ta\n2\t\\N\n", buf.String())
+
+	// rows must have a value for each column
+	rows = [][]any{{int64(1)}}
+	require.Error(t, writeCopyRows(io.Discard, pgtype.NewMap(), 2, next))
+
+	// errors of next are returned
+	errNext := errors.New("next failed")
+	err := writeCopyRows(io.Discard, pgtype.NewMap(), 2, func() ([]any, bool, error) { return nil, false, errNext })
+	require.ErrorIs(t, err, errNext)
+}
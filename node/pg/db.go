@@ -465,6 +465,47 @@ func (db *DB) BeginReservedReadTx(ctx context.Context) (sql.Tx, error) {
 	}, nil
 }
 
+// simulationLockTimeout is how long a statement of a simulation transaction
+// waits for a lock, such as that of a row written by the uncommitted block
+// transaction, before failing.
+const simulationLockTimeout = "50ms"
+
+// BeginSimulationTx starts a read-write transaction on a reader connection
+// that can never be committed. It is used to run calls that modify the
+// database without keeping their changes. It reads from a snapshot taken when
+// it starts, and fails, rather than waits, when it needs a lock held by
+// another transaction, so that it does not wait on the block transaction. The
+// rows it writes stay locked until it is rolled back, so it should be short
+// lived.
+func (db *DB) BeginSimulationTx(ctx context.Context) (sql.Tx, error) {
+	conn, err := db.pool.readers.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := conn.BeginTx(ctx, pgx.TxOptions{
+		AccessMode: pgx.ReadWrite,
+		IsoLevel:   pgx.RepeatableRead,
+	})
+	if err != nil {
+		conn.Release()
+		return nil, err
+	}
+	if _, err = tx.Exec(ctx, "SET LOCAL lock_timeout = '"+simulationLockTimeout+"'"); err != nil {
+		tx.Rollback(ctx)
+		conn.Release()
+		return nil, err
+	}
+
+	return &simulationTx{
+		nestedTx: &nestedTx{
+			Tx:         tx,
+			accessMode: sql.ReadWrite,
+			oidTypes:   db.pool.idTypes,
+		},
+		release: sync.OnceFunc(conn.Release),
+	}, nil
+}
+
 // BeginDelayedReadTx returns a valid SQL transaction, but will only
 // start the transaction once the first query is executed. This is useful
 // for when a calling module is expected to control the lifetime of a read
@@ -4,6 +4,7 @@ package pg
 
 import (
 	"context"
+	"errors"
 
 	"github.com/jackc/pgx/v5"
 
@@ -152,6 +153,31 @@ func (tx *readTx) Subscribe(ctx context.Context) (ch <-chan string, done func(co
 	return subscribe(ctx, tx, tx.subscribers)
 }
 
+// simulationTx is a read-write transaction that can never be committed.
+type simulationTx struct {
+	*nestedTx
+	release func()
+}
+
+// Commit rolls back the transaction and returns an error, since the changes
+// of a simulation are never kept. It will unconditionally return the
+// connection to the pool.
+func (tx *simulationTx) Commit(ctx context.Context) error {
+	defer tx.release()
+
+	if err := tx.nestedTx.Rollback(ctx); err != nil {
+		return err
+	}
+	return errors.New("a simulation transaction cannot be committed")
+}
+
+// Rollback will unconditionally return the connection to the pool.
+func (tx *simulationTx) Rollback(ctx context.Context) error {
+	defer tx.release()
+
+	return tx.nestedTx.Rollback(ctx)
+}
+
 // delayedReadTx is a tx that handles a read-only transaction.
 // It is delayed, meaning that the tx will only be actually started
 // when the first query is executed. This is useful for when a calling
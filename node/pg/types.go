@@ -5,10 +5,13 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"math"
 	"math/big"
+	"net/netip"
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/kwilteam/kwil-db/core/types"
@@ -22,6 +25,10 @@ func init() {
 	registerDatatype(blobType, blobArrayType)
 	registerDatatype(uuidType, uuidArrayType)
 	registerDatatype(decimalType, decimalArrayType)
+	registerDatatype(timestamptzType, timestamptzArrayType)
+	registerDatatype(intervalType, intervalArrayType)
+	registerDatatype(inetType, inetArrayType)
+	registerDatatype(cidrType, cidrArrayType)
 }
 
 var (
@@ -650,8 +657,353 @@ var (
 		SerializeChangeset:   arrayFromChildFunc(2, decimalType.SerializeChangeset),
 		DeserializeChangeset: deserializeArrayFn[types.Decimal](2, decimalType.DeserializeChangeset),
 	}
+
+	timestamptzType = &datatype{
+		KwilType: types.TimestampTZType,
+		Matches:  []reflect.Type{reflect.TypeFor[time.Time](), reflect.TypeFor[*time.Time]()},
+		OID:      func(*pgtype.Map) uint32 { return pgtype.TimestamptzOID },
+		EncodeInferred: func(v any) (any, error) {
+			var ts *time.Time
+			switch v := v.(type) {
+			case time.Time:
+				ts = &v
+			case *time.Time:
+				ts = v
+			case nil:
+			default:
+				return nil, fmt.Errorf("unexpected type encoding timestamptz %T", v)
+			}
+			if ts == nil {
+				return pgtype.Timestamptz{Valid: false}, nil
+			}
+
+			return pgtype.Timestamptz{Time: ts.UTC(), Valid: true}, nil
+		},
+		Decode: func(a any) (any, error) {
+			switch v := a.(type) {
+			case time.Time:
+				return v.UTC(), nil
+			case pgtype.Timestamptz:
+				if !v.Valid {
+					return nil, nil
+				}
+				if v.InfinityModifier != pgtype.Finite {
+					return nil, errors.New("infinite timestamps are not supported")
+				}
+				return v.Time.UTC(), nil
+			default:
+				return nil, fmt.Errorf("unexpected type decoding timestamptz %T", a)
+			}
+		},
+		SerializeChangeset: func(value string) ([]byte, error) {
+			if value == `NULL` {
+				return nil, nil
+			}
+			ts, err := parsePGTimestamptz(value)
+			if err != nil {
+				return nil, err
+			}
+
+			buf := make([]byte, 8)
+			binary.LittleEndian.PutUint64(buf, uint64(ts.UnixMicro()))
+			return buf, nil
+		},
+		DeserializeChangeset: func(b []byte) (any, error) {
+			if len(b) == 0 {
+				return nil, nil
+			}
+			if len(b) != 8 {
+				return nil, fmt.Errorf("invalid timestamptz: %x", b)
+			}
+			return time.UnixMicro(int64(binary.LittleEndian.Uint64(b))).UTC(), nil
+		},
+	}
+
+	timestamptzArrayType = &datatype{
+		KwilType:       types.TimestampTZArrayType,
+		Matches:        []reflect.Type{reflect.TypeFor[[]time.Time](), reflect.TypeFor[[]*time.Time]()},
+		OID:            func(*pgtype.Map) uint32 { return pgtype.TimestamptzArrayOID },
+		EncodeInferred: defaultEncodeDecode,
+		Decode:         decodePtrArray[time.Time](timestamptzType.Decode),
+		SerializeChangeset: func(value string) ([]byte, error) {
+			// the elements are quoted, since they contain spaces
+			value, ok := trimCurlys(value)
+			if !ok {
+				return nil, fmt.Errorf("invalid timestamptz array: %s", value)
+			}
+
+			return serializeArray(pgStringArraySplit(value), 1, timestamptzType.SerializeChangeset)
+		},
+		DeserializeChangeset: deserializeArrayFn[time.Time](1, timestamptzType.DeserializeChangeset),
+	}
+
+	intervalType = &datatype{
+		KwilType: types.IntervalType,
+		Matches:  []reflect.Type{reflect.TypeFor[time.Duration](), reflect.TypeFor[*time.Duration]()},
+		OID:      func(*pgtype.Map) uint32 { return pgtype.IntervalOID },
+		EncodeInferred: func(v any) (any, error) {
+			var d *time.Duration
+			switch v := v.(type) {
+			case time.Duration:
+				d = &v
+			case *time.Duration:
+				d = v
+			case nil:
+			default:
+				return nil, fmt.Errorf("unexpected type encoding interval %T", v)
+			}
+			if d == nil {
+				return pgtype.Interval{Valid: false}, nil
+			}
+
+			return pgIntervalFromDuration(*d), nil
+		},
+		Decode: func(a any) (any, error) {
+			switch v := a.(type) {
+			case time.Duration:
+				return v, nil
+			case pgtype.Interval:
+				if !v.Valid {
+					return nil, nil
+				}
+				return durationFromPGInterval(v)
+			default:
+				return nil, fmt.Errorf("unexpected type decoding interval %T", a)
+			}
+		},
+		SerializeChangeset: func(value string) ([]byte, error) {
+			if value == `NULL` {
+				return nil, nil
+			}
+			d, err := types.ParseInterval(value)
+			if err != nil {
+				return nil, err
+			}
+
+			buf := make([]byte, 8)
+			binary.LittleEndian.PutUint64(buf, uint64(d.Microseconds()))
+			return buf, nil
+		},
+		DeserializeChangeset: func(b []byte) (any, error) {
+			if len(b) == 0 {
+				return nil, nil
+			}
+			if len(b) != 8 {
+				return nil, fmt.Errorf("invalid interval: %x", b)
+			}
+			return time.Duration(int64(binary.LittleEndian.Uint64(b))) * time.Microsecond, nil
+		},
+	}
+
+	intervalArrayType = &datatype{
+		KwilType:       types.IntervalArrayType,
+		Matches:        []reflect.Type{reflect.TypeFor[[]time.Duration](), reflect.TypeFor[[]*time.Duration]()},
+		OID:            func(*pgtype.Map) uint32 { return pgtype.IntervalArrayOID },
+		EncodeInferred: defaultEncodeDecode,
+		Decode:         decodePtrArray[time.Duration](intervalType.Decode),
+		SerializeChangeset: func(value string) ([]byte, error) {
+			// like timestamptz arrays, the elements are quoted
+			value, ok := trimCurlys(value)
+			if !ok {
+				return nil, fmt.Errorf("invalid interval array: %s", value)
+			}
+
+			return serializeArray(pgStringArraySplit(value), 1, intervalType.SerializeChangeset)
+		},
+		DeserializeChangeset: deserializeArrayFn[time.Duration](1, intervalType.DeserializeChangeset),
+	}
+
+	inetType = &datatype{
+		KwilType: types.InetType,
+		Matches:  []reflect.Type{reflect.TypeFor[netip.Addr](), reflect.TypeFor[*netip.Addr]()},
+		OID:      func(*pgtype.Map) uint32 { return pgtype.InetOID },
+		EncodeInferred: func(v any) (any, error) {
+			switch v := v.(type) {
+			case netip.Addr:
+				return v, nil
+			case *netip.Addr:
+				if v == nil {
+					return nil, nil
+				}
+				return *v, nil
+			case nil:
+				return nil, nil
+			default:
+				return nil, fmt.Errorf("unexpected type encoding inet %T", v)
+			}
+		},
+		Decode: func(a any) (any, error) {
+			switch v := a.(type) {
+			case netip.Addr:
+				return v, nil
+			case netip.Prefix:
+				// pgx decodes inets as prefixes
+				if v.Bits() != v.Addr().BitLen() {
+					return nil, fmt.Errorf("inet %s has a netmask, which is not supported", v)
+				}
+				return v.Addr(), nil
+			default:
+				return nil, fmt.Errorf("unexpected type decoding inet %T", a)
+			}
+		},
+		SerializeChangeset: func(value string) ([]byte, error) {
+			if value == `NULL` {
+				return nil, nil
+			}
+			addr, err := types.ParseInet(value)
+			if err != nil {
+				return nil, err
+			}
+
+			return addr.MarshalBinary()
+		},
+		DeserializeChangeset: func(b []byte) (any, error) {
+			if len(b) == 0 {
+				return nil, nil
+			}
+			var addr netip.Addr
+			if err := addr.UnmarshalBinary(b); err != nil {
+				return nil, err
+			}
+			return addr, nil
+		},
+	}
+
+	inetArrayType = &datatype{
+		KwilType:       types.InetArrayType,
+		Matches:        []reflect.Type{reflect.TypeFor[[]netip.Addr](), reflect.TypeFor[[]*netip.Addr]()},
+		OID:            func(*pgtype.Map) uint32 { return pgtype.InetArrayOID },
+		EncodeInferred: defaultEncodeDecode,
+		Decode:         decodePtrArray[netip.Addr](inetType.Decode),
+		SerializeChangeset: func(value string) ([]byte, error) {
+			value, ok := trimCurlys(value)
+			if !ok {
+				return nil, fmt.Errorf("invalid inet array: %s", value)
+			}
+
+			return serializeArray(pgStringArraySplit(value), 1, inetType.SerializeChangeset)
+		},
+		DeserializeChangeset: deserializeArrayFn[netip.Addr](1, inetType.DeserializeChangeset),
+	}
+
+	cidrType = &datatype{
+		KwilType: types.CIDRType,
+		Matches:  []reflect.Type{reflect.TypeFor[netip.Prefix](), reflect.TypeFor[*netip.Prefix]()},
+		OID:      func(*pgtype.Map) uint32 { return pgtype.CIDROID },
+		EncodeInferred: func(v any) (any, error) {
+			switch v := v.(type) {
+			case netip.Prefix:
+				return v, nil
+			case *netip.Prefix:
+				if v == nil {
+					return nil, nil
+				}
+				return *v, nil
+			case nil:
+				return nil, nil
+			default:
+				return nil, fmt.Errorf("unexpected type encoding cidr %T", v)
+			}
+		},
+		Decode: func(a any) (any, error) {
+			v, ok := a.(netip.Prefix)
+			if !ok {
+				return nil, fmt.Errorf("unexpected type decoding cidr %T", a)
+			}
+			return v, nil
+		},
+		SerializeChangeset: func(value string) ([]byte, error) {
+			if value == `NULL` {
+				return nil, nil
+			}
+			prefix, err := types.ParseCIDR(value)
+			if err != nil {
+				return nil, err
+			}
+
+			return prefix.MarshalBinary()
+		},
+		DeserializeChangeset: func(b []byte) (any, error) {
+			if len(b) == 0 {
+				return nil, nil
+			}
+			var prefix netip.Prefix
+			if err := prefix.UnmarshalBinary(b); err != nil {
+				return nil, err
+			}
+			return prefix, nil
+		},
+	}
+
+	cidrArrayType = &datatype{
+		KwilType:       types.CIDRArrayType,
+		Matches:        []reflect.Type{reflect.TypeFor[[]netip.Prefix](), reflect.TypeFor[[]*netip.Prefix]()},
+		OID:            func(*pgtype.Map) uint32 { return pgtype.CIDRArrayOID },
+		EncodeInferred: defaultEncodeDecode,
+		Decode:         decodePtrArray[netip.Prefix](cidrType.Decode),
+		SerializeChangeset: func(value string) ([]byte, error) {
+			value, ok := trimCurlys(value)
+			if !ok {
+				return nil, fmt.Errorf("invalid cidr array: %s", value)
+			}
+
+			return serializeArray(pgStringArraySplit(value), 1, cidrType.SerializeChangeset)
+		},
+		DeserializeChangeset: deserializeArrayFn[netip.Prefix](1, cidrType.DeserializeChangeset),
+	}
 )
 
+// pgIntervalFromDuration converts a duration to a Postgres interval of days
+// and microseconds, so that it is formatted like types.FormatInterval.
+func pgIntervalFromDuration(d time.Duration) pgtype.Interval {
+	micros := d.Microseconds()
+	const microsPerDay = 24 * 60 * 60 * 1_000_000
+	return pgtype.Interval{
+		Days:         int32(micros / microsPerDay),
+		Microseconds: micros % microsPerDay,
+		Valid:        true,
+	}
+}
+
+// durationFromPGInterval converts a Postgres interval to a duration. Intervals
+// of months cannot be converted, since their length varies.
+func durationFromPGInterval(v pgtype.Interval) (time.Duration, error) {
+	if v.Months != 0 {
+		return 0, errors.New("intervals of months are not supported")
+	}
+
+	const microsPerDay = 24 * 60 * 60 * 1_000_000
+	const maxMicros = math.MaxInt64 / 1_000
+	const maxDays = maxMicros / microsPerDay
+	if v.Days > maxDays || v.Days < -maxDays {
+		return 0, errors.New("interval out of range")
+	}
+
+	micros := int64(v.Days)*microsPerDay + v.Microseconds
+	if micros > maxMicros || micros < -maxMicros {
+		return 0, errors.New("interval out of range")
+	}
+	return time.Duration(micros) * time.Microsecond, nil
+}
+
+// pgTimestamptzLayouts are the layouts of timestamptz values in the text
+// format of Postgres, with the ISO date style. Offsets include minutes only if
+// they are not whole hours.
+var pgTimestamptzLayouts = []string{"2006-01-02 15:04:05.999999Z07", "2006-01-02 15:04:05.999999Z07:00"}
+
+// parsePGTimestamptz parses a timestamptz in the text format of Postgres.
+func parsePGTimestamptz(value string) (time.Time, error) {
+	var err error
+	for _, layout := range pgTimestamptzLayouts {
+		var ts time.Time
+		ts, err = time.Parse(layout, value)
+		if err == nil {
+			return ts.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid timestamptz %s: %w", value, err)
+}
+
 // defaultEncodeDecode is the default Encode and Decode function for data types.
 // It simply returns the value as is, without any modifications.
 func defaultEncodeDecode(v any) (any, error) { return v, nil }
@@ -2,8 +2,13 @@ package pg
 
 import (
 	"encoding/binary"
+	"math"
+	"net/netip"
 	"testing"
+	"time"
 
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/kwilteam/kwil-db/core/types"
 	"github.com/stretchr/testify/require"
 )
 
@@ -152,3 +157,77 @@ func TestSplitString(t *testing.T) {
 		})
 	}
 }
+
+func Test_TimestamptzChangeset(t *testing.T) {
+	want := time.Date(2024, 3, 1, 4, 30, 0, 123456000, time.UTC)
+
+	// the offset is that of the session, but the point in time is the same
+	for _, value := range []string{"2024-03-01 04:30:00.123456+00", "2024-02-29 23:30:00.123456-05", "2024-03-01 10:00:00.123456+05:30"} {
+		b, err := timestamptzType.SerializeChangeset(value)
+		require.NoError(t, err)
+
+		ts, err := timestamptzType.DeserializeChangeset(b)
+		require.NoError(t, err)
+		require.Equal(t, want, ts, value)
+	}
+
+	b, err := timestamptzArrayType.SerializeChangeset(`{"2024-03-01 04:30:00.123456+00",NULL}`)
+	require.NoError(t, err)
+	arr, err := timestamptzArrayType.DeserializeChangeset(b)
+	require.NoError(t, err)
+	require.Equal(t, []*time.Time{&want, nil}, arr)
+
+	_, err = timestamptzType.SerializeChangeset("not a timestamp")
+	require.Error(t, err)
+}
+
+func Test_IntervalChangeset(t *testing.T) {
+	for _, value := range []string{"1 day 02:03:04.5", "-1 days", "-00:00:00.000001"} {
+		b, err := intervalType.SerializeChangeset(value)
+		require.NoError(t, err)
+
+		d, err := intervalType.DeserializeChangeset(b)
+		require.NoError(t, err)
+		require.Equal(t, value, types.FormatInterval(d.(time.Duration)))
+	}
+
+	_, err := intervalType.SerializeChangeset("1 mon")
+	require.Error(t, err)
+}
+
+func Test_PGInterval(t *testing.T) {
+	// durations are split into days and microseconds, like they are formatted
+	pgInt := pgIntervalFromDuration(-26 * time.Hour)
+	require.Equal(t, pgtype.Interval{Days: -1, Microseconds: -2 * 60 * 60 * 1_000_000, Valid: true}, pgInt)
+
+	d, err := durationFromPGInterval(pgInt)
+	require.NoError(t, err)
+	require.Equal(t, -26*time.Hour, d)
+
+	_, err = durationFromPGInterval(pgtype.Interval{Months: 1, Valid: true})
+	require.Error(t, err)
+
+	_, err = durationFromPGInterval(pgtype.Interval{Days: math.MaxInt32, Valid: true})
+	require.Error(t, err)
+}
+
+func Test_InetChangeset(t *testing.T) {
+	b, err := inetArrayType.SerializeChangeset(`{192.168.1.1,2001:db8::1,NULL}`)
+	require.NoError(t, err)
+	arr, err := inetArrayType.DeserializeChangeset(b)
+	require.NoError(t, err)
+	v4, v6 := netip.MustParseAddr("192.168.1.1"), netip.MustParseAddr("2001:db8::1")
+	require.Equal(t, []*netip.Addr{&v4, &v6, nil}, arr)
+
+	b, err = cidrType.SerializeChangeset("10.0.0.0/8")
+	require.NoError(t, err)
+	prefix, err := cidrType.DeserializeChangeset(b)
+	require.NoError(t, err)
+	require.Equal(t, netip.MustParsePrefix("10.0.0.0/8"), prefix)
+
+	// inets with a netmask cannot be converted to an address
+	_, err = inetType.SerializeChangeset("192.168.1.5/24")
+	require.Error(t, err)
+	_, err = inetType.Decode(netip.MustParsePrefix("192.168.1.5/24"))
+	require.Error(t, err)
+}
@@ -44,8 +44,9 @@ const (
 type contextRPCKey string
 
 const (
-	RequestIPCtx contextRPCKey = "clientIP"
-	ServerCtx    contextRPCKey = "server"
+	RequestIPCtx      contextRPCKey = "clientIP"
+	ServerCtx         contextRPCKey = "server"
+	RequestHeadersCtx contextRPCKey = "headers" // http.Header of the request
 )
 
 // Server is a JSON-RPC server.
@@ -279,6 +280,7 @@ func NewServer(addr string, log log.Logger, opts ...Opt) (*Server, error) {
 	}
 	h = compMW(h)
 	h = realIPHandler(h, cfg.proxyCount) // for effective rate limiting
+	h = requestHeadersHandler(h)
 
 	// h = recoverer(h, log) // first, wrap with defer and call next ^
 
@@ -392,7 +394,8 @@ func jsonRPCTimeoutHandler(h http.Handler, timeout time.Duration, logger log.Log
 // NOTE: if this server is served behind KGW, those headers will be stripped.
 func corsHandler(h http.Handler) http.Handler {
 	allowMethods := "GET, POST, OPTIONS"
-	allowHeaders := "Accept, Content-Type, Content-Length, Accept-Encoding, Authorization, ResponseType, Range"
+	allowHeaders := "Accept, Content-Type, Content-Length, Accept-Encoding, Authorization, ResponseType, Range, " +
+		jsonrpc.HeaderRequestID + ", " + jsonrpc.HeaderTraceParent
 
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", r.Header.Get("Origin"))
@@ -441,6 +444,25 @@ func realIPHandler(h http.Handler, trustedProxyCount int) http.Handler {
 	})
 }
 
+// requestHeadersHandler makes the headers of a request available to the method
+// handlers.
+func requestHeadersHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r = r.WithContext(context.WithValue(r.Context(), RequestHeadersCtx, r.Header.Clone()))
+		h.ServeHTTP(w, r)
+	})
+}
+
+// requestID returns the request ID that the client set with the X-Request-ID
+// header, or an empty string if it did not set one.
+func requestID(ctx context.Context) string {
+	headers, ok := ctx.Value(RequestHeadersCtx).(http.Header)
+	if !ok {
+		return ""
+	}
+	return headers.Get(jsonrpc.HeaderRequestID)
+}
+
 func addrHost(addr string) string {
 	if net.ParseIP(addr) != nil {
 		return addr
@@ -657,6 +679,9 @@ func (s *Server) handlerJSONRPCV1(w http.ResponseWriter, r *http.Request) {
 func (s *Server) processJSONRPCRequest(ctx context.Context, w http.ResponseWriter, req *jsonrpc.Request) {
 	// Handle and time the request.
 	resp := s.handleJSONRPCRequest(ctx, req)
+	if resp.Error != nil {
+		resp.Error.RequestID = requestID(ctx)
+	}
 
 	// Some conventions dictate 200 for everything, with the Response.Error
 	// being the only sign of issue. However, a certain set of errors warrant an
@@ -731,14 +756,17 @@ func (s *Server) handleJSONRPCRequest(ctx context.Context, req *jsonrpc.Request)
 		case jsonrpc.ErrorInvalidParams, jsonrpc.ErrorInvalidRequest,
 			jsonrpc.ErrorParse, jsonrpc.ErrorUnknownMethod,
 			jsonrpc.ErrorTxNotFound, jsonrpc.ErrorBlkNotFound,
-			jsonrpc.ErrorEngineDatasetNotFound:
+			jsonrpc.ErrorEngineDatasetNotFound, jsonrpc.ErrorEngineNotHomeNode:
 			level = log.LevelDebug
 		case jsonrpc.ErrorInternal, jsonrpc.ErrorTimeout, jsonrpc.ErrorResultEncoding:
 			level = log.LevelWarn
 		}
-		s.log.Log(level, "request failure", "method", req.Method,
-			"elapsed", time.Since(t0), "code", rpcErr.Code,
-			"message", rpcErr.Message)
+		logArgs := []any{"method", req.Method, "elapsed", time.Since(t0),
+			"code", rpcErr.Code, "message", rpcErr.Message}
+		if reqID := requestID(ctx); reqID != "" {
+			logArgs = append(logArgs, "request_id", reqID)
+		}
+		s.log.Log(level, "request failure", logArgs...)
 
 		return jsonrpc.NewErrorResponse(req.ID, rpcErr)
 	}
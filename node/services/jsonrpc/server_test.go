@@ -1,11 +1,13 @@
 package rpcserver
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
 	"slices"
 	"strings"
@@ -16,6 +18,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/kwilteam/kwil-db/core/log"
+	rpcclient "github.com/kwilteam/kwil-db/core/rpc/client"
 	jsonrpc "github.com/kwilteam/kwil-db/core/rpc/json"
 )
 
@@ -82,7 +85,7 @@ func Test_options(t *testing.T) {
 
 	wantCorsHeaders := http.Header{
 		"Access-Control-Allow-Credentials": {"true"},
-		"Access-Control-Allow-Headers":     {strings.Join([]string{"Accept", "Content-Type", "Content-Length", "Accept-Encoding", "Authorization", "ResponseType", "Range"}, ", ")},
+		"Access-Control-Allow-Headers":     {strings.Join([]string{"Accept", "Content-Type", "Content-Length", "Accept-Encoding", "Authorization", "ResponseType", "Range", "X-Request-ID", "traceparent"}, ", ")},
 		"Access-Control-Allow-Methods":     {strings.Join([]string{http.MethodGet, http.MethodPost, http.MethodOptions}, ", ")},
 		"Access-Control-Allow-Origin":      {testOrigin},
 	}
@@ -219,3 +222,38 @@ func Test_options(t *testing.T) {
 		})
 	}
 }
+
+func Test_requestID(t *testing.T) {
+	var logs bytes.Buffer
+	logger := log.New(log.WithWriter(&logs), log.WithLevel(log.LevelDebug))
+
+	srv, err := NewServer("127.0.0.1:", logger)
+	require.NoError(t, err)
+
+	srv.RegisterMethodHandler(
+		"rpc.fail",
+		MakeMethodHandler(func(context.Context, *any) (*json.RawMessage, *jsonrpc.Error) {
+			return nil, jsonrpc.NewError(jsonrpc.ErrorEngineInternal, "action failed", nil)
+		}),
+	)
+
+	hs := httptest.NewServer(srv.srv.Handler)
+	defer hs.Close()
+
+	u, err := url.Parse(hs.URL)
+	require.NoError(t, err)
+	cl := rpcclient.NewJSONRPCClient(u)
+
+	const reqID = "trace-7f3a/call 1"
+	ctx := rpcclient.ContextWithRequestID(context.Background(), reqID)
+	err = cl.CallMethod(ctx, "rpc.fail", nil, new(json.RawMessage))
+	require.Error(t, err)
+
+	// the ID is returned in the error response...
+	var rpcErr *rpcclient.RPCError
+	require.ErrorAs(t, err, &rpcErr)
+	assert.Equal(t, reqID, rpcErr.RequestID)
+
+	// ...and logged with the failure
+	assert.Contains(t, logs.String(), reqID)
+}
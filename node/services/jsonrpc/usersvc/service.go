@@ -9,7 +9,9 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"net/http"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -36,6 +38,7 @@ import (
 type EngineReader interface {
 	Call(ctx *common.EngineContext, tx sql.DB, namespace, action string, args []any, resultFn func(*common.Row) error) (*common.CallResult, error)
 	Execute(ctx *common.EngineContext, tx sql.DB, query string, params map[string]any, resultFn func(*common.Row) error) error
+	ComparePlans(ctx context.Context, db sql.DB, namespace, action string, args []any, baselineID string) (*types.PlanComparison, error)
 }
 
 type BlockchainTransactor interface {
@@ -48,6 +51,7 @@ type BlockchainTransactor interface {
 
 type NodeApp interface {
 	AccountInfo(ctx context.Context, db sql.DB, account *types.AccountID, pending bool) (balance *big.Int, nonce int64, err error)
+	TokenBalance(ctx context.Context, db sql.DB, account *types.AccountID, token []byte) (*big.Int, error)
 	NumAccounts(ctx context.Context, db sql.Executor) (count, height int64, err error)
 	Price(ctx context.Context, dbTx sql.DB, tx *types.Transaction) (*big.Int, error)
 	GetMigrationMetadata(ctx context.Context) (*types.MigrationMetadata, error)
@@ -90,6 +94,7 @@ type Service struct {
 type DB interface {
 	sql.ReadTxMaker
 	sql.DelayedReadTxMaker
+	sql.SimulationTxMaker
 }
 
 type serviceCfg struct {
@@ -311,6 +316,16 @@ func (svc *Service) Methods() map[jsonrpc.Method]rpcserver.MethodDef {
 			"call an action",
 			"the result of the action call as a encoded records",
 		),
+		userjson.MethodComparePlans: rpcserver.MakeMethodDef(
+			svc.ComparePlans,
+			"compare the query plans of an action against a stored baseline",
+			"the nodes added to and removed from the plans, and the change in estimated cost",
+		),
+		userjson.MethodSimulateCall: rpcserver.MakeMethodDef(
+			svc.SimulateCall,
+			"simulate an action call without keeping its changes",
+			"the logs, result rows, and gas usage of the action, and whether it would succeed",
+		),
 		userjson.MethodChainInfo: rpcserver.MakeMethodDef(
 			svc.ChainInfo,
 			"get current blockchain info",
@@ -610,6 +625,9 @@ func (svc *Service) Account(ctx context.Context, req *userjson.AccountRequest) (
 	if req.ID == nil || len(req.ID.Identifier) == 0 {
 		return nil, jsonrpc.NewError(jsonrpc.ErrorInvalidParams, "missing account identifier", nil)
 	}
+	if err := types.ValidateTokenID(req.TokenID); err != nil {
+		return nil, jsonrpc.NewError(jsonrpc.ErrorInvalidParams, err.Error(), nil)
+	}
 
 	readTx := svc.db.BeginDelayedReadTx()
 	defer readTx.Rollback(ctx)
@@ -619,6 +637,14 @@ func (svc *Service) Account(ctx context.Context, req *userjson.AccountRequest) (
 		return nil, jsonrpc.NewError(jsonrpc.ErrorAccountInternal, "account info error", nil)
 	}
 
+	// the nonce is that of the account, but the balance is of the token
+	if len(req.TokenID) > 0 {
+		balance, err = svc.nodeApp.TokenBalance(ctx, readTx, req.ID, req.TokenID)
+		if err != nil {
+			return nil, jsonrpc.NewError(jsonrpc.ErrorAccountInternal, "token balance error", nil)
+		}
+	}
+
 	var ident *types.AccountID
 	var zeroBal big.Int
 	if nonce > 0 || balance.Cmp(&zeroBal) > 0 { // return nil pubkey for non-existent account
@@ -673,6 +699,16 @@ func engineError(err error) *jsonrpc.Error {
 	if err == nil {
 		return nil // would not be constructing a jsonrpc.Error
 	}
+
+	var notHome *engine.NotHomeNodeError
+	if errors.As(err, &notHome) {
+		data, _ := json.Marshal(&userjson.NotHomeNodeError{
+			Namespace: notHome.Namespace,
+			HomeNode:  notHome.HomeNode,
+		})
+		return jsonrpc.NewError(jsonrpc.ErrorEngineNotHomeNode, err.Error(), data)
+	}
+
 	code, msg := checkEngineError(err)
 	return &jsonrpc.Error{
 		Code:    code,
@@ -680,6 +716,24 @@ func engineError(err error) *jsonrpc.Error {
 	}
 }
 
+// requestMetadata returns the headers of the RPC request as engine call
+// metadata, keyed by lower case name. Only the first value of each header is
+// used.
+func requestMetadata(ctx context.Context) map[string]string {
+	headers, _ := ctx.Value(rpcserver.RequestHeadersCtx).(http.Header)
+	if len(headers) == 0 {
+		return nil
+	}
+
+	md := make(map[string]string, len(headers))
+	for name, vals := range headers {
+		if len(vals) > 0 {
+			md[strings.ToLower(name)] = vals[0]
+		}
+	}
+	return md
+}
+
 func unmarshalActionCall(req *userjson.CallRequest) (*types.ActionCall, *types.CallMessage, error) {
 	var actionPayload types.ActionCall
 	err := actionPayload.UnmarshalBinary(req.Body.Payload)
@@ -750,7 +804,7 @@ func (svc *Service) Call(ctx context.Context, req *userjson.CallRequest) (*userj
 	defer readTx.Rollback(ctx)
 
 	r := &rowReader{}
-	callRes, err := svc.engine.Call(&common.EngineContext{TxContext: txContext}, readTx, body.Namespace, body.Action, args, r.read)
+	callRes, err := svc.engine.Call(&common.EngineContext{TxContext: txContext, Metadata: requestMetadata(ctx)}, readTx, body.Namespace, body.Action, args, r.read)
 	if err != nil {
 		return nil, engineError(err)
 	}
@@ -768,6 +822,96 @@ func (svc *Service) Call(ctx context.Context, req *userjson.CallRequest) (*userj
 	}, nil
 }
 
+func (svc *Service) ComparePlans(ctx context.Context, req *userjson.ComparePlansRequest) (*userjson.ComparePlansResponse, *jsonrpc.Error) {
+	ctxExec, cancel := context.WithTimeout(ctx, svc.readTxTimeout)
+	defer cancel()
+
+	// the action is called without a caller, so it is treated like an ad-hoc query
+	if svc.privateMode {
+		return nil, jsonrpc.NewError(jsonrpc.ErrorNoQueryWithPrivateRPC,
+			"plan comparison is prohibited when authenticated calls are enforced (private mode)", nil)
+	}
+
+	args := make([]any, len(req.Arguments))
+	for i, arg := range req.Arguments {
+		argVal, err := arg.Decode()
+		if err != nil {
+			return nil, jsonrpc.NewError(jsonrpc.ErrorInvalidParams, "failed to decode argument: "+err.Error(), nil)
+		}
+		args[i] = argVal
+	}
+
+	readTx := svc.db.BeginDelayedReadTx()
+	defer readTx.Rollback(ctx)
+
+	comparison, err := svc.engine.ComparePlans(ctxExec, readTx, req.Namespace, req.Action, args, req.BaselineID)
+	if err != nil {
+		return nil, engineError(err)
+	}
+
+	return comparison, nil
+}
+
+// SimulateCall is the handler for the user.simulate_call RPC. It runs an
+// action in a transaction that is always rolled back, so that actions that
+// modify the database can be tried before they are broadcast.
+func (svc *Service) SimulateCall(ctx context.Context, req *userjson.SimulateCallRequest) (*userjson.SimulateCallResponse, *jsonrpc.Error) {
+	// the caller is not authenticated, so it could be used to call actions
+	// as anyone
+	if svc.privateMode {
+		return nil, jsonrpc.NewError(jsonrpc.ErrorNoQueryWithPrivateRPC,
+			"call simulation is prohibited when authenticated calls are enforced (private mode)", nil)
+	}
+
+	args := make([]any, len(req.Arguments))
+	for i, arg := range req.Arguments {
+		argVal, err := arg.Decode()
+		if err != nil {
+			return nil, jsonrpc.NewError(jsonrpc.ErrorInvalidParams, "failed to decode argument: "+err.Error(), nil)
+		}
+		args[i] = argVal
+	}
+
+	ctxExec, cancel := context.WithTimeout(ctx, svc.readTxTimeout)
+	defer cancel()
+
+	txContext, jsonRPCErr := svc.txCtx(ctxExec, req.Caller, req.AuthType)
+	if jsonRPCErr != nil {
+		return nil, jsonRPCErr
+	}
+
+	simTx, err := svc.db.BeginSimulationTx(ctxExec)
+	if err != nil {
+		return nil, jsonrpc.NewError(jsonrpc.ErrorNodeInternal, "failed to start simulation tx", nil)
+	}
+	defer simTx.Rollback(ctx)
+
+	r := &rowReader{}
+	callRes, err := svc.engine.Call(&common.EngineContext{TxContext: txContext, Metadata: requestMetadata(ctx), Simulate: true},
+		simTx, req.Namespace, req.Action, args, r.read)
+	if err != nil {
+		return nil, engineError(err)
+	}
+
+	return simulationResult(callRes, r.qr.Values), nil
+}
+
+// simulationResult returns the result of a simulated call. The error of a
+// failed call is its last log.
+func simulationResult(callRes *common.CallResult, rows [][]any) *types.SimulationResult {
+	logs := callRes.Logs
+	if callRes.Error != nil {
+		logs = append(logs, callRes.Error.Error())
+	}
+
+	return &types.SimulationResult{
+		Logs:         logs,
+		GasEstimate:  callRes.GasUsed,
+		ResultRows:   rows,
+		WouldSucceed: callRes.Error == nil,
+	}
+}
+
 // rowReader is a helper struct that writes data for a query response
 type rowReader struct {
 	qr types.QueryResult
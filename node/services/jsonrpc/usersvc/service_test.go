@@ -0,0 +1,31 @@
+package usersvc
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kwilteam/kwil-db/common"
+)
+
+func Test_SimulationResult(t *testing.T) {
+	// a failed action would not succeed, and its error is its last log
+	res := simulationResult(&common.CallResult{
+		Logs:    []string{"inserting user"},
+		Error:   errors.New(`duplicate key value violates unique constraint "users_pkey"`),
+		GasUsed: 2,
+	}, nil)
+	require.False(t, res.WouldSucceed)
+	require.Equal(t, []string{"inserting user", `duplicate key value violates unique constraint "users_pkey"`}, res.Logs)
+	require.Equal(t, uint64(2), res.GasEstimate)
+
+	res = simulationResult(&common.CallResult{
+		Logs:    []string{"inserting user"},
+		GasUsed: 1,
+	}, [][]any{{"1"}})
+	require.True(t, res.WouldSucceed)
+	require.Equal(t, []string{"inserting user"}, res.Logs)
+	require.Equal(t, [][]any{{"1"}}, res.ResultRows)
+	require.Equal(t, uint64(1), res.GasEstimate)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"math/big"
 
+	"github.com/kwilteam/kwil-db/common"
 	"github.com/kwilteam/kwil-db/core/crypto"
 	"github.com/kwilteam/kwil-db/core/types"
 	"github.com/kwilteam/kwil-db/node/types/sql"
@@ -17,6 +18,8 @@ type Accounts interface {
 	GetAccount(ctx context.Context, tx sql.Executor, acctID *types.AccountID) (*types.Account, error)
 	NumAccounts(ctx context.Context, tx sql.Executor) (int64, error)
 	ApplySpend(ctx context.Context, tx sql.Executor, acctID *types.AccountID, amount *big.Int, nonce int64) error
+	TransferToken(ctx context.Context, tx sql.TxMaker, from, to *types.AccountID, token []byte, amount *big.Int) error
+	TokenBalance(ctx context.Context, tx sql.Executor, acctID *types.AccountID, token []byte) (*big.Int, error)
 	Commit() error
 	Rollback()
 }
@@ -29,6 +32,25 @@ type Validators interface {
 	Rollback()
 }
 
+// BlockCommitter is implemented by engines that hold state about the block
+// being executed, which must be notified when it is committed or rolled back.
+type BlockCommitter interface {
+	// BlockCommitted is called after the block's changes are committed.
+	BlockCommitted()
+	// BlockRolledBack is called after the block's changes are rolled back.
+	BlockRolledBack()
+}
+
+// NamespaceDeployer is implemented by engines that can deploy a schema to a
+// namespace, checking the version of the namespace.
+type NamespaceDeployer interface {
+	// DeployNamespace creates the namespace if it does not exist, executes the
+	// statements in it, and returns the new version of the namespace. If
+	// expectedVersion is not nil, it fails with engine.ErrVersionConflict
+	// unless the namespace is at that version.
+	DeployNamespace(ctx *common.EngineContext, db sql.DB, namespace string, statements []string, expectedVersion *int64) (int64, error)
+}
+
 // Rebroadcaster is a service that marks events for rebroadcasting.
 type Rebroadcaster interface {
 	// MarkRebroadcast marks events for rebroadcasting.
@@ -86,6 +86,8 @@ func (m *mempool) applyTransaction(ctx *common.TxContext, tx *types.Transaction,
 			return fmt.Errorf("%w: validator vote bodies", types.ErrDisallowedInMigration)
 		case types.PayloadTypeRawStatement:
 			return fmt.Errorf("%w: raw statement", types.ErrDisallowedInMigration)
+		case types.PayloadTypeDeployNamespace:
+			return fmt.Errorf("%w: deploy namespace", types.ErrDisallowedInMigration)
 		case types.PayloadTypeTransfer:
 			return fmt.Errorf("%w: transfer", types.ErrDisallowedInMigration)
 		}
@@ -218,11 +220,15 @@ func (m *mempool) applyTransaction(ctx *common.TxContext, tx *types.Transaction,
 			return errors.Join(types.ErrInvalidAmount, errors.New("negative transfer not permitted"))
 		}
 
-		if amt.Cmp(acct.Balance) > 0 {
-			return types.ErrInsufficientBalance
-		}
+		// the balances of other tokens are checked when the transfer is
+		// executed, since the mempool only tracks the native token
+		if len(transfer.TokenID) == 0 {
+			if amt.Cmp(acct.Balance) > 0 {
+				return types.ErrInsufficientBalance
+			}
 
-		spend.Add(spend, amt)
+			spend.Add(spend, amt)
+		}
 	}
 
 	// We'd check balance against the total spend (fees plus value sent) if we
@@ -24,6 +24,7 @@ import (
 func init() {
 	err := errors.Join(
 		RegisterRoute(types.PayloadTypeRawStatement, NewRoute(&rawStatementRoute{})),
+		RegisterRoute(types.PayloadTypeDeployNamespace, NewRoute(&deployNamespaceRoute{})),
 		RegisterRoute(types.PayloadTypeExecute, NewRoute(&executeActionRoute{})),
 		RegisterRoute(types.PayloadTypeTransfer, NewRoute(&transferRoute{})),
 		RegisterRoute(types.PayloadTypeValidatorJoin, NewRoute(&validatorJoinRoute{})),
@@ -182,6 +183,9 @@ func (d *baseRoute) Execute(ctx *common.TxContext, router *TxApp, db sql.DB, tx
 
 	code, log, err := d.InTx(ctx, app, tx)
 	if err != nil {
+		if fc, ok := d.Route.(failureCommitter); ok && fc.commitOnFailure() {
+			logErr(router.service.Logger, tx2.Commit(ctx.Ctx))
+		}
 		return txRes(spend, code, log, err)
 	}
 
@@ -193,6 +197,14 @@ func (d *baseRoute) Execute(ctx *common.TxContext, router *TxApp, db sql.DB, tx
 	return txRes(spend, types.CodeOk, log, nil)
 }
 
+// failureCommitter is implemented by routes that must commit their nested
+// transaction even if InTx fails, because the route has already rolled back
+// its own changes and kept only what must outlive the failure, such as a
+// refund.
+type failureCommitter interface {
+	commitOnFailure() bool
+}
+
 // ========================== route implementations ==========================
 // Each of the following route implementation satisfy the consensus.Route
 // interface, which is embedded by the baseRoute for used by TxApp.
@@ -260,6 +272,49 @@ func (d *rawStatementRoute) InTx(ctx *common.TxContext, app *common.App, tx *typ
 	return 0, "", nil
 }
 
+type deployNamespaceRoute struct {
+	namespace       string
+	statements      []string
+	expectedVersion *int64
+}
+
+var _ consensus.Route = (*deployNamespaceRoute)(nil)
+
+func (d *deployNamespaceRoute) Name() string {
+	return types.PayloadTypeDeployNamespace.String()
+}
+
+func (d *deployNamespaceRoute) Price(ctx context.Context, app *common.App, tx *types.Transaction) (*big.Int, error) {
+	return big.NewInt(10000000000000), nil
+}
+
+func (d *deployNamespaceRoute) PreTx(ctx *common.TxContext, svc *common.Service, tx *types.Transaction) (types.TxCode, error) {
+	deploy := &types.DeployNamespace{}
+	err := deploy.UnmarshalBinary(tx.Body.Payload)
+	if err != nil {
+		return types.CodeEncodingError, err
+	}
+
+	d.namespace = deploy.Namespace
+	d.statements = deploy.Statements
+	d.expectedVersion = deploy.ExpectedVersion
+
+	return 0, nil
+}
+
+func (d *deployNamespaceRoute) InTx(ctx *common.TxContext, app *common.App, tx *types.Transaction) (types.TxCode, string, error) {
+	deployer, ok := app.Engine.(NamespaceDeployer)
+	if !ok {
+		return types.CodeUnknownError, "", errors.New("engine does not support namespace deploys")
+	}
+
+	version, err := deployer.DeployNamespace(makeEngineCtx(ctx), app.DB, d.namespace, d.statements, d.expectedVersion)
+	if err != nil {
+		return codeForEngineError(err), "", err
+	}
+	return 0, fmt.Sprintf("deployed namespace %s at version %d", d.namespace, version), nil
+}
+
 func makeEngineCtx(ctx *common.TxContext) *common.EngineContext {
 	return &common.EngineContext{
 		TxContext:     ctx,
@@ -267,20 +322,38 @@ func makeEngineCtx(ctx *common.TxContext) *common.EngineContext {
 	}
 }
 
+const (
+	// executeActionPrice is the fee of an action execution.
+	executeActionPrice = 2000000000000000
+	// executeActionGasLimit is the gas that the fee of an action execution
+	// pays for. The engine uses a unit of gas for each statement.
+	executeActionGasLimit = 1000
+)
+
 type executeActionRoute struct {
 	namespace string
 	action    string
 	args      [][]any
+	// refunded is true if the call failed and the engine refunded the fee
+	// that it did not use.
+	refunded bool
 }
 
 var _ consensus.Route = (*executeActionRoute)(nil)
+var _ failureCommitter = (*executeActionRoute)(nil)
 
 func (d *executeActionRoute) Name() string {
 	return types.PayloadTypeExecute.String()
 }
 
 func (d *executeActionRoute) Price(ctx context.Context, app *common.App, tx *types.Transaction) (*big.Int, error) {
-	return big.NewInt(2000000000000000), nil
+	return big.NewInt(executeActionPrice), nil
+}
+
+// commitOnFailure keeps the refund of a failed call, whose changes the engine
+// rolled back before crediting it.
+func (d *executeActionRoute) commitOnFailure() bool {
+	return d.refunded
 }
 
 func (d *executeActionRoute) PreTx(ctx *common.TxContext, svc *common.Service, tx *types.Transaction) (types.TxCode, error) {
@@ -292,6 +365,7 @@ func (d *executeActionRoute) PreTx(ctx *common.TxContext, svc *common.Service, t
 
 	d.action = action.Action
 	d.namespace = action.Namespace
+	d.refunded = false
 
 	// here, we decode the [][]types.EncodedTypes into [][]any
 	d.args = make([][]any, len(action.Arguments))
@@ -315,9 +389,21 @@ func (d *executeActionRoute) PreTx(ctx *common.TxContext, svc *common.Service, t
 }
 
 func (d *executeActionRoute) InTx(ctx *common.TxContext, app *common.App, tx *types.Transaction) (types.TxCode, string, error) {
+	engineCtx := makeEngineCtx(ctx)
+
+	// A failed call is refunded the part of the fee it did not use. This is
+	// only done for a single call, since the refund of a later call would be
+	// rolled back with the changes of the calls before it.
+	refund := len(d.args) == 1 && !ctx.BlockContext.ChainContext.NetworkParameters.DisabledGasCosts
+	if refund {
+		engineCtx.RefundOnFailure = true
+		engineCtx.GasLimit = executeActionGasLimit
+		engineCtx.GasPrice = big.NewInt(executeActionPrice / executeActionGasLimit)
+	}
+
 	var logs string
 	for i := range d.args {
-		res, err := app.Engine.Call(makeEngineCtx(ctx), app.DB, d.namespace, d.action, d.args[i], func(r *common.Row) error {
+		res, err := app.Engine.Call(engineCtx, app.DB, d.namespace, d.action, d.args[i], func(r *common.Row) error {
 			// we throw away all results for execute actions
 			return nil
 		})
@@ -333,10 +419,12 @@ func (d *executeActionRoute) InTx(ctx *common.TxContext, app *common.App, tx *ty
 		}
 
 		if err != nil {
+			d.refunded = refund
 			return codeForEngineError(err), logs, err
 		}
 
 		if res.Error != nil {
+			d.refunded = refund
 			return types.CodeUnknownError, logs, res.Error
 		}
 	}
@@ -344,8 +432,9 @@ func (d *executeActionRoute) InTx(ctx *common.TxContext, app *common.App, tx *ty
 }
 
 type transferRoute struct {
-	to  *types.AccountID
-	amt *big.Int
+	to    *types.AccountID
+	amt   *big.Int
+	token []byte // empty for the native token
 }
 
 var _ consensus.Route = (*transferRoute)(nil)
@@ -380,6 +469,7 @@ func (d *transferRoute) PreTx(ctx *common.TxContext, svc *common.Service, tx *ty
 
 	d.to = transferBody.To
 	d.amt = bigAmt
+	d.token = transferBody.TokenID
 	return 0, nil
 }
 
@@ -389,7 +479,11 @@ func (d *transferRoute) InTx(ctx *common.TxContext, app *common.App, tx *types.T
 		return types.CodeInvalidSender, "", err
 	}
 
-	err = app.Accounts.Transfer(ctx.Ctx, app.DB, sender, d.to, d.amt)
+	if len(d.token) == 0 {
+		err = app.Accounts.Transfer(ctx.Ctx, app.DB, sender, d.to, d.amt)
+	} else {
+		err = app.Accounts.TransferToken(ctx.Ctx, app.DB, sender, d.to, d.token, d.amt)
+	}
 	if err != nil {
 		if errors.Is(err, accounts.ErrInsufficientFunds) {
 			return types.CodeInsufficientBalance, "", err
@@ -2,6 +2,8 @@ package txapp
 
 import (
 	"encoding/hex"
+	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/kwilteam/kwil-db/common"
@@ -10,6 +12,7 @@ import (
 	"github.com/kwilteam/kwil-db/core/log"
 	"github.com/kwilteam/kwil-db/core/types"
 	"github.com/kwilteam/kwil-db/extensions/resolutions"
+	"github.com/kwilteam/kwil-db/node/engine"
 	"github.com/kwilteam/kwil-db/node/types/sql"
 	"github.com/kwilteam/kwil-db/node/voting"
 
@@ -319,6 +322,14 @@ func (a *mockAccount) Transfer(_ context.Context, _ sql.TxMaker, from, to *types
 func (a *mockAccount) ApplySpend(_ context.Context, _ sql.Executor, acctID *types.AccountID, amount *big.Int, nonce int64) error {
 	return nil
 }
+
+func (a *mockAccount) TransferToken(_ context.Context, _ sql.TxMaker, from, to *types.AccountID, token []byte, amount *big.Int) error {
+	return nil
+}
+
+func (a *mockAccount) TokenBalance(_ context.Context, _ sql.Executor, acctID *types.AccountID, token []byte) (*big.Int, error) {
+	return big.NewInt(0), nil
+}
 func (a *mockAccount) Commit() error {
 	return nil
 }
@@ -359,3 +370,250 @@ func getSigner(hexPrivKey string) (crypto.PrivateKey, auth.Signer) {
 
 	return pk, auth.GetNodeSigner(pk)
 }
+
+func Test_ExecuteActionRefund(t *testing.T) {
+	arg, err := types.EncodeValue(int64(1))
+	require.NoError(t, err)
+
+	type testcase struct {
+		name       string
+		args       [][]*types.EncodedValue
+		callErr    error
+		noGasCosts bool
+		refund     bool // whether the calls refund the caller if they fail
+		committed  bool // whether the route's nested transaction is committed
+	}
+
+	testCases := []testcase{
+		{
+			name:      "successful call",
+			args:      [][]*types.EncodedValue{{arg}},
+			refund:    true,
+			committed: true,
+		},
+		{
+			// the engine rolled back the changes of the call, so the
+			// nested transaction only keeps the refund
+			name:      "failed call",
+			args:      [][]*types.EncodedValue{{arg}},
+			callErr:   errors.New("fail"),
+			refund:    true,
+			committed: true,
+		},
+		{
+			name:    "failed batch of calls",
+			args:    [][]*types.EncodedValue{{arg}, {arg}},
+			callErr: errors.New("fail"),
+		},
+		{
+			name:       "failed call without gas costs",
+			args:       [][]*types.EncodedValue{{arg}},
+			callErr:    errors.New("fail"),
+			noGasCosts: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tx, err := types.CreateTransaction(&types.ActionExecution{
+				Namespace: "main",
+				Action:    "act",
+				Arguments: tc.args,
+			}, "chainid", 1)
+			require.NoError(t, err)
+			tx.Body.Fee = big.NewInt(executeActionPrice)
+			require.NoError(t, tx.Sign(signer1))
+
+			engine := &mockEngine{err: tc.callErr}
+			app := &TxApp{
+				Engine:     engine,
+				Accounts:   &mockAccount{},
+				Validators: &mockValidator{},
+				signer:     signer1,
+				service: &common.Service{
+					Logger:   log.DiscardLogger,
+					Identity: signer1.CompactID(),
+				},
+			}
+
+			var txs []*recordingTx
+			db := &recordingTx{mockDb: &mockDb{}, txs: &txs}
+			res := app.Execute(&common.TxContext{
+				Ctx: context.Background(),
+				BlockContext: &common.BlockContext{
+					ChainContext: &common.ChainContext{
+						NetworkParameters: &types.NetworkParameters{
+							DisabledGasCosts: tc.noGasCosts,
+						},
+					},
+				},
+			}, db, tx)
+			if tc.callErr != nil {
+				require.ErrorIs(t, res.Error, tc.callErr)
+			} else {
+				require.NoError(t, res.Error)
+			}
+
+			require.NotEmpty(t, engine.calls)
+			for _, call := range engine.calls {
+				require.Equal(t, tc.refund, call.RefundOnFailure)
+				if tc.refund {
+					require.EqualValues(t, executeActionGasLimit, call.GasLimit)
+					require.Equal(t, big.NewInt(executeActionPrice/executeActionGasLimit), call.GasPrice)
+				}
+			}
+
+			// the outer transaction holds the spend, and the nested one the
+			// changes of the calls
+			require.Len(t, txs, 2)
+			require.True(t, txs[0].committed)
+			require.Equal(t, tc.committed, txs[1].committed)
+		})
+	}
+}
+
+func Test_DeployNamespaceRoute(t *testing.T) {
+	expected := int64(2)
+
+	type testcase struct {
+		name     string
+		engine   common.Engine
+		expected *int64
+		err      error // the error of the transaction
+	}
+
+	testCases := []testcase{
+		{
+			name:     "deploy with expected version",
+			engine:   &mockDeployer{version: 3},
+			expected: &expected,
+		},
+		{
+			name:   "deploy without expected version",
+			engine: &mockDeployer{version: 1},
+		},
+		{
+			name:     "version conflict",
+			engine:   &mockDeployer{mockEngine: mockEngine{err: engine.ErrVersionConflict}},
+			expected: &expected,
+			err:      engine.ErrVersionConflict,
+		},
+		{
+			name:   "engine cannot deploy namespaces",
+			engine: &mockEngine{},
+			err:    errors.New("engine does not support namespace deploys"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			stmts := []string{"CREATE TABLE items (id INT PRIMARY KEY);"}
+			tx, err := types.CreateTransaction(&types.DeployNamespace{
+				Namespace:       "shop",
+				Statements:      stmts,
+				ExpectedVersion: tc.expected,
+			}, "chainid", 1)
+			require.NoError(t, err)
+			tx.Body.Fee = big.NewInt(10000000000000)
+			require.NoError(t, tx.Sign(signer1))
+
+			app := &TxApp{
+				Engine:     tc.engine,
+				Accounts:   &mockAccount{},
+				Validators: &mockValidator{},
+				signer:     signer1,
+				service: &common.Service{
+					Logger:   log.DiscardLogger,
+					Identity: signer1.CompactID(),
+				},
+			}
+
+			var txs []*recordingTx
+			db := &recordingTx{mockDb: &mockDb{}, txs: &txs}
+			res := app.Execute(&common.TxContext{
+				Ctx: context.Background(),
+				BlockContext: &common.BlockContext{
+					ChainContext: &common.ChainContext{
+						NetworkParameters: &types.NetworkParameters{},
+					},
+				},
+			}, db, tx)
+			if tc.err != nil {
+				require.ErrorContains(t, res.Error, tc.err.Error())
+				return
+			}
+			require.NoError(t, res.Error)
+
+			deployer := tc.engine.(*mockDeployer)
+			require.Equal(t, "shop", deployer.namespace)
+			require.Equal(t, stmts, deployer.statements)
+			require.Equal(t, tc.expected, deployer.expected)
+			require.Contains(t, res.Log, fmt.Sprintf("version %d", deployer.version))
+		})
+	}
+}
+
+// mockDeployer is a mockEngine that records the namespace deploys made to it,
+// and fails them with err.
+type mockDeployer struct {
+	mockEngine
+	namespace  string
+	statements []string
+	expected   *int64
+	version    int64
+}
+
+func (m *mockDeployer) DeployNamespace(_ *common.EngineContext, _ sql.DB, namespace string, statements []string, expectedVersion *int64) (int64, error) {
+	m.namespace, m.statements, m.expected = namespace, statements, expectedVersion
+	if m.err != nil {
+		return 0, m.err
+	}
+	return m.version, nil
+}
+
+// mockEngine records the engine contexts of the calls made to it, and fails
+// them with err.
+type mockEngine struct {
+	calls []*common.EngineContext
+	err   error
+}
+
+func (m *mockEngine) Call(ctx *common.EngineContext, _ sql.DB, _, _ string, _ []any, _ func(*common.Row) error) (*common.CallResult, error) {
+	m.calls = append(m.calls, ctx)
+	return &common.CallResult{Error: m.err}, nil
+}
+
+func (m *mockEngine) CallWithoutEngineCtx(ctx context.Context, db sql.DB, namespace, action string, args []any, resultFn func(*common.Row) error) (*common.CallResult, error) {
+	return m.Call(&common.EngineContext{TxContext: &common.TxContext{Ctx: ctx}, InvalidTxCtx: true}, db, namespace, action, args, resultFn)
+}
+
+func (m *mockEngine) Execute(_ *common.EngineContext, _ sql.DB, _ string, _ map[string]any, _ func(*common.Row) error) error {
+	return m.err
+}
+
+func (m *mockEngine) ExecuteWithoutEngineCtx(_ context.Context, _ sql.DB, _ string, _ map[string]any, _ func(*common.Row) error) error {
+	return m.err
+}
+
+// recordingTx is a transaction that records the transactions begun from it,
+// and whether they were committed.
+type recordingTx struct {
+	*mockDb
+	txs       *[]*recordingTx
+	committed bool
+}
+
+func (m *recordingTx) BeginTx(ctx context.Context) (sql.Tx, error) {
+	tx := &recordingTx{mockDb: m.mockDb, txs: m.txs}
+	*m.txs = append(*m.txs, tx)
+	return tx, nil
+}
+
+func (m *recordingTx) Commit(ctx context.Context) error {
+	m.committed = true
+	return nil
+}
+
+func (m *recordingTx) Rollback(ctx context.Context) error {
+	return nil
+}
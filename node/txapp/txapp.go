@@ -220,6 +220,9 @@ func (r *TxApp) Finalize(ctx context.Context, db sql.DB, block *common.BlockCont
 func (r *TxApp) Commit() error {
 	r.Accounts.Commit()
 	r.Validators.Commit()
+	if bc, ok := r.Engine.(BlockCommitter); ok {
+		bc.BlockCommitted()
+	}
 
 	r.mempool.reset()
 	r.approvedJoins = nil
@@ -229,6 +232,9 @@ func (r *TxApp) Commit() error {
 func (r *TxApp) Rollback() {
 	r.Accounts.Rollback()
 	r.Validators.Rollback()
+	if bc, ok := r.Engine.(BlockCommitter); ok {
+		bc.BlockRolledBack()
+	}
 
 	r.mempool.reset() // will issue recheck before next block
 	r.approvedJoins = nil
@@ -627,6 +633,13 @@ func (r *TxApp) AccountInfo(ctx context.Context, db sql.DB, acctID *types.Accoun
 	return a.Balance, a.Nonce, nil
 }
 
+// TokenBalance gets the balance of an account in a token other than the
+// native token. Unlike AccountInfo, it only reads the account store, since
+// the mempool only tracks the native token.
+func (r *TxApp) TokenBalance(ctx context.Context, db sql.DB, acctID *types.AccountID, token []byte) (*big.Int, error) {
+	return r.Accounts.TokenBalance(ctx, db, acctID, token)
+}
+
 func (r *TxApp) NumAccounts(ctx context.Context, db sql.Executor) (int64, int64, error) {
 	// TODO: provide a cache for this information so RPC doesn't have to hit DB.
 	count, err := r.Accounts.NumAccounts(ctx, db)
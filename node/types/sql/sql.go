@@ -123,6 +123,13 @@ type ReservedReadTxMaker interface {
 	BeginReservedReadTx(ctx context.Context) (Tx, error)
 }
 
+// SimulationTxMaker can make transactions that may be written to, but that
+// can never be committed. They are used to run calls without keeping their
+// changes.
+type SimulationTxMaker interface {
+	BeginSimulationTx(ctx context.Context) (Tx, error)
+}
+
 // PreparedTx is an outermost database transaction that uses two-phase commit
 // with the Precommit method.
 //
@@ -177,6 +184,17 @@ type AccessModer interface {
 	AccessMode() AccessMode
 }
 
+// Copier is a database or transaction that can insert many rows into a table
+// with COPY, which is much faster than inserting them with separate
+// statements. It is not universally required (type assert as needed).
+type Copier interface {
+	// CopyRows copies rows into the columns of a table in a schema, and
+	// returns the number of rows copied. next returns the values of the next
+	// row, or false when there are no more rows. The values are encoded like
+	// the arguments of a query.
+	CopyRows(ctx context.Context, schema, table string, columns []string, next func() ([]any, bool, error)) (int64, error)
+}
+
 // Subscriber is a transaction that can be subscribed to.
 // When subscribed, the passed channel will receive notifications.
 // Only one subscription is allowed per transaction.
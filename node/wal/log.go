@@ -0,0 +1,13 @@
+package wal
+
+// This file declares a package-level logger, as in the pg package, since the
+// streams started by this package run in the background with no other place
+// to report transient errors.
+
+import "github.com/kwilteam/kwil-db/core/log"
+
+var logger = log.DiscardLogger
+
+func UseLogger(log log.Logger) {
+	logger = log
+}
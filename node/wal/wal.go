@@ -0,0 +1,323 @@
+// Package wal streams committed row changes from the postgres write-ahead log
+// for consumers that maintain state outside of the node, such as secondary
+// indexes or analytics pipelines. Changes are read from a logical replication
+// slot using the pgoutput plugin, and are delivered as WALEvents in commit
+// order.
+//
+// Unlike the replication monitor in the pg package, which uses a dedicated
+// replication connection and is integral to computing commit IDs, streams
+// started by this package poll the slot with regular SQL functions. This allows
+// any sql.DB to be used, at the cost of some latency.
+package wal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pglogrepl"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/kwilteam/kwil-db/node/types/sql"
+)
+
+// PublicationName is the name of the publication that WAL streams decode
+// changes for. It is created for all tables if it does not already exist.
+const PublicationName = "kwild_wal"
+
+// pollInterval is how long a stream waits before checking the slot for new
+// changes after finding none.
+var pollInterval = 250 * time.Millisecond
+
+var (
+	ErrStreamExists   = errors.New("WAL stream already started for slot")
+	ErrStreamNotFound = errors.New("no WAL stream for slot")
+)
+
+// Operation is the kind of row change described by a WALEvent.
+type Operation string
+
+const (
+	OperationInsert Operation = "INSERT"
+	OperationUpdate Operation = "UPDATE"
+	OperationDelete Operation = "DELETE"
+)
+
+// WALEvent is a single committed row change.
+type WALEvent struct {
+	// Namespace is the namespace (postgres schema) of the changed table.
+	Namespace string
+	// Table is the name of the changed table.
+	Table string
+	// Operation is the kind of change.
+	Operation Operation
+	// OldRow is the row prior to an UPDATE or DELETE, keyed by column name. It
+	// is nil for an INSERT. Depending on the table's replica identity, it may
+	// only include the primary key columns.
+	OldRow map[string]any
+	// NewRow is the row after an INSERT or UPDATE, keyed by column name. It is
+	// nil for a DELETE.
+	NewRow map[string]any
+}
+
+// stream is a running WAL stream.
+type stream struct {
+	db     sql.DB
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+var (
+	streamsMtx sync.Mutex
+	streams    = map[string]*stream{}
+)
+
+// StartWALStream creates the named logical replication slot if it does not
+// exist, and begins sending committed changes to the provided channel. The
+// stream runs until the context is cancelled or StopWALStream is called for
+// the slot. The channel is never closed by the stream. Changes to tables in
+// the node's internal schemas (those prefixed with "kwild_") are not sent.
+//
+// The database user must have the REPLICATION attribute, and postgres must be
+// configured with wal_level=logical.
+func StartWALStream(ctx context.Context, db sql.DB, slotName string, ch chan<- WALEvent) error {
+	if slotName == "" {
+		return errors.New("slot name must not be empty")
+	}
+	if ch == nil {
+		return errors.New("nil WAL event channel")
+	}
+
+	streamsMtx.Lock()
+	defer streamsMtx.Unlock()
+	if _, ok := streams[slotName]; ok {
+		return fmt.Errorf("%w: %s", ErrStreamExists, slotName)
+	}
+
+	if err := ensurePublication(ctx, db); err != nil {
+		return err
+	}
+	if err := ensureSlot(ctx, db, slotName); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	s := &stream{
+		db:     db,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	streams[slotName] = s
+
+	go func() {
+		defer close(s.done)
+		err := s.run(ctx, slotName, ch)
+		if err != nil && !errors.Is(err, context.Canceled) {
+			logger.Errorf("WAL stream for slot %s stopped: %v", slotName, err)
+		}
+	}()
+
+	return nil
+}
+
+// StopWALStream stops the stream for the named slot, and drops the slot so
+// that postgres may recycle the WAL that it retained.
+func StopWALStream(slotName string) error {
+	streamsMtx.Lock()
+	s, ok := streams[slotName]
+	delete(streams, slotName)
+	streamsMtx.Unlock()
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrStreamNotFound, slotName)
+	}
+
+	s.cancel()
+	<-s.done
+
+	_, err := s.db.Execute(context.Background(), `SELECT pg_drop_replication_slot($1::text)`, slotName)
+	return err
+}
+
+// DropSlot drops the named replication slot if it exists and no stream uses
+// it. A slot retains the WAL that its stream has not read, so the slot of a
+// stream that is no longer started must be dropped for postgres to recycle it.
+func DropSlot(ctx context.Context, db sql.DB, slotName string) error {
+	streamsMtx.Lock()
+	_, ok := streams[slotName]
+	streamsMtx.Unlock()
+	if ok {
+		return fmt.Errorf("%w: %s", ErrStreamExists, slotName)
+	}
+
+	_, err := db.Execute(ctx, `SELECT pg_drop_replication_slot(slot_name) FROM pg_replication_slots WHERE slot_name = $1::text`, slotName)
+	return err
+}
+
+func ensurePublication(ctx context.Context, db sql.DB) error {
+	res, err := db.Execute(ctx, `SELECT EXISTS (SELECT 1 FROM pg_publication WHERE pubname = $1::text)`, PublicationName)
+	if err != nil {
+		return err
+	}
+	if exists, _ := res.Rows[0][0].(bool); exists {
+		return nil
+	}
+	_, err = db.Execute(ctx, `CREATE PUBLICATION `+PublicationName+` FOR ALL TABLES`)
+	return err
+}
+
+func ensureSlot(ctx context.Context, db sql.DB, slotName string) error {
+	res, err := db.Execute(ctx, `SELECT EXISTS (SELECT 1 FROM pg_replication_slots WHERE slot_name = $1::text)`, slotName)
+	if err != nil {
+		return err
+	}
+	if exists, _ := res.Rows[0][0].(bool); exists {
+		return nil
+	}
+	_, err = db.Execute(ctx, `SELECT pg_create_logical_replication_slot($1::text, 'pgoutput')`, slotName)
+	return err
+}
+
+// run polls the slot for changes until the context is cancelled.
+func (s *stream) run(ctx context.Context, slotName string, ch chan<- WALEvent) error {
+	dec := &decoder{
+		relations: make(map[uint32]*pglogrepl.RelationMessage),
+		types:     pgtype.NewMap(),
+	}
+
+	const getChanges = `SELECT data FROM pg_logical_slot_get_binary_changes($1::text, NULL, NULL,
+		'proto_version', '1', 'publication_names', '` + PublicationName + `')`
+
+	for {
+		res, err := s.db.Execute(ctx, getChanges, slotName)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+
+		for _, row := range res.Rows {
+			data, ok := row[0].([]byte)
+			if !ok {
+				return fmt.Errorf("unexpected WAL data type %T", row[0])
+			}
+
+			evt, err := dec.decode(data)
+			if err != nil {
+				return err
+			}
+			if evt == nil {
+				continue
+			}
+
+			select {
+			case ch <- *evt:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		if len(res.Rows) > 0 {
+			continue // there may be more
+		}
+
+		select {
+		case <-time.After(pollInterval):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// decoder converts pgoutput messages into WALEvents, tracking the relation
+// messages that describe the tables referenced by subsequent row changes.
+type decoder struct {
+	relations map[uint32]*pglogrepl.RelationMessage
+	types     *pgtype.Map
+}
+
+// decode decodes a single pgoutput message. It returns a nil event for
+// messages that do not describe a row change of interest.
+func (d *decoder) decode(data []byte) (*WALEvent, error) {
+	msg, err := pglogrepl.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse WAL message: %w", err)
+	}
+
+	var relID uint32
+	var op Operation
+	var oldTuple, newTuple *pglogrepl.TupleData
+	switch msg := msg.(type) {
+	case *pglogrepl.RelationMessage:
+		d.relations[msg.RelationID] = msg
+		return nil, nil
+	case *pglogrepl.InsertMessage:
+		relID, op, newTuple = msg.RelationID, OperationInsert, msg.Tuple
+	case *pglogrepl.UpdateMessage:
+		relID, op, oldTuple, newTuple = msg.RelationID, OperationUpdate, msg.OldTuple, msg.NewTuple
+	case *pglogrepl.DeleteMessage:
+		relID, op, oldTuple = msg.RelationID, OperationDelete, msg.OldTuple
+	default: // begin, commit, truncate, etc.
+		return nil, nil
+	}
+
+	rel, ok := d.relations[relID]
+	if !ok {
+		return nil, fmt.Errorf("unknown relation ID %d", relID)
+	}
+	if strings.HasPrefix(rel.Namespace, "kwild_") {
+		return nil, nil
+	}
+
+	evt := &WALEvent{
+		Namespace: rel.Namespace,
+		Table:     rel.RelationName,
+		Operation: op,
+	}
+	if evt.OldRow, err = d.decodeTuple(rel, oldTuple); err != nil {
+		return nil, err
+	}
+	if evt.NewRow, err = d.decodeTuple(rel, newTuple); err != nil {
+		return nil, err
+	}
+
+	return evt, nil
+}
+
+func (d *decoder) decodeTuple(rel *pglogrepl.RelationMessage, tuple *pglogrepl.TupleData) (map[string]any, error) {
+	if tuple == nil {
+		return nil, nil
+	}
+	if len(tuple.Columns) != len(rel.Columns) {
+		return nil, fmt.Errorf("tuple for %s.%s has %d columns, expected %d", rel.Namespace,
+			rel.RelationName, len(tuple.Columns), len(rel.Columns))
+	}
+
+	row := make(map[string]any, len(tuple.Columns))
+	for i, col := range tuple.Columns {
+		relCol := rel.Columns[i]
+		switch col.DataType {
+		case pglogrepl.TupleDataTypeNull:
+			row[relCol.Name] = nil
+		case pglogrepl.TupleDataTypeToast:
+			// unchanged TOASTed value, which is not sent
+		case pglogrepl.TupleDataTypeText:
+			if dt, ok := d.types.TypeForOID(relCol.DataType); ok {
+				val, err := dt.Codec.DecodeValue(d.types, relCol.DataType, pgtype.TextFormatCode, col.Data)
+				if err != nil {
+					return nil, fmt.Errorf("failed to decode column %s: %w", relCol.Name, err)
+				}
+				row[relCol.Name] = val
+			} else {
+				row[relCol.Name] = string(col.Data) // e.g. a domain type
+			}
+		default:
+			return nil, fmt.Errorf("unsupported tuple data type %q", col.DataType)
+		}
+	}
+
+	return row, nil
+}
@@ -0,0 +1,63 @@
+//go:build pglive
+
+package wal
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kwilteam/kwil-db/node/pg"
+)
+
+func TestWALStreamInsert(t *testing.T) {
+	ctx := context.Background()
+
+	pool, err := pg.NewPool(ctx, &pg.PoolConfig{
+		ConnConfig: pg.ConnConfig{
+			Host:   "127.0.0.1",
+			Port:   "5432",
+			User:   "kwild",
+			Pass:   "kwild", // would be ignored if pg_hba.conf set with trust
+			DBName: "kwil_test_db",
+		},
+		MaxConns: 2,
+	})
+	require.NoError(t, err)
+	defer pool.Close()
+
+	pollInterval = 10 * time.Millisecond
+
+	_, err = pool.Execute(ctx, `CREATE SCHEMA IF NOT EXISTS wal_test`)
+	require.NoError(t, err)
+	_, err = pool.Execute(ctx, `CREATE TABLE IF NOT EXISTS wal_test.users (id INT8 PRIMARY KEY, name TEXT)`)
+	require.NoError(t, err)
+	defer pool.Execute(ctx, `DROP SCHEMA wal_test CASCADE`)
+
+	const slotName = "kwild_wal_test"
+	ch := make(chan WALEvent, 10)
+	err = StartWALStream(ctx, pool, slotName, ch)
+	require.NoError(t, err)
+	defer StopWALStream(slotName)
+
+	err = StartWALStream(ctx, pool, slotName, ch)
+	require.ErrorIs(t, err, ErrStreamExists)
+
+	_, err = pool.Execute(ctx, `INSERT INTO wal_test.users (id, name) VALUES (1, 'satoshi')`)
+	require.NoError(t, err)
+
+	select {
+	case evt := <-ch:
+		assert.Equal(t, "wal_test", evt.Namespace)
+		assert.Equal(t, "users", evt.Table)
+		assert.Equal(t, OperationInsert, evt.Operation)
+		assert.Nil(t, evt.OldRow)
+		assert.EqualValues(t, 1, evt.NewRow["id"])
+		assert.Equal(t, "satoshi", evt.NewRow["name"])
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for WAL event")
+	}
+}
@@ -29,6 +29,18 @@ import (
 var dev = flag.Bool("dev", false, "run for development purpose (no tests)")
 var withKGW = flag.Bool("kgw", false, "test with kgw")
 
+// TestMain writes the JUnit report of the test networks if --junit-output is set.
+func TestMain(m *testing.M) {
+	code := m.Run()
+	if err := setup.WriteJUnitOutput(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		if code == 0 {
+			code = 1
+		}
+	}
+	os.Exit(code)
+}
+
 func TestLocalDevSetup(t *testing.T) {
 	if !*dev {
 		t.Skip("skipping local dev setup")
@@ -3,7 +3,9 @@ package integration
 import (
 	"context"
 	"crypto/rand"
+	"fmt"
 	"math/big"
+	"os"
 	"path/filepath"
 	"slices"
 	"strings"
@@ -35,6 +37,18 @@ var (
 	defaultContainerTimeout = 30 * time.Second
 )
 
+// TestMain writes the JUnit report of the test networks if --junit-output is set.
+func TestMain(m *testing.M) {
+	code := m.Run()
+	if err := setup.WriteJUnitOutput(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		if code == 0 {
+			code = 1
+		}
+	}
+	os.Exit(code)
+}
+
 // TestKwildDatabaseIntegration is to ensure that nodes are able to
 // produce blocks and accept db related transactions and agree on the
 // state of the database
@@ -1396,6 +1410,44 @@ func TestKwildNetworkParamsLeaderUpdate(t *testing.T) {
 	}
 }
 
+// TestNamespaceGossip checks that a namespace deployed through node 0 can be
+// called on node 1 when namespace gossip is enabled.
+func TestNamespaceGossip(t *testing.T) {
+	gossip := func(nc *setup.NodeConfig) {
+		nc.Configure = func(conf *config.Config) {
+			conf.P2P.NamespaceGossip = true
+		}
+	}
+
+	p := setup.SetupTests(t, &setup.TestConfig{
+		ClientDriver: setup.Go,
+		Network: &setup.NetworkConfig{
+			Nodes: []*setup.NodeConfig{
+				setup.CustomNodeConfig(gossip),
+				setup.CustomNodeConfig(gossip),
+			},
+			DBOwner: OwnerAddress,
+		},
+		ContainerStartTimeout: defaultContainerTimeout,
+	})
+
+	ctx := context.Background()
+
+	clt0 := p.Nodes[0].JSONRPCClient(t, ctx, &setup.ClientOptions{PrivateKey: UserPrivkey1})
+	clt1 := p.Nodes[1].JSONRPCClient(t, ctx, &setup.ClientOptions{PrivateKey: UserPrivkey1})
+
+	txHash, err := clt0.ExecuteSQL(ctx, `CREATE NAMESPACE gossiped;
+	{gossiped}CREATE ACTION ping() public view returns (text) { return 'pong'; };`, nil)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		res, err := clt1.Call(ctx, "gossiped", "ping", nil)
+		return err == nil && res.Error == nil
+	}, 5*time.Second, 250*time.Millisecond)
+
+	require.NoError(t, clt0.TxSuccess(ctx, txHash))
+}
+
 // TODO: There is no straightforward way to test the oracle expiry and refund
 // as we can't update the resolution expiry on fly. Can run these two tests with a
 // custom build with a very short Expiration period.
@@ -24,6 +24,9 @@ var ErrTxNotConfirmed = errors.New("transaction not confirmed")
 
 type AdminClient struct {
 	container *testcontainers.DockerContainer
+	// rpcArgs are the flags that commands connect to the admin service with,
+	// if it does not listen on the default socket.
+	rpcArgs []string
 }
 type cliResponse struct {
 	Result any    `json:"result"`
@@ -35,6 +38,7 @@ type cliResponse struct {
 func exec[T any](a *AdminClient, ctx context.Context, result T, args ...string) error {
 	// request output in the json format
 	args = append(args, "--output", "json")
+	args = append(args, a.rpcArgs...)
 
 	_, reader, err := a.container.Exec(ctx, append([]string{"/app/kwild"}, args...))
 	if err != nil {
@@ -289,7 +293,9 @@ type snapshotRes struct {
 
 func (a *AdminClient) CreateSnapshot(ctx context.Context, host, port, dbname, user, snapDir string) (string, types.HexBytes, error) {
 	var res snapshotRes
-	err := exec(a, ctx, &res, "snapshot", "create", "--host", host, "--port", port, "--dbname", dbname, "--user", user, "--snapdir", snapDir)
+	// the snapshot command does not connect to the admin service
+	local := &AdminClient{container: a.container}
+	err := exec(local, ctx, &res, "snapshot", "create", "--host", host, "--port", port, "--dbname", dbname, "--user", user, "--snapdir", snapDir)
 	if err != nil {
 		return "", nil, err
 	}
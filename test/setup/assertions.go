@@ -0,0 +1,107 @@
+package setup
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/kwilteam/kwil-db/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+// assertT is the part of testing.T that the assertions of KwilNode use, so
+// that their failures can be tested without a network.
+type assertT interface {
+	require.TestingT
+	Helper()
+}
+
+// AssertBlockHeight fails the test if the node has not committed the block at
+// minHeight.
+func (k *kwilNode) AssertBlockHeight(ctx context.Context, t *testing.T, minHeight int64) {
+	t.Helper()
+	assertBlockHeight(ctx, t, k.JSONRPCClient(t, ctx, nil), minHeight)
+}
+
+// AssertNamespaceExists fails the test if the namespace does not exist.
+func (k *kwilNode) AssertNamespaceExists(ctx context.Context, t *testing.T, namespace string) {
+	t.Helper()
+	assertNamespaceExists(ctx, t, k.JSONRPCClient(t, ctx, nil), namespace)
+}
+
+// AssertActionResult fails the test if calling the action with args does not
+// return the expected rows, in order. Values are compared by their string
+// formatting, since the drivers decode them differently.
+func (k *kwilNode) AssertActionResult(ctx context.Context, t *testing.T, namespace, action string, args []any, expected []map[string]any) {
+	t.Helper()
+	assertActionResult(ctx, t, k.JSONRPCClient(t, ctx, nil), namespace, action, args, expected)
+}
+
+// AssertTransactionCommitted fails the test if the transaction is not in a
+// block, or if it failed.
+func (k *kwilNode) AssertTransactionCommitted(ctx context.Context, t *testing.T, txHash []byte) {
+	t.Helper()
+	assertTransactionCommitted(ctx, t, k.JSONRPCClient(t, ctx, nil), txHash)
+}
+
+func assertBlockHeight(ctx context.Context, t assertT, client JSONRPCClient, minHeight int64) {
+	t.Helper()
+
+	info, err := client.ChainInfo(ctx)
+	require.NoError(t, err, "failed to get the chain info")
+	require.GreaterOrEqualf(t, int64(info.BlockHeight), minHeight,
+		"node is at block %d, want at least block %d", info.BlockHeight, minHeight)
+}
+
+func assertNamespaceExists(ctx context.Context, t assertT, client JSONRPCClient, namespace string) {
+	t.Helper()
+
+	// namespaces are lower cased in info.namespaces
+	res, err := client.Query(ctx, `SELECT name FROM info.namespaces WHERE name = $name`, map[string]any{
+		"name": strings.ToLower(namespace),
+	}, true)
+	require.NoErrorf(t, err, "failed to query namespace %s", namespace)
+	require.NotEmptyf(t, res.Values, "namespace %s does not exist", namespace)
+}
+
+func assertActionResult(ctx context.Context, t assertT, client JSONRPCClient, namespace, action string, args []any, expected []map[string]any) {
+	t.Helper()
+
+	res, err := client.Call(ctx, namespace, action, args)
+	require.NoErrorf(t, err, "failed to call %s.%s", namespace, action)
+	if res.Error != nil {
+		require.FailNowf(t, "action failed", "%s.%s returned an error: %s", namespace, action, *res.Error)
+	}
+
+	var got []map[string]string
+	if res.QueryResult != nil {
+		got = res.QueryResult.ExportToStringMap()
+	}
+
+	want := make([]map[string]string, len(expected))
+	for i, row := range expected {
+		want[i] = make(map[string]string, len(row))
+		for col, val := range row {
+			want[i][col] = fmt.Sprintf("%v", val)
+		}
+	}
+
+	require.Equalf(t, len(want), len(got), "%s.%s returned %d rows, want %d: %v",
+		namespace, action, len(got), len(want), got)
+	for i := range want {
+		require.Equalf(t, want[i], got[i], "row %d of %s.%s", i, namespace, action)
+	}
+}
+
+func assertTransactionCommitted(ctx context.Context, t assertT, client JSONRPCClient, txHash []byte) {
+	t.Helper()
+
+	hash, err := types.NewHashFromBytes(txHash)
+	require.NoErrorf(t, err, "invalid transaction hash %x", txHash)
+
+	resp, err := client.TxQuery(ctx, hash)
+	require.NoErrorf(t, err, "failed to query transaction %s", hash)
+	require.Truef(t, resp.Height > 0 && resp.Result != nil, "transaction %s is not committed", hash)
+	require.Equalf(t, uint32(types.CodeOk), resp.Result.Code, "transaction %s failed: %s", hash, resp.Result.Log)
+}
@@ -0,0 +1,152 @@
+package setup
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/kwilteam/kwil-db/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeT records the failure of an assertion.
+type fakeT struct {
+	failed bool
+	msg    strings.Builder
+}
+
+func (f *fakeT) Helper() {}
+
+func (f *fakeT) Errorf(format string, args ...any) {
+	f.failed = true
+	fmt.Fprintf(&f.msg, format, args...)
+}
+
+func (f *fakeT) FailNow() {
+	f.failed = true
+	runtime.Goexit()
+}
+
+// runAssertion runs an assertion with a fakeT, and returns whether it failed
+// and its message.
+func runAssertion(assertion func(t assertT)) (bool, string) {
+	ft := &fakeT{}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		assertion(ft)
+	}()
+	<-done
+	return ft.failed, ft.msg.String()
+}
+
+// fakeClient is a JSONRPCClient with a chain state set by the test.
+type fakeClient struct {
+	JSONRPCClient
+	height     uint64
+	namespaces []string
+	results    map[string]*types.CallResult
+	txs        map[types.Hash]*types.TxQueryResponse
+}
+
+func (f *fakeClient) ChainInfo(context.Context) (*types.ChainInfo, error) {
+	return &types.ChainInfo{BlockHeight: f.height}, nil
+}
+
+func (f *fakeClient) Query(_ context.Context, _ string, params map[string]any, _ bool) (*types.QueryResult, error) {
+	res := &types.QueryResult{ColumnNames: []string{"name"}}
+	for _, ns := range f.namespaces {
+		if ns == params["name"] {
+			res.Values = append(res.Values, []any{ns})
+		}
+	}
+	return res, nil
+}
+
+func (f *fakeClient) Call(_ context.Context, namespace, action string, _ []any) (*types.CallResult, error) {
+	res, ok := f.results[namespace+"."+action]
+	if !ok {
+		return nil, fmt.Errorf("action %s.%s not found", namespace, action)
+	}
+	return res, nil
+}
+
+func (f *fakeClient) TxQuery(_ context.Context, txHash types.Hash) (*types.TxQueryResponse, error) {
+	res, ok := f.txs[txHash]
+	if !ok {
+		return &types.TxQueryResponse{Hash: txHash, Height: -1}, nil
+	}
+	return res, nil
+}
+
+func Test_Assertions(t *testing.T) {
+	ctx := context.Background()
+
+	committed := types.HashBytes([]byte("committed"))
+	failed := types.HashBytes([]byte("failed"))
+	actionErr := "division by zero"
+	client := &fakeClient{
+		height:     5,
+		namespaces: []string{"main", "users"},
+		results: map[string]*types.CallResult{
+			"users.get": {QueryResult: &types.QueryResult{
+				ColumnNames: []string{"id", "name"},
+				Values:      [][]any{{1, "alice"}, {2, "bob"}},
+			}},
+			"users.broken": {Error: &actionErr},
+		},
+		txs: map[types.Hash]*types.TxQueryResponse{
+			committed: {Hash: committed, Height: 3, Result: &types.TxResult{Code: uint32(types.CodeOk)}},
+			failed:    {Hash: failed, Height: 4, Result: &types.TxResult{Code: uint32(types.CodeUnknownError), Log: "out of gas"}},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		assertion func(t assertT)
+		wantMsg   string // empty if the assertion holds
+	}{
+		{"height reached", func(t assertT) { assertBlockHeight(ctx, t, client, 5) }, ""},
+		{"height not reached", func(t assertT) { assertBlockHeight(ctx, t, client, 6) }, "node is at block 5, want at least block 6"},
+		{"namespace exists", func(t assertT) { assertNamespaceExists(ctx, t, client, "Users") }, ""},
+		{"namespace does not exist", func(t assertT) { assertNamespaceExists(ctx, t, client, "posts") }, "namespace posts does not exist"},
+		{"action result", func(t assertT) {
+			assertActionResult(ctx, t, client, "users", "get", nil, []map[string]any{
+				{"id": 1, "name": "alice"},
+				{"id": int64(2), "name": "bob"},
+			})
+		}, ""},
+		{"wrong row", func(t assertT) {
+			assertActionResult(ctx, t, client, "users", "get", nil, []map[string]any{
+				{"id": 1, "name": "alice"},
+				{"id": 2, "name": "carol"},
+			})
+		}, "row 1 of users.get"},
+		{"wrong row count", func(t assertT) {
+			assertActionResult(ctx, t, client, "users", "get", nil, []map[string]any{{"id": 1, "name": "alice"}})
+		}, "users.get returned 2 rows, want 1"},
+		{"action error", func(t assertT) { assertActionResult(ctx, t, client, "users", "broken", nil, nil) }, "division by zero"},
+		{"unknown action", func(t assertT) { assertActionResult(ctx, t, client, "users", "missing", nil, nil) }, "failed to call users.missing"},
+		{"tx committed", func(t assertT) { assertTransactionCommitted(ctx, t, client, committed[:]) }, ""},
+		{"tx failed", func(t assertT) { assertTransactionCommitted(ctx, t, client, failed[:]) }, "out of gas"},
+		{"tx not committed", func(t assertT) {
+			hash := types.HashBytes([]byte("pending"))
+			assertTransactionCommitted(ctx, t, client, hash[:])
+		}, "is not committed"},
+		{"invalid tx hash", func(t assertT) { assertTransactionCommitted(ctx, t, client, []byte{1, 2}) }, "invalid transaction hash 0102"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failed, msg := runAssertion(tt.assertion)
+			if tt.wantMsg == "" {
+				require.False(t, failed, msg)
+				return
+			}
+			require.True(t, failed)
+			require.Contains(t, msg, tt.wantMsg)
+		})
+	}
+}
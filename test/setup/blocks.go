@@ -0,0 +1,73 @@
+package setup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// blockPollInitialInterval is the first interval at which the block
+	// height of a node is polled. It doubles up to blockPollMaxInterval.
+	blockPollInitialInterval = 100 * time.Millisecond
+	blockPollMaxInterval     = 2 * time.Second
+)
+
+// WaitAllNodesBlock waits until all running nodes of the testnet have
+// committed the block at the height. Nodes whose services were not started
+// are skipped.
+func (t *Testnet) WaitAllNodesBlock(ctx context.Context, height int64) error {
+	var wg sync.WaitGroup
+	errs := make([]error, len(t.Nodes))
+	for i, n := range t.Nodes {
+		node, ok := n.(*kwilNode)
+		if !ok {
+			return fmt.Errorf("unexpected node type %T", n)
+		}
+		if _, running := t.testCtx.containers[node.generatedInfo.KwilNodeServiceName]; !running {
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			client, err := node.newJSONRPCClient(ctx)
+			if err == nil {
+				err = waitForBlock(ctx, client, height)
+			}
+			if err != nil {
+				errs[i] = fmt.Errorf("node %s: %w", node.generatedInfo.KwilNodeServiceName, err)
+			}
+		}()
+	}
+
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// waitForBlock polls the chain info of a node, with exponential backoff,
+// until the node has committed the block at the height. Errors getting the
+// chain info are retried, since the node may still be starting.
+func waitForBlock(ctx context.Context, client JSONRPCClient, height int64) error {
+	interval := blockPollInitialInterval
+	for {
+		info, err := client.ChainInfo(ctx)
+		if err == nil && int64(info.BlockHeight) >= height {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			if err != nil {
+				return fmt.Errorf("block %d not reached: %w", height, err)
+			}
+			return fmt.Errorf("block %d not reached, at block %d: %w", height, info.BlockHeight, ctx.Err())
+		case <-time.After(interval):
+		}
+
+		interval = min(2*interval, blockPollMaxInterval)
+	}
+}
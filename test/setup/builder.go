@@ -0,0 +1,102 @@
+package setup
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kwilteam/kwil-db/config"
+)
+
+// TestnetBuilder builds a test network with chained methods, as a shorter
+// alternative to writing a TestConfig:
+//
+//	testnet := setup.NewTestnetBuilder(t).
+//		WithNodes(3).
+//		WithValidator(0).
+//		WithValidator(1).
+//		WithClientDriver(setup.Go).
+//		Build()
+//
+// Nodes are created with DefaultNodeConfig. All of them are validators, unless
+// WithValidator is used to choose the validators.
+type TestnetBuilder struct {
+	t          *testing.T
+	nodes      int
+	validators []int
+	config     TestConfig
+	network    NetworkConfig
+}
+
+// NewTestnetBuilder returns a builder for a test network of the test.
+func NewTestnetBuilder(t *testing.T) *TestnetBuilder {
+	return &TestnetBuilder{t: t}
+}
+
+// WithNodes sets the number of nodes in the network. It must be at least one.
+func (b *TestnetBuilder) WithNodes(n int) *TestnetBuilder {
+	b.nodes = n
+	return b
+}
+
+// WithValidator makes the node at index i a validator. Once it is used, the
+// nodes that it is not used for are not validators.
+func (b *TestnetBuilder) WithValidator(i int) *TestnetBuilder {
+	b.validators = append(b.validators, i)
+	return b
+}
+
+// WithGenesisConfig sets the function that alters the genesis configuration.
+func (b *TestnetBuilder) WithGenesisConfig(f func(*config.GenesisConfig)) *TestnetBuilder {
+	b.network.ConfigureGenesis = f
+	return b
+}
+
+// WithDBOwner sets the wallet address that owns the database.
+func (b *TestnetBuilder) WithDBOwner(owner string) *TestnetBuilder {
+	b.network.DBOwner = owner
+	return b
+}
+
+// WithClientDriver sets the driver used by the clients of the nodes.
+func (b *TestnetBuilder) WithClientDriver(d ClientDriver) *TestnetBuilder {
+	b.config.ClientDriver = d
+	return b
+}
+
+// WithContainerTimeout sets the timeout for starting a container.
+func (b *TestnetBuilder) WithContainerTimeout(timeout time.Duration) *TestnetBuilder {
+	b.config.ContainerStartTimeout = timeout
+	return b
+}
+
+// Config returns the TestConfig that Build sets the network up with. The
+// test fails if a validator is not one of the nodes.
+func (b *TestnetBuilder) Config() *TestConfig {
+	b.t.Helper()
+
+	nodes := make([]*NodeConfig, max(b.nodes, 0))
+	for i := range nodes {
+		nodes[i] = DefaultNodeConfig()
+		nodes[i].Validator = len(b.validators) == 0
+	}
+	for _, i := range b.validators {
+		if i < 0 || i >= len(nodes) {
+			b.t.Fatalf("validator %d is not one of the %d nodes", i, len(nodes))
+		}
+		nodes[i].Validator = true
+	}
+
+	network := b.network
+	network.Nodes = nodes
+
+	cfg := b.config
+	cfg.Network = &network
+	return &cfg
+}
+
+// Build sets the network up with SetupTests. The test fails if the
+// configuration is invalid, e.g. if the network has no nodes.
+func (b *TestnetBuilder) Build() *Testnet {
+	b.t.Helper()
+	return SetupTests(b.t, b.Config())
+}
@@ -0,0 +1,91 @@
+package setup_test
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/kwilteam/kwil-db/config"
+	"github.com/kwilteam/kwil-db/test/setup"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_TestnetBuilderConfig(t *testing.T) {
+	genesisConfigured := false
+	built := setup.NewTestnetBuilder(t).
+		WithNodes(3).
+		WithValidator(0).
+		WithValidator(1).
+		WithGenesisConfig(func(*config.GenesisConfig) { genesisConfigured = true }).
+		WithClientDriver(setup.Go).
+		WithContainerTimeout(time.Minute).
+		Config()
+
+	// the same network, configured with the struct API
+	want := &setup.TestConfig{
+		ClientDriver:          setup.Go,
+		ContainerStartTimeout: time.Minute,
+		Network: &setup.NetworkConfig{
+			Nodes: []*setup.NodeConfig{
+				setup.DefaultNodeConfig(),
+				setup.DefaultNodeConfig(),
+				setup.CustomNodeConfig(func(nc *setup.NodeConfig) {
+					nc.Validator = false
+				}),
+			},
+		},
+	}
+
+	require.Equal(t, want.ClientDriver, built.ClientDriver)
+	require.Equal(t, want.ContainerStartTimeout, built.ContainerStartTimeout)
+	require.Len(t, built.Network.Nodes, len(want.Network.Nodes))
+	for i, node := range built.Network.Nodes {
+		require.Equal(t, want.Network.Nodes[i].Validator, node.Validator, "node %d", i)
+		require.Equal(t, want.Network.Nodes[i].DockerImage, node.DockerImage, "node %d", i)
+		require.NotNil(t, node.PrivateKey, "node %d", i)
+	}
+
+	built.Network.ConfigureGenesis(nil)
+	require.True(t, genesisConfigured)
+
+	// without WithValidator, all nodes are validators, as with DefaultNodeConfig
+	built = setup.NewTestnetBuilder(t).WithNodes(2).Config()
+	for _, node := range built.Network.Nodes {
+		require.True(t, node.Validator)
+	}
+}
+
+// Test_TestnetBuilderInvalid checks that invalid configurations fail the
+// test. Since a failed test cannot be recovered from, each configuration is
+// built by a subprocess that runs this test.
+func Test_TestnetBuilderInvalid(t *testing.T) {
+	invalid := map[string]func(t *testing.T){
+		"no nodes": func(t *testing.T) {
+			setup.NewTestnetBuilder(t).WithClientDriver(setup.Go).Build()
+		},
+		"zero nodes": func(t *testing.T) {
+			setup.NewTestnetBuilder(t).WithNodes(0).WithClientDriver(setup.Go).Build()
+		},
+		"validator out of range": func(t *testing.T) {
+			setup.NewTestnetBuilder(t).WithNodes(1).WithValidator(1).WithClientDriver(setup.Go).Build()
+		},
+	}
+
+	if name := os.Getenv("TESTNET_BUILDER_INVALID"); name != "" {
+		invalid[name](t)
+		return
+	}
+
+	for name := range invalid {
+		t.Run(name, func(t *testing.T) {
+			cmd := exec.Command(os.Args[0], "-test.run=^Test_TestnetBuilderInvalid$")
+			cmd.Env = append(os.Environ(), "TESTNET_BUILDER_INVALID="+name)
+			out, err := cmd.CombinedOutput()
+
+			var exitErr *exec.ExitError
+			require.ErrorAs(t, err, &exitErr, string(out))
+			require.Contains(t, string(out), "--- FAIL: Test_TestnetBuilderInvalid")
+		})
+	}
+}
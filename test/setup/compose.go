@@ -3,26 +3,41 @@ package setup
 import (
 	"bytes"
 	_ "embed"
+	"fmt"
+	"net"
 	"os"
+	"slices"
 	"strconv"
+	"strings"
 	"text/template"
+
+	"github.com/testcontainers/testcontainers-go/wait"
+	"gopkg.in/yaml.v3"
 )
 
 var (
+	// DefaultNodeComposeTemplate is the default TestConfig.ComposeTemplate.
+	// It is executed for each node with the fields Network, NodeNumber,
+	// NodeServicePrefix, NoHealthCheck, PGServicePrefix, TestnetDir,
+	// ExposedJSONRPCPort, ExposedP2PPort, DockerImage, EnableProfiling,
+	// EnableMTLS, AdminMTLSPort, UserID, and GroupID.
 	//go:embed node-compose.yml.template
-	nodeComposeTemplateString string
-	nodeComposeTemplate       = template.Must(template.New("node-compose-template").Parse(nodeComposeTemplateString))
+	DefaultNodeComposeTemplate string
 
 	//go:embed header-compose.yml.template
 	headerComposeTemplateString string
 	headerComposeTemplate       = template.Must(template.New("header-compose-template").Parse(headerComposeTemplateString))
 
+	//go:embed netem-compose.yml.template
+	netemComposeTemplateString string
+	netemComposeTemplate       = template.Must(template.New("netem-compose-template").Parse(netemComposeTemplateString))
+
 	//go:embed other-compose.yml.template
 	otherComposeTemplateString string
 	otherComposeTemplate       = template.Must(template.New("other-compose-template").Parse(otherComposeTemplateString))
 )
 
-// nodeTemplate works with the node-compose.yml.template file to generate
+// nodeTemplate works with the node compose template to generate
 // part of the docker-compose.yml file
 type nodeTemplate struct {
 	// Network is the name of the network
@@ -44,15 +59,57 @@ type nodeTemplate struct {
 	ExposedP2PPort int
 	// DockerImage is the Kwil docker image to use
 	DockerImage string
+	// EnableProfiling exposes the http profiler on a port assigned by docker
+	EnableProfiling bool
+	// EnableMTLS makes the admin service listen on AdminMTLSPort, which is
+	// exposed on a port assigned by docker, with mutual TLS
+	EnableMTLS bool
+	// AdminMTLSPort is the port that the admin service listens on if
+	// EnableMTLS is set
+	AdminMTLSPort int
 	// UserID is the user ID to run the node as
 	UserID string
 	// GroupID is the group ID to run the node as
 	GroupID string
 }
 
-func (n *nodeTemplate) generate() (string, error) {
+// newNodeTemplate returns the template data of the node with the number.
+func newNodeTemplate(dockerNetwork string, testnetDir string, nodeConf *NodeConfig, number int, userAndGroupIDs *[2]string, networkPrefix string, portsOffset int, enableMTLS bool) *nodeTemplate {
+	node := &nodeTemplate{
+		Network:            dockerNetwork,
+		NodeNumber:         number,
+		NodeServicePrefix:  networkPrefix + "node",
+		NoHealthCheck:      nodeConf.NoHealthCheck,
+		PGServicePrefix:    networkPrefix + "pg",
+		TestnetDir:         testnetDir,
+		ExposedJSONRPCPort: 8484 + number + portsOffset,
+		ExposedP2PPort:     6600 + number + portsOffset,
+		DockerImage:        nodeConf.DockerImage,
+		EnableProfiling:    nodeConf.EnableProfiling,
+		EnableMTLS:         enableMTLS,
+		AdminMTLSPort:      adminMTLSPort,
+	}
+
+	if userAndGroupIDs != nil {
+		node.UserID = userAndGroupIDs[0]
+		node.GroupID = userAndGroupIDs[1]
+	}
+
+	return node
+}
+
+// info returns the generated info of the node.
+func (n *nodeTemplate) info() *generatedNodeInfo {
+	return &generatedNodeInfo{
+		ExposedJSONRPCPort:  n.ExposedJSONRPCPort,
+		KwilNodeServiceName: n.NodeServicePrefix + strconv.Itoa(n.NodeNumber),
+		PostgresServiceName: n.PGServicePrefix + strconv.Itoa(n.NodeNumber),
+	}
+}
+
+func (n *nodeTemplate) generate(tmpl *template.Template) (string, error) {
 	var res bytes.Buffer
-	err := nodeComposeTemplate.Execute(&res, n)
+	err := tmpl.Execute(&res, n)
 	if err != nil {
 		return "", err
 	}
@@ -69,7 +126,10 @@ type headerTemplate struct {
 // generateCompose generates a full docker-compose.yml file for a given number of nodes.
 // It takes a network name, docker image, and node count.
 // Optionally, it can also be given a user and group, which if set, will be used to run the nodes as.
-func generateCompose(dockerNetwork string, testnetDir string, nodeConfs []*NodeConfig, otherSvcs []*CustomService, userAndGroupIDs *[2]string, networkPrefix string, portsOffset int,
+// The part of each node is generated with nodeTmpl, which can be nil if there are no nodes.
+// If enableMTLS is set, the admin services of the nodes listen with mutual TLS.
+func generateCompose(dockerNetwork string, testnetDir string, nodeConfs []*NodeConfig, nodeTmpl *template.Template, otherSvcs []*CustomService, userAndGroupIDs *[2]string, networkPrefix string, portsOffset int,
+	enableMTLS bool,
 ) (composeFilepath string, nodeGeneratedInfo []*generatedNodeInfo, err error) {
 	var res bytes.Buffer
 	err = headerComposeTemplate.Execute(&res, &headerTemplate{Network: dockerNetwork})
@@ -77,39 +137,43 @@ func generateCompose(dockerNetwork string, testnetDir string, nodeConfs []*NodeC
 		return "", nil, err
 	}
 
-	nodePrefix := networkPrefix + "node"
-	pgPrefix := networkPrefix + "pg"
-
 	var nodes []*generatedNodeInfo
 	for i, nodeConf := range nodeConfs {
-		node := &nodeTemplate{
-			Network:            dockerNetwork,
-			NodeNumber:         i,
-			NodeServicePrefix:  nodePrefix,
-			NoHealthCheck:      nodeConf.NoHealthCheck,
-			PGServicePrefix:    pgPrefix,
-			TestnetDir:         testnetDir,
-			ExposedJSONRPCPort: 8484 + i + portsOffset,
-			ExposedP2PPort:     6600 + i + portsOffset,
-			DockerImage:        nodeConf.DockerImage,
-		}
+		node := newNodeTemplate(dockerNetwork, testnetDir, nodeConf, i, userAndGroupIDs, networkPrefix, portsOffset, enableMTLS)
 
-		if userAndGroupIDs != nil {
-			node.UserID = userAndGroupIDs[0]
-			node.GroupID = userAndGroupIDs[1]
-		}
-
-		nodeYml, err := node.generate()
+		nodeYml, err := node.generate(nodeTmpl)
 		if err != nil {
 			return "", nil, err
 		}
 		res.WriteString(nodeYml)
 
-		nodes = append(nodes, &generatedNodeInfo{
-			ExposedJSONRPCPort:  node.ExposedJSONRPCPort,
-			KwilNodeServiceName: nodePrefix + strconv.Itoa(i),
-			PostgresServiceName: pgPrefix + strconv.Itoa(i),
-		})
+		nodes = append(nodes, node.info())
+	}
+
+	for i, nodeConf := range nodeConfs {
+		if nodeConf.Region == nil {
+			continue
+		}
+
+		netem := &netemTemplate{
+			ServiceName:     nodes[i].KwilNodeServiceName + "-netem",
+			NodeServiceName: nodes[i].KwilNodeServiceName,
+			DelayMicros:     nodeConf.Region.InterRegionLatencyMs * 1000 / 2,
+			WaitMsg:         netemWaitMsg,
+		}
+		for j, other := range nodeConfs {
+			if other.Region != nil && other.Region.RegionName != nodeConf.Region.RegionName {
+				netem.OtherRegionNodes = append(netem.OtherRegionNodes, nodes[j].KwilNodeServiceName)
+			}
+		}
+		if len(netem.OtherRegionNodes) == 0 {
+			continue
+		}
+
+		if err = netemComposeTemplate.Execute(&res, netem); err != nil {
+			return "", nil, err
+		}
+		nodes[i].NetemServiceName = netem.ServiceName
 	}
 
 	for _, svc := range otherSvcs {
@@ -156,6 +220,27 @@ type generatedNodeInfo struct {
 	KwilNodeServiceName string
 	// the service name of the postgres container
 	PostgresServiceName string
+	// the service name of the container that simulates the latency to
+	// other regions. It is empty if the node has no latency to simulate.
+	NetemServiceName string
+}
+
+// netemTemplate works with the netem-compose.yml.template file to generate
+// the container that simulates the latency between a node and the nodes of
+// other regions. The container shares the network namespace of the node, and
+// delays the packets it sends to the other regions by half the latency, so
+// that the round trip has the configured latency.
+type netemTemplate struct {
+	// ServiceName is the name of the netem service
+	ServiceName string
+	// NodeServiceName is the name of the node's kwild service
+	NodeServiceName string
+	// OtherRegionNodes are the kwild services of the nodes in other regions
+	OtherRegionNodes []string
+	// DelayMicros is the delay added to the packets sent to other regions
+	DelayMicros int
+	// WaitMsg is logged once the delay is configured
+	WaitMsg string
 }
 
 // serviceTemplate is is used to generate part of a docker-compose.yml file for
@@ -207,6 +292,66 @@ type CustomService struct {
 	ServiceProto string
 	// OPTIONAL: WaitMsg is a log that Docker will wait for before considering the service to be up
 	WaitMsg string
+	// OPTIONAL: WaitStrategy is used to wait for the service to be up, for
+	// services that are not ready when a message is logged. It cannot be set
+	// with WaitMsg.
+	WaitStrategy wait.Strategy
 	// OPTIONAL: DependsOn specify a service that needs to be healthy
 	DependsOn string
 }
+
+// checkComposePorts verifies that the host ports mapped by the services of a
+// docker-compose.yml file are unique, and available on the host. Ports that
+// are assigned by docker are not checked.
+func checkComposePorts(composePath string) error {
+	bts, err := os.ReadFile(composePath)
+	if err != nil {
+		return err
+	}
+
+	var compose struct {
+		Services map[string]struct {
+			Ports []string `yaml:"ports"`
+		} `yaml:"services"`
+	}
+	if err = yaml.Unmarshal(bts, &compose); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", composePath, err)
+	}
+
+	// services are sorted so that errors are deterministic
+	services := make([]string, 0, len(compose.Services))
+	for name := range compose.Services {
+		services = append(services, name)
+	}
+	slices.Sort(services)
+
+	hostPorts := make(map[string]string) // host port -> service
+	var ports []string
+	for _, svc := range services {
+		for _, mapping := range compose.Services[svc].Ports {
+			// the mapping is [[ip:]host:]container[/protocol]
+			mapping, _, _ = strings.Cut(mapping, "/")
+			segs := strings.Split(strings.TrimSpace(mapping), ":")
+			if len(segs) < 2 {
+				continue
+			}
+			port := segs[len(segs)-2]
+
+			if other, ok := hostPorts[port]; ok {
+				return fmt.Errorf("services %s and %s both map host port %s", other, svc, port)
+			}
+			hostPorts[port] = svc
+			ports = append(ports, port)
+		}
+	}
+
+	for _, port := range ports {
+		l, err := net.Listen("tcp", ":"+port)
+		if err != nil {
+			return fmt.Errorf("host port %s of service %s is not available: %w", port, hostPorts[port], err)
+		}
+		l.Close()
+	}
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package setup
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CheckComposePorts(t *testing.T) {
+	writeCompose := func(t *testing.T, services string) string {
+		path := filepath.Join(t.TempDir(), "docker-compose.yml")
+		err := os.WriteFile(path, []byte("services:\n"+services), 0644)
+		require.NoError(t, err)
+		return path
+	}
+
+	t.Run("unique ports", func(t *testing.T) {
+		path := writeCompose(t, `
+  node0:
+    ports:
+      - "38484:8484"
+      - "36600:6600"
+      - "6060"
+  pg0:
+    ports:
+      - "5432"
+`)
+		require.NoError(t, checkComposePorts(path))
+	})
+
+	t.Run("port collision", func(t *testing.T) {
+		path := writeCompose(t, `
+  node0:
+    ports:
+      - "38484:8484"
+      - "36600:6600"
+  node1:
+    ports:
+      - "38485:8484"
+      - "127.0.0.1:36600:6600/tcp"
+`)
+		err := checkComposePorts(path)
+		require.EqualError(t, err, "services node0 and node1 both map host port 36600")
+	})
+
+	t.Run("port in use", func(t *testing.T) {
+		l, err := net.Listen("tcp", ":0")
+		require.NoError(t, err)
+		defer l.Close()
+		port := strconv.Itoa(l.Addr().(*net.TCPAddr).Port)
+
+		path := writeCompose(t, `
+  node0:
+    ports:
+      - "`+port+`:8484"
+`)
+		err = checkComposePorts(path)
+		require.ErrorContains(t, err, "host port "+port+" of service node0 is not available")
+	})
+}
+
+func Test_GenerateComposeCustomTemplate(t *testing.T) {
+	tmpl, err := template.New("node-compose-template").Parse(DefaultNodeComposeTemplate + `
+  sidecar{{ .NodeNumber }}:
+    image: busybox
+    networks:
+      - {{ .Network }}
+`)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	path, nodes, err := generateCompose("testnet", dir, []*NodeConfig{DefaultNodeConfig(), DefaultNodeConfig()}, tmpl, nil, nil, "", 0, false)
+	require.NoError(t, err)
+	require.Len(t, nodes, 2)
+
+	bts, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Contains(t, string(bts), "sidecar0:")
+	require.Contains(t, string(bts), "sidecar1:")
+
+	require.NoError(t, checkComposePorts(path))
+}
@@ -0,0 +1,63 @@
+package setup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kwilteam/kwil-db/core/types"
+	"github.com/kwilteam/kwil-db/node/engine"
+)
+
+// healthPollInterval is how often WaitForNamespaceHealth calls the health
+// action of a namespace.
+var healthPollInterval = 500 * time.Millisecond
+
+// WaitForNamespaceHealth waits until the health action of a namespace,
+// called on the first node of the testnet, succeeds. It returns an error if
+// the namespace is not healthy before the timeout.
+func (t *Testnet) WaitForNamespaceHealth(ctx context.Context, namespace string, timeout time.Duration) error {
+	if len(t.Nodes) == 0 {
+		return errors.New("testnet has no nodes")
+	}
+	node, ok := t.Nodes[0].(*kwilNode)
+	if !ok {
+		return fmt.Errorf("unexpected node type %T", t.Nodes[0])
+	}
+
+	client, err := node.newJSONRPCClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	return waitForHealth(ctx, client.Call, namespace, timeout)
+}
+
+// callFunc calls an action. It matches the Call method of the clients.
+type callFunc func(ctx context.Context, namespace string, action string, inputs []any) (*types.CallResult, error)
+
+// waitForHealth polls the health action of a namespace until it succeeds.
+func waitForHealth(ctx context.Context, call callFunc, namespace string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(healthPollInterval)
+	defer ticker.Stop()
+
+	for {
+		res, err := call(ctx, namespace, engine.HealthAction, nil)
+		if err == nil && res.Error != nil {
+			err = errors.New(*res.Error)
+		}
+		if err == nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("namespace %s is not healthy after %s: %w", namespace, timeout, err)
+		case <-ticker.C:
+		}
+	}
+}
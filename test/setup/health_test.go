@@ -0,0 +1,46 @@
+package setup
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kwilteam/kwil-db/core/types"
+	"github.com/kwilteam/kwil-db/node/engine"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_WaitForHealth(t *testing.T) {
+	healthPollInterval = 10 * time.Millisecond
+
+	t.Run("healthy", func(t *testing.T) {
+		calls := 0
+		call := func(ctx context.Context, namespace string, action string, inputs []any) (*types.CallResult, error) {
+			require.Equal(t, "ext", namespace)
+			require.Equal(t, engine.HealthAction, action)
+			require.Empty(t, inputs)
+
+			calls++
+			if calls < 3 {
+				return nil, errors.New("namespace not found")
+			}
+			return &types.CallResult{}, nil
+		}
+
+		err := waitForHealth(context.Background(), call, "ext", time.Second)
+		require.NoError(t, err)
+		require.Equal(t, 3, calls)
+	})
+
+	t.Run("health action errors", func(t *testing.T) {
+		call := func(ctx context.Context, namespace string, action string, inputs []any) (*types.CallResult, error) {
+			msg := "extension not ready"
+			return &types.CallResult{Error: &msg}, nil
+		}
+
+		err := waitForHealth(context.Background(), call, "ext", 100*time.Millisecond)
+		require.Error(t, err)
+		require.ErrorContains(t, err, "extension not ready")
+	})
+}
@@ -388,6 +388,55 @@ func (j *jsonRPCCLIDriver) GetAccount(ctx context.Context, acct *types.AccountID
 	}, nil
 }
 
+func (j *jsonRPCCLIDriver) SimulateCall(ctx context.Context, namespace string, action string, inputs []any, caller []byte) (*types.SimulationResult, error) {
+	if caller != nil {
+		return nil, fmt.Errorf("simulating as another caller is not supported in cli driver")
+	}
+
+	args := []string{"exec-action", action, "--simulate"}
+	if namespace != "" {
+		args = append(args, "--namespace", namespace)
+	}
+
+	params, err := formatActionParams(inputs)
+	if err != nil {
+		return nil, err
+	}
+	args = append(args, params...)
+
+	r := &types.SimulationResult{}
+	err = cmd(j, ctx, r, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (j *jsonRPCCLIDriver) GetTokenBalance(ctx context.Context, acct *types.AccountID, token []byte, status types.AccountStatus) (*big.Int, error) {
+	r := &respAccount{}
+
+	args := []string{"account", "balance", hex.EncodeToString(acct.Identifier), "--keytype", acct.KeyType.String()}
+	if len(token) > 0 {
+		args = append(args, "--token", hex.EncodeToString(token))
+	}
+	if status == types.AccountStatusPending {
+		args = append(args, "--pending")
+	}
+
+	err := cmd(j, ctx, r, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	bal, ok := big.NewInt(0).SetString(r.Balance, 10)
+	if !ok {
+		return nil, errors.New("invalid decimal string balance")
+	}
+
+	return bal, nil
+}
+
 func (j *jsonRPCCLIDriver) Ping(ctx context.Context) (string, error) {
 	var r string
 	err := cmd(j, ctx, &r, "utils", "ping")
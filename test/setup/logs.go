@@ -0,0 +1,162 @@
+package setup
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/testcontainers/testcontainers-go"
+)
+
+var saveLogsDir = flag.String("save-logs-dir", "", "directory to save the captured container logs to, instead of the test log")
+
+// logPollInterval is how often captured container logs are read.
+var logPollInterval = time.Second
+
+// CaptureLogs captures the logs of the kwild container of each node until the
+// test ends. The logs are written to the test log, or to a file per container
+// in TestConfig.SaveLogsDir if it is set.
+func (tt *Testnet) CaptureLogs(ctx context.Context, t *testing.T) {
+	dir := tt.testCtx.config.SaveLogsDir
+	if dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, n := range tt.Nodes {
+		node, ok := n.(*kwilNode)
+		if !ok {
+			t.Fatalf("unexpected node type %T", n)
+		}
+
+		name := node.generatedInfo.KwilNodeServiceName
+		ct, ok := tt.testCtx.containers[name]
+		if !ok {
+			t.Fatalf("container %s not found", name)
+		}
+
+		lc := &logCapture{
+			container: ct,
+			done:      make(chan struct{}),
+		}
+		if dir != "" {
+			f, err := os.Create(filepath.Join(dir, name+".log"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			lc.out = f
+			lc.close = f.Close
+		} else {
+			w := &testLogWriter{t: t, prefix: name}
+			lc.out = w
+			lc.close = w.flush
+		}
+
+		tt.testCtx.logCaptures = append(tt.testCtx.logCaptures, lc)
+		lc.wg.Add(1)
+		go lc.run(ctx)
+	}
+}
+
+// flushLogs stops capturing logs, and writes the logs that were not yet
+// written.
+func (c *testingContext) flushLogs(ctx context.Context, t *testing.T) {
+	for _, lc := range c.logCaptures {
+		close(lc.done)
+		lc.wg.Wait()
+
+		if err := lc.poll(ctx); err != nil {
+			t.Logf("could not read logs: %v", err)
+		}
+		if err := lc.close(); err != nil {
+			t.Logf("could not close logs: %v", err)
+		}
+	}
+	c.logCaptures = nil
+}
+
+// logCapture captures the logs of a container. Since the container only
+// returns the logs written so far, it is polled, and only the logs that were
+// not yet read are written.
+type logCapture struct {
+	container *testcontainers.DockerContainer
+	out       io.Writer
+	close     func() error
+	done      chan struct{}
+	wg        sync.WaitGroup
+
+	read int64 // bytes of the logs that were already read
+}
+
+func (lc *logCapture) run(ctx context.Context) {
+	defer lc.wg.Done()
+
+	ticker := time.NewTicker(logPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-lc.done:
+			return
+		case <-ticker.C:
+			// the container may be restarting, so errors are retried
+			_ = lc.poll(ctx)
+		}
+	}
+}
+
+// poll writes the logs that were written since the last poll.
+func (lc *logCapture) poll(ctx context.Context) error {
+	rc, err := lc.container.Logs(ctx)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	if _, err = io.CopyN(io.Discard, rc, lc.read); err != nil {
+		return err
+	}
+
+	n, err := io.Copy(lc.out, rc)
+	lc.read += n
+	return err
+}
+
+// testLogWriter writes lines to the test log.
+type testLogWriter struct {
+	t      *testing.T
+	prefix string
+	buf    []byte
+}
+
+func (w *testLogWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.t.Log(fmt.Sprintf("[%s] %s", w.prefix, w.buf[:i]))
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// flush writes the last line, if it did not end with a newline.
+func (w *testLogWriter) flush() error {
+	if len(w.buf) > 0 {
+		w.t.Log(fmt.Sprintf("[%s] %s", w.prefix, w.buf))
+		w.buf = nil
+	}
+	return nil
+}
@@ -0,0 +1,243 @@
+package setup
+
+import (
+	"bufio"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/docker/go-connections/nat"
+	"github.com/kwilteam/kwil-db/config"
+	"github.com/testcontainers/testcontainers-go"
+)
+
+// adminMTLSPort is the port that the admin service of the nodes listens on
+// when TestConfig.EnableMTLS is set.
+const adminMTLSPort = 8485
+
+// testCA is a certificate authority that issues the certificates of the admin
+// services of the nodes, and of the client that the tests connect to them
+// with.
+type testCA struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM []byte
+	// clientCertPEM and clientKeyPEM are the certificate and key of the
+	// client, which are also used by the admin commands run in the nodes.
+	clientCertPEM, clientKeyPEM []byte
+	// clientTLS is the configuration of the client, with a certificate
+	// issued by the CA.
+	clientTLS *tls.Config
+}
+
+// The files written to the root directory of a node for its admin commands,
+// in addition to the key pair and clients.pem file of its admin service.
+const (
+	adminCAFile         = "admin-ca.cert"
+	adminClientCertFile = "adminclient.cert"
+	adminClientKeyFile  = "adminclient.key"
+)
+
+// newTestCA generates a certificate authority, and issues a client
+// certificate with it.
+func newTestCA() (*testCA, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "kwil testnet CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+
+	ca := &testCA{
+		cert:    cert,
+		key:     key,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	}
+
+	ca.clientCertPEM, ca.clientKeyPEM, err = ca.issue("kwil testnet client", nil, x509.ExtKeyUsageClientAuth)
+	if err != nil {
+		return nil, err
+	}
+	clientCert, err := tls.X509KeyPair(ca.clientCertPEM, ca.clientKeyPEM)
+	if err != nil {
+		return nil, err
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(cert)
+	ca.clientTLS = &tls.Config{
+		Certificates: []tls.Certificate{clientCert},
+		RootCAs:      roots,
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	return ca, nil
+}
+
+// issue issues a certificate for the hosts, and returns it with its key.
+func (ca *testCA) issue(commonName string, hosts []string, usage x509.ExtKeyUsage) (certPEM, keyPEM []byte, err error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	for _, host := range hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			tmpl.IPAddresses = append(tmpl.IPAddresses, ip)
+		} else {
+			tmpl.DNSNames = append(tmpl.DNSNames, host)
+		}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), nil
+}
+
+// writeNodeCerts writes the certificate of the admin service of a node to its
+// root directory, along with the clients.pem file that authorizes the clients
+// with certificates issued by the CA. The certificate is valid for the service
+// name of the node in the docker network, and for the loopback addresses that
+// its exposed port is reached on. The client key pair and the CA are also
+// written, for the admin commands that are run in the node.
+func (ca *testCA) writeNodeCerts(nodeDir, serviceName string) error {
+	certPEM, keyPEM, err := ca.issue(serviceName, []string{serviceName, "localhost", "127.0.0.1", "::1"},
+		x509.ExtKeyUsageServerAuth)
+	if err != nil {
+		return err
+	}
+
+	files := []struct {
+		name string
+		data []byte
+		perm os.FileMode
+	}{
+		{config.AdminServerCertName, certPEM, 0644},
+		{config.AdminServerKeyName, keyPEM, 0600},
+		{"clients.pem", ca.certPEM, 0644},
+		{adminCAFile, ca.certPEM, 0644},
+		{adminClientCertFile, ca.clientCertPEM, 0644},
+		{adminClientKeyFile, ca.clientKeyPEM, 0600},
+	}
+	for _, f := range files {
+		if err = os.WriteFile(filepath.Join(nodeDir, f.name), f.data, f.perm); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// adminRPCArgs returns the flags that the admin commands run in a node connect
+// to its admin service with, over mutual TLS.
+func adminRPCArgs() []string {
+	return []string{
+		"--rpcserver", fmt.Sprintf("127.0.0.1:%d", adminMTLSPort),
+		"--authrpc-cert", "/app/kwil/" + adminCAFile,
+		"--tlscert", "/app/kwil/" + adminClientCertFile,
+		"--tlskey", "/app/kwil/" + adminClientKeyFile,
+	}
+}
+
+// verifyAdminMTLS checks that the admin service of a node only accepts
+// connections over mutual TLS with certificates issued by the CA. With TLS
+// 1.3, the server verifies the client certificate after the client considers
+// the handshake complete, so a request is made to check that the server
+// accepted it.
+func (ca *testCA) verifyAdminMTLS(ctx context.Context, ctr *testcontainers.DockerContainer) error {
+	endpoint, err := ctr.PortEndpoint(ctx, nat.Port(fmt.Sprint(adminMTLSPort)), "")
+	if err != nil {
+		return err
+	}
+
+	conn, err := tls.DialWithDialer(&net.Dialer{Timeout: 5 * time.Second}, "tcp", endpoint, ca.clientTLS)
+	if err != nil {
+		return fmt.Errorf("mutual TLS handshake with %s failed: %w", endpoint, err)
+	}
+	defer conn.Close()
+
+	if err = conn.SetDeadline(time.Now().Add(5 * time.Second)); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://"+endpoint+"/", nil)
+	if err != nil {
+		return err
+	}
+	if err = req.Write(conn); err != nil {
+		return fmt.Errorf("admin service at %s rejected the client certificate: %w", endpoint, err)
+	}
+	resp, err := http.ReadResponse(bufio.NewReader(conn), req)
+	if err != nil {
+		return fmt.Errorf("admin service at %s rejected the client certificate: %w", endpoint, err)
+	}
+	resp.Body.Close()
+
+	// a connection without a client certificate must be rejected
+	noClientCert := ca.clientTLS.Clone()
+	noClientCert.Certificates = nil
+	conn2, err := tls.DialWithDialer(&net.Dialer{Timeout: 5 * time.Second}, "tcp", endpoint, noClientCert)
+	if err != nil {
+		return nil // rejected during the handshake
+	}
+	defer conn2.Close()
+
+	if err = conn2.SetDeadline(time.Now().Add(5 * time.Second)); err != nil {
+		return err
+	}
+	if err = req.Write(conn2); err == nil {
+		if _, err = http.ReadResponse(bufio.NewReader(conn2), req); err == nil {
+			return fmt.Errorf("admin service at %s accepted a client without a certificate", endpoint)
+		}
+	}
+
+	return nil
+}
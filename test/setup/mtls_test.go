@@ -0,0 +1,60 @@
+package setup
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kwilteam/kwil-db/config"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_TestCA(t *testing.T) {
+	ca, err := newTestCA()
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	require.NoError(t, ca.writeNodeCerts(dir, "node0"))
+
+	// serve with the files the admin service of the node loads
+	serverCert, err := tls.LoadX509KeyPair(filepath.Join(dir, config.AdminServerCertName),
+		filepath.Join(dir, config.AdminServerKeyName))
+	require.NoError(t, err)
+	clientCAs, err := os.ReadFile(filepath.Join(dir, "clients.pem"))
+	require.NoError(t, err)
+	pool := x509.NewCertPool()
+	require.True(t, pool.AppendCertsFromPEM(clientCAs))
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	srv.TLS = &tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    pool,
+	}
+	srv.StartTLS()
+	defer srv.Close()
+
+	get := func(cfg *tls.Config) error {
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: cfg}}
+		resp, err := client.Get(srv.URL)
+		if err != nil {
+			return err
+		}
+		return resp.Body.Close()
+	}
+
+	require.NoError(t, get(ca.clientTLS))
+
+	noClientCert := ca.clientTLS.Clone()
+	noClientCert.Certificates = nil
+	require.Error(t, get(noClientCert))
+
+	// the server certificate is only trusted by clients of the CA
+	untrusted := ca.clientTLS.Clone()
+	untrusted.RootCAs = x509.NewCertPool()
+	require.Error(t, get(untrusted))
+}
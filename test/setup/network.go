@@ -16,6 +16,7 @@ import (
 const (
 	jsonRPCPort = 8484
 	p2pPort     = 6600
+	profilePort = 6060
 
 	kgwRPCPort = 8090
 )
@@ -7,10 +7,13 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"net"
 	"os"
+	"path/filepath"
 	"slices"
 	"strings"
 	"testing"
+	"text/template"
 	"time"
 
 	"github.com/docker/go-connections/nat"
@@ -67,6 +70,23 @@ type TestConfig struct {
 	ServicesPrefix string
 	// PortOffset is the offset to use for the kwild and pg service ports
 	PortOffset int
+	// OPTIONAL: ComposeTemplate is the text/template that generates the
+	// docker compose service of each node, and its Postgres service. It can be
+	// used to add volumes or sidecar containers to the nodes. It is executed
+	// with the same fields as DefaultNodeComposeTemplate, which is used if it
+	// is not set.
+	ComposeTemplate string
+	// OPTIONAL: SaveLogsDir is the directory that logs captured with
+	// Testnet.CaptureLogs are saved to. If not set, it defaults to the
+	// --save-logs-dir flag, and if that is not set, the logs are written
+	// to the test log.
+	SaveLogsDir string
+	// OPTIONAL: EnableMTLS makes the admin service of each node listen on TCP
+	// with mutual TLS, with certificates issued by a CA that is generated for
+	// the network. Setup fails if a node accepts connections without a
+	// client certificate issued by it. The P2P connections between the nodes
+	// are always encrypted and mutually authenticated with their node keys.
+	EnableMTLS bool
 }
 
 func (c *TestConfig) ensureDefaults(t *testing.T) {
@@ -74,6 +94,14 @@ func (c *TestConfig) ensureDefaults(t *testing.T) {
 		c.ContainerStartTimeout = 30 * time.Second
 	}
 
+	if c.SaveLogsDir == "" {
+		c.SaveLogsDir = *saveLogsDir
+	}
+
+	if c.ComposeTemplate == "" {
+		c.ComposeTemplate = DefaultNodeComposeTemplate
+	}
+
 	if c.Network == nil {
 		t.Fatal("Network is required")
 	}
@@ -112,7 +140,7 @@ func (n *NetworkConfig) ensureDefaults(t *testing.T) {
 		n.DBOwner = "0xabc"
 	}
 
-	if n.Nodes == nil {
+	if len(n.Nodes) == 0 {
 		t.Fatal("Nodes is required")
 	}
 }
@@ -132,8 +160,43 @@ type NodeConfig struct {
 	// If not set, a random key will be generated.
 	PrivateKey *crypto.Secp256k1PrivateKey
 
-	// OPTIONAL: Configure is a function that alter's the node's configuration
+	// OPTIONAL: Configure is a function that alter's the node's configuration.
+	// Settings that only affect the node itself, such as the log level,
+	// profiling, and consensus timeouts, can be configured. Settings that
+	// connect the node to the test network, such as listen addresses, boot
+	// nodes, and database credentials, cannot.
 	Configure func(*config.Config)
+
+	// OPTIONAL: Region places the node in a simulated region. Packets between
+	// nodes in different regions are delayed.
+	Region *RegionConfig
+
+	// OPTIONAL: EnableProfiling starts the node's http profiler, and exposes
+	// it on a port assigned by docker. Its endpoint is returned by
+	// KwilNode.ProfilingEndpoint.
+	EnableProfiling bool
+
+	// OPTIONAL: P2PDialTimeout, P2PMaxRetries, and P2PRetryBackoff control
+	// how quickly the node gives up on peers that it cannot connect to, for
+	// tests of unreliable networks. They set the p2p.dial_timeout,
+	// p2p.max_retries, and p2p.retry_backoff configurations before Configure
+	// is called. DefaultNodeConfig sets them to the defaults of kwild. With
+	// P2PMaxRetries of zero, a peer is removed after the first failed
+	// connection.
+	P2PDialTimeout  time.Duration
+	P2PMaxRetries   int
+	P2PRetryBackoff time.Duration
+}
+
+// RegionConfig is the simulated region of a node.
+type RegionConfig struct {
+	// RegionName is the name of the region.
+	RegionName string
+	// InterRegionLatencyMs is the round trip latency, in milliseconds,
+	// between the nodes of the region and the nodes of other regions. If
+	// two regions configure different latencies, the latency between them
+	// is the average.
+	InterRegionLatencyMs int
 }
 
 // DefaultNodeConfig returns a default node configuration
@@ -142,11 +205,15 @@ func DefaultNodeConfig() *NodeConfig {
 	if err != nil {
 		panic(err)
 	}
+	p2p := config.DefaultConfig().P2P
 	return &NodeConfig{
-		DockerImage: "kwild:latest",
-		Validator:   true,
-		PrivateKey:  pk.(*crypto.Secp256k1PrivateKey),
-		Configure:   func(*config.Config) {},
+		DockerImage:     "kwild:latest",
+		Validator:       true,
+		PrivateKey:      pk.(*crypto.Secp256k1PrivateKey),
+		Configure:       func(*config.Config) {},
+		P2PDialTimeout:  time.Duration(p2p.DialTimeout),
+		P2PMaxRetries:   p2p.MaxRetries,
+		P2PRetryBackoff: time.Duration(p2p.RetryBackoff),
 	}
 }
 
@@ -157,6 +224,49 @@ func CustomNodeConfig(f func(*NodeConfig)) *NodeConfig {
 	return cfg
 }
 
+// ValidateValidatorKeys checks that the validator set of a genesis config
+// matches the nodes of a test network: each validator must be the key of a
+// node, the leader must be a validator, and no key may be used twice. Unlike
+// GenesisConfig.SanityChecks, which only checks that the validator set is
+// well formed, it catches networks that would never make a block, e.g.
+// because ConfigureGenesis replaced the leader.
+func ValidateValidatorKeys(genesis *config.GenesisConfig, nodes []*NodeConfig) error {
+	nodeKeys := make(map[string]int, len(nodes))
+	for i, node := range nodes {
+		if node.PrivateKey == nil {
+			return fmt.Errorf("node %d has no private key", i)
+		}
+		key := hex.EncodeToString(node.PrivateKey.Public().Bytes())
+		if j, ok := nodeKeys[key]; ok {
+			return fmt.Errorf("nodes %d and %d have the same key %s", j, i, key)
+		}
+		nodeKeys[key] = i
+	}
+
+	validators := make(map[string]struct{}, len(genesis.Validators))
+	for _, val := range genesis.Validators {
+		key := hex.EncodeToString(val.Identifier)
+		if _, ok := validators[key]; ok {
+			return fmt.Errorf("validator %s is in the genesis validator set twice", key)
+		}
+		validators[key] = struct{}{}
+
+		if _, ok := nodeKeys[key]; !ok {
+			return fmt.Errorf("genesis validator %s is not the key of any node in the network", key)
+		}
+	}
+
+	if genesis.Leader.PublicKey == nil {
+		return errors.New("genesis has no leader")
+	}
+	leader := hex.EncodeToString(genesis.Leader.Bytes())
+	if _, ok := validators[leader]; !ok {
+		return fmt.Errorf("genesis leader %s is not in the validator set", leader)
+	}
+
+	return nil
+}
+
 type ExtraNode struct {
 	ServiceName       string
 	ExposedChainRPC   string
@@ -186,8 +296,38 @@ func (t *Testnet) TestDir() string {
 	return t.testCtx.tmpdir
 }
 
-// ExtraServiceEndpoint gets the endpoint for an extra service that was configured in the testnet
-func (t *Testnet) ExtraServiceEndpoint(ctx context.Context, serviceName string, protocol string, port string) (string, error) {
+// ExtraServiceEndpoints gets the exposed endpoints of an extra service that was
+// configured in the testnet. The endpoints are host:port strings, keyed by the
+// service's "<protocol>/<port>", e.g. "tcp/8090".
+func (t *Testnet) ExtraServiceEndpoints(ctx context.Context, serviceName string) (map[string]string, error) {
+	ct, ok := t.testCtx.containers[serviceName]
+	if !ok {
+		return nil, fmt.Errorf("container not found")
+	}
+
+	host, err := ct.Host(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ports, err := ct.Ports(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoints := make(map[string]string, len(ports))
+	for port, bindings := range ports {
+		if len(bindings) == 0 {
+			continue
+		}
+		endpoints[port.Proto()+"/"+port.Port()] = net.JoinHostPort(host, bindings[0].HostPort)
+	}
+
+	return endpoints, nil
+}
+
+// ExtraServiceEndpointForPort gets the endpoint for a port of an extra service that was configured in the testnet
+func (t *Testnet) ExtraServiceEndpointForPort(ctx context.Context, serviceName string, protocol string, port string) (string, error) {
 	ct, ok := t.testCtx.containers[serviceName]
 	if !ok {
 		return "", fmt.Errorf("container not found")
@@ -240,7 +380,7 @@ func DeployETHNode(t *testing.T, ctx context.Context, dockerName string, privKey
 	ugids, err := getFlagUserGroupID()
 	require.NoError(t, err)
 
-	composePath, _, err := generateCompose(dockerName, tmpDir, nil, services, ugids, "", 0)
+	composePath, _, err := generateCompose(dockerName, tmpDir, nil, nil, services, ugids, "", 0, false)
 	require.NoError(t, err)
 
 	testCtx := &testingContext{
@@ -250,6 +390,8 @@ func DeployETHNode(t *testing.T, ctx context.Context, dockerName string, privKey
 	}
 
 	runDockerCompose(ctx, t, testCtx, composePath, []*ServiceDefinition{{Name: "hardhat"}}, 30*time.Second)
+	// the hardhat service has no message to wait for
+	time.Sleep(3 * time.Second)
 
 	// check if the hardhat service is running
 	ctr, ok := testCtx.containers["hardhat"]
@@ -312,7 +454,13 @@ func SetupTests(t *testing.T, testConfig *TestConfig) *Testnet {
 	ugids, err := getFlagUserGroupID()
 	require.NoError(t, err)
 
-	composePath, nodeInfo, err := generateCompose(dockerNetworkName, tmpDir, testConfig.Network.Nodes, testConfig.Network.ExtraServices, ugids, testConfig.ServicesPrefix, testConfig.PortOffset) //TODO: need user id and groups
+	nodeTmpl, err := template.New("node-compose-template").Parse(testConfig.ComposeTemplate)
+	require.NoError(t, err, "invalid compose template")
+
+	composePath, nodeInfo, err := generateCompose(dockerNetworkName, tmpDir, testConfig.Network.Nodes, nodeTmpl, testConfig.Network.ExtraServices, ugids, testConfig.ServicesPrefix, testConfig.PortOffset, testConfig.EnableMTLS) //TODO: need user id and groups
+	require.NoError(t, err)
+
+	err = checkComposePorts(composePath)
 	require.NoError(t, err)
 
 	require.Equal(t, len(testConfig.Network.Nodes), len(nodeInfo)) // ensure that the number of nodes is the same as the number of node info
@@ -327,6 +475,10 @@ func SetupTests(t *testing.T, testConfig *TestConfig) *Testnet {
 		generatedConfig: nil,
 		networkName:     dockerNetworkName,
 		tmpdir:          tmpDir,
+		startTime:       time.Now(),
+		nodeTmpl:        nodeTmpl,
+		ugids:           ugids,
+		nextNodeNumber:  len(nodeInfo),
 	}
 
 	genesisConfig := config.DefaultGenesisConfig()
@@ -385,6 +537,16 @@ func SetupTests(t *testing.T, testConfig *TestConfig) *Testnet {
 			})
 		}
 
+		// netem, if the node is in a region:
+		if nodeInfo[i].NetemServiceName != "" {
+			if _, ok := serviceFilter[nodeInfo[i].KwilNodeServiceName]; ok || !filterServices {
+				servicesToRun = append(servicesToRun, &ServiceDefinition{
+					Name:    nodeInfo[i].NetemServiceName,
+					WaitMsg: &netemWaitMsg,
+				})
+			}
+		}
+
 		// if i == 0, then it is the first node and will be the leader.
 		// All nodes that are validators, including the leader, will be added to the Validator list
 		if i == 0 {
@@ -416,6 +578,7 @@ func SetupTests(t *testing.T, testConfig *TestConfig) *Testnet {
 
 	generatedConfig.genesisConfig = genesisConfig
 	require.NoError(t, genesisConfig.SanityChecks())
+	require.NoError(t, ValidateValidatorKeys(genesisConfig, testConfig.Network.Nodes))
 
 	// validate the user-provided services
 	for _, svc := range testConfig.Network.ExtraServices {
@@ -423,20 +586,34 @@ func SetupTests(t *testing.T, testConfig *TestConfig) *Testnet {
 		require.Falsef(t, ok, "duplicate service name %s", svc.ServiceName)
 		serviceSet[svc.ServiceName] = struct{}{}
 
+		require.Falsef(t, svc.WaitMsg != "" && svc.WaitStrategy != nil,
+			"service %s sets both WaitMsg and WaitStrategy", svc.ServiceName)
+
 		var waitMsg *string
 		if svc.WaitMsg != "" {
 			waitMsg = &svc.WaitMsg
 		}
 
 		servicesToRun = append(servicesToRun, &ServiceDefinition{
-			Name:    svc.ServiceName,
-			WaitMsg: waitMsg,
+			Name:         svc.ServiceName,
+			WaitMsg:      waitMsg,
+			WaitStrategy: svc.WaitStrategy,
 		})
 	}
 
 	err = setup.GenerateTestnetDir(tmpDir, genesisConfig, testnetNodeConfigs, testConfig.Network.GenesisSnapshot)
 	require.NoError(t, err)
 
+	if testConfig.EnableMTLS {
+		testCtx.mtlsCA, err = newTestCA()
+		require.NoError(t, err)
+
+		for _, info := range nodeInfo {
+			err = testCtx.mtlsCA.writeNodeCerts(filepath.Join(tmpDir, info.KwilNodeServiceName), info.KwilNodeServiceName)
+			require.NoError(t, err)
+		}
+	}
+
 	testCtx.generatedConfig = generatedConfig
 
 	runDockerCompose(ctx, t, testCtx, composePath, servicesToRun, testConfig.ContainerStartTimeout)
@@ -450,6 +627,24 @@ func SetupTests(t *testing.T, testConfig *TestConfig) *Testnet {
 		tp.Nodes = append(tp.Nodes, node)
 	}
 
+	if *junitOutput != "" {
+		// registered before waiting for the nodes, so that the report is
+		// collected if they fail to start
+		t.Cleanup(func() {
+			collectReport(tp.GenerateReport(t))
+		})
+	}
+
+	err = tp.WaitAllNodesBlock(ctx, 1)
+	require.NoError(t, err)
+
+	if testCtx.mtlsCA != nil {
+		for _, info := range nodeInfo {
+			err = testCtx.mtlsCA.verifyAdminMTLS(ctx, testCtx.containers[info.KwilNodeServiceName])
+			require.NoErrorf(t, err, "mutual TLS of %s", info.KwilNodeServiceName)
+		}
+	}
+
 	for _, svc := range testConfig.Network.ExtraServices {
 		// check if that service is running
 		ctr, ok := testCtx.containers[svc.ServiceName]
@@ -484,12 +679,17 @@ func (t *Testnet) NetworkName() string {
 var (
 	kwildWaitMsg    string = "Committed Block"
 	postgresWaitMsg string = `listening on IPv4 address "0.0.0.0", port 5432`
+	netemWaitMsg    string = "netem configured"
 )
 
 // ServiceDefinition is a definition of a service in a docker-compose file
 type ServiceDefinition struct {
 	Name    string
 	WaitMsg *string // if nil, no wait
+	// WaitStrategy is used to wait for the service instead of WaitMsg, for
+	// services that are not ready when a message is logged. See
+	// WaitForHTTPEndpoint and WaitForGRPCHealth.
+	WaitStrategy wait.Strategy
 }
 
 // runDockerCompose runs docker-compose with the given compose file
@@ -502,6 +702,9 @@ func runDockerCompose(ctx context.Context, t *testing.T, testCtx *testingContext
 	ctxUp, cancel := context.WithCancel(ctx)
 
 	t.Cleanup(func() {
+		// write the captured logs before the containers are stopped
+		testCtx.flushLogs(ctx, t)
+
 		if t.Failed() {
 			t.Logf("Stopping but keeping containers for inspection after failed test: %v", dc.Services())
 			time.Sleep(5 * time.Second)
@@ -528,18 +731,15 @@ func runDockerCompose(ctx context.Context, t *testing.T, testCtx *testingContext
 
 	serviceNames := make([]string, len(services))
 	for i, svc := range services {
-		if svc.WaitMsg != nil {
+		if strategy := svc.waitStrategy(startTimeout); strategy != nil {
 			// wait for the service to be ready
-			dc = dc.WaitForService(svc.Name, wait.NewLogStrategy(*svc.WaitMsg).WithStartupTimeout(startTimeout))
+			dc = dc.WaitForService(svc.Name, strategy)
 		}
 		serviceNames[i] = svc.Name
 	}
 
 	err = dc.Up(ctxUp, compose.Wait(true), compose.RunServices(serviceNames...))
 	t.Log("docker-compose up done")
-	// wait as some protection against RPC errors with chain_info.
-	// This was in the old tests, so I retain it here.
-	time.Sleep(3 * time.Second)
 	require.NoError(t, err)
 
 	for _, svc := range services {
@@ -561,19 +761,30 @@ func (c *NodeConfig) makeNode(generated *generatedNodeInfo, isFirstNode bool, fi
 	// through configure function depending on the test
 	conf.Consensus.EmptyBlockTimeout = types.Duration(1 * time.Second)
 
+	conf.P2P.DialTimeout = types.Duration(c.P2PDialTimeout)
+	conf.P2P.MaxRetries = c.P2PMaxRetries
+	conf.P2P.RetryBackoff = types.Duration(c.P2PRetryBackoff)
+
 	c.Configure(conf)
 
 	// there are some configurations that the user cannot set, as they will screw up the test.
-	// These are:
+	// These are the network addresses and database credentials:
 	// --admin.listen
 	// --rpc.listen
 	// --p2p.listen
+	// --p2p.bootnodes
 	// --db.host
 	// --db.port
 	// --db.user
 	// --db.password
 	// --db.name
-	// --p2p.bootnodes
+	// --profile_listen (set with EnableProfiling)
+	// Other configurations are safe to set, most commonly:
+	// --log.level
+	// --profile_mode and --profile_file
+	// --consensus.* timeouts
+	// --p2p.dial_timeout, --p2p.max_retries, and --p2p.retry_backoff (set
+	// with the P2P fields of NodeConfig)
 	ensureEq := func(name string, a, b interface{}) error {
 		if a != b {
 			return fmt.Errorf("configuration %s cannot be custom configured in tests", name)
@@ -584,19 +795,26 @@ func (c *NodeConfig) makeNode(generated *generatedNodeInfo, isFirstNode bool, fi
 		ensureEq("admin.listen", conf.Admin.ListenAddress, defaultConf.Admin.ListenAddress),
 		ensureEq("rpc.listen", conf.RPC.ListenAddress, defaultConf.RPC.ListenAddress),
 		ensureEq("p2p.listen", conf.P2P.ListenAddress, defaultConf.P2P.ListenAddress),
+		ensureEq("p2p.bootnodes", len(conf.P2P.BootNodes), len(defaultConf.P2P.BootNodes)), // []string is not comparable, but it should be empty anyways
 		ensureEq("db.host", conf.DB.Host, defaultConf.DB.Host),
 		ensureEq("db.port", conf.DB.Port, defaultConf.DB.Port),
 		ensureEq("db.user", conf.DB.User, defaultConf.DB.User),
 		ensureEq("db.password", conf.DB.Pass, defaultConf.DB.Pass),
 		ensureEq("db.name", conf.DB.DBName, defaultConf.DB.DBName),
-		ensureEq("p2p.bootnodes", len(conf.P2P.BootNodes), len(defaultConf.P2P.BootNodes)), // []string is not comparable, but it should be empty anyways
+		ensureEq("profile_listen", conf.ProfileListen, defaultConf.ProfileListen),
 	)
 	if err != nil {
 		return nil, err
 	}
 
+	if c.EnableProfiling {
+		// the profiler must listen on all interfaces for docker to expose it
+		conf.ProfileMode = "http"
+		conf.ProfileListen = fmt.Sprintf("0.0.0.0:%d", profilePort)
+	}
+
 	// these configurations set here will be combined with the configs hard-coded
-	// in node-compose.yml.template. There, we hardcore things like Postgres connection
+	// in the node compose template. There, we hardcore things like Postgres connection
 	// info, rpc endpoints (which don't concern us since the container maps ports to the host),
 	// and other things that are not relevant to the test.
 
@@ -640,6 +858,9 @@ func NewServiceDefinition(name string, waitMsg string) *ServiceDefinition {
 
 func (tt *Testnet) RunServices(t *testing.T, ctx context.Context, services []*ServiceDefinition, startTimeout time.Duration) {
 	runDockerCompose(ctx, t, tt.testCtx, tt.testCtx.composePath, services, startTimeout)
+	// wait as some protection against RPC errors with chain_info.
+	// This was in the old tests, so I retain it here.
+	time.Sleep(3 * time.Second)
 }
 
 type generatedNodeConfig struct {
@@ -654,6 +875,14 @@ type testingContext struct {
 	generatedConfig *generatedNodeConfig
 	networkName     string
 	tmpdir          string
+	logCaptures     []*logCapture
+	startTime       time.Time
+	// used to add nodes with Testnet.AddNode
+	nodeTmpl       *template.Template
+	ugids          *[2]string
+	nextNodeNumber int
+	// mtlsCA issues the admin service certificates if EnableMTLS is set
+	mtlsCA *testCA
 }
 type kwilNode struct {
 	config         *config.Config
@@ -685,6 +914,22 @@ func (k *kwilNode) Config() *config.Config {
 	return k.config
 }
 
+// newJSONRPCClient creates a client for the node. Unlike JSONRPCClient, it
+// does not need the test, and discards the client's logs.
+func (k *kwilNode) newJSONRPCClient(ctx context.Context) (JSONRPCClient, error) {
+	container, ok := k.testCtx.containers[k.generatedInfo.KwilNodeServiceName]
+	if !ok {
+		return nil, fmt.Errorf("container %s not found", k.generatedInfo.KwilNodeServiceName)
+	}
+
+	endpoint, _, err := kwildJSONRPCEndpoints(container, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return getNewClientFn(k.testCtx.config.ClientDriver)(ctx, endpoint, func(string, ...any) {}, k.testCtx, nil)
+}
+
 func (k *kwilNode) JSONRPCClient(t *testing.T, ctx context.Context, opts *ClientOptions) JSONRPCClient {
 	container, ok := k.testCtx.containers[k.generatedInfo.KwilNodeServiceName]
 	if !ok {
@@ -710,9 +955,13 @@ func (k *kwilNode) AdminClient(t *testing.T, ctx context.Context) *AdminClient {
 		t.Fatalf("container %s not found", k.generatedInfo.KwilNodeServiceName)
 	}
 
-	return &AdminClient{
+	client := &AdminClient{
 		container: container,
 	}
+	if k.testCtx.mtlsCA != nil {
+		client.rpcArgs = adminRPCArgs()
+	}
+	return client
 }
 
 func (k *kwilNode) JSONRPCEndpoint(t *testing.T, ctx context.Context) (string, string, error) {
@@ -724,6 +973,27 @@ func (k *kwilNode) JSONRPCEndpoint(t *testing.T, ctx context.Context) (string, s
 	return kwildJSONRPCEndpoints(container, ctx)
 }
 
+// WaitForBlock waits until the node has committed the block at the height.
+func (k *kwilNode) WaitForBlock(ctx context.Context, t *testing.T, height int64) error {
+	return waitForBlock(ctx, k.JSONRPCClient(t, ctx, nil), height)
+}
+
+// ProfilingEndpoint returns the exposed endpoint of the node's http profiler.
+// The node must have been configured with EnableProfiling.
+func (k *kwilNode) ProfilingEndpoint(ctx context.Context) (string, error) {
+	if !k.nodeTestConfig.EnableProfiling {
+		return "", errors.New("profiling is not enabled for the node")
+	}
+
+	container, ok := k.testCtx.containers[k.generatedInfo.KwilNodeServiceName]
+	if !ok {
+		return "", fmt.Errorf("container %s not found", k.generatedInfo.KwilNodeServiceName)
+	}
+
+	exposed, _, err := getEndpoints(container, ctx, nat.Port(fmt.Sprint(profilePort)), "http")
+	return exposed, err
+}
+
 // PeerID returns the peer ID of the node.
 // This is of the format <hexPubKey#keyType>
 func (k *kwilNode) PeerID() string {
@@ -750,4 +1020,14 @@ type KwilNode interface {
 	AdminClient(t *testing.T, ctx context.Context) *AdminClient
 	PeerID() string
 	PostgresEndpoint(t *testing.T, ctx context.Context, name string) (exposed string, unexposed string, err error)
+	WaitForBlock(ctx context.Context, t *testing.T, height int64) error
+	ProfilingEndpoint(ctx context.Context) (string, error)
+
+	// The assertions fail the test with a descriptive message if the state
+	// of the chain is not as expected. They use the node's JSON-RPC client.
+
+	AssertBlockHeight(ctx context.Context, t *testing.T, minHeight int64)
+	AssertNamespaceExists(ctx context.Context, t *testing.T, namespace string)
+	AssertActionResult(ctx context.Context, t *testing.T, namespace, action string, args []any, expected []map[string]any)
+	AssertTransactionCommitted(ctx context.Context, t *testing.T, txHash []byte)
 }
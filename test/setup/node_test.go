@@ -0,0 +1,87 @@
+package setup
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kwilteam/kwil-db/config"
+	"github.com/kwilteam/kwil-db/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_MakeNodeP2PRetries(t *testing.T) {
+	info := &generatedNodeInfo{KwilNodeServiceName: "node0"}
+
+	// the defaults of kwild are kept
+	node, err := DefaultNodeConfig().makeNode(info, true, nil)
+	require.NoError(t, err)
+	require.Equal(t, config.DefaultConfig().P2P, node.config.P2P)
+
+	// a node that gives up on a peer after the first failed connection
+	node, err = CustomNodeConfig(func(nc *NodeConfig) {
+		nc.P2PDialTimeout = time.Second
+		nc.P2PMaxRetries = 0
+		nc.P2PRetryBackoff = 100 * time.Millisecond
+	}).makeNode(info, true, nil)
+	require.NoError(t, err)
+	require.Equal(t, types.Duration(time.Second), node.config.P2P.DialTimeout)
+	require.Equal(t, 0, node.config.P2P.MaxRetries)
+	require.Equal(t, types.Duration(100*time.Millisecond), node.config.P2P.RetryBackoff)
+
+	// Configure can still override them
+	node, err = CustomNodeConfig(func(nc *NodeConfig) {
+		nc.P2PMaxRetries = 0
+		nc.Configure = func(c *config.Config) {
+			c.P2P.MaxRetries = 3
+		}
+	}).makeNode(info, true, nil)
+	require.NoError(t, err)
+	require.Equal(t, 3, node.config.P2P.MaxRetries)
+}
+
+func Test_ValidateValidatorKeys(t *testing.T) {
+	nodes := []*NodeConfig{DefaultNodeConfig(), DefaultNodeConfig(), CustomNodeConfig(func(nc *NodeConfig) {
+		nc.Validator = false
+	})}
+
+	validator := func(nc *NodeConfig) *types.Validator {
+		return &types.Validator{
+			AccountID: types.AccountID{
+				Identifier: nc.PrivateKey.Public().Bytes(),
+				KeyType:    nc.PrivateKey.Type(),
+			},
+			Power: 1,
+		}
+	}
+	genesis := func() *config.GenesisConfig {
+		g := config.DefaultGenesisConfig()
+		g.Leader = types.PublicKey{PublicKey: nodes[0].PrivateKey.Public()}
+		g.Validators = []*types.Validator{validator(nodes[0]), validator(nodes[1])}
+		return g
+	}
+
+	require.NoError(t, ValidateValidatorKeys(genesis(), nodes))
+
+	// the leader is not a validator
+	g := genesis()
+	g.Leader = types.PublicKey{PublicKey: nodes[2].PrivateKey.Public()}
+	err := ValidateValidatorKeys(g, nodes)
+	require.ErrorContains(t, err, "genesis leader")
+	require.ErrorContains(t, err, "is not in the validator set")
+
+	// a validator is not a node of the network
+	g = genesis()
+	g.Validators = append(g.Validators, validator(DefaultNodeConfig()))
+	require.ErrorContains(t, ValidateValidatorKeys(g, nodes), "is not the key of any node")
+
+	// a validator is in the set twice
+	g = genesis()
+	g.Validators = append(g.Validators, validator(nodes[1]))
+	require.ErrorContains(t, ValidateValidatorKeys(g, nodes), "twice")
+
+	// two nodes have the same key
+	dup := CustomNodeConfig(func(nc *NodeConfig) {
+		nc.PrivateKey = nodes[1].PrivateKey
+	})
+	require.ErrorContains(t, ValidateValidatorKeys(genesis(), append(nodes, dup)), "have the same key")
+}
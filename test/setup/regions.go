@@ -0,0 +1,72 @@
+package setup
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"time"
+
+	tcexec "github.com/testcontainers/testcontainers-go/exec"
+)
+
+// pingAvgRegexp matches the average round trip time in the summary of ping,
+// e.g. "rtt min/avg/max/mdev = 100.1/100.5/101.2/0.4 ms".
+var pingAvgRegexp = regexp.MustCompile(`= [\d.]+/([\d.]+)/`)
+
+// MeasureInterRegionLatency pings each node from each node in another region,
+// and returns the average round trip times keyed by "<from>-><to>", where
+// from and to are the nodes' service names. Only nodes with a RegionConfig
+// are measured.
+func (t *Testnet) MeasureInterRegionLatency(ctx context.Context) (map[string]time.Duration, error) {
+	latencies := make(map[string]time.Duration)
+	for _, from := range t.Nodes {
+		fromNode, ok := from.(*kwilNode)
+		if !ok {
+			return nil, fmt.Errorf("unexpected node type %T", from)
+		}
+		if fromNode.generatedInfo.NetemServiceName == "" {
+			continue
+		}
+
+		// the netem container shares the network of the node, and has ping
+		ct, ok := t.testCtx.containers[fromNode.generatedInfo.NetemServiceName]
+		if !ok {
+			return nil, fmt.Errorf("container %s not found", fromNode.generatedInfo.NetemServiceName)
+		}
+
+		for _, to := range t.Nodes {
+			toNode := to.(*kwilNode)
+			if toNode.nodeTestConfig.Region == nil || toNode.nodeTestConfig.Region.RegionName == fromNode.nodeTestConfig.Region.RegionName {
+				continue
+			}
+
+			fromName, toName := fromNode.generatedInfo.KwilNodeServiceName, toNode.generatedInfo.KwilNodeServiceName
+			code, out, err := ct.Exec(ctx, []string{"ping", "-c", "5", "-i", "0.2", "-q", toName}, tcexec.Multiplexed())
+			if err != nil {
+				return nil, fmt.Errorf("failed to ping %s from %s: %w", toName, fromName, err)
+			}
+			output, err := io.ReadAll(out)
+			if err != nil {
+				return nil, err
+			}
+			if code != 0 {
+				return nil, fmt.Errorf("failed to ping %s from %s: %s", toName, fromName, output)
+			}
+
+			match := pingAvgRegexp.FindSubmatch(output)
+			if match == nil {
+				return nil, fmt.Errorf("unexpected ping output: %s", output)
+			}
+			avg, err := strconv.ParseFloat(string(match[1]), 64)
+			if err != nil {
+				return nil, err
+			}
+
+			latencies[fromName+"->"+toName] = time.Duration(avg * float64(time.Millisecond))
+		}
+	}
+
+	return latencies, nil
+}
@@ -0,0 +1,304 @@
+package setup
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+var junitOutput = flag.String("junit-output", "", "path to write a JUnit XML report of the test networks to, see WriteJUnitOutput")
+
+// reportLogLines is the number of last log lines of each node in a report.
+const reportLogLines = 200
+
+// TestReport is the state of a test network at the end of a test.
+type TestReport struct {
+	// TestName is the name of the test that ran the network.
+	TestName string
+	// Failed is true if the test failed.
+	Failed bool
+	// Duration is the time since the network was set up.
+	Duration time.Duration
+	// Nodes are the reports of the nodes of the network.
+	Nodes []*NodeReport
+}
+
+// NodeReport is the state of a node at the end of a test.
+type NodeReport struct {
+	// Name is the service name of the node.
+	Name string
+	// BlockHeight is the height of the last committed block.
+	BlockHeight int64
+	// Validator is true if the node is in the validator set.
+	Validator bool
+	// Power is the power of the node, if it is a validator.
+	Power int64
+	// Logs are the last lines of the node's logs.
+	Logs string
+	// Errors are the errors that occurred while collecting the report.
+	Errors []string
+}
+
+// GenerateReport collects the state of the network's nodes. Nodes that are
+// not running, or fail to respond, are reported with the errors that
+// occurred.
+func (tt *Testnet) GenerateReport(t *testing.T) *TestReport {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	report := &TestReport{
+		TestName: t.Name(),
+		Failed:   t.Failed(),
+		Duration: time.Since(tt.testCtx.startTime),
+	}
+
+	// the validator set is the same on all nodes, so it is read from the
+	// first one that responds
+	var validators map[string]int64
+	var pubKeys []string
+
+	for _, n := range tt.Nodes {
+		node, ok := n.(*kwilNode)
+		if !ok {
+			continue
+		}
+
+		nr := &NodeReport{Name: node.generatedInfo.KwilNodeServiceName}
+		report.Nodes = append(report.Nodes, nr)
+		pubKeys = append(pubKeys, string(node.PublicKey().Bytes()))
+
+		ct, ok := tt.testCtx.containers[nr.Name]
+		if !ok {
+			nr.Errors = append(nr.Errors, "node is not running")
+			continue
+		}
+
+		client, err := node.newJSONRPCClient(ctx)
+		if err == nil {
+			info, err := client.ChainInfo(ctx)
+			if err == nil {
+				nr.BlockHeight = int64(info.BlockHeight)
+			} else {
+				nr.Errors = append(nr.Errors, fmt.Sprintf("chain info: %v", err))
+			}
+		} else {
+			nr.Errors = append(nr.Errors, fmt.Sprintf("client: %v", err))
+		}
+
+		if validators == nil {
+			vals, err := (&AdminClient{container: ct}).ValidatorsList(ctx)
+			if err == nil {
+				validators = make(map[string]int64, len(vals))
+				for _, v := range vals {
+					validators[string(v.Identifier)] = v.Power
+				}
+			} else {
+				nr.Errors = append(nr.Errors, fmt.Sprintf("validators: %v", err))
+			}
+		}
+
+		logs, err := lastLogLines(ctx, ct.Logs, reportLogLines)
+		if err == nil {
+			nr.Logs = logs
+		} else {
+			nr.Errors = append(nr.Errors, fmt.Sprintf("logs: %v", err))
+		}
+	}
+
+	for i, nr := range report.Nodes {
+		nr.Power, nr.Validator = validators[pubKeys[i]]
+	}
+
+	return report
+}
+
+// lastLogLines returns the last lines of a container's logs.
+func lastLogLines(ctx context.Context, logs func(context.Context) (io.ReadCloser, error), n int) (string, error) {
+	rc, err := logs(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+
+	bts, err := io.ReadAll(rc)
+	if err != nil {
+		return "", err
+	}
+
+	lines := strings.Split(strings.TrimRight(string(bts), "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name       string          `xml:"name,attr"`
+	Tests      int             `xml:"tests,attr"`
+	Failures   int             `xml:"failures,attr"`
+	Time       string          `xml:"time,attr"`
+	Properties []junitProperty `xml:"properties>property,omitempty"`
+	TestCases  []junitTestCase `xml:"testcase"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message  string `xml:"message,attr"`
+	Contents string `xml:",chardata"`
+}
+
+// junitSuite converts the report to a JUnit test suite, with a test case for
+// each node.
+func (r *TestReport) junitSuite() junitTestSuite {
+	seconds := fmt.Sprintf("%.3f", r.Duration.Seconds())
+	suite := junitTestSuite{
+		Name: r.TestName,
+		Time: seconds,
+	}
+
+	for _, n := range r.Nodes {
+		suite.Properties = append(suite.Properties,
+			junitProperty{Name: n.Name + ".block_height", Value: fmt.Sprint(n.BlockHeight)},
+			junitProperty{Name: n.Name + ".validator", Value: fmt.Sprint(n.Validator)},
+			junitProperty{Name: n.Name + ".power", Value: fmt.Sprint(n.Power)},
+		)
+
+		tc := junitTestCase{
+			Name:      n.Name,
+			Classname: r.TestName,
+			Time:      seconds,
+			SystemOut: n.Logs,
+		}
+		switch {
+		case len(n.Errors) > 0:
+			tc.Failure = &junitFailure{Message: "node did not respond", Contents: strings.Join(n.Errors, "\n")}
+		case r.Failed:
+			tc.Failure = &junitFailure{Message: "test failed"}
+		}
+		if tc.Failure != nil {
+			suite.Failures++
+		}
+
+		suite.Tests++
+		suite.TestCases = append(suite.TestCases, tc)
+	}
+
+	return suite
+}
+
+// WriteJUnit writes the report as JUnit XML.
+func (r *TestReport) WriteJUnit(w io.Writer) error {
+	return writeJUnit(w, []*TestReport{r})
+}
+
+func writeJUnit(w io.Writer, reports []*TestReport) error {
+	suites := junitTestSuites{}
+	for _, r := range reports {
+		suites.Suites = append(suites.Suites, r.junitSuite())
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(suites); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// WriteMarkdown writes the report as Markdown.
+func (r *TestReport) WriteMarkdown(w io.Writer) error {
+	var buf bytes.Buffer
+
+	status := "PASSED"
+	if r.Failed {
+		status = "FAILED"
+	}
+	fmt.Fprintf(&buf, "## %s\n\n", r.TestName)
+	fmt.Fprintf(&buf, "Status: %s, duration: %s\n\n", status, r.Duration.Round(time.Millisecond))
+
+	buf.WriteString("| Node | Block height | Validator | Power | Errors |\n")
+	buf.WriteString("| --- | --- | --- | --- | --- |\n")
+	for _, n := range r.Nodes {
+		fmt.Fprintf(&buf, "| %s | %d | %t | %d | %s |\n", n.Name, n.BlockHeight, n.Validator, n.Power, strings.Join(n.Errors, "; "))
+	}
+
+	for _, n := range r.Nodes {
+		if n.Logs == "" {
+			continue
+		}
+		fmt.Fprintf(&buf, "\n<details><summary>%s logs</summary>\n\n```\n%s\n```\n\n</details>\n", n.Name, n.Logs)
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// reports are the reports collected for the --junit-output flag.
+var reports struct {
+	mu      sync.Mutex
+	reports []*TestReport
+}
+
+// collectReport adds a report to those written by WriteJUnitOutput.
+func collectReport(r *TestReport) {
+	reports.mu.Lock()
+	defer reports.mu.Unlock()
+	reports.reports = append(reports.reports, r)
+}
+
+// WriteJUnitOutput writes the reports of the test networks set up by the
+// tests to the path of the --junit-output flag. It does nothing if the flag
+// is not set. It should be called by TestMain after the tests ran:
+//
+//	func TestMain(m *testing.M) {
+//		code := m.Run()
+//		if err := setup.WriteJUnitOutput(); err != nil {
+//			fmt.Fprintln(os.Stderr, err)
+//		}
+//		os.Exit(code)
+//	}
+func WriteJUnitOutput() error {
+	if *junitOutput == "" {
+		return nil
+	}
+
+	reports.mu.Lock()
+	defer reports.mu.Unlock()
+
+	f, err := os.Create(*junitOutput)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return writeJUnit(f, reports.reports)
+}
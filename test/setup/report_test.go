@@ -0,0 +1,73 @@
+package setup_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/kwilteam/kwil-db/test/setup"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_TestReport(t *testing.T) {
+	report := &setup.TestReport{
+		TestName: "Test_Network",
+		Failed:   true,
+		Duration: 12500 * time.Millisecond,
+		Nodes: []*setup.NodeReport{
+			{Name: "node0", BlockHeight: 10, Validator: true, Power: 1, Logs: "Committed Block <height=10>"},
+			{Name: "node1", Errors: []string{"node is not running"}},
+		},
+	}
+
+	var buf bytes.Buffer
+	err := report.WriteJUnit(&buf)
+	require.NoError(t, err)
+
+	// parse the report with the JUnit schema
+	var suites struct {
+		XMLName xml.Name `xml:"testsuites"`
+		Suites  []struct {
+			Name       string  `xml:"name,attr"`
+			Tests      int     `xml:"tests,attr"`
+			Failures   int     `xml:"failures,attr"`
+			Time       float64 `xml:"time,attr"`
+			Properties []struct {
+				Name  string `xml:"name,attr"`
+				Value string `xml:"value,attr"`
+			} `xml:"properties>property"`
+			TestCases []struct {
+				Name      string  `xml:"name,attr"`
+				Classname string  `xml:"classname,attr"`
+				Time      float64 `xml:"time,attr"`
+				Failure   *struct {
+					Message string `xml:"message,attr"`
+					Text    string `xml:",chardata"`
+				} `xml:"failure"`
+				SystemOut string `xml:"system-out"`
+			} `xml:"testcase"`
+		} `xml:"testsuite"`
+	}
+	err = xml.Unmarshal(buf.Bytes(), &suites)
+	require.NoError(t, err)
+
+	require.Len(t, suites.Suites, 1)
+	suite := suites.Suites[0]
+	require.Equal(t, "Test_Network", suite.Name)
+	require.Equal(t, 2, suite.Tests)
+	require.Equal(t, 2, suite.Failures)
+	require.Equal(t, 12.5, suite.Time)
+	require.Len(t, suite.Properties, 6)
+
+	require.Len(t, suite.TestCases, 2)
+	require.Equal(t, "node0", suite.TestCases[0].Name)
+	require.Equal(t, "Committed Block <height=10>", suite.TestCases[0].SystemOut)
+	require.Equal(t, "test failed", suite.TestCases[0].Failure.Message)
+	require.Equal(t, "node is not running", suite.TestCases[1].Failure.Text)
+
+	buf.Reset()
+	err = report.WriteMarkdown(&buf)
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), "| node0 | 10 | true | 1 |  |")
+}
@@ -0,0 +1,100 @@
+package setup
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kwilteam/kwil-db/config"
+	tcexec "github.com/testcontainers/testcontainers-go/exec"
+)
+
+// Reset resets the chain and the application state of all running nodes, as
+// `kwild setup reset --all` does, so that a test can start from the genesis
+// state without setting up a new network. Since kwild cannot reset a running
+// node, the kwild containers are stopped while their data is deleted, and
+// started again. The Postgres containers keep running, and their database is
+// recreated. Reset returns once all nodes have committed the first block.
+func (tt *Testnet) Reset(ctx context.Context, t *testing.T) error {
+	var nodes []*kwilNode
+	for _, n := range tt.Nodes {
+		node, ok := n.(*kwilNode)
+		if !ok {
+			return fmt.Errorf("unexpected node type %T", n)
+		}
+		if _, running := tt.testCtx.containers[node.generatedInfo.KwilNodeServiceName]; running {
+			nodes = append(nodes, node)
+		}
+	}
+
+	// all nodes are stopped before any is reset, so that no node syncs
+	// blocks from a node that was not yet reset
+	stopTimeout := 10 * time.Second
+	for _, node := range nodes {
+		t.Logf("stopping %s to reset it", node.generatedInfo.KwilNodeServiceName)
+		err := tt.testCtx.containers[node.generatedInfo.KwilNodeServiceName].Stop(ctx, &stopTimeout)
+		if err != nil {
+			return fmt.Errorf("failed to stop %s: %w", node.generatedInfo.KwilNodeServiceName, err)
+		}
+	}
+
+	for _, node := range nodes {
+		if err := tt.resetNode(ctx, node); err != nil {
+			return fmt.Errorf("failed to reset %s: %w", node.generatedInfo.KwilNodeServiceName, err)
+		}
+	}
+
+	for _, node := range nodes {
+		err := tt.testCtx.containers[node.generatedInfo.KwilNodeServiceName].Start(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to start %s: %w", node.generatedInfo.KwilNodeServiceName, err)
+		}
+	}
+
+	return tt.WaitAllNodesBlock(ctx, 1)
+}
+
+// resetNode deletes the chain data of a stopped node, and recreates its
+// database.
+func (tt *Testnet) resetNode(ctx context.Context, node *kwilNode) error {
+	// the root directory of the node is mounted from the testnet directory
+	rootDir := filepath.Join(tt.testCtx.tmpdir, node.generatedInfo.KwilNodeServiceName)
+	for _, dir := range []string{
+		config.BlockstoreDir(rootDir),
+		config.ReceivedSnapshotsDir(rootDir),
+		config.LocalSnapshotsDir(rootDir),
+		config.MigrationDir(rootDir),
+		config.GenesisStateFileName(rootDir),
+		config.LeaderUpdatesFilePath(rootDir),
+	} {
+		if err := os.RemoveAll(dir); err != nil {
+			return err
+		}
+	}
+
+	pg, ok := tt.testCtx.containers[node.generatedInfo.PostgresServiceName]
+	if !ok {
+		return fmt.Errorf("container %s not found", node.generatedInfo.PostgresServiceName)
+	}
+
+	dbName, dbUser := node.config.DB.DBName, node.config.DB.User
+	for _, stmt := range []string{
+		fmt.Sprintf("DROP DATABASE IF EXISTS %s WITH (FORCE)", dbName),
+		fmt.Sprintf("CREATE DATABASE %s OWNER %s", dbName, dbUser),
+	} {
+		code, out, err := pg.Exec(ctx, []string{"psql", "-U", "postgres", "-c", stmt}, tcexec.Multiplexed())
+		if err != nil {
+			return err
+		}
+		if code != 0 {
+			output, _ := io.ReadAll(out)
+			return fmt.Errorf("%s: %s", stmt, output)
+		}
+	}
+
+	return nil
+}
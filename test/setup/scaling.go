@@ -0,0 +1,121 @@
+package setup
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/kwilteam/kwil-db/app/setup"
+)
+
+// AddNode adds a node, with its Postgres service, to the running network. The
+// node uses the genesis of the network, and the first node as its boot node.
+// Since it is not in the genesis validator set, it starts as a non-validator,
+// and can join the validator set with its admin client. AddNode returns once
+// the node has started.
+func (tt *Testnet) AddNode(ctx context.Context, t *testing.T, cfg *NodeConfig) (KwilNode, error) {
+	if len(tt.Nodes) == 0 {
+		return nil, fmt.Errorf("testnet has no nodes")
+	}
+	firstNode, ok := tt.Nodes[0].(*kwilNode)
+	if !ok {
+		return nil, fmt.Errorf("unexpected node type %T", tt.Nodes[0])
+	}
+
+	testCtx := tt.testCtx
+	number := testCtx.nextNodeNumber
+	testCtx.nextNodeNumber++
+
+	tmplData := newNodeTemplate(testCtx.networkName, testCtx.tmpdir, cfg, number, testCtx.ugids,
+		testCtx.config.ServicesPrefix, testCtx.config.PortOffset, testCtx.mtlsCA != nil)
+	info := tmplData.info()
+
+	node, err := cfg.makeNode(info, false, firstNode)
+	if err != nil {
+		return nil, err
+	}
+	node.testCtx = testCtx
+
+	// the node is added with its own compose file, so that the services of
+	// the network are not changed
+	var res bytes.Buffer
+	if err = headerComposeTemplate.Execute(&res, &headerTemplate{Network: testCtx.networkName}); err != nil {
+		return nil, err
+	}
+	nodeYml, err := tmplData.generate(testCtx.nodeTmpl)
+	if err != nil {
+		return nil, err
+	}
+	res.WriteString(nodeYml)
+
+	composePath := filepath.Join(testCtx.tmpdir, "docker-compose-"+info.KwilNodeServiceName+".yml")
+	if err = os.WriteFile(composePath, res.Bytes(), 0644); err != nil {
+		return nil, err
+	}
+	if err = checkComposePorts(composePath); err != nil {
+		return nil, err
+	}
+
+	err = setup.GenerateNodeDir(filepath.Join(testCtx.tmpdir, info.KwilNodeServiceName), testCtx.generatedConfig.genesisConfig,
+		node.config, cfg.PrivateKey, testCtx.config.Network.GenesisSnapshot)
+	if err != nil {
+		return nil, err
+	}
+	testCtx.generatedConfig.nodeConfigs[info.KwilNodeServiceName] = node.config
+
+	if testCtx.mtlsCA != nil {
+		if err = testCtx.mtlsCA.writeNodeCerts(filepath.Join(testCtx.tmpdir, info.KwilNodeServiceName), info.KwilNodeServiceName); err != nil {
+			return nil, err
+		}
+	}
+
+	runDockerCompose(ctx, t, testCtx, composePath, []*ServiceDefinition{
+		PostgresServiceDefinition(info.PostgresServiceName),
+		KwildServiceDefinition(info.KwilNodeServiceName),
+	}, testCtx.config.ContainerStartTimeout)
+
+	if testCtx.mtlsCA != nil {
+		if err = testCtx.mtlsCA.verifyAdminMTLS(ctx, testCtx.containers[info.KwilNodeServiceName]); err != nil {
+			return nil, err
+		}
+	}
+
+	tt.Nodes = append(tt.Nodes, node)
+	return node, nil
+}
+
+// RemoveNode stops and removes the containers of the node at the index of
+// Nodes, and removes it from Nodes.
+func (tt *Testnet) RemoveNode(ctx context.Context, t *testing.T, index int) error {
+	if index < 0 || index >= len(tt.Nodes) {
+		return fmt.Errorf("node index %d out of range", index)
+	}
+	node, ok := tt.Nodes[index].(*kwilNode)
+	if !ok {
+		return fmt.Errorf("unexpected node type %T", tt.Nodes[index])
+	}
+
+	for _, svc := range []string{
+		node.generatedInfo.KwilNodeServiceName,
+		node.generatedInfo.NetemServiceName,
+		node.generatedInfo.PostgresServiceName,
+	} {
+		ct, ok := tt.testCtx.containers[svc]
+		if !ok {
+			continue // not running
+		}
+
+		t.Logf("removing %s", svc)
+		if err := ct.Terminate(ctx); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", svc, err)
+		}
+		delete(tt.testCtx.containers, svc)
+	}
+
+	tt.Nodes = slices.Delete(tt.Nodes, index, index+1)
+	return nil
+}
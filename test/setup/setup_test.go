@@ -2,9 +2,18 @@ package setup_test
 
 import (
 	"context"
+	"crypto/rand"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/kwilteam/kwil-db/config"
+	ctypes "github.com/kwilteam/kwil-db/core/client/types"
+	"github.com/kwilteam/kwil-db/core/crypto"
+	"github.com/kwilteam/kwil-db/core/crypto/auth"
+	authExt "github.com/kwilteam/kwil-db/extensions/auth"
 	"github.com/kwilteam/kwil-db/test/setup"
 	"github.com/stretchr/testify/require"
 )
@@ -35,3 +44,179 @@ func Test_Setup(t *testing.T) {
 
 	require.Equal(t, "pong", ping)
 }
+
+func Test_CaptureLogs(t *testing.T) {
+	dir := t.TempDir()
+	p := setup.SetupTests(t, &setup.TestConfig{
+		ClientDriver: setup.Go,
+		Network: &setup.NetworkConfig{
+			Nodes: []*setup.NodeConfig{
+				setup.DefaultNodeConfig(),
+			},
+		},
+		SaveLogsDir: dir,
+	})
+
+	ctx := context.Background()
+	p.CaptureLogs(ctx, t)
+
+	// the node logs each block it commits
+	require.Eventually(t, func() bool {
+		files, err := os.ReadDir(dir)
+		if err != nil || len(files) != 1 {
+			return false
+		}
+
+		logs, err := os.ReadFile(filepath.Join(dir, files[0].Name()))
+		return err == nil && strings.Contains(string(logs), "Committed Block")
+	}, 30*time.Second, time.Second)
+}
+
+func Test_WaitAllNodesBlock(t *testing.T) {
+	p := setup.SetupTests(t, &setup.TestConfig{
+		ClientDriver: setup.Go,
+		Network: &setup.NetworkConfig{
+			Nodes: []*setup.NodeConfig{
+				setup.DefaultNodeConfig(),
+				setup.DefaultNodeConfig(),
+			},
+		},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	err := p.WaitAllNodesBlock(ctx, 3)
+	require.NoError(t, err)
+
+	err = p.Nodes[1].WaitForBlock(ctx, t, 4)
+	require.NoError(t, err)
+}
+
+func Test_MTLS(t *testing.T) {
+	// SetupTests fails if the admin services do not require mutual TLS
+	p := setup.SetupTests(t, &setup.TestConfig{
+		ClientDriver: setup.Go,
+		Network: &setup.NetworkConfig{
+			Nodes: []*setup.NodeConfig{
+				setup.DefaultNodeConfig(),
+				setup.DefaultNodeConfig(),
+			},
+		},
+		EnableMTLS: true,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// the nodes still reach consensus
+	err := p.WaitAllNodesBlock(ctx, 3)
+	require.NoError(t, err)
+
+	// and the admin commands connect with the client certificate
+	for _, node := range p.Nodes {
+		validators, err := node.AdminClient(t, ctx).ValidatorsList(ctx)
+		require.NoError(t, err)
+		require.Len(t, validators, 2)
+	}
+}
+
+func Test_InterRegionLatency(t *testing.T) {
+	inRegion := func(region string) *setup.NodeConfig {
+		return setup.CustomNodeConfig(func(nc *setup.NodeConfig) {
+			nc.Region = &setup.RegionConfig{
+				RegionName:           region,
+				InterRegionLatencyMs: 100,
+			}
+		})
+	}
+
+	p := setup.SetupTests(t, &setup.TestConfig{
+		ClientDriver: setup.Go,
+		Network: &setup.NetworkConfig{
+			Nodes: []*setup.NodeConfig{
+				inRegion("us"),
+				inRegion("eu"),
+			},
+		},
+	})
+
+	latencies, err := p.MeasureInterRegionLatency(context.Background())
+	require.NoError(t, err)
+	require.Len(t, latencies, 2)
+
+	for pair, latency := range latencies {
+		require.GreaterOrEqualf(t, latency, 90*time.Millisecond, "latency %s", pair)
+		require.LessOrEqualf(t, latency, 150*time.Millisecond, "latency %s", pair)
+	}
+}
+
+func Test_Reset(t *testing.T) {
+	ownerKey, _, err := crypto.GenerateSecp256k1Key(rand.Reader)
+	require.NoError(t, err)
+	owner, err := authExt.GetIdentifierFromSigner(auth.GetUserSigner(ownerKey))
+	require.NoError(t, err)
+
+	p := setup.SetupTests(t, &setup.TestConfig{
+		ClientDriver: setup.Go,
+		Network: &setup.NetworkConfig{
+			Nodes: []*setup.NodeConfig{
+				setup.DefaultNodeConfig(),
+				setup.DefaultNodeConfig(),
+			},
+			DBOwner: owner,
+		},
+	})
+
+	ctx := context.Background()
+
+	// test A creates a table
+	clt := p.Nodes[0].JSONRPCClient(t, ctx, &setup.ClientOptions{PrivateKey: ownerKey})
+	txHash, err := clt.ExecuteSQL(ctx, "CREATE TABLE users (id int primary key);", nil, ctypes.WithSyncBroadcast(true))
+	require.NoError(t, err)
+	require.NoError(t, clt.TxSuccess(ctx, txHash))
+
+	_, err = clt.Query(ctx, "SELECT * FROM users;", nil, false)
+	require.NoError(t, err)
+
+	err = p.Reset(ctx, t)
+	require.NoError(t, err)
+
+	// test B does not see the table
+	for _, node := range p.Nodes {
+		clt := node.JSONRPCClient(t, ctx, &setup.ClientOptions{PrivateKey: ownerKey})
+		_, err = clt.Query(ctx, "SELECT * FROM users;", nil, false)
+		require.Error(t, err)
+	}
+}
+
+func Test_AddRemoveNode(t *testing.T) {
+	p := setup.SetupTests(t, &setup.TestConfig{
+		ClientDriver: setup.Go,
+		Network: &setup.NetworkConfig{
+			Nodes: []*setup.NodeConfig{
+				setup.DefaultNodeConfig(),
+				setup.DefaultNodeConfig(),
+			},
+		},
+	})
+
+	ctx := context.Background()
+
+	info, err := p.Nodes[0].JSONRPCClient(t, ctx, nil).ChainInfo(ctx)
+	require.NoError(t, err)
+
+	node, err := p.AddNode(ctx, t, setup.DefaultNodeConfig())
+	require.NoError(t, err)
+	require.Len(t, p.Nodes, 3)
+
+	// the new node syncs to the current height
+	syncCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	err = node.WaitForBlock(syncCtx, t, int64(info.BlockHeight))
+	require.NoError(t, err)
+
+	err = p.RemoveNode(ctx, t, 2)
+	require.NoError(t, err)
+	require.Len(t, p.Nodes, 2)
+}
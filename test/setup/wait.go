@@ -0,0 +1,105 @@
+package setup
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/docker/go-connections/nat"
+	"github.com/testcontainers/testcontainers-go/wait"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// WaitForHTTPEndpoint returns a wait strategy for services that are ready
+// once an HTTP GET request to the path of their lowest exposed port returns
+// the status code.
+func WaitForHTTPEndpoint(path string, statusCode int) wait.Strategy {
+	return wait.ForHTTP(path).WithStatusCodeMatcher(func(status int) bool {
+		return status == statusCode
+	})
+}
+
+// WaitForGRPCHealth returns a wait strategy for services that are ready once
+// the standard gRPC health service on the internal port reports that they are
+// serving.
+func WaitForGRPCHealth(port string) wait.Strategy {
+	return &grpcHealthStrategy{
+		port:         nat.Port(port),
+		pollInterval: 500 * time.Millisecond,
+	}
+}
+
+// grpcHealthStrategy waits for the gRPC health service of a container to
+// report that it is serving.
+type grpcHealthStrategy struct {
+	port         nat.Port
+	pollInterval time.Duration
+}
+
+var _ wait.Strategy = (*grpcHealthStrategy)(nil)
+
+func (s *grpcHealthStrategy) WaitUntilReady(ctx context.Context, target wait.StrategyTarget) error {
+	var lastErr error
+	for {
+		err := s.check(ctx, target)
+		if err == nil {
+			return nil
+		}
+		// a check cut short by the deadline says less about the service
+		// than the one before it
+		if lastErr == nil || ctx.Err() == nil {
+			lastErr = err
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("gRPC health of port %s: %w (last error: %v)", s.port, ctx.Err(), lastErr)
+		case <-time.After(s.pollInterval):
+		}
+	}
+}
+
+// check checks the health of the container once.
+func (s *grpcHealthStrategy) check(ctx context.Context, target wait.StrategyTarget) error {
+	host, err := target.Host(ctx)
+	if err != nil {
+		return err
+	}
+	port, err := target.MappedPort(ctx, s.port)
+	if err != nil {
+		return err
+	}
+
+	conn, err := grpc.NewClient(net.JoinHostPort(host, port.Port()),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	resp, err := grpc_health_v1.NewHealthClient(conn).Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+	if err != nil {
+		return err
+	}
+	if resp.Status != grpc_health_v1.HealthCheckResponse_SERVING {
+		return fmt.Errorf("service is %s", resp.Status)
+	}
+	return nil
+}
+
+// waitStrategy returns the strategy that is used to wait for the service to
+// be ready, or nil if it is not waited for. If WaitStrategy is not set, it
+// waits for WaitMsg to be logged.
+func (s *ServiceDefinition) waitStrategy(startTimeout time.Duration) wait.Strategy {
+	switch {
+	case s.WaitStrategy != nil:
+		return wait.ForAll(s.WaitStrategy).WithDeadline(startTimeout)
+	case s.WaitMsg != nil:
+		return wait.NewLogStrategy(*s.WaitMsg).WithStartupTimeout(startTimeout)
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,66 @@
+package setup
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/docker/go-connections/nat"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go/wait"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// localTarget is a wait.StrategyTarget for a port on the host.
+type localTarget struct {
+	wait.StrategyTarget
+	port string
+}
+
+func (l *localTarget) Host(context.Context) (string, error) {
+	return "127.0.0.1", nil
+}
+
+func (l *localTarget) MappedPort(context.Context, nat.Port) (nat.Port, error) {
+	return nat.Port(l.port + "/tcp"), nil
+}
+
+func Test_WaitForGRPCHealth(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	healthSrv := health.NewServer()
+	healthSrv.SetServingStatus("", grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+
+	srv := grpc.NewServer()
+	grpc_health_v1.RegisterHealthServer(srv, healthSrv)
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	_, port, err := net.SplitHostPort(lis.Addr().String())
+	require.NoError(t, err)
+	target := &localTarget{port: port}
+	strategy := WaitForGRPCHealth("9090")
+
+	t.Run("not serving", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		err := strategy.WaitUntilReady(ctx, target)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.ErrorContains(t, err, "NOT_SERVING")
+	})
+
+	t.Run("serving", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		time.AfterFunc(time.Second, func() {
+			healthSrv.SetServingStatus("", grpc_health_v1.HealthCheckResponse_SERVING)
+		})
+		require.NoError(t, strategy.WaitUntilReady(ctx, target))
+	})
+}